@@ -0,0 +1,127 @@
+package acls
+
+import (
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func aclNames(items []map[string]interface{}) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		name, _ := item["acl_name"].(string)
+		names = append(names, name)
+	}
+	return names
+}
+
+func seedACLs(srv *testserver.Server, acls ...ACL) {
+	for _, a := range acls {
+		srv.AddListItem("frontends", "web", "acls", a.toPayload())
+	}
+}
+
+func TestApplyACLFromYAML_RewritesFromFirstDifference(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+	seedACLs(srv,
+		ACL{Name: "is_api", Criterion: "path_beg", Value: "/api"},
+		ACL{Name: "is_old", Criterion: "hdr(host)", Value: "old.example.com"},
+	)
+
+	manifest := []byte(`apiVersion: haproxyctl/v1
+kind: ACL
+frontend: web
+acls:
+  - name: is_api
+    criterion: path_beg
+    value: /api
+  - name: is_static
+    criterion: path_end
+    value: .css .js
+`)
+	internal.CaptureStdout(t, func() {
+		if err := ApplyACLFromYAML(manifest, "", false); err != nil {
+			t.Fatalf("ApplyACLFromYAML failed: %v", err)
+		}
+	})
+
+	got := aclNames(srv.List("frontends", "web", "acls"))
+	if len(got) != 2 || got[0] != "is_api" || got[1] != "is_static" {
+		t.Fatalf("unexpected ACLs after apply: %v", got)
+	}
+	if n := srv.CountRequests("DELETE", "/v3/services/haproxy/configuration/frontends/web/acls/0"); n != 0 {
+		t.Fatalf("unchanged first ACL was rewritten")
+	}
+
+	// Applying the same manifest again changes nothing.
+	version := srv.Version()
+	out := internal.CaptureStdout(t, func() {
+		if err := ApplyACLFromYAML(manifest, "", false); err != nil {
+			t.Fatalf("second ApplyACLFromYAML failed: %v", err)
+		}
+	})
+	if srv.Version() != version {
+		t.Fatalf("unchanged apply bumped the version")
+	}
+	if want := "acl/web unchanged\n"; out != want {
+		t.Fatalf("output = %q, want %q", out, want)
+	}
+}
+
+func TestCreateAndDeleteACL(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+	seedACLs(srv,
+		ACL{Name: "is_api", Criterion: "path_beg", Value: "/api"},
+		ACL{Name: "is_admin", Criterion: "path_beg", Value: "/admin"},
+		ACL{Name: "is_api", Criterion: "hdr(host)", Value: "api.example.com"},
+	)
+
+	internal.CaptureStdout(t, func() {
+		if err := CreateACL(parentFrontend, "web", ACL{Name: "from_lan", Criterion: "src", Value: "10.0.0.0/8"}, 0, false); err != nil {
+			t.Fatalf("CreateACL failed: %v", err)
+		}
+	})
+	if got := aclNames(srv.List("frontends", "web", "acls")); len(got) != 4 || got[0] != "from_lan" {
+		t.Fatalf("unexpected ACLs after create: %v", got)
+	}
+
+	internal.CaptureStdout(t, func() {
+		if err := DeleteACL(parentFrontend, "web", "is_api"); err != nil {
+			t.Fatalf("DeleteACL failed: %v", err)
+		}
+	})
+	if got := aclNames(srv.List("frontends", "web", "acls")); len(got) != 2 || got[0] != "from_lan" || got[1] != "is_admin" {
+		t.Fatalf("unexpected ACLs after delete: %v", got)
+	}
+
+	if err := DeleteACL(parentFrontend, "web", "missing"); err == nil {
+		t.Fatalf("expected error for a missing ACL")
+	}
+	if err := CreateACL(parentFrontend, "web", ACL{Name: "no_criterion"}, -1, false); err == nil {
+		t.Fatalf("expected error for an ACL without criterion")
+	}
+}
+
+func TestACLManifest_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []ACLManifest{
+		{ACLs: []ACL{{Name: "a", Criterion: "src"}}},
+		{Frontend: "web", Backend: "app"},
+		{Frontend: "web", Kind: "Backend"},
+		{Backend: "app", ACLs: []ACL{{Criterion: "src"}}},
+	}
+	for i, m := range cases {
+		if err := m.Validate(); err == nil {
+			t.Fatalf("case %d: expected validation error", i)
+		}
+	}
+
+	ok := ACLManifest{APIVersion: apiVersionV1, Kind: aclKind, Backend: "app", ACLs: []ACL{{Name: "a", Criterion: "src", Value: "10.0.0.0/8"}}}
+	if err := ok.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
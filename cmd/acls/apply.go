@@ -0,0 +1,63 @@
+package acls
+
+import (
+	"fmt"
+	"slices"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ApplyACLFromYAML makes the ACLs of the manifest's frontend or backend
+// exactly the listed ones, in order. Only the lines from the first
+// difference on are rewritten, in one transaction.
+func ApplyACLFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest ACLManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse ACL manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid ACL configuration: %w", err)
+	}
+	parentType, parent, _ := manifest.parent()
+
+	if outputFormat != "" || dryRun {
+		if outputFormat == "" {
+			internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		} else {
+			payload := make([]map[string]interface{}, 0, len(manifest.ACLs))
+			for _, a := range manifest.ACLs {
+				payload = append(payload, a.toPayload())
+			}
+			internal.FormatOutput(payload, outputFormat)
+		}
+		if dryRun {
+			internal.PrintDryRun()
+		}
+		return nil
+	}
+
+	current, err := liveACLs(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+	if slices.Equal(current, manifest.ACLs) {
+		internal.PrintStatus("ACL", parent, internal.ActionUnchanged)
+		return nil
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		return replaceACLs(tx, parentType, parent, current, manifest.ACLs)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(current) == 0 {
+		internal.PrintStatus("ACL", parent, internal.ActionCreated)
+	} else {
+		internal.PrintStatus("ACL", parent, internal.ActionConfigured)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package acls provides commands to manage HAProxy ACLs via the Data Plane API.
+package acls
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// CreateACLsCmd represents "create acls <frontend|backend> <name>".
+var CreateACLsCmd = &cobra.Command{
+	Use:     "acls <frontend|backend> <name>",
+	Aliases: []string{"acl"},
+	Short:   "Add an ACL to a frontend or backend",
+	Long: `Add one "acl <name> <criterion> <value>" line to a frontend or backend.
+The ACL is appended unless --index places it at a given position.
+
+Examples:
+  haproxyctl create acls frontend web --name is_api --criterion path_beg --value /api
+  haproxyctl create acls backend app --name from_lan --criterion src --value 10.0.0.0/8 --index 0`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		parentType, _, err := internal.ParseParent(args[0]+"/"+args[1], parentTypes...)
+		if err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid arguments: %v", err)
+		}
+		acl := ACL{
+			Name:      internal.GetFlagString(cmd, "name"),
+			Criterion: internal.GetFlagString(cmd, "criterion"),
+			Value:     internal.GetFlagString(cmd, "value"),
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateACL(parentType, args[1], acl, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+			internal.Fatalf("Failed to create ACL on %s '%s': %v", parentType, args[1], err)
+		}
+	},
+}
+
+func init() {
+	CreateACLsCmd.Flags().String("name", "", "ACL name (required)")
+	CreateACLsCmd.Flags().String("criterion", "", "ACL criterion, e.g. path_beg or src (required)")
+	CreateACLsCmd.Flags().String("value", "", "ACL value(s)")
+	CreateACLsCmd.Flags().Int("index", -1, "Position to insert the ACL at (default: append)")
+	CreateACLsCmd.Flags().Bool("dry-run", false, "Print the ACL without creating it")
+}
+
+// CreateACL inserts one ACL into a frontend or backend at index, or appends
+// it when index is negative.
+func CreateACL(parentType, parent string, acl ACL, index int, dryRun bool) error {
+	if err := acl.validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(acl.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := liveACLs(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", aclsPath(parentType, parent)+"/"+strconv.Itoa(index), params, acl.toPayload()); err != nil {
+		return internal.FormatAPIError("ACL", acl.Name, "create", err)
+	}
+
+	internal.PrintStatus("ACL", parent+"/"+acl.Name, internal.ActionCreated)
+	return nil
+}
+
+// CreateACLsFromFile appends the ACLs of a manifest to its frontend or
+// backend in one transaction.
+func CreateACLsFromFile(data []byte) error {
+	var manifest ACLManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse ACL manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	parentType, parent, _ := manifest.parent()
+
+	if internal.IsOffline() {
+		internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := liveACLs(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		return replaceACLs(tx, parentType, parent, current, slices.Concat(current, manifest.ACLs))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, a := range manifest.ACLs {
+		internal.PrintStatus("ACL", parent+"/"+a.Name, internal.ActionCreated)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package acls provides commands to manage HAProxy ACLs via the Data Plane API.
+package acls
+
+import (
+	"fmt"
+	"slices"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// DeleteACLsCmd represents "delete acls <frontend|backend> <name> <acl_name>".
+var DeleteACLsCmd = &cobra.Command{
+	Use:     "acls <frontend|backend> <name> <acl_name>",
+	Aliases: []string{"acl"},
+	Short:   "Delete an ACL from a frontend or backend",
+	Long: `Delete every line of the named ACL from a frontend or backend, in one
+transaction. Rules that still refer to the ACL make the transaction fail,
+so remove or change those first.
+
+Examples:
+  haproxyctl delete acls frontend web is_api
+  haproxyctl delete acls backend app from_lan`,
+	Args: cobra.ExactArgs(3),
+	Run: func(_ *cobra.Command, args []string) {
+		parentType, _, err := internal.ParseParent(args[0]+"/"+args[1], parentTypes...)
+		if err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid arguments: %v", err)
+		}
+		if err := DeleteACL(parentType, args[1], args[2]); err != nil {
+			internal.Fatalf("Failed to delete ACL '%s' from %s '%s': %v", args[2], parentType, args[1], err)
+		}
+	},
+}
+
+// DeleteACL removes every line of the named ACL from a frontend or backend.
+func DeleteACL(parentType, parent, name string) error {
+	return deleteMatching(parentType, parent, func(a ACL) bool { return a.Name == name }, name)
+}
+
+// DeleteACLsFromFile removes the ACLs listed in a manifest from its
+// frontend or backend; other ACLs of the parent are kept.
+func DeleteACLsFromFile(data []byte) error {
+	var manifest ACLManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse ACL manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	parentType, parent, _ := manifest.parent()
+
+	for _, a := range manifest.ACLs {
+		listed := a
+		if err := deleteMatching(parentType, parent, func(live ACL) bool { return live == listed }, a.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteMatching removes the ACLs for which match returns true in one
+// transaction and reports them under name.
+func deleteMatching(parentType, parent string, match func(ACL) bool, name string) error {
+	current, err := liveACLs(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+	desired := slices.DeleteFunc(slices.Clone(current), match)
+	if len(desired) == len(current) {
+		return fmt.Errorf("%s %q has no ACL %q", parentType, parent, name)
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		return replaceACLs(tx, parentType, parent, current, desired)
+	})
+	if err != nil {
+		return internal.FormatAPIError("ACL", name, "delete", err)
+	}
+
+	internal.PrintStatus("ACL", parent+"/"+name, internal.ActionDeleted)
+	return nil
+}
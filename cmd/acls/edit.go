@@ -0,0 +1,113 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package acls provides commands to manage HAProxy ACLs via the Data Plane API.
+package acls
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// EditACLsCmd represents "edit acls <frontend|backend> <name>".
+var EditACLsCmd = &cobra.Command{
+	Use:     "acls <frontend|backend> <name>",
+	Aliases: []string{"acl"},
+	Short:   "Edit the ACLs of a frontend or backend in your editor",
+	Long: `Open the ACLs of a frontend or backend as an ACL manifest in $EDITOR.
+Lines can be added, changed, removed or reordered; the result replaces the
+parent's ACLs in one transaction.
+
+Examples:
+  haproxyctl edit acls frontend web
+  EDITOR=nano haproxyctl edit acls backend app`,
+	Args: cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		parentType, _, err := internal.ParseParent(args[0]+"/"+args[1], parentTypes...)
+		if err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid arguments: %v", err)
+		}
+		if err := editACLs(parentType, args[1]); err != nil {
+			internal.Fatalf("Edit failed: %v", err)
+		}
+	},
+}
+
+func editACLs(parentType, parent string) error {
+	current, err := liveACLs(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+	manifest := manifestFor(parentType, parent, current)
+
+	origYAML, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ACL manifest to YAML: %w", err)
+	}
+
+	tmpFile, err := internal.WriteTempYAML("haproxyctl-acls-"+parent+"-", manifest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rmErr := os.Remove(tmpFile); rmErr != nil {
+			internal.Warnf("failed to remove temp file %q: %v", tmpFile, rmErr)
+		}
+	}()
+
+	if err := internal.OpenInEditor(tmpFile); err != nil {
+		return err
+	}
+
+	// tmpFile is created via os.CreateTemp in WriteTempYAML and lives in
+	// the system temp directory, so this read is safe.
+	editedYAML, err := os.ReadFile(tmpFile) //nolint:gosec // tmpFile is controlled by this process
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	if bytes.Equal(bytes.TrimSpace(origYAML), bytes.TrimSpace(editedYAML)) {
+		internal.PrintStatus("ACL", parent, internal.ActionUnchanged)
+		return nil
+	}
+
+	var edited ACLManifest
+	if err := yaml.Unmarshal(editedYAML, &edited); err != nil {
+		return fmt.Errorf("failed to parse edited YAML: %w", err)
+	}
+	if err := edited.Validate(); err != nil {
+		return fmt.Errorf("invalid ACL manifest: %w", err)
+	}
+	if t, name, _ := edited.parent(); t != parentType || name != parent {
+		return fmt.Errorf("cannot move ACLs to another section via edit (got %s %q, expected %s %q)", t, name, parentType, parent)
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		return replaceACLs(tx, parentType, parent, current, edited.ACLs)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update ACLs of %s %q: %w", parentType, parent, err)
+	}
+
+	internal.PrintStatus("ACL", parent, internal.ActionConfigured)
+	return nil
+}
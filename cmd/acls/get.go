@@ -19,52 +19,57 @@ package acls
 
 import (
 	"encoding/json"
-	"fmt"
 	"haproxyctl/internal"
-	"log"
-	"os"
 
 	"github.com/spf13/cobra"
 )
 
-// GetACLsCmd represents "get acls <frontend_name>".
+// GetACLsCmd represents "get acls [frontend|backend] <name>".
 var GetACLsCmd = &cobra.Command{
-	Use:     "acls <frontend_name>",
+	Use:     "acls [frontend|backend] <name>",
 	Aliases: []string{"acl"},
-	Short:   "Retrieve ACLs for a specific HAProxy frontend",
-	Args:    cobra.ExactArgs(1), // Requires exactly 1 argument: the frontend name
+	Short:   "Retrieve ACLs for a specific HAProxy frontend or backend",
+	Long: `List the ACLs of a frontend, or of a backend when the name is preceded
+by "backend".
+
+Examples:
+  haproxyctl get acls web
+  haproxyctl get acls backend app -o yaml`,
+	Args: cobra.RangeArgs(1, 2), // [parent type] and the parent name
 	Run: func(cmd *cobra.Command, args []string) {
-		frontendName := args[0]
-		getACLs(frontendName, cmd)
+		parentType := parentFrontend
+		if len(args) == 2 {
+			var err error
+			if parentType, _, err = internal.ParseParent(args[0]+"/"+args[1], parentTypes...); err != nil {
+				internal.FatalCodef(internal.ExitUsage, "Invalid arguments: %v", err)
+			}
+		}
+		getACLs(parentType, args[len(args)-1], cmd)
 	},
 }
 
-// getACLs fetches the list of ACLs for a specific HAProxy frontend.
-func getACLs(frontendName string, cmd *cobra.Command) {
-	endpoint := fmt.Sprintf("/services/haproxy/configuration/frontends/%s/acls", frontendName)
-
-	data, err := internal.SendRequestWithContext(cmd.Context(), "GET", endpoint, nil, nil)
+// getACLs fetches the list of ACLs for a specific HAProxy frontend or backend.
+func getACLs(parentType, parent string, cmd *cobra.Command) {
+	data, err := internal.SendRequestWithContext(cmd.Context(), "GET", aclsPath(parentType, parent), nil, nil)
 	if err != nil {
 		if internal.IsNotFoundError(err) {
-			_, _ = fmt.Fprintf(os.Stderr, "Error: frontend %q not found\n\n", frontendName)
-			_ = cmd.Usage()
-			return
+			internal.FatalCodef(internal.ExitNotFound, "%s %q not found", parentType, parent)
 		}
-		log.Fatal(err)
+		internal.Fatalf("%v", err)
 	}
 
 	outputFormat, _ := cmd.Flags().GetString("output")
 
 	var acls []map[string]interface{}
 	if err := json.Unmarshal(data, &acls); err != nil {
-		log.Fatalf("failed to parse ACL response: %v\nResponse: %s", err, string(data))
+		internal.Fatalf("failed to parse ACL response: %v\nResponse: %s", err, string(data))
 	}
 
 	// Ensure deterministic ordering of ACLs by acl_name when listing.
 	internal.SortByStringField(acls, "acl_name")
 
-	// Use FormatOutput to pretty-print JSON or YAML
-	internal.FormatOutput(acls, outputFormat)
+	// Use FormatOutputForCmd to pretty-print JSON or YAML
+	internal.FormatOutputForCmd(cmd, acls, outputFormat)
 }
 
 func init() {
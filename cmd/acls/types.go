@@ -0,0 +1,181 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package acls provides commands to manage HAProxy ACLs via the Data Plane API.
+package acls
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	apiVersionV1 = "haproxyctl/v1"
+	aclKind      = "ACL"
+
+	parentFrontend = internal.ParentFrontend
+	parentBackend  = internal.ParentBackend
+)
+
+// parentTypes are the sections that own ACLs.
+var parentTypes = []string{parentFrontend, parentBackend}
+
+// ACLManifest describes every ACL of one frontend or backend, in order.
+// Exactly one of Frontend and Backend is set.
+type ACLManifest struct {
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string `json:"kind" yaml:"kind"`
+	Frontend   string `json:"frontend,omitempty" yaml:"frontend,omitempty"`
+	Backend    string `json:"backend,omitempty" yaml:"backend,omitempty"`
+	ACLs       []ACL  `json:"acls" yaml:"acls"`
+}
+
+// ACL is a single "acl <name> <criterion> <value>" line.
+type ACL struct {
+	Name      string `json:"name" yaml:"name"`
+	Criterion string `json:"criterion" yaml:"criterion"`
+	Value     string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// String renders the ACL the way it appears in haproxy.cfg.
+func (a ACL) String() string {
+	return strings.TrimSpace(fmt.Sprintf("acl %s %s %s", a.Name, a.Criterion, a.Value))
+}
+
+// displayParent returns the parent type as shown by PrintStatus ("Frontend").
+func displayParent(parentType string) string {
+	return strings.ToUpper(parentType[:1]) + parentType[1:]
+}
+
+// aclsPath returns the ACL list endpoint of a frontend or backend.
+func aclsPath(parentType, parent string) string {
+	return fmt.Sprintf("/services/haproxy/configuration/%ss/%s/acls", parentType, parent)
+}
+
+// parent returns the parent type and name the manifest refers to.
+func (m *ACLManifest) parent() (string, string, error) {
+	switch {
+	case m.Frontend != "" && m.Backend != "":
+		return "", "", errors.New("ACL manifest must set only one of frontend and backend")
+	case m.Frontend != "":
+		return parentFrontend, m.Frontend, nil
+	case m.Backend != "":
+		return parentBackend, m.Backend, nil
+	default:
+		return "", "", errors.New("ACL manifest must set frontend or backend")
+	}
+}
+
+// Validate checks the manifest header, the parent and every ACL.
+func (m *ACLManifest) Validate() error {
+	if m.APIVersion != "" && m.APIVersion != apiVersionV1 {
+		return fmt.Errorf("unsupported apiVersion %q (expected %s)", m.APIVersion, apiVersionV1)
+	}
+	if m.Kind != "" && m.Kind != aclKind {
+		return fmt.Errorf("invalid kind %q, expected %q", m.Kind, aclKind)
+	}
+	if _, _, err := m.parent(); err != nil {
+		return err
+	}
+	for i, a := range m.ACLs {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("acls[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (a ACL) validate() error {
+	if a.Name == "" {
+		return errors.New("acl is missing name")
+	}
+	if a.Criterion == "" {
+		return fmt.Errorf("acl %q is missing criterion", a.Name)
+	}
+	return nil
+}
+
+// toPayload converts an ACL into the Data Plane API wire format.
+func (a ACL) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{
+		"acl_name":  a.Name,
+		"criterion": a.Criterion,
+	}
+	if a.Value != "" {
+		payload["value"] = a.Value
+	}
+	return payload
+}
+
+// aclFromAPI converts an API ACL object.
+func aclFromAPI(obj map[string]interface{}) ACL {
+	var a ACL
+	a.Name, _ = obj["acl_name"].(string)
+	a.Criterion, _ = obj["criterion"].(string)
+	a.Value, _ = obj["value"].(string)
+	return a
+}
+
+// liveACLs returns the ACLs of a frontend or backend in list order.
+func liveACLs(parentType, parent string) ([]ACL, error) {
+	list, err := internal.GetResourceList(aclsPath(parentType, parent))
+	if err != nil {
+		return nil, err
+	}
+	acls := make([]ACL, 0, len(list))
+	for _, obj := range list {
+		acls = append(acls, aclFromAPI(obj))
+	}
+	return acls, nil
+}
+
+// manifestFor builds the manifest describing the live ACLs of a parent.
+func manifestFor(parentType, parent string, acls []ACL) ACLManifest {
+	m := ACLManifest{APIVersion: apiVersionV1, Kind: aclKind, ACLs: acls}
+	if parentType == parentFrontend {
+		m.Frontend = parent
+	} else {
+		m.Backend = parent
+	}
+	return m
+}
+
+// replaceACLs turns the ACL list of a parent from current into desired
+// inside tx. Positions are the only identity ACL lines have, so every line
+// from the first difference on is removed and re-created in order.
+func replaceACLs(tx *internal.Transaction, parentType, parent string, current, desired []ACL) error {
+	common := 0
+	for common < len(current) && common < len(desired) && current[common] == desired[common] {
+		common++
+	}
+
+	path := aclsPath(parentType, parent)
+	for i := len(current) - 1; i >= common; i-- {
+		if _, err := internal.SendRequest("DELETE", path+"/"+strconv.Itoa(i), tx.Params(), nil); err != nil {
+			return fmt.Errorf("failed to delete %q: %w", current[i].String(), err)
+		}
+	}
+	for i := common; i < len(desired); i++ {
+		if _, err := internal.SendRequest("POST", path+"/"+strconv.Itoa(i), tx.Params(), desired[i].toPayload()); err != nil {
+			return fmt.Errorf("failed to create %q: %w", desired[i].String(), err)
+		}
+	}
+	return nil
+}
@@ -18,15 +18,32 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"haproxyctl/cmd/acls"
 	"haproxyctl/cmd/backends"
+	"haproxyctl/cmd/caches"
 	"haproxyctl/cmd/configuration"
 	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/httprules"
+	"haproxyctl/cmd/logforwards"
+	"haproxyctl/cmd/resolvers"
+	"haproxyctl/cmd/rings"
 	"haproxyctl/cmd/servers"
+	"haproxyctl/cmd/spoe"
+	"haproxyctl/cmd/userlists"
 	"haproxyctl/internal"
+	"io"
+	"io/fs"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -35,11 +52,21 @@ import (
 var applyFile string
 
 const (
-	kindBackend  = "backend"
-	kindFrontend = "frontend"
-	kindServer   = "server"
-	kindGlobal   = "global"
-	kindDefaults = "defaults"
+	kindBackend          = "backend"
+	kindFrontend         = "frontend"
+	kindServer           = "server"
+	kindGlobal           = "global"
+	kindDefaults         = "defaults"
+	kindUserlist         = "userlist"
+	kindResolver         = "resolver"
+	kindCache            = "cache"
+	kindHTTPErrors       = "httperrors"
+	kindRing             = "ring"
+	kindLogForward       = "logforward"
+	kindSPOE             = "spoe"
+	kindACL              = "acl"
+	kindHTTPRequestRule  = "httprequestrule"
+	kindHTTPResponseRule = "httpresponserule"
 )
 
 // applyCmd represents the top-level "apply" command.
@@ -48,42 +75,325 @@ var applyCmd = &cobra.Command{
 	Short: "Apply a manifest to HAProxy (create or replace)",
 	Long: `Apply a manifest file in a declarative way, similar to kubectl apply.
 
-The manifest must include apiVersion: haproxyctl/v1 and a supported kind
-(Backend, Frontend, or Server). If the resource does not exist it will be
-created; if it exists it will be replaced using the same logic as the
-interactive edit flows.`,
+The manifest must include apiVersion: haproxyctl/v1 (or v2, see below) and a supported kind
+(Backend, Frontend, Server, Global, Defaults, Userlist, Resolver, Cache,
+HTTPErrors, Ring, LogForward, SPOE, ACL, HTTPRequestRule or HTTPResponseRule). If the resource does not exist it
+will be created; if it exists it will be replaced using the same logic as
+the interactive edit flows.
+
+A file may hold several documents separated by "---". They are applied in
+dependency order (Ring, LogForward, Global, Defaults, Userlist, Resolver,
+Cache, HTTPErrors, SPOE, Backend, Server, Frontend, ACL, HTTPRequestRule, HTTPResponseRule)
+regardless of their order in the file, and apply stops at the first failing document.
+
+apiVersion: haproxyctl/v2 is an opt-in layout that nests the name, labels
+and annotations under "metadata:" and every other field under "spec:":
+
+  apiVersion: haproxyctl/v2
+  kind: Backend
+  metadata:
+    name: app
+    labels: {team: web}
+  spec:
+    mode: http
+
+With -l/--selector only the documents whose labels match are applied (e.g.
+-l team=web,tier!=edge); v1 documents have no labels. With --prune, objects
+described by documents the selector leaves out are still kept.
+
+Manifests may also be written in JSON: a .json file (or any content starting
+with "{" or "[") holds one object, a list of objects, or a stream of them.
+
+When -f points to a directory, every *.yaml, *.yml and *.json file in it is
+applied (with -R, in all subdirectories too), sorted by kind across files,
+followed by a summary of created, configured and unchanged resources.
+
+-f also accepts an http:// or https:// URL. Use --checksum to make sure the
+downloaded file is the one you expect.
+
+With --local, the manifests are only validated, as "haproxyctl validate"
+does, and the Data Plane API is not contacted.
+
+With --prune, the applied set becomes the source of truth: after a
+successful apply, frontends and backends of a kind present in the set but
+not described by any manifest are deleted, as are servers of a described
+backend that neither its Backend manifest nor a Server manifest lists. All
+deletions happen in one transaction.
+
+Examples:
+  haproxyctl apply -f backend.yaml
+  haproxyctl apply -f all.yaml
+  haproxyctl apply -f ./manifests/ -R
+  haproxyctl apply -f https://example.com/haproxy/backend.yaml --checksum sha256:<hex>
+  haproxyctl apply -f ./manifests/ -R --prune --dry-run
+  haproxyctl apply -f ./manifests/ -R --local
+  haproxyctl apply -f ./manifests/ -R -l team=web`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
 		if applyFile == "" {
 			return errors.New("apply requires -f/--file")
 		}
-		return applyFromFile(cmd, applyFile)
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		checksum, _ := cmd.Flags().GetString("checksum")
+		selector, _ := cmd.Flags().GetString("selector")
+		opts := manifestOptions{Recursive: recursive, Checksum: checksum, Selector: selector}
+		if local, _ := cmd.Flags().GetBool("local"); local {
+			return validateFromPath(applyFile, opts)
+		}
+		return applyFromPath(cmd, applyFile, opts)
 	},
 }
 
-func applyFromFile(cmd *cobra.Command, filepath string) error {
-	// The CLI is expected to read user-specified manifest files.
-	data, err := os.ReadFile(filepath) //nolint:gosec // filepath comes from user input by design
+func applyFromPath(cmd *cobra.Command, path string, opts manifestOptions) error {
+	all, err := readManifests(path, opts)
 	if err != nil {
-		return fmt.Errorf("failed to read file %s: %w", filepath, err)
+		return err
 	}
 
-	// Extract kind and apiVersion to figure out what to do.
-	var metadata struct {
-		APIVersion string `yaml:"apiVersion"`
-		Kind       string `yaml:"kind"`
+	// Check every document before applying any of them.
+	for _, m := range all {
+		if err := checkAPIVersion(m); err != nil {
+			return fmt.Errorf("%s: %w", m, err)
+		}
 	}
-	if err := yaml.Unmarshal(data, &metadata); err != nil {
-		return fmt.Errorf("failed to parse YAML file: %w", err)
+	manifests, err := selectManifests(all, opts.Selector)
+	if err != nil {
+		return err
 	}
 
-	if metadata.APIVersion != "haproxyctl/v1" {
-		return fmt.Errorf("unsupported apiVersion %q (expected haproxyctl/v1)", metadata.APIVersion)
+	prune, _ := cmd.Flags().GetBool("prune")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	dryRun = dryRun || internal.IsOffline()
+
+	// Documents left out by the selector still protect their objects.
+	var keep pruneSet
+	if prune {
+		if keep, err = newPruneSet(all); err != nil {
+			return err
+		}
 	}
 
+	apply := func() error {
+		for _, m := range manifests {
+			if err := applyManifest(cmd, m); err != nil {
+				return fmt.Errorf("%s: %w", m, err)
+			}
+		}
+		if prune {
+			return pruneUnlisted(keep, dryRun)
+		}
+		return nil
+	}
+
+	if !prune {
+		if isManifestURL(path) {
+			return apply()
+		}
+		if info, statErr := os.Stat(path); statErr != nil || !info.IsDir() {
+			return apply()
+		}
+	}
+
+	counts, err := internal.TallyStatuses(apply)
+	printApplySummary(counts)
+	return err
+}
+
+// printApplySummary prints e.g. "Summary: 2 created, 1 configured, 3 unchanged".
+func printApplySummary(counts map[string]int) {
+	parts := []string{
+		fmt.Sprintf("%d %s", counts[internal.ActionCreated], internal.ActionCreated),
+		fmt.Sprintf("%d %s", counts[internal.ActionConfigured], internal.ActionConfigured),
+		fmt.Sprintf("%d %s", counts[internal.ActionUnchanged], internal.ActionUnchanged),
+	}
+	if n := counts[internal.ActionPruned]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", n, internal.ActionPruned))
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "Summary: %s\n", strings.Join(parts, ", "))
+}
+
+// manifestOptions controls how readManifests loads its source.
+type manifestOptions struct {
+	// Recursive includes the subdirectories of a directory source.
+	Recursive bool
+	// Checksum, if set, is the expected SHA-256 of a file or URL source,
+	// as "sha256:<hex>" or plain hex.
+	Checksum string
+	// Selector, if set, is a label selector picking the documents to act
+	// on (see selectManifests); readManifests still returns them all.
+	Selector string
+}
+
+// checkAPIVersion rejects documents of an apiVersion haproxyctl does not
+// know.
+func checkAPIVersion(m internal.Manifest) error {
+	if m.APIVersion != internal.APIVersionV1 && m.APIVersion != internal.APIVersionV2 {
+		return fmt.Errorf("unsupported apiVersion %q (expected %s or %s)", m.APIVersion, internal.APIVersionV1, internal.APIVersionV2)
+	}
+	return nil
+}
+
+// selectManifests returns the manifests whose labels match selector, or
+// all of them when selector is empty.
+func selectManifests(manifests []internal.Manifest, selector string) ([]internal.Manifest, error) {
+	sel, err := internal.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(sel) == 0 {
+		return manifests, nil
+	}
+
+	var selected []internal.Manifest
+	for _, m := range manifests {
+		if sel.Matches(m.Labels) {
+			selected = append(selected, m)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no manifests match selector %q", selector)
+	}
+	return selected, nil
+}
+
+// manifestFetchTimeout bounds how long fetching a remote manifest may take.
+const manifestFetchTimeout = 30 * time.Second
+
+// readManifests loads the manifests from a file, an http(s) URL, or the
+// *.yaml, *.yml and *.json files of a directory, and returns them in
+// dependency order.
+func readManifests(path string, opts manifestOptions) ([]internal.Manifest, error) {
+	var sources map[string][]byte
+	var order []string
+
+	if isManifestURL(path) {
+		data, err := fetchManifest(path)
+		if err != nil {
+			return nil, err
+		}
+		sources, order = map[string][]byte{path: data}, []string{path}
+	} else {
+		files, err := manifestFiles(path, opts.Recursive)
+		if err != nil {
+			return nil, err
+		}
+		sources = make(map[string][]byte, len(files))
+		for _, file := range files {
+			// The CLI is expected to read user-specified manifest files.
+			data, err := os.ReadFile(file) //nolint:gosec // file comes from user input by design
+			if err != nil {
+				return nil, fmt.Errorf("failed to read file %s: %w", file, err)
+			}
+			sources[file] = data
+		}
+		order = files
+	}
+
+	if opts.Checksum != "" {
+		if len(order) != 1 || order[0] != path {
+			return nil, errors.New("--checksum can only be used with a single file or URL")
+		}
+		if err := verifyChecksum(sources[path], opts.Checksum); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var manifests []internal.Manifest
+	for _, source := range order {
+		parsed, err := internal.ParseManifests(sources[source], source)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, parsed...)
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("no manifests found in %s", path)
+	}
+
+	internal.SortManifestsByKind(manifests)
+	return manifests, nil
+}
+
+func isManifestURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// fetchManifest downloads a remote manifest.
+func fetchManifest(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), manifestFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest URL %s: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// verifyChecksum compares the SHA-256 of data with want.
+func verifyChecksum(data []byte, want string) error {
+	want = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(want), "sha256:"))
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch: got sha256:%s, want sha256:%s", got, want)
+	}
+	return nil
+}
+
+// manifestFiles returns path itself for a file, or the manifest files below
+// a directory in lexical order.
+func manifestFiles(path string, recursive bool) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != path && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", path, err)
+	}
+	return files, nil
+}
+
+func applyManifest(cmd *cobra.Command, m internal.Manifest) error {
+	data := m.Data
+
 	outputFormat := cmd.Flags().Lookup("output").Value.String()
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	dryRun = dryRun || internal.IsOffline()
 
-	switch strings.ToLower(metadata.Kind) {
+	switch strings.ToLower(m.Kind) {
 	case kindBackend:
 		return backends.ApplyBackendFromYAML(data, outputFormat, dryRun)
 	case kindFrontend:
@@ -92,6 +402,24 @@ func applyFromFile(cmd *cobra.Command, filepath string) error {
 		return configuration.ApplyGlobalFromYAML(data, outputFormat, dryRun)
 	case kindDefaults:
 		return configuration.ApplyDefaultsFromYAML(data, outputFormat, dryRun)
+	case kindUserlist:
+		return userlists.ApplyUserlistFromYAML(data, outputFormat, dryRun)
+	case kindResolver:
+		return resolvers.ApplyResolverFromYAML(data, outputFormat, dryRun)
+	case kindCache:
+		return caches.ApplyCacheFromYAML(data, outputFormat, dryRun)
+	case kindHTTPErrors:
+		return httperrors.ApplyHTTPErrorsFromYAML(data, outputFormat, dryRun)
+	case kindRing:
+		return rings.ApplyRingFromYAML(data, outputFormat, dryRun)
+	case kindLogForward:
+		return logforwards.ApplyLogForwardFromYAML(data, outputFormat, dryRun)
+	case kindSPOE:
+		return spoe.ApplySPOEFromYAML(data, outputFormat, dryRun)
+	case kindACL:
+		return acls.ApplyACLFromYAML(data, outputFormat, dryRun)
+	case kindHTTPRequestRule, kindHTTPResponseRule:
+		return httprules.ApplyRulesFromYAML(data, outputFormat, dryRun)
 	case kindServer:
 		var s servers.ServerConfig
 		if err := yaml.Unmarshal(data, &s); err != nil {
@@ -124,14 +452,20 @@ func applyFromFile(cmd *cobra.Command, filepath string) error {
 
 		return servers.CreateServer(s, "", false)
 	default:
-		return fmt.Errorf("unsupported resource kind: %s (supported: Backend, Frontend, Server)", metadata.Kind)
+		return fmt.Errorf("unsupported resource kind: %s (supported: Backend, Frontend, Server, Global, Defaults, Userlist, Resolver, Cache, Ring, LogForward, SPOE, ACL, HTTPRequestRule, HTTPResponseRule)", m.Kind)
 	}
 }
 
 func init() {
 	rootCmd.AddCommand(applyCmd)
 
-	applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "Apply resource from YAML file (kind: Backend, Frontend, or Server)")
+	applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "Apply resources from a YAML or JSON file, directory, or http(s) URL (kind: Backend, Frontend, Server, Global, Defaults, Userlist, Resolver, Cache, Ring, LogForward, SPOE, ACL, HTTPRequestRule, or HTTPResponseRule)")
+	addWaitFlags(applyCmd)
 	applyCmd.Flags().StringP("output", "o", "", "Output format: yaml or json")
 	applyCmd.Flags().Bool("dry-run", false, "Simulate apply without actually making changes")
+	applyCmd.Flags().BoolP("recursive", "R", false, "Process the directory given with -f recursively")
+	applyCmd.Flags().Bool("prune", false, "Delete frontends, backends and servers not described by the applied manifests")
+	applyCmd.Flags().StringP("selector", "l", "", "Only apply documents whose metadata.labels match, e.g. team=web,tier!=edge (haproxyctl/v2 manifests)")
+	applyCmd.Flags().Bool("local", false, "Only validate the manifests, without contacting the Data Plane API (see \"haproxyctl validate\")")
+	applyCmd.Flags().String("checksum", "", "Expected SHA-256 of the file or URL given with -f (sha256:<hex>)")
 }
@@ -0,0 +1,222 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"haproxyctl/cmd/servers"
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// pruneSet records which objects an applied set of manifests describes.
+type pruneSet struct {
+	backends  map[string]bool
+	frontends map[string]bool
+	// servers maps every backend a manifest refers to (a Backend manifest
+	// or a Server's parent) to the server names described for it.
+	servers map[string]map[string]bool
+}
+
+func newPruneSet(manifests []internal.Manifest) (pruneSet, error) {
+	set := pruneSet{servers: map[string]map[string]bool{}}
+	addServer := func(backend, name string) {
+		if set.servers[backend] == nil {
+			set.servers[backend] = map[string]bool{}
+		}
+		if name != "" {
+			set.servers[backend][name] = true
+		}
+	}
+
+	for _, m := range manifests {
+		var obj struct {
+			Name    string `yaml:"name"`
+			Backend string `yaml:"backend"`
+			Parent  string `yaml:"parent"`
+			Servers []struct {
+				Name string `yaml:"name"`
+			} `yaml:"servers"`
+		}
+		if err := yaml.Unmarshal(m.Data, &obj); err != nil {
+			return set, fmt.Errorf("%s: %w", m, err)
+		}
+
+		switch strings.ToLower(m.Kind) {
+		case kindBackend:
+			if set.backends == nil {
+				set.backends = map[string]bool{}
+			}
+			set.backends[obj.Name] = true
+			addServer(obj.Name, "")
+			for _, srv := range obj.Servers {
+				addServer(obj.Name, srv.Name)
+			}
+		case kindFrontend:
+			if set.frontends == nil {
+				set.frontends = map[string]bool{}
+			}
+			set.frontends[obj.Name] = true
+		case kindServer:
+			parent := obj.Parent
+			if parent == "" {
+				parent = obj.Backend
+			}
+			addServer(parent, obj.Name)
+		}
+	}
+	return set, nil
+}
+
+// pruneTarget is an object apply --prune is about to delete.
+type pruneTarget struct {
+	kind, backend, name string
+}
+
+func (p pruneTarget) id() string {
+	if p.kind == "Server" {
+		return p.backend + "/" + p.name
+	}
+	return p.name
+}
+
+// pruneUnlisted deletes the objects the applied manifests no longer
+// describe. Kinds missing from the set entirely (e.g. no Frontend manifest
+// at all) are left alone, so applying a backends-only directory never
+// wipes the frontends.
+func pruneUnlisted(set pruneSet, dryRun bool) error {
+	var targets []pruneTarget
+
+	if set.frontends != nil {
+		names, err := liveNames("/services/haproxy/configuration/frontends")
+		if err != nil {
+			return fmt.Errorf("failed to list frontends: %w", err)
+		}
+		for _, name := range names {
+			if !set.frontends[name] {
+				targets = append(targets, pruneTarget{kind: "Frontend", name: name})
+			}
+		}
+	}
+
+	prunedBackends := map[string]bool{}
+	if set.backends != nil {
+		names, err := liveNames("/services/haproxy/configuration/backends")
+		if err != nil {
+			return fmt.Errorf("failed to list backends: %w", err)
+		}
+		for _, name := range names {
+			if !set.backends[name] {
+				prunedBackends[name] = true
+			}
+		}
+	}
+
+	backendNames := make([]string, 0, len(set.servers))
+	for backend := range set.servers {
+		backendNames = append(backendNames, backend)
+	}
+	sort.Strings(backendNames)
+	for _, backend := range backendNames {
+		if prunedBackends[backend] {
+			continue
+		}
+		names, err := liveNames("/services/haproxy/configuration/backends/" + backend + "/servers")
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				continue
+			}
+			return fmt.Errorf("failed to list servers of backend %q: %w", backend, err)
+		}
+		for _, name := range names {
+			if !set.servers[backend][name] {
+				targets = append(targets, pruneTarget{kind: "Server", backend: backend, name: name})
+			}
+		}
+	}
+
+	// Backends go last: frontends and servers may still refer to them.
+	for _, name := range sortedKeys(prunedBackends) {
+		targets = append(targets, pruneTarget{kind: "Backend", name: name})
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	if dryRun {
+		for _, t := range targets {
+			internal.PrintStatus(t.kind, t.id(), internal.ActionPruned+" (dry run)")
+		}
+		return nil
+	}
+
+	err := internal.WithTransaction(func(tx *internal.Transaction) error {
+		for _, t := range targets {
+			var err error
+			switch t.kind {
+			case "Server":
+				err = servers.DeleteServerInTransaction(tx, t.backend, t.name)
+			case "Frontend":
+				_, err = internal.SendRequest("DELETE", "/services/haproxy/configuration/frontends/"+t.name, tx.Params(), nil)
+			case "Backend":
+				_, err = internal.SendRequest("DELETE", "/services/haproxy/configuration/backends/"+t.name, tx.Params(), nil)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to prune %s: %w", internal.ResourceID(t.kind, t.id()), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range targets {
+		internal.PrintStatus(t.kind, t.id(), internal.ActionPruned)
+	}
+	return nil
+}
+
+// liveNames returns the names of the objects listed at endpoint.
+func liveNames(endpoint string) ([]string, error) {
+	items, err := internal.GetResourceList(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if name, ok := item["name"].(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
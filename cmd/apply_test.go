@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+
+	"github.com/spf13/cobra"
+)
+
+func writeManifest(t *testing.T, path, kind, name string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data := "apiVersion: haproxyctl/v1\nkind: " + kind + "\nname: " + name + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestReadManifestsDirectory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeManifest(t, filepath.Join(dir, "a-frontend.yaml"), "Frontend", "web")
+	writeManifest(t, filepath.Join(dir, "b-backend.yml"), "Backend", "app")
+	writeManifest(t, filepath.Join(dir, "nested", "server.yaml"), "Server", "app1")
+	writeManifest(t, filepath.Join(dir, "nested", "deep", "backend.yaml"), "Backend", "api")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0o600); err != nil {
+		t.Fatalf("write README: %v", err)
+	}
+	cache := `{"apiVersion": "haproxyctl/v1", "kind": "Cache", "name": "static"}`
+	if err := os.WriteFile(filepath.Join(dir, "c-cache.json"), []byte(cache), 0o600); err != nil {
+		t.Fatalf("write cache: %v", err)
+	}
+
+	kinds := func(recursive bool) string {
+		manifests, err := readManifests(dir, manifestOptions{Recursive: recursive})
+		if err != nil {
+			t.Fatalf("readManifests(recursive=%v) error = %v", recursive, err)
+		}
+		var out []string
+		for _, m := range manifests {
+			out = append(out, m.Kind)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got, want := kinds(false), "Cache,Backend,Frontend"; got != want {
+		t.Errorf("top-level kinds = %s, want %s", got, want)
+	}
+	if got, want := kinds(true), "Cache,Backend,Backend,Server,Frontend"; got != want {
+		t.Errorf("recursive kinds = %s, want %s", got, want)
+	}
+}
+
+func TestReadManifestsEmptyDirectory(t *testing.T) {
+	t.Parallel()
+
+	if _, err := readManifests(t.TempDir(), manifestOptions{Recursive: true}); err == nil || !strings.Contains(err.Error(), "no manifests found") {
+		t.Fatalf("readManifests() error = %v, want no manifests found", err)
+	}
+}
+
+func TestReadManifestsURL(t *testing.T) {
+	t.Parallel()
+
+	body := "apiVersion: haproxyctl/v1\nkind: Frontend\nname: web\n---\napiVersion: haproxyctl/v1\nkind: Backend\nname: app\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/golden.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	sum := sha256.Sum256([]byte(body))
+	checksum := "sha256:" + hex.EncodeToString(sum[:])
+
+	manifests, err := readManifests(srv.URL+"/golden.yaml", manifestOptions{Checksum: checksum})
+	if err != nil {
+		t.Fatalf("readManifests() error = %v", err)
+	}
+	if len(manifests) != 2 || manifests[0].Kind != "Backend" || manifests[1].Kind != "Frontend" {
+		t.Fatalf("unexpected manifests: %v", manifests)
+	}
+
+	_, err = readManifests(srv.URL+"/golden.yaml", manifestOptions{Checksum: "sha256:" + strings.Repeat("0", 64)})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("readManifests() with wrong checksum error = %v, want checksum mismatch", err)
+	}
+
+	_, err = readManifests(srv.URL+"/missing.yaml", manifestOptions{})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("readManifests() for missing URL error = %v, want 404", err)
+	}
+}
+
+func newApplyTestCmd(t *testing.T, flags map[string]string) *cobra.Command {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("output", "o", "", "")
+	cmd.Flags().Bool("dry-run", false, "")
+	cmd.Flags().Bool("prune", false, "")
+	for name, value := range flags {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("set --%s: %v", name, err)
+		}
+	}
+	return cmd
+}
+
+func TestApplyPrune(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddServer("web", map[string]interface{}{"name": "s1", "address": "10.0.0.1", "port": 80})
+	srv.AddServer("web", map[string]interface{}{"name": "old", "address": "10.0.0.9", "port": 80})
+	srv.AddBackend(map[string]interface{}{"name": "legacy"})
+	srv.AddFrontend(map[string]interface{}{"name": "stale"})
+
+	dir := t.TempDir()
+	backend := "apiVersion: haproxyctl/v1\nkind: Backend\nname: web\nmode: http\nservers:\n  - name: s1\n    address: 10.0.0.1\n    port: 80\n"
+	frontend := "apiVersion: haproxyctl/v1\nkind: Frontend\nname: main\nmode: http\ndefault_backend: web\n"
+	if err := os.WriteFile(filepath.Join(dir, "web.yaml"), []byte(backend+"---\n"+frontend), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		cmd := newApplyTestCmd(t, map[string]string{"prune": "true", "dry-run": "true"})
+		if err := applyFromPath(cmd, dir, manifestOptions{}); err != nil {
+			t.Fatalf("dry-run apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/legacy pruned (dry run)") {
+		t.Fatalf("expected dry-run prune of legacy, got:\n%s", output)
+	}
+	if _, ok := srv.Backend("legacy"); !ok {
+		t.Fatal("dry run must not delete anything")
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		cmd := newApplyTestCmd(t, map[string]string{"prune": "true"})
+		if err := applyFromPath(cmd, dir, manifestOptions{}); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+
+	for _, want := range []string{
+		"frontend/main created",
+		"frontend/stale pruned",
+		"backend/legacy pruned",
+		"Summary: 1 created, 1 configured, 0 unchanged, 2 pruned",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if _, ok := srv.Backend("legacy"); ok {
+		t.Error("backend legacy was not pruned")
+	}
+	if _, ok := srv.Frontend("stale"); ok {
+		t.Error("frontend stale was not pruned")
+	}
+	if got := len(srv.Servers("web")); got != 1 {
+		t.Errorf("servers of web = %d, want 1", got)
+	}
+}
+
+func TestApplySelector_V2(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "legacy"})
+
+	dir := t.TempDir()
+	manifests := `apiVersion: haproxyctl/v2
+kind: Backend
+metadata:
+  name: web
+  labels: {team: web}
+spec:
+  mode: http
+---
+apiVersion: haproxyctl/v2
+kind: Backend
+metadata:
+  name: api
+  labels: {team: api}
+spec:
+  mode: http
+`
+	if err := os.WriteFile(filepath.Join(dir, "backends.yaml"), []byte(manifests), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		cmd := newApplyTestCmd(t, map[string]string{"prune": "true"})
+		if err := applyFromPath(cmd, dir, manifestOptions{Selector: "team=web"}); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/web created") || !strings.Contains(output, "backend/legacy pruned") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if _, ok := srv.Backend("api"); ok {
+		t.Error("backend api was applied although the selector left it out")
+	}
+
+	// The unselected document still protects its object from pruning.
+	srv.AddBackend(map[string]interface{}{"name": "api", "mode": "http"})
+	_ = internal.CaptureStdout(t, func() {
+		cmd := newApplyTestCmd(t, map[string]string{"prune": "true"})
+		if err := applyFromPath(cmd, dir, manifestOptions{Selector: "team=web"}); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if _, ok := srv.Backend("api"); !ok {
+		t.Error("backend api was pruned although a manifest describes it")
+	}
+
+	cmd := newApplyTestCmd(t, nil)
+	if err := applyFromPath(cmd, dir, manifestOptions{Selector: "team=db"}); err == nil || !strings.Contains(err.Error(), "no manifests match") {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+}
+
+func TestApplyAndDeleteManifestList(t *testing.T) {
+	srv := testserver.New(t)
+
+	path := filepath.Join(t.TempDir(), "list.yaml")
+	list := `apiVersion: haproxyctl/v1
+kind: List
+items:
+- apiVersion: haproxyctl/v1
+  kind: Frontend
+  name: web
+  mode: http
+  default_backend: app
+- apiVersion: haproxyctl/v1
+  kind: Backend
+  name: app
+  mode: http
+  servers:
+  - name: app1
+    address: 10.0.0.1
+    port: 8080
+`
+	if err := os.WriteFile(path, []byte(list), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := applyFromPath(newApplyTestCmd(t, nil), path, manifestOptions{}); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/app created") || !strings.Contains(output, "frontend/web created") {
+		t.Fatalf("unexpected apply output:\n%s", output)
+	}
+	if servers := srv.Servers("app"); len(servers) != 1 {
+		t.Fatalf("servers = %+v, want app1", servers)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := deleteFromFile(path); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+	})
+	// The frontend refers to the backend, so it goes first.
+	if strings.Index(output, "frontend/web deleted") > strings.Index(output, "backend/app deleted") || !strings.Contains(output, "backend/app deleted") {
+		t.Fatalf("unexpected delete output:\n%s", output)
+	}
+	if _, ok := srv.Backend("app"); ok {
+		t.Error("backend app still exists")
+	}
+	if _, ok := srv.Frontend("web"); ok {
+		t.Error("frontend web still exists")
+	}
+}
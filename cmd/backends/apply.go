@@ -1,19 +1,37 @@
 package backends
 
 import (
+	"context"
 	"fmt"
+	"haproxyctl/cmd/filters"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/logtargets"
 	"haproxyctl/cmd/servers"
 	"haproxyctl/internal"
 	"reflect"
-	"strconv"
 
 	"gopkg.in/yaml.v2"
 )
 
+// ValidateBackendFromYAML checks a backend manifest the way apply does,
+// without contacting the Data Plane API.
+func ValidateBackendFromYAML(data []byte) error {
+	var manifest backendWithServers
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse backend manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid backend configuration: %w", err)
+	}
+	return nil
+}
+
 // ApplyBackendFromYAML applies a backend manifest in a declarative way:
 //   - If the backend does not exist, it is created along with its servers.
 //   - If it exists, it is replaced via PUT and servers are reconciled using
 //     the same diff logic as the interactive edit flow.
+//   - Stick rules, server switching rules, filters and log targets are
+//     reconciled only when the manifest lists them.
 func ApplyBackendFromYAML(data []byte, outputFormat string, dryRun bool) error {
 	var manifest backendWithServers
 	if err := yaml.Unmarshal(data, &manifest); err != nil {
@@ -23,6 +41,8 @@ func ApplyBackendFromYAML(data []byte, outputFormat string, dryRun bool) error {
 	if err := manifest.Validate(); err != nil {
 		return fmt.Errorf("invalid backend configuration: %w", err)
 	}
+	httperrors.SortErrorFiles(manifest.ErrorFiles)
+	manifest.normalizeDurations()
 
 	// Preview/dry-run behaviour mirrors `create backends`.
 	if outputFormat != "" || dryRun {
@@ -42,94 +62,193 @@ func ApplyBackendFromYAML(data []byte, outputFormat string, dryRun bool) error {
 
 	// Determine whether the backend exists and, if it does, capture its
 	// current configuration so we can detect no-op applies.
-	path := "/services/haproxy/configuration/backends/" + name
-	rawBackend, err := internal.GetResource(path)
-	notFound := false
+	current, exists, err := fetchCurrentBackend(context.Background(), name)
 	if err != nil {
-		if internal.IsNotFoundError(err) {
-			notFound = true
-			rawBackend = nil
-		} else {
-			return fmt.Errorf("failed to check backend existence: %w", err)
-		}
+		return err
 	}
 
-	version, err := internal.GetConfigurationVersion()
+	payload, err := manifest.toClient()
 	if err != nil {
-		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+		return err
+	}
+
+	if !exists {
+		// Create backend, then create servers to match manifest, all in
+		// one transaction so a failing server leaves nothing behind.
+		err := internal.WithTransaction(func(tx *internal.Transaction) error {
+			return createBackendInTransaction(tx, manifest)
+		})
+		if err != nil {
+			return err
+		}
+
+		internal.PrintStatus("Backend", name, internal.ActionCreated)
+		return nil
+	}
+
+	before := current.Servers
+	manageStickRules := manifest.StickRules != nil
+	manageSwitchingRules := manifest.ServerSwitchingRules != nil
+	manageFilters := manifest.Filters != nil
+	manageLogTargets := manifest.LogTargets != nil
+	manageHTTPChecks := manifest.HTTPChecks != nil
+	manageTCPChecks := manifest.TCPChecks != nil
+
+	if reflect.DeepEqual(current.backendConfig, manifest.backendConfig) &&
+		serversEqualByName(before, manifest.Servers) &&
+		(!manageStickRules || internal.IndexedEqual(current.StickRules, manifest.StickRules)) &&
+		(!manageSwitchingRules || internal.IndexedEqual(current.ServerSwitchingRules, manifest.ServerSwitchingRules)) &&
+		(!manageFilters || internal.IndexedEqual(current.Filters, manifest.Filters)) &&
+		(!manageLogTargets || internal.IndexedEqual(current.LogTargets, manifest.LogTargets)) &&
+		(!manageHTTPChecks || httpChecksEqual(current.HTTPChecks, manifest.HTTPChecks)) &&
+		(!manageTCPChecks || internal.IndexedEqual(current.TCPChecks, manifest.TCPChecks)) {
+		internal.PrintStatus("Backend", name, internal.ActionUnchanged)
+		return nil
 	}
 
-	payload := manifest.toPayload()
+	// Update existing backend via PUT, then reconcile servers using the
+	// same diff logic as the interactive edit flow, in one transaction.
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		if err := internal.API().UpdateBackend(context.Background(), tx.Client(), payload); err != nil {
+			return fmt.Errorf("failed to update backend %q: %w", name, err)
+		}
 
-	if notFound {
-		// Create backend, then create servers to match manifest.
-		if _, err := internal.SendRequest(
-			"POST",
-			"/services/haproxy/configuration/backends",
-			map[string]string{"version": strconv.Itoa(version)},
-			payload,
-		); err != nil {
-			return fmt.Errorf("failed to create backend %q: %w", name, err)
+		if err := applyServerDiff(tx, name, before, manifest.Servers); err != nil {
+			return fmt.Errorf("failed to apply server changes for backend %q: %w", name, err)
 		}
 
-		for _, srv := range manifest.Servers {
-			srv.Backend = name
-			if err := servers.CreateServer(srv, "", false); err != nil {
-				return fmt.Errorf("failed to create server %q for backend %q: %w", srv.Name, name, err)
+		if manageStickRules {
+			if err := applyStickRuleDiff(tx, name, current.StickRules, manifest.StickRules); err != nil {
+				return fmt.Errorf("failed to apply stick rule changes for backend %q: %w", name, err)
+			}
+		}
+		if manageSwitchingRules {
+			if err := applyServerSwitchingRuleDiff(tx, name, current.ServerSwitchingRules, manifest.ServerSwitchingRules); err != nil {
+				return fmt.Errorf("failed to apply server switching rule changes for backend %q: %w", name, err)
+			}
+		}
+		if manageFilters {
+			if err := filters.ApplyDiff(tx, filters.ParentBackend, name, current.Filters, manifest.Filters); err != nil {
+				return fmt.Errorf("failed to apply filter changes for backend %q: %w", name, err)
+			}
+		}
+		if manageLogTargets {
+			if err := logtargets.ApplyDiff(tx, logtargets.ParentBackend, name, current.LogTargets, manifest.LogTargets); err != nil {
+				return fmt.Errorf("failed to apply log target changes for backend %q: %w", name, err)
+			}
+		}
+		if manageHTTPChecks {
+			if err := applyHTTPCheckDiff(tx, name, current.HTTPChecks, manifest.HTTPChecks); err != nil {
+				return fmt.Errorf("failed to apply http check changes for backend %q: %w", name, err)
+			}
+		}
+		if manageTCPChecks {
+			if err := applyTCPCheckDiff(tx, name, current.TCPChecks, manifest.TCPChecks); err != nil {
+				return fmt.Errorf("failed to apply tcp check changes for backend %q: %w", name, err)
 			}
 		}
-
-		internal.PrintStatus("Backend", name, internal.ActionCreated)
 		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Fetch current servers to compute the diff and to detect no-op applies.
-	rawServers, err := internal.GetResourceList(
-		"/services/haproxy/configuration/backends/" + name + "/servers",
+	internal.PrintStatus("Backend", name, internal.ActionConfigured)
+	return nil
+}
+
+// createBackendInTransaction stages a new backend with its servers, rules,
+// filters, log targets and checks in tx.
+func createBackendInTransaction(tx *internal.Transaction, manifest backendWithServers) error {
+	name := manifest.Name
+	payload, err := manifest.toClient()
+	if err != nil {
+		return err
+	}
+	if err := internal.API().CreateBackend(context.Background(), tx.Client(), payload); err != nil {
+		return fmt.Errorf("failed to create backend %q: %w", name, err)
+	}
+
+	for _, srv := range manifest.Servers {
+		srv.Backend = name
+		if err := servers.CreateServerInTransaction(tx, srv); err != nil {
+			return fmt.Errorf("failed to create server %q for backend %q: %w", srv.Name, name, err)
+		}
+	}
+	if err := applyStickRuleDiff(tx, name, nil, manifest.StickRules); err != nil {
+		return fmt.Errorf("failed to create stick rules for backend %q: %w", name, err)
+	}
+	if err := applyServerSwitchingRuleDiff(tx, name, nil, manifest.ServerSwitchingRules); err != nil {
+		return fmt.Errorf("failed to create server switching rules for backend %q: %w", name, err)
+	}
+	if err := filters.ApplyDiff(tx, filters.ParentBackend, name, nil, manifest.Filters); err != nil {
+		return fmt.Errorf("failed to create filters for backend %q: %w", name, err)
+	}
+	if err := logtargets.ApplyDiff(tx, logtargets.ParentBackend, name, nil, manifest.LogTargets); err != nil {
+		return fmt.Errorf("failed to create log targets for backend %q: %w", name, err)
+	}
+	if err := applyHTTPCheckDiff(tx, name, nil, manifest.HTTPChecks); err != nil {
+		return fmt.Errorf("failed to create http checks for backend %q: %w", name, err)
+	}
+	if err := applyTCPCheckDiff(tx, name, nil, manifest.TCPChecks); err != nil {
+		return fmt.Errorf("failed to create tcp checks for backend %q: %w", name, err)
+	}
+	return nil
+}
+
+// fetchCurrentBackend returns the live backend, its servers, its stick
+// rules, its server switching rules and its filters in manifest
+// form, normalized the same way as manifests read from files. exists is
+// false when the backend is not configured.
+func fetchCurrentBackend(ctx context.Context, name string) (current backendWithServers, exists bool, err error) {
+	rawBackend, err := internal.GetResourceWithContext(ctx, "/services/haproxy/configuration/backends/"+name)
+	if err != nil {
+		if internal.IsNotFoundError(err) {
+			return current, false, nil
+		}
+		return current, false, fmt.Errorf("failed to check backend existence: %w", err)
+	}
+
+	rawServers, err := internal.GetResourceListWithContext(ctx,
+		"/services/haproxy/configuration/backends/"+name+"/servers",
 	)
 	if err != nil && !internal.IsNotFoundError(err) {
-		return fmt.Errorf("failed to fetch existing servers for backend %q: %w", name, err)
+		return current, false, fmt.Errorf("failed to fetch existing servers for backend %q: %w", name, err)
 	}
 
-	var before []servers.ServerConfig
+	populateBackendConfigFromMap(&current.backendConfig, rawBackend)
 	for _, srv := range rawServers {
 		sc := mapServerFromAPI(name, srv)
 		if sc.Name != "" && sc.Address != "" && sc.Port != 0 {
-			before = append(before, sc)
+			current.Servers = append(current.Servers, sc)
 		}
 	}
 
-	// Build a normalized view of the current backend (config + servers) to
-	// detect if this apply would be a no-op.
-	var current backendWithServers
-	if rawBackend != nil {
-		populateBackendConfigFromMap(&current.backendConfig, rawBackend)
-		current.Servers = before
+	current.StickRules, err = liveStickRules(ctx, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch stick rules for backend %q: %w", name, err)
 	}
-
-	if reflect.DeepEqual(current.backendConfig, manifest.backendConfig) &&
-		serversEqualByName(before, manifest.Servers) {
-		internal.PrintStatus("Backend", name, internal.ActionUnchanged)
-		return nil
+	current.ServerSwitchingRules, err = liveServerSwitchingRules(ctx, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch server switching rules for backend %q: %w", name, err)
 	}
-
-	// Update existing backend via PUT, then reconcile servers using the
-	// same diff logic as the interactive edit flow.
-	if _, err := internal.SendRequest(
-		"PUT",
-		path,
-		map[string]string{"version": strconv.Itoa(version)},
-		payload,
-	); err != nil {
-		return fmt.Errorf("failed to update backend %q: %w", name, err)
+	current.Filters, err = filters.LiveWithContext(ctx, filters.ParentBackend, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch filters for backend %q: %w", name, err)
 	}
-
-	if err := applyServerDiff(name, before, manifest.Servers); err != nil {
-		return fmt.Errorf("failed to apply server changes for backend %q: %w", name, err)
+	current.LogTargets, err = logtargets.LiveWithContext(ctx, logtargets.ParentBackend, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch log targets for backend %q: %w", name, err)
 	}
-
-	internal.PrintStatus("Backend", name, internal.ActionConfigured)
-	return nil
+	current.HTTPChecks, err = liveHTTPChecks(ctx, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch http checks for backend %q: %w", name, err)
+	}
+	current.TCPChecks, err = liveTCPChecks(ctx, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch tcp checks for backend %q: %w", name, err)
+	}
+	return current, true, nil
 }
 
 // serversEqualByName compares two slices of ServerConfig using the same
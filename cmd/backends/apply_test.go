@@ -0,0 +1,251 @@
+package backends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/cmd/servers"
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testBackendManifest = `apiVersion: haproxyctl/v1
+kind: Backend
+name: web
+mode: http
+balance:
+  algorithm: roundrobin
+servers:
+  - name: s1
+    address: 10.0.0.1
+    port: 80
+    weight: 100
+`
+
+func TestApplyBackendFromYAML_CreateThenUnchanged(t *testing.T) {
+	srv := testserver.New(t)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testBackendManifest), "", false); err != nil {
+			t.Fatalf("first apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/web created") {
+		t.Fatalf("expected created status, got:\n%s", output)
+	}
+
+	if _, ok := srv.Backend("web"); !ok {
+		t.Fatal("backend web was not created")
+	}
+	if got := len(srv.Servers("web")); got != 1 {
+		t.Fatalf("servers = %d, want 1", got)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testBackendManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+}
+
+func TestApplyBackendFromYAML_ReconcilesServers(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "web", "mode": "http", "balance": map[string]interface{}{"algorithm": "roundrobin"}})
+	srv.AddServer("web", map[string]interface{}{"name": "old", "address": "10.0.0.9", "port": 80})
+
+	internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testBackendManifest), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+
+	got := srv.Servers("web")
+	if len(got) != 1 || got[0]["name"] != "s1" {
+		t.Fatalf("servers after apply = %+v, want only s1", got)
+	}
+}
+
+func TestApplyBackendFromYAML_SingleTransaction(t *testing.T) {
+	srv := testserver.New(t)
+
+	manifest := testBackendManifest + `  - name: s2
+    address: 10.0.0.2
+    port: 80
+`
+	internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+
+	// Backend and both servers land in one commit: one version bump.
+	if srv.Version() != 2 {
+		t.Fatalf("version = %d, want 2", srv.Version())
+	}
+	if n := srv.CountRequests("POST", "/v3/services/haproxy/transactions"); n != 1 {
+		t.Fatalf("transactions started = %d, want 1", n)
+	}
+}
+
+func TestApplyBackendFromYAML_FailedServerLeavesNothing(t *testing.T) {
+	srv := testserver.New(t)
+
+	// The second s1 conflicts inside the transaction.
+	manifest := testBackendManifest + `  - name: s1
+    address: 10.0.0.2
+    port: 80
+`
+	internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(manifest), "", false); err == nil {
+			t.Fatal("expected apply to fail")
+		}
+	})
+
+	if _, ok := srv.Backend("web"); ok {
+		t.Fatal("backend web was created despite the failed apply")
+	}
+	if srv.Version() != 1 {
+		t.Fatalf("version = %d, want 1", srv.Version())
+	}
+}
+
+func TestApplyBackendFromYAML_FullFieldsRoundTrip(t *testing.T) {
+	srv := testserver.New(t)
+
+	manifest := `apiVersion: haproxyctl/v1
+kind: Backend
+name: app
+mode: http
+retries: 3
+retry_on: conn-failure empty-response
+http_reuse: safe
+http_connection_mode: http-server-close
+fullconn: 500
+timeout_connect: 5s
+timeout_check: 2s
+timeout_tunnel: 1h
+abortonclose: enabled
+persist: disabled
+hash_type:
+  method: consistent
+cookie:
+  name: SRV
+  type: insert
+  indirect: true
+  nocache: true
+  domains: [example.com]
+  maxidle: 1800
+`
+	internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("first apply failed: %v", err)
+		}
+	})
+	backend, _ := srv.Backend("app")
+	if backend["timeout_connect"] != float64(5000) || backend["retries"] != float64(3) || backend["abortonclose"] != "enabled" {
+		t.Fatalf("fields not sent in API form: %+v", backend)
+	}
+	cookie, _ := backend["cookie"].(map[string]interface{})
+	if cookie["indirect"] != true || cookie["domain"] == nil {
+		t.Fatalf("cookie = %+v", cookie)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/app unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	if err := ApplyBackendFromYAML([]byte(strings.Replace(manifest, "http_reuse: safe", "http_reuse: sometimes", 1)), "", false); err == nil {
+		t.Fatal("expected error for an invalid http_reuse")
+	}
+}
+
+func TestApplyBackendFromYAML_ServerOptionsRoundTrip(t *testing.T) {
+	srv := testserver.New(t)
+
+	manifest := testBackendManifest + `    check: true
+    inter: 2s
+    rise: 2
+    fall: 3
+    maxconn: 100
+    cookie: s1
+    ssl: true
+    verify: none
+    sni: req.hdr(host)
+    alpn: h2,http/1.1
+    proto: h2
+    send_proxy_v2: true
+  - name: spare
+    address: 10.0.0.9
+    port: 80
+    backup: true
+`
+	internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("first apply failed: %v", err)
+		}
+	})
+	var s1, spare map[string]interface{}
+	for _, s := range srv.Servers("web") {
+		switch s["name"] {
+		case "s1":
+			s1 = s
+		case "spare":
+			spare = s
+		}
+	}
+	if s1["check"] != "enabled" || s1["inter"] != float64(2000) || s1["send-proxy-v2"] != "enabled" || s1["proto"] != "h2" {
+		t.Fatalf("server options not sent in API form: %+v", s1)
+	}
+	if spare["backup"] != "enabled" {
+		t.Fatalf("backup not sent: %+v", spare)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	if err := ApplyBackendFromYAML([]byte(strings.Replace(manifest, "verify: none", "verify: maybe", 1)), "", false); err == nil {
+		t.Fatal("expected error for an invalid verify")
+	}
+}
+
+func TestApplyServerDiff_FetchesVersionOnce(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddServer("web", map[string]interface{}{"name": "old", "address": "10.0.0.9", "port": 80})
+
+	before := []servers.ServerConfig{{Name: "old", Address: "10.0.0.9", Port: 80}}
+	after := []servers.ServerConfig{
+		{Name: "s1", Address: "10.0.0.1", Port: 80},
+		{Name: "s2", Address: "10.0.0.2", Port: 80},
+		{Name: "s3", Address: "10.0.0.3", Port: 80},
+	}
+	_ = internal.CaptureStdout(t, func() {
+		if err := applyServerDiff(nil, "web", before, after); err != nil {
+			t.Fatalf("applyServerDiff failed: %v", err)
+		}
+	})
+
+	if got := len(srv.Servers("web")); got != 3 {
+		t.Fatalf("backend has %d servers, want 3", got)
+	}
+	if srv.Version() != 5 {
+		t.Fatalf("version = %d, want 5 (four changes)", srv.Version())
+	}
+	if n := srv.CountRequests("GET", "/v3/services/haproxy/configuration/version"); n != 1 {
+		t.Fatalf("fetched the configuration version %d times for four changes, want 1", n)
+	}
+}
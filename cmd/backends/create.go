@@ -19,10 +19,7 @@ package backends
 
 import (
 	"fmt"
-	"haproxyctl/cmd/servers"
 	"haproxyctl/internal"
-	"log"
-	"strconv"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -40,6 +37,8 @@ Examples:
     --balance algorithm=roundrobin \
     --server name=s1,address=10.0.0.1,port=80,weight=100 \
     --server name=s2,address=10.0.0.2,port=8080,weight=200
+  haproxyctl create backends mybackend --http-check "GET /healthz" --http-check-expect "status 200"
+  haproxyctl create backends mybackend --retries 3 --timeout-connect 5s --cookie "SRV insert indirect nocache" --option abortonclose
   haproxyctl create backends mybackend -f mybackend.yaml`,
 
 	Args: cobra.ExactArgs(1),
@@ -50,14 +49,14 @@ Examples:
 		backendWithServers.LoadFromFlags(cmd, backendName)
 
 		if err := backendWithServers.Validate(); err != nil {
-			log.Fatalf("Invalid backend configuration: %v", err)
+			internal.FatalCodef(internal.ExitInvalid, "Invalid backend configuration: %v", err)
 		}
 
 		outputFormat := internal.GetFlagString(cmd, "output")
-		dryRun := internal.GetFlagBool(cmd, "dry-run")
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
 
 		if err := createBackend(backendWithServers, outputFormat, dryRun); err != nil {
-			log.Fatalf("Failed to create backend: %v", err)
+			internal.Fatalf("Failed to create backend: %v", err)
 		}
 	},
 }
@@ -73,13 +72,14 @@ func CreateBackendFromFile(data []byte) error {
 		return fmt.Errorf("invalid backend configuration: %w", err)
 	}
 
-	if err := createBackend(backendWithServers, "", false); err != nil {
+	if err := createBackend(backendWithServers, "", internal.IsOffline()); err != nil {
 		return internal.FormatAPIError("Backend", backendWithServers.Name, "create", err)
 	}
 	return nil
 }
 
-// createBackend handles backend creation with validation.
+// createBackend handles backend creation with validation. The backend and
+// its servers are created in a single transaction.
 func createBackend(backendWithServers backendWithServers, outputFormat string, dryRun bool) error {
 	if outputFormat != "" || dryRun {
 		// For structured formats, preview the actual API payload
@@ -98,28 +98,16 @@ func createBackend(backendWithServers backendWithServers, outputFormat string, d
 		return nil
 	}
 
-	version, err := internal.GetConfigurationVersion()
+	// The backend and everything attached to it go in one transaction, so
+	// HAProxy reloads once and a failing server leaves nothing behind.
+	err := internal.WithTransaction(func(tx *internal.Transaction) error {
+		return createBackendInTransaction(tx, backendWithServers)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
-	}
-
-	_, err = internal.SendRequest("POST", "/services/haproxy/configuration/backends",
-		map[string]string{"version": strconv.Itoa(version)},
-		backendWithServers.toPayload(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create backend '%s': %w", backendWithServers.Name, err)
+		return err
 	}
 	internal.PrintStatus("Backend", backendWithServers.Name, internal.ActionCreated)
 
-	// Create attached servers if any
-	for _, server := range backendWithServers.Servers {
-		server.Backend = backendWithServers.Name // Set the backend name directly
-		if err := servers.CreateServer(server, outputFormat, dryRun); err != nil {
-			return fmt.Errorf("failed to create server '%s' for backend '%s': %w", server.Name, backendWithServers.Name, err)
-		}
-	}
-
 	return nil
 }
 
@@ -132,11 +120,29 @@ func init() {
 	CreateBackendsCmd.Flags().String("timeout-client", "", "Client timeout (e.g., 30s)")
 	CreateBackendsCmd.Flags().String("timeout-queue", "", "Queue timeout (e.g., 30s)")
 	CreateBackendsCmd.Flags().String("timeout-server", "", "Server timeout (e.g., 30s)")
+	CreateBackendsCmd.Flags().String("timeout-connect", "", "Connect timeout (e.g., 5s)")
+	CreateBackendsCmd.Flags().String("timeout-check", "", "Health check read timeout (e.g., 2s)")
+	CreateBackendsCmd.Flags().String("timeout-tunnel", "", "Tunnel (e.g. WebSocket) inactivity timeout (e.g., 1h)")
+
+	CreateBackendsCmd.Flags().Int("retries", 0, "Connection retries to a server before giving up")
+	CreateBackendsCmd.Flags().String("retry-on", "", "Failures to retry on, e.g. \"conn-failure empty-response 503\"")
+	CreateBackendsCmd.Flags().String("http-reuse", "", "Connection reuse: never, safe, aggressive or always")
+	CreateBackendsCmd.Flags().String("http-connection-mode", "", "httpclose, http-server-close or http-keep-alive")
+	CreateBackendsCmd.Flags().Int("fullconn", 0, "Load at which servers reach their maxconn (for minconn)")
+	CreateBackendsCmd.Flags().String("cookie", "", "Cookie persistence, e.g. \"SRV insert indirect nocache\"")
+	CreateBackendsCmd.Flags().StringArray("option", nil, "Enable an option such as abortonclose or prefer-last-server; no-<option> disables it. Repeatable.")
 
 	CreateBackendsCmd.Flags().Bool("redispatch", false, "Enable redispatch")
+	CreateBackendsCmd.Flags().String("stick-table-type", "", "Declare a stick table of this type (ip, ipv6, integer, string, binary)")
+	CreateBackendsCmd.Flags().Int("stick-table-size", 0, "Stick table size in entries")
+	CreateBackendsCmd.Flags().String("stick-table-expire", "", "Stick table entry expiry (e.g., 30m)")
+	CreateBackendsCmd.Flags().String("stick-table-store", "", "Stick table data types to store (e.g., conn_cur,http_req_rate(10s))")
+	CreateBackendsCmd.Flags().String("stick-on", "", "Add a \"stick on <pattern>\" rule, e.g. src")
+	CreateBackendsCmd.Flags().String("http-check", "", "Enable option httpchk with \"[METHOD] URI [VERSION]\", e.g. \"GET /healthz\"")
+	CreateBackendsCmd.Flags().String("http-check-expect", "", "Add an \"http-check expect <match> <pattern>\" rule, e.g. \"status 200\"")
 
 	// Server flag supports multiple servers
-	CreateBackendsCmd.Flags().StringArray("server", nil, "Define server (name=s1,address=10.0.0.1,port=80,weight=100). Repeat for multiple servers.")
+	CreateBackendsCmd.Flags().StringArray("server", nil, "Define server (name=s1,address=10.0.0.1,port=80,weight=100; also check, inter, rise, fall, backup, maxconn, cookie, ssl, verify, sni, alpn, proto, send_proxy). Repeat for multiple servers.")
 
 	// Output and dry-run
 	CreateBackendsCmd.Flags().StringP("output", "o", "", "Output format: yaml or json")
@@ -0,0 +1,51 @@
+package backends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestCreateBackendFromFile_SingleTransaction(t *testing.T) {
+	srv := testserver.New(t)
+
+	manifest := testBackendManifest + `  - name: s2
+    address: 10.0.0.2
+    port: 80
+  - name: s3
+    address: 10.0.0.3
+    port: 80
+`
+	output := internal.CaptureStdout(t, func() {
+		if err := CreateBackendFromFile([]byte(manifest)); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	for _, want := range []string{"backend/web created", "server/web/s1 created", "server/web/s3 created"} {
+		if !strings.Contains(strings.ToLower(output), want) {
+			t.Fatalf("output missing %q:\n%s", want, output)
+		}
+	}
+	if got := len(srv.Servers("web")); got != 3 {
+		t.Fatalf("backend has %d servers, want 3", got)
+	}
+	if srv.Version() != 2 {
+		t.Fatalf("version = %d, want 2 (backend and servers in one commit)", srv.Version())
+	}
+
+	// A server that cannot be created leaves nothing behind.
+	broken := strings.Replace(testBackendManifest, "name: web", "name: api", 1) + `  - name: s1
+    address: 10.0.0.9
+    port: 81
+`
+	_ = internal.CaptureStdout(t, func() {
+		if err := CreateBackendFromFile([]byte(broken)); err == nil {
+			t.Fatal("expected an error for a conflicting server")
+		}
+	})
+	if _, ok := srv.Backend("api"); ok || srv.Version() != 2 {
+		t.Fatalf("backend created despite the failing server (version %d)", srv.Version())
+	}
+}
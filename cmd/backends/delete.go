@@ -18,9 +18,9 @@ limitations under the License.
 package backends
 
 import (
+	"context"
+
 	"haproxyctl/internal"
-	"log"
-	"strconv"
 
 	"github.com/spf13/cobra"
 )
@@ -30,27 +30,16 @@ var DeleteBackendsCmd = &cobra.Command{
 	Use:   "backends <backend_name>",
 	Short: "Delete a specific HAProxy backend",
 	Args:  cobra.ExactArgs(1),
-	Run: func(_ *cobra.Command, args []string) {
+	Run: func(cmd *cobra.Command, args []string) {
 		backendName := args[0]
-		deleteBackend(backendName)
+		deleteBackend(cmd.Context(), backendName)
 	},
 }
 
 // deleteBackend handles backend deletion.
-func deleteBackend(backendName string) {
-	version, err := internal.GetConfigurationVersion()
-	if err != nil {
-		log.Fatalf("Failed to fetch HAProxy configuration version: %v", err)
-	}
-
-	endpoint := "/services/haproxy/configuration/backends/" + backendName
-	_, err = internal.SendRequest("DELETE",
-		endpoint,
-		map[string]string{"version": strconv.Itoa(version)},
-		nil,
-	)
-	if err != nil {
-		log.Fatalf("Failed to delete backend '%s': %v", backendName, err)
+func deleteBackend(ctx context.Context, backendName string) {
+	if err := internal.API().DeleteBackend(ctx, nil, backendName); err != nil {
+		internal.Fatalf("Failed to delete backend '%s': %v", backendName, err)
 	}
 
 	internal.PrintStatus("Backend", backendName, internal.ActionDeleted)
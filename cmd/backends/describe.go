@@ -18,9 +18,9 @@ limitations under the License.
 package backends
 
 import (
+	"context"
 	"fmt"
 	"haproxyctl/internal"
-	"log"
 	"os"
 	"strconv"
 	"strings"
@@ -32,58 +32,67 @@ import (
 var DescribeBackendsCmd = &cobra.Command{
 	Use:   "backends <backend_name>",
 	Short: "Describe a specific HAProxy backend and its servers",
-	Args:  cobra.ExactArgs(1),
-	Run: func(_ *cobra.Command, args []string) {
+	Long: `Describe a backend: every setting it has, grouped into sections such
+as timeouts, health checks and persistence, followed by its servers, rules
+and checks. --show-empty also lists the settings that are not set.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
 		backendName := args[0]
-		describeBackend(backendName)
+		describeBackend(cmd.Context(), backendName, internal.GetFlagBool(cmd, "show-empty"))
 	},
 }
 
+func init() {
+	DescribeBackendsCmd.Flags().Bool("show-empty", false, "Also list settings that are not set")
+}
+
 // describeBackend fetches a backend and its servers, and prints a detailed description.
-func describeBackend(backendName string) {
-	backend, err := internal.GetResource("/services/haproxy/configuration/backends/" + backendName)
+func describeBackend(ctx context.Context, backendName string, showEmpty bool) {
+	backend, err := internal.GetResourceWithContext(ctx, "/services/haproxy/configuration/backends/"+backendName)
 	if err != nil {
-		log.Fatalf("Failed to fetch backend '%s': %v", backendName, err)
+		internal.Fatalf("Failed to fetch backend '%s': %v", backendName, err)
 	}
 
-	servers, err := internal.GetResourceList("/services/haproxy/configuration/backends/" + backendName + "/servers")
+	servers, err := internal.GetResourceListWithContext(ctx, "/services/haproxy/configuration/backends/"+backendName+"/servers")
 	if err != nil {
-		log.Fatalf("Failed to fetch servers for backend '%s': %v", backendName, err)
+		internal.Fatalf("Failed to fetch servers for backend '%s': %v", backendName, err)
 	}
 
-	httpRequestRules := fetchBackendListSection(backendName, "HTTP request rules", "/services/haproxy/configuration/backends/"+backendName+"/http_request_rules")
-	httpResponseRules := fetchBackendListSection(backendName, "HTTP response rules", "/services/haproxy/configuration/backends/"+backendName+"/http_response_rules")
-	tcpRequestRules := fetchBackendListSection(backendName, "TCP request rules", "/services/haproxy/configuration/backends/"+backendName+"/tcp_request_rules")
-	httpChecks := fetchBackendListSection(backendName, "HTTP checks", "/services/haproxy/configuration/backends/"+backendName+"/http_checks")
-	tcpChecks := fetchBackendListSection(backendName, "TCP checks", "/services/haproxy/configuration/backends/"+backendName+"/tcp_checks")
+	sections := make([][]map[string]interface{}, len(backendRuleSections))
+	_ = internal.ForEachConcurrent(ctx, len(backendRuleSections), func(ctx context.Context, i int) error {
+		s := backendRuleSections[i]
+		sections[i] = fetchBackendListSection(ctx, backendName, s.title, "/services/haproxy/configuration/backends/"+backendName+"/"+s.child)
+		return nil
+	})
 
-	internal.PrintResourceDescription(backendKind, backend, backendDescriptionSections(), servers)
+	internal.PrintResourceDescription(backendKind, backend, servers, internal.DescribeOptions{
+		Fields:    internal.SchemaFields(backendPayload{}),
+		ShowEmpty: showEmpty,
+	})
 
-	printRuleSection("HTTP Request Rules", httpRequestRules)
-	printRuleSection("HTTP Response Rules", httpResponseRules)
-	printRuleSection("TCP Request Rules", tcpRequestRules)
-	printRuleSection("HTTP Checks", httpChecks)
-	printRuleSection("TCP Checks", tcpChecks)
+	for i, s := range backendRuleSections {
+		printRuleSection(s.title, sections[i])
+	}
 }
 
-// backendDescriptionSections defines the sections and fields to display in backend descriptions.
-func backendDescriptionSections() map[string][]string {
-	return map[string][]string{
-		"basic":          {"name", "mode", "balance"},
-		"timeouts":       {"timeout_client", "timeout_queue", "timeout_server"},
-		"advanced":       {"tcpka", "redispatch"},
-		"default_server": {"alpn", "check", "check_alpn", "maxconn", "weight"},
-	}
+// backendRuleSections are the rule and check lists describe prints after
+// the backend settings, in order.
+var backendRuleSections = []struct{ title, child string }{
+	{"HTTP Request Rules", "http_request_rules"},
+	{"HTTP Response Rules", "http_response_rules"},
+	{"TCP Request Rules", "tcp_request_rules"},
+	{"HTTP Checks", "http_checks"},
+	{"TCP Checks", "tcp_checks"},
 }
 
 // fetchBackendListSection retrieves a list-valued configuration section for a backend,
 // such as HTTP/TCP rules or checks. Failures are logged as warnings so that describe
 // output remains as complete as possible.
-func fetchBackendListSection(backendName, sectionLabel, endpoint string) []map[string]interface{} {
-	list, err := internal.GetResourceList(endpoint)
+func fetchBackendListSection(ctx context.Context, backendName, sectionLabel, endpoint string) []map[string]interface{} {
+	list, err := internal.GetResourceListWithContext(ctx, endpoint)
 	if err != nil {
 		if !internal.IsNotFoundError(err) {
-			log.Printf("warning: failed to fetch %s for backend %q: %v", sectionLabel, backendName, err)
+			internal.Warnf("failed to fetch %s for backend %q: %v", sectionLabel, backendName, err)
 		}
 		return nil
 	}
@@ -97,7 +106,7 @@ func printRuleSection(title string, rules []map[string]interface{}) {
 	}
 
 	if _, err := fmt.Fprintf(os.Stdout, "\n%s:\n", title); err != nil {
-		log.Printf("warning: failed to write %s header: %v", title, err)
+		internal.Warnf("failed to write %s header: %v", title, err)
 		return
 	}
 
@@ -107,7 +116,7 @@ func printRuleSection(title string, rules []map[string]interface{}) {
 			continue
 		}
 		if _, err := fmt.Fprintf(os.Stdout, "- %s\n", line); err != nil {
-			log.Printf("warning: failed to write %s line: %v", title, err)
+			internal.Warnf("failed to write %s line: %v", title, err)
 			return
 		}
 	}
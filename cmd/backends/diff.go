@@ -0,0 +1,93 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backends provides commands to manage HAProxy backends.
+package backends
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"haproxyctl/cmd/servers"
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CompareBackendManifest returns the live and desired state of a backend
+// manifest, normalized the same way apply compares them: servers sorted
+// by name and without their client-side backend reference. Stick rules,
+// server switching rules, filters and log targets are only compared when
+// the manifest lists them.
+func CompareBackendManifest(data []byte) (internal.ManifestComparison, error) {
+	var manifest backendWithServers
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return internal.ManifestComparison{}, fmt.Errorf("failed to parse backend manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return internal.ManifestComparison{}, fmt.Errorf("invalid backend configuration: %w", err)
+	}
+	manifest.normalizeDurations()
+
+	cmp := internal.ManifestComparison{Kind: backendKind, Name: manifest.Name}
+
+	current, exists, err := fetchCurrentBackend(context.Background(), manifest.Name)
+	if err != nil {
+		return cmp, err
+	}
+
+	manifest.APIVersion = "haproxyctl/v1"
+	manifest.Kind = backendKind
+	manifest.Servers = normalizeServers(manifest.Servers)
+	cmp.Desired = manifest
+
+	if exists {
+		current.APIVersion = manifest.APIVersion
+		current.Kind = backendKind
+		current.Servers = normalizeServers(current.Servers)
+		if manifest.StickRules == nil {
+			current.StickRules = nil
+		}
+		if manifest.ServerSwitchingRules == nil {
+			current.ServerSwitchingRules = nil
+		}
+		if manifest.Filters == nil {
+			current.Filters = nil
+		}
+		if manifest.LogTargets == nil {
+			current.LogTargets = nil
+		}
+		if manifest.HTTPChecks == nil {
+			current.HTTPChecks = nil
+		}
+		if manifest.TCPChecks == nil {
+			current.TCPChecks = nil
+		}
+		cmp.Live = current
+	}
+	return cmp, nil
+}
+
+func normalizeServers(in []servers.ServerConfig) []servers.ServerConfig {
+	out := make([]servers.ServerConfig, 0, len(in))
+	for _, s := range in {
+		s.APIVersion, s.Kind, s.Backend, s.Parent = "", "", "", ""
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,65 @@
+package backends
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+
+	"gopkg.in/yaml.v2"
+)
+
+func comparisonDiff(t *testing.T, cmp internal.ManifestComparison) string {
+	t.Helper()
+
+	var live []byte
+	if cmp.Live != nil {
+		var err error
+		if live, err = yaml.Marshal(cmp.Live); err != nil {
+			t.Fatalf("marshal live: %v", err)
+		}
+	}
+	desired, err := yaml.Marshal(cmp.Desired)
+	if err != nil {
+		t.Fatalf("marshal desired: %v", err)
+	}
+
+	var buf bytes.Buffer
+	internal.WriteUnifiedDiff(&buf, string(live), string(desired), "live", "manifest", false)
+	return buf.String()
+}
+
+func TestCompareBackendManifest(t *testing.T) {
+	srv := testserver.New(t)
+
+	cmp, err := CompareBackendManifest([]byte(testBackendManifest))
+	if err != nil {
+		t.Fatalf("CompareBackendManifest() error = %v", err)
+	}
+	if cmp.Live != nil {
+		t.Fatalf("expected no live backend, got %+v", cmp.Live)
+	}
+
+	srv.AddBackend(map[string]interface{}{"name": "web", "mode": "http", "balance": map[string]interface{}{"algorithm": "roundrobin"}})
+	srv.AddServer("web", map[string]interface{}{"name": "s1", "address": "10.0.0.1", "port": 80, "weight": 50})
+
+	cmp, err = CompareBackendManifest([]byte(testBackendManifest))
+	if err != nil {
+		t.Fatalf("CompareBackendManifest() error = %v", err)
+	}
+	diff := comparisonDiff(t, cmp)
+	if !strings.Contains(diff, "-  weight: 50\n+  weight: 100\n") {
+		t.Fatalf("expected only the weight to differ, got:\n%s", diff)
+	}
+	if strings.Contains(diff, "-  name: s1") || strings.Contains(diff, "backend:") {
+		t.Fatalf("unexpected server noise in diff:\n%s", diff)
+	}
+
+	for _, r := range srv.Requests() {
+		if r.Method != "GET" {
+			t.Fatalf("diff must not write, saw %s %s", r.Method, r.Path)
+		}
+	}
+}
@@ -19,12 +19,14 @@ package backends
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"haproxyctl/cmd/filters"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/logtargets"
 	"haproxyctl/cmd/servers"
 	"haproxyctl/internal"
-	"log"
 	"os"
-	"strconv"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -36,34 +38,35 @@ var EditBackendsCmd = &cobra.Command{
 	Aliases: []string{"backend"},
 	Short:   "Edit a backend definition in your editor",
 	Args:    cobra.ExactArgs(1),
-	Run: func(_ *cobra.Command, args []string) {
+	Run: func(cmd *cobra.Command, args []string) {
 		backendName := args[0]
-		if err := editBackend(backendName); err != nil {
-			log.Fatalf("Edit failed: %v", err)
+		if err := editBackend(cmd.Context(), backendName); err != nil {
+			internal.Fatalf("Edit failed: %v", err)
 		}
 	},
 }
 
-func editBackend(backendName string) error {
-	cfgVer, err := internal.GetConfigurationVersion()
-	if err != nil {
+func editBackend(ctx context.Context, backendName string) error {
+	// The update below is based on this version (see internal.WriteParams),
+	// the one the editor starts from.
+	if _, err := internal.GetConfigurationVersion(); err != nil {
 		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
 	}
 
 	// Fetch the current backend and its servers.
-	rawBackend, err := internal.GetResource(
-		"/services/haproxy/configuration/backends/" + backendName,
+	rawBackend, err := internal.GetResourceWithContext(ctx,
+		"/services/haproxy/configuration/backends/"+backendName,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to fetch backend %q: %w", backendName, err)
 	}
 
-	rawServers, err := internal.GetResourceList(
-		"/services/haproxy/configuration/backends/" + backendName + "/servers",
+	rawServers, err := internal.GetResourceListWithContext(ctx,
+		"/services/haproxy/configuration/backends/"+backendName+"/servers",
 	)
 	if err != nil {
 		// Treat missing/empty servers as non-fatal
-		log.Printf("warning: failed to fetch servers for backend %q: %v", backendName, err)
+		internal.Warnf("failed to fetch servers for backend %q: %v", backendName, err)
 	}
 
 	// Build manifest-style object: backendWithServers
@@ -80,6 +83,31 @@ func editBackend(backendName string) error {
 		}
 	}
 
+	manifest.ServerSwitchingRules, err = liveServerSwitchingRules(ctx, backendName)
+	if err != nil {
+		internal.Warnf("failed to fetch server switching rules for backend %q: %v", backendName, err)
+	}
+	manifest.StickRules, err = liveStickRules(ctx, backendName)
+	if err != nil {
+		internal.Warnf("failed to fetch stick rules for backend %q: %v", backendName, err)
+	}
+	manifest.Filters, err = filters.LiveWithContext(ctx, filters.ParentBackend, backendName)
+	if err != nil {
+		internal.Warnf("failed to fetch filters for backend %q: %v", backendName, err)
+	}
+	manifest.LogTargets, err = logtargets.LiveWithContext(ctx, logtargets.ParentBackend, backendName)
+	if err != nil {
+		internal.Warnf("failed to fetch log targets for backend %q: %v", backendName, err)
+	}
+	manifest.HTTPChecks, err = liveHTTPChecks(ctx, backendName)
+	if err != nil {
+		internal.Warnf("failed to fetch http checks for backend %q: %v", backendName, err)
+	}
+	manifest.TCPChecks, err = liveTCPChecks(ctx, backendName)
+	if err != nil {
+		internal.Warnf("failed to fetch tcp checks for backend %q: %v", backendName, err)
+	}
+
 	origYAML, err := yaml.Marshal(manifest)
 	if err != nil {
 		return fmt.Errorf("failed to marshal backend manifest to YAML: %w", err)
@@ -91,7 +119,7 @@ func editBackend(backendName string) error {
 	}
 	defer func() {
 		if rmErr := os.Remove(tmpFile); rmErr != nil {
-			log.Printf("warning: failed to remove temp file %q: %v", tmpFile, rmErr)
+			internal.Warnf("failed to remove temp file %q: %v", tmpFile, rmErr)
 		}
 	}()
 
@@ -125,23 +153,39 @@ func editBackend(backendName string) error {
 		return fmt.Errorf("invalid backend configuration: %w", err)
 	}
 
-	payload := edited.toPayload()
-
-	_, err = internal.SendRequest(
-		"PUT",
-		"/services/haproxy/configuration/backends/"+backendName,
-		map[string]string{"version": strconv.Itoa(cfgVer)},
-		payload,
-	)
+	payload, err := edited.toClient()
 	if err != nil {
+		return err
+	}
+	if err := internal.API().UpdateBackend(ctx, nil, payload); err != nil {
 		return fmt.Errorf("failed to update backend %q: %w", backendName, err)
 	}
 
 	// Apply server changes based on the edited manifest.
-	if err := applyServerDiff(backendName, manifest.Servers, edited.Servers); err != nil {
+	if err := applyServerDiff(nil, backendName, manifest.Servers, edited.Servers); err != nil {
 		return fmt.Errorf("failed to apply server changes for backend %q: %w", backendName, err)
 	}
 
+	// The editor shows every rule, so a removed key means no rules.
+	if err := applyStickRuleDiff(nil, backendName, manifest.StickRules, edited.StickRules); err != nil {
+		return fmt.Errorf("failed to apply stick rule changes for backend %q: %w", backendName, err)
+	}
+	if err := applyServerSwitchingRuleDiff(nil, backendName, manifest.ServerSwitchingRules, edited.ServerSwitchingRules); err != nil {
+		return fmt.Errorf("failed to apply server switching rule changes for backend %q: %w", backendName, err)
+	}
+	if err := filters.ApplyDiff(nil, filters.ParentBackend, backendName, manifest.Filters, edited.Filters); err != nil {
+		return fmt.Errorf("failed to apply filter changes for backend %q: %w", backendName, err)
+	}
+	if err := logtargets.ApplyDiff(nil, logtargets.ParentBackend, backendName, manifest.LogTargets, edited.LogTargets); err != nil {
+		return fmt.Errorf("failed to apply log target changes for backend %q: %w", backendName, err)
+	}
+	if err := applyHTTPCheckDiff(nil, backendName, manifest.HTTPChecks, edited.HTTPChecks); err != nil {
+		return fmt.Errorf("failed to apply http check changes for backend %q: %w", backendName, err)
+	}
+	if err := applyTCPCheckDiff(nil, backendName, manifest.TCPChecks, edited.TCPChecks); err != nil {
+		return fmt.Errorf("failed to apply tcp check changes for backend %q: %w", backendName, err)
+	}
+
 	internal.PrintStatus("Backend", backendName, internal.ActionConfigured)
 	return nil
 }
@@ -155,6 +199,9 @@ func populateBackendConfigFromMap(cfg *backendConfig, obj map[string]interface{}
 	if v, ok := obj["mode"].(string); ok {
 		cfg.Mode = v
 	}
+	if v, ok := obj["from"].(string); ok {
+		cfg.From = v
+	}
 	if m, ok := obj["balance"].(map[string]interface{}); ok {
 		cfg.Balance = toStringMap(m)
 	}
@@ -167,23 +214,26 @@ func populateBackendConfigFromMap(cfg *backendConfig, obj map[string]interface{}
 
 	// Timeouts come back as integer milliseconds; render them as
 	// human-readable strings for the manifest.
-	if ms, ok := getIntField(obj, "timeout_client"); ok {
-		cfg.TimeoutClient = internal.FormatMillisAsDuration(ms)
-	}
-	if ms, ok := getIntField(obj, "timeout_http_keep_alive"); ok {
-		cfg.TimeoutHTTPKeepAlive = internal.FormatMillisAsDuration(ms)
-	}
-	if ms, ok := getIntField(obj, "timeout_http_request"); ok {
-		cfg.TimeoutHTTPRequest = internal.FormatMillisAsDuration(ms)
+	for field, value := range cfg.durations() {
+		if ms, ok := internal.GetIntField(obj, field); ok {
+			*value = internal.FormatMillisAsDuration(ms)
+		}
 	}
-	if ms, ok := getIntField(obj, "timeout_queue"); ok {
-		cfg.TimeoutQueue = internal.FormatMillisAsDuration(ms)
+
+	cfg.Retries, _ = internal.GetIntField(obj, "retries")
+	cfg.Fullconn, _ = internal.GetIntField(obj, "fullconn")
+	cfg.MaxKeepAliveQueue, _ = internal.GetIntField(obj, "max_keep_alive_queue")
+	cfg.RetryOn, _ = obj["retry_on"].(string)
+	cfg.HTTPReuse, _ = obj["http_reuse"].(string)
+	cfg.HTTPConnectionMode, _ = obj["http_connection_mode"].(string)
+	for name, field := range cfg.options() {
+		*field, _ = obj[name].(string)
 	}
-	if ms, ok := getIntField(obj, "timeout_server"); ok {
-		cfg.TimeoutServer = internal.FormatMillisAsDuration(ms)
+	if m, ok := obj["hash_type"].(map[string]interface{}); ok {
+		cfg.HashType = toStringMap(m)
 	}
-	if ms, ok := getIntField(obj, "timeout_server_fin"); ok {
-		cfg.TimeoutServerFin = internal.FormatMillisAsDuration(ms)
+	if m, ok := obj["cookie"].(map[string]interface{}); ok {
+		cfg.Cookie = mapCookieFromAPI(m)
 	}
 
 	if v, ok := obj["tcpka"].(string); ok && v == stateEnabled {
@@ -199,6 +249,20 @@ func populateBackendConfigFromMap(cfg *backendConfig, obj map[string]interface{}
 	if m, ok := obj["source"].(map[string]interface{}); ok {
 		cfg.Source = toStringMap(m)
 	}
+
+	if m, ok := obj["stick_table"].(map[string]interface{}); ok {
+		cfg.StickTable = mapStickTableFromAPI(m)
+	}
+
+	if v, ok := obj["adv_check"].(string); ok {
+		cfg.AdvCheck = v
+	}
+	if m, ok := obj["httpchk_params"].(map[string]interface{}); ok {
+		cfg.HTTPChkParams = toStringMap(m)
+	}
+
+	cfg.ErrorFiles = httperrors.ErrorFilesFromAPI(obj["error_files"])
+	cfg.ErrorFilesFromHTTPErrors = httperrors.ErrorFilesFromFromAPI(obj["errorfiles_from_http_errors"])
 }
 
 // toStringMap converts a map[string]interface{} to map[string]string.
@@ -215,56 +279,17 @@ func toStringMap(m map[string]interface{}) map[string]string {
 	return out
 }
 
-// getIntField extracts an integer field from a generic map where
-// numbers are typically float64 from JSON decoding.
-func getIntField(obj map[string]interface{}, key string) (int, bool) {
-	v, ok := obj[key]
-	if !ok || v == nil {
-		return 0, false
-	}
-
-	switch n := v.(type) {
-	case int:
-		return n, true
-	case int64:
-		return int(n), true
-	case float64:
-		return int(n), true
-	default:
-		return 0, false
-	}
-}
-
 // mapServerFromAPI converts a generic server object into a ServerConfig
-// suitable for inclusion in backendWithServers manifests. It treats
-// "ssl" enum values as booleans and preserves the backend name.
+// of the given backend.
 func mapServerFromAPI(backendName string, obj map[string]interface{}) servers.ServerConfig {
-	var sc servers.ServerConfig
-
-	if v, ok := obj["name"].(string); ok {
-		sc.Name = v
-	}
-	if v, ok := obj["address"].(string); ok {
-		sc.Address = v
-	}
-	if ms, ok := getIntField(obj, "port"); ok {
-		sc.Port = ms
-	}
-	if w, ok := getIntField(obj, "weight"); ok {
-		sc.Weight = w
-	}
-	if v, ok := obj["ssl"].(string); ok && v == "enabled" {
-		sc.SSL = true
-	}
-
-	sc.Backend = backendName
-	return sc
+	return servers.ServerFromAPI(backendName, obj)
 }
 
 // applyServerDiff reconciles the server list for a backend based on the
 // original and edited manifests. It creates, updates, or deletes servers
-// using the existing server helpers in the servers package.
-func applyServerDiff(backendName string, before, after []servers.ServerConfig) error {
+// using the existing server helpers in the servers package, staging the
+// changes in tx when it is non-nil.
+func applyServerDiff(tx *internal.Transaction, backendName string, before, after []servers.ServerConfig) error {
 	beforeByName := make(map[string]servers.ServerConfig, len(before))
 	for _, s := range before {
 		beforeByName[s.Name] = s
@@ -282,7 +307,7 @@ func applyServerDiff(backendName string, before, after []servers.ServerConfig) e
 	// Deletes: present before, missing after.
 	for name := range beforeByName {
 		if _, ok := afterByName[name]; !ok {
-			if err := servers.DeleteServer(backendName, name); err != nil {
+			if err := servers.DeleteServerInTransaction(tx, backendName, name); err != nil {
 				return err
 			}
 		}
@@ -292,7 +317,7 @@ func applyServerDiff(backendName string, before, after []servers.ServerConfig) e
 	for name, newS := range afterByName {
 		oldS, existed := beforeByName[name]
 		if !existed {
-			if err := servers.CreateServer(newS, "", false); err != nil {
+			if err := servers.CreateServerInTransaction(tx, newS); err != nil {
 				return err
 			}
 			continue
@@ -302,7 +327,7 @@ func applyServerDiff(backendName string, before, after []servers.ServerConfig) e
 			continue
 		}
 
-		if err := servers.UpdateServer(newS); err != nil {
+		if err := servers.UpdateServerInTransaction(tx, newS); err != nil {
 			return err
 		}
 	}
@@ -310,12 +335,8 @@ func applyServerDiff(backendName string, before, after []servers.ServerConfig) e
 	return nil
 }
 
-// serverConfigEqual compares the fields of two ServerConfig objects that
-// are significant to the Data Plane API (name, address, port, weight, ssl).
+// serverConfigEqual reports whether two servers send the same object to
+// the Data Plane API.
 func serverConfigEqual(a, b servers.ServerConfig) bool {
-	return a.Name == b.Name &&
-		a.Address == b.Address &&
-		a.Port == b.Port &&
-		a.Weight == b.Weight &&
-		a.SSL == b.SSL
+	return a.Equal(b)
 }
@@ -18,10 +18,9 @@ limitations under the License.
 package backends
 
 import (
+	"context"
 	"fmt"
 	"haproxyctl/internal"
-	"log"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -41,6 +40,14 @@ var GetBackendsCmd = &cobra.Command{
 	},
 }
 
+// backendColumns are the table columns of "get backends"; timeouts and the
+// default-server settings are only shown with -o wide.
+var backendColumns = internal.ColumnSet{
+	Kind:    "Backend",
+	Default: []string{"name", "mode", "balance", "servers"},
+	Wide:    []string{"connect_timeout", "server_timeout", "queue_timeout", "default_server"},
+}
+
 // getBackends handles fetching backends (list or single item).
 func getBackends(cmd *cobra.Command, backendName string) {
 	outputFormat := internal.GetFlagString(cmd, "output")
@@ -49,46 +56,125 @@ func getBackends(cmd *cobra.Command, backendName string) {
 		outputFormat = "table" // Default to table if not specified
 	}
 
-	var data interface{}
-	var err error
+	watch := internal.WatchRequested(cmd)
+	if watch && backendName != "" {
+		internal.FatalCodef(internal.ExitUsage, "--watch only works when listing backends")
+	}
 
-	if backendName == "" {
-		// Fetch all backends (list)
-		data, err = internal.GetResourceList("/services/haproxy/configuration/backends")
+	if internal.ManifestFileOutput(cmd, outputFormat) {
+		data, err := backendManifestOutput(cmd.Context(), backendName)
 		if err != nil {
-			log.Fatalf("Failed to fetch backends: %v", err)
+			internal.Fatalf("Failed to fetch backend(s): %v", err)
 		}
+		internal.FormatOutputForCmd(cmd, data, outputFormat)
+		return
+	}
 
-		// Enrich each backend with servers (applies only to tables, but harmless for yaml/json)
-		if backendList, ok := data.([]map[string]interface{}); ok {
-			for i := range backendList {
-				internal.EnrichBackendWithServers(backendList[i])
-			}
+	var data interface{}
+	var err error
 
-			internal.SortByStringField(backendList, "name")
-			data = backendList
+	if backendName == "" {
+		// Fetch all backends with their servers (applies only to tables,
+		// but harmless for yaml/json)
+		backendList, err := internal.GetBackendsWithServers(cmd.Context())
+		if err != nil {
+			internal.Fatalf("Failed to fetch backends: %v", err)
 		}
+		internal.SortByStringField(backendList, "name")
+		internal.SortForCmd(cmd, backendList)
+		data = backendList
 	} else {
 		// Fetch a specific backend (single object)
 		data, err = internal.GetResource("/services/haproxy/configuration/backends/" + backendName)
 		if err == nil {
 			if backend, ok := data.(map[string]interface{}); ok {
-				internal.EnrichBackendWithServers(backend)
+				internal.EnrichBackendWithServers(cmd.Context(), backend)
 			}
 		}
 	}
 
 	if err != nil {
 		if backendName != "" && internal.IsNotFoundError(err) {
-			_, _ = fmt.Fprintln(os.Stdout, internal.ResourceID("Backend", backendName)+" not found")
-			return
+			internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Backend", backendName))
 		}
-		log.Fatalf("Failed to fetch backend(s): %v", err)
+		internal.Fatalf("Failed to fetch backend(s): %v", err)
 	}
 
-	internal.FormatOutput(data, outputFormat)
+	internal.FormatOutputForCmd(cmd, internal.WithColumns(data, backendColumns), outputFormat)
+
+	if watch {
+		if err := internal.WatchListForCmd(cmd, "Backend", "/services/haproxy/configuration/backends"); err != nil {
+			internal.Fatalf("Failed to watch backends: %v", err)
+		}
+	}
+}
+
+// BackendManifests returns every backend, with its servers, as a manifest
+// that apply accepts, sorted by name.
+func BackendManifests(ctx context.Context) ([]interface{}, error) {
+	list, err := internal.GetResourceListWithContext(ctx, "/services/haproxy/configuration/backends")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch backends: %w", err)
+	}
+	internal.SortByStringField(list, "name")
+
+	// Each object takes several requests; fetch them concurrently.
+	fetched := make([]*backendWithServers, len(list))
+	err = internal.ForEachConcurrent(ctx, len(list), func(ctx context.Context, i int) error {
+		name, _ := list[i]["name"].(string)
+		current, exists, err := fetchCurrentBackend(ctx, name)
+		if err != nil || !exists {
+			return err
+		}
+		fetched[i] = &current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]interface{}, 0, len(list))
+	for _, current := range fetched {
+		if current == nil {
+			continue
+		}
+		manifests = append(manifests, backendManifest(*current))
+	}
+	return manifests, nil
+}
+
+// backendManifest returns current, as fetched by fetchCurrentBackend, as a
+// manifest.
+func backendManifest(current backendWithServers) backendWithServers {
+	current.APIVersion = "haproxyctl/v1"
+	current.Kind = backendKind
+	current.Servers = normalizeServers(current.Servers)
+	return current
+}
+
+// backendManifestOutput returns what "get backends" writes to --output-file:
+// the manifest of backendName, or a List of every backend's manifest when
+// backendName is empty.
+func backendManifestOutput(ctx context.Context, backendName string) (interface{}, error) {
+	if backendName == "" {
+		manifests, err := BackendManifests(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return internal.ManifestListOf(manifests), nil
+	}
+
+	current, exists, err := fetchCurrentBackend(ctx, backendName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(backendKind, backendName))
+	}
+	return backendManifest(current), nil
 }
 
 func init() {
-	GetBackendsCmd.Flags().StringP("output", "o", "", "Output format: table, yaml, or json")
+	GetBackendsCmd.Flags().StringP("output", "o", "", "Output format: table, wide, yaml, or json")
+	internal.AddWatchFlags(GetBackendsCmd)
 }
@@ -0,0 +1,105 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGetBackends_ListsServersInOneRequest(t *testing.T) {
+	srv := testserver.New(t)
+	for i := range 20 {
+		name := fmt.Sprintf("app%02d", i)
+		srv.AddBackend(map[string]interface{}{"name": name, "mode": "http"})
+		srv.AddServer(name, map[string]interface{}{"name": "s1", "address": "10.0.0.1", "port": 80})
+	}
+	srv.AddBackend(map[string]interface{}{"name": "empty", "mode": "tcp"})
+
+	if err := GetBackendsCmd.Flags().Set("output", "json"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = GetBackendsCmd.Flags().Set("output", "") })
+	GetBackendsCmd.SetContext(context.Background())
+
+	output := internal.CaptureStdout(t, func() { getBackends(GetBackendsCmd, "") })
+	var backends []map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &backends); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	if len(backends) != 21 {
+		t.Fatalf("got %d backends, want 21", len(backends))
+	}
+	for _, b := range backends {
+		want := 1
+		if b["name"] == "empty" {
+			want = 0
+		}
+		if servers, _ := b["servers"].([]interface{}); len(servers) != want {
+			t.Errorf("backend %v: %d servers, want %d", b["name"], len(servers), want)
+		}
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("listing 21 backends took %d requests, want 1", n)
+	}
+}
+
+func TestBackendManifests_UsesContext(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "web", "mode": "http"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := BackendManifests(ctx); err == nil {
+		t.Fatal("BackendManifests with a canceled context succeeded")
+	}
+
+	manifests, err := BackendManifests(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 1 {
+		t.Fatalf("got %d manifests, want 1", len(manifests))
+	}
+}
+
+func TestGetBackends_OutputFileWritesManifests(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddServer("web", map[string]interface{}{"name": "s1", "address": "10.0.0.1", "port": 80})
+
+	dir := t.TempDir()
+	cmd := &cobra.Command{Use: "backends"}
+	cmd.SetContext(context.Background())
+	cmd.Flags().StringP("output", "o", "", "")
+	cmd.Flags().String(internal.OutputFileFlag, "", "")
+	cmd.Flags().Bool(internal.SplitByResourceFlag, false, "")
+	for name, value := range map[string]string{"output": "yaml", internal.OutputFileFlag: dir, internal.SplitByResourceFlag: "true"} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	internal.CaptureStdout(t, func() { getBackends(cmd, "") })
+
+	data, err := os.ReadFile(filepath.Join(dir, "backend-web.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"apiVersion: haproxyctl/v1", "kind: Backend", "name: s1"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("backend-web.yaml lacks %q:\n%s", want, data)
+		}
+	}
+	if err := ValidateBackendFromYAML(data); err != nil {
+		t.Errorf("written manifest does not validate: %v", err)
+	}
+}
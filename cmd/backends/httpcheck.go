@@ -0,0 +1,408 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backends provides commands to manage HAProxy backends.
+package backends
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	httpCheckTypes   = []string{"send", "expect", "connect", "comment", "disable-on-404", "send-state"}
+	httpCheckMatches = []string{"status", "rstatus", "hdr", "fhdr", "string", "rstring"}
+)
+
+// HTTPCheckHeader is one "hdr <name> <fmt>" of an http-check send rule.
+type HTTPCheckHeader struct {
+	Name string `json:"name" yaml:"name"`
+	Fmt  string `json:"fmt" yaml:"fmt"`
+}
+
+// HTTPCheck is an "http-check <type> ..." line. Only the fields of its type
+// are used: method, uri, version, headers and body for send; match,
+// pattern and negate for expect; addr, port and ssl for connect.
+//
+//nolint:tagliatelle // manifest uses snake_case like backendConfig
+type HTTPCheck struct {
+	Type    string            `json:"type" yaml:"type"`
+	Method  string            `json:"method,omitempty" yaml:"method,omitempty"`
+	URI     string            `json:"uri,omitempty" yaml:"uri,omitempty"`
+	Version string            `json:"version,omitempty" yaml:"version,omitempty"`
+	Headers []HTTPCheckHeader `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body    string            `json:"body,omitempty" yaml:"body,omitempty"`
+	Match   string            `json:"match,omitempty" yaml:"match,omitempty"`
+	Pattern string            `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Negate  bool              `json:"negate,omitempty" yaml:"negate,omitempty"`
+	Addr    string            `json:"addr,omitempty" yaml:"addr,omitempty"`
+	Port    int               `json:"port,omitempty" yaml:"port,omitempty"`
+	SSL     bool              `json:"ssl,omitempty" yaml:"ssl,omitempty"`
+	Comment string            `json:"check_comment,omitempty" yaml:"check_comment,omitempty"`
+}
+
+// String renders the check the way it appears in haproxy.cfg.
+func (c HTTPCheck) String() string {
+	parts := []string{"http-check", c.Type}
+	switch c.Type {
+	case "send":
+		if c.Method != "" {
+			parts = append(parts, "meth", c.Method)
+		}
+		if c.URI != "" {
+			parts = append(parts, "uri", c.URI)
+		}
+		if c.Version != "" {
+			parts = append(parts, "ver", c.Version)
+		}
+		for _, h := range c.Headers {
+			parts = append(parts, "hdr", h.Name, h.Fmt)
+		}
+		if c.Body != "" {
+			parts = append(parts, "body", strconv.Quote(c.Body))
+		}
+	case "expect":
+		if c.Negate {
+			parts = append(parts, "!")
+		}
+		parts = append(parts, c.Match, c.Pattern)
+	case "connect":
+		if c.Addr != "" {
+			parts = append(parts, "addr", c.Addr)
+		}
+		if c.Port != 0 {
+			parts = append(parts, "port", strconv.Itoa(c.Port))
+		}
+		if c.SSL {
+			parts = append(parts, "ssl")
+		}
+	}
+	if c.Comment != "" {
+		parts = append(parts, "comment", strconv.Quote(c.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c HTTPCheck) validate() error {
+	if !slices.Contains(httpCheckTypes, c.Type) {
+		return fmt.Errorf("invalid http-check type %q (allowed: %s)", c.Type, strings.Join(httpCheckTypes, ", "))
+	}
+	if c.Type != "send" && (c.Method != "" || c.URI != "" || c.Version != "" || len(c.Headers) > 0 || c.Body != "") {
+		return fmt.Errorf("%q: method, uri, version, headers and body are only valid for send", c.String())
+	}
+	if c.Type != "expect" && (c.Match != "" || c.Pattern != "" || c.Negate) {
+		return fmt.Errorf("%q: match, pattern and negate are only valid for expect", c.String())
+	}
+	if c.Type != "connect" && (c.Addr != "" || c.Port != 0 || c.SSL) {
+		return fmt.Errorf("%q: addr, port and ssl are only valid for connect", c.String())
+	}
+	if c.Type == "expect" {
+		if !slices.Contains(httpCheckMatches, c.Match) {
+			return fmt.Errorf("%q: invalid match %q (allowed: %s)", c.String(), c.Match, strings.Join(httpCheckMatches, ", "))
+		}
+		if c.Pattern == "" {
+			return fmt.Errorf("%q: pattern is required", c.String())
+		}
+	}
+	for _, h := range c.Headers {
+		if h.Name == "" {
+			return fmt.Errorf("%q: header name is required", c.String())
+		}
+	}
+	return nil
+}
+
+func (c HTTPCheck) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"type": c.Type}
+	setIfNotEmpty := func(key, value string) {
+		if value != "" {
+			payload[key] = value
+		}
+	}
+	setIfNotEmpty("method", c.Method)
+	setIfNotEmpty("uri", c.URI)
+	setIfNotEmpty("version", c.Version)
+	setIfNotEmpty("body", c.Body)
+	setIfNotEmpty("match", c.Match)
+	setIfNotEmpty("pattern", c.Pattern)
+	setIfNotEmpty("addr", c.Addr)
+	setIfNotEmpty("check_comment", c.Comment)
+	if len(c.Headers) > 0 {
+		headers := make([]map[string]interface{}, 0, len(c.Headers))
+		for _, h := range c.Headers {
+			headers = append(headers, map[string]interface{}{"name": h.Name, "fmt": h.Fmt})
+		}
+		payload["headers"] = headers
+	}
+	if c.Negate {
+		payload["exclamation_mark"] = true
+	}
+	if c.Port != 0 {
+		payload["port"] = c.Port
+	}
+	if c.SSL {
+		payload["ssl"] = true
+	}
+	return payload
+}
+
+// mapHTTPCheckFromAPI converts an API http check.
+func mapHTTPCheckFromAPI(obj map[string]interface{}) HTTPCheck {
+	var c HTTPCheck
+	c.Type, _ = obj["type"].(string)
+	c.Method, _ = obj["method"].(string)
+	c.URI, _ = obj["uri"].(string)
+	c.Version, _ = obj["version"].(string)
+	c.Body, _ = obj["body"].(string)
+	c.Match, _ = obj["match"].(string)
+	c.Pattern, _ = obj["pattern"].(string)
+	c.Negate, _ = obj["exclamation_mark"].(bool)
+	c.Addr, _ = obj["addr"].(string)
+	c.Port, _ = internal.GetIntField(obj, "port")
+	c.SSL, _ = obj["ssl"].(bool)
+	c.Comment, _ = obj["check_comment"].(string)
+	if headers, ok := obj["headers"].([]interface{}); ok {
+		for _, raw := range headers {
+			if h, ok := raw.(map[string]interface{}); ok {
+				var header HTTPCheckHeader
+				header.Name, _ = h["name"].(string)
+				header.Fmt, _ = h["fmt"].(string)
+				c.Headers = append(c.Headers, header)
+			}
+		}
+	}
+	return c
+}
+
+// httpCheckEqual compares two checks field by field, headers included.
+func httpCheckEqual(a, b HTTPCheck) bool {
+	if !slices.Equal(a.Headers, b.Headers) {
+		return false
+	}
+	a.Headers, b.Headers = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// httpChecksEqual compares two check lists, treating nil and empty alike.
+func httpChecksEqual(a, b []HTTPCheck) bool {
+	return slices.EqualFunc(a, b, httpCheckEqual)
+}
+
+// parseHTTPCheckHeader parses a "Name: value" header flag.
+func parseHTTPCheckHeader(s string) (HTTPCheckHeader, error) {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return HTTPCheckHeader{}, fmt.Errorf("invalid header %q (expected Name: value)", s)
+	}
+	return HTTPCheckHeader{Name: strings.TrimSpace(name), Fmt: strings.TrimSpace(value)}, nil
+}
+
+func httpChecksPath(backendName string) string {
+	return "/services/haproxy/configuration/backends/" + backendName + "/http_checks"
+}
+
+// liveHTTPChecks returns the http checks of a backend in order, or nil when
+// it has none.
+func liveHTTPChecks(ctx context.Context, backendName string) ([]HTTPCheck, error) {
+	list, err := internal.GetResourceListWithContext(ctx, httpChecksPath(backendName))
+	if err != nil {
+		return nil, err
+	}
+	var checks []HTTPCheck
+	for _, raw := range list {
+		checks = append(checks, mapHTTPCheckFromAPI(raw))
+	}
+	return checks, nil
+}
+
+// applyHTTPCheckDiff turns the http checks of a backend from before into
+// after, staging the changes in tx when it is non-nil.
+func applyHTTPCheckDiff(tx *internal.Transaction, backendName string, before, after []HTTPCheck) error {
+	return internal.ApplyIndexedDiffFunc(tx, httpChecksPath(backendName), before, after, httpCheckEqual, func(c HTTPCheck) interface{} { return c.toPayload() })
+}
+
+// GetHTTPChecksCmd represents "get http-checks <backend>".
+var GetHTTPChecksCmd = &cobra.Command{
+	Use:     "http-checks <backend_name>",
+	Aliases: []string{"http-check"},
+	Short:   "List the http-check rules of a backend",
+	Long: `List the http-check send/expect/connect rules of a backend in order.
+The index column is the position used by the delete command.
+
+Examples:
+  haproxyctl get http-checks app
+  haproxyctl get http-checks app -o yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checks, err := internal.GetResourceList(httpChecksPath(args[0]))
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(backendKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch http checks of backend '%s': %v", args[0], err)
+		}
+		internal.SortForCmd(cmd, checks)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(checks, httpCheckColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// httpCheckColumns shows each check as position, type and the fields of the
+// common send and expect types.
+var httpCheckColumns = internal.ColumnSet{
+	Default: []string{"index", "type", "method", "uri", "match", "pattern"},
+	Wide:    []string{"version", "exclamation_mark", "addr", "port", "check_comment"},
+}
+
+// CreateHTTPCheckCmd represents "create http-checks <backend>".
+var CreateHTTPCheckCmd = &cobra.Command{
+	Use:     "http-checks <backend_name>",
+	Aliases: []string{"http-check"},
+	Short:   "Add an http-check rule to a backend",
+	Long: `Add an "http-check <type> ..." rule to a backend. The rule is appended
+unless --index places it at a given position. Rules only take effect when
+the backend has "option httpchk" (adv_check: httpchk, or --http-check on
+"create backends").
+
+Examples:
+  haproxyctl create http-checks app --type send --method GET --uri /healthz --header "Host: app.example.com"
+  haproxyctl create http-checks app --type expect --match status --pattern 200
+  haproxyctl create http-checks app --type expect --match string --pattern maintenance --negate`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		check := HTTPCheck{
+			Type:    internal.GetFlagString(cmd, "type"),
+			Method:  internal.GetFlagString(cmd, "method"),
+			URI:     internal.GetFlagString(cmd, "uri"),
+			Version: internal.GetFlagString(cmd, "version"),
+			Body:    internal.GetFlagString(cmd, "body"),
+			Match:   internal.GetFlagString(cmd, "match"),
+			Pattern: internal.GetFlagString(cmd, "pattern"),
+			Negate:  internal.GetFlagBool(cmd, "negate"),
+			Addr:    internal.GetFlagString(cmd, "addr"),
+			Port:    internal.GetFlagInt(cmd, "port"),
+			SSL:     internal.GetFlagBool(cmd, "ssl"),
+			Comment: internal.GetFlagString(cmd, "comment"),
+		}
+		for _, raw := range internal.GetFlagStringSlice(cmd, "header") {
+			header, err := parseHTTPCheckHeader(raw)
+			if err != nil {
+				internal.FatalCodef(internal.ExitUsage, "Invalid --header: %v", err)
+			}
+			check.Headers = append(check.Headers, header)
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateHTTPCheck(args[0], check, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+			internal.Fatalf("Failed to create http check on backend '%s': %v", args[0], err)
+		}
+	},
+}
+
+// DeleteHTTPCheckCmd represents "delete http-checks <backend> --index N".
+var DeleteHTTPCheckCmd = &cobra.Command{
+	Use:     "http-checks <backend_name>",
+	Aliases: []string{"http-check"},
+	Short:   "Delete an http-check rule from a backend",
+	Long: `Delete the http-check rule at --index (as shown by "get http-checks").
+Later rules move up by one position.
+
+Examples:
+  haproxyctl delete http-checks app --index 1`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		index := internal.GetFlagInt(cmd, "index")
+		if index < 0 {
+			internal.FatalCodef(internal.ExitUsage, "--index is required")
+		}
+		if err := DeleteHTTPCheck(args[0], index); err != nil {
+			internal.Fatalf("Failed to delete http check %d of backend '%s': %v", index, args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateHTTPCheckCmd.Flags().String("type", "", "Check type: send, expect, connect, comment, disable-on-404 or send-state (required)")
+	CreateHTTPCheckCmd.Flags().String("method", "", "send: HTTP method, e.g. GET")
+	CreateHTTPCheckCmd.Flags().String("uri", "", "send: request URI, e.g. /healthz")
+	CreateHTTPCheckCmd.Flags().String("version", "", "send: HTTP version, e.g. HTTP/1.1")
+	CreateHTTPCheckCmd.Flags().StringArray("header", nil, "send: request header as \"Name: value\" (repeatable)")
+	CreateHTTPCheckCmd.Flags().String("body", "", "send: request body")
+	CreateHTTPCheckCmd.Flags().String("match", "", "expect: status, rstatus, hdr, fhdr, string or rstring")
+	CreateHTTPCheckCmd.Flags().String("pattern", "", "expect: value to match, e.g. 200")
+	CreateHTTPCheckCmd.Flags().Bool("negate", false, "expect: invert the match (\"!\")")
+	CreateHTTPCheckCmd.Flags().String("addr", "", "connect: address to connect to")
+	CreateHTTPCheckCmd.Flags().Int("port", 0, "connect: port to connect to")
+	CreateHTTPCheckCmd.Flags().Bool("ssl", false, "connect: use SSL")
+	CreateHTTPCheckCmd.Flags().String("comment", "", "Comment reported in the check status")
+	CreateHTTPCheckCmd.Flags().Int("index", -1, "Position to insert the rule at (default: append)")
+	CreateHTTPCheckCmd.Flags().Bool("dry-run", false, "Print the rule without creating it")
+
+	DeleteHTTPCheckCmd.Flags().Int("index", -1, "Position of the rule to delete (required)")
+}
+
+// CreateHTTPCheck inserts a check at index, or appends it when index is
+// negative.
+func CreateHTTPCheck(backendName string, check HTTPCheck, index int, dryRun bool) error {
+	if err := check.validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(check.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := liveHTTPChecks(context.Background(), backendName)
+	if err != nil {
+		return internal.FormatAPIError(backendKind, backendName, "get", err)
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", httpChecksPath(backendName)+"/"+strconv.Itoa(index), params, check.toPayload()); err != nil {
+		return fmt.Errorf("failed to create %q: %w", check.String(), err)
+	}
+
+	internal.PrintStatus("HTTPCheck", fmt.Sprintf("%s/%d", backendName, index), internal.ActionCreated)
+	return nil
+}
+
+// DeleteHTTPCheck removes the check at index.
+func DeleteHTTPCheck(backendName string, index int) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%d", backendName, index)
+	if _, err := internal.SendRequest("DELETE", httpChecksPath(backendName)+"/"+strconv.Itoa(index), params, nil); err != nil {
+		return internal.FormatAPIError("HTTPCheck", id, "delete", err)
+	}
+
+	internal.PrintStatus("HTTPCheck", id, internal.ActionDeleted)
+	return nil
+}
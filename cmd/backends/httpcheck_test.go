@@ -0,0 +1,103 @@
+package backends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testHTTPCheckManifest = testBackendManifest + `adv_check: httpchk
+httpchk_params:
+  method: GET
+  uri: /healthz
+http_checks:
+  - type: send
+    method: GET
+    uri: /healthz
+    headers:
+      - name: Host
+        fmt: app.example.com
+  - type: expect
+    match: status
+    pattern: "200"
+`
+
+func TestApplyBackendFromYAML_HTTPChecks(t *testing.T) {
+	srv := testserver.New(t)
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testHTTPCheckManifest), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+
+	be, _ := srv.Backend("web")
+	if be["adv_check"] != "httpchk" {
+		t.Fatalf("adv_check = %v, want httpchk", be["adv_check"])
+	}
+	checks := srv.List("backends", "web", "http_checks")
+	if len(checks) != 2 || checks[0]["type"] != "send" || checks[1]["match"] != "status" {
+		t.Fatalf("http checks = %+v", checks)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testHTTPCheckManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	changed := strings.Replace(testHTTPCheckManifest, "fmt: app.example.com", "fmt: www.example.com", 1)
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	checks = srv.List("backends", "web", "http_checks")
+	if headers, _ := checks[0]["headers"].([]interface{}); len(headers) != 1 ||
+		headers[0].(map[string]interface{})["fmt"] != "www.example.com" {
+		t.Fatalf("send check not updated: %+v", checks[0])
+	}
+}
+
+func TestHTTPCheckValidation(t *testing.T) {
+	cases := []struct {
+		check HTTPCheck
+		ok    bool
+	}{
+		{HTTPCheck{Type: "send", Method: "GET", URI: "/healthz"}, true},
+		{HTTPCheck{Type: "expect", Match: "status", Pattern: "200"}, true},
+		{HTTPCheck{Type: "connect", Port: 8080, SSL: true}, true},
+		{HTTPCheck{Type: "expect", Match: "body", Pattern: "ok"}, false},
+		{HTTPCheck{Type: "expect", Match: "status"}, false},
+		{HTTPCheck{Type: "send", Match: "status", Pattern: "200"}, false},
+		{HTTPCheck{Type: "probe"}, false},
+	}
+	for _, tc := range cases {
+		if err := tc.check.validate(); (err == nil) != tc.ok {
+			t.Errorf("validate(%+v) error = %v, want ok=%v", tc.check, err, tc.ok)
+		}
+	}
+
+	b := backendWithServers{APIVersion: "haproxyctl/v1", Kind: backendKind}
+	b.Name, b.Mode = "web", "http"
+	b.HTTPChecks = []HTTPCheck{{Type: "expect", Match: "status", Pattern: "200"}}
+	if err := b.Validate(); err == nil {
+		t.Error("expected error for http_checks without adv_check: httpchk")
+	}
+}
+
+func TestParseHTTPChkFlag(t *testing.T) {
+	got := parseHTTPChkFlag("GET /healthz HTTP/1.1")
+	if got["method"] != "GET" || got["uri"] != "/healthz" || got["version"] != "HTTP/1.1" {
+		t.Fatalf("parseHTTPChkFlag = %v", got)
+	}
+	got = parseHTTPChkFlag("/ping")
+	if _, ok := got["method"]; ok || got["uri"] != "/ping" {
+		t.Fatalf("parseHTTPChkFlag = %v", got)
+	}
+}
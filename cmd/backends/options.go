@@ -0,0 +1,214 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package backends provides commands to manage HAProxy backends.
+package backends
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const stateDisabled = "disabled"
+
+var (
+	cookieTypes         = []string{"rewrite", "insert", "prefix"}
+	httpReuseModes      = []string{"aggressive", "always", "never", "safe"}
+	httpConnectionModes = []string{"httpclose", "http-server-close", "http-keep-alive"}
+)
+
+// Cookie is the "cookie" persistence setting of a backend. Maxidle and
+// Maxlife are in seconds, as in HAProxy.
+type Cookie struct {
+	Name     string   `json:"name" yaml:"name"`
+	Type     string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Indirect bool     `json:"indirect,omitempty" yaml:"indirect,omitempty"`
+	Nocache  bool     `json:"nocache,omitempty" yaml:"nocache,omitempty"`
+	Postonly bool     `json:"postonly,omitempty" yaml:"postonly,omitempty"`
+	Preserve bool     `json:"preserve,omitempty" yaml:"preserve,omitempty"`
+	Httponly bool     `json:"httponly,omitempty" yaml:"httponly,omitempty"`
+	Secure   bool     `json:"secure,omitempty" yaml:"secure,omitempty"`
+	Dynamic  bool     `json:"dynamic,omitempty" yaml:"dynamic,omitempty"`
+	Domains  []string `json:"domains,omitempty" yaml:"domains,omitempty"`
+	Attrs    []string `json:"attrs,omitempty" yaml:"attrs,omitempty"`
+	Maxidle  int      `json:"maxidle,omitempty" yaml:"maxidle,omitempty"`
+	Maxlife  int      `json:"maxlife,omitempty" yaml:"maxlife,omitempty"`
+}
+
+// cookieFlags lists the keyword switches of the cookie line.
+func (c *Cookie) cookieFlags() map[string]*bool {
+	return map[string]*bool{
+		"indirect": &c.Indirect,
+		"nocache":  &c.Nocache,
+		"postonly": &c.Postonly,
+		"preserve": &c.Preserve,
+		"httponly": &c.Httponly,
+		"secure":   &c.Secure,
+		"dynamic":  &c.Dynamic,
+	}
+}
+
+// ParseCookie parses "--cookie" in HAProxy's own order, e.g.
+// "SRV insert indirect nocache".
+func ParseCookie(value string) (*Cookie, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return nil, errors.New("cookie name is required")
+	}
+	c := &Cookie{Name: fields[0]}
+	flags := c.cookieFlags()
+	for _, f := range fields[1:] {
+		switch {
+		case slices.Contains(cookieTypes, f):
+			c.Type = f
+		case flags[f] != nil:
+			*flags[f] = true
+		default:
+			return nil, fmt.Errorf("unknown cookie keyword %q", f)
+		}
+	}
+	return c, c.validate()
+}
+
+func (c Cookie) validate() error {
+	if c.Name == "" {
+		return errors.New("cookie name is required")
+	}
+	if c.Type != "" && !slices.Contains(cookieTypes, c.Type) {
+		return fmt.Errorf("invalid cookie type %q (allowed: rewrite, insert, prefix)", c.Type)
+	}
+	if c.Maxidle < 0 || c.Maxlife < 0 {
+		return errors.New("cookie maxidle and maxlife cannot be negative")
+	}
+	return nil
+}
+
+// toPayload converts the cookie to the Data Plane API shape, where
+// domains and attributes are lists of {value: ...} objects.
+func (c Cookie) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"name": c.Name}
+	if c.Type != "" {
+		payload["type"] = c.Type
+	}
+	for key, set := range c.cookieFlags() {
+		if *set {
+			payload[key] = true
+		}
+	}
+	if len(c.Domains) > 0 {
+		payload["domain"] = valueList(c.Domains)
+	}
+	if len(c.Attrs) > 0 {
+		payload["attr"] = valueList(c.Attrs)
+	}
+	if c.Maxidle > 0 {
+		payload["maxidle"] = c.Maxidle
+	}
+	if c.Maxlife > 0 {
+		payload["maxlife"] = c.Maxlife
+	}
+	return payload
+}
+
+// mapCookieFromAPI converts an API cookie object.
+func mapCookieFromAPI(obj map[string]interface{}) *Cookie {
+	c := &Cookie{}
+	c.Name, _ = obj["name"].(string)
+	c.Type, _ = obj["type"].(string)
+	for key, set := range c.cookieFlags() {
+		*set, _ = obj[key].(bool)
+	}
+	c.Domains = valuesFromList(obj["domain"])
+	c.Attrs = valuesFromList(obj["attr"])
+	c.Maxidle, _ = internal.GetIntField(obj, "maxidle")
+	c.Maxlife, _ = internal.GetIntField(obj, "maxlife")
+	return c
+}
+
+func valueList(values []string) []map[string]string {
+	out := make([]map[string]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, map[string]string{"value": v})
+	}
+	return out
+}
+
+func valuesFromList(raw interface{}) []string {
+	items, _ := raw.([]interface{})
+	var out []string
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			if v, ok := m["value"].(string); ok {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// options lists the backend's "option" switches by their API name.
+func (c *backendConfig) options() map[string]*string {
+	return map[string]*string{
+		"abortonclose":           &c.Abortonclose,
+		"allbackups":             &c.Allbackups,
+		"checkcache":             &c.Checkcache,
+		"http_pretend_keepalive": &c.HTTPPretendKeepalive,
+		"independent_streams":    &c.IndependentStreams,
+		"log_health_checks":      &c.LogHealthChecks,
+		"nolinger":               &c.Nolinger,
+		"persist":                &c.Persist,
+		"prefer_last_server":     &c.PreferLastServer,
+	}
+}
+
+// setOption applies an "--option" flag: the HAProxy option name, e.g.
+// abortonclose or prefer-last-server, or "no-<name>" to disable it.
+func (c *backendConfig) setOption(value string) error {
+	state := stateEnabled
+	name := value
+	if rest, ok := strings.CutPrefix(value, "no-"); ok {
+		state, name = stateDisabled, rest
+	}
+	field, ok := c.options()[strings.ReplaceAll(name, "-", "_")]
+	if !ok {
+		return fmt.Errorf("unknown backend option %q", value)
+	}
+	*field = state
+	return nil
+}
+
+func (c *backendConfig) validateOptions() error {
+	options := c.options()
+	for _, name := range slices.Sorted(maps.Keys(options)) {
+		if state := options[name]; *state != "" && *state != stateEnabled && *state != stateDisabled {
+			return fmt.Errorf("invalid %s %q (allowed: enabled, disabled)", name, *state)
+		}
+	}
+	if c.HTTPReuse != "" && !slices.Contains(httpReuseModes, c.HTTPReuse) {
+		return fmt.Errorf("invalid http_reuse %q (allowed: aggressive, always, never, safe)", c.HTTPReuse)
+	}
+	if c.HTTPConnectionMode != "" && !slices.Contains(httpConnectionModes, c.HTTPConnectionMode) {
+		return fmt.Errorf("invalid http_connection_mode %q (allowed: httpclose, http-server-close, http-keep-alive)", c.HTTPConnectionMode)
+	}
+	if c.Retries < 0 || c.Fullconn < 0 || c.MaxKeepAliveQueue < 0 {
+		return errors.New("retries, fullconn and max_keep_alive_queue cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backends provides commands to manage HAProxy backends.
+package backends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	stickTableTypes = []string{"ip", "ipv6", "integer", "string", "binary"}
+	stickRuleTypes  = []string{"match", "on", "store-request", "store-response"}
+)
+
+// StickTable is the "stick-table" declaration of a backend. Expire uses
+// the same duration syntax as the timeouts (e.g. 30m).
+type StickTable struct {
+	Type    string `json:"type" yaml:"type"`
+	Size    int    `json:"size,omitempty" yaml:"size,omitempty"`
+	Expire  string `json:"expire,omitempty" yaml:"expire,omitempty"`
+	Store   string `json:"store,omitempty" yaml:"store,omitempty"`
+	Peers   string `json:"peers,omitempty" yaml:"peers,omitempty"`
+	Keylen  int    `json:"keylen,omitempty" yaml:"keylen,omitempty"`
+	NoPurge bool   `json:"nopurge,omitempty" yaml:"nopurge,omitempty"`
+}
+
+func (t StickTable) validate() error {
+	if !slices.Contains(stickTableTypes, t.Type) {
+		return fmt.Errorf("invalid stick_table type %q (allowed: ip, ipv6, integer, string, binary)", t.Type)
+	}
+	if t.Size <= 0 {
+		return errors.New("stick_table size must be greater than 0")
+	}
+	if _, err := internal.ParseDurationToMillis(t.Expire); err != nil {
+		return fmt.Errorf("invalid stick_table expire: %w", err)
+	}
+	return nil
+}
+
+// toPayload converts the table to the Data Plane API shape, where expire
+// is in milliseconds.
+func (t StickTable) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"type": t.Type, "size": t.Size}
+	if ms, err := internal.ParseDurationToMillis(t.Expire); err != nil {
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend stick_table expire: %v", err)
+	} else if ms > 0 {
+		payload["expire"] = ms
+	}
+	if t.Store != "" {
+		payload["store"] = t.Store
+	}
+	if t.Peers != "" {
+		payload["peers"] = t.Peers
+	}
+	if t.Keylen > 0 {
+		payload["keylen"] = t.Keylen
+	}
+	if t.NoPurge {
+		payload["nopurge"] = true
+	}
+	return payload
+}
+
+// mapStickTableFromAPI converts an API stick_table object.
+func mapStickTableFromAPI(obj map[string]interface{}) *StickTable {
+	var t StickTable
+	t.Type, _ = obj["type"].(string)
+	t.Size, _ = internal.GetIntField(obj, "size")
+	if ms, ok := internal.GetIntField(obj, "expire"); ok {
+		t.Expire = internal.FormatMillisAsDuration(ms)
+	}
+	t.Store, _ = obj["store"].(string)
+	t.Peers, _ = obj["peers"].(string)
+	t.Keylen, _ = internal.GetIntField(obj, "keylen")
+	t.NoPurge, _ = obj["nopurge"].(bool)
+	return &t
+}
+
+// StickRule is a "stick match|on|store-request|store-response <pattern>
+// [table <table>] [if|unless <condition>]" line.
+//
+//nolint:tagliatelle // manifest uses snake_case like backendConfig
+type StickRule struct {
+	Type     string `json:"type" yaml:"type"`
+	Pattern  string `json:"pattern" yaml:"pattern"`
+	Table    string `json:"table,omitempty" yaml:"table,omitempty"`
+	Cond     string `json:"cond,omitempty" yaml:"cond,omitempty"`
+	CondTest string `json:"cond_test,omitempty" yaml:"cond_test,omitempty"`
+}
+
+// String renders the rule the way it appears in haproxy.cfg.
+func (r StickRule) String() string {
+	s := "stick " + r.Type + " " + r.Pattern
+	if r.Table != "" {
+		s += " table " + r.Table
+	}
+	if r.Cond != "" {
+		s += " " + r.Cond + " " + r.CondTest
+	}
+	return s
+}
+
+func (r StickRule) validate() error {
+	if !slices.Contains(stickRuleTypes, r.Type) {
+		return fmt.Errorf("%q: invalid type %q (allowed: match, on, store-request, store-response)", r.String(), r.Type)
+	}
+	if r.Pattern == "" {
+		return fmt.Errorf("%q: pattern is required", r.String())
+	}
+	switch r.Cond {
+	case "":
+		if r.CondTest != "" {
+			return fmt.Errorf("%q: cond_test needs cond (if or unless)", r.String())
+		}
+	case "if", "unless":
+		if r.CondTest == "" {
+			return fmt.Errorf("%q: cond %s needs a cond_test", r.String(), r.Cond)
+		}
+	default:
+		return fmt.Errorf("%q: invalid cond %q (expected if or unless)", r.String(), r.Cond)
+	}
+	return nil
+}
+
+func (r StickRule) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"type": r.Type, "pattern": r.Pattern}
+	if r.Table != "" {
+		payload["table"] = r.Table
+	}
+	if r.Cond != "" {
+		payload["cond"] = r.Cond
+		payload["cond_test"] = r.CondTest
+	}
+	return payload
+}
+
+// mapStickRuleFromAPI converts an API stick rule.
+func mapStickRuleFromAPI(obj map[string]interface{}) StickRule {
+	var r StickRule
+	r.Type, _ = obj["type"].(string)
+	r.Pattern, _ = obj["pattern"].(string)
+	r.Table, _ = obj["table"].(string)
+	r.Cond, _ = obj["cond"].(string)
+	r.CondTest, _ = obj["cond_test"].(string)
+	return r
+}
+
+func stickRulesPath(backendName string) string {
+	return "/services/haproxy/configuration/backends/" + backendName + "/stick_rules"
+}
+
+// liveStickRules returns the stick rules of a backend in order, or nil when
+// it has none.
+func liveStickRules(ctx context.Context, backendName string) ([]StickRule, error) {
+	list, err := internal.GetResourceListWithContext(ctx, stickRulesPath(backendName))
+	if err != nil {
+		return nil, err
+	}
+	var rules []StickRule
+	for _, raw := range list {
+		rules = append(rules, mapStickRuleFromAPI(raw))
+	}
+	return rules, nil
+}
+
+// applyStickRuleDiff turns the stick rules of a backend from before into
+// after, staging the changes in tx when it is non-nil.
+func applyStickRuleDiff(tx *internal.Transaction, backendName string, before, after []StickRule) error {
+	return internal.ApplyIndexedDiff(tx, stickRulesPath(backendName), before, after, func(r StickRule) interface{} { return r.toPayload() })
+}
+
+// GetStickRulesCmd represents "get stick-rules <backend>".
+var GetStickRulesCmd = &cobra.Command{
+	Use:     "stick-rules <backend_name>",
+	Aliases: []string{"stick-rule"},
+	Short:   "List the stick rules of a backend",
+	Long: `List the stick rules (stick match/on/store-request/store-response) of
+a backend in evaluation order. The index column is the position used by the
+delete command.
+
+Examples:
+  haproxyctl get stick-rules app
+  haproxyctl get stick-rules app -o yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rules, err := internal.GetResourceList(stickRulesPath(args[0]))
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(backendKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch stick rules of backend '%s': %v", args[0], err)
+		}
+		internal.SortForCmd(cmd, rules)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(rules, stickRuleColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// stickRuleColumns shows each rule as position, type, pattern, table and
+// condition.
+var stickRuleColumns = internal.ColumnSet{
+	Default: []string{"index", "type", "pattern", "table", "cond", "cond_test"},
+}
+
+// CreateStickRuleCmd represents "create stick-rules <backend>".
+var CreateStickRuleCmd = &cobra.Command{
+	Use:     "stick-rules <backend_name>",
+	Aliases: []string{"stick-rule"},
+	Short:   "Add a stick rule to a backend",
+	Long: `Add a "stick <type> <pattern> [table <table>] [if|unless <condition>]"
+rule to a backend. The rule is appended unless --index places it at a given
+position. The backend needs a stick table (stick_table in its manifest)
+unless --table names another one.
+
+Examples:
+  haproxyctl create stick-rules app --type on --pattern src
+  haproxyctl create stick-rules app --type store-response --pattern "res.cook(SRV)" --table sessions`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rule := StickRule{
+			Type:     internal.GetFlagString(cmd, "type"),
+			Pattern:  internal.GetFlagString(cmd, "pattern"),
+			Table:    internal.GetFlagString(cmd, "table"),
+			Cond:     internal.GetFlagString(cmd, "cond"),
+			CondTest: internal.GetFlagString(cmd, "cond-test"),
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateStickRule(args[0], rule, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+			internal.Fatalf("Failed to create stick rule on backend '%s': %v", args[0], err)
+		}
+	},
+}
+
+// DeleteStickRuleCmd represents "delete stick-rules <backend> --index N".
+var DeleteStickRuleCmd = &cobra.Command{
+	Use:     "stick-rules <backend_name>",
+	Aliases: []string{"stick-rule"},
+	Short:   "Delete a stick rule from a backend",
+	Long: `Delete the stick rule at --index (as shown by "get stick-rules").
+Later rules move up by one position.
+
+Examples:
+  haproxyctl delete stick-rules app --index 0`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		index := internal.GetFlagInt(cmd, "index")
+		if index < 0 {
+			internal.FatalCodef(internal.ExitUsage, "--index is required")
+		}
+		if err := DeleteStickRule(args[0], index); err != nil {
+			internal.Fatalf("Failed to delete stick rule %d of backend '%s': %v", index, args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateStickRuleCmd.Flags().String("type", "on", "Rule type: match, on, store-request or store-response")
+	CreateStickRuleCmd.Flags().String("pattern", "", "Sample expression to stick on, e.g. src or req.cook(SRV) (required)")
+	CreateStickRuleCmd.Flags().String("table", "", "Stick table to use (default: the backend's own)")
+	CreateStickRuleCmd.Flags().String("cond", "", "Condition keyword: if or unless")
+	CreateStickRuleCmd.Flags().String("cond-test", "", "Condition, e.g. an ACL name")
+	CreateStickRuleCmd.Flags().Int("index", -1, "Position to insert the rule at (default: append)")
+	CreateStickRuleCmd.Flags().Bool("dry-run", false, "Print the rule without creating it")
+
+	DeleteStickRuleCmd.Flags().Int("index", -1, "Position of the rule to delete (required)")
+}
+
+// CreateStickRule inserts a rule at index, or appends it when index is
+// negative.
+func CreateStickRule(backendName string, rule StickRule, index int, dryRun bool) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(rule.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := liveStickRules(context.Background(), backendName)
+	if err != nil {
+		return internal.FormatAPIError(backendKind, backendName, "get", err)
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", stickRulesPath(backendName)+"/"+strconv.Itoa(index), params, rule.toPayload()); err != nil {
+		return fmt.Errorf("failed to create %q: %w", rule.String(), err)
+	}
+
+	internal.PrintStatus("StickRule", fmt.Sprintf("%s/%d", backendName, index), internal.ActionCreated)
+	return nil
+}
+
+// DeleteStickRule removes the rule at index.
+func DeleteStickRule(backendName string, index int) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%d", backendName, index)
+	if _, err := internal.SendRequest("DELETE", stickRulesPath(backendName)+"/"+strconv.Itoa(index), params, nil); err != nil {
+		return internal.FormatAPIError("StickRule", id, "delete", err)
+	}
+
+	internal.PrintStatus("StickRule", id, internal.ActionDeleted)
+	return nil
+}
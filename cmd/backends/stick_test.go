@@ -0,0 +1,71 @@
+package backends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testStickManifest = testBackendManifest + `stick_table:
+  type: ip
+  size: 100000
+  expire: 30m0s
+  store: conn_cur
+stick_rules:
+  - type: on
+    pattern: src
+`
+
+func TestApplyBackendFromYAML_StickTableAndRules(t *testing.T) {
+	srv := testserver.New(t)
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testStickManifest), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+
+	be, _ := srv.Backend("web")
+	table, ok := be["stick_table"].(map[string]interface{})
+	if !ok || table["type"] != "ip" || table["expire"] != float64(1800000) {
+		t.Fatalf("stick_table not sent in API form: %+v", be["stick_table"])
+	}
+	rules := srv.List("backends", "web", "stick_rules")
+	if len(rules) != 1 || rules[0]["type"] != "on" || rules[0]["pattern"] != "src" {
+		t.Fatalf("stick rules = %+v", rules)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testStickManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+}
+
+func TestStickValidation(t *testing.T) {
+	if err := (StickTable{Type: "ip", Size: 0}).validate(); err == nil {
+		t.Error("expected error for zero size")
+	}
+	if err := (StickTable{Type: "mac", Size: 10}).validate(); err == nil {
+		t.Error("expected error for unknown type")
+	}
+	if err := (StickRule{Type: "on"}).validate(); err == nil {
+		t.Error("expected error for missing pattern")
+	}
+	if err := (StickRule{Type: "store", Pattern: "src"}).validate(); err == nil {
+		t.Error("expected error for unknown rule type")
+	}
+
+	rule := StickRule{Type: "match", Pattern: "req.cook(SRV)", Table: "sessions", Cond: "if", CondTest: "is_api"}
+	if err := rule.validate(); err != nil {
+		t.Fatalf("valid rule rejected: %v", err)
+	}
+	if got := rule.String(); got != "stick match req.cook(SRV) table sessions if is_api" {
+		t.Fatalf("String() = %q", got)
+	}
+}
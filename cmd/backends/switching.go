@@ -0,0 +1,246 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backends provides commands to manage HAProxy backends.
+package backends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// ServerSwitchingRule is a "use-server <server> [if|unless <condition>]" line.
+//
+//nolint:tagliatelle // manifest uses snake_case like backendConfig
+type ServerSwitchingRule struct {
+	Server   string `json:"server" yaml:"server"`
+	Cond     string `json:"cond,omitempty" yaml:"cond,omitempty"`
+	CondTest string `json:"cond_test,omitempty" yaml:"cond_test,omitempty"`
+}
+
+// String renders the rule the way it appears in haproxy.cfg.
+func (r ServerSwitchingRule) String() string {
+	if r.Cond == "" {
+		return "use-server " + r.Server
+	}
+	return fmt.Sprintf("use-server %s %s %s", r.Server, r.Cond, r.CondTest)
+}
+
+func (r ServerSwitchingRule) validate() error {
+	if r.Server == "" {
+		return errors.New("server switching rule is missing server")
+	}
+	switch r.Cond {
+	case "":
+		if r.CondTest != "" {
+			return fmt.Errorf("%q: cond_test needs cond (if or unless)", r.String())
+		}
+	case "if", "unless":
+		if r.CondTest == "" {
+			return fmt.Errorf("%q: cond %s needs a cond_test", r.String(), r.Cond)
+		}
+	default:
+		return fmt.Errorf("%q: invalid cond %q (expected if or unless)", r.String(), r.Cond)
+	}
+	return nil
+}
+
+func (r ServerSwitchingRule) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"target_server": r.Server}
+	if r.Cond != "" {
+		payload["cond"] = r.Cond
+		payload["cond_test"] = r.CondTest
+	}
+	return payload
+}
+
+// mapServerSwitchingRuleFromAPI converts an API server switching rule.
+func mapServerSwitchingRuleFromAPI(obj map[string]interface{}) ServerSwitchingRule {
+	var r ServerSwitchingRule
+	r.Server, _ = obj["target_server"].(string)
+	r.Cond, _ = obj["cond"].(string)
+	r.CondTest, _ = obj["cond_test"].(string)
+	return r
+}
+
+func serverSwitchingRulesPath(backendName string) string {
+	return "/services/haproxy/configuration/backends/" + backendName + "/server_switching_rules"
+}
+
+// liveServerSwitchingRules returns the server switching rules of a backend in
+// order, or nil when it has none.
+func liveServerSwitchingRules(ctx context.Context, backendName string) ([]ServerSwitchingRule, error) {
+	list, err := internal.GetResourceListWithContext(ctx, serverSwitchingRulesPath(backendName))
+	if err != nil {
+		return nil, err
+	}
+	var rules []ServerSwitchingRule
+	for _, raw := range list {
+		rules = append(rules, mapServerSwitchingRuleFromAPI(raw))
+	}
+	return rules, nil
+}
+
+// applyServerSwitchingRuleDiff turns the server switching rules of a backend
+// from before into after, staging the changes in tx when it is non-nil.
+func applyServerSwitchingRuleDiff(tx *internal.Transaction, backendName string, before, after []ServerSwitchingRule) error {
+	return internal.ApplyIndexedDiff(tx, serverSwitchingRulesPath(backendName), before, after, func(r ServerSwitchingRule) interface{} { return r.toPayload() })
+}
+
+// GetServerSwitchingRulesCmd represents "get server-switching-rules <backend>".
+var GetServerSwitchingRulesCmd = &cobra.Command{
+	Use:     "server-switching-rules <backend_name>",
+	Aliases: []string{"server-switching-rule", "use-server"},
+	Short:   "List the use-server rules of a backend",
+	Long: `List the server switching (use-server) rules of a backend in
+evaluation order. The index column is the position used by the delete
+command.
+
+Examples:
+  haproxyctl get server-switching-rules app
+  haproxyctl get server-switching-rules app -o yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rules, err := internal.GetResourceList(serverSwitchingRulesPath(args[0]))
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(backendKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch server switching rules of backend '%s': %v", args[0], err)
+		}
+		internal.SortForCmd(cmd, rules)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(rules, serverSwitchingRuleColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// serverSwitchingRuleColumns shows each rule as position, server and condition.
+var serverSwitchingRuleColumns = internal.ColumnSet{
+	Default: []string{"index", "target_server", "cond", "cond_test"},
+}
+
+// CreateServerSwitchingRuleCmd represents "create server-switching-rules <backend>".
+var CreateServerSwitchingRuleCmd = &cobra.Command{
+	Use:     "server-switching-rules <backend_name>",
+	Aliases: []string{"server-switching-rule", "use-server"},
+	Short:   "Add a use-server rule to a backend",
+	Long: `Add a "use-server <server> if|unless <condition>" rule to a backend.
+The rule is appended unless --index places it at a given position; rules
+are evaluated in order and the first match wins.
+
+Examples:
+  haproxyctl create server-switching-rules app --server app1 --cond if --cond-test "{ req.cook(node) app1 }"
+  haproxyctl create server-switching-rules app --server app2 --cond if --cond-test is_canary --index 0`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rule := ServerSwitchingRule{
+			Server:   internal.GetFlagString(cmd, "server"),
+			Cond:     internal.GetFlagString(cmd, "cond"),
+			CondTest: internal.GetFlagString(cmd, "cond-test"),
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateServerSwitchingRule(args[0], rule, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+			internal.Fatalf("Failed to create server switching rule on backend '%s': %v", args[0], err)
+		}
+	},
+}
+
+// DeleteServerSwitchingRuleCmd represents "delete server-switching-rules <backend> --index N".
+var DeleteServerSwitchingRuleCmd = &cobra.Command{
+	Use:     "server-switching-rules <backend_name>",
+	Aliases: []string{"server-switching-rule", "use-server"},
+	Short:   "Delete a use-server rule from a backend",
+	Long: `Delete the server switching rule at --index (as shown by "get
+server-switching-rules"). Later rules move up by one position.
+
+Examples:
+  haproxyctl delete server-switching-rules app --index 1`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		index := internal.GetFlagInt(cmd, "index")
+		if index < 0 {
+			internal.FatalCodef(internal.ExitUsage, "--index is required")
+		}
+		if err := DeleteServerSwitchingRule(args[0], index); err != nil {
+			internal.Fatalf("Failed to delete server switching rule %d of backend '%s': %v", index, args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateServerSwitchingRuleCmd.Flags().String("server", "", "Server to send matching traffic to (required)")
+	CreateServerSwitchingRuleCmd.Flags().String("cond", "", "Condition keyword: if or unless")
+	CreateServerSwitchingRuleCmd.Flags().String("cond-test", "", "Condition, e.g. \"{ req.cook(node) app1 }\" or an ACL name")
+	CreateServerSwitchingRuleCmd.Flags().Int("index", -1, "Position to insert the rule at (default: append)")
+	CreateServerSwitchingRuleCmd.Flags().Bool("dry-run", false, "Print the rule without creating it")
+
+	DeleteServerSwitchingRuleCmd.Flags().Int("index", -1, "Position of the rule to delete (required)")
+}
+
+// CreateServerSwitchingRule inserts a rule at index, or appends it when index
+// is negative.
+func CreateServerSwitchingRule(backendName string, rule ServerSwitchingRule, index int, dryRun bool) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(rule.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := liveServerSwitchingRules(context.Background(), backendName)
+	if err != nil {
+		return internal.FormatAPIError(backendKind, backendName, "get", err)
+	}
+	if _, err := internal.GetResource("/services/haproxy/configuration/backends/" + backendName + "/servers/" + rule.Server); err != nil {
+		return internal.FormatAPIError("Server", backendName+"/"+rule.Server, "get", err)
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", serverSwitchingRulesPath(backendName)+"/"+strconv.Itoa(index), params, rule.toPayload()); err != nil {
+		return fmt.Errorf("failed to create %q: %w", rule.String(), err)
+	}
+
+	internal.PrintStatus("ServerSwitchingRule", fmt.Sprintf("%s/%d", backendName, index), internal.ActionCreated)
+	return nil
+}
+
+// DeleteServerSwitchingRule removes the rule at index.
+func DeleteServerSwitchingRule(backendName string, index int) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%d", backendName, index)
+	if _, err := internal.SendRequest("DELETE", serverSwitchingRulesPath(backendName)+"/"+strconv.Itoa(index), params, nil); err != nil {
+		return internal.FormatAPIError("ServerSwitchingRule", id, "delete", err)
+	}
+
+	internal.PrintStatus("ServerSwitchingRule", id, internal.ActionDeleted)
+	return nil
+}
@@ -0,0 +1,99 @@
+package backends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func serverSwitchingTargets(srv *testserver.Server) []string {
+	var out []string
+	for _, r := range srv.List("backends", "web", "server_switching_rules") {
+		out = append(out, r["target_server"].(string))
+	}
+	return out
+}
+
+func TestApplyBackendFromYAML_ServerSwitchingRules(t *testing.T) {
+	srv := testserver.New(t)
+
+	withRules := testBackendManifest + `server_switching_rules:
+  - server: s1
+    cond: if
+    cond_test: "{ req.cook(node) s1 }"
+`
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(withRules), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+	if got := strings.Join(serverSwitchingTargets(srv), ","); got != "s1" {
+		t.Fatalf("rules after create = %s, want s1", got)
+	}
+	if srv.Version() != 2 {
+		t.Fatalf("version after create = %d, want 2 (one transaction)", srv.Version())
+	}
+
+	// A manifest without the key leaves the rules alone.
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testBackendManifest), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testBackendManifest+"server_switching_rules: []\n"), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if got := serverSwitchingTargets(srv); len(got) != 0 {
+		t.Fatalf("rules after clearing = %v, want none", got)
+	}
+}
+
+func TestBackendValidate_ServerSwitchingRuleTarget(t *testing.T) {
+	var b backendWithServers
+	b.APIVersion, b.Kind, b.Name, b.Mode = "haproxyctl/v1", backendKind, "web", "http"
+	b.Servers = parseServersFromFlags([]string{"name=s1,address=10.0.0.1,port=80"})
+	b.ServerSwitchingRules = []ServerSwitchingRule{{Server: "s2"}}
+	if err := b.Validate(); err == nil || !strings.Contains(err.Error(), "not listed") {
+		t.Fatalf("expected unknown server error, got %v", err)
+	}
+
+	b.ServerSwitchingRules = []ServerSwitchingRule{{Server: "s1", Cond: "if"}}
+	if err := b.Validate(); err == nil {
+		t.Fatal("expected error for cond without cond_test")
+	}
+}
+
+func TestCreateServerSwitchingRule(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddServer("web", map[string]interface{}{"name": "s1", "address": "10.0.0.1", "port": 80})
+
+	output := internal.CaptureStdout(t, func() {
+		if err := CreateServerSwitchingRule("web", ServerSwitchingRule{Server: "s1", Cond: "if", CondTest: "is_canary"}, -1, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "web/0 created") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if err := CreateServerSwitchingRule("web", ServerSwitchingRule{Server: "missing"}, -1, false); err == nil {
+		t.Fatal("expected error for unknown server")
+	}
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := DeleteServerSwitchingRule("web", 0); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+	})
+	if got := serverSwitchingTargets(srv); len(got) != 0 {
+		t.Fatalf("rules after delete = %v", got)
+	}
+}
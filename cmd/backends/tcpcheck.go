@@ -0,0 +1,327 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backends provides commands to manage HAProxy backends.
+package backends
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	tcpCheckActions = []string{"connect", "send", "send-binary", "expect", "comment"}
+	tcpCheckMatches = []string{"string", "rstring", "binary", "rbinary"}
+)
+
+// TCPCheck is a "tcp-check <action> ..." line. Only the fields of its action
+// are used: data for send, hex for send-binary, match, pattern and negate
+// for expect, and addr, port and ssl for connect.
+//
+//nolint:tagliatelle // manifest uses snake_case like backendConfig
+type TCPCheck struct {
+	Action  string `json:"action" yaml:"action"`
+	Data    string `json:"data,omitempty" yaml:"data,omitempty"`
+	Hex     string `json:"hex_string,omitempty" yaml:"hex_string,omitempty"`
+	Match   string `json:"match,omitempty" yaml:"match,omitempty"`
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Negate  bool   `json:"negate,omitempty" yaml:"negate,omitempty"`
+	Addr    string `json:"addr,omitempty" yaml:"addr,omitempty"`
+	Port    int    `json:"port,omitempty" yaml:"port,omitempty"`
+	SSL     bool   `json:"ssl,omitempty" yaml:"ssl,omitempty"`
+	Comment string `json:"check_comment,omitempty" yaml:"check_comment,omitempty"`
+}
+
+// String renders the check the way it appears in haproxy.cfg.
+func (c TCPCheck) String() string {
+	parts := []string{"tcp-check", c.Action}
+	switch c.Action {
+	case "send":
+		parts = append(parts, strconv.Quote(c.Data))
+	case "send-binary":
+		parts = append(parts, c.Hex)
+	case "expect":
+		if c.Negate {
+			parts = append(parts, "!")
+		}
+		parts = append(parts, c.Match, strconv.Quote(c.Pattern))
+	case "connect":
+		if c.Addr != "" {
+			parts = append(parts, "addr", c.Addr)
+		}
+		if c.Port != 0 {
+			parts = append(parts, "port", strconv.Itoa(c.Port))
+		}
+		if c.SSL {
+			parts = append(parts, "ssl")
+		}
+	}
+	if c.Comment != "" {
+		parts = append(parts, "comment", strconv.Quote(c.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c TCPCheck) validate() error {
+	if !slices.Contains(tcpCheckActions, c.Action) {
+		return fmt.Errorf("invalid tcp-check action %q (allowed: %s)", c.Action, strings.Join(tcpCheckActions, ", "))
+	}
+	if (c.Action == "send") != (c.Data != "") {
+		return fmt.Errorf("%q: data is required for send and only valid there", c.String())
+	}
+	if (c.Action == "send-binary") != (c.Hex != "") {
+		return fmt.Errorf("%q: hex_string is required for send-binary and only valid there", c.String())
+	}
+	if c.Action != "expect" && (c.Match != "" || c.Pattern != "" || c.Negate) {
+		return fmt.Errorf("%q: match, pattern and negate are only valid for expect", c.String())
+	}
+	if c.Action != "connect" && (c.Addr != "" || c.Port != 0 || c.SSL) {
+		return fmt.Errorf("%q: addr, port and ssl are only valid for connect", c.String())
+	}
+	if c.Action == "expect" {
+		if !slices.Contains(tcpCheckMatches, c.Match) {
+			return fmt.Errorf("%q: invalid match %q (allowed: %s)", c.String(), c.Match, strings.Join(tcpCheckMatches, ", "))
+		}
+		if c.Pattern == "" {
+			return fmt.Errorf("%q: pattern is required", c.String())
+		}
+	}
+	return nil
+}
+
+func (c TCPCheck) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"action": c.Action}
+	setIfNotEmpty := func(key, value string) {
+		if value != "" {
+			payload[key] = value
+		}
+	}
+	setIfNotEmpty("data", c.Data)
+	setIfNotEmpty("hex_string", c.Hex)
+	setIfNotEmpty("match", c.Match)
+	setIfNotEmpty("pattern", c.Pattern)
+	setIfNotEmpty("addr", c.Addr)
+	setIfNotEmpty("check_comment", c.Comment)
+	if c.Negate {
+		payload["exclamation_mark"] = true
+	}
+	if c.Port != 0 {
+		payload["port"] = c.Port
+	}
+	if c.SSL {
+		payload["ssl"] = true
+	}
+	return payload
+}
+
+// mapTCPCheckFromAPI converts an API tcp check.
+func mapTCPCheckFromAPI(obj map[string]interface{}) TCPCheck {
+	var c TCPCheck
+	c.Action, _ = obj["action"].(string)
+	c.Data, _ = obj["data"].(string)
+	c.Hex, _ = obj["hex_string"].(string)
+	c.Match, _ = obj["match"].(string)
+	c.Pattern, _ = obj["pattern"].(string)
+	c.Negate, _ = obj["exclamation_mark"].(bool)
+	c.Addr, _ = obj["addr"].(string)
+	c.Port, _ = internal.GetIntField(obj, "port")
+	c.SSL, _ = obj["ssl"].(bool)
+	c.Comment, _ = obj["check_comment"].(string)
+	return c
+}
+
+func tcpChecksPath(backendName string) string {
+	return "/services/haproxy/configuration/backends/" + backendName + "/tcp_checks"
+}
+
+// liveTCPChecks returns the tcp checks of a backend in order, or nil when
+// it has none.
+func liveTCPChecks(ctx context.Context, backendName string) ([]TCPCheck, error) {
+	list, err := internal.GetResourceListWithContext(ctx, tcpChecksPath(backendName))
+	if err != nil {
+		return nil, err
+	}
+	var checks []TCPCheck
+	for _, raw := range list {
+		checks = append(checks, mapTCPCheckFromAPI(raw))
+	}
+	return checks, nil
+}
+
+// applyTCPCheckDiff turns the tcp checks of a backend from before into
+// after, staging the changes in tx when it is non-nil.
+func applyTCPCheckDiff(tx *internal.Transaction, backendName string, before, after []TCPCheck) error {
+	return internal.ApplyIndexedDiff(tx, tcpChecksPath(backendName), before, after, func(c TCPCheck) interface{} { return c.toPayload() })
+}
+
+// GetTCPChecksCmd represents "get tcp-checks <backend>".
+var GetTCPChecksCmd = &cobra.Command{
+	Use:     "tcp-checks <backend_name>",
+	Aliases: []string{"tcp-check"},
+	Short:   "List the tcp-check rules of a backend",
+	Long: `List the tcp-check connect/send/expect rules of a backend in order.
+The index column is the position used by the delete command.
+
+Examples:
+  haproxyctl get tcp-checks redis
+  haproxyctl get tcp-checks redis -o yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checks, err := internal.GetResourceList(tcpChecksPath(args[0]))
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(backendKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch tcp checks of backend '%s': %v", args[0], err)
+		}
+		internal.SortForCmd(cmd, checks)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(checks, tcpCheckColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// tcpCheckColumns shows each check as position, action and the fields of
+// the common send and expect actions.
+var tcpCheckColumns = internal.ColumnSet{
+	Default: []string{"index", "action", "data", "match", "pattern"},
+	Wide:    []string{"hex_string", "exclamation_mark", "addr", "port", "check_comment"},
+}
+
+// CreateTCPCheckCmd represents "create tcp-checks <backend>".
+var CreateTCPCheckCmd = &cobra.Command{
+	Use:     "tcp-checks <backend_name>",
+	Aliases: []string{"tcp-check"},
+	Short:   "Add a tcp-check rule to a backend",
+	Long: `Add a "tcp-check <action> ..." rule to a backend. The rule is appended
+unless --index places it at a given position. Rules only take effect when
+the backend has "option tcp-check" (adv_check: tcp-check in its manifest).
+
+Examples:
+  haproxyctl create tcp-checks redis --action send --data 'PING\r\n'
+  haproxyctl create tcp-checks redis --action expect --match string --pattern +PONG
+  haproxyctl create tcp-checks db --action connect --port 3307 --index 0`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		check := TCPCheck{
+			Action:  internal.GetFlagString(cmd, "action"),
+			Data:    internal.GetFlagString(cmd, "data"),
+			Hex:     internal.GetFlagString(cmd, "hex"),
+			Match:   internal.GetFlagString(cmd, "match"),
+			Pattern: internal.GetFlagString(cmd, "pattern"),
+			Negate:  internal.GetFlagBool(cmd, "negate"),
+			Addr:    internal.GetFlagString(cmd, "addr"),
+			Port:    internal.GetFlagInt(cmd, "port"),
+			SSL:     internal.GetFlagBool(cmd, "ssl"),
+			Comment: internal.GetFlagString(cmd, "comment"),
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateTCPCheck(args[0], check, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+			internal.Fatalf("Failed to create tcp check on backend '%s': %v", args[0], err)
+		}
+	},
+}
+
+// DeleteTCPCheckCmd represents "delete tcp-checks <backend> --index N".
+var DeleteTCPCheckCmd = &cobra.Command{
+	Use:     "tcp-checks <backend_name>",
+	Aliases: []string{"tcp-check"},
+	Short:   "Delete a tcp-check rule from a backend",
+	Long: `Delete the tcp-check rule at --index (as shown by "get tcp-checks").
+Later rules move up by one position.
+
+Examples:
+  haproxyctl delete tcp-checks redis --index 1`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		index := internal.GetFlagInt(cmd, "index")
+		if index < 0 {
+			internal.FatalCodef(internal.ExitUsage, "--index is required")
+		}
+		if err := DeleteTCPCheck(args[0], index); err != nil {
+			internal.Fatalf("Failed to delete tcp check %d of backend '%s': %v", index, args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateTCPCheckCmd.Flags().String("action", "", "Check action: connect, send, send-binary, expect or comment (required)")
+	CreateTCPCheckCmd.Flags().String("data", "", "send: data to send, e.g. 'PING\\r\\n'")
+	CreateTCPCheckCmd.Flags().String("hex", "", "send-binary: data to send as a hex string")
+	CreateTCPCheckCmd.Flags().String("match", "", "expect: string, rstring, binary or rbinary")
+	CreateTCPCheckCmd.Flags().String("pattern", "", "expect: value to match, e.g. +PONG")
+	CreateTCPCheckCmd.Flags().Bool("negate", false, "expect: invert the match (\"!\")")
+	CreateTCPCheckCmd.Flags().String("addr", "", "connect: address to connect to")
+	CreateTCPCheckCmd.Flags().Int("port", 0, "connect: port to connect to")
+	CreateTCPCheckCmd.Flags().Bool("ssl", false, "connect: use SSL")
+	CreateTCPCheckCmd.Flags().String("comment", "", "Comment reported in the check status")
+	CreateTCPCheckCmd.Flags().Int("index", -1, "Position to insert the rule at (default: append)")
+	CreateTCPCheckCmd.Flags().Bool("dry-run", false, "Print the rule without creating it")
+
+	DeleteTCPCheckCmd.Flags().Int("index", -1, "Position of the rule to delete (required)")
+}
+
+// CreateTCPCheck inserts a check at index, or appends it when index is
+// negative.
+func CreateTCPCheck(backendName string, check TCPCheck, index int, dryRun bool) error {
+	if err := check.validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(check.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := liveTCPChecks(context.Background(), backendName)
+	if err != nil {
+		return internal.FormatAPIError(backendKind, backendName, "get", err)
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", tcpChecksPath(backendName)+"/"+strconv.Itoa(index), params, check.toPayload()); err != nil {
+		return fmt.Errorf("failed to create %q: %w", check.String(), err)
+	}
+
+	internal.PrintStatus("TCPCheck", fmt.Sprintf("%s/%d", backendName, index), internal.ActionCreated)
+	return nil
+}
+
+// DeleteTCPCheck removes the check at index.
+func DeleteTCPCheck(backendName string, index int) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%d", backendName, index)
+	if _, err := internal.SendRequest("DELETE", tcpChecksPath(backendName)+"/"+strconv.Itoa(index), params, nil); err != nil {
+		return internal.FormatAPIError("TCPCheck", id, "delete", err)
+	}
+
+	internal.PrintStatus("TCPCheck", id, internal.ActionDeleted)
+	return nil
+}
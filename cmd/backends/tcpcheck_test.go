@@ -0,0 +1,90 @@
+package backends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testTCPCheckManifest = `apiVersion: haproxyctl/v1
+kind: Backend
+name: redis
+mode: tcp
+adv_check: tcp-check
+tcp_checks:
+  - action: connect
+  - action: send
+    data: "PING\r\n"
+  - action: expect
+    match: string
+    pattern: +PONG
+`
+
+func TestApplyBackendFromYAML_TCPChecks(t *testing.T) {
+	srv := testserver.New(t)
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testTCPCheckManifest), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+
+	checks := srv.List("backends", "redis", "tcp_checks")
+	if len(checks) != 3 || checks[1]["data"] != "PING\r\n" || checks[2]["pattern"] != "+PONG" {
+		t.Fatalf("tcp checks = %+v", checks)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(testTCPCheckManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/redis unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	// Only the changed expect rule is rewritten.
+	changed := strings.Replace(testTCPCheckManifest, "+PONG", "+OK", 1)
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyBackendFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	if n := srv.CountRequests("DELETE", "/v3/services/haproxy/configuration/backends/redis/tcp_checks/1"); n != 0 {
+		t.Fatalf("unchanged send rule was deleted %d times", n)
+	}
+	if got := srv.List("backends", "redis", "tcp_checks")[2]["pattern"]; got != "+OK" {
+		t.Fatalf("expect pattern = %v, want +OK", got)
+	}
+}
+
+func TestTCPCheckValidation(t *testing.T) {
+	cases := []struct {
+		check TCPCheck
+		ok    bool
+	}{
+		{TCPCheck{Action: "connect", Port: 6379}, true},
+		{TCPCheck{Action: "send", Data: "PING\r\n"}, true},
+		{TCPCheck{Action: "send-binary", Hex: "0a0b"}, true},
+		{TCPCheck{Action: "expect", Match: "string", Pattern: "+PONG"}, true},
+		{TCPCheck{Action: "send"}, false},
+		{TCPCheck{Action: "connect", Data: "x"}, false},
+		{TCPCheck{Action: "expect", Match: "status", Pattern: "200"}, false},
+		{TCPCheck{Action: "send", Data: "x", Port: 1}, false},
+		{TCPCheck{Action: "recv"}, false},
+	}
+	for _, tc := range cases {
+		if err := tc.check.validate(); (err == nil) != tc.ok {
+			t.Errorf("validate(%+v) error = %v, want ok=%v", tc.check, err, tc.ok)
+		}
+	}
+
+	b := backendWithServers{APIVersion: "haproxyctl/v1", Kind: backendKind}
+	b.Name, b.Mode = "redis", "tcp"
+	b.TCPChecks = []TCPCheck{{Action: "connect"}}
+	if err := b.Validate(); err == nil {
+		t.Error("expected error for tcp_checks without adv_check: tcp-check")
+	}
+}
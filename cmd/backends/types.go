@@ -20,12 +20,15 @@ package backends
 import (
 	"errors"
 	"fmt"
-	"log"
-	"strconv"
+	"slices"
 	"strings"
 
+	"haproxyctl/cmd/filters"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/logtargets"
 	"haproxyctl/cmd/servers"
 	"haproxyctl/internal"
+	"haproxyctl/pkg/client"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -42,22 +45,45 @@ const stateEnabled = "enabled"
 type backendConfig struct {
 	Name                 string                   `json:"name" yaml:"name"`
 	Mode                 string                   `json:"mode,omitempty" yaml:"mode,omitempty"`
+	From                 string                   `json:"from,omitempty" yaml:"from,omitempty"`
 	Balance              map[string]string        `json:"balance,omitempty" yaml:"balance,omitempty"`
+	AdvCheck             string                   `json:"adv_check,omitempty" yaml:"adv_check,omitempty"`
 	HTTPChkParams        map[string]string        `json:"httpchk_params,omitempty" yaml:"httpchk_params,omitempty"`
-	Cookie               map[string]string        `json:"cookie,omitempty" yaml:"cookie,omitempty"`
+	HashType             map[string]string        `json:"hash_type,omitempty" yaml:"hash_type,omitempty"`
+	Cookie               *Cookie                  `json:"cookie,omitempty" yaml:"cookie,omitempty"`
 	Log                  []map[string]interface{} `json:"log,omitempty" yaml:"log,omitempty"`
 	DefaultServer        map[string]interface{}   `json:"default_server,omitempty" yaml:"default_server,omitempty"`
 	ForwardFor           map[string]string        `json:"forwardfor,omitempty" yaml:"forwardfor,omitempty"`
 	HTTPRequestRules     []map[string]interface{} `json:"http_request_rules,omitempty" yaml:"http_request_rules,omitempty"`
 	HTTPResponseRules    []map[string]interface{} `json:"http_response_rules,omitempty" yaml:"http_response_rules,omitempty"`
 	TCPRequestRules      []map[string]interface{} `json:"tcp_request_rules,omitempty" yaml:"tcp_request_rules,omitempty"`
-	ErrorFiles           []map[string]interface{} `json:"error_files,omitempty" yaml:"error_files,omitempty"`
+	ErrorFiles           []httperrors.ErrorFile   `json:"error_files,omitempty" yaml:"error_files,omitempty"`
 	TimeoutClient        string                   `json:"timeout_client,omitempty" yaml:"timeout_client,omitempty"`
 	TimeoutHTTPKeepAlive string                   `json:"timeout_http_keep_alive,omitempty" yaml:"timeout_http_keep_alive,omitempty"`
 	TimeoutHTTPRequest   string                   `json:"timeout_http_request,omitempty" yaml:"timeout_http_request,omitempty"`
 	TimeoutQueue         string                   `json:"timeout_queue,omitempty" yaml:"timeout_queue,omitempty"`
 	TimeoutServer        string                   `json:"timeout_server,omitempty" yaml:"timeout_server,omitempty"`
 	TimeoutServerFin     string                   `json:"timeout_server_fin,omitempty" yaml:"timeout_server_fin,omitempty"`
+	TimeoutConnect       string                   `json:"timeout_connect,omitempty" yaml:"timeout_connect,omitempty"`
+	TimeoutCheck         string                   `json:"timeout_check,omitempty" yaml:"timeout_check,omitempty"`
+	TimeoutTunnel        string                   `json:"timeout_tunnel,omitempty" yaml:"timeout_tunnel,omitempty"`
+	Retries              int                      `json:"retries,omitempty" yaml:"retries,omitempty"`
+	RetryOn              string                   `json:"retry_on,omitempty" yaml:"retry_on,omitempty"`
+	HTTPReuse            string                   `json:"http_reuse,omitempty" yaml:"http_reuse,omitempty"`
+	HTTPConnectionMode   string                   `json:"http_connection_mode,omitempty" yaml:"http_connection_mode,omitempty"`
+	Fullconn             int                      `json:"fullconn,omitempty" yaml:"fullconn,omitempty"`
+	MaxKeepAliveQueue    int                      `json:"max_keep_alive_queue,omitempty" yaml:"max_keep_alive_queue,omitempty"`
+	// The "option" switches below take "enabled" or "disabled", as in
+	// the API; "disabled" writes "no option <name>" to override defaults.
+	Abortonclose         string `json:"abortonclose,omitempty" yaml:"abortonclose,omitempty"`
+	Allbackups           string `json:"allbackups,omitempty" yaml:"allbackups,omitempty"`
+	Checkcache           string `json:"checkcache,omitempty" yaml:"checkcache,omitempty"`
+	HTTPPretendKeepalive string `json:"http_pretend_keepalive,omitempty" yaml:"http_pretend_keepalive,omitempty"`
+	IndependentStreams   string `json:"independent_streams,omitempty" yaml:"independent_streams,omitempty"`
+	LogHealthChecks      string `json:"log_health_checks,omitempty" yaml:"log_health_checks,omitempty"`
+	Nolinger             string `json:"nolinger,omitempty" yaml:"nolinger,omitempty"`
+	Persist              string `json:"persist,omitempty" yaml:"persist,omitempty"`
+	PreferLastServer     string `json:"prefer_last_server,omitempty" yaml:"prefer_last_server,omitempty"`
 	// TCPKA and Redispatch are exposed as simple booleans in the CLI/YAML
 	// view, but the v3 Data Plane API expects different wire formats:
 	//   - tcpka: enum "enabled"/"disabled"
@@ -67,6 +93,12 @@ type backendConfig struct {
 	TCPKA      bool              `yaml:"tcpka,omitempty" json:"-"`
 	Redispatch bool              `yaml:"redispatch,omitempty" json:"-"`
 	Source     map[string]string `json:"source,omitempty" yaml:"source,omitempty"`
+	// StickTable is sent as-is apart from expire, which backendPayload
+	// converts to milliseconds like the timeouts.
+	StickTable *StickTable `json:"stick_table,omitempty" yaml:"stick_table,omitempty"`
+	// ErrorFilesFromHTTPErrors are the "errorfiles <section>" lines that
+	// use the pages of an http-errors section.
+	ErrorFilesFromHTTPErrors []httperrors.ErrorFilesFrom `json:"errorfiles_from_http_errors,omitempty" yaml:"errorfiles_from_http_errors,omitempty"`
 }
 
 // redispatchPayload matches the HAProxy Data Plane API v3 definition
@@ -83,14 +115,19 @@ type redispatchPayload struct {
 //nolint:tagliatelle
 type backendPayload struct {
 	backendConfig
-	TimeoutClient        int                `json:"timeout_client,omitempty"`
-	TimeoutHTTPKeepAlive int                `json:"timeout_http_keep_alive,omitempty"`
-	TimeoutHTTPRequest   int                `json:"timeout_http_request,omitempty"`
-	TimeoutQueue         int                `json:"timeout_queue,omitempty"`
-	TimeoutServer        int                `json:"timeout_server,omitempty"`
-	TimeoutServerFin     int                `json:"timeout_server_fin,omitempty"`
-	TCPKA                string             `json:"tcpka,omitempty"`
-	Redispatch           *redispatchPayload `json:"redispatch,omitempty"`
+	TimeoutClient        int                    `json:"timeout_client,omitempty"`
+	TimeoutHTTPKeepAlive int                    `json:"timeout_http_keep_alive,omitempty"`
+	TimeoutHTTPRequest   int                    `json:"timeout_http_request,omitempty"`
+	TimeoutQueue         int                    `json:"timeout_queue,omitempty"`
+	TimeoutServer        int                    `json:"timeout_server,omitempty"`
+	TimeoutServerFin     int                    `json:"timeout_server_fin,omitempty"`
+	TimeoutConnect       int                    `json:"timeout_connect,omitempty"`
+	TimeoutCheck         int                    `json:"timeout_check,omitempty"`
+	TimeoutTunnel        int                    `json:"timeout_tunnel,omitempty"`
+	Cookie               map[string]interface{} `json:"cookie,omitempty"`
+	TCPKA                string                 `json:"tcpka,omitempty"`
+	Redispatch           *redispatchPayload     `json:"redispatch,omitempty"`
+	StickTable           map[string]interface{} `json:"stick_table,omitempty"`
 }
 
 // backendWithServers represents the user-facing object that includes servers.
@@ -100,6 +137,19 @@ type backendWithServers struct {
 	Kind          string                 `yaml:"kind"`
 	backendConfig `yaml:",inline"`       // Embed all backendConfig fields directly
 	Servers       []servers.ServerConfig `json:"servers,omitempty" yaml:"servers,omitempty"`
+	// StickRules are the backend's stick rules in order, managed like
+	// ServerSwitchingRules.
+	StickRules []StickRule `json:"stick_rules,omitempty" yaml:"stick_rules,omitempty"` //nolint:tagliatelle // manifest uses snake_case like backendConfig
+	// ServerSwitchingRules are the backend's use-server rules in order.
+	// Apply leaves the live rules alone when the key is absent (nil); an
+	// explicit empty list removes them.
+	ServerSwitchingRules []ServerSwitchingRule `json:"server_switching_rules,omitempty" yaml:"server_switching_rules,omitempty"` //nolint:tagliatelle // manifest uses snake_case like backendConfig
+	// Filters, LogTargets, HTTPChecks and TCPChecks are managed like
+	// ServerSwitchingRules.
+	Filters    []filters.Filter       `json:"filters,omitempty" yaml:"filters,omitempty"`
+	LogTargets []logtargets.LogTarget `json:"log_targets,omitempty" yaml:"log_targets,omitempty"` //nolint:tagliatelle // manifest uses snake_case like backendConfig
+	HTTPChecks []HTTPCheck            `json:"http_checks,omitempty" yaml:"http_checks,omitempty"` //nolint:tagliatelle // manifest uses snake_case like backendConfig
+	TCPChecks  []TCPCheck             `json:"tcp_checks,omitempty" yaml:"tcp_checks,omitempty"`   //nolint:tagliatelle // manifest uses snake_case like backendConfig
 }
 
 // LoadFromFile loads backend + servers from a YAML file.
@@ -135,16 +185,81 @@ func (b *backendWithServers) LoadFromFlags(cmd *cobra.Command, backendName strin
 	b.TimeoutClient = internal.GetFlagString(cmd, "timeout-client")
 	b.TimeoutQueue = internal.GetFlagString(cmd, "timeout-queue")
 	b.TimeoutServer = internal.GetFlagString(cmd, "timeout-server")
+	b.TimeoutConnect = internal.GetFlagString(cmd, "timeout-connect")
+	b.TimeoutCheck = internal.GetFlagString(cmd, "timeout-check")
+	b.TimeoutTunnel = internal.GetFlagString(cmd, "timeout-tunnel")
+	b.Retries = internal.GetFlagInt(cmd, "retries")
+	b.RetryOn = internal.GetFlagString(cmd, "retry-on")
+	b.HTTPReuse = internal.GetFlagString(cmd, "http-reuse")
+	b.HTTPConnectionMode = internal.GetFlagString(cmd, "http-connection-mode")
+	b.Fullconn = internal.GetFlagInt(cmd, "fullconn")
 	b.Redispatch = internal.GetFlagBool(cmd, "redispatch")
 
+	if typ := internal.GetFlagString(cmd, "stick-table-type"); typ != "" {
+		b.StickTable = &StickTable{
+			Type:   typ,
+			Size:   internal.GetFlagInt(cmd, "stick-table-size"),
+			Expire: internal.GetFlagString(cmd, "stick-table-expire"),
+			Store:  internal.GetFlagString(cmd, "stick-table-store"),
+		}
+	}
+	if pattern := internal.GetFlagString(cmd, "stick-on"); pattern != "" {
+		b.StickRules = []StickRule{{Type: "on", Pattern: pattern}}
+	}
+
+	if check := internal.GetFlagString(cmd, "http-check"); check != "" {
+		b.AdvCheck = advCheckHTTPChk
+		b.HTTPChkParams = parseHTTPChkFlag(check)
+	}
+	if expect := internal.GetFlagString(cmd, "http-check-expect"); expect != "" {
+		match, pattern, _ := strings.Cut(expect, " ")
+		b.HTTPChecks = []HTTPCheck{{Type: "expect", Match: match, Pattern: strings.TrimSpace(pattern)}}
+	}
+
+	if cookie := internal.GetFlagString(cmd, "cookie"); cookie != "" {
+		c, err := ParseCookie(cookie)
+		if err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid --cookie: %v", err)
+		}
+		b.Cookie = c
+	}
+	for _, opt := range internal.GetFlagStringSlice(cmd, "option") {
+		if err := b.setOption(opt); err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid --option: %v", err)
+		}
+	}
+
 	rawServers := internal.GetFlagStringSlice(cmd, "server")
 	b.Servers = parseServersFromFlags(rawServers)
 }
 
 const keyValueParts = 2
 
+const (
+	advCheckHTTPChk  = "httpchk"
+	advCheckTCPCheck = "tcp-check"
+)
+
+// parseHTTPChkFlag turns "--http-check [METHOD] URI [VERSION]" into
+// httpchk_params, e.g. "GET /healthz HTTP/1.1" or just "/healthz".
+func parseHTTPChkFlag(value string) map[string]string {
+	fields := strings.Fields(value)
+	params := map[string]string{}
+	if len(fields) > 0 && !strings.HasPrefix(fields[0], "/") {
+		params["method"] = fields[0]
+		fields = fields[1:]
+	}
+	if len(fields) > 0 {
+		params["uri"] = fields[0]
+	}
+	if len(fields) > 1 {
+		params["version"] = fields[1]
+	}
+	return params
+}
+
 // parseServersFromFlags converts `--server` flags into servers.ServerConfig structs.
-// Example: --server name=s1,address=10.0.0.1,port=80,weight=100.
+// Example: --server name=s1,address=10.0.0.1,port=80,weight=100,check=true,inter=2s.
 func parseServersFromFlags(rawServers []string) []servers.ServerConfig {
 	var result []servers.ServerConfig
 	for _, serverStr := range rawServers {
@@ -155,21 +270,7 @@ func parseServersFromFlags(rawServers []string) []servers.ServerConfig {
 			if len(kv) != keyValueParts {
 				continue
 			}
-			key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
-			switch key {
-			case "name":
-				server.Name = value
-			case "address":
-				server.Address = value
-			case "port":
-				port, _ := strconv.Atoi(value)
-				server.Port = port
-			case "weight":
-				weight, _ := strconv.Atoi(value)
-				server.Weight = weight
-			case "ssl":
-				server.SSL = (strings.ToLower(value) == "true")
-			}
+			server.ParseFlagOption(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
 		}
 		if server.Name != "" && server.Address != "" && server.Port != 0 {
 			result = append(result, server)
@@ -183,6 +284,37 @@ func (b *backendWithServers) ToBackendConfig() backendConfig {
 	return b.backendConfig
 }
 
+// durations lists the backend's timeout fields by their API name.
+func (c *backendConfig) durations() map[string]*string {
+	return map[string]*string{
+		"timeout_client":          &c.TimeoutClient,
+		"timeout_http_keep_alive": &c.TimeoutHTTPKeepAlive,
+		"timeout_http_request":    &c.TimeoutHTTPRequest,
+		"timeout_queue":           &c.TimeoutQueue,
+		"timeout_server":          &c.TimeoutServer,
+		"timeout_server_fin":      &c.TimeoutServerFin,
+		"timeout_connect":         &c.TimeoutConnect,
+		"timeout_check":           &c.TimeoutCheck,
+		"timeout_tunnel":          &c.TimeoutTunnel,
+	}
+}
+
+// normalizeDurations renders the timeouts the way they come back from the
+// API (e.g. 60s as 1m0s), so manifests and live backends compare equal.
+func (c *backendConfig) normalizeDurations() {
+	for _, value := range c.durations() {
+		if ms, err := internal.ParseDurationToMillis(*value); err == nil {
+			*value = internal.FormatMillisAsDuration(ms)
+		}
+	}
+}
+
+// toClient returns the payload of b as a backend of the typed client.
+func (b *backendWithServers) toClient() (client.Backend, error) {
+	var out client.Backend
+	return out, internal.ConvertPayload(b.toPayload(), &out)
+}
+
 // toPayload converts the CLI/YAML view into the backendPayload that
 // matches the Data Plane API v3 schema.
 func (b *backendWithServers) toPayload() backendPayload {
@@ -191,41 +323,63 @@ func (b *backendWithServers) toPayload() backendPayload {
 	}
 
 	if ms, err := internal.ParseDurationToMillis(b.TimeoutClient); err != nil {
-		log.Fatalf("invalid backend timeout_client: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend timeout_client: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutClient = ms
 	}
 
 	if ms, err := internal.ParseDurationToMillis(b.TimeoutHTTPKeepAlive); err != nil {
-		log.Fatalf("invalid backend timeout_http_keep_alive: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend timeout_http_keep_alive: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutHTTPKeepAlive = ms
 	}
 
 	if ms, err := internal.ParseDurationToMillis(b.TimeoutHTTPRequest); err != nil {
-		log.Fatalf("invalid backend timeout_http_request: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend timeout_http_request: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutHTTPRequest = ms
 	}
 
 	if ms, err := internal.ParseDurationToMillis(b.TimeoutQueue); err != nil {
-		log.Fatalf("invalid backend timeout_queue: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend timeout_queue: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutQueue = ms
 	}
 
 	if ms, err := internal.ParseDurationToMillis(b.TimeoutServer); err != nil {
-		log.Fatalf("invalid backend timeout_server: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend timeout_server: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutServer = ms
 	}
 
 	if ms, err := internal.ParseDurationToMillis(b.TimeoutServerFin); err != nil {
-		log.Fatalf("invalid backend timeout_server_fin: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend timeout_server_fin: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutServerFin = ms
 	}
 
+	if ms, err := internal.ParseDurationToMillis(b.TimeoutConnect); err != nil {
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend timeout_connect: %v", err)
+	} else if ms > 0 {
+		payload.TimeoutConnect = ms
+	}
+
+	if ms, err := internal.ParseDurationToMillis(b.TimeoutCheck); err != nil {
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend timeout_check: %v", err)
+	} else if ms > 0 {
+		payload.TimeoutCheck = ms
+	}
+
+	if ms, err := internal.ParseDurationToMillis(b.TimeoutTunnel); err != nil {
+		internal.FatalCodef(internal.ExitInvalid, "invalid backend timeout_tunnel: %v", err)
+	} else if ms > 0 {
+		payload.TimeoutTunnel = ms
+	}
+
+	if b.Cookie != nil {
+		payload.Cookie = b.Cookie.toPayload()
+	}
+
 	if b.TCPKA {
 		payload.TCPKA = stateEnabled
 	}
@@ -238,6 +392,10 @@ func (b *backendWithServers) toPayload() backendPayload {
 		}
 	}
 
+	if b.StickTable != nil {
+		payload.StickTable = b.StickTable.toPayload()
+	}
+
 	return payload
 }
 
@@ -255,10 +413,90 @@ func (b *backendWithServers) Validate() error {
 	if b.Mode != "http" && b.Mode != "tcp" {
 		return fmt.Errorf("invalid mode: %s (allowed: http, tcp)", b.Mode)
 	}
+	seen := make(map[string]bool, len(b.Servers))
 	for _, server := range b.Servers {
 		if server.Name == "" || server.Address == "" || server.Port == 0 {
 			return errors.New("each server must have name, address, and port")
 		}
+		if err := internal.ValidatePort(server.Port); err != nil {
+			return fmt.Errorf("server %q: %w", server.Name, err)
+		}
+		if err := server.ValidateOptions(); err != nil {
+			return fmt.Errorf("server %q: %w", server.Name, err)
+		}
+		if seen[server.Name] {
+			return fmt.Errorf("duplicate server name %q", server.Name)
+		}
+		seen[server.Name] = true
+	}
+	if err := internal.ValidateDurations(map[string]string{
+		"timeout_client":          b.TimeoutClient,
+		"timeout_http_keep_alive": b.TimeoutHTTPKeepAlive,
+		"timeout_http_request":    b.TimeoutHTTPRequest,
+		"timeout_queue":           b.TimeoutQueue,
+		"timeout_server":          b.TimeoutServer,
+		"timeout_server_fin":      b.TimeoutServerFin,
+		"timeout_connect":         b.TimeoutConnect,
+		"timeout_check":           b.TimeoutCheck,
+		"timeout_tunnel":          b.TimeoutTunnel,
+	}); err != nil {
+		return err
+	}
+	if err := b.validateOptions(); err != nil {
+		return err
+	}
+	if b.Cookie != nil {
+		if err := b.Cookie.validate(); err != nil {
+			return err
+		}
+	}
+	if err := httperrors.ValidateErrorFiles(b.ErrorFiles); err != nil {
+		return err
+	}
+	for _, e := range b.ErrorFilesFromHTTPErrors {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+	}
+	if b.StickTable != nil {
+		if err := b.StickTable.validate(); err != nil {
+			return err
+		}
+	}
+	for _, rule := range b.StickRules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	for _, rule := range b.ServerSwitchingRules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+		if len(b.Servers) > 0 && !slices.ContainsFunc(b.Servers, func(s servers.ServerConfig) bool { return s.Name == rule.Server }) {
+			return fmt.Errorf("%q: server %q is not listed in servers", rule.String(), rule.Server)
+		}
+	}
+	if err := filters.ValidateAll(b.Filters); err != nil {
+		return err
+	}
+	if err := logtargets.ValidateAll(b.LogTargets); err != nil {
+		return err
+	}
+	if len(b.HTTPChecks) > 0 && b.AdvCheck != advCheckHTTPChk {
+		return fmt.Errorf("http_checks need adv_check: %s", advCheckHTTPChk)
+	}
+	for _, check := range b.HTTPChecks {
+		if err := check.validate(); err != nil {
+			return err
+		}
+	}
+	if len(b.TCPChecks) > 0 && b.AdvCheck != advCheckTCPCheck {
+		return fmt.Errorf("tcp_checks need adv_check: %s", advCheckTCPCheck)
+	}
+	for _, check := range b.TCPChecks {
+		if err := check.validate(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -54,3 +54,39 @@ func TestBackendWithServersToPayload_TimeoutsAndFlags(t *testing.T) {
 		t.Fatalf("Redispatch.Enabled = %q, want %q", payload.Redispatch.Enabled, stateEnabled)
 	}
 }
+
+func TestParseCookieAndOptions(t *testing.T) {
+	t.Parallel()
+
+	c, err := ParseCookie("SRV insert indirect nocache")
+	if err != nil || c.Name != "SRV" || c.Type != "insert" || !c.Indirect || !c.Nocache || c.Secure {
+		t.Fatalf("ParseCookie = %+v, %v", c, err)
+	}
+	if _, err := ParseCookie("SRV insert sticky"); err == nil {
+		t.Error("expected error for an unknown cookie keyword")
+	}
+
+	var cfg backendConfig
+	if err := cfg.setOption("prefer-last-server"); err != nil || cfg.PreferLastServer != stateEnabled {
+		t.Fatalf("setOption: %v, prefer_last_server = %q", err, cfg.PreferLastServer)
+	}
+	if err := cfg.setOption("no-abortonclose"); err != nil || cfg.Abortonclose != stateDisabled {
+		t.Fatalf("setOption: %v, abortonclose = %q", err, cfg.Abortonclose)
+	}
+	if err := cfg.setOption("tcp-smart-connect"); err == nil {
+		t.Error("expected error for an unknown option")
+	}
+}
+
+func TestParseServersFromFlags_Options(t *testing.T) {
+	t.Parallel()
+
+	got := parseServersFromFlags([]string{"name=s1,address=10.0.0.1,port=80,check=true,inter=2s,fall=3,backup=true,send-proxy=true"})
+	if len(got) != 1 {
+		t.Fatalf("servers = %+v", got)
+	}
+	s := got[0]
+	if !s.Check || s.Inter != "2s" || s.Fall != 3 || !s.Backup || !s.SendProxy {
+		t.Fatalf("server options = %+v", s)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/backup"
+
+	"github.com/spf13/cobra"
+)
+
+// backupCmd represents the "backup" command.
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up and restore the HAProxy configuration and SSL storage",
+	Long: `Snapshot the raw HAProxy configuration and SSL certificate storage into a
+tarball, and push such a snapshot back.
+
+Examples:
+  haproxyctl backup create haproxy-backup.tar.gz --ssl-dir /etc/haproxy/ssl
+  haproxyctl backup restore haproxy-backup.tar.gz`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+
+	backupCmd.AddCommand(backup.CreateBackupCmd)
+	backupCmd.AddCommand(backup.RestoreBackupCmd)
+}
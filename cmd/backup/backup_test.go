@@ -0,0 +1,144 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testConfig = "global\n  daemon\n\nfrontend web\n  bind :443 ssl crt /etc/haproxy/ssl/site.pem\n"
+
+func TestBackup_CreateRestoreRoundTrip(t *testing.T) {
+	srv := testserver.New(t)
+	srv.SetRawConfig(testConfig)
+	srv.AddCertificate(map[string]interface{}{"storage_name": "site.pem", "file": "/etc/haproxy/ssl/site.pem"})
+
+	sslDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sslDir, "site.pem"), []byte("PEM-DATA\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "backup.tar.gz")
+	ctx := context.Background()
+
+	internal.CaptureStdout(t, func() {
+		if err := CreateBackup(ctx, file, sslDir); err != nil {
+			t.Fatalf("CreateBackup failed: %v", err)
+		}
+	})
+
+	a, err := readArchive(file)
+	if err != nil {
+		t.Fatalf("readArchive failed: %v", err)
+	}
+	if a.Meta.Version != srv.Version() || string(a.Config) != testConfig {
+		t.Fatalf("unexpected archive: version %d, config %q", a.Meta.Version, a.Config)
+	}
+	if string(a.Certificates["site.pem"]) != "PEM-DATA\n" {
+		t.Fatalf("certificate contents not captured: %+v", a.Meta.Certificates)
+	}
+
+	// Restoring onto an unchanged API is a no-op for the configuration.
+	out := internal.CaptureStdout(t, func() {
+		if err := RestoreBackup(ctx, file, false, false); err != nil {
+			t.Fatalf("RestoreBackup failed: %v", err)
+		}
+	})
+	if !strings.Contains(out, "raw unchanged") {
+		t.Fatalf("expected unchanged configuration, got:\n%s", out)
+	}
+
+	// After drift, restore refuses to discard the changes without --force,
+	// and then pushes the backed-up configuration back.
+	if _, err := internal.SendRawRequestWithContext(ctx, "POST", internal.RawConfigPath,
+		map[string]string{"version": strconv.Itoa(srv.Version())}, []byte("global\n"), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	version := srv.Version()
+	internal.CaptureStdout(t, func() {
+		err := RestoreBackup(ctx, file, false, false)
+		if err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Fatalf("RestoreBackup after drift = %v, want a refusal", err)
+		}
+	})
+	if srv.RawConfig() != "global\n" {
+		t.Fatalf("configuration restored without --force: %q", srv.RawConfig())
+	}
+	internal.CaptureStdout(t, func() {
+		if err := RestoreBackup(ctx, file, false, true); err != nil {
+			t.Fatalf("RestoreBackup failed: %v", err)
+		}
+	})
+	if srv.RawConfig() != testConfig || srv.Version() != version+1 {
+		t.Fatalf("configuration not restored: version %d, config %q", srv.Version(), srv.RawConfig())
+	}
+	if data, _ := srv.CertificateFile("site.pem"); string(data) != "PEM-DATA\n" {
+		t.Fatalf("certificate not restored, got %q", data)
+	}
+}
+
+func TestBackup_RestoreUploadsMissingCertificates(t *testing.T) {
+	srv := testserver.New(t)
+	srv.SetRawConfig("global\n")
+
+	file := filepath.Join(t.TempDir(), "backup.tar.gz")
+	a := archive{
+		Meta: metadata{
+			Version:      7,
+			Certificates: []certificateInfo{{StorageName: "site.pem", Included: true}, {StorageName: "skipped.pem"}},
+		},
+		Config:       []byte(testConfig),
+		Certificates: map[string][]byte{"site.pem": []byte("PEM-DATA\n")},
+	}
+	if err := writeArchive(file, a); err != nil {
+		t.Fatalf("writeArchive failed: %v", err)
+	}
+
+	out := internal.CaptureStdout(t, func() {
+		if err := RestoreBackup(context.Background(), file, true, false); err != nil {
+			t.Fatalf("dry-run RestoreBackup failed: %v", err)
+		}
+	})
+	if srv.RawConfig() != "global\n" || !strings.Contains(out, "site.pem created") {
+		t.Fatalf("dry-run changed state or missed certificate:\n%s", out)
+	}
+
+	internal.CaptureStdout(t, func() {
+		if err := RestoreBackup(context.Background(), file, false, true); err != nil {
+			t.Fatalf("RestoreBackup failed: %v", err)
+		}
+	})
+	if data, ok := srv.CertificateFile("site.pem"); !ok || string(data) != "PEM-DATA\n" {
+		t.Fatalf("certificate not uploaded, got %q", data)
+	}
+	if _, ok := srv.CertificateFile("skipped.pem"); ok {
+		t.Fatalf("certificate without contents should be skipped")
+	}
+	if srv.RawConfig() != testConfig {
+		t.Fatalf("configuration not restored: %q", srv.RawConfig())
+	}
+}
+
+func TestReadArchive_RejectsUnsafeStorageNames(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"../haproxy.cfg", "ssl/site.pem", ".."} {
+		file := filepath.Join(t.TempDir(), "backup.tar.gz")
+		a := archive{
+			Meta:         metadata{Version: 1, Certificates: []certificateInfo{{StorageName: name, Included: true}}},
+			Config:       []byte(testConfig),
+			Certificates: map[string][]byte{name: []byte("PEM-DATA\n")},
+		}
+		if err := writeArchive(file, a); err != nil {
+			t.Fatalf("writeArchive failed: %v", err)
+		}
+		if _, err := readArchive(file); err == nil || !strings.Contains(err.Error(), "invalid certificate storage name") {
+			t.Fatalf("readArchive with certificate %q = %v, want an invalid name error", name, err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup provides commands to snapshot and restore the raw HAProxy
+// configuration together with SSL certificate storage.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateBackupCmd represents "backup create <file>".
+var CreateBackupCmd = &cobra.Command{
+	Use:   "create <file>",
+	Short: "Snapshot the raw configuration and SSL storage into a tarball",
+	Long: `Write a gzip-compressed tarball containing the raw HAProxy configuration,
+the configuration version it was taken at, and the SSL certificate storage.
+
+The Data Plane API only exposes certificate metadata, so the PEM contents are
+read from --ssl-dir (the HAProxy host's SSL storage directory, or a copy of
+it). Without --ssl-dir only the certificate list is recorded and restore
+skips those certificates.
+
+Examples:
+  haproxyctl backup create haproxy-backup.tar.gz
+  haproxyctl backup create haproxy-backup.tar.gz --ssl-dir /etc/haproxy/ssl`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		sslDir := internal.GetFlagString(cmd, "ssl-dir")
+		if err := CreateBackup(cmd.Context(), args[0], sslDir); err != nil {
+			internal.Fatalf("Failed to create backup: %v", err)
+		}
+	},
+}
+
+func init() {
+	CreateBackupCmd.Flags().String("ssl-dir", "", "Directory holding the PEM files of the SSL certificate storage")
+}
+
+// CreateBackup snapshots the raw configuration and SSL storage into file.
+// The configuration version is read before and after fetching the raw
+// configuration so the recorded version matches the captured contents.
+func CreateBackup(ctx context.Context, file, sslDir string) error {
+	cfg, err := internal.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	version, err := internal.GetConfigurationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to fetch configuration version: %w", err)
+	}
+	raw, err := internal.SendRequestWithContext(ctx, "GET", internal.RawConfigPath, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch raw configuration: %w", err)
+	}
+	after, err := internal.GetConfigurationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to fetch configuration version: %w", err)
+	}
+	if after != version {
+		return fmt.Errorf("configuration changed while it was being read (version %d -> %d); try again", version, after)
+	}
+
+	a := archive{
+		Meta: metadata{
+			CreatedAt:  time.Now().UTC(),
+			APIBaseURL: cfg.APIBaseURL,
+			Version:    version,
+		},
+		Config:       raw,
+		Certificates: make(map[string][]byte),
+	}
+
+	certs, err := listCertificates(ctx)
+	if err != nil {
+		return err
+	}
+	for _, cert := range certs {
+		if sslDir != "" {
+			data, err := os.ReadFile(filepath.Join(sslDir, cert.StorageName)) //nolint:gosec // CLI intentionally reads from user-specified directory
+			if err != nil {
+				return fmt.Errorf("failed to read certificate %q: %w", cert.StorageName, err)
+			}
+			a.Certificates[cert.StorageName] = data
+			cert.Included = true
+		}
+		a.Meta.Certificates = append(a.Meta.Certificates, cert)
+	}
+	if sslDir == "" && len(certs) > 0 {
+		internal.Warnf("--ssl-dir not set; recording %d certificate(s) without their contents", len(certs))
+	}
+
+	if err := writeArchive(file, a); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "Backup of configuration version %d written to %s (%d certificate(s))\n",
+		version, file, len(a.Certificates))
+	return nil
+}
+
+// listCertificates returns the ssl_certificates storage entries.
+func listCertificates(ctx context.Context) ([]certificateInfo, error) {
+	data, err := internal.SendRequestWithContext(ctx, "GET", certificatesPath, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSL certificates: %w", err)
+	}
+	var certs []certificateInfo
+	if err := json.Unmarshal(data, &certs); err != nil {
+		return nil, fmt.Errorf("failed to parse SSL certificate list: %w", err)
+	}
+	return certs, nil
+}
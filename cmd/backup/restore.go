@@ -0,0 +1,169 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup provides commands to snapshot and restore the raw HAProxy
+// configuration together with SSL certificate storage.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// RestoreBackupCmd represents "backup restore <file>".
+var RestoreBackupCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Push a backup tarball back to HAProxy",
+	Long: `Restore a tarball written by "haproxyctl backup create".
+
+Certificates included in the backup are uploaded first (replacing existing
+storage entries of the same name), so the configuration can reference them.
+The raw configuration is then pushed against the current configuration
+version. Parts that already match the backup are left unchanged. When the
+configuration has changed since the backup was taken (its version differs
+from the one recorded in the archive), nothing is restored unless --force
+is set, as the restore would discard those changes.
+
+Examples:
+  haproxyctl backup restore haproxy-backup.tar.gz
+  haproxyctl backup restore haproxy-backup.tar.gz --dry-run
+  haproxyctl backup restore haproxy-backup.tar.gz --force`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun := internal.GetFlagBool(cmd, "dry-run")
+		force := internal.GetFlagBool(cmd, "force")
+		if err := RestoreBackup(cmd.Context(), args[0], dryRun, force); err != nil {
+			internal.Fatalf("Failed to restore backup: %v", err)
+		}
+	},
+}
+
+func init() {
+	RestoreBackupCmd.Flags().Bool("dry-run", false, "Show what would be restored without changing anything")
+	RestoreBackupCmd.Flags().Bool("force", false, "Restore even when the configuration changed since the backup was taken")
+}
+
+// RestoreBackup pushes the certificates and raw configuration stored in
+// file back through the storage and raw configuration endpoints. Unless
+// force is set, it refuses to replace a configuration that changed since
+// the backup was taken.
+func RestoreBackup(ctx context.Context, file string, dryRun, force bool) error {
+	a, err := readArchive(file)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "Restoring backup of configuration version %d taken %s\n",
+		a.Meta.Version, a.Meta.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+
+	version, err := internal.GetConfigurationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to fetch configuration version: %w", err)
+	}
+	current, err := internal.SendRequestWithContext(ctx, "GET", internal.RawConfigPath, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch raw configuration: %w", err)
+	}
+	changed := !bytes.Equal(bytes.TrimSpace(current), bytes.TrimSpace(a.Config))
+	if changed && version != a.Meta.Version && !force {
+		if !dryRun {
+			return fmt.Errorf("configuration is at version %d but the backup was taken at version %d; use --force to discard the changes made since",
+				version, a.Meta.Version)
+		}
+		internal.Warnf("configuration is at version %d but the backup was taken at version %d; restoring needs --force", version, a.Meta.Version)
+	}
+
+	existing, err := listCertificates(ctx)
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(existing))
+	for _, cert := range existing {
+		present[cert.StorageName] = true
+	}
+
+	for _, cert := range a.Meta.Certificates {
+		if !cert.Included {
+			internal.Warnf("backup has no contents for certificate %q; skipping", cert.StorageName)
+			continue
+		}
+		if err := restoreCertificate(ctx, cert.StorageName, a.Certificates[cert.StorageName], present[cert.StorageName], dryRun); err != nil {
+			return internal.FormatAPIError("Certificate", cert.StorageName, "restore", err)
+		}
+	}
+
+	if err := restoreConfig(ctx, a.Config, version, changed, dryRun); err != nil {
+		return err
+	}
+
+	if dryRun {
+		internal.PrintDryRun()
+	}
+	return nil
+}
+
+// restoreCertificate replaces an existing storage entry or uploads a new one.
+func restoreCertificate(ctx context.Context, name string, pem []byte, exists, dryRun bool) error {
+	action := internal.ActionCreated
+	if exists {
+		action = internal.ActionConfigured
+	}
+	if !dryRun {
+		var err error
+		if exists {
+			_, err = internal.SendRawRequestWithContext(ctx, "PUT", certificatesPath+"/"+url.PathEscape(name), nil, pem, "text/plain")
+		} else {
+			// The upload endpoint names the entry after the uploaded file.
+			err = internal.UploadSSLCertificateWithContext(ctx, strings.TrimSuffix(name, ".pem"), pem)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	internal.PrintStatus("Certificate", name, action)
+	return nil
+}
+
+// restoreConfig pushes the raw configuration against version, the one it
+// was compared at, unless it is already live.
+func restoreConfig(ctx context.Context, raw []byte, version int, changed, dryRun bool) error {
+	if !changed {
+		internal.PrintStatus("Configuration", "raw", internal.ActionUnchanged)
+		return nil
+	}
+
+	if !dryRun {
+		// Sent once: after a conflict the backup would silently replace
+		// changes made since version was read.
+		params := map[string]string{"version": strconv.Itoa(version)}
+		if _, err := internal.SendRawRequestOnce(ctx, "POST", internal.RawConfigPath, params, raw, "text/plain"); err != nil {
+			if internal.IsVersionConflictError(err) {
+				return fmt.Errorf("configuration changed since version %d was read; try again", version)
+			}
+			return fmt.Errorf("failed to push raw configuration: %w", err)
+		}
+	}
+	internal.PrintStatus("Configuration", "raw", internal.ActionConfigured)
+	return nil
+}
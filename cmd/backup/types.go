@@ -0,0 +1,197 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup provides commands to snapshot and restore the raw HAProxy
+// configuration together with SSL certificate storage.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	metadataFile = "backup.json"
+	configFile   = "haproxy.cfg"
+	sslDir       = "ssl"
+
+	certificatesPath = "/services/haproxy/storage/ssl_certificates"
+
+	archiveFileMode = 0o600
+)
+
+// metadata describes a backup archive. It is stored as backup.json next to
+// the configuration and certificate files.
+type metadata struct {
+	CreatedAt    time.Time         `json:"created_at"`   //nolint:tagliatelle // backup archive format
+	APIBaseURL   string            `json:"api_base_url"` //nolint:tagliatelle // backup archive format
+	Version      int               `json:"version"`
+	Certificates []certificateInfo `json:"certificates"`
+}
+
+// certificateInfo records one ssl_certificates storage entry. Included is
+// false when only the metadata, not the PEM contents, could be captured.
+type certificateInfo struct {
+	StorageName string `json:"storage_name"` //nolint:tagliatelle // Data Plane API field name
+	File        string `json:"file,omitempty"`
+	Included    bool   `json:"included"`
+}
+
+// archive is the in-memory form of a backup tarball.
+type archive struct {
+	Meta         metadata
+	Config       []byte
+	Certificates map[string][]byte
+}
+
+// certificateEntry returns the tar path of a certificate's PEM contents.
+func certificateEntry(name string) string {
+	return path.Join(sslDir, name)
+}
+
+// writeArchive writes a as a gzip-compressed tarball to file.
+func writeArchive(file string, a archive) (err error) {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, archiveFileMode) //nolint:gosec // CLI intentionally writes user-specified path
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", file, err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s: %w", file, cerr)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	meta, err := json.MarshalIndent(a.Meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backup metadata: %w", err)
+	}
+	if err := writeEntry(tw, metadataFile, append(meta, '\n'), a.Meta.CreatedAt); err != nil {
+		return err
+	}
+	if err := writeEntry(tw, configFile, a.Config, a.Meta.CreatedAt); err != nil {
+		return err
+	}
+	for _, cert := range a.Meta.Certificates {
+		if !cert.Included {
+			continue
+		}
+		if err := writeEntry(tw, certificateEntry(cert.StorageName), a.Certificates[cert.StorageName], a.Meta.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    archiveFileMode,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+// readArchive reads a backup tarball written by writeArchive.
+func readArchive(file string) (archive, error) {
+	a := archive{Certificates: make(map[string][]byte)}
+
+	f, err := os.Open(file) //nolint:gosec // CLI intentionally reads user-specified path
+	if err != nil {
+		return a, fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return a, fmt.Errorf("%s is not a backup archive: %w", file, err)
+	}
+	tr := tar.NewReader(gz)
+
+	var haveMeta, haveConfig bool
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return a, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return a, fmt.Errorf("failed to read %s from %s: %w", hdr.Name, file, err)
+		}
+
+		switch {
+		case hdr.Name == metadataFile:
+			if err := json.Unmarshal(data, &a.Meta); err != nil {
+				return a, fmt.Errorf("invalid %s in %s: %w", metadataFile, file, err)
+			}
+			haveMeta = true
+		case hdr.Name == configFile:
+			a.Config = data
+			haveConfig = true
+		case strings.HasPrefix(hdr.Name, sslDir+"/"):
+			a.Certificates[strings.TrimPrefix(hdr.Name, sslDir+"/")] = data
+		}
+	}
+
+	if !haveMeta || !haveConfig {
+		return a, fmt.Errorf("%s is missing %s or %s", file, metadataFile, configFile)
+	}
+	for _, cert := range a.Meta.Certificates {
+		if err := validateStorageName(cert.StorageName); err != nil {
+			return a, fmt.Errorf("%s: %w", file, err)
+		}
+		if _, ok := a.Certificates[cert.StorageName]; cert.Included && !ok {
+			return a, fmt.Errorf("%s lists certificate %q but does not contain it", file, cert.StorageName)
+		}
+	}
+	return a, nil
+}
+
+// validateStorageName rejects certificate names that are not a single
+// storage entry, such as "../haproxy.cfg" or "ssl/site.pem".
+func validateStorageName(name string) error {
+	if name == "" || strings.Contains(name, "/") || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid certificate storage name %q", name)
+	}
+	return nil
+}
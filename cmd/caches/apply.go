@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package caches
+
+import (
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ApplyCacheFromYAML applies a Cache manifest in a declarative way: the
+// section is created when missing, otherwise replaced in place.
+func ApplyCacheFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest CacheManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse cache manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid cache configuration: %w", err)
+	}
+	manifest.normalize()
+
+	if outputFormat != "" || dryRun {
+		if outputFormat == "" {
+			internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		} else {
+			internal.FormatOutput(manifest.toAPIPayload(), outputFormat)
+		}
+		if dryRun {
+			internal.PrintDryRun()
+		}
+		return nil
+	}
+
+	name := manifest.Name
+	current, err := getCacheManifest(name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return fmt.Errorf("failed to check cache existence: %w", err)
+	}
+	exists := err == nil
+
+	if exists && *current == manifest {
+		internal.PrintStatus(cacheKind, name, internal.ActionUnchanged)
+		return nil
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if _, err := internal.SendRequest("PUT", cachePath(name), params, manifest.toAPIPayload()); err != nil {
+			return fmt.Errorf("failed to update cache %q: %w", name, err)
+		}
+		internal.PrintStatus(cacheKind, name, internal.ActionConfigured)
+		return nil
+	}
+	if _, err := internal.SendRequest("POST", cachesPath, params, manifest.toAPIPayload()); err != nil {
+		return fmt.Errorf("failed to create cache %q: %w", name, err)
+	}
+	internal.PrintStatus(cacheKind, name, internal.ActionCreated)
+	return nil
+}
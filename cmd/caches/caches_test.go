@@ -0,0 +1,133 @@
+package caches
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testCacheManifest = `apiVersion: haproxyctl/v1
+kind: Cache
+name: static
+total_max_size: 64
+max_age: 10m
+max_object_size: 1048576
+`
+
+func TestApplyCacheFromYAML(t *testing.T) {
+	srv := testserver.New(t)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyCacheFromYAML([]byte(testCacheManifest), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "cache/static created") {
+		t.Fatalf("expected created status, got:\n%s", output)
+	}
+	section, ok := srv.Cache("static")
+	if !ok || section["max_age"] != float64(600) || section["total_max_size"] != float64(64) {
+		t.Fatalf("cache not sent in API form: %+v", section)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyCacheFromYAML([]byte(testCacheManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "cache/static unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	changed := strings.Replace(testCacheManifest, "max_age: 10m", "max_age: 1h", 1)
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyCacheFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "cache/static configured") {
+		t.Fatalf("expected configured status, got:\n%s", output)
+	}
+	if section, _ := srv.Cache("static"); section["max_age"] != float64(3600) {
+		t.Fatalf("max_age = %v, want 3600", section["max_age"])
+	}
+}
+
+func TestCacheManifestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		manifest CacheManifest
+		wantErr  string
+	}{
+		{"valid", CacheManifest{Name: "static", TotalMaxSize: 4, MaxAge: "60", MaxObjectSize: 1024}, ""},
+		{"missing name", CacheManifest{TotalMaxSize: 4}, "name"},
+		{"object too large", CacheManifest{Name: "static", TotalMaxSize: 1, MaxObjectSize: 1 << 20}, "max_object_size"},
+		{"fractional age", CacheManifest{Name: "static", MaxAge: "1500ms"}, "max_age"},
+	}
+	for _, tt := range tests {
+		err := tt.manifest.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: error = %v, want mention of %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEnableCache_AddsRulesOnce(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddCache(map[string]interface{}{"name": "static", "total_max_size": 64})
+	srv.AddBackend(map[string]interface{}{"name": "assets", "mode": "http"})
+	srv.AddListItem("backends", "assets", "filters", map[string]interface{}{"type": "compression"})
+
+	output := internal.CaptureStdout(t, func() {
+		if err := EnableCache("static", "backend", "assets", enableCacheOptions{}); err != nil {
+			t.Fatalf("EnableCache failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "backend/assets cache static enabled") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+
+	requestRules := srv.List("backends", "assets", "http_request_rules")
+	if len(requestRules) != 1 || requestRules[0]["type"] != "cache-use" || requestRules[0]["cache_name"] != "static" {
+		t.Fatalf("cache-use rule not added: %+v", requestRules)
+	}
+	responseRules := srv.List("backends", "assets", "http_response_rules")
+	if len(responseRules) != 1 || responseRules[0]["type"] != "cache-store" {
+		t.Fatalf("cache-store rule not added: %+v", responseRules)
+	}
+	filterList := srv.List("backends", "assets", "filters")
+	if len(filterList) != 2 || filterList[1]["type"] != "cache" || filterList[1]["cache_name"] != "static" {
+		t.Fatalf("cache filter not added after compression: %+v", filterList)
+	}
+	if srv.Version() != 2 {
+		t.Fatalf("version = %d, want 2 (single commit)", srv.Version())
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := EnableCache("static", "backend", "assets", enableCacheOptions{}); err != nil {
+			t.Fatalf("second EnableCache failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "cache static unchanged") {
+		t.Fatalf("expected unchanged on second run, got:\n%s", output)
+	}
+}
+
+func TestEnableCache_UnknownCache(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+
+	if err := EnableCache("missing", "frontend", "web", enableCacheOptions{}); err == nil {
+		t.Fatal("expected error for a cache that does not exist")
+	}
+	if rules := srv.List("frontends", "web", "http_request_rules"); len(rules) != 0 {
+		t.Fatalf("rules added despite missing cache: %+v", rules)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package caches provides commands to manage HAProxy cache sections and to
+// turn caching on for a frontend or backend.
+package caches
+
+import (
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// CreateCachesCmd represents "create caches <name>".
+var CreateCachesCmd = &cobra.Command{
+	Use:     "caches <name>",
+	Aliases: []string{"cache"},
+	Short:   "Create a cache section",
+	Long: `Create a cache section for HAProxy's small-object HTTP cache. The cache
+is not used until a frontend or backend stores responses in it and serves
+them from it, which "haproxyctl enable cache" sets up.
+
+Examples:
+  haproxyctl create caches static --total-max-size 64 --max-age 10m
+  haproxyctl create caches api --total-max-size 16 --max-age 60 --max-object-size 65536 --process-vary
+  haproxyctl create -f cache.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifest := CacheManifest{
+			APIVersion:          apiVersionV1,
+			Kind:                cacheKind,
+			Name:                args[0],
+			TotalMaxSize:        internal.GetFlagInt(cmd, "total-max-size"),
+			MaxAge:              internal.GetFlagString(cmd, "max-age"),
+			MaxObjectSize:       internal.GetFlagInt(cmd, "max-object-size"),
+			MaxSecondaryEntries: internal.GetFlagInt(cmd, "max-secondary-entries"),
+			ProcessVary:         internal.GetFlagBool(cmd, "process-vary"),
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := createCache(manifest, dryRun); err != nil {
+			internal.Fatalf("Failed to create cache %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateCachesCmd.Flags().Int("total-max-size", 0, "Size of the cache in megabytes")
+	CreateCachesCmd.Flags().String("max-age", "", "How long an object is served from the cache (e.g., 60s, 10m, or seconds)")
+	CreateCachesCmd.Flags().Int("max-object-size", 0, "Largest object to cache, in bytes (at most half of --total-max-size)")
+	CreateCachesCmd.Flags().Int("max-secondary-entries", 0, "Maximum variants of one object kept when --process-vary is set")
+	CreateCachesCmd.Flags().Bool("process-vary", false, "Cache a variant per Vary header value instead of skipping such responses")
+	CreateCachesCmd.Flags().Bool("dry-run", false, "Print the manifest without creating it")
+}
+
+// CreateCacheFromFile is used for "haproxyctl create -f cache.yaml".
+func CreateCacheFromFile(data []byte) error {
+	var manifest CacheManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse cache manifest: %w", err)
+	}
+	if err := createCache(manifest, internal.IsOffline()); err != nil {
+		return internal.FormatAPIError(cacheKind, manifest.Name, "create", err)
+	}
+	return nil
+}
+
+// createCache creates a cache section.
+func createCache(manifest CacheManifest, dryRun bool) error {
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	manifest.normalize()
+
+	if dryRun {
+		internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", cachesPath, params, manifest.toAPIPayload()); err != nil {
+		return err
+	}
+
+	internal.PrintStatus(cacheKind, manifest.Name, internal.ActionCreated)
+	return nil
+}
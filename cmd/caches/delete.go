@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package caches provides commands to manage HAProxy cache sections and to
+// turn caching on for a frontend or backend.
+package caches
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteCachesCmd represents "delete caches <name>".
+var DeleteCachesCmd = &cobra.Command{
+	Use:     "caches <name>",
+	Aliases: []string{"cache"},
+	Short:   "Delete a cache section",
+	Long: `Delete a cache section. Rules and filters that still use it must be
+removed first, or HAProxy rejects the configuration.
+
+Examples:
+  haproxyctl delete caches static`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := DeleteCacheByName(args[0]); err != nil {
+			internal.Fatalf("Failed to delete cache %q: %v", args[0], err)
+		}
+	},
+}
+
+// DeleteCacheByName deletes a cache section.
+func DeleteCacheByName(name string) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("DELETE", cachePath(name), params, nil); err != nil {
+		return internal.FormatAPIError(cacheKind, name, "delete", err)
+	}
+
+	internal.PrintStatus(cacheKind, name, internal.ActionDeleted)
+	return nil
+}
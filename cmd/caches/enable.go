@@ -0,0 +1,184 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package caches provides commands to manage HAProxy cache sections and to
+// turn caching on for a frontend or backend.
+package caches
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"haproxyctl/cmd/filters"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// EnableCacheCmd represents "enable cache <cache> --parent <type/name>".
+var EnableCacheCmd = &cobra.Command{
+	Use:   "cache <cache_name>",
+	Short: "Serve and store responses of a frontend or backend in a cache",
+	Long: `Turn on caching for a frontend or backend by appending the
+"http-request cache-use <cache>" and "http-response cache-store <cache>"
+rules. HAProxy needs an explicit "filter cache <cache>" when the section
+declares other filters (for example compression); it is added in that case,
+or always with --filter. Everything is done in one transaction and running
+the command again is a no-op.
+
+With --cond/--cond-test both rules only apply to matching traffic.
+
+Examples:
+  haproxyctl enable cache static --parent backend/assets
+  haproxyctl enable cache static --parent frontend/web --cond if --cond-test "{ path_beg /static }"
+  haproxyctl enable cache api --parent backend/api --filter --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, filters.ParentTypes...)
+		opts := enableCacheOptions{
+			Filter:   internal.GetFlagBool(cmd, "filter"),
+			Cond:     internal.GetFlagString(cmd, "cond"),
+			CondTest: internal.GetFlagString(cmd, "cond-test"),
+			DryRun:   internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline(),
+		}
+		if err := EnableCache(args[0], parentType, parent, opts); err != nil {
+			internal.Fatalf("Failed to enable cache %q on %s/%s: %v", args[0], parentType, parent, err)
+		}
+	},
+}
+
+func init() {
+	EnableCacheCmd.Flags().String("parent", "", "Section to cache: frontend/<name> or backend/<name> (required)")
+	EnableCacheCmd.Flags().Bool("filter", false, "Always declare \"filter cache\", even without other filters")
+	EnableCacheCmd.Flags().String("cond", "", "Condition keyword for both rules: if or unless")
+	EnableCacheCmd.Flags().String("cond-test", "", "Condition, e.g. \"{ path_beg /static }\" or an ACL name")
+	EnableCacheCmd.Flags().Bool("dry-run", false, "Print the rules (and filter) without creating them")
+}
+
+type enableCacheOptions struct {
+	Filter   bool
+	Cond     string
+	CondTest string
+	DryRun   bool
+}
+
+// cacheRule returns the http-request cache-use or http-response cache-store
+// rule payload for a cache.
+func cacheRule(action, cacheName string, opts enableCacheOptions) map[string]interface{} {
+	rule := map[string]interface{}{"type": action, "cache_name": cacheName}
+	if opts.CondTest != "" {
+		rule["cond"] = opts.Cond
+		rule["cond_test"] = opts.CondTest
+	}
+	return rule
+}
+
+// hasCacheRule reports whether an API rule list already uses the cache
+// with the given action.
+func hasCacheRule(rules []map[string]interface{}, action, cacheName string) bool {
+	for _, r := range rules {
+		if r["type"] == action && r["cache_name"] == cacheName {
+			return true
+		}
+	}
+	return false
+}
+
+// EnableCache adds the cache-use and cache-store rules, and the cache
+// filter when needed, to a frontend or backend in a single transaction.
+func EnableCache(cacheName, parentType, parent string, opts enableCacheOptions) error {
+	if (opts.Cond == "") != (opts.CondTest == "") {
+		return fmt.Errorf("--cond and --cond-test go together")
+	}
+	if opts.Cond != "" && opts.Cond != "if" && opts.Cond != "unless" {
+		return fmt.Errorf("invalid --cond %q (expected if or unless)", opts.Cond)
+	}
+
+	useRule := cacheRule("cache-use", cacheName, opts)
+	storeRule := cacheRule("cache-store", cacheName, opts)
+	cacheFilter := filters.Filter{Type: "cache", CacheName: cacheName}
+
+	if opts.DryRun {
+		preview := map[string]interface{}{"http_request_rule": useRule, "http_response_rule": storeRule}
+		if opts.Filter {
+			preview["filter"] = cacheFilter
+		}
+		internal.FormatOutput(preview, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	if _, err := internal.GetResource(cachePath(cacheName)); err != nil {
+		return internal.FormatAPIError(cacheKind, cacheName, "get", err)
+	}
+
+	base := fmt.Sprintf("/services/haproxy/configuration/%ss/%s", parentType, parent)
+	requestRules, err := internal.GetResourceList(base + "/http_request_rules")
+	if err != nil {
+		return internal.FormatAPIError(parentType, parent, "get", err)
+	}
+	responseRules, err := internal.GetResourceList(base + "/http_response_rules")
+	if err != nil {
+		return fmt.Errorf("failed to fetch http-response rules: %w", err)
+	}
+	currentFilters, err := filters.Live(parentType, parent)
+	if err != nil {
+		return fmt.Errorf("failed to fetch filters: %w", err)
+	}
+
+	needUse := !hasCacheRule(requestRules, "cache-use", cacheName)
+	needStore := !hasCacheRule(responseRules, "cache-store", cacheName)
+	needFilter := opts.Filter || len(currentFilters) > 0
+	for _, f := range currentFilters {
+		if f == cacheFilter {
+			needFilter = false
+		}
+	}
+
+	displayKind := strings.ToUpper(parentType[:1]) + parentType[1:]
+	if !needUse && !needStore && !needFilter {
+		internal.PrintStatus(displayKind, parent, "cache "+cacheName+" "+internal.ActionUnchanged)
+		return nil
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		if needFilter {
+			if err := filters.ApplyDiff(tx, parentType, parent, currentFilters, append(currentFilters, cacheFilter)); err != nil {
+				return fmt.Errorf("failed to create %q: %w", cacheFilter.String(), err)
+			}
+		}
+		if needUse {
+			path := base + "/http_request_rules/" + strconv.Itoa(len(requestRules))
+			if _, err := internal.SendRequest("POST", path, tx.Params(), useRule); err != nil {
+				return fmt.Errorf("failed to create cache-use rule: %w", err)
+			}
+		}
+		if needStore {
+			path := base + "/http_response_rules/" + strconv.Itoa(len(responseRules))
+			if _, err := internal.SendRequest("POST", path, tx.Params(), storeRule); err != nil {
+				return fmt.Errorf("failed to create cache-store rule: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus(displayKind, parent, "cache "+cacheName+" enabled")
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package caches provides commands to manage HAProxy cache sections and to
+// turn caching on for a frontend or backend.
+package caches
+
+import (
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetCachesCmd represents "get caches".
+var GetCachesCmd = &cobra.Command{
+	Use:     "caches [name]",
+	Aliases: []string{"cache"},
+	Short:   "List HAProxy cache sections or fetch details of one",
+	Long: `List the cache sections of the small-object HTTP cache, or show one.
+With -o yaml the output is a Cache manifest that apply accepts.
+
+Examples:
+  haproxyctl get caches
+  haproxyctl get caches static -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFormat := internal.GetFlagString(cmd, "output")
+
+		if len(args) == 0 {
+			manifests, err := CacheManifests()
+			if err != nil {
+				internal.Fatalf("Failed to fetch caches: %v", err)
+			}
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(manifests, cacheColumns), outputFormat)
+			return
+		}
+
+		manifest, err := getCacheManifest(args[0])
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(cacheKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch cache %q: %v", args[0], err)
+		}
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(manifest, cacheColumns), outputFormat)
+	},
+}
+
+// cacheColumns shows a cache with its size limits and expiry.
+var cacheColumns = internal.ColumnSet{
+	Kind:    cacheKind,
+	Default: []string{"name", "total_max_size", "max_age", "max_object_size"},
+	Wide:    []string{"max_secondary_entries", "process_vary"},
+}
+
+// getCacheManifest fetches a cache section.
+func getCacheManifest(name string) (*CacheManifest, error) {
+	obj, err := internal.GetResource(cachePath(name))
+	if err != nil {
+		return nil, err
+	}
+	return manifestFromAPI(obj), nil
+}
+
+// CacheManifests returns every cache section as a manifest that apply
+// accepts, sorted by name.
+func CacheManifests() ([]interface{}, error) {
+	list, err := internal.GetResourceList(cachesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch caches: %w", err)
+	}
+	internal.SortByStringField(list, "name")
+
+	manifests := make([]interface{}, 0, len(list))
+	for _, raw := range list {
+		manifests = append(manifests, manifestFromAPI(raw))
+	}
+	return manifests, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package caches provides commands to manage HAProxy cache sections and to
+// turn caching on for a frontend or backend.
+package caches
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	apiVersionV1 = "haproxyctl/v1"
+	cacheKind    = "Cache"
+
+	cachesPath = "/services/haproxy/configuration/caches"
+
+	millisPerSecond = 1000
+)
+
+// CacheManifest is the manifest view of a "cache <name>" section. max_age is
+// written like the backend timeouts (e.g. 60s, 10m) or, as in haproxy.cfg,
+// as a bare number of seconds; it is sent to the API in seconds.
+//
+//nolint:tagliatelle // manifest uses HAProxy's snake_case names like backendConfig
+type CacheManifest struct {
+	APIVersion          string `json:"apiVersion" yaml:"apiVersion"`
+	Kind                string `json:"kind" yaml:"kind"`
+	Name                string `json:"name" yaml:"name"`
+	TotalMaxSize        int    `json:"total_max_size,omitempty" yaml:"total_max_size,omitempty"`
+	MaxAge              string `json:"max_age,omitempty" yaml:"max_age,omitempty"`
+	MaxObjectSize       int    `json:"max_object_size,omitempty" yaml:"max_object_size,omitempty"`
+	MaxSecondaryEntries int    `json:"max_secondary_entries,omitempty" yaml:"max_secondary_entries,omitempty"`
+	ProcessVary         bool   `json:"process_vary,omitempty" yaml:"process_vary,omitempty"`
+}
+
+// Validate checks the manifest before anything is sent to the API.
+func (m *CacheManifest) Validate() error {
+	if m.Name == "" {
+		return errors.New("cache name is required")
+	}
+	if m.Kind != "" && m.Kind != cacheKind {
+		return fmt.Errorf("kind must be %q", cacheKind)
+	}
+	if m.APIVersion != "" && m.APIVersion != apiVersionV1 {
+		return fmt.Errorf("apiVersion must be %q", apiVersionV1)
+	}
+	if m.TotalMaxSize < 0 || m.MaxObjectSize < 0 || m.MaxSecondaryEntries < 0 {
+		return errors.New("total_max_size, max_object_size and max_secondary_entries must not be negative")
+	}
+	// haproxy.cfg rejects a max-object-size above half of total-max-size.
+	if m.TotalMaxSize > 0 && m.MaxObjectSize > m.TotalMaxSize*1024*1024/2 {
+		return fmt.Errorf("max_object_size %d exceeds half of total_max_size (%d MB)", m.MaxObjectSize, m.TotalMaxSize)
+	}
+	if seconds, err := parseMaxAge(m.MaxAge); err != nil || seconds < 0 {
+		return fmt.Errorf("invalid max_age %q: must be a non-negative duration", m.MaxAge)
+	}
+	return nil
+}
+
+// parseMaxAge returns max_age in seconds. Unlike the timeouts, a bare number
+// means seconds, which is the unit HAProxy uses for it.
+func parseMaxAge(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	ms, err := internal.ParseDurationToMillis(s)
+	if err != nil {
+		return 0, err
+	}
+	if ms%millisPerSecond != 0 {
+		return 0, fmt.Errorf("%q is not a whole number of seconds", s)
+	}
+	return ms / millisPerSecond, nil
+}
+
+// toAPIPayload converts the manifest into the object expected by the caches
+// endpoint. It must be called on a validated manifest.
+func (m *CacheManifest) toAPIPayload() map[string]interface{} {
+	payload := map[string]interface{}{"name": m.Name}
+	if m.TotalMaxSize > 0 {
+		payload["total_max_size"] = m.TotalMaxSize
+	}
+	if seconds, _ := parseMaxAge(m.MaxAge); seconds > 0 {
+		payload["max_age"] = seconds
+	}
+	if m.MaxObjectSize > 0 {
+		payload["max_object_size"] = m.MaxObjectSize
+	}
+	if m.MaxSecondaryEntries > 0 {
+		payload["max_secondary_entries"] = m.MaxSecondaryEntries
+	}
+	if m.ProcessVary {
+		payload["process_vary"] = true
+	}
+	return payload
+}
+
+// normalize fills in kind and apiVersion and renders max_age the way it
+// comes back from the API, so manifests from files and from the API compare
+// equal.
+func (m *CacheManifest) normalize() {
+	m.APIVersion, m.Kind = apiVersionV1, cacheKind
+	if seconds, err := parseMaxAge(m.MaxAge); err == nil {
+		m.MaxAge = internal.FormatMillisAsDuration(seconds * millisPerSecond)
+	}
+}
+
+// manifestFromAPI converts an API cache object into a manifest.
+func manifestFromAPI(obj map[string]interface{}) *CacheManifest {
+	m := &CacheManifest{}
+	m.Name, _ = obj["name"].(string)
+	m.TotalMaxSize, _ = internal.GetIntField(obj, "total_max_size")
+	if seconds, ok := internal.GetIntField(obj, "max_age"); ok {
+		m.MaxAge = internal.FormatMillisAsDuration(seconds * millisPerSecond)
+	}
+	m.MaxObjectSize, _ = internal.GetIntField(obj, "max_object_size")
+	m.MaxSecondaryEntries, _ = internal.GetIntField(obj, "max_secondary_entries")
+	m.ProcessVary, _ = obj["process_vary"].(bool)
+	m.normalize()
+	return m
+}
+
+func cachePath(name string) string {
+	return cachesPath + "/" + name
+}
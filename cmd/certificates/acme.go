@@ -0,0 +1,213 @@
+// Package certificates provides commands to manage HAProxy SSL certificates.
+package certificates
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+// The ACME (RFC 8555) protocol is spoken by golang.org/x/crypto/acme; this
+// file drives it: register an account, order a certificate for a set of DNS
+// names, answer http-01 or dns-01 challenges through a caller-supplied
+// solver and download the issued chain. Accounts and certificate keys are
+// ECDSA P-256.
+
+const (
+	letsEncryptDirectory        = acme.LetsEncryptURL
+	letsEncryptStagingDirectory = "https://acme-staging-v02.api.letsencrypt.org/directory"
+
+	acmeChallengeHTTP01 = "http-01"
+	acmeChallengeDNS01  = "dns-01"
+
+	// acmeTimeout bounds the wait for authorizations and the order.
+	acmeTimeout = 2 * time.Minute
+	// challengeCleanupTimeout bounds removing the challenges again, which
+	// also happens after ctx is canceled.
+	challengeCleanupTimeout = 30 * time.Second
+)
+
+// acmeChallenge is a challenge the solver has to make answerable.
+type acmeChallenge struct {
+	Type   string
+	Domain string
+	Token  string
+	// KeyAuthorization is what http-01 serves at
+	// /.well-known/acme-challenge/<Token>.
+	KeyAuthorization string
+	// TXTValue is the TXT record value dns-01 expects at
+	// _acme-challenge.<Domain>.
+	TXTValue string
+}
+
+// acmeSolver makes challenges answerable and cleans them up afterwards.
+type acmeSolver interface {
+	Present(ctx context.Context, challenges []acmeChallenge) error
+	CleanUp(ctx context.Context, challenges []acmeChallenge) error
+}
+
+// cleanupContext returns the context challenges are removed with: it is
+// not canceled with ctx, so an interrupted renewal still cleans up, but
+// gives up after challengeCleanupTimeout.
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), challengeCleanupTimeout)
+}
+
+// obtainCertificate runs the whole flow for domains with client and returns
+// the private key and the issued chain, both PEM encoded.
+func obtainCertificate(ctx context.Context, client *acme.Client, domains []string, email, challengeType string, solver acmeSolver) (keyPEM, chainPEM []byte, err error) {
+	account := &acme.Account{}
+	if email != "" {
+		account.Contact = []string{"mailto:" + email}
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return nil, nil, fmt.Errorf("failed to register ACME account: %w", err)
+	}
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(domains...))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	var pending []acmeChallenge
+	var accept []*acme.Challenge
+	for _, authzURL := range order.AuthzURLs {
+		authz, err := client.GetAuthorization(ctx, authzURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch authorization: %w", err)
+		}
+		if authz.Status == acme.StatusValid {
+			continue
+		}
+		var chal *acme.Challenge
+		for _, c := range authz.Challenges {
+			if c.Type == challengeType {
+				chal = c
+				break
+			}
+		}
+		if chal == nil {
+			return nil, nil, fmt.Errorf("no %s challenge offered for %s", challengeType, authz.Identifier.Value)
+		}
+		keyAuth, err := client.HTTP01ChallengeResponse(chal.Token)
+		if err != nil {
+			return nil, nil, err
+		}
+		txt, err := client.DNS01ChallengeRecord(chal.Token)
+		if err != nil {
+			return nil, nil, err
+		}
+		pending = append(pending, acmeChallenge{
+			Type:             chal.Type,
+			Domain:           authz.Identifier.Value,
+			Token:            chal.Token,
+			KeyAuthorization: keyAuth,
+			TXTValue:         txt,
+		})
+		accept = append(accept, chal)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, acmeTimeout)
+	defer cancel()
+
+	if len(pending) > 0 {
+		if err := solver.Present(ctx, pending); err != nil {
+			return nil, nil, fmt.Errorf("failed to present challenges: %w", err)
+		}
+		err := answerChallenges(waitCtx, client, accept, order.AuthzURLs)
+		cleanCtx, cancelClean := cleanupContext(ctx)
+		if cleanErr := solver.CleanUp(cleanCtx, pending); cleanErr != nil && err == nil {
+			err = fmt.Errorf("failed to clean up challenges: %w", cleanErr)
+		}
+		cancelClean()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	order, err = client.WaitOrder(waitCtx, order.URI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("order not ready: %w", err)
+	}
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, certKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CSR: %w", err)
+	}
+	chain, _, err := client.CreateOrderCert(waitCtx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("certificate not issued: %w", err)
+	}
+	for _, der := range chain {
+		chainPEM = append(chainPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(certKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), chainPEM, nil
+}
+
+// answerChallenges tells the CA the challenges are ready and waits for
+// every authorization to become valid.
+func answerChallenges(ctx context.Context, client *acme.Client, challenges []*acme.Challenge, authzURLs []string) error {
+	for _, chal := range challenges {
+		if _, err := client.Accept(ctx, chal); err != nil {
+			return fmt.Errorf("failed to answer challenge: %w", err)
+		}
+	}
+	for _, authzURL := range authzURLs {
+		if _, err := client.WaitAuthorization(ctx, authzURL); err != nil {
+			return fmt.Errorf("authorization failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadOrCreateAccountKey reads a PEM EC account key, creating it (mode
+// 0600) when the file does not exist.
+func loadOrCreateAccountKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path comes from explicit CLI input
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("%s does not contain a PEM key", path)
+		}
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse account key %s: %w", path, err)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to save account key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,438 @@
+package certificates
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+// selfSignedPEM returns a key and self-signed certificate bundle valid from
+// notBefore to notAfter.
+func selfSignedPEM(t *testing.T, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(0x2a),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		DNSNames:     []string{"www.example.com", "example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("192.0.2.10")},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+}
+
+func TestFetchCertificateDetails_ParsesStoredPEM(t *testing.T) {
+	testserver.New(t)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	bundle := selfSignedPEM(t, now.AddDate(0, 0, -10), now.AddDate(0, 0, 30))
+	if err := internal.UploadSSLCertificateWithContext(context.Background(), "site", bundle); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	details, err := fetchCertificateDetails(context.Background(), "site.pem", "")
+	if err != nil {
+		t.Fatalf("fetchCertificateDetails failed: %v", err)
+	}
+	got := details.describe("site.pem", now)
+	if got["subject"] != "CN=www.example.com" || got["serial"] != "2a" || got["chain"] != "1 certificate(s)" {
+		t.Fatalf("details = %+v", got)
+	}
+	if got["subject_alt_names"] != "www.example.com, example.com, 192.0.2.10" {
+		t.Fatalf("subject_alt_names = %v", got["subject_alt_names"])
+	}
+	if got["days_until_expiry"] != 30 || got["status"] != "valid" {
+		t.Fatalf("validity = %v, %v", got["days_until_expiry"], got["status"])
+	}
+
+	if _, err := fetchCertificateDetails(context.Background(), "missing.pem", ""); err == nil {
+		t.Fatal("expected error for an unknown certificate")
+	}
+}
+
+func TestCertificateDetails_ExpiredAndMetadata(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	details := detailsFromMetadata(map[string]interface{}{
+		"file":       "/etc/haproxy/ssl/old.pem",
+		"subject":    "CN=old.example.com",
+		"issuers":    "CN=Example CA",
+		"domains":    "old.example.com,www.old.example.com",
+		"not_before": "2025-01-01T00:00:00Z",
+		"not_after":  "2025-12-30T00:00:00Z",
+	})
+	got := details.describe("old.pem", now)
+	if got["status"] != "expired" || got["days_until_expiry"] != -3 {
+		t.Fatalf("validity = %v, %v", got["status"], got["days_until_expiry"])
+	}
+	if got["subject_alt_names"] != "old.example.com, www.old.example.com" || got["issuer"] != "CN=Example CA" {
+		t.Fatalf("details = %+v", got)
+	}
+	if _, ok := got["chain"]; ok {
+		t.Fatal("chain shown without a parsed PEM")
+	}
+
+	if _, err := parseCertificatePEM([]byte("not a pem")); err == nil || !strings.Contains(err.Error(), "no certificate") {
+		t.Fatalf("parseCertificatePEM error = %v", err)
+	}
+}
+
+func TestAuditCertificates(t *testing.T) {
+	testserver.New(t)
+	now := time.Now()
+	if err := internal.UploadSSLCertificateWithContext(context.Background(), "soon", selfSignedPEM(t, now.AddDate(0, 0, -30), now.AddDate(0, 0, 10))); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	list := []map[string]interface{}{
+		{"storage_name": "later.pem", "not_after": now.AddDate(0, 0, 90).UTC().Format(time.RFC3339)},
+		{"storage_name": "soon.pem"},
+		{"storage_name": "old.pem", "not_after": now.AddDate(0, 0, -1).UTC().Format(time.RFC3339)},
+	}
+	rows, expiring := auditCertificates(context.Background(), list, 30*24*time.Hour, now)
+	if expiring != 2 {
+		t.Fatalf("expiring = %d, want 2", expiring)
+	}
+	var got []string
+	for _, row := range rows {
+		got = append(got, row["storage_name"].(string)+"="+row["status"].(string))
+	}
+	if strings.Join(got, ",") != "old.pem=expired,soon.pem=expiring,later.pem=valid" {
+		t.Fatalf("rows = %v", got)
+	}
+
+	if _, expiring := auditCertificates(context.Background(), list[:1], 30*24*time.Hour, now); expiring != 0 {
+		t.Fatalf("expiring = %d for a certificate valid for 90 days", expiring)
+	}
+}
+
+func TestParseExpiryWindow(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "7": 7 * 24 * time.Hour, "72h": 72 * time.Hour} {
+		if got, err := parseExpiryWindow(in); err != nil || got != want {
+			t.Errorf("parseExpiryWindow(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "soon", "-3d"} {
+		if _, err := parseExpiryWindow(bad); err == nil {
+			t.Errorf("parseExpiryWindow(%q): expected error", bad)
+		}
+	}
+}
+
+// fakeACME is a minimal ACME CA for one http-01 order. It does not verify
+// signatures; when the challenge is answered it checks that the frontend
+// serves it, and it issues whatever the CSR asks for.
+type fakeACME struct {
+	*httptest.Server
+
+	t        *testing.T
+	srv      *testserver.Server
+	frontend string
+	caKey    *ecdsa.PrivateKey
+
+	mu           sync.Mutex
+	authzStatus  string
+	orderStatus  string
+	chain        []byte
+	ruleAnswered bool
+}
+
+func newFakeACME(t *testing.T, srv *testserver.Server, frontend string) *fakeACME {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeACME{t: t, srv: srv, frontend: frontend, caKey: caKey, authzStatus: "pending", orderStatus: "pending"}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeACME) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Replay-Nonce", "nonce")
+
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+	if r.Method == http.MethodPost {
+		_ = json.NewDecoder(r.Body).Decode(&jws)
+	}
+	reply := func(status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	order := func() map[string]interface{} {
+		return map[string]interface{}{
+			"status":         f.orderStatus,
+			"authorizations": []string{f.URL + "/authz/1"},
+			"finalize":       f.URL + "/finalize/1",
+			"certificate":    f.URL + "/cert/1",
+		}
+	}
+
+	switch r.URL.Path {
+	case "/directory":
+		reply(http.StatusOK, map[string]string{
+			"newNonce":   f.URL + "/nonce",
+			"newAccount": f.URL + "/account",
+			"newOrder":   f.URL + "/order",
+		})
+	case "/nonce":
+		w.WriteHeader(http.StatusOK)
+	case "/account":
+		w.Header().Set("Location", f.URL+"/acct/1")
+		reply(http.StatusCreated, map[string]string{"status": "valid"})
+	case "/order":
+		w.Header().Set("Location", f.URL+"/order/1")
+		reply(http.StatusCreated, order())
+	case "/order/1":
+		reply(http.StatusOK, order())
+	case "/authz/1":
+		reply(http.StatusOK, map[string]interface{}{
+			"status":     f.authzStatus,
+			"identifier": map[string]string{"type": "dns", "value": "example.com"},
+			"challenges": []map[string]string{
+				{"type": "dns-01", "url": f.URL + "/chall/2", "token": "dnstok", "status": "pending"},
+				{"type": "http-01", "url": f.URL + "/chall/1", "token": "tok123", "status": "pending"},
+			},
+		})
+	case "/chall/1":
+		rules := f.srv.List("frontends", f.frontend, "http_request_rules")
+		if len(rules) > 0 && rules[0]["cond_test"] == "{ path /.well-known/acme-challenge/tok123 }" &&
+			strings.HasPrefix(rules[0]["return_content"].(string), "tok123.") {
+			f.ruleAnswered = true
+			f.authzStatus = "valid"
+			f.orderStatus = "ready"
+		} else {
+			f.authzStatus = "invalid"
+		}
+		reply(http.StatusOK, map[string]string{"status": "processing"})
+	case "/finalize/1":
+		payload, _ := base64.RawURLEncoding.DecodeString(jws.Payload)
+		var req struct {
+			CSR string `json:"csr"`
+		}
+		_ = json.Unmarshal(payload, &req)
+		der, _ := base64.RawURLEncoding.DecodeString(req.CSR)
+		csr, err := x509.ParseCertificateRequest(der)
+		if err != nil {
+			reply(http.StatusBadRequest, map[string]string{"type": "urn:ietf:params:acme:error:badCSR", "detail": err.Error()})
+			return
+		}
+		ca := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "Fake CA"}, IsCA: true, BasicConstraintsValid: true,
+			NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().AddDate(1, 0, 0), KeyUsage: x509.KeyUsageCertSign}
+		caDER, _ := x509.CreateCertificate(rand.Reader, ca, ca, &f.caKey.PublicKey, f.caKey)
+		leaf := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: csr.Subject, DNSNames: csr.DNSNames,
+			NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().AddDate(0, 0, 90)}
+		leafDER, err := x509.CreateCertificate(rand.Reader, leaf, ca, csr.PublicKey, f.caKey)
+		if err != nil {
+			f.t.Errorf("issuing certificate: %v", err)
+		}
+		f.chain = append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER}),
+			pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})...)
+		f.orderStatus = "valid"
+		reply(http.StatusOK, order())
+	case "/cert/1":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		_, _ = w.Write(f.chain)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestRenewCertificate_HTTP01(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddListItem("frontends", "web", "http_request_rules", map[string]interface{}{"type": "redirect", "redir_type": "scheme", "redir_value": "https"})
+	srv.AddCrtList("sites")
+	acme := newFakeACME(t, srv, "web")
+
+	opts := renewOptions{
+		Domains:      []string{"example.com"},
+		Challenge:    acmeChallengeHTTP01,
+		HTTPFrontend: "web",
+		DirectoryURL: acme.URL + "/directory",
+		AccountKey:   filepath.Join(t.TempDir(), "account.pem"),
+		CrtList:      "sites",
+		RenewBefore:  30 * 24 * time.Hour,
+	}
+	output := internal.CaptureStdout(t, func() {
+		if err := RenewCertificate(context.Background(), "site", opts); err != nil {
+			t.Fatalf("RenewCertificate failed: %v", err)
+		}
+	})
+	if !acme.ruleAnswered {
+		t.Fatal("challenge rule was not in place when the CA validated")
+	}
+	if !strings.Contains(output, "certificate/site.pem created") || !strings.Contains(output, "entry site.pem added") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	rules := srv.List("frontends", "web", "http_request_rules")
+	if len(rules) != 1 || rules[0]["type"] != "redirect" {
+		t.Fatalf("challenge rule not cleaned up: %+v", rules)
+	}
+	stored, ok := srv.CertificateFile("site.pem")
+	if !ok {
+		t.Fatal("certificate not uploaded")
+	}
+	details, err := parseCertificatePEM(stored)
+	if err != nil || len(details.SANs) != 1 || details.SANs[0] != "example.com" || details.Chain != 2 {
+		t.Fatalf("uploaded bundle = %+v, %v", details, err)
+	}
+	if info, err := os.Stat(opts.AccountKey); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("account key not created privately: %v %v", info, err)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := RenewCertificate(context.Background(), "site", opts); err != nil {
+			t.Fatalf("second RenewCertificate failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "certificate/site.pem unchanged") {
+		t.Fatalf("expected unchanged on second run, got:\n%s", output)
+	}
+	entries := srv.CrtListEntries("sites")
+	if len(entries) != 1 || entries[0]["file"] != "/etc/haproxy/ssl/site.pem" {
+		t.Fatalf("crt-list entries = %+v", entries)
+	}
+}
+
+func TestRenewCertificate_SolverCommand(t *testing.T) {
+	testserver.New(t)
+	now := time.Now()
+	if err := internal.UploadSSLCertificateWithContext(context.Background(), "site", selfSignedPEM(t, now.AddDate(0, 0, -80), now.AddDate(0, 0, 5))); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	keyBlock, rest := pem.Decode(selfSignedPEM(t, now, now.AddDate(0, 0, 90)))
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(keyBlock), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, rest, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := renewOptions{
+		Domains:       []string{"www.example.com", "example.com"},
+		SolverCommand: `printf %s "$ACME_DOMAINS" > ` + filepath.Join(dir, "domains"),
+		CertFile:      certFile,
+		KeyFile:       keyFile,
+		RenewBefore:   30 * 24 * time.Hour,
+	}
+	output := internal.CaptureStdout(t, func() {
+		if err := RenewCertificate(context.Background(), "site", opts); err != nil {
+			t.Fatalf("RenewCertificate failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "certificate/site.pem renewed") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "domains")); string(got) != "www.example.com,example.com" {
+		t.Fatalf("ACME_DOMAINS = %q", got)
+	}
+	details, err := fetchCertificateDetails(context.Background(), "site.pem", "")
+	if err != nil || details.NotAfter.Before(now.AddDate(0, 0, 80)) {
+		t.Fatalf("certificate not replaced: %+v, %v", details, err)
+	}
+}
+
+func TestRenewOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    renewOptions
+		wantErr string
+	}{
+		{"http-01", renewOptions{Domains: []string{"a.example"}, Challenge: "http-01", HTTPFrontend: "web"}, ""},
+		{"no domains", renewOptions{Challenge: "http-01", HTTPFrontend: "web"}, "--domain"},
+		{"http-01 without frontend", renewOptions{Domains: []string{"a.example"}, Challenge: "http-01"}, "--http-frontend"},
+		{"wildcard over http", renewOptions{Domains: []string{"*.example.com"}, Challenge: "http-01", HTTPFrontend: "web"}, "dns-01"},
+		{"dns-01 without hook", renewOptions{Domains: []string{"a.example"}, Challenge: "dns-01"}, "--dns-hook"},
+		{"solver without files", renewOptions{Domains: []string{"a.example"}, SolverCommand: "true"}, "--cert"},
+		{"unknown challenge", renewOptions{Domains: []string{"a.example"}, Challenge: "tls-alpn-01"}, "invalid --challenge"},
+	}
+	for _, tt := range tests {
+		err := tt.opts.validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: error = %v, want mention of %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDNSHookSolver_PresentCleansUpOnFailure(t *testing.T) {
+	t.Parallel()
+
+	log := filepath.Join(t.TempDir(), "hook.log")
+	solver := dnsHookSolver{hook: `echo "$ACME_ACTION $ACME_DOMAIN" >> ` + log + `; [ "$ACME_ACTION $ACME_DOMAIN" != "present b.example" ]`}
+	challenges := []acmeChallenge{
+		{Type: acmeChallengeDNS01, Domain: "a.example", TXTValue: "one"},
+		{Type: acmeChallengeDNS01, Domain: "b.example", TXTValue: "two"},
+		{Type: acmeChallengeDNS01, Domain: "c.example", TXTValue: "three"},
+	}
+	if err := solver.Present(context.Background(), challenges); err == nil {
+		t.Fatal("Present: expected the failing hook's error")
+	}
+	got, _ := os.ReadFile(log)
+	if want := "present a.example\npresent b.example\ncleanup a.example\n"; string(got) != want {
+		t.Fatalf("hook runs = %q, want %q", got, want)
+	}
+}
+
+func TestCleanupContext_SurvivesCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cleanCtx, cancelClean := cleanupContext(ctx)
+	defer cancelClean()
+	if err := cleanCtx.Err(); err != nil {
+		t.Fatalf("cleanup context done with its parent: %v", err)
+	}
+	if deadline, ok := cleanCtx.Deadline(); !ok || time.Until(deadline) > challengeCleanupTimeout {
+		t.Fatalf("cleanup context deadline = %v, %v", deadline, ok)
+	}
+}
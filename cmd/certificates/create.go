@@ -44,7 +44,7 @@ Examples:
 		caPath := internal.GetFlagString(cmd, "ca-file")
 
 		outputFormat := internal.GetFlagString(cmd, "output")
-		dryRun := internal.GetFlagBool(cmd, "dry-run")
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
 
 		fullPEM, from, err := buildCertificatePEM(pemPath, certPath, keyPath, caPath)
 		if err != nil {
@@ -3,7 +3,6 @@ package certificates
 
 import (
 	"haproxyctl/internal"
-	"log"
 
 	"github.com/spf13/cobra"
 )
@@ -16,7 +15,7 @@ var DeleteCertificatesCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
 		if err := deleteCertificate(cmd, name); err != nil {
-			log.Fatalf("Failed to delete certificate %q: %v", name, err)
+			internal.Fatalf("Failed to delete certificate %q: %v", name, err)
 		}
 	},
 }
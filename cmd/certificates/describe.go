@@ -0,0 +1,197 @@
+// Package certificates provides commands to manage HAProxy SSL certificates.
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DescribeCertificatesCmd represents "describe certificates <name>".
+var DescribeCertificatesCmd = &cobra.Command{
+	Use:     "certificates <name>",
+	Aliases: []string{"certificate"},
+	Short:   "Show the subject, SANs, issuer and validity of a stored certificate",
+	Long: `Show the subject, subject alternative names, issuer, validity period and
+days until expiry of a certificate in the Data Plane API SSL storage.
+
+When the API serves the stored PEM it is parsed locally with crypto/x509;
+otherwise the metadata the API reports is shown. --from-file parses a local
+copy of the PEM instead (for example from /etc/haproxy/ssl).
+
+Examples:
+  haproxyctl describe certificates site.pem
+  haproxyctl describe certificates site.pem --from-file ./certs/site.pem`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		details, err := fetchCertificateDetails(cmd.Context(), args[0], internal.GetFlagString(cmd, "from-file"))
+		if err != nil {
+			internal.Fatalf("Failed to describe certificate %q: %v", args[0], err)
+		}
+		internal.PrintResourceDescription("Certificate", details.describe(args[0], time.Now()), nil, internal.DescribeOptions{
+			Sections: certificateDescriptionSections(),
+		})
+	},
+}
+
+func init() {
+	DescribeCertificatesCmd.Flags().String("from-file", "", "Parse this local PEM instead of downloading the stored one")
+}
+
+// certificateDescriptionSections puts the validity fields in their own
+// section; the rest are shown under the header.
+func certificateDescriptionSections() map[string]string {
+	return map[string]string{
+		"not_before":        "Validity",
+		"not_after":         "Validity",
+		"days_until_expiry": "Validity",
+		"status":            "Validity",
+	}
+}
+
+// certificateDetails is the parsed metadata of a certificate's leaf.
+type certificateDetails struct {
+	File      string
+	Subject   string
+	Issuer    string
+	SANs      []string
+	Serial    string
+	NotBefore time.Time
+	NotAfter  time.Time
+	// Chain counts the certificates in the PEM, the leaf included; 0 when
+	// only API metadata was available.
+	Chain int
+}
+
+// describe returns the fields PrintResourceDescription shows, with the
+// days until expiry counted from now.
+func (d certificateDetails) describe(name string, now time.Time) map[string]interface{} {
+	out := map[string]interface{}{
+		"name":              name,
+		"file":              d.File,
+		"subject":           d.Subject,
+		"subject_alt_names": strings.Join(d.SANs, ", "),
+		"issuer":            d.Issuer,
+		"serial":            d.Serial,
+	}
+	if d.Chain > 0 {
+		out["chain"] = fmt.Sprintf("%d certificate(s)", d.Chain)
+	}
+	if !d.NotBefore.IsZero() {
+		out["not_before"] = d.NotBefore.UTC().Format(time.RFC3339)
+	}
+	if !d.NotAfter.IsZero() {
+		out["not_after"] = d.NotAfter.UTC().Format(time.RFC3339)
+		out["days_until_expiry"] = int(math.Floor(d.NotAfter.Sub(now).Hours() / 24))
+		switch {
+		case now.After(d.NotAfter):
+			out["status"] = "expired"
+		case !d.NotBefore.IsZero() && now.Before(d.NotBefore):
+			out["status"] = "not yet valid"
+		default:
+			out["status"] = "valid"
+		}
+	}
+	return out
+}
+
+// fetchCertificateDetails returns the details of a stored certificate,
+// parsed from localFile when it is set.
+func fetchCertificateDetails(ctx context.Context, name, localFile string) (certificateDetails, error) {
+	if localFile != "" {
+		data, err := os.ReadFile(localFile)
+		if err != nil {
+			return certificateDetails{}, fmt.Errorf("failed to read %s: %w", localFile, err)
+		}
+		return parseCertificatePEM(data)
+	}
+
+	data, err := internal.SendRequestWithContext(ctx, "GET", "/services/haproxy/storage/ssl_certificates/"+name, nil, nil)
+	if err != nil {
+		return certificateDetails{}, internal.FormatAPIError("Certificate", name, "get", err)
+	}
+	if strings.Contains(string(data), "-----BEGIN CERTIFICATE-----") {
+		return parseCertificatePEM(data)
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return certificateDetails{}, fmt.Errorf("unexpected certificate response: %w", err)
+	}
+	return detailsFromMetadata(obj), nil
+}
+
+// parseCertificatePEM parses the certificates of a PEM bundle, skipping the
+// private key, and returns the details of the first (leaf) certificate.
+func parseCertificatePEM(data []byte) (certificateDetails, error) {
+	var certs []*x509.Certificate
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return certificateDetails{}, fmt.Errorf("failed to parse certificate %d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return certificateDetails{}, errors.New("no certificate found in PEM data")
+	}
+
+	leaf := certs[0]
+	sans := append([]string{}, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, leaf.EmailAddresses...)
+	for _, uri := range leaf.URIs {
+		sans = append(sans, uri.String())
+	}
+	return certificateDetails{
+		Subject:   leaf.Subject.String(),
+		Issuer:    leaf.Issuer.String(),
+		SANs:      sans,
+		Serial:    leaf.SerialNumber.Text(16),
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+		Chain:     len(certs),
+	}, nil
+}
+
+// detailsFromMetadata maps the certificate metadata the Data Plane API
+// reports for a storage entry.
+func detailsFromMetadata(obj map[string]interface{}) certificateDetails {
+	str := func(key string) string {
+		v, _ := obj[key].(string)
+		return v
+	}
+	d := certificateDetails{
+		File:    str("file"),
+		Subject: str("subject"),
+		Issuer:  str("issuers"),
+		Serial:  str("serial"),
+	}
+	for _, key := range []string{"domains", "ip_addresses"} {
+		d.SANs = append(d.SANs, strings.FieldsFunc(str(key), func(r rune) bool { return r == ',' || r == ' ' })...)
+	}
+	d.NotBefore, _ = time.Parse(time.RFC3339, str("not_before"))
+	d.NotAfter, _ = time.Parse(time.RFC3339, str("not_after"))
+	return d
+}
@@ -2,11 +2,15 @@
 package certificates
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"haproxyctl/internal"
-	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -16,7 +20,19 @@ var GetCertificatesCmd = &cobra.Command{
 	Use:     "certificates [name]",
 	Aliases: []string{"certificate"},
 	Short:   "List SSL certificates or show details for one",
-	Args:    cobra.MaximumNArgs(1),
+	Long: `List the certificates in the Data Plane API SSL storage, or show one.
+
+With --expiring-within the certificates are listed with their expiry date
+instead, and the command exits with status 1 when any of them expires within
+the window (or already has), so it can run from cron or a monitoring check.
+The window takes days ("30d"), a Go duration ("72h") or a bare number of
+days.
+
+Examples:
+  haproxyctl get certificates
+  haproxyctl get certificates site.pem -o yaml
+  haproxyctl get certificates --expiring-within 30d`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		var name string
 		if len(args) > 0 {
@@ -28,8 +44,12 @@ var GetCertificatesCmd = &cobra.Command{
 
 func init() {
 	GetCertificatesCmd.Flags().StringP("output", "o", "", "Output format: yaml or json")
+	GetCertificatesCmd.Flags().String("expiring-within", "", "List expiry dates and exit 1 if a certificate expires within this window (e.g. 30d)")
 }
 
+// certificateColumns names certificates for -o name; tables show every field.
+var certificateColumns = internal.ColumnSet{Kind: "Certificate"}
+
 func getCertificates(cmd *cobra.Command, name string) {
 	outputFormat := internal.GetFlagString(cmd, "output")
 
@@ -37,12 +57,31 @@ func getCertificates(cmd *cobra.Command, name string) {
 
 	data, err := internal.SendRequestWithContext(cmd.Context(), "GET", endpoint, nil, nil)
 	if err != nil {
-		log.Fatalf("Failed to fetch certificate(s): %v", err)
+		internal.Fatalf("Failed to fetch certificate(s): %v", err)
 	}
 
 	var list []map[string]interface{}
 	if err := json.Unmarshal(data, &list); err != nil {
-		log.Fatalf("Failed to parse certificates list response: %v\nResponse: %s", err, string(data))
+		internal.Fatalf("Failed to parse certificates list response: %v\nResponse: %s", err, string(data))
+	}
+
+	if window := internal.GetFlagString(cmd, "expiring-within"); window != "" {
+		within, err := parseExpiryWindow(window)
+		if err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid --expiring-within: %v", err)
+		}
+		if name != "" {
+			list = filterCertificates(list, name)
+			if len(list) == 0 {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Certificate", name))
+			}
+		}
+		rows, expiring := auditCertificates(cmd.Context(), list, within, time.Now())
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, expiryColumns), outputFormat)
+		if expiring > 0 {
+			os.Exit(internal.ReportError(fmt.Sprintf("%d certificate(s) expire within %s", expiring, window), nil))
+		}
+		return
 	}
 
 	if name == "" {
@@ -52,7 +91,7 @@ func getCertificates(cmd *cobra.Command, name string) {
 		for _, m := range list {
 			rows = append(rows, m)
 		}
-		internal.FormatOutput(rows, outputFormat)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, certificateColumns), outputFormat)
 		return
 	}
 
@@ -66,9 +105,99 @@ func getCertificates(cmd *cobra.Command, name string) {
 	}
 
 	if found == nil {
-		_, _ = fmt.Fprintln(os.Stdout, internal.ResourceID("Certificate", name)+" not found")
-		return
+		internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Certificate", name))
+	}
+
+	internal.FormatOutputForCmd(cmd, internal.WithColumns(found, certificateColumns), outputFormat)
+}
+
+// expiryColumns are the columns of the --expiring-within audit.
+var expiryColumns = internal.ColumnSet{
+	Kind:    "Certificate",
+	Default: []string{"storage_name", "not_after", "days_until_expiry", "status"},
+}
+
+// parseExpiryWindow parses "30d", a Go duration such as "72h", or a bare
+// number of days.
+func parseExpiryWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, found := strings.CutSuffix(s, "d"); found {
+		s = days
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("window %q must not be negative", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid window %q (expected e.g. 30d or 72h)", s)
 	}
+	return d, nil
+}
 
-	internal.FormatOutput(found, outputFormat)
+// filterCertificates returns the storage entries named name.
+func filterCertificates(list []map[string]interface{}, name string) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, m := range list {
+		if m["storage_name"] == name {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// auditCertificates returns one row per certificate, soonest expiry first,
+// and how many expire within the window. The expiry comes from the list
+// metadata when the API reports it, and from the stored certificate
+// otherwise; a certificate whose expiry cannot be read is reported as
+// unknown with a warning but does not count as expiring.
+func auditCertificates(ctx context.Context, list []map[string]interface{}, within time.Duration, now time.Time) ([]map[string]interface{}, int) {
+	type audited struct {
+		row      map[string]interface{}
+		notAfter time.Time
+	}
+	var entries []audited
+	expiring := 0
+	for _, m := range list {
+		name, _ := m["storage_name"].(string)
+		notAfter := detailsFromMetadata(m).NotAfter
+		if notAfter.IsZero() {
+			details, err := fetchCertificateDetails(ctx, name, "")
+			if err != nil {
+				internal.Warnf("cannot read the expiry of certificate %q: %v", name, err)
+			}
+			notAfter = details.NotAfter
+		}
+
+		row := map[string]interface{}{"storage_name": name, "status": "unknown"}
+		if !notAfter.IsZero() {
+			validity := certificateDetails{NotAfter: notAfter}.describe(name, now)
+			for _, key := range []string{"not_after", "days_until_expiry", "status"} {
+				row[key] = validity[key]
+			}
+			switch {
+			case now.After(notAfter):
+				expiring++
+			case notAfter.Sub(now) <= within:
+				row["status"] = "expiring"
+				expiring++
+			}
+		}
+		entries = append(entries, audited{row: row, notAfter: notAfter})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].notAfter, entries[j].notAfter
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+	rows := make([]map[string]interface{}, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, e.row)
+	}
+	return rows, expiring
 }
@@ -0,0 +1,440 @@
+// Package certificates provides commands to manage HAProxy SSL certificates.
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/acme"
+)
+
+// RenewCertificatesCmd represents "renew certificates <name>".
+var RenewCertificatesCmd = &cobra.Command{
+	Use:     "certificates <name>",
+	Aliases: []string{"certificate"},
+	Short:   "Obtain or renew a certificate with ACME (e.g. Let's Encrypt) and upload it",
+	Long: `Obtain a certificate from an ACME CA such as Let's Encrypt, assemble the
+PEM bundle (key, certificate and chain) and upload it to the SSL storage as
+<name>.pem, replacing the stored one. Nothing is done while the stored
+certificate is valid for longer than --renew-before, unless --force is set.
+
+Challenges can be answered in three ways:
+  --challenge http-01 --http-frontend <name>
+      HAProxy answers the challenge itself: a temporary
+      "http-request return" rule per token is added to the frontend that
+      serves port 80, and removed once the CA has validated it.
+  --challenge dns-01 --dns-hook "<cmd>"
+      The hook is run with sh -c for every name, once with
+      ACME_ACTION=present and once with ACME_ACTION=cleanup, and gets
+      ACME_DOMAIN, ACME_TXT_NAME and ACME_TXT_VALUE to create or remove the
+      TXT record with the DNS provider's tooling.
+  --solver-command "<cmd>" --cert <file> --key <file> [--ca-file <file>]
+      An external client such as certbot, lego or acme.sh does everything;
+      the files it writes are uploaded afterwards. ACME_DOMAINS holds the
+      names, comma-separated.
+
+With --crt-list the certificate is also added to that crt-list, filtered
+on its names, unless the list already references it.
+
+Examples:
+  haproxyctl renew certificates site -d example.com -d www.example.com \
+    --email ops@example.com --http-frontend web
+  haproxyctl renew certificates wildcard -d '*.example.com' --challenge dns-01 \
+    --dns-hook ./route53-txt.sh --staging
+  haproxyctl renew certificates site -d example.com \
+    --solver-command "certbot renew --cert-name example.com" \
+    --cert /etc/letsencrypt/live/example.com/fullchain.pem \
+    --key /etc/letsencrypt/live/example.com/privkey.pem`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		domains, _ := cmd.Flags().GetStringSlice("domain")
+		wait, _ := cmd.Flags().GetDuration("propagation-wait")
+		opts := renewOptions{
+			Domains:         domains,
+			Email:           internal.GetFlagString(cmd, "email"),
+			Challenge:       internal.GetFlagString(cmd, "challenge"),
+			HTTPFrontend:    internal.GetFlagString(cmd, "http-frontend"),
+			DNSHook:         internal.GetFlagString(cmd, "dns-hook"),
+			SolverCommand:   internal.GetFlagString(cmd, "solver-command"),
+			CertFile:        internal.GetFlagString(cmd, "cert"),
+			KeyFile:         internal.GetFlagString(cmd, "key"),
+			CAFile:          internal.GetFlagString(cmd, "ca-file"),
+			DirectoryURL:    internal.GetFlagString(cmd, "directory-url"),
+			AccountKey:      internal.GetFlagString(cmd, "account-key"),
+			CrtList:         internal.GetFlagString(cmd, "crt-list"),
+			Force:           internal.GetFlagBool(cmd, "force"),
+			DryRun:          internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline(),
+			PropagationWait: wait,
+		}
+		if internal.GetFlagBool(cmd, "staging") {
+			opts.DirectoryURL = letsEncryptStagingDirectory
+		}
+		window, err := parseExpiryWindow(internal.GetFlagString(cmd, "renew-before"))
+		if err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid --renew-before: %v", err)
+		}
+		opts.RenewBefore = window
+
+		if err := RenewCertificate(cmd.Context(), args[0], opts); err != nil {
+			internal.Fatalf("Failed to renew certificate %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	RenewCertificatesCmd.Flags().StringSliceP("domain", "d", nil, "Name to include in the certificate (repeatable; the first is the common name)")
+	RenewCertificatesCmd.Flags().String("email", "", "Contact address for the ACME account")
+	RenewCertificatesCmd.Flags().String("challenge", acmeChallengeHTTP01, "Challenge type: http-01 or dns-01")
+	RenewCertificatesCmd.Flags().String("http-frontend", "", "Frontend serving port 80 that answers http-01 challenges")
+	RenewCertificatesCmd.Flags().String("dns-hook", "", "Command that creates (ACME_ACTION=present) and removes (cleanup) dns-01 TXT records")
+	RenewCertificatesCmd.Flags().String("solver-command", "", "External ACME client to run instead of the built-in one")
+	RenewCertificatesCmd.Flags().String("cert", "", "Certificate (chain) file written by --solver-command")
+	RenewCertificatesCmd.Flags().String("key", "", "Private key file written by --solver-command")
+	RenewCertificatesCmd.Flags().String("ca-file", "", "Optional CA chain file written by --solver-command")
+	RenewCertificatesCmd.Flags().String("directory-url", letsEncryptDirectory, "ACME directory URL")
+	RenewCertificatesCmd.Flags().Bool("staging", false, "Use the Let's Encrypt staging directory")
+	RenewCertificatesCmd.Flags().String("account-key", "", "ACME account key file, created if missing (default ~/.config/haproxyctl/acme-account.pem)")
+	RenewCertificatesCmd.Flags().String("crt-list", "", "crt-list to add the certificate to when it is not listed yet")
+	RenewCertificatesCmd.Flags().String("renew-before", "30d", "Only renew when the stored certificate expires within this window")
+	RenewCertificatesCmd.Flags().Bool("force", false, "Renew even when the stored certificate is still valid")
+	RenewCertificatesCmd.Flags().Duration("propagation-wait", 5*time.Second, "Time to let HAProxy reload or DNS propagate before the CA validates")
+	RenewCertificatesCmd.Flags().Bool("dry-run", false, "Show what would be done without contacting the CA")
+}
+
+type renewOptions struct {
+	Domains         []string
+	Email           string
+	Challenge       string
+	HTTPFrontend    string
+	DNSHook         string
+	SolverCommand   string
+	CertFile        string
+	KeyFile         string
+	CAFile          string
+	DirectoryURL    string
+	AccountKey      string
+	CrtList         string
+	RenewBefore     time.Duration
+	Force           bool
+	DryRun          bool
+	PropagationWait time.Duration
+}
+
+// validate checks that exactly one way of answering challenges is set up.
+func (o renewOptions) validate() error {
+	if len(o.Domains) == 0 {
+		return errors.New("at least one --domain is required")
+	}
+	if o.SolverCommand != "" {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return errors.New("--solver-command needs --cert and --key")
+		}
+		return nil
+	}
+	switch o.Challenge {
+	case acmeChallengeHTTP01:
+		if o.HTTPFrontend == "" {
+			return errors.New("http-01 needs --http-frontend")
+		}
+	case acmeChallengeDNS01:
+		if o.DNSHook == "" {
+			return errors.New("dns-01 needs --dns-hook")
+		}
+	default:
+		return fmt.Errorf("invalid --challenge %q (allowed: http-01, dns-01)", o.Challenge)
+	}
+	for _, d := range o.Domains {
+		if strings.HasPrefix(d, "*.") && o.Challenge != acmeChallengeDNS01 {
+			return fmt.Errorf("wildcard name %q needs --challenge dns-01", d)
+		}
+	}
+	return nil
+}
+
+// RenewCertificate obtains a certificate for opts.Domains and uploads it
+// as <name>.pem, unless the stored one is valid for long enough.
+func RenewCertificate(ctx context.Context, name string, opts renewOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	storageName := name + ".pem"
+
+	if opts.DryRun {
+		plan := map[string]interface{}{
+			"name":      storageName,
+			"domains":   opts.Domains,
+			"challenge": opts.Challenge,
+			"directory": opts.DirectoryURL,
+		}
+		if opts.SolverCommand != "" {
+			plan["challenge"] = "external"
+			plan["solver_command"] = opts.SolverCommand
+		}
+		if opts.CrtList != "" {
+			plan["crt_list"] = opts.CrtList
+		}
+		internal.FormatOutput(plan, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	list, err := internal.GetResourceList("/services/haproxy/storage/ssl_certificates")
+	if err != nil {
+		return fmt.Errorf("failed to list certificates: %w", err)
+	}
+	exists := len(filterCertificates(list, storageName)) > 0
+	if exists && !opts.Force {
+		details, err := fetchCertificateDetails(ctx, storageName, "")
+		if err == nil && !details.NotAfter.IsZero() && time.Until(details.NotAfter) > opts.RenewBefore {
+			internal.PrintStatus("Certificate", storageName, fmt.Sprintf("%s (expires %s)", internal.ActionUnchanged, details.NotAfter.UTC().Format(time.DateOnly)))
+			return opts.ensureCrtList(ctx, storageName)
+		}
+	}
+
+	bundle, err := opts.obtain(ctx)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		_, err = internal.SendRawRequestWithContext(ctx, "PUT", "/services/haproxy/storage/ssl_certificates/"+storageName, nil, bundle, "text/plain")
+	} else {
+		err = internal.UploadSSLCertificateWithContext(ctx, name, bundle)
+	}
+	if err != nil {
+		return internal.FormatAPIError("Certificate", storageName, "upload", err)
+	}
+	if exists {
+		internal.PrintStatus("Certificate", storageName, "renewed")
+	} else {
+		internal.PrintStatus("Certificate", storageName, internal.ActionCreated)
+	}
+	return opts.ensureCrtList(ctx, storageName)
+}
+
+// obtain returns the PEM bundle from the external solver or the built-in
+// ACME client.
+func (o renewOptions) obtain(ctx context.Context) ([]byte, error) {
+	if o.SolverCommand != "" {
+		cmd := exec.CommandContext(ctx, "sh", "-c", o.SolverCommand) //nolint:gosec // the command comes from explicit CLI input
+		cmd.Env = append(os.Environ(), "ACME_DOMAINS="+strings.Join(o.Domains, ","))
+		cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("solver command failed: %w", err)
+		}
+		bundle, _, err := buildCertificatePEM("", o.CertFile, o.KeyFile, o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := parseCertificatePEM(bundle); err != nil {
+			return nil, fmt.Errorf("solver output: %w", err)
+		}
+		return bundle, nil
+	}
+
+	keyPath := o.AccountKey
+	if keyPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		keyPath = filepath.Join(home, ".config", "haproxyctl", "acme-account.pem")
+		if err := os.MkdirAll(filepath.Dir(keyPath), 0o700); err != nil {
+			return nil, err
+		}
+	}
+	accountKey, err := loadOrCreateAccountKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var solver acmeSolver = dnsHookSolver{hook: o.DNSHook, wait: o.PropagationWait}
+	if o.Challenge == acmeChallengeHTTP01 {
+		solver = frontendSolver{frontend: o.HTTPFrontend, wait: o.PropagationWait}
+	}
+	client := &acme.Client{
+		Key:          accountKey,
+		DirectoryURL: o.DirectoryURL,
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+		UserAgent:    "haproxyctl",
+	}
+	keyPEM, chainPEM, err := obtainCertificate(ctx, client, o.Domains, o.Email, o.Challenge, solver)
+	if err != nil {
+		return nil, err
+	}
+	return append(keyPEM, chainPEM...), nil
+}
+
+// ensureCrtList adds the certificate to the crt-list when it is set and
+// does not reference the certificate yet.
+func (o renewOptions) ensureCrtList(ctx context.Context, storageName string) error {
+	if o.CrtList == "" {
+		return nil
+	}
+	list, err := internal.GetResourceList("/services/haproxy/storage/ssl_certificates")
+	if err != nil {
+		return fmt.Errorf("failed to list certificates: %w", err)
+	}
+	file := "/etc/haproxy/ssl/" + storageName
+	for _, c := range filterCertificates(list, storageName) {
+		if f, ok := c["file"].(string); ok && f != "" {
+			file = f
+		}
+	}
+
+	entriesPath := "/services/haproxy/storage/ssl_crt_lists/" + o.CrtList + "/entries"
+	entries, err := internal.GetResourceList(entriesPath)
+	if err != nil {
+		return internal.FormatAPIError("CrtList", o.CrtList, "get", err)
+	}
+	for _, e := range entries {
+		if e["file"] == file {
+			return nil
+		}
+	}
+	entry := map[string]interface{}{"file": file, "sni_filter": o.Domains}
+	if _, err := internal.SendRequestWithContext(ctx, "POST", entriesPath, nil, entry); err != nil {
+		return internal.FormatAPIError("CrtList", o.CrtList, "update", err)
+	}
+	internal.PrintStatus("CrtList", o.CrtList, "entry "+filepath.Base(file)+" added")
+	return nil
+}
+
+// acmeChallengePath is the request path an http-01 challenge is fetched at.
+func acmeChallengePath(token string) string {
+	return "/.well-known/acme-challenge/" + token
+}
+
+// frontendSolver answers http-01 challenges from HAProxy itself with a
+// temporary "http-request return" rule per token on a frontend.
+type frontendSolver struct {
+	frontend string
+	wait     time.Duration
+}
+
+func (s frontendSolver) rulesPath() string {
+	return "/services/haproxy/configuration/frontends/" + s.frontend + "/http_request_rules"
+}
+
+// Present inserts the rules first, so no redirect or deny rule sees the
+// challenge requests, in one transaction.
+func (s frontendSolver) Present(ctx context.Context, challenges []acmeChallenge) error {
+	err := internal.WithTransaction(func(tx *internal.Transaction) error {
+		for _, ch := range challenges {
+			rule := map[string]interface{}{
+				"type":                  "return",
+				"return_status_code":    http.StatusOK,
+				"return_content_type":   "text/plain",
+				"return_content_format": "string",
+				"return_content":        ch.KeyAuthorization,
+				"cond":                  "if",
+				"cond_test":             "{ path " + acmeChallengePath(ch.Token) + " }",
+			}
+			if _, err := internal.SendRequestWithContext(ctx, "POST", s.rulesPath()+"/0", tx.Params(), rule); err != nil {
+				return fmt.Errorf("failed to add challenge rule to frontend %q: %w", s.frontend, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return sleepContext(ctx, s.wait)
+}
+
+// CleanUp removes the challenge rules again, whatever their position now.
+func (s frontendSolver) CleanUp(ctx context.Context, challenges []acmeChallenge) error {
+	rules, err := internal.GetResourceListWithContext(ctx, s.rulesPath())
+	if err != nil {
+		return err
+	}
+	tokens := make(map[string]bool, len(challenges))
+	for _, ch := range challenges {
+		tokens["{ path "+acmeChallengePath(ch.Token)+" }"] = true
+	}
+	var stale []int
+	for i, r := range rules {
+		if test, _ := r["cond_test"].(string); r["type"] == "return" && tokens[test] {
+			stale = append(stale, i)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	return internal.WithTransaction(func(tx *internal.Transaction) error {
+		for i := len(stale) - 1; i >= 0; i-- {
+			if _, err := internal.SendRequestWithContext(ctx, "DELETE", s.rulesPath()+"/"+strconv.Itoa(stale[i]), tx.Params(), nil); err != nil {
+				return fmt.Errorf("failed to remove challenge rule from frontend %q: %w", s.frontend, err)
+			}
+		}
+		return nil
+	})
+}
+
+// dnsHookSolver answers dns-01 challenges by running a user command.
+type dnsHookSolver struct {
+	hook string
+	wait time.Duration
+}
+
+func (s dnsHookSolver) run(ctx context.Context, action string, ch acmeChallenge) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.hook) //nolint:gosec // the command comes from explicit CLI input
+	cmd.Env = append(os.Environ(),
+		"ACME_ACTION="+action,
+		"ACME_DOMAIN="+ch.Domain,
+		"ACME_TXT_NAME=_acme-challenge."+strings.TrimPrefix(ch.Domain, "*."),
+		"ACME_TXT_VALUE="+ch.TXTValue,
+	)
+	cmd.Stdout, cmd.Stderr = os.Stderr, &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("dns hook (%s %s) failed: %w: %s", action, ch.Domain, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Present creates the records one by one; when a hook fails, the records
+// created before it are removed again.
+func (s dnsHookSolver) Present(ctx context.Context, challenges []acmeChallenge) error {
+	for i, ch := range challenges {
+		if err := s.run(ctx, "present", ch); err != nil {
+			cleanCtx, cancel := cleanupContext(ctx)
+			defer cancel()
+			return errors.Join(err, s.CleanUp(cleanCtx, challenges[:i]))
+		}
+	}
+	return sleepContext(ctx, s.wait)
+}
+
+func (s dnsHookSolver) CleanUp(ctx context.Context, challenges []acmeChallenge) error {
+	var errs []error
+	for _, ch := range challenges {
+		errs = append(errs, s.run(ctx, "cleanup", ch))
+	}
+	return errors.Join(errs...)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/cluster"
+
+	"github.com/spf13/cobra"
+)
+
+// clusterCmd represents the "cluster" command.
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Join or leave Data Plane API cluster mode",
+	Long: `Join the Data Plane API to a cluster managed by a central controller, or
+leave it again. "haproxyctl get cluster" shows the current mode.
+
+Examples:
+  haproxyctl cluster join --bootstrap-key KEY
+  haproxyctl cluster leave`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(clusterCmd)
+
+	clusterCmd.AddCommand(cluster.JoinClusterCmd)
+	clusterCmd.AddCommand(cluster.LeaveClusterCmd)
+}
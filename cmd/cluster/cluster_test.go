@@ -0,0 +1,62 @@
+package cluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestJoinAndLeaveCluster(t *testing.T) {
+	t.Cleanup(internal.SetStateDir(t.TempDir()))
+	srv := testserver.New(t)
+	ctx := context.Background()
+
+	settings, err := GetSettings(ctx)
+	if err != nil || settings.Mode != modeSingle || internal.ClusterNode() != "" {
+		t.Fatalf("GetSettings = %+v, %v (node %q)", settings, err, internal.ClusterNode())
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := JoinCluster(ctx, "bootstrap-key"); err != nil {
+			t.Fatalf("JoinCluster failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "cluster/prod joined (node lb1)") {
+		t.Fatalf("unexpected join output:\n%s", output)
+	}
+	if srv.ClusterMode() != modeCluster || internal.ClusterNode() != "lb1" {
+		t.Fatalf("mode = %s, node = %q", srv.ClusterMode(), internal.ClusterNode())
+	}
+
+	// Later changes name the node; statuses without a change do not.
+	output = internal.CaptureStdout(t, func() {
+		internal.PrintStatus("Backend", "app", internal.ActionCreated)
+		internal.PrintStatus("Backend", "web", internal.ActionUnchanged)
+	})
+	if !strings.Contains(output, "backend/app created (node lb1)") || strings.Contains(output, "unchanged (node") {
+		t.Fatalf("unexpected status output:\n%s", output)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := LeaveCluster(ctx, true); err != nil {
+			t.Fatalf("LeaveCluster failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "cluster/prod left") || strings.Contains(output, "(node") {
+		t.Fatalf("unexpected leave output:\n%s", output)
+	}
+	if srv.ClusterMode() != modeSingle || internal.ClusterNode() != "" {
+		t.Fatalf("still in cluster mode after leave: mode = %s, node = %q", srv.ClusterMode(), internal.ClusterNode())
+	}
+}
+
+func TestJoinCluster_RequiresKey(t *testing.T) {
+	t.Parallel()
+
+	if err := JoinCluster(context.Background(), ""); err == nil {
+		t.Fatal("expected an error without a bootstrap key")
+	}
+}
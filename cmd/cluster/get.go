@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster provides commands to inspect, join and leave Data Plane
+// API cluster mode, in which a central controller manages the node.
+package cluster
+
+import (
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetClusterCmd represents "get cluster".
+var GetClusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Show whether the Data Plane API runs in cluster mode",
+	Long: `Show the mode of the Data Plane API (single or cluster) and, in cluster
+mode, the cluster it belongs to, its status and the node name. Once an
+endpoint is known to be in cluster mode, commands that change the
+configuration name the node that handled the change.
+
+Examples:
+  haproxyctl get cluster
+  haproxyctl get cluster -o yaml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		settings, err := GetSettings(cmd.Context())
+		if err != nil {
+			internal.Fatalf("Failed to fetch cluster settings: %v", err)
+		}
+		outputFormat := internal.GetFlagString(cmd, "output")
+		if outputFormat == internal.OutputFormatYAML || outputFormat == "json" {
+			internal.FormatOutputForCmd(cmd, settings, outputFormat)
+			return
+		}
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(settingsRow(settings), clusterColumns), outputFormat)
+	},
+}
+
+// clusterColumns shows the mode and, in cluster mode, where the cluster is.
+var clusterColumns = internal.ColumnSet{
+	Kind:    clusterKind,
+	Default: []string{"mode", "status", "cluster", "node"},
+	Wide:    []string{"address", "cluster_id"},
+}
+
+// settingsRow flattens settings for the table view.
+func settingsRow(s Settings) map[string]interface{} {
+	row := map[string]interface{}{"mode": s.Mode, "status": s.Status, "node": s.Node, "cluster": s.name()}
+	if s.Cluster != nil {
+		address := s.Cluster.Address
+		if s.Cluster.Port != 0 {
+			address += ":" + strconv.Itoa(s.Cluster.Port)
+		}
+		row["address"] = address
+		row["cluster_id"] = s.Cluster.ClusterID
+	}
+	return row
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster provides commands to inspect, join and leave Data Plane
+// API cluster mode, in which a central controller manages the node.
+package cluster
+
+import (
+	"context"
+	"errors"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// JoinClusterCmd represents "cluster join".
+var JoinClusterCmd = &cobra.Command{
+	Use:   "join",
+	Short: "Join the Data Plane API to a cluster",
+	Long: `Switch the Data Plane API to cluster mode with the bootstrap key issued
+by the cluster controller. The controller then manages the configuration
+of the node; until it approves the node the status is waiting_approval.
+
+Examples:
+  haproxyctl cluster join --bootstrap-key "$(cat bootstrap.key)"
+  haproxyctl cluster join --bootstrap-key KEY --context lb2`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		key := internal.GetFlagString(cmd, "bootstrap-key")
+		if err := JoinCluster(cmd.Context(), key); err != nil {
+			internal.Fatalf("Failed to join cluster: %v", err)
+		}
+	},
+}
+
+// LeaveClusterCmd represents "cluster leave".
+var LeaveClusterCmd = &cobra.Command{
+	Use:   "leave",
+	Short: "Switch the Data Plane API back to single mode",
+	Long: `Leave the cluster and switch the Data Plane API back to single mode. The
+configuration the controller pushed is removed unless --keep-configuration
+is set.
+
+Examples:
+  haproxyctl cluster leave --keep-configuration`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if err := LeaveCluster(cmd.Context(), internal.GetFlagBool(cmd, "keep-configuration")); err != nil {
+			internal.Fatalf("Failed to leave cluster: %v", err)
+		}
+	},
+}
+
+func init() {
+	JoinClusterCmd.Flags().String("bootstrap-key", "", "Bootstrap key issued by the cluster controller (required)")
+	LeaveClusterCmd.Flags().Bool("keep-configuration", false, "Keep the current HAProxy configuration instead of clearing it")
+}
+
+// JoinCluster posts the bootstrap key to /cluster and records the node.
+func JoinCluster(ctx context.Context, bootstrapKey string) error {
+	if bootstrapKey == "" {
+		return errors.New("--bootstrap-key is required")
+	}
+	if _, err := internal.SendRequestWithContext(ctx, "POST", clusterPath, nil, map[string]string{"bootstrap_key": bootstrapKey}); err != nil {
+		return err
+	}
+	settings, err := GetSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	action := "joined"
+	if settings.Status != "" && settings.Status != "active" {
+		action += ", " + settings.Status
+	}
+	internal.PrintStatus(clusterKind, settings.name(), action)
+	return nil
+}
+
+// LeaveCluster deletes the cluster settings, returning the node to single
+// mode.
+func LeaveCluster(ctx context.Context, keepConfiguration bool) error {
+	settings, err := GetSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if settings.Mode != modeCluster {
+		internal.PrintStatus(clusterKind, modeSingle, "not in cluster mode, "+internal.ActionUnchanged)
+		return nil
+	}
+
+	var params map[string]string
+	if keepConfiguration {
+		params = map[string]string{"configuration": "keep"}
+	}
+	if _, err := internal.SendRequestWithContext(ctx, "DELETE", clusterPath, params, nil); err != nil {
+		return err
+	}
+	if err := rememberNode(""); err != nil {
+		return err
+	}
+
+	internal.PrintStatus(clusterKind, settings.name(), "left")
+	return nil
+}
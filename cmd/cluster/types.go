@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster provides commands to inspect, join and leave Data Plane
+// API cluster mode, in which a central controller manages the node.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"haproxyctl/internal"
+)
+
+const (
+	clusterKind = "Cluster"
+	clusterPath = "/cluster"
+
+	modeCluster = "cluster"
+	modeSingle  = "single"
+)
+
+// Settings is the /cluster resource: the mode of the node and, in cluster
+// mode, the cluster it belongs to.
+//
+//nolint:tagliatelle // Data Plane API field names
+type Settings struct {
+	Mode    string       `json:"mode" yaml:"mode"`
+	Status  string       `json:"status,omitempty" yaml:"status,omitempty"`
+	Node    string       `json:"node,omitempty" yaml:"node,omitempty"`
+	Cluster *ClusterInfo `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+}
+
+// ClusterInfo describes the cluster a node joined.
+//
+//nolint:tagliatelle // Data Plane API field names
+type ClusterInfo struct {
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Address     string `json:"address,omitempty" yaml:"address,omitempty"`
+	Port        int    `json:"port,omitempty" yaml:"port,omitempty"`
+	APIBasePath string `json:"api_base_path,omitempty" yaml:"api_base_path,omitempty"`
+	ClusterID   string `json:"cluster_id,omitempty" yaml:"cluster_id,omitempty"`
+}
+
+// name returns the cluster name, or its address when it has none.
+func (s Settings) name() string {
+	if s.Cluster == nil {
+		return ""
+	}
+	if s.Cluster.Name != "" {
+		return s.Cluster.Name
+	}
+	return s.Cluster.Address
+}
+
+// GetSettings fetches the cluster settings of the configured endpoint and
+// records the node name when it runs in cluster mode (see
+// internal.SetClusterNode).
+func GetSettings(ctx context.Context) (Settings, error) {
+	data, err := internal.SendRequestWithContext(ctx, "GET", clusterPath, nil, nil)
+	if err != nil {
+		return Settings{}, err
+	}
+	var settings Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse cluster settings: %w", err)
+	}
+	if settings.Mode == modeCluster {
+		settings.Node = nodeName(ctx)
+	}
+	if err := rememberNode(settings.Node); err != nil {
+		return Settings{}, err
+	}
+	return settings, nil
+}
+
+// nodeName returns the hostname the Data Plane API reports in /info, or
+// the host of the API URL when /info does not tell.
+func nodeName(ctx context.Context) string {
+	var info struct {
+		System struct {
+			Hostname string `json:"hostname"`
+		} `json:"system"`
+	}
+	if data, err := internal.SendRequestWithContext(ctx, "GET", "/info", nil, nil); err == nil && json.Unmarshal(data, &info) == nil && info.System.Hostname != "" {
+		return info.System.Hostname
+	}
+	cfg, err := internal.LoadConfig()
+	if err != nil {
+		return ""
+	}
+	if u, err := url.Parse(cfg.APIBaseURL); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+// rememberNode stores node ("" for single mode) for the configured
+// endpoint.
+func rememberNode(node string) error {
+	cfg, err := internal.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if err := internal.SetClusterNode(cfg.APIBaseURL, node); err != nil {
+		return fmt.Errorf("failed to record cluster mode: %w", err)
+	}
+	return nil
+}
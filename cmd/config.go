@@ -0,0 +1,261 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the "config" command, which manages the local
+// haproxyctl configuration file rather than HAProxy itself.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the haproxyctl configuration file",
+	Long: `Manage ~/.config/haproxyctl/config.json and its named contexts.
+
+Examples:
+  haproxyctl config get-contexts
+  haproxyctl config use-context staging
+  haproxyctl config prune --dry-run`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+// configPruneCmd represents "config prune".
+var configPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove contexts whose API endpoint no longer resolves",
+	Long: `Remove named contexts whose api_base_url host can no longer be resolved,
+for example after a load balancer was decommissioned, along with their
+keyring entries. The default endpoint is never removed; if the current
+context is pruned, commands fall back to it.
+
+Examples:
+  haproxyctl config prune
+  haproxyctl config prune --dry-run`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		cfg, err := internal.LoadConfigFile()
+		if err != nil {
+			internal.Fatalf("Failed to load configuration: %v", err)
+		}
+
+		stale := cfg.UnresolvableContexts(nil)
+		if len(stale) == 0 {
+			_, _ = fmt.Fprintln(os.Stdout, "no contexts to prune")
+			return
+		}
+
+		if internal.GetFlagBool(cmd, "dry-run") {
+			for _, name := range stale {
+				internal.PrintStatus("Context", name, "would be pruned")
+			}
+			internal.PrintDryRun()
+			return
+		}
+
+		if err := pruneContexts(&cfg, stale); err != nil {
+			internal.Fatalf("Failed to prune contexts: %v", err)
+		}
+		if err := internal.SaveConfig(cfg); err != nil {
+			internal.Fatalf("Failed to save configuration: %v", err)
+		}
+		for _, name := range stale {
+			internal.PrintStatus("Context", name, "pruned")
+		}
+	},
+}
+
+// pruneContexts removes the named contexts from cfg like delete-context
+// does: their keyring entries go too, and a pruned current_context falls
+// back to the top-level endpoint.
+func pruneContexts(cfg *internal.Config, names []string) error {
+	for _, name := range names {
+		forgetKeyringPassword(*cfg, name)
+		if err := cfg.DeleteContext(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configGetContextsCmd represents "config get-contexts".
+var configGetContextsCmd = &cobra.Command{
+	Use:   "get-contexts",
+	Short: "List the endpoints in the configuration file",
+	Long: `List the top-level endpoint (as "default") and every named context.
+The context commands use is marked with "*".
+
+Examples:
+  haproxyctl config get-contexts`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		cfg, err := internal.LoadConfigFile()
+		if err != nil {
+			internal.Fatalf("Failed to load configuration: %v", err)
+		}
+		if err := printContexts(cfg); err != nil {
+			internal.Fatalf("Failed to list contexts: %v", err)
+		}
+	},
+}
+
+// configUseContextCmd represents "config use-context <name>".
+var configUseContextCmd = &cobra.Command{
+	Use:   "use-context <context_name>",
+	Short: "Make a context the current_context",
+	Long: `Store the named context as current_context, so commands without --context
+talk to it. "default" selects the top-level endpoint again.
+
+Examples:
+  haproxyctl config use-context prod
+  haproxyctl config use-context default`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		cfg, err := internal.LoadConfigFile()
+		if err != nil {
+			internal.Fatalf("Failed to load configuration: %v", err)
+		}
+		if err := cfg.UseContext(args[0]); err != nil {
+			internal.Fatalf("Failed to switch context: %v", err)
+		}
+		if err := internal.SaveConfig(cfg); err != nil {
+			internal.Fatalf("Failed to save configuration: %v", err)
+		}
+		internal.PrintStatus("Context", args[0], "selected")
+	},
+}
+
+// configSetContextCmd represents "config set-context <name>".
+var configSetContextCmd = &cobra.Command{
+	Use:   "set-context <context_name>",
+	Short: "Create or update a named context",
+	Long: `Create a named context, or update the fields of an existing one. Fields
+whose flags are not given keep their current values; a new context needs
+at least --api-base-url.
+
+Examples:
+  haproxyctl config set-context staging --api-base-url http://lb-staging:5555 --username admin --password secret
+  haproxyctl config set-context staging --password rotated`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := internal.LoadConfigFile()
+		if err != nil {
+			// A missing file just means this is the first context.
+			cfg = internal.Config{}
+		}
+		endpoint := internal.Config{
+			APIBaseURL: internal.GetFlagString(cmd, "api-base-url"),
+			Username:   internal.GetFlagString(cmd, "username"),
+			Password:   internal.GetFlagString(cmd, "password"),
+		}
+		created, err := cfg.SetContextEndpoint(args[0], endpoint)
+		if err != nil {
+			internal.Fatalf("Failed to set context: %v", err)
+		}
+		if err := internal.SaveConfig(cfg); err != nil {
+			internal.Fatalf("Failed to save configuration: %v", err)
+		}
+		action := internal.ActionConfigured
+		if created {
+			action = internal.ActionCreated
+		}
+		internal.PrintStatus("Context", args[0], action)
+	},
+}
+
+// configDeleteContextCmd represents "config delete-context <name>".
+var configDeleteContextCmd = &cobra.Command{
+	Use:   "delete-context <context_name>",
+	Short: "Remove a named context",
+	Long: `Remove a named context from the configuration file. If it was the
+current_context, commands fall back to the top-level endpoint.
+
+Examples:
+  haproxyctl config delete-context staging`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		cfg, err := internal.LoadConfigFile()
+		if err != nil {
+			internal.Fatalf("Failed to load configuration: %v", err)
+		}
+		forgetKeyringPassword(cfg, args[0])
+		if err := cfg.DeleteContext(args[0]); err != nil {
+			internal.Fatalf("Failed to delete context: %v", err)
+		}
+		if err := internal.SaveConfig(cfg); err != nil {
+			internal.Fatalf("Failed to save configuration: %v", err)
+		}
+		internal.PrintStatus("Context", args[0], internal.ActionDeleted)
+	},
+}
+
+// printContexts writes the endpoints of cfg as a table, marking the one
+// commands currently use.
+func printContexts(cfg internal.Config) error {
+	const (
+		tabWidth   = 8
+		tabPadding = 2
+	)
+
+	selected := contextLabel(cfg.SelectedContext())
+	names := append([]string{internal.DefaultContextName}, cfg.ContextNames()...)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, tabWidth, tabPadding, ' ', 0)
+	if _, err := fmt.Fprintln(w, "CURRENT\tNAME\tAPI URL\tUSER"); err != nil {
+		return err
+	}
+	for _, name := range names {
+		lookup := name
+		if name == internal.DefaultContextName {
+			lookup = ""
+		}
+		endpoint, _ := cfg.Context(lookup)
+		marker := ""
+		if name == selected {
+			marker = "*"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", marker, name, endpoint.APIBaseURL, endpoint.Username); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	configCmd.AddCommand(configPruneCmd)
+	configPruneCmd.Flags().Bool("dry-run", false, "List the contexts that would be removed without removing them")
+
+	configCmd.AddCommand(configGetContextsCmd)
+	configCmd.AddCommand(configUseContextCmd)
+	configCmd.AddCommand(configDeleteContextCmd)
+
+	configCmd.AddCommand(configSetContextCmd)
+	configSetContextCmd.Flags().String("api-base-url", "", "Data Plane API base URL of the context")
+	configSetContextCmd.Flags().String("username", "", "Data Plane API username")
+	configSetContextCmd.Flags().String("password", "", "Data Plane API password")
+}
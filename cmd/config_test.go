@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"haproxyctl/internal"
+)
+
+func TestPruneContexts_CurrentContext(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	config := `{
+  "api_base_url": "http://127.0.0.1:5555",
+  "current_context": "gone",
+  "contexts": {
+    "gone": {"api_base_url": "http://gone.invalid:5555", "username": "admin", "keyring_account": "gone"},
+    "kept": {"api_base_url": "http://127.0.0.1:5556", "username": "admin", "password": "secret"}
+  }
+}`
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer internal.SetConfigFile(configPath)()
+	secrets := map[string]string{"gone": "from-keyring"}
+	defer internal.SetKeyring(secrets)()
+
+	cfg, err := internal.LoadConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pruneContexts(&cfg, []string{"gone"}); err != nil {
+		t.Fatalf("pruneContexts failed: %v", err)
+	}
+	if err := internal.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := secrets["gone"]; ok {
+		t.Error("keyring entry of the pruned context was left behind")
+	}
+	cfg, err = internal.LoadConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Contexts["gone"]; ok {
+		t.Error("context was not pruned")
+	}
+	if _, ok := cfg.Contexts["kept"]; !ok {
+		t.Error("unrelated context was pruned")
+	}
+	if cfg.CurrentContext != "" {
+		t.Errorf("current_context = %q, want the top-level endpoint", cfg.CurrentContext)
+	}
+	if _, err := internal.LoadConfig(); err != nil {
+		t.Errorf("commands fail after pruning the current context: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package configuration
 
 import (
 	"fmt"
+	"haproxyctl/cmd/logtargets"
 	"haproxyctl/internal"
 	"reflect"
 	"strings"
@@ -14,8 +15,9 @@ func applyConfig[T any](
 	outputFormat string,
 	dryRun bool,
 	kind string,
+	name string,
 	getCurrent func() (T, error),
-	putFn func(int, T) error,
+	putFn func(int, T) (action string, err error),
 ) error {
 	var manifest T
 	if err := yaml.Unmarshal(data, &manifest); err != nil {
@@ -41,7 +43,7 @@ func applyConfig[T any](
 	}
 
 	if reflect.DeepEqual(current, manifest) {
-		internal.PrintStatus(kind, "config", internal.ActionUnchanged)
+		internal.PrintStatus(kind, name, internal.ActionUnchanged)
 		return nil
 	}
 
@@ -50,64 +52,298 @@ func applyConfig[T any](
 		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
 	}
 
-	if err := putFn(version, manifest); err != nil {
+	action, err := putFn(version, manifest)
+	if err != nil {
 		return err
 	}
 
-	internal.PrintStatus(kind, "config", internal.ActionConfigured)
+	internal.PrintStatus(kind, name, action)
 	return nil
 }
 
-// ApplyGlobalFromYAML applies a GlobalConfig manifest declaratively.
+// ApplyGlobalFromYAML applies a GlobalConfig manifest declaratively. The
+// global log targets are reconciled only when the manifest lists them.
 func ApplyGlobalFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest GlobalConfig
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse global manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid global configuration: %w", err)
+	}
+	manageLogTargets := manifest.LogTargets != nil
+
+	var liveTargets []logtargets.LogTarget
 	return applyConfig(
 		data,
 		outputFormat,
 		dryRun,
 		"Global",
+		"config",
 		func() (GlobalConfig, error) {
-			obj, err := internal.GetResource("/services/haproxy/configuration/global")
-			if err != nil && !internal.IsNotFoundError(err) {
-				return GlobalConfig{}, fmt.Errorf("failed to fetch current global configuration: %w", err)
+			current, err := fetchCurrentGlobal()
+			if err != nil {
+				return current, err
 			}
-			if obj == nil {
-				return GlobalConfig{}, nil
+			liveTargets = current.LogTargets
+			current.LogTargets = nil
+			if manageLogTargets {
+				// Match the manifest's non-nil list so DeepEqual sees
+				// "no targets" on both sides as equal.
+				current.LogTargets = append([]logtargets.LogTarget{}, liveTargets...)
 			}
-			return mapGlobalFromAPI(obj), nil
+			return current, nil
+		},
+		func(version int, cfg GlobalConfig) (string, error) {
+			if err := putGlobal(version, cfg); err != nil {
+				return "", err
+			}
+			if manageLogTargets {
+				if err := logtargets.ApplyDiff(nil, logtargets.ParentGlobal, "", liveTargets, cfg.LogTargets); err != nil {
+					return "", fmt.Errorf("failed to apply global log target changes: %w", err)
+				}
+			}
+			return internal.ActionConfigured, nil
 		},
-		putGlobal,
 	)
 }
 
-// ApplyDefaultsFromYAML applies a DefaultsConfig manifest declaratively.
+// ApplyDefaultsFromYAML applies a DefaultsConfig manifest declaratively. A
+// named manifest targets the defaults section of that name, which is
+// created when it does not exist; an unnamed one targets the primary
+// defaults section. The section named by from must already exist.
 func ApplyDefaultsFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var named struct {
+		Name string `yaml:"name"`
+	}
+	// Parse errors are reported by applyConfig.
+	_ = yaml.Unmarshal(data, &named)
+
 	var currentName string
+	missing := false
+
+	statusName := named.Name
+	if statusName == "" {
+		statusName = "config"
+	}
 
 	return applyConfig(
 		data,
 		outputFormat,
 		dryRun,
 		"Defaults",
+		statusName,
 		func() (DefaultsConfig, error) {
-			list, err := internal.GetResourceList("/services/haproxy/configuration/defaults")
-			if err != nil && !internal.IsNotFoundError(err) {
-				return DefaultsConfig{}, fmt.Errorf("failed to fetch current defaults configuration: %w", err)
-			}
-			if len(list) == 0 {
-				currentName = ""
-				return DefaultsConfig{}, nil
+			if named.Name == "" {
+				cfg, err := fetchCurrentDefaults()
+				currentName = cfg.Name
+				return cfg, err
 			}
-			cfg := mapDefaultsFromAPI(list[0])
-			currentName = cfg.Name
-			return cfg, nil
+			cfg, exists, err := fetchDefaultsSection(named.Name)
+			missing = !exists
+			return cfg, err
 		},
-		func(version int, cfg DefaultsConfig) error {
+		func(version int, cfg DefaultsConfig) (string, error) {
+			if err := cfg.Validate(); err != nil {
+				return "", fmt.Errorf("invalid defaults configuration: %w", err)
+			}
+			if err := checkDefaultsFrom(cfg.Name, cfg.From); err != nil {
+				return "", err
+			}
+			if missing {
+				return internal.ActionCreated, createDefaults(version, cfg)
+			}
 			// If the manifest did not specify a name, fall back to the
 			// current defaults section name (best-effort primary defaults).
 			if cfg.Name == "" {
 				cfg.Name = currentName
 			}
-			return putDefaults(version, cfg)
+			return internal.ActionConfigured, putDefaults(version, cfg)
 		},
 	)
 }
+
+// fetchDefaultsSection returns the named defaults section as a manifest.
+// exists is false when there is no such section.
+func fetchDefaultsSection(name string) (cfg DefaultsConfig, exists bool, err error) {
+	obj, err := internal.GetResource("/services/haproxy/configuration/defaults/" + name)
+	if err != nil {
+		if internal.IsNotFoundError(err) {
+			return DefaultsConfig{}, false, nil
+		}
+		return DefaultsConfig{}, false, fmt.Errorf("failed to fetch defaults section %q: %w", name, err)
+	}
+	return mapDefaultsFromAPI(obj), true, nil
+}
+
+// checkDefaultsFrom verifies that the section a defaults section inherits
+// from exists, so a typo fails before anything is written.
+func checkDefaultsFrom(name, from string) error {
+	if from == "" {
+		return nil
+	}
+	if from == name {
+		return fmt.Errorf("defaults section %q cannot inherit from itself", name)
+	}
+	_, exists, err := fetchDefaultsSection(from)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("defaults section %q referenced by from does not exist", from)
+	}
+	return nil
+}
+
+// fetchCurrentGlobal returns the live global section as a manifest, or the
+// zero value when the API has none.
+func fetchCurrentGlobal() (GlobalConfig, error) {
+	obj, err := internal.GetResource("/services/haproxy/configuration/global")
+	if err != nil && !internal.IsNotFoundError(err) {
+		return GlobalConfig{}, fmt.Errorf("failed to fetch current global configuration: %w", err)
+	}
+	if obj == nil {
+		return GlobalConfig{}, nil
+	}
+	cfg := mapGlobalFromAPI(obj)
+	cfg.LogTargets, err = logtargets.Live(logtargets.ParentGlobal, "")
+	if err != nil && !internal.IsNotFoundError(err) {
+		return cfg, fmt.Errorf("failed to fetch global log targets: %w", err)
+	}
+	return cfg, nil
+}
+
+// fetchCurrentDefaults returns the first (primary) defaults section as a
+// manifest, or the zero value when there is none.
+func fetchCurrentDefaults() (DefaultsConfig, error) {
+	list, err := internal.GetResourceList("/services/haproxy/configuration/defaults")
+	if err != nil && !internal.IsNotFoundError(err) {
+		return DefaultsConfig{}, fmt.Errorf("failed to fetch current defaults configuration: %w", err)
+	}
+	if len(list) == 0 {
+		return DefaultsConfig{}, nil
+	}
+	return mapDefaultsFromAPI(list[0]), nil
+}
+
+// SectionManifests returns the global section (unless it is empty) and
+// every defaults section as manifests that apply accepts.
+func SectionManifests() ([]interface{}, error) {
+	var manifests []interface{}
+
+	global, err := fetchCurrentGlobal()
+	if err != nil {
+		return nil, err
+	}
+	if !global.isEmpty() {
+		manifests = append(manifests, global)
+	}
+
+	list, err := internal.GetResourceList("/services/haproxy/configuration/defaults")
+	if err != nil && !internal.IsNotFoundError(err) {
+		return nil, fmt.Errorf("failed to fetch defaults sections: %w", err)
+	}
+	for _, cfg := range orderDefaults(list) {
+		manifests = append(manifests, cfg)
+	}
+	return manifests, nil
+}
+
+// orderDefaults returns defaults sections sorted by name, except that a
+// section always follows the one it inherits from, so applying them in
+// order never references a section that does not exist yet.
+func orderDefaults(list []map[string]interface{}) []DefaultsConfig {
+	internal.SortByStringField(list, "name")
+	byName := make(map[string]DefaultsConfig, len(list))
+	for _, obj := range list {
+		cfg := mapDefaultsFromAPI(obj)
+		byName[cfg.Name] = cfg
+	}
+
+	ordered := make([]DefaultsConfig, 0, len(list))
+	done := make(map[string]bool, len(list))
+	var visit func(name string)
+	visit = func(name string) {
+		cfg, ok := byName[name]
+		if !ok || done[name] {
+			return
+		}
+		done[name] = true
+		visit(cfg.From)
+		ordered = append(ordered, cfg)
+	}
+	for _, obj := range list {
+		name, _ := obj["name"].(string)
+		visit(name)
+	}
+	return ordered
+}
+
+// compareConfig parses a section manifest and pairs it with the live
+// section, the way applyConfig compares them.
+func compareConfig[T any](data []byte, kind string, getCurrent func() (T, error), isEmpty func(T) bool) (internal.ManifestComparison, error) {
+	cmp := internal.ManifestComparison{Kind: kind, Name: "config"}
+
+	var manifest T
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return cmp, fmt.Errorf("failed to parse %s manifest: %w", strings.ToLower(kind), err)
+	}
+	cmp.Desired = manifest
+
+	current, err := getCurrent()
+	if err != nil {
+		return cmp, err
+	}
+	if !isEmpty(current) {
+		cmp.Live = current
+	}
+	return cmp, nil
+}
+
+// CompareGlobalManifest returns the live and desired state of a Global
+// manifest. Like apply, log targets are only compared when the manifest
+// lists them.
+func CompareGlobalManifest(data []byte) (internal.ManifestComparison, error) {
+	cmp, err := compareConfig(data, "Global", fetchCurrentGlobal, GlobalConfig.isEmpty)
+	if err != nil {
+		return cmp, err
+	}
+	if desired, ok := cmp.Desired.(GlobalConfig); ok && desired.LogTargets == nil {
+		if live, ok := cmp.Live.(GlobalConfig); ok {
+			live.LogTargets = nil
+			cmp.Live = live
+		}
+	}
+	return cmp, nil
+}
+
+// CompareDefaultsManifest returns the live and desired state of a Defaults
+// manifest. Like apply, a named manifest is compared with the section of
+// that name and a manifest without a name with the primary defaults section.
+func CompareDefaultsManifest(data []byte) (internal.ManifestComparison, error) {
+	var named struct {
+		Name string `yaml:"name"`
+	}
+	_ = yaml.Unmarshal(data, &named)
+
+	getCurrent := fetchCurrentDefaults
+	if named.Name != "" {
+		getCurrent = func() (DefaultsConfig, error) {
+			cfg, _, err := fetchDefaultsSection(named.Name)
+			return cfg, err
+		}
+	}
+	cmp, err := compareConfig(data, "Defaults", getCurrent, func(d DefaultsConfig) bool {
+		return d.Name == "" && d.isEmpty()
+	})
+	if err != nil {
+		return cmp, err
+	}
+	if desired, ok := cmp.Desired.(DefaultsConfig); ok && desired.Name == "" {
+		if live, ok := cmp.Live.(DefaultsConfig); ok {
+			desired.Name = live.Name
+			cmp.Desired = desired
+		}
+	}
+	return cmp, nil
+}
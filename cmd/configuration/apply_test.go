@@ -0,0 +1,211 @@
+package configuration
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testGlobalManifest = `apiVersion: haproxyctl/v1
+kind: Global
+maxconn: 2000
+`
+
+func TestApplyGlobalFromYAML_LogTargets(t *testing.T) {
+	srv := testserver.New(t)
+	srv.SetGlobal(map[string]interface{}{"maxconn": 2000})
+	srv.AddListItem("global", "", "log_targets", map[string]interface{}{"address": "/dev/log", "facility": "local0"})
+
+	// Without logTargets the live targets are left alone.
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyGlobalFromYAML([]byte(testGlobalManifest), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "global/config unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	withTargets := testGlobalManifest + `logTargets:
+  - address: /dev/log
+    facility: local0
+  - address: 10.0.0.5:514
+    facility: local1
+    level: notice
+`
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyGlobalFromYAML([]byte(withTargets), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	targets := srv.List("global", "", "log_targets")
+	if len(targets) != 2 || targets[1]["address"] != "10.0.0.5:514" || targets[1]["level"] != "notice" {
+		t.Fatalf("global log targets = %+v", targets)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyGlobalFromYAML([]byte(withTargets), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "global/config unchanged") {
+		t.Fatalf("expected unchanged status on re-apply, got:\n%s", output)
+	}
+
+	if err := ApplyGlobalFromYAML([]byte(testGlobalManifest+"logTargets:\n  - address: /dev/log\n"), "", false); err == nil {
+		t.Fatal("expected validation error for a target without facility")
+	}
+}
+
+const testNamedDefaultsManifest = `apiVersion: haproxyctl/v1
+kind: Defaults
+name: web
+from: base
+mode: http
+timeoutClient: 30s
+`
+
+func TestApplyDefaultsFromYAML_NamedWithFrom(t *testing.T) {
+	srv := testserver.New(t)
+
+	if err := ApplyDefaultsFromYAML([]byte(testNamedDefaultsManifest), "", false); err == nil {
+		t.Fatal("expected error while the from section does not exist")
+	}
+	if _, ok := srv.Defaults("web"); ok {
+		t.Fatal("defaults web created despite a missing from section")
+	}
+
+	srv.AddDefaults(map[string]interface{}{"name": "base", "mode": "tcp"})
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyDefaultsFromYAML([]byte(testNamedDefaultsManifest), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "defaults/web created") {
+		t.Fatalf("expected created status, got:\n%s", output)
+	}
+	if web, _ := srv.Defaults("web"); web["from"] != "base" || web["mode"] != "http" {
+		t.Fatalf("defaults web = %+v", web)
+	}
+	if base, _ := srv.Defaults("base"); base["mode"] != "tcp" {
+		t.Fatalf("defaults base changed: %+v", base)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyDefaultsFromYAML([]byte(testNamedDefaultsManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "defaults/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	cmp, err := CompareDefaultsManifest([]byte(testNamedDefaultsManifest))
+	if err != nil {
+		t.Fatalf("compare failed: %v", err)
+	}
+	if live, ok := cmp.Live.(DefaultsConfig); !ok || live.Name != "web" {
+		t.Fatalf("compared with %+v, want the web section", cmp.Live)
+	}
+}
+
+func TestOrderDefaults_ParentsFirst(t *testing.T) {
+	t.Parallel()
+
+	ordered := orderDefaults([]map[string]interface{}{
+		{"name": "api", "from": "web"},
+		{"name": "web", "from": "zbase"},
+		{"name": "zbase"},
+		{"name": "other"},
+	})
+	var names []string
+	for _, cfg := range ordered {
+		names = append(names, cfg.Name)
+	}
+	if got := strings.Join(names, ","); got != "zbase,web,api,other" {
+		t.Fatalf("order = %s, want zbase,web,api,other", got)
+	}
+}
+
+const testHardenedGlobalManifest = `apiVersion: haproxyctl/v1
+kind: Global
+maxconn: 2000
+nbthread: 4
+cpuMaps:
+  - process: auto:1/1-4
+    cpuSet: 0-3
+maxsslconn: 1000
+sslDefaultBindOptions: ssl-min-ver TLSv1.2 no-tls-tickets
+sslDefaultBindCiphers: ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256
+tuneOptions:
+  http_maxhdr: 128
+tuneSSLOptions:
+  cachesize: 20000
+runtimeAPIs:
+  - address: /var/run/haproxy/admin.sock
+    level: admin
+    mode: "660"
+    exposeFdListeners: true
+`
+
+func TestApplyGlobalFromYAML_Hardening(t *testing.T) {
+	srv := testserver.New(t)
+	srv.SetGlobal(map[string]interface{}{"maxconn": 2000})
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyGlobalFromYAML([]byte(testHardenedGlobalManifest), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "global/config configured") {
+		t.Fatalf("expected configured status, got:\n%s", output)
+	}
+
+	global := srv.Global()
+	ssl, _ := global["ssl_options"].(map[string]interface{})
+	perf, _ := global["performance_options"].(map[string]interface{})
+	apis, _ := global["runtime_apis"].([]interface{})
+	if global["nbthread"] != float64(4) || perf["maxsslconn"] != float64(1000) ||
+		ssl["default_bind_options"] != "ssl-min-ver TLSv1.2 no-tls-tickets" || len(apis) != 1 {
+		t.Fatalf("global = %+v", global)
+	}
+	if api, _ := apis[0].(map[string]interface{}); api["level"] != "admin" || api["expose_fd_listeners"] != true {
+		t.Fatalf("runtime API = %+v", apis[0])
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyGlobalFromYAML([]byte(testHardenedGlobalManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "global/config unchanged") {
+		t.Fatalf("expected unchanged status on re-apply, got:\n%s", output)
+	}
+}
+
+func TestGlobalConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     GlobalConfig
+		wantErr string
+	}{
+		{"valid", GlobalConfig{Nbthread: 2, RuntimeAPIs: []RuntimeAPI{{Address: "/run/haproxy.sock", Level: "admin"}}}, ""},
+		{"cpu map without set", GlobalConfig{CPUMaps: []CPUMap{{Process: "1"}}}, "cpuSet"},
+		{"runtime API level", GlobalConfig{RuntimeAPIs: []RuntimeAPI{{Address: "/run/a.sock", Level: "root"}}}, "level"},
+		{"duplicate runtime API", GlobalConfig{RuntimeAPIs: []RuntimeAPI{{Address: "/run/a.sock"}, {Address: "/run/a.sock"}}}, "duplicate"},
+		{"dotted tune key", GlobalConfig{TuneOptions: map[string]interface{}{"tune.http.maxhdr": 128}}, "http_maxhdr"},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: error = %v, want mention of %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -19,7 +19,6 @@ package configuration
 
 import (
 	"haproxyctl/internal"
-	"log"
 	"os"
 	"strconv"
 
@@ -54,30 +53,30 @@ Examples:
 		var path string
 		switch {
 		case fileFlag != "" && len(args) > 0:
-			log.Fatal("specify either a positional file or --file, not both")
+			internal.FatalCodef(internal.ExitUsage, "specify either a positional file or --file, not both")
 		case fileFlag != "":
 			path = fileFlag
 		case len(args) == 1:
 			path = args[0]
 		default:
-			log.Fatal("file path is required (positional or --file)")
+			internal.FatalCodef(internal.ExitUsage, "file path is required (positional or --file)")
 		}
 
 		// Read the raw HAProxy config. The path is explicitly provided
 		// by the user on the CLI, which is expected for this tool.
 		data, err := os.ReadFile(path) //nolint:gosec // CLI intentionally reads user-specified config path
 		if err != nil {
-			log.Fatalf("failed to read %s: %v", path, err)
+			internal.Fatalf("failed to read %s: %v", path, err)
 		}
 
 		// Fetch the current HAProxy config version
 		version, err := internal.GetConfigurationVersion()
 		if err != nil {
-			log.Fatalf("failed to fetch HAProxy configuration version: %v", err)
+			internal.Fatalf("failed to fetch HAProxy configuration version: %v", err)
 		}
 
 		// POST the raw config
-		endpoint := "/services/haproxy/configuration/raw"
+		endpoint := rawConfigPath
 		if _, err := internal.SendRawRequest(
 			"POST",
 			endpoint,
@@ -85,7 +84,7 @@ Examples:
 			data,
 			"text/plain",
 		); err != nil {
-			log.Fatalf("failed to push raw configuration: %v", err)
+			internal.Fatalf("failed to push raw configuration: %v", err)
 		}
 
 		internal.PrintStatus("Configuration", "raw", internal.ActionConfigured)
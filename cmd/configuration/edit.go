@@ -19,10 +19,11 @@ package configuration
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"haproxyctl/cmd/logtargets"
 	"haproxyctl/internal"
-	"log"
 	"os"
 	"strconv"
 	"strings"
@@ -49,20 +50,36 @@ var EditGlobalsCmd = &cobra.Command{
 	Short:   "Edit HAProxy global configuration in your editor",
 	Args:    cobra.NoArgs,
 	Run: func(_ *cobra.Command, _ []string) {
+		// The editor shows every log target, so a removed key means none.
+		var liveTargets []logtargets.LogTarget
 		if err := editSection(
 			"/services/haproxy/configuration/global",
 			"Global",
 			"haproxyctl-global-",
-			func(obj map[string]interface{}) interface{} { return mapGlobalFromAPI(obj) },
+			func(obj map[string]interface{}) interface{} {
+				g := mapGlobalFromAPI(obj)
+				targets, err := logtargets.Live(logtargets.ParentGlobal, "")
+				if err != nil {
+					internal.Warnf("failed to fetch global log targets: %v", err)
+				}
+				g.LogTargets, liveTargets = targets, targets
+				return g
+			},
 			func(version int, cfg interface{}) error {
 				g, ok := cfg.(GlobalConfig)
 				if !ok {
 					return fmt.Errorf("expected GlobalConfig, got %T", cfg)
 				}
-				return putGlobal(version, g)
+				if err := g.Validate(); err != nil {
+					return err
+				}
+				if err := putGlobal(version, g); err != nil {
+					return err
+				}
+				return logtargets.ApplyDiff(nil, logtargets.ParentGlobal, "", liveTargets, g.LogTargets)
 			},
 		); err != nil {
-			log.Fatalf("Edit globals failed: %v", err)
+			internal.Fatalf("Edit globals failed: %v", err)
 		}
 	},
 }
@@ -75,7 +92,27 @@ var EditDefaultsCmd = &cobra.Command{
 	Run: func(_ *cobra.Command, args []string) {
 		name := args[0]
 		if err := editDefaults(name); err != nil {
-			log.Fatalf("Edit defaults failed: %v", err)
+			internal.Fatalf("Edit defaults failed: %v", err)
+		}
+	},
+}
+
+// EditRawCmd represents "edit configuration raw".
+var EditRawCmd = &cobra.Command{
+	Use:   "raw",
+	Short: "Edit the raw HAProxy configuration in your editor",
+	Long: `Open the raw HAProxy configuration in $EDITOR and push it back when it
+changed. The change is sent against the configuration version the file was
+read at, so it fails instead of overwriting changes made in the meantime;
+the edited file is kept in that case so the work is not lost.
+
+Examples:
+  haproxyctl edit configuration raw
+  EDITOR=nano haproxyctl edit configuration raw`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if err := editRaw(cmd.Context()); err != nil {
+			internal.Fatalf("Edit raw configuration failed: %v", err)
 		}
 	},
 }
@@ -83,6 +120,82 @@ var EditDefaultsCmd = &cobra.Command{
 func init() {
 	EditConfigurationCmd.AddCommand(EditGlobalsCmd)
 	EditConfigurationCmd.AddCommand(EditDefaultsCmd)
+	EditConfigurationCmd.AddCommand(EditRawCmd)
+}
+
+// editRaw opens the raw configuration in the user's editor and posts it
+// back at the version it was read at.
+func editRaw(ctx context.Context) error {
+	version, err := internal.GetConfigurationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+	}
+	orig, err := internal.SendRequestWithContext(ctx, "GET", rawConfigPath, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch raw configuration: %w", err)
+	}
+	after, err := internal.GetConfigurationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+	}
+	if after != version {
+		return fmt.Errorf("configuration changed while it was being read (version %d -> %d); try again", version, after)
+	}
+
+	file, err := os.CreateTemp("", "haproxyctl-raw-*.cfg")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpFile := file.Name()
+	_, err = file.Write(orig)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	keep := false
+	defer func() {
+		if keep {
+			return
+		}
+		if rmErr := os.Remove(tmpFile); rmErr != nil {
+			internal.Warnf("failed to remove temp file %q: %v", tmpFile, rmErr)
+		}
+	}()
+
+	if err := internal.OpenInEditor(tmpFile); err != nil {
+		return err
+	}
+
+	edited, err := os.ReadFile(tmpFile) //nolint:gosec // tmpFile is controlled by this process
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	if bytes.Equal(orig, edited) {
+		internal.PrintStatus("Configuration", "raw", internal.ActionUnchanged)
+		return nil
+	}
+
+	if _, err := internal.SendRawRequestOnce(
+		ctx,
+		"POST",
+		rawConfigPath,
+		map[string]string{"version": strconv.Itoa(version)},
+		edited,
+		"text/plain",
+	); err != nil {
+		keep = true
+		if internal.IsVersionConflictError(err) {
+			return fmt.Errorf("configuration changed since version %d was opened for editing; your edits are kept in %s", version, tmpFile)
+		}
+		return fmt.Errorf("failed to push raw configuration (your edits are kept in %s): %w", tmpFile, err)
+	}
+
+	internal.PrintStatus("Configuration", "raw", internal.ActionConfigured)
+	return nil
 }
 
 // editDefaults opens a specific defaults section identified by name in the
@@ -115,7 +228,7 @@ func editDefaults(name string) error {
 	}
 	defer func() {
 		if rmErr := os.Remove(tmpFile); rmErr != nil {
-			log.Printf("warning: failed to remove temp file %q: %v", tmpFile, rmErr)
+			internal.Warnf("failed to remove temp file %q: %v", tmpFile, rmErr)
 		}
 	}()
 
@@ -145,6 +258,9 @@ func editDefaults(name string) error {
 	if edited.Name == "" {
 		edited.Name = name
 	}
+	if err := checkDefaultsFrom(edited.Name, edited.From); err != nil {
+		return err
+	}
 
 	if err := putDefaults(version, edited); err != nil {
 		return err
@@ -197,7 +313,7 @@ func editSection(
 	}
 	defer func() {
 		if rmErr := os.Remove(tmpFile); rmErr != nil {
-			log.Printf("warning: failed to remove temp file %q: %v", tmpFile, rmErr)
+			internal.Warnf("failed to remove temp file %q: %v", tmpFile, rmErr)
 		}
 	}()
 
@@ -263,6 +379,37 @@ func putGlobal(version int, cfg GlobalConfig) error {
 	if cfg.SpreadChecks != 0 {
 		payload["spread_checks"] = cfg.SpreadChecks
 	}
+	if cfg.Nbthread != 0 {
+		payload["nbthread"] = cfg.Nbthread
+	}
+	if len(cfg.CPUMaps) > 0 {
+		payload["cpu_maps"] = cfg.CPUMaps
+	}
+	if cfg.MaxSSLConn != 0 {
+		payload["performance_options"] = map[string]interface{}{"maxsslconn": cfg.MaxSSLConn}
+	}
+	ssl := map[string]interface{}{}
+	if cfg.SSLDefaultBindOptions != "" {
+		ssl["default_bind_options"] = cfg.SSLDefaultBindOptions
+	}
+	if cfg.SSLDefaultBindCiphers != "" {
+		ssl["default_bind_ciphers"] = cfg.SSLDefaultBindCiphers
+	}
+	if cfg.SSLDefaultBindCiphersuites != "" {
+		ssl["default_bind_ciphersuites"] = cfg.SSLDefaultBindCiphersuites
+	}
+	if len(ssl) > 0 {
+		payload["ssl_options"] = ssl
+	}
+	if len(cfg.TuneOptions) > 0 {
+		payload["tune_options"] = cfg.TuneOptions
+	}
+	if len(cfg.TuneSSLOptions) > 0 {
+		payload["tune_ssl_options"] = cfg.TuneSSLOptions
+	}
+	if len(cfg.RuntimeAPIs) > 0 {
+		payload["runtime_apis"] = cfg.RuntimeAPIs
+	}
 
 	_, err := internal.SendRequest(
 		"PUT",
@@ -281,8 +428,41 @@ func putDefaults(version int, cfg DefaultsConfig) error {
 		return errors.New("defaults name is required to update configuration")
 	}
 
-	payload := map[string]interface{}{}
+	endpoint := "/services/haproxy/configuration/defaults/" + cfg.Name
+
+	_, err := internal.SendRequest(
+		"PUT",
+		endpoint,
+		map[string]string{"version": strconv.Itoa(version)},
+		defaultsPayload(cfg),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update defaults configuration: %w", err)
+	}
+	return nil
+}
+
+// createDefaults adds a new named defaults section.
+func createDefaults(version int, cfg DefaultsConfig) error {
+	_, err := internal.SendRequest(
+		"POST",
+		"/services/haproxy/configuration/defaults",
+		map[string]string{"version": strconv.Itoa(version)},
+		defaultsPayload(cfg),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create defaults section %q: %w", cfg.Name, err)
+	}
+	return nil
+}
+
+// defaultsPayload converts a manifest into the Data Plane API object.
+func defaultsPayload(cfg DefaultsConfig) map[string]interface{} {
+	payload := map[string]interface{}{"name": cfg.Name}
 
+	if cfg.From != "" {
+		payload["from"] = cfg.From
+	}
 	if cfg.Mode != "" {
 		payload["mode"] = cfg.Mode
 	}
@@ -307,17 +487,5 @@ func putDefaults(version int, cfg DefaultsConfig) error {
 	if cfg.Log != "" {
 		payload["log"] = cfg.Log
 	}
-
-	endpoint := "/services/haproxy/configuration/defaults/" + cfg.Name
-
-	_, err := internal.SendRequest(
-		"PUT",
-		endpoint,
-		map[string]string{"version": strconv.Itoa(version)},
-		payload,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to update defaults configuration: %w", err)
-	}
-	return nil
+	return payload
 }
@@ -0,0 +1,91 @@
+package configuration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testRawConfig = "global\n  maxconn 100\n"
+
+// fakeEditor points $EDITOR at a script that runs body with the file to
+// edit as $1.
+func fakeEditor(t *testing.T, body string) {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"+body+"\n"), 0o700); err != nil { //nolint:gosec // test script must be executable
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", script)
+}
+
+func TestEditRaw(t *testing.T) {
+	srv := testserver.New(t)
+	srv.SetRawConfig(testRawConfig)
+	version := srv.Version()
+
+	fakeEditor(t, "exit 0")
+	output := internal.CaptureStdout(t, func() {
+		if err := editRaw(context.Background()); err != nil {
+			t.Fatalf("editRaw failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "configuration/raw unchanged") || srv.Version() != version {
+		t.Fatalf("expected no change, got version %d and:\n%s", srv.Version(), output)
+	}
+
+	fakeEditor(t, `echo "  nbthread 4" >> "$1"`)
+	output = internal.CaptureStdout(t, func() {
+		if err := editRaw(context.Background()); err != nil {
+			t.Fatalf("editRaw failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "configuration/raw configured") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if srv.RawConfig() != testRawConfig+"  nbthread 4\n" || srv.Version() != version+1 {
+		t.Fatalf("edit not pushed: version %d, config %q", srv.Version(), srv.RawConfig())
+	}
+}
+
+func TestEditRaw_KeepsEditsOnConflict(t *testing.T) {
+	srv := testserver.New(t)
+	srv.SetRawConfig(testRawConfig)
+
+	// The editor waits until someone else changed the configuration.
+	dir := t.TempDir()
+	t.Setenv("EDIT_SYNC_DIR", dir)
+	fakeEditor(t, `echo "  nbthread 4" >> "$1"
+touch "$EDIT_SYNC_DIR/ready"
+while [ ! -f "$EDIT_SYNC_DIR/go" ]; do sleep 0.05; done`)
+	go func() {
+		for {
+			if _, err := os.Stat(filepath.Join(dir, "ready")); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		params, _ := internal.WriteParams(nil)
+		_, _ = internal.SendRequest("POST", "/services/haproxy/configuration/backends", params, map[string]interface{}{"name": "other"})
+		_ = os.WriteFile(filepath.Join(dir, "go"), nil, 0o600)
+	}()
+
+	err := editRaw(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "edits are kept in ") {
+		t.Fatalf("expected a conflict keeping the edits, got %v", err)
+	}
+	kept := strings.TrimSpace(err.Error()[strings.LastIndex(err.Error(), " ")+1:])
+	t.Cleanup(func() { _ = os.Remove(kept) })
+	if data, rerr := os.ReadFile(kept); rerr != nil || !strings.Contains(string(data), "nbthread 4") { //nolint:gosec // path printed by editRaw
+		t.Fatalf("edited file not kept: %q, %v", data, rerr)
+	}
+	if srv.RawConfig() != testRawConfig {
+		t.Fatalf("stale edit overwrote the configuration: %q", srv.RawConfig())
+	}
+}
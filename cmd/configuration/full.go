@@ -0,0 +1,169 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configuration provides commands to manage HAProxy global configuration.
+package configuration
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+const configurationBase = "/services/haproxy/configuration"
+
+// rawConfigPath is the Data Plane API endpoint of the raw configuration.
+const rawConfigPath = configurationBase + "/raw"
+
+// optionalSections are list sections that are included in the full
+// configuration when the API exposes them and left out otherwise.
+var optionalSections = []string{
+	"userlists", "resolvers", "peers", "caches", "http_errors",
+	"rings", "log_forwards", "mailers_sections", "programs", "fcgi_apps",
+}
+
+// getConfigurationFullCmd fetches the whole configuration as one nested document.
+var getConfigurationFullCmd = &cobra.Command{
+	Use:     "full",
+	Aliases: []string{"structured"},
+	Short:   "Retrieves the whole HAProxy configuration as one structured document",
+	Long: `Retrieves global, defaults, frontends (with binds), backends (with servers)
+and the other configuration sections as one nested YAML/JSON document.
+
+The structured configuration endpoint is used when the Data Plane API
+provides it; otherwise the document is assembled from per-section GETs.
+Output defaults to YAML.
+
+Examples:
+  haproxyctl get configuration full -o yaml
+  haproxyctl get configuration full -o json --output-file haproxy-config.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		outputFormat := internal.GetFlagString(cmd, "output")
+		if outputFormat == "" {
+			outputFormat = internal.OutputFormatYAML
+		}
+
+		cfg, err := GetFullConfiguration()
+		if err != nil {
+			internal.Fatalf("Failed to fetch full configuration: %v", err)
+		}
+		internal.FormatOutputForCmd(cmd, cfg, outputFormat)
+	},
+}
+
+// GetFullConfiguration returns the structured configuration, falling back
+// to assembling it section by section on APIs without the structured
+// endpoint.
+func GetFullConfiguration() (map[string]interface{}, error) {
+	cfg, err := internal.GetResource(configurationBase + "/structured")
+	if err == nil {
+		return cfg, nil
+	}
+	if !internal.IsNotFoundError(err) {
+		return nil, err
+	}
+	return assembleFullConfiguration()
+}
+
+// assembleFullConfiguration builds the structured document from the
+// individual section endpoints.
+func assembleFullConfiguration() (map[string]interface{}, error) {
+	cfg := make(map[string]interface{})
+
+	global, err := internal.GetResource(configurationBase + "/global")
+	switch {
+	case err == nil:
+		cfg["global"] = global
+	case !internal.IsNotFoundError(err):
+		return nil, fmt.Errorf("failed to fetch global section: %w", err)
+	}
+
+	if err := addSectionList(cfg, "defaults", true); err != nil {
+		return nil, err
+	}
+	if err := addSectionList(cfg, "frontends", true); err != nil {
+		return nil, err
+	}
+	if err := addSectionList(cfg, "backends", true); err != nil {
+		return nil, err
+	}
+	if err := addChildren(cfg, "frontends", "binds"); err != nil {
+		return nil, err
+	}
+	if err := addChildren(cfg, "backends", "servers"); err != nil {
+		return nil, err
+	}
+
+	for _, section := range optionalSections {
+		if err := addSectionList(cfg, section, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// addSectionList stores the full_section list of a section in cfg. Missing
+// sections (404) are skipped; required sections are stored even when empty.
+func addSectionList(cfg map[string]interface{}, section string, required bool) error {
+	data, err := internal.SendRequest("GET", configurationBase+"/"+section, map[string]string{"full_section": "true"}, nil)
+	if err != nil {
+		if internal.IsNotFoundError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch %s: %w", section, err)
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", section, err)
+	}
+	if len(items) == 0 && !required {
+		return nil
+	}
+	cfg[section] = items
+	return nil
+}
+
+// addChildren attaches child objects (binds, servers) keyed by name, the
+// shape full_section uses, to every parent that does not already carry them.
+func addChildren(cfg map[string]interface{}, section, child string) error {
+	parents, _ := cfg[section].([]map[string]interface{})
+	for _, parent := range parents {
+		if _, ok := parent[child]; ok {
+			continue
+		}
+		name, _ := parent["name"].(string)
+		items, err := internal.GetResourceList(fmt.Sprintf("%s/%s/%s/%s", configurationBase, section, name, child))
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s of %s %q: %w", child, section, name, err)
+		}
+		if len(items) == 0 {
+			continue
+		}
+		byName := make(map[string]interface{}, len(items))
+		for _, item := range items {
+			itemName, _ := item["name"].(string)
+			byName[itemName] = item
+		}
+		parent[child] = byName
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package configuration
+
+import (
+	"testing"
+
+	"haproxyctl/internal/testserver"
+)
+
+func TestGetFullConfiguration_AssemblesSections(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "default_backend": "app"})
+	srv.AddBind("web", map[string]interface{}{"name": "http", "address": "0.0.0.0", "port": 80})
+	srv.AddBackend(map[string]interface{}{"name": "app", "balance": map[string]interface{}{"algorithm": "roundrobin"}})
+	srv.AddServer("app", map[string]interface{}{"name": "app1", "address": "10.0.0.1", "port": 8080})
+
+	cfg, err := GetFullConfiguration()
+	if err != nil {
+		t.Fatalf("GetFullConfiguration failed: %v", err)
+	}
+
+	frontends, _ := cfg["frontends"].([]map[string]interface{})
+	if len(frontends) != 1 || frontends[0]["name"] != "web" {
+		t.Fatalf("unexpected frontends: %+v", cfg["frontends"])
+	}
+	binds, _ := frontends[0]["binds"].(map[string]interface{})
+	if _, ok := binds["http"]; !ok {
+		t.Fatalf("binds not attached by name: %+v", frontends[0])
+	}
+
+	backends, _ := cfg["backends"].([]map[string]interface{})
+	if len(backends) != 1 {
+		t.Fatalf("unexpected backends: %+v", cfg["backends"])
+	}
+	servers, _ := backends[0]["servers"].(map[string]interface{})
+	if _, ok := servers["app1"]; !ok {
+		t.Fatalf("servers not attached by name: %+v", backends[0])
+	}
+
+	// Sections the API does not expose are left out rather than failing.
+	if _, ok := cfg["global"]; ok {
+		t.Fatalf("expected no global section from the fake API, got %+v", cfg["global"])
+	}
+	if _, ok := cfg["userlists"]; ok {
+		t.Fatalf("expected empty optional sections to be omitted")
+	}
+}
@@ -19,7 +19,6 @@ package configuration
 
 import (
 	"fmt"
-	"log"
 	"os"
 
 	"haproxyctl/internal"
@@ -48,7 +47,7 @@ var getConfigurationGlobalCmd = &cobra.Command{
 
 		obj, err := internal.GetResource("/services/haproxy/configuration/global")
 		if err != nil {
-			log.Fatalf("Failed to fetch global configuration: %v", err)
+			internal.Fatalf("Failed to fetch global configuration: %v", err)
 		}
 
 		cfg := mapGlobalFromAPI(obj)
@@ -65,37 +64,50 @@ var getConfigurationGlobalCmd = &cobra.Command{
 				"daemon":        cfg.Daemon,
 				"nbproc":        cfg.Nbproc,
 				"maxconn":       cfg.Maxconn,
+				"nbthread":      cfg.Nbthread,
+				"maxsslconn":    cfg.MaxSSLConn,
 				"log":           cfg.Log,
 				"log_send_host": cfg.LogSendHost,
 				"stats_socket":  cfg.StatsSocket,
 				"stats_timeout": cfg.StatsTimeout,
 				"spread_checks": cfg.SpreadChecks,
 			}
-			internal.FormatOutput(row, "")
+			internal.FormatOutputForCmd(cmd, row, "")
 			return
 		}
 
-		internal.FormatOutput(cfg, outputFormat)
+		internal.FormatOutputForCmd(cmd, cfg, outputFormat)
 	},
 }
 
-// getConfigurationDefaultsCmd fetches a named HAProxy defaults section via JSON.
+// getConfigurationDefaultsCmd lists the HAProxy defaults sections or fetches
+// a named one via JSON.
 var getConfigurationDefaultsCmd = &cobra.Command{
-	Use:   "defaults <name>",
+	Use:   "defaults [name]",
 	Short: "Retrieves HAProxy defaults configuration",
-	Long:  `Retrieve a specific HAProxy defaults section as table/JSON/YAML.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `List every HAProxy defaults section, or retrieve a specific one, as
+table/JSON/YAML. The "from" column shows the section a defaults section
+inherits from.
+
+Examples:
+  haproxyctl get configuration defaults
+  haproxyctl get configuration defaults web -o yaml`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		outputFormat := internal.GetFlagString(cmd, "output")
 
+		if len(args) == 0 {
+			listDefaults(cmd, outputFormat)
+			return
+		}
+
 		name := args[0]
 		obj, err := internal.GetResource("/services/haproxy/configuration/defaults/" + name)
 		if err != nil {
 			if internal.IsNotFoundError(err) {
-				_, _ = fmt.Fprintf(os.Stdout, "configuration/defaults %s not found\n", name)
-				return
+				internal.FatalCodef(internal.ExitNotFound, "configuration/defaults %s not found", name)
 			}
-			log.Fatalf("Failed to fetch defaults configuration %q: %v", name, err)
+			internal.Fatalf("Failed to fetch defaults configuration %q: %v", name, err)
 		}
 
 		cfg := mapDefaultsFromAPI(obj)
@@ -111,6 +123,7 @@ var getConfigurationDefaultsCmd = &cobra.Command{
 		if outputFormat == "" {
 			row := map[string]interface{}{
 				"name":            cfg.Name,
+				"from":            cfg.From,
 				"mode":            cfg.Mode,
 				"timeout_client":  cfg.TimeoutClient,
 				"timeout_server":  cfg.TimeoutServer,
@@ -120,14 +133,41 @@ var getConfigurationDefaultsCmd = &cobra.Command{
 				"balance":         cfg.Balance,
 				"log":             cfg.Log,
 			}
-			internal.FormatOutput(row, "")
+			internal.FormatOutputForCmd(cmd, row, "")
 			return
 		}
 
-		internal.FormatOutput(cfg, outputFormat)
+		internal.FormatOutputForCmd(cmd, cfg, outputFormat)
 	},
 }
 
+// listDefaults prints every defaults section, parents before the sections
+// that inherit from them.
+func listDefaults(cmd *cobra.Command, outputFormat string) {
+	list, err := internal.GetResourceList("/services/haproxy/configuration/defaults")
+	if err != nil {
+		internal.Fatalf("Failed to fetch defaults sections: %v", err)
+	}
+	sections := orderDefaults(list)
+
+	if outputFormat != "" {
+		internal.FormatOutputForCmd(cmd, sections, outputFormat)
+		return
+	}
+	rows := make([]map[string]interface{}, 0, len(sections))
+	for _, cfg := range sections {
+		rows = append(rows, map[string]interface{}{
+			"name":            cfg.Name,
+			"from":            cfg.From,
+			"mode":            cfg.Mode,
+			"timeout_client":  cfg.TimeoutClient,
+			"timeout_server":  cfg.TimeoutServer,
+			"timeout_connect": cfg.TimeoutConnect,
+		})
+	}
+	internal.FormatOutputForCmd(cmd, rows, "")
+}
+
 // getConfigurationVersionCmd fetches the HAProxy configuration version.
 var getConfigurationVersionCmd = &cobra.Command{
 	Use:   "version",
@@ -146,7 +186,7 @@ var getConfigurationRawCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, _ []string) {
 		data, err := GetConfigurationRaw(cmd)
 		if err != nil {
-			log.Fatalf("Failed to fetch raw configuration: %v", err)
+			internal.Fatalf("Failed to fetch raw configuration: %v", err)
 		}
 		cmd.Println(string(data))
 	},
@@ -158,7 +198,7 @@ func GetConfigurationVersion(cmd *cobra.Command) {
 
 	version, err := internal.GetConfigurationVersion()
 	if err != nil {
-		log.Fatalf("Failed to fetch HAProxy configuration version: %v", err)
+		internal.Fatalf("Failed to fetch HAProxy configuration version: %v", err)
 	}
 
 	// Build a structured object to support multiple output formats
@@ -169,12 +209,12 @@ func GetConfigurationVersion(cmd *cobra.Command) {
 		outputFormat = outputFormatJSON
 	}
 
-	internal.FormatOutput(versionData, outputFormat)
+	internal.FormatOutputForCmd(cmd, versionData, outputFormat)
 }
 
 // GetConfigurationRaw fetches the raw HAProxy configuration.
 func GetConfigurationRaw(cmd *cobra.Command) ([]byte, error) {
-	return internal.SendRequestWithContext(cmd.Context(), "GET", "/services/haproxy/configuration/raw", nil, nil)
+	return internal.SendRequestWithContext(cmd.Context(), "GET", rawConfigPath, nil, nil)
 }
 
 func init() {
@@ -183,4 +223,5 @@ func init() {
 	GetConfigurationCmd.AddCommand(getConfigurationRawCmd)
 	GetConfigurationCmd.AddCommand(getConfigurationGlobalCmd)
 	GetConfigurationCmd.AddCommand(getConfigurationDefaultsCmd)
+	GetConfigurationCmd.AddCommand(getConfigurationFullCmd)
 }
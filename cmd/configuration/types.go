@@ -1,5 +1,13 @@
 package configuration
 
+import (
+	"fmt"
+	"strings"
+
+	"haproxyctl/cmd/logtargets"
+	"haproxyctl/internal"
+)
+
 // mapGlobalFromAPI converts a generic API response for the "global" section
 // into a GlobalConfig manifest structure.
 func mapGlobalFromAPI(obj map[string]interface{}) GlobalConfig {
@@ -33,6 +41,45 @@ func mapGlobalFromAPI(obj map[string]interface{}) GlobalConfig {
 		cfg.SpreadChecks = v
 	}
 
+	if v, ok := getInt(obj, "nbthread"); ok {
+		cfg.Nbthread = v
+	}
+	if list, ok := obj["cpu_maps"].([]interface{}); ok {
+		for _, item := range list {
+			m, _ := item.(map[string]interface{})
+			process, _ := m["process"].(string)
+			cpuSet, _ := m["cpu_set"].(string)
+			cfg.CPUMaps = append(cfg.CPUMaps, CPUMap{Process: process, CPUSet: cpuSet})
+		}
+	}
+	if perf, ok := obj["performance_options"].(map[string]interface{}); ok {
+		if v, ok := getInt(perf, "maxsslconn"); ok {
+			cfg.MaxSSLConn = v
+		}
+	}
+	if ssl, ok := obj["ssl_options"].(map[string]interface{}); ok {
+		cfg.SSLDefaultBindOptions, _ = ssl["default_bind_options"].(string)
+		cfg.SSLDefaultBindCiphers, _ = ssl["default_bind_ciphers"].(string)
+		cfg.SSLDefaultBindCiphersuites, _ = ssl["default_bind_ciphersuites"].(string)
+	}
+	if m, ok := obj["tune_options"].(map[string]interface{}); ok && len(m) > 0 {
+		cfg.TuneOptions = wholeNumbers(m)
+	}
+	if m, ok := obj["tune_ssl_options"].(map[string]interface{}); ok && len(m) > 0 {
+		cfg.TuneSSLOptions = wholeNumbers(m)
+	}
+	if list, ok := obj["runtime_apis"].([]interface{}); ok {
+		for _, item := range list {
+			m, _ := item.(map[string]interface{})
+			api := RuntimeAPI{}
+			api.Address, _ = m["address"].(string)
+			api.Level, _ = m["level"].(string)
+			api.Mode, _ = m["mode"].(string)
+			api.ExposeFDListeners, _ = m["expose_fd_listeners"].(bool)
+			cfg.RuntimeAPIs = append(cfg.RuntimeAPIs, api)
+		}
+	}
+
 	return cfg
 }
 
@@ -48,6 +95,10 @@ func mapDefaultsFromAPI(obj map[string]interface{}) DefaultsConfig {
 		cfg.Name = v
 	}
 
+	if v, ok := obj["from"].(string); ok {
+		cfg.From = v
+	}
+
 	if v, ok := obj["mode"].(string); ok {
 		cfg.Mode = v
 	}
@@ -76,6 +127,19 @@ func mapDefaultsFromAPI(obj map[string]interface{}) DefaultsConfig {
 	return cfg
 }
 
+// wholeNumbers returns a copy of m with whole JSON numbers as ints, the way
+// YAML decodes them, so API options compare equal to a manifest's.
+func wholeNumbers(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if f, ok := v.(float64); ok && f == float64(int(f)) {
+			v = int(f)
+		}
+		out[k] = v
+	}
+	return out
+}
+
 // getInt extracts an integer field from a map that may contain JSON numbers
 // as float64 values.
 func getInt(obj map[string]interface{}, key string) (int, bool) {
@@ -114,6 +178,83 @@ type GlobalConfig struct {
 
 	// Misc tuning knobs
 	SpreadChecks int `yaml:"spreadChecks,omitempty" json:"spread_checks,omitempty"` //nolint:tagliatelle // JSON field comes from Data Plane API
+
+	// LogTargets are the global "log" lines. They live in their own API
+	// list, so apply only reconciles them when the manifest lists them.
+	LogTargets []logtargets.LogTarget `yaml:"logTargets,omitempty" json:"-"`
+
+	// Threads and CPU pinning ("nbthread", "cpu-map").
+	Nbthread int      `yaml:"nbthread,omitempty" json:"nbthread,omitempty"`
+	CPUMaps  []CPUMap `yaml:"cpuMaps,omitempty" json:"cpu_maps,omitempty"` //nolint:tagliatelle // Data Plane API field name
+
+	// TLS hardening: "maxsslconn" and the ssl-default-bind-* defaults
+	// every bind inherits.
+	MaxSSLConn                 int    `yaml:"maxsslconn,omitempty" json:"-"`
+	SSLDefaultBindOptions      string `yaml:"sslDefaultBindOptions,omitempty" json:"-"`
+	SSLDefaultBindCiphers      string `yaml:"sslDefaultBindCiphers,omitempty" json:"-"`
+	SSLDefaultBindCiphersuites string `yaml:"sslDefaultBindCiphersuites,omitempty" json:"-"`
+
+	// TuneOptions and TuneSSLOptions are the tune.* and tune.ssl.*
+	// settings, keyed by their Data Plane API names (e.g. http_maxhdr,
+	// cachesize) and sent as-is.
+	TuneOptions    map[string]interface{} `yaml:"tuneOptions,omitempty" json:"-"`
+	TuneSSLOptions map[string]interface{} `yaml:"tuneSSLOptions,omitempty" json:"-"`
+
+	// RuntimeAPIs are the "stats socket" lines of the runtime API.
+	RuntimeAPIs []RuntimeAPI `yaml:"runtimeAPIs,omitempty" json:"-"`
+}
+
+// CPUMap is a "cpu-map <process> <cpu_set>" line.
+type CPUMap struct {
+	Process string `yaml:"process" json:"process"`
+	CPUSet  string `yaml:"cpuSet" json:"cpu_set"` //nolint:tagliatelle // Data Plane API field name
+}
+
+// RuntimeAPI is a runtime API socket ("stats socket <address> ...").
+type RuntimeAPI struct {
+	Address           string `yaml:"address" json:"address"`
+	Level             string `yaml:"level,omitempty" json:"level,omitempty"`
+	Mode              string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	ExposeFDListeners bool   `yaml:"exposeFdListeners,omitempty" json:"expose_fd_listeners,omitempty"` //nolint:tagliatelle // Data Plane API field name
+}
+
+// Validate checks the settings the API would otherwise reject with a less
+// helpful message.
+func (g GlobalConfig) Validate() error {
+	if g.Nbthread < 0 || g.MaxSSLConn < 0 {
+		return fmt.Errorf("nbthread and maxsslconn must not be negative")
+	}
+	for i, m := range g.CPUMaps {
+		if m.Process == "" || m.CPUSet == "" {
+			return fmt.Errorf("cpuMaps[%d]: process and cpuSet are required", i)
+		}
+	}
+	seen := make(map[string]bool, len(g.RuntimeAPIs))
+	for i, api := range g.RuntimeAPIs {
+		if api.Address == "" {
+			return fmt.Errorf("runtimeAPIs[%d]: address is required", i)
+		}
+		if seen[api.Address] {
+			return fmt.Errorf("runtimeAPIs[%d]: duplicate address %q", i, api.Address)
+		}
+		seen[api.Address] = true
+		switch api.Level {
+		case "", "user", "operator", "admin":
+		default:
+			return fmt.Errorf("runtimeAPIs[%d]: invalid level %q (allowed: user, operator, admin)", i, api.Level)
+		}
+	}
+	for key := range g.TuneOptions {
+		if strings.Contains(key, ".") {
+			return fmt.Errorf("tuneOptions key %q: use the Data Plane API name (e.g. http_maxhdr for tune.http.maxhdr)", key)
+		}
+	}
+	for key := range g.TuneSSLOptions {
+		if strings.Contains(key, ".") {
+			return fmt.Errorf("tuneSSLOptions key %q: use the Data Plane API name (e.g. cachesize for tune.ssl.cachesize)", key)
+		}
+	}
+	return logtargets.ValidateAll(g.LogTargets)
 }
 
 // DefaultsConfig represents a minimal, manifest-friendly view of the
@@ -123,6 +264,8 @@ type DefaultsConfig struct {
 	Kind       string `yaml:"kind,omitempty" json:"-"`
 
 	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// From names the defaults section this one inherits from.
+	From string `yaml:"from,omitempty" json:"from,omitempty"`
 
 	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
 
@@ -136,6 +279,22 @@ type DefaultsConfig struct {
 	Log     string `yaml:"log,omitempty" json:"log,omitempty"`
 }
 
+// Validate checks the mode and timeouts of a defaults section.
+func (d DefaultsConfig) Validate() error {
+	switch d.Mode {
+	case "", "http", "tcp":
+	default:
+		return fmt.Errorf("invalid mode %q (allowed: http, tcp)", d.Mode)
+	}
+	return internal.ValidateDurations(map[string]string{
+		"timeoutClient":  d.TimeoutClient,
+		"timeoutServer":  d.TimeoutServer,
+		"timeoutConnect": d.TimeoutConnect,
+		"timeoutQueue":   d.TimeoutQueue,
+		"timeoutTunnel":  d.TimeoutTunnel,
+	})
+}
+
 // isEmpty reports whether the GlobalConfig has no meaningful settings
 // (i.e., all fields other than apiVersion/kind are zero values).
 func (g GlobalConfig) isEmpty() bool {
@@ -146,12 +305,23 @@ func (g GlobalConfig) isEmpty() bool {
 		g.LogSendHost == "" &&
 		g.StatsSocket == "" &&
 		g.StatsTimeout == "" &&
-		g.SpreadChecks == 0
+		g.SpreadChecks == 0 &&
+		len(g.LogTargets) == 0 &&
+		g.Nbthread == 0 &&
+		len(g.CPUMaps) == 0 &&
+		g.MaxSSLConn == 0 &&
+		g.SSLDefaultBindOptions == "" &&
+		g.SSLDefaultBindCiphers == "" &&
+		g.SSLDefaultBindCiphersuites == "" &&
+		len(g.TuneOptions) == 0 &&
+		len(g.TuneSSLOptions) == 0 &&
+		len(g.RuntimeAPIs) == 0
 }
 
 // isEmpty reports whether the DefaultsConfig has no meaningful settings.
 func (d DefaultsConfig) isEmpty() bool {
-	return d.Mode == "" &&
+	return d.From == "" &&
+		d.Mode == "" &&
 		d.TimeoutClient == "" &&
 		d.TimeoutServer == "" &&
 		d.TimeoutConnect == "" &&
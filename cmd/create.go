@@ -19,18 +19,30 @@ package cmd
 
 import (
 	"fmt"
+	"haproxyctl/cmd/acls"
 	"haproxyctl/cmd/backends"
+	"haproxyctl/cmd/caches"
 	"haproxyctl/cmd/certificates"
 	"haproxyctl/cmd/configuration"
+	"haproxyctl/cmd/crtlists"
+	"haproxyctl/cmd/filters"
 	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/httprules"
+	"haproxyctl/cmd/logforwards"
+	"haproxyctl/cmd/logtargets"
+	"haproxyctl/cmd/resolvers"
+	"haproxyctl/cmd/rings"
 	"haproxyctl/cmd/servers"
+	"haproxyctl/cmd/spoe"
+	"haproxyctl/cmd/storage"
+	"haproxyctl/cmd/templates"
+	"haproxyctl/cmd/transactions"
 	"haproxyctl/cmd/userlists"
-	"log"
-	"os"
+	"haproxyctl/internal"
 	"strings"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 )
 
 var createFile string
@@ -39,43 +51,60 @@ var createFile string
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a resource in HAProxy",
+	Long: `Create resources from positional arguments or from a manifest file.
+
+With -f, the file may hold several documents separated by "---"; they are
+created in dependency order (backends before their servers).`,
 	RunE: func(_ *cobra.Command, _ []string) error {
 		if createFile != "" {
 			return createFromFile(createFile)
 		}
 
-		log.Fatal("Specify a resource type (backends, servers) and its name, or use '-f' to create from file.")
+		internal.FatalCodef(internal.ExitUsage, "Specify a resource type (backends, servers) and its name, or use '-f' to create from file.")
 		return nil
 	},
 }
 
 func createFromFile(filepath string) error {
-	// The CLI is expected to read user-specified manifest files.
-	data, err := os.ReadFile(filepath) //nolint:gosec // filepath comes from user input by design
+	manifests, err := readManifests(filepath, manifestOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to read file %s: %w", filepath, err)
+		return err
 	}
 
-	// Extract kind and apiVersion to figure out what to do.
-	var metadata struct {
-		APIVersion string `yaml:"apiVersion"`
-		Kind       string `yaml:"kind"`
-	}
-	if err := yaml.Unmarshal(data, &metadata); err != nil {
-		return fmt.Errorf("failed to parse YAML file: %w", err)
+	for _, m := range manifests {
+		if err := createManifest(m); err != nil {
+			return fmt.Errorf("%s: %w", m, err)
+		}
 	}
+	return nil
+}
 
-	kind := strings.ToLower(metadata.Kind)
-
-	switch kind {
-	case "backend":
-		return backends.CreateBackendFromFile(data)
-	case "server":
-		return servers.CreateServerFromFile(data)
-	case "userlist":
-		return userlists.CreateUserlistFromFile(data)
+func createManifest(m internal.Manifest) error {
+	switch strings.ToLower(m.Kind) {
+	case kindBackend:
+		return backends.CreateBackendFromFile(m.Data)
+	case kindServer:
+		return servers.CreateServerFromFile(m.Data)
+	case kindUserlist:
+		return userlists.CreateUserlistFromFile(m.Data)
+	case kindResolver:
+		return resolvers.CreateResolverFromFile(m.Data)
+	case kindCache:
+		return caches.CreateCacheFromFile(m.Data)
+	case kindHTTPErrors:
+		return httperrors.CreateHTTPErrorsFromFile(m.Data)
+	case kindRing:
+		return rings.CreateRingFromFile(m.Data)
+	case kindLogForward:
+		return logforwards.CreateLogForwardFromFile(m.Data)
+	case kindSPOE:
+		return spoe.CreateSPOEFromFile(m.Data)
+	case kindACL:
+		return acls.CreateACLsFromFile(m.Data)
+	case kindHTTPRequestRule, kindHTTPResponseRule:
+		return httprules.CreateRulesFromFile(m.Data)
 	default:
-		return fmt.Errorf("unsupported resource kind: %s (supported: Backend, Server, Userlist)", metadata.Kind)
+		return fmt.Errorf("unsupported resource kind: %s (supported: Backend, Server, Userlist, Resolver, Cache, Ring, LogForward, SPOE, ACL, HTTPRequestRule, HTTPResponseRule)", m.Kind)
 	}
 }
 
@@ -83,12 +112,38 @@ func init() {
 	rootCmd.AddCommand(createCmd)
 
 	// Add subcommands for explicit CLI resource creation (with positional args).
+	createCmd.AddCommand(acls.CreateACLsCmd)
 	createCmd.AddCommand(backends.CreateBackendsCmd)
 	createCmd.AddCommand(certificates.CreateCertificatesCmd)
+	createCmd.AddCommand(crtlists.CreateCrtListsCmd)
 	createCmd.AddCommand(servers.CreateServersCmd)
 	createCmd.AddCommand(frontends.CreateFrontendsCmd)
+	createCmd.AddCommand(httprules.CreateHTTPRequestRulesCmd)
+	createCmd.AddCommand(httprules.CreateHTTPResponseRulesCmd)
+	createCmd.AddCommand(frontends.CreateSwitchingRuleCmd)
+	createCmd.AddCommand(backends.CreateServerSwitchingRuleCmd)
+	createCmd.AddCommand(backends.CreateStickRuleCmd)
+	createCmd.AddCommand(backends.CreateHTTPCheckCmd)
+	createCmd.AddCommand(backends.CreateTCPCheckCmd)
+	createCmd.AddCommand(filters.CreateFiltersCmd)
+	createCmd.AddCommand(logtargets.CreateLogTargetsCmd)
+	createCmd.AddCommand(frontends.CreateCaptureCmd)
+	createCmd.AddCommand(resolvers.CreateResolversCmd)
+	createCmd.AddCommand(resolvers.CreateNameserverCmd)
+	createCmd.AddCommand(userlists.CreateUsersCmd)
+	createCmd.AddCommand(userlists.CreateGroupsCmd)
+	createCmd.AddCommand(caches.CreateCachesCmd)
+	createCmd.AddCommand(httperrors.CreateHTTPErrorsCmd)
+	createCmd.AddCommand(httperrors.CreateErrorFilesCmd)
+	createCmd.AddCommand(storage.CreateStorageFilesCmd)
+	createCmd.AddCommand(rings.CreateRingsCmd)
+	createCmd.AddCommand(logforwards.CreateLogForwardsCmd)
+	createCmd.AddCommand(spoe.CreateSPOECmd)
 	createCmd.AddCommand(configuration.CreateConfigurationCmd)
+	createCmd.AddCommand(templates.CreateFromTemplateCmd)
+	createCmd.AddCommand(transactions.CreateTransactionsCmd)
 
 	// Global flag for file-based creation (works for multiple resource kinds)
-	createCmd.Flags().StringVarP(&createFile, "file", "f", "", "Create resource from YAML file (supports kind: Backend, Server, and Userlist)")
+	createCmd.Flags().StringVarP(&createFile, "file", "f", "", "Create resource from a YAML or JSON file (supports kind: Backend, Server, Userlist, Resolver, Cache, Ring, LogForward, SPOE, ACL, HTTPRequestRule, and HTTPResponseRule)")
+	addWaitFlags(createCmd)
 }
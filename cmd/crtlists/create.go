@@ -0,0 +1,95 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crtlists provides commands to manage crt-list files in the Data
+// Plane API SSL storage, which map certificates to SNI names for binds.
+package crtlists
+
+import (
+	"os"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateCrtListsCmd represents "create crt-lists <name>".
+var CreateCrtListsCmd = &cobra.Command{
+	Use:     "crt-lists <name> (--from-file <path> | --entry <line>...)",
+	Aliases: []string{"crt-list"},
+	Short:   "Upload a crt-list to the SSL storage",
+	Long: `Upload a crt-list to the Data Plane API SSL storage, from a local file or
+from --entry lines. Each line names a certificate, optionally followed by
+SSL options in brackets and the SNI names it is served for:
+
+  /etc/haproxy/ssl/site.pem [alpn h2,http/1.1] example.com www.example.com
+
+A crt-list of the same name is replaced when its contents differ, so running
+the command again is safe. Reference the list from a bind with
+crt_list=<path>, using the path "haproxyctl get crt-lists" prints.
+
+Examples:
+  haproxyctl create crt-lists sites.txt --from-file ./sites.txt
+  haproxyctl create crt-lists sites.txt \
+    --entry "/etc/haproxy/ssl/site.pem example.com www.example.com" \
+    --entry "/etc/haproxy/ssl/default.pem"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := internal.GetFlagString(cmd, "from-file")
+		lines, _ := cmd.Flags().GetStringArray("entry")
+		if (source == "") == (len(lines) == 0) {
+			internal.FatalCodef(internal.ExitUsage, "Specify either --from-file or --entry")
+		}
+
+		var data []byte
+		if source != "" {
+			var err error
+			data, err = os.ReadFile(source) //nolint:gosec // path comes from explicit CLI input
+			if err != nil {
+				internal.Fatalf("Failed to read %s: %v", source, err)
+			}
+		} else {
+			entries := make([]Entry, 0, len(lines))
+			for _, line := range lines {
+				e, err := ParseEntry(line)
+				if err != nil {
+					internal.FatalCodef(internal.ExitUsage, "Invalid --entry: %v", err)
+				}
+				entries = append(entries, e)
+			}
+			data = FormatCrtList(entries)
+		}
+
+		if internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline() {
+			entries, err := ParseCrtList(data)
+			if err != nil {
+				internal.FatalCodef(internal.ExitInvalid, "Invalid crt-list: %v", err)
+			}
+			internal.FormatOutput(map[string]interface{}{"name": args[0], "entries": entries}, internal.OutputFormatYAML)
+			internal.PrintDryRun()
+			return
+		}
+		if _, err := Upload(cmd.Context(), args[0], data); err != nil {
+			internal.Fatalf("Failed to upload crt-list %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateCrtListsCmd.Flags().String("from-file", "", "Local crt-list file to upload")
+	CreateCrtListsCmd.Flags().StringArray("entry", nil, "crt-list line: <certificate> [[ssl options]] [sni...] (repeatable)")
+	CreateCrtListsCmd.Flags().Bool("dry-run", false, "Show the entries without uploading them")
+}
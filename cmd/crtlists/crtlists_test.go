@@ -0,0 +1,108 @@
+package crtlists
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestParseEntry(t *testing.T) {
+	t.Parallel()
+
+	e, err := ParseEntry("/etc/haproxy/ssl/site.pem [alpn h2,http/1.1 ocsp-update on] example.com !old.example.com")
+	if err != nil {
+		t.Fatalf("ParseEntry failed: %v", err)
+	}
+	if e.File != "/etc/haproxy/ssl/site.pem" || e.SSLBindConfig != "alpn h2,http/1.1 ocsp-update on" ||
+		len(e.SNIFilter) != 2 || e.SNIFilter[1] != "!old.example.com" {
+		t.Fatalf("ParseEntry = %+v", e)
+	}
+	if got := e.String(); got != "/etc/haproxy/ssl/site.pem [alpn h2,http/1.1 ocsp-update on] example.com !old.example.com" {
+		t.Fatalf("String() = %q", got)
+	}
+
+	for _, bad := range []string{"", "[alpn h2] /etc/haproxy/ssl/site.pem", "/a.pem [alpn h2", "/a.pem example.com [alpn h2]"} {
+		if _, err := ParseEntry(bad); err == nil {
+			t.Errorf("ParseEntry(%q): expected error", bad)
+		}
+	}
+
+	entries, err := ParseCrtList([]byte("# sites\n\n/a.pem a.example\n/b.pem\n"))
+	if err != nil || len(entries) != 2 || entries[1].File != "/b.pem" {
+		t.Fatalf("ParseCrtList = %+v, %v", entries, err)
+	}
+	if _, err := ParseCrtList([]byte("/a.pem\n[x\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected line number in error, got %v", err)
+	}
+}
+
+func TestUpload_CreatesAndIsIdempotent(t *testing.T) {
+	srv := testserver.New(t)
+	ctx := context.Background()
+	data := []byte("/etc/haproxy/ssl/site.pem example.com\n")
+
+	var path string
+	output := internal.CaptureStdout(t, func() {
+		var err error
+		if path, err = Upload(ctx, "sites.txt", data); err != nil {
+			t.Fatalf("Upload failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "crtlist/sites.txt created") || path != "/etc/haproxy/ssl/sites.txt" {
+		t.Fatalf("unexpected output %q, path %q", output, path)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if _, err := Upload(ctx, "sites.txt", data); err != nil {
+			t.Fatalf("second Upload failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "crtlist/sites.txt unchanged") {
+		t.Fatalf("expected unchanged, got:\n%s", output)
+	}
+
+	changed := []byte("/etc/haproxy/ssl/site.pem example.com www.example.com\n")
+	output = internal.CaptureStdout(t, func() {
+		if _, err := Upload(ctx, "sites.txt", changed); err != nil {
+			t.Fatalf("third Upload failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "crtlist/sites.txt configured") {
+		t.Fatalf("expected configured, got:\n%s", output)
+	}
+	if stored, _ := srv.CrtListFile("sites.txt"); string(stored) != string(changed) {
+		t.Fatalf("stored crt-list = %q", stored)
+	}
+
+	if _, err := Upload(ctx, "bad.txt", []byte("[alpn h2]\n")); err == nil {
+		t.Fatal("expected error for an invalid crt-list")
+	}
+}
+
+func TestEditCrtList_AddReplaceRemove(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddCrtList("sites.txt",
+		map[string]interface{}{"file": "/etc/haproxy/ssl/site.pem", "sni_filter": []string{"example.com"}},
+		map[string]interface{}{"file": "/etc/haproxy/ssl/old.pem"},
+	)
+
+	_ = internal.CaptureStdout(t, func() {
+		err := EditCrtList(context.Background(), "sites.txt",
+			[]string{"/etc/haproxy/ssl/site.pem [alpn h2] example.com www.example.com", "/etc/haproxy/ssl/api.pem api.example.com"},
+			[]string{"/etc/haproxy/ssl/old.pem"}, false)
+		if err != nil {
+			t.Fatalf("EditCrtList failed: %v", err)
+		}
+	})
+	want := "/etc/haproxy/ssl/site.pem [alpn h2] example.com www.example.com\n/etc/haproxy/ssl/api.pem api.example.com\n"
+	if stored, _ := srv.CrtListFile("sites.txt"); string(stored) != want {
+		t.Fatalf("stored crt-list = %q, want %q", stored, want)
+	}
+
+	if err := EditCrtList(context.Background(), "sites.txt", nil, []string{"/etc/haproxy/ssl/missing.pem"}, false); err == nil {
+		t.Fatal("expected error when removing an entry that does not exist")
+	}
+}
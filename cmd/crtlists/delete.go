@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crtlists provides commands to manage crt-list files in the Data
+// Plane API SSL storage, which map certificates to SNI names for binds.
+package crtlists
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteCrtListsCmd represents "delete crt-lists <name>".
+var DeleteCrtListsCmd = &cobra.Command{
+	Use:     "crt-lists <name>",
+	Aliases: []string{"crt-list"},
+	Short:   "Delete a crt-list from the SSL storage",
+	Long: `Delete a crt-list from the SSL storage. Binds that still reference it
+must be changed first, or HAProxy rejects the configuration. The
+certificates it lists are not deleted.
+
+Examples:
+  haproxyctl delete crt-lists sites.txt`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := internal.SendRequestWithContext(cmd.Context(), "DELETE", crtListsPath+"/"+args[0], nil, nil); err != nil {
+			internal.Fatalf("Failed to delete crt-list %q: %v", args[0], internal.FormatAPIError(crtListKind, args[0], "delete", err))
+		}
+		internal.PrintStatus(crtListKind, args[0], internal.ActionDeleted)
+	},
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crtlists provides commands to manage crt-list files in the Data
+// Plane API SSL storage, which map certificates to SNI names for binds.
+package crtlists
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// EditCrtListsCmd represents "edit crt-lists <name>".
+var EditCrtListsCmd = &cobra.Command{
+	Use:     "crt-lists <name>",
+	Aliases: []string{"crt-list"},
+	Short:   "Edit a crt-list in your editor or add and remove entries",
+	Long: `Open a crt-list in your editor and replace it with the result, or change
+it in place with --add-entry and --remove-entry. An added entry replaces the
+entry for the same certificate, so it also updates SNI names and SSL
+options; --remove-entry takes the certificate path. The list is checked
+before it is uploaded and left alone when nothing changed.
+
+Examples:
+  haproxyctl edit crt-lists sites.txt
+  haproxyctl edit crt-lists sites.txt --add-entry "/etc/haproxy/ssl/api.pem api.example.com"
+  haproxyctl edit crt-lists sites.txt --remove-entry /etc/haproxy/ssl/old.pem`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		add, _ := cmd.Flags().GetStringArray("add-entry")
+		remove, _ := cmd.Flags().GetStringArray("remove-entry")
+		dryRun := internal.GetFlagBool(cmd, "dry-run")
+
+		var err error
+		if len(add) == 0 && len(remove) == 0 {
+			err = editInEditor(cmd.Context(), args[0])
+		} else {
+			err = EditCrtList(cmd.Context(), args[0], add, remove, dryRun)
+		}
+		if err != nil {
+			internal.Fatalf("Failed to edit crt-list %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	EditCrtListsCmd.Flags().StringArray("add-entry", nil, "crt-list line to add or replace (repeatable)")
+	EditCrtListsCmd.Flags().StringArray("remove-entry", nil, "Certificate path whose entry to remove (repeatable)")
+	EditCrtListsCmd.Flags().Bool("dry-run", false, "Print the resulting crt-list without uploading it")
+}
+
+// EditCrtList adds, replaces and removes entries of a crt-list. Entries
+// are matched by certificate path.
+func EditCrtList(ctx context.Context, name string, add, remove []string, dryRun bool) error {
+	current, err := Contents(ctx, name)
+	if err != nil {
+		return internal.FormatAPIError(crtListKind, name, "get", err)
+	}
+	entries, err := ParseCrtList(current)
+	if err != nil {
+		return fmt.Errorf("stored crt-list is invalid: %w", err)
+	}
+
+	for _, file := range remove {
+		kept := entries[:0]
+		for _, e := range entries {
+			if e.File != file {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) == len(entries) {
+			return fmt.Errorf("no entry for %s", file)
+		}
+		entries = kept
+	}
+	for _, line := range add {
+		e, err := ParseEntry(line)
+		if err != nil {
+			return err
+		}
+		replaced := false
+		for i := range entries {
+			if entries[i].File == e.File {
+				entries[i], replaced = e, true
+			}
+		}
+		if !replaced {
+			entries = append(entries, e)
+		}
+	}
+
+	if dryRun {
+		_, _ = os.Stdout.Write(FormatCrtList(entries))
+		internal.PrintDryRun()
+		return nil
+	}
+	_, err = Upload(ctx, name, FormatCrtList(entries))
+	return err
+}
+
+// editInEditor opens the crt-list text in the user's editor and uploads
+// the edited version.
+func editInEditor(ctx context.Context, name string) error {
+	current, err := Contents(ctx, name)
+	if err != nil {
+		return internal.FormatAPIError(crtListKind, name, "get", err)
+	}
+
+	file, err := os.CreateTemp("", "haproxyctl-crtlist-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpFile := file.Name()
+	defer func() {
+		if rmErr := os.Remove(tmpFile); rmErr != nil {
+			internal.Warnf("failed to remove temp file %q: %v", tmpFile, rmErr)
+		}
+	}()
+	_, err = file.Write(current)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := internal.OpenInEditor(tmpFile); err != nil {
+		return err
+	}
+	edited, err := os.ReadFile(tmpFile) //nolint:gosec // tmpFile is controlled by this process
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %w", err)
+	}
+	_, err = Upload(ctx, name, edited)
+	return err
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crtlists provides commands to manage crt-list files in the Data
+// Plane API SSL storage, which map certificates to SNI names for binds.
+package crtlists
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetCrtListsCmd represents "get crt-lists [name]".
+var GetCrtListsCmd = &cobra.Command{
+	Use:     "crt-lists [name]",
+	Aliases: []string{"crt-list"},
+	Short:   "List crt-lists or show the entries of one",
+	Long: `List the crt-lists in the Data Plane API SSL storage with the path binds
+reference them by, or show the entries of one: the certificate, its SSL
+options and the SNI names it is served for.
+
+Examples:
+  haproxyctl get crt-lists
+  haproxyctl get crt-lists sites.txt
+  haproxyctl get crt-lists sites.txt -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFormat := internal.GetFlagString(cmd, "output")
+
+		if len(args) == 0 {
+			list, err := Lists(cmd.Context())
+			if err != nil {
+				internal.Fatalf("Failed to fetch crt-lists: %v", err)
+			}
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(list, crtListColumns), outputFormat)
+			return
+		}
+
+		entries, err := internal.GetResourceList(crtListsPath + "/" + args[0] + "/entries")
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(crtListKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch crt-list %q: %v", args[0], err)
+		}
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(entries, crtListEntryColumns), outputFormat)
+	},
+}
+
+// crtListColumns shows each crt-list with the path binds reference.
+var crtListColumns = internal.ColumnSet{
+	Kind:    crtListKind,
+	Default: []string{"storage_name", "file"},
+	Wide:    []string{"size", "description"},
+}
+
+// crtListEntryColumns are the columns of one crt-list's entries.
+var crtListEntryColumns = internal.ColumnSet{
+	Default: []string{"file", "ssl_bind_config", "sni_filter"},
+	Wide:    []string{"line_number"},
+}
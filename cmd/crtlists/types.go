@@ -0,0 +1,179 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crtlists provides commands to manage crt-list files in the Data
+// Plane API SSL storage, which map certificates to SNI names for binds.
+package crtlists
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	crtListKind = "CrtList"
+
+	crtListsPath = "/services/haproxy/storage/ssl_crt_lists"
+)
+
+// Entry is one line of a crt-list:
+//
+//	<certificate> [<ssl bind options>] [<sni filter> ...]
+//
+//nolint:tagliatelle
+type Entry struct {
+	File          string   `json:"file" yaml:"file"`
+	SSLBindConfig string   `json:"ssl_bind_config,omitempty" yaml:"ssl_bind_config,omitempty"`
+	SNIFilter     []string `json:"sni_filter,omitempty" yaml:"sni_filter,omitempty"`
+}
+
+// String renders the entry as a crt-list line.
+func (e Entry) String() string {
+	parts := []string{e.File}
+	if e.SSLBindConfig != "" {
+		parts = append(parts, "["+e.SSLBindConfig+"]")
+	}
+	return strings.Join(append(parts, e.SNIFilter...), " ")
+}
+
+// ParseEntry parses a crt-list line.
+func ParseEntry(line string) (Entry, error) {
+	line = strings.TrimSpace(line)
+	file, rest, _ := strings.Cut(line, " ")
+	if file == "" || strings.HasPrefix(file, "[") {
+		return Entry{}, fmt.Errorf("entry %q must start with a certificate file", line)
+	}
+	e := Entry{File: file}
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "[") {
+		opts, after, ok := strings.Cut(rest[1:], "]")
+		if !ok {
+			return Entry{}, fmt.Errorf("entry %q has an unterminated [ssl options] block", line)
+		}
+		e.SSLBindConfig = strings.TrimSpace(opts)
+		rest = after
+	}
+	e.SNIFilter = strings.Fields(rest)
+	for _, f := range e.SNIFilter {
+		if strings.ContainsAny(f, "[]") {
+			return Entry{}, fmt.Errorf("entry %q: ssl options must come before the SNI filters", line)
+		}
+	}
+	return e, nil
+}
+
+// ParseCrtList parses crt-list contents, skipping blank lines and comments.
+func ParseCrtList(data []byte) ([]Entry, error) {
+	var entries []Entry
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		e, err := ParseEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// FormatCrtList renders entries as crt-list contents, one per line.
+func FormatCrtList(entries []Entry) []byte {
+	var buf bytes.Buffer
+	for _, e := range entries {
+		buf.WriteString(e.String())
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// Lists returns the crt-list storage entries, sorted by name.
+func Lists(ctx context.Context) ([]map[string]interface{}, error) {
+	data, err := internal.SendRequestWithContext(ctx, "GET", crtListsPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var list []map[string]interface{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse crt-list response: %w", err)
+	}
+	internal.SortByStringField(list, "storage_name")
+	return list, nil
+}
+
+// find returns the storage entry of a crt-list, or nil.
+func find(list []map[string]interface{}, name string) map[string]interface{} {
+	for _, entry := range list {
+		if entry["storage_name"] == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Contents returns the text of a crt-list.
+func Contents(ctx context.Context, name string) ([]byte, error) {
+	return internal.SendRequestWithContext(ctx, "GET", crtListsPath+"/"+name, nil, nil)
+}
+
+// Upload stores data as the crt-list name, replacing the list of the same
+// name when its contents differ, and returns the path binds reference it
+// by.
+func Upload(ctx context.Context, name string, data []byte) (string, error) {
+	if _, err := ParseCrtList(data); err != nil {
+		return "", err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "", errors.New("crt-list has no entries")
+	}
+
+	list, err := Lists(ctx)
+	if err != nil {
+		return "", internal.FormatAPIError(crtListKind, name, "get", err)
+	}
+	existing := find(list, name)
+	if existing == nil {
+		path, err := internal.UploadSSLCrtListWithContext(ctx, name, data)
+		if err != nil {
+			return "", internal.FormatAPIError(crtListKind, name, "upload", err)
+		}
+		internal.PrintStatus(crtListKind, name, internal.ActionCreated)
+		return path, nil
+	}
+
+	path, _ := existing["file"].(string)
+	current, err := Contents(ctx, name)
+	if err != nil {
+		return "", internal.FormatAPIError(crtListKind, name, "get", err)
+	}
+	if bytes.Equal(bytes.TrimSpace(current), bytes.TrimSpace(data)) {
+		internal.PrintStatus(crtListKind, name, internal.ActionUnchanged)
+		return path, nil
+	}
+	if _, err := internal.SendRawRequestWithContext(ctx, "PUT", crtListsPath+"/"+name, nil, data, "text/plain"); err != nil {
+		return "", internal.FormatAPIError(crtListKind, name, "update", err)
+	}
+	internal.PrintStatus(crtListKind, name, internal.ActionConfigured)
+	return path, nil
+}
@@ -24,10 +24,25 @@ import (
 	"strconv"
 	"strings"
 
+	"haproxyctl/cmd/acls"
 	"haproxyctl/cmd/backends"
+	"haproxyctl/cmd/caches"
 	"haproxyctl/cmd/certificates"
+	"haproxyctl/cmd/crtlists"
+	"haproxyctl/cmd/filters"
 	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/httprules"
+	"haproxyctl/cmd/logforwards"
+	"haproxyctl/cmd/logtargets"
+	"haproxyctl/cmd/maps"
+	"haproxyctl/cmd/resolvers"
+	"haproxyctl/cmd/rings"
 	"haproxyctl/cmd/servers"
+	"haproxyctl/cmd/spoe"
+	"haproxyctl/cmd/sticktables"
+	"haproxyctl/cmd/storage"
+	"haproxyctl/cmd/transactions"
 	"haproxyctl/cmd/userlists"
 	"haproxyctl/internal"
 
@@ -52,14 +67,41 @@ var deleteCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(deleteCmd)
 
-	deleteCmd.Flags().StringVarP(&deleteFile, "file", "f", "", "Delete resource from YAML manifest (kind: Backend, Frontend, or Server)")
+	deleteCmd.Flags().StringVarP(&deleteFile, "file", "f", "", "Delete resource from a YAML or JSON manifest (kind: Backend, Frontend, Server, Userlist, Resolver, Cache, Ring, LogForward, SPOE, ACL, HTTPRequestRule, or HTTPResponseRule)")
+	addWaitFlags(deleteCmd)
 
 	// Add subcommands.
+	deleteCmd.AddCommand(acls.DeleteACLsCmd)
 	deleteCmd.AddCommand(backends.DeleteBackendsCmd)
 	deleteCmd.AddCommand(certificates.DeleteCertificatesCmd)
+	deleteCmd.AddCommand(crtlists.DeleteCrtListsCmd)
 	deleteCmd.AddCommand(servers.DeleteServersCmd)
 	deleteCmd.AddCommand(frontends.DeleteFrontendsCmd)
+	deleteCmd.AddCommand(httprules.DeleteHTTPRequestRulesCmd)
+	deleteCmd.AddCommand(httprules.DeleteHTTPResponseRulesCmd)
+	deleteCmd.AddCommand(frontends.DeleteSwitchingRuleCmd)
+	deleteCmd.AddCommand(backends.DeleteServerSwitchingRuleCmd)
+	deleteCmd.AddCommand(backends.DeleteStickRuleCmd)
+	deleteCmd.AddCommand(backends.DeleteHTTPCheckCmd)
+	deleteCmd.AddCommand(backends.DeleteTCPCheckCmd)
+	deleteCmd.AddCommand(filters.DeleteFiltersCmd)
+	deleteCmd.AddCommand(logtargets.DeleteLogTargetsCmd)
+	deleteCmd.AddCommand(frontends.DeleteCaptureCmd)
 	deleteCmd.AddCommand(userlists.DeleteUserlistsCmd)
+	deleteCmd.AddCommand(userlists.DeleteUsersCmd)
+	deleteCmd.AddCommand(userlists.DeleteGroupsCmd)
+	deleteCmd.AddCommand(resolvers.DeleteResolversCmd)
+	deleteCmd.AddCommand(resolvers.DeleteNameserverCmd)
+	deleteCmd.AddCommand(caches.DeleteCachesCmd)
+	deleteCmd.AddCommand(httperrors.DeleteHTTPErrorsCmd)
+	deleteCmd.AddCommand(httperrors.DeleteErrorFilesCmd)
+	deleteCmd.AddCommand(storage.DeleteStorageFilesCmd)
+	deleteCmd.AddCommand(rings.DeleteRingsCmd)
+	deleteCmd.AddCommand(logforwards.DeleteLogForwardsCmd)
+	deleteCmd.AddCommand(spoe.DeleteSPOECmd)
+	deleteCmd.AddCommand(transactions.DeleteTransactionsCmd)
+	deleteCmd.AddCommand(maps.DeleteMapsCmd)
+	deleteCmd.AddCommand(sticktables.DeleteStickTableEntryCmd)
 }
 
 func deleteFromFile(filepath string) error {
@@ -69,6 +111,40 @@ func deleteFromFile(filepath string) error {
 		return fmt.Errorf("failed to read file %s: %w", filepath, err)
 	}
 
+	manifests, err := internal.ParseManifests(data, filepath)
+	if err != nil {
+		return err
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no manifests found in %s", filepath)
+	}
+
+	// Check every document before deleting any of them, then delete in
+	// the reverse of the apply order, so servers, ACLs and frontends go
+	// before the backends they refer to.
+	internal.SortManifestsByKind(manifests)
+	deletes := make([]func() error, 0, len(manifests))
+	for _, m := range manifests {
+		del, err := manifestDeleter(m.Data)
+		if err != nil {
+			if len(manifests) > 1 {
+				return fmt.Errorf("%s: %w", m, err)
+			}
+			return err
+		}
+		deletes = append(deletes, del)
+	}
+	for i := len(deletes) - 1; i >= 0; i-- {
+		if err := deletes[i](); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifestDeleter returns the function that deletes the object a single
+// manifest describes.
+func manifestDeleter(data []byte) (func() error, error) {
 	var meta struct {
 		APIVersion string `yaml:"apiVersion"`
 		Kind       string `yaml:"kind"`
@@ -78,35 +154,56 @@ func deleteFromFile(filepath string) error {
 	}
 
 	if err := yaml.Unmarshal(data, &meta); err != nil {
-		return fmt.Errorf("failed to parse YAML file: %w", err)
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
 	}
 
 	if meta.APIVersion != "haproxyctl/v1" {
-		return fmt.Errorf("unsupported apiVersion %q (expected haproxyctl/v1)", meta.APIVersion)
+		return nil, fmt.Errorf("unsupported apiVersion %q (expected haproxyctl/v1)", meta.APIVersion)
+	}
+
+	// ACL and HTTP rule manifests name their frontend or backend
+	// instead of themselves.
+	switch strings.ToLower(meta.Kind) {
+	case kindACL:
+		return func() error { return acls.DeleteACLsFromFile(data) }, nil
+	case kindHTTPRequestRule, kindHTTPResponseRule:
+		return func() error { return httprules.DeleteRulesFromFile(data) }, nil
 	}
 
 	if meta.Name == "" {
-		return errors.New("manifest is missing required name field")
+		return nil, errors.New("manifest is missing required name field")
 	}
 
 	switch strings.ToLower(meta.Kind) {
 	case "backend":
-		return deleteBackendByName(meta.Name)
+		return func() error { return deleteBackendByName(meta.Name) }, nil
 	case "frontend":
-		return deleteFrontendByName(meta.Name)
+		return func() error { return deleteFrontendByName(meta.Name) }, nil
 	case "userlist":
-		return userlists.DeleteUserlistByName(meta.Name)
+		return func() error { return userlists.DeleteUserlistByName(meta.Name) }, nil
+	case "resolver":
+		return func() error { return resolvers.DeleteResolverByName(meta.Name) }, nil
+	case "cache":
+		return func() error { return caches.DeleteCacheByName(meta.Name) }, nil
+	case "httperrors":
+		return func() error { return httperrors.DeleteHTTPErrorsByName(meta.Name) }, nil
+	case "ring":
+		return func() error { return rings.DeleteRingByName(meta.Name) }, nil
+	case "logforward":
+		return func() error { return logforwards.DeleteLogForwardByName(meta.Name) }, nil
+	case "spoe":
+		return func() error { return spoe.DeleteSPOEByName(meta.Name) }, nil
 	case "server":
 		backendName := meta.Parent
 		if backendName == "" {
 			backendName = meta.Backend
 		}
 		if backendName == "" {
-			return errors.New("server manifest must specify backend or parent")
+			return nil, errors.New("server manifest must specify backend or parent")
 		}
-		return servers.DeleteServer(backendName, meta.Name)
+		return func() error { return servers.DeleteServer(backendName, meta.Name) }, nil
 	default:
-		return fmt.Errorf("unsupported resource kind: %s (supported: Backend, Frontend, Server, Userlist)", meta.Kind)
+		return nil, fmt.Errorf("unsupported resource kind: %s (supported: Backend, Frontend, Server, Userlist, Resolver, Cache, Ring, LogForward, SPOE, ACL, HTTPRequestRule, HTTPResponseRule)", meta.Kind)
 	}
 }
 
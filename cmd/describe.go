@@ -18,11 +18,11 @@ limitations under the License.
 package cmd
 
 import (
-	"log"
-
 	"haproxyctl/cmd/backends"
+	"haproxyctl/cmd/certificates"
 	"haproxyctl/cmd/frontends"
 	"haproxyctl/cmd/servers"
+	"haproxyctl/internal"
 
 	"github.com/spf13/cobra"
 )
@@ -32,7 +32,7 @@ var describeCmd = &cobra.Command{
 	Use:   "describe",
 	Short: "Describe resources in HAProxy",
 	Run: func(_ *cobra.Command, _ []string) {
-		log.Fatal("Specify a resource type (backends, servers).")
+		internal.FatalCodef(internal.ExitUsage, "Specify a resource type (backends, frontends, servers, certificates).")
 	},
 }
 
@@ -43,4 +43,5 @@ func init() {
 	describeCmd.AddCommand(backends.DescribeBackendsCmd)
 	describeCmd.AddCommand(frontends.DescribeFrontendsCmd)
 	describeCmd.AddCommand(servers.DescribeServersCmd)
+	describeCmd.AddCommand(certificates.DescribeCertificatesCmd)
 }
@@ -0,0 +1,206 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"haproxyctl/cmd/backends"
+	"haproxyctl/cmd/configuration"
+	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/servers"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// diffCmd represents the "diff" command.
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show what apply would change, or compare two HAProxy instances",
+	Long: `With -f, compare manifests against the live configuration and print a
+unified diff of what "apply -f" would change. The live objects are
+normalized into the same manifest structures apply uses, so only real
+changes show up. Nothing is written and the configuration version is left
+untouched. -f accepts the same files, directories (-R) and URLs as apply.
+
+With --against, fetch the structured configuration (see "get configuration
+full") from two Data Plane API endpoints and print the differences section
+by section. Frontends, backends and other named sections are matched by
+name, so ordering alone never shows up as drift.
+
+Endpoints are named contexts from the "contexts" map in the config file;
+without --context the default endpoint is used. The command exits with
+status 1 when there are differences, like diff(1).
+
+Examples:
+  haproxyctl diff -f backend.yaml
+  haproxyctl diff -f ./manifests/ -R --context staging
+  haproxyctl diff --context prod-a --against prod-b
+  haproxyctl diff --against staging`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		contextA := contextName
+		contextB := internal.GetFlagString(cmd, "against")
+		file := internal.GetFlagString(cmd, "file")
+
+		var differ bool
+		var err error
+		switch {
+		case file != "" && contextB != "":
+			internal.FatalCodef(internal.ExitUsage, "-f and --against cannot be used together")
+		case file != "":
+			differ, err = diffManifests(file, manifestOptions{
+				Recursive: internal.GetFlagBool(cmd, "recursive"),
+				Selector:  internal.GetFlagString(cmd, "selector"),
+			})
+		case contextB != "":
+			differ, err = diffContexts(contextA, contextB)
+		default:
+			internal.FatalCodef(internal.ExitUsage, "either -f or --against is required")
+		}
+		if err != nil {
+			internal.Fatalf("Failed to diff configurations: %v", err)
+		}
+		if differ {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringP("file", "f", "", "Manifest file, directory, or http(s) URL to compare with the live configuration")
+	diffCmd.Flags().BoolP("recursive", "R", false, "Process the directory given with -f recursively")
+	diffCmd.Flags().StringP("selector", "l", "", "With -f, only compare documents whose metadata.labels match, e.g. team=web")
+	diffCmd.Flags().String("against", "", "Context to compare against")
+}
+
+// diffContexts prints the differences between two contexts and reports
+// whether there were any.
+func diffContexts(contextA, contextB string) (bool, error) {
+	// Resolve both contexts before pointing the request helpers anywhere.
+	cfgA, err := internal.ResolveContext(contextA)
+	if err != nil {
+		return false, err
+	}
+	cfgB, err := internal.ResolveContext(contextB)
+	if err != nil {
+		return false, err
+	}
+
+	docA, err := fetchFullConfiguration(cfgA)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", contextLabel(contextA), err)
+	}
+	docB, err := fetchFullConfiguration(cfgB)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", contextLabel(contextB), err)
+	}
+
+	diffs := internal.DiffConfigurations(docA, docB)
+	if len(diffs) == 0 {
+		_, _ = fmt.Fprintf(os.Stdout, "no differences between %s and %s\n", contextLabel(contextA), contextLabel(contextB))
+		return false, nil
+	}
+	internal.PrintConfigurationDiff(os.Stdout, diffs, contextLabel(contextA), contextLabel(contextB))
+	return true, nil
+}
+
+// diffManifests prints a unified diff between the live configuration of
+// the active context and the manifests at path, and reports whether there
+// were any differences.
+func diffManifests(path string, opts manifestOptions) (bool, error) {
+	manifests, err := readManifests(path, opts)
+	if err != nil {
+		return false, err
+	}
+	if manifests, err = selectManifests(manifests, opts.Selector); err != nil {
+		return false, err
+	}
+
+	color := internal.ColorEnabled(os.Stdout)
+	differ := false
+	for _, m := range manifests {
+		cmp, err := compareManifest(m)
+		if err != nil {
+			return differ, fmt.Errorf("%s: %w", m, err)
+		}
+
+		live, err := manifestYAML(cmp.Live)
+		if err != nil {
+			return differ, err
+		}
+		desired, err := manifestYAML(cmp.Desired)
+		if err != nil {
+			return differ, err
+		}
+
+		id := internal.ResourceID(cmp.Kind, cmp.Name)
+		if internal.WriteUnifiedDiff(os.Stdout, live, desired, "live/"+id, "manifest/"+id, color) {
+			differ = true
+		}
+	}
+	return differ, nil
+}
+
+func compareManifest(m internal.Manifest) (internal.ManifestComparison, error) {
+	switch strings.ToLower(m.Kind) {
+	case kindBackend:
+		return backends.CompareBackendManifest(m.Data)
+	case kindFrontend:
+		return frontends.CompareFrontendManifest(m.Data)
+	case kindServer:
+		return servers.CompareServerManifest(m.Data)
+	case kindGlobal:
+		return configuration.CompareGlobalManifest(m.Data)
+	case kindDefaults:
+		return configuration.CompareDefaultsManifest(m.Data)
+	default:
+		return internal.ManifestComparison{}, fmt.Errorf("unsupported resource kind: %s (supported: Backend, Frontend, Server, Global, Defaults)", m.Kind)
+	}
+}
+
+// manifestYAML renders one side of a comparison; a missing object is empty.
+func manifestYAML(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to render manifest: %w", err)
+	}
+	return string(out), nil
+}
+
+func fetchFullConfiguration(cfg internal.Config) (map[string]interface{}, error) {
+	restore := internal.SetConfigOverride(&cfg)
+	defer restore()
+	return configuration.GetFullConfiguration()
+}
+
+func contextLabel(name string) string {
+	if name == "" {
+		return internal.DefaultContextName
+	}
+	return name
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/servers"
+
+	"github.com/spf13/cobra"
+)
+
+// disableCmd represents the "disable" command, the counterpart of enable.
+var disableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable an HAProxy resource",
+	Long: `Disable an HAProxy resource, such as a server during an incident.
+
+Examples:
+  haproxyctl disable server app app1
+  haproxyctl disable server app app1 --drain`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(disableCmd)
+
+	disableCmd.AddCommand(servers.DisableServerCmd)
+}
@@ -18,9 +18,13 @@ limitations under the License.
 package cmd
 
 import (
+	"haproxyctl/cmd/acls"
 	"haproxyctl/cmd/backends"
 	"haproxyctl/cmd/configuration"
+	"haproxyctl/cmd/crtlists"
 	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/httprules"
+	"haproxyctl/cmd/rings"
 
 	"github.com/spf13/cobra"
 )
@@ -41,4 +45,9 @@ func init() {
 	editCmd.AddCommand(backends.EditBackendsCmd)
 	editCmd.AddCommand(frontends.EditFrontendsCmd)
 	editCmd.AddCommand(configuration.EditConfigurationCmd)
+	editCmd.AddCommand(crtlists.EditCrtListsCmd)
+	editCmd.AddCommand(acls.EditACLsCmd)
+	editCmd.AddCommand(httprules.EditHTTPRequestRulesCmd)
+	editCmd.AddCommand(httprules.EditHTTPResponseRulesCmd)
+	editCmd.AddCommand(rings.EditRingsCmd)
 }
@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/caches"
+	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/servers"
+
+	"github.com/spf13/cobra"
+)
+
+// enableCmd represents the "enable" command, which turns on common
+// multi-object features in one step.
+var enableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable a common feature or an HAProxy resource",
+	Long: `Enable a common feature that needs several configuration objects, or put
+a resource such as a server back into service.
+
+Examples:
+  haproxyctl enable https-redirect web --code 301
+  haproxyctl enable cache static --parent backend/assets
+  haproxyctl enable server app app1`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(enableCmd)
+
+	enableCmd.AddCommand(frontends.EnableHTTPSRedirectCmd)
+	enableCmd.AddCommand(servers.EnableServerCmd)
+	enableCmd.AddCommand(caches.EnableCacheCmd)
+}
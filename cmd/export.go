@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"haproxyctl/cmd/backends"
+	"haproxyctl/cmd/caches"
+	"haproxyctl/cmd/configuration"
+	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/logforwards"
+	"haproxyctl/cmd/resolvers"
+	"haproxyctl/cmd/rings"
+	"haproxyctl/cmd/spoe"
+	"haproxyctl/cmd/userlists"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the "export" command.
+var exportCmd = &cobra.Command{
+	Use:   "export -o <directory>",
+	Short: "Write the whole configuration as manifests apply accepts",
+	Long: `Convert the global section, every defaults section, userlist,
+resolvers section (with its nameservers), cache, http-errors section, ring
+(with its servers), log-forward section (with its binds and log targets),
+backend (with its servers) and frontend (with its binds) into haproxyctl/v1 manifests, one YAML file per object named "<kind>-<name>.yaml". Running
+"haproxyctl apply -f <directory>" against an empty HAProxy recreates them,
+which makes this the starting point for managing an existing HAProxy from
+Git.
+
+Files of the same name are overwritten; other files in the directory are
+left alone.
+
+Examples:
+  haproxyctl export -o manifests/
+  haproxyctl export -o manifests/ --context prod`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dir := internal.GetFlagString(cmd, "output-dir")
+		if dir == "" {
+			internal.FatalCodef(internal.ExitUsage, "--output-dir (-o) is required")
+		}
+
+		manifests, err := exportManifests(cmd.Context())
+		if err != nil {
+			internal.Fatalf("Failed to export configuration: %v", err)
+		}
+		files, err := internal.WriteManifestFiles(dir, manifests)
+		if err != nil {
+			internal.Fatalf("Failed to export configuration: %v", err)
+		}
+		for _, f := range files {
+			_, _ = fmt.Fprintf(os.Stdout, "wrote %s\n", f)
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "Exported %d manifests to %s\n", len(files), dir)
+	},
+}
+
+// exportManifests returns every exportable object as a manifest.
+func exportManifests(ctx context.Context) ([]interface{}, error) {
+	fetchers := []func() ([]interface{}, error){
+		configuration.SectionManifests,
+		func() ([]interface{}, error) { return userlists.UserlistManifests(ctx) },
+		resolvers.ResolverManifests,
+		caches.CacheManifests,
+		httperrors.HTTPErrorsManifests,
+		rings.RingManifests,
+		logforwards.LogForwardManifests,
+		spoe.SPOEManifests,
+		func() ([]interface{}, error) { return backends.BackendManifests(ctx) },
+		func() ([]interface{}, error) { return frontends.FrontendManifests(ctx) },
+	}
+
+	var manifests []interface{}
+	for _, fetch := range fetchers {
+		items, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, items...)
+	}
+	return manifests, nil
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringP("output-dir", "o", "", "Directory to write the manifests to (created if needed)")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestExportRoundTrip(t *testing.T) {
+	src := testserver.New(t)
+	seedGetAll(src)
+	src.AddDefaults(map[string]interface{}{"name": "unnamed_defaults_1", "mode": "http", "timeout_client": "30s"})
+	src.AddUserlist(map[string]interface{}{
+		"name": "ops",
+		"users": map[string]interface{}{
+			"bob":   map[string]interface{}{"username": "bob", "password": "$5$hash", "groups": "admin"},
+			"alice": map[string]interface{}{"username": "alice", "password": "$5$hash"},
+		},
+	})
+
+	manifests, err := exportManifests(context.Background())
+	if err != nil {
+		t.Fatalf("exportManifests() error = %v", err)
+	}
+	dir := t.TempDir()
+	if _, err := internal.WriteManifestFiles(dir, manifests); err != nil {
+		t.Fatalf("WriteManifestFiles() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	want := "backend-app.yaml,defaults-unnamed_defaults_1.yaml,frontend-web.yaml,userlist-admins.yaml,userlist-ops.yaml"
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("exported files = %s, want %s", got, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "userlist-ops.yaml"))
+	if err != nil {
+		t.Fatalf("read userlist: %v", err)
+	}
+	if strings.Index(string(data), "alice") > strings.Index(string(data), "bob") {
+		t.Fatalf("users are not sorted by name:\n%s", data)
+	}
+
+	// Re-applying the export to the same API changes nothing.
+	apply := func() string {
+		return internal.CaptureStdout(t, func() {
+			if err := applyFromPath(newApplyTestCmd(t, nil), dir, manifestOptions{}); err != nil {
+				t.Errorf("apply export: %v", err)
+			}
+		})
+	}
+	if out := apply(); !strings.Contains(out, "Summary: 0 created, 0 configured, 5 unchanged") {
+		t.Fatalf("re-apply output:\n%s", out)
+	}
+
+	// Applying it to an empty API recreates every object.
+	dst := testserver.New(t)
+	if out := apply(); !strings.Contains(out, "Summary: 6 created, 0 configured, 0 unchanged") {
+		t.Fatalf("apply to empty API output:\n%s", out)
+	}
+	if len(dst.Servers("app")) != 1 || len(dst.Binds("web")) != 1 {
+		t.Fatalf("servers/binds were not recreated: %v / %v", dst.Servers("app"), dst.Binds("web"))
+	}
+	if _, ok := dst.Userlist("ops"); !ok {
+		t.Fatalf("userlist ops was not recreated")
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/storage"
+
+	"github.com/spf13/cobra"
+)
+
+// filesCmd represents the "files" command, which manages the Data Plane API
+// general storage.
+var filesCmd = &cobra.Command{
+	Use:   "files",
+	Short: "Upload, download, list and delete general storage files",
+	Long: `Manage the files in the Data Plane API general storage that the
+configuration reads, such as map files, Lua scripts and error pages.
+
+Examples:
+  haproxyctl files list
+  haproxyctl files upload -f ./maps/hosts.map
+  haproxyctl files download hosts.map
+  haproxyctl files delete hosts.map`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(filesCmd)
+
+	filesCmd.AddCommand(storage.ListFilesCmd)
+	filesCmd.AddCommand(storage.UploadFilesCmd)
+	filesCmd.AddCommand(storage.DownloadFilesCmd)
+	filesCmd.AddCommand(storage.DeleteFilesCmd)
+}
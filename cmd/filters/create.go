@@ -0,0 +1,103 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filters provides commands to manage the filters (compression,
+// SPOE, trace, ...) of HAProxy frontends and backends.
+package filters
+
+import (
+	"fmt"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateFiltersCmd represents "create filters --parent <type>/<name>".
+var CreateFiltersCmd = &cobra.Command{
+	Use:     "filters",
+	Aliases: []string{"filter"},
+	Short:   "Add a filter to a frontend or backend",
+	Long: `Add a filter to a frontend or backend. The filter is appended unless
+--index places it at a given position. To manage the whole list, use the
+filters key of a Frontend or Backend manifest instead.
+
+Examples:
+  haproxyctl create filters --parent frontend/web --type compression
+  haproxyctl create filters --parent backend/app --type spoe --spoe-engine waf --spoe-config /etc/haproxy/waf.conf
+  haproxyctl create filters --parent backend/app --type trace --trace-name debug --index 0`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, ParentTypes...)
+		f := Filter{
+			Type:       internal.GetFlagString(cmd, "type"),
+			SPOEEngine: internal.GetFlagString(cmd, "spoe-engine"),
+			SPOEConfig: internal.GetFlagString(cmd, "spoe-config"),
+			TraceName:  internal.GetFlagString(cmd, "trace-name"),
+			CacheName:  internal.GetFlagString(cmd, "cache-name"),
+			AppName:    internal.GetFlagString(cmd, "app-name"),
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateFilter(parentType, parent, f, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+			internal.Fatalf("Failed to create filter on %s '%s': %v", parentType, parent, err)
+		}
+	},
+}
+
+func init() {
+	internal.AddParentFlag(CreateFiltersCmd, "filters", ParentTypes...)
+	CreateFiltersCmd.Flags().String("type", "", "Filter type: compression, spoe, trace, cache or fcgi-app (required)")
+	CreateFiltersCmd.Flags().String("spoe-engine", "", "SPOE engine name (spoe)")
+	CreateFiltersCmd.Flags().String("spoe-config", "", "SPOE configuration file (spoe)")
+	CreateFiltersCmd.Flags().String("trace-name", "", "Name printed in trace messages (trace)")
+	CreateFiltersCmd.Flags().String("cache-name", "", "Cache section to use (cache)")
+	CreateFiltersCmd.Flags().String("app-name", "", "FastCGI application to use (fcgi-app)")
+	CreateFiltersCmd.Flags().Int("index", -1, "Position to insert the filter at (default: append)")
+	CreateFiltersCmd.Flags().Bool("dry-run", false, "Print the filter without creating it")
+}
+
+// CreateFilter inserts a filter at index, or appends it when index is
+// negative.
+func CreateFilter(parentType, parent string, f Filter, index int, dryRun bool) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(f.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := Live(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(parentType, parent, "get", err)
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", path(parentType, parent)+"/"+strconv.Itoa(index), params, f.toPayload()); err != nil {
+		return fmt.Errorf("failed to create %q: %w", f.String(), err)
+	}
+
+	internal.PrintStatus("Filter", fmt.Sprintf("%s/%s/%d", parentType, parent, index), internal.ActionCreated)
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filters provides commands to manage the filters (compression,
+// SPOE, trace, ...) of HAProxy frontends and backends.
+package filters
+
+import (
+	"fmt"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteFiltersCmd represents "delete filters --parent <type>/<name> --index N".
+var DeleteFiltersCmd = &cobra.Command{
+	Use:     "filters",
+	Aliases: []string{"filter"},
+	Short:   "Delete a filter from a frontend or backend",
+	Long: `Delete the filter at --index (as shown by "get filters"). Later filters
+move up by one position.
+
+Examples:
+  haproxyctl delete filters --parent frontend/web --index 0`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, ParentTypes...)
+		index := internal.GetFlagInt(cmd, "index")
+		if index < 0 {
+			internal.FatalCodef(internal.ExitUsage, "--index is required")
+		}
+		if err := DeleteFilter(parentType, parent, index); err != nil {
+			internal.Fatalf("Failed to delete filter %d of %s '%s': %v", index, parentType, parent, err)
+		}
+	},
+}
+
+func init() {
+	internal.AddParentFlag(DeleteFiltersCmd, "filters", ParentTypes...)
+	DeleteFiltersCmd.Flags().Int("index", -1, "Position of the filter to delete (required)")
+}
+
+// DeleteFilter removes the filter at index.
+func DeleteFilter(parentType, parent string, index int) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%s/%d", parentType, parent, index)
+	if _, err := internal.SendRequest("DELETE", path(parentType, parent)+"/"+strconv.Itoa(index), params, nil); err != nil {
+		return internal.FormatAPIError("Filter", id, "delete", err)
+	}
+
+	internal.PrintStatus("Filter", id, internal.ActionDeleted)
+	return nil
+}
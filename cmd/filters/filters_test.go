@@ -0,0 +1,99 @@
+package filters
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func filterTypesOf(items []map[string]interface{}) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		t, _ := item["type"].(string)
+		out = append(out, t)
+	}
+	return out
+}
+
+func TestFilterValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := []Filter{
+		{Type: "compression"},
+		{Type: "spoe", SPOEEngine: "waf", SPOEConfig: "/etc/haproxy/waf.conf"},
+		{Type: "trace", TraceName: "debug", TraceHexdump: true},
+		{Type: "cache", CacheName: "static"},
+	}
+	for _, f := range valid {
+		if err := f.Validate(); err != nil {
+			t.Errorf("Validate(%s) = %v", f, err)
+		}
+	}
+	invalid := []Filter{
+		{Type: "gzip"},
+		{Type: "spoe", SPOEEngine: "waf"},
+		{Type: "cache"},
+		{Type: "fcgi-app"},
+	}
+	for _, f := range invalid {
+		if err := f.Validate(); err == nil {
+			t.Errorf("expected error for %+v", f)
+		}
+	}
+
+	if got := (Filter{Type: "spoe", SPOEEngine: "waf", SPOEConfig: "/etc/waf.conf"}).String(); got != "filter spoe engine waf config /etc/waf.conf" {
+		t.Fatalf("String() = %q", got)
+	}
+}
+
+func TestApplyDiffKeepsCommonPrefix(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "app", "mode": "http"})
+	srv.AddListItem("backends", "app", "filters", map[string]interface{}{"type": "compression"})
+	srv.AddListItem("backends", "app", "filters", map[string]interface{}{"type": "trace", "trace_name": "old"})
+
+	before, err := Live(ParentBackend, "app")
+	if err != nil {
+		t.Fatalf("Live failed: %v", err)
+	}
+	after := []Filter{{Type: "compression"}, {Type: "spoe", SPOEConfig: "/etc/waf.conf"}}
+	if err := ApplyDiff(nil, ParentBackend, "app", before, after); err != nil {
+		t.Fatalf("ApplyDiff failed: %v", err)
+	}
+
+	if got := strings.Join(filterTypesOf(srv.List("backends", "app", "filters")), ","); got != "compression,spoe" {
+		t.Fatalf("filters = %s, want compression,spoe", got)
+	}
+	if n := srv.CountRequests("DELETE", "/v3/services/haproxy/configuration/backends/app/filters/0"); n != 0 {
+		t.Fatalf("unchanged filter 0 was deleted %d times", n)
+	}
+}
+
+func TestCreateAndDeleteFilter(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddListItem("frontends", "web", "filters", map[string]interface{}{"type": "compression"})
+
+	output := internal.CaptureStdout(t, func() {
+		if err := CreateFilter(ParentFrontend, "web", Filter{Type: "trace", TraceName: "dbg"}, 0, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "filter/frontend/web/0 created") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if got := strings.Join(filterTypesOf(srv.List("frontends", "web", "filters")), ","); got != "trace,compression" {
+		t.Fatalf("filters after create = %s", got)
+	}
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := DeleteFilter(ParentFrontend, "web", 1); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+	})
+	if got := strings.Join(filterTypesOf(srv.List("frontends", "web", "filters")), ","); got != "trace" {
+		t.Fatalf("filters after delete = %s", got)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filters provides commands to manage the filters (compression,
+// SPOE, trace, ...) of HAProxy frontends and backends.
+package filters
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetFiltersCmd represents "get filters --parent <type>/<name>".
+var GetFiltersCmd = &cobra.Command{
+	Use:     "filters",
+	Aliases: []string{"filter"},
+	Short:   "List the filters of a frontend or backend",
+	Long: `List the filters of a frontend or backend in declaration order. The
+index column is the position used by the delete command.
+
+Examples:
+  haproxyctl get filters --parent frontend/web
+  haproxyctl get filters --parent backend/app -o yaml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, ParentTypes...)
+		list, err := internal.GetResourceList(path(parentType, parent))
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(parentType, parent))
+			}
+			internal.Fatalf("Failed to fetch filters of %s '%s': %v", parentType, parent, err)
+		}
+		internal.SortForCmd(cmd, list)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(list, filterColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// filterColumns shows the options of the common filter types; wide adds
+// the trace switches.
+var filterColumns = internal.ColumnSet{
+	Default: []string{"index", "type", "spoe_engine", "spoe_config", "trace_name", "cache_name", "app_name"},
+	Wide:    []string{"trace_hexdump", "trace_rnd_parsing", "trace_rnd_forwarding"},
+}
+
+func init() {
+	internal.AddParentFlag(GetFiltersCmd, "filters", ParentTypes...)
+}
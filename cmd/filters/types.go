@@ -0,0 +1,172 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filters provides commands to manage the filters (compression,
+// SPOE, trace, ...) of HAProxy frontends and backends.
+package filters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	// ParentFrontend is the parent type of filters declared on a frontend.
+	ParentFrontend = internal.ParentFrontend
+	// ParentBackend is the parent type of filters declared on a backend.
+	ParentBackend = internal.ParentBackend
+)
+
+// ParentTypes are the sections that own filters.
+var ParentTypes = []string{ParentFrontend, ParentBackend}
+
+var filterTypes = []string{"compression", "spoe", "trace", "cache", "fcgi-app"}
+
+// Filter is a "filter <type> [options]" line of a frontend or backend.
+//
+//nolint:tagliatelle // manifest uses the Data Plane API snake_case names
+type Filter struct {
+	Type               string `json:"type" yaml:"type"`
+	SPOEEngine         string `json:"spoe_engine,omitempty" yaml:"spoe_engine,omitempty"`
+	SPOEConfig         string `json:"spoe_config,omitempty" yaml:"spoe_config,omitempty"`
+	TraceName          string `json:"trace_name,omitempty" yaml:"trace_name,omitempty"`
+	TraceHexdump       bool   `json:"trace_hexdump,omitempty" yaml:"trace_hexdump,omitempty"`
+	TraceRndParsing    bool   `json:"trace_rnd_parsing,omitempty" yaml:"trace_rnd_parsing,omitempty"`
+	TraceRndForwarding bool   `json:"trace_rnd_forwarding,omitempty" yaml:"trace_rnd_forwarding,omitempty"`
+	CacheName          string `json:"cache_name,omitempty" yaml:"cache_name,omitempty"`
+	AppName            string `json:"app_name,omitempty" yaml:"app_name,omitempty"`
+}
+
+// String renders the filter the way it appears in haproxy.cfg.
+func (f Filter) String() string {
+	parts := []string{"filter", f.Type}
+	switch f.Type {
+	case "spoe":
+		if f.SPOEEngine != "" {
+			parts = append(parts, "engine", f.SPOEEngine)
+		}
+		parts = append(parts, "config", f.SPOEConfig)
+	case "trace":
+		if f.TraceName != "" {
+			parts = append(parts, "name", f.TraceName)
+		}
+	case "cache":
+		parts = append(parts, f.CacheName)
+	case "fcgi-app":
+		parts = append(parts, f.AppName)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Validate checks the filter type and the options it requires.
+func (f Filter) Validate() error {
+	if !slices.Contains(filterTypes, f.Type) {
+		return fmt.Errorf("invalid filter type %q (allowed: %s)", f.Type, strings.Join(filterTypes, ", "))
+	}
+	switch {
+	case f.Type == "spoe" && f.SPOEConfig == "":
+		return errors.New("spoe filter needs spoe_config")
+	case f.Type == "cache" && f.CacheName == "":
+		return errors.New("cache filter needs cache_name")
+	case f.Type == "fcgi-app" && f.AppName == "":
+		return errors.New("fcgi-app filter needs app_name")
+	}
+	return nil
+}
+
+// ValidateAll validates every filter of a list.
+func ValidateAll(list []Filter) error {
+	for i, f := range list {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("filter %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (f Filter) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"type": f.Type}
+	set := func(key, value string) {
+		if value != "" {
+			payload[key] = value
+		}
+	}
+	set("spoe_engine", f.SPOEEngine)
+	set("spoe_config", f.SPOEConfig)
+	set("trace_name", f.TraceName)
+	set("cache_name", f.CacheName)
+	set("app_name", f.AppName)
+	if f.TraceHexdump {
+		payload["trace_hexdump"] = true
+	}
+	if f.TraceRndParsing {
+		payload["trace_rnd_parsing"] = true
+	}
+	if f.TraceRndForwarding {
+		payload["trace_rnd_forwarding"] = true
+	}
+	return payload
+}
+
+// fromAPI converts an API filter object.
+func fromAPI(obj map[string]interface{}) Filter {
+	var f Filter
+	f.Type, _ = obj["type"].(string)
+	f.SPOEEngine, _ = obj["spoe_engine"].(string)
+	f.SPOEConfig, _ = obj["spoe_config"].(string)
+	f.TraceName, _ = obj["trace_name"].(string)
+	f.TraceHexdump, _ = obj["trace_hexdump"].(bool)
+	f.TraceRndParsing, _ = obj["trace_rnd_parsing"].(bool)
+	f.TraceRndForwarding, _ = obj["trace_rnd_forwarding"].(bool)
+	f.CacheName, _ = obj["cache_name"].(string)
+	f.AppName, _ = obj["app_name"].(string)
+	return f
+}
+
+// path returns the filters endpoint of a frontend or backend.
+func path(parentType, parent string) string {
+	return fmt.Sprintf("/services/haproxy/configuration/%ss/%s/filters", parentType, parent)
+}
+
+// Live returns the filters of a frontend or backend in order, or nil when
+// it has none.
+func Live(parentType, parent string) ([]Filter, error) {
+	return LiveWithContext(context.Background(), parentType, parent)
+}
+
+// LiveWithContext is Live with a context.
+func LiveWithContext(ctx context.Context, parentType, parent string) ([]Filter, error) {
+	list, err := internal.GetResourceListWithContext(ctx, path(parentType, parent))
+	if err != nil {
+		return nil, err
+	}
+	var out []Filter
+	for _, raw := range list {
+		out = append(out, fromAPI(raw))
+	}
+	return out, nil
+}
+
+// ApplyDiff turns the filters of a frontend or backend from before into
+// after, staging the changes in tx when it is non-nil.
+func ApplyDiff(tx *internal.Transaction, parentType, parent string, before, after []Filter) error {
+	return internal.ApplyIndexedDiff(tx, path(parentType, parent), before, after, func(f Filter) interface{} { return f.toPayload() })
+}
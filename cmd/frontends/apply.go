@@ -1,18 +1,36 @@
 package frontends
 
 import (
+	"context"
 	"fmt"
+	"haproxyctl/cmd/filters"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/logtargets"
 	"haproxyctl/internal"
 	"reflect"
-	"strconv"
 
 	"gopkg.in/yaml.v2"
 )
 
+// ValidateFrontendFromYAML checks a frontend manifest the way apply does,
+// without contacting the Data Plane API.
+func ValidateFrontendFromYAML(data []byte) error {
+	var manifest frontendWithBinds
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse frontend manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid frontend configuration: %w", err)
+	}
+	return nil
+}
+
 // ApplyFrontendFromYAML applies a frontend manifest declaratively:
 //   - If the frontend does not exist, it is created along with its binds.
 //   - If it exists, it is replaced via PUT and binds are reconciled using
 //     the same diff logic as the interactive edit flow.
+//   - Backend switching rules, filters and log targets are reconciled only
+//     when the manifest lists them.
 func ApplyFrontendFromYAML(data []byte, outputFormat string, dryRun bool) error {
 	var manifest frontendWithBinds
 	if err := yaml.Unmarshal(data, &manifest); err != nil {
@@ -22,6 +40,8 @@ func ApplyFrontendFromYAML(data []byte, outputFormat string, dryRun bool) error
 	if err := manifest.Validate(); err != nil {
 		return fmt.Errorf("invalid frontend configuration: %w", err)
 	}
+	httperrors.SortErrorFiles(manifest.ErrorFiles)
+	manifest.normalizeDurations()
 
 	// Preview/dry-run behaviour mirrors `create frontends`.
 	if outputFormat != "" || dryRun {
@@ -41,97 +61,158 @@ func ApplyFrontendFromYAML(data []byte, outputFormat string, dryRun bool) error
 
 	// Determine whether the frontend exists and, if it does, capture its
 	// current configuration so we can detect no-op applies.
-	path := "/services/haproxy/configuration/frontends/" + name
-	rawFrontend, err := internal.GetResource(path)
-	notFound := false
+	current, exists, err := fetchCurrentFrontend(context.Background(), name)
 	if err != nil {
-		if internal.IsNotFoundError(err) {
-			notFound = true
-			rawFrontend = nil
-		} else {
-			return fmt.Errorf("failed to check frontend existence: %w", err)
-		}
+		return err
 	}
 
-	version, err := internal.GetConfigurationVersion()
+	payload, err := manifest.toClient()
 	if err != nil {
-		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+		return err
 	}
 
-	payload := manifest.ToPayload()
-
-	if notFound {
-		// Create frontend, then create binds to match manifest.
-		if _, err := internal.SendRequest(
-			"POST",
-			"/services/haproxy/configuration/frontends",
-			map[string]string{"version": strconv.Itoa(version)},
-			payload,
-		); err != nil {
-			return fmt.Errorf("failed to create frontend %q: %w", name, err)
-		}
+	if !exists {
+		// Create frontend, then create binds to match manifest, all in one
+		// transaction so a failing bind leaves nothing behind.
+		err := internal.WithTransaction(func(tx *internal.Transaction) error {
+			if err := internal.API().CreateFrontend(context.Background(), tx.Client(), payload); err != nil {
+				return fmt.Errorf("failed to create frontend %q: %w", name, err)
+			}
 
-		for _, b := range manifest.Binds {
-			if err := createBind(name, b); err != nil {
-				return fmt.Errorf("failed to create bind on frontend %q: %w", name, err)
+			for _, b := range manifest.EffectiveBinds() {
+				if err := createBind(tx, name, b); err != nil {
+					return fmt.Errorf("failed to create bind on frontend %q: %w", name, err)
+				}
+			}
+			if err := applySwitchingRuleDiff(tx, name, nil, manifest.BackendSwitchingRules); err != nil {
+				return fmt.Errorf("failed to create backend switching rules on frontend %q: %w", name, err)
 			}
+			if err := filters.ApplyDiff(tx, filters.ParentFrontend, name, nil, manifest.Filters); err != nil {
+				return fmt.Errorf("failed to create filters on frontend %q: %w", name, err)
+			}
+			if err := logtargets.ApplyDiff(tx, logtargets.ParentFrontend, name, nil, manifest.LogTargets); err != nil {
+				return fmt.Errorf("failed to create log targets for frontend %q: %w", name, err)
+			}
+			if err := applyCaptureDiff(tx, name, nil, manifest.Captures); err != nil {
+				return fmt.Errorf("failed to create captures on frontend %q: %w", name, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 
 		internal.PrintStatus("Frontend", name, internal.ActionCreated)
 		return nil
 	}
 
-	rawBinds, err := internal.GetResourceList(
-		"/services/haproxy/configuration/frontends/" + name + "/binds",
+	before := current.Binds
+	manageRules := manifest.BackendSwitchingRules != nil
+	manageFilters := manifest.Filters != nil
+	manageLogTargets := manifest.LogTargets != nil
+	manageCaptures := manifest.Captures != nil
+
+	if reflect.DeepEqual(current.frontendConfig, manifest.frontendConfig) &&
+		bindsEqualByKey(before, manifest.EffectiveBinds()) &&
+		(!manageRules || internal.IndexedEqual(current.BackendSwitchingRules, manifest.BackendSwitchingRules)) &&
+		(!manageFilters || internal.IndexedEqual(current.Filters, manifest.Filters)) &&
+		(!manageLogTargets || internal.IndexedEqual(current.LogTargets, manifest.LogTargets)) &&
+		(!manageCaptures || internal.IndexedEqual(current.Captures, manifest.Captures)) {
+		internal.PrintStatus("Frontend", name, internal.ActionUnchanged)
+		return nil
+	}
+
+	// Update existing frontend via PUT, then reconcile binds using the
+	// same diff logic as the interactive edit flow, in one transaction.
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		if err := internal.API().UpdateFrontend(context.Background(), tx.Client(), payload); err != nil {
+			return fmt.Errorf("failed to update frontend %q: %w", name, err)
+		}
+
+		if err := applyBindDiff(tx, name, before, manifest.EffectiveBinds()); err != nil {
+			return fmt.Errorf("failed to apply bind changes for frontend %q: %w", name, err)
+		}
+
+		if manageRules {
+			if err := applySwitchingRuleDiff(tx, name, current.BackendSwitchingRules, manifest.BackendSwitchingRules); err != nil {
+				return fmt.Errorf("failed to apply backend switching rule changes for frontend %q: %w", name, err)
+			}
+		}
+		if manageFilters {
+			if err := filters.ApplyDiff(tx, filters.ParentFrontend, name, current.Filters, manifest.Filters); err != nil {
+				return fmt.Errorf("failed to apply filter changes for frontend %q: %w", name, err)
+			}
+		}
+		if manageLogTargets {
+			if err := logtargets.ApplyDiff(tx, logtargets.ParentFrontend, name, current.LogTargets, manifest.LogTargets); err != nil {
+				return fmt.Errorf("failed to apply log target changes for frontend %q: %w", name, err)
+			}
+		}
+		if manageCaptures {
+			if err := applyCaptureDiff(tx, name, current.Captures, manifest.Captures); err != nil {
+				return fmt.Errorf("failed to apply capture changes for frontend %q: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus("Frontend", name, internal.ActionConfigured)
+	return nil
+}
+
+// fetchCurrentFrontend returns the live frontend, its binds, its backend
+// switching rules, filters, log targets and captures in manifest form,
+// normalized the same way as manifests read from files. exists is
+// false when the frontend is not configured.
+func fetchCurrentFrontend(ctx context.Context, name string) (current frontendWithBinds, exists bool, err error) {
+	rawFrontend, err := internal.GetResourceWithContext(ctx, "/services/haproxy/configuration/frontends/"+name)
+	if err != nil {
+		if internal.IsNotFoundError(err) {
+			return current, false, nil
+		}
+		return current, false, fmt.Errorf("failed to check frontend existence: %w", err)
+	}
+
+	rawBinds, err := internal.GetResourceListWithContext(ctx,
+		"/services/haproxy/configuration/frontends/"+name+"/binds",
 	)
 	if err != nil && !internal.IsNotFoundError(err) {
-		return fmt.Errorf("failed to fetch existing binds for frontend %q: %w", name, err)
+		return current, false, fmt.Errorf("failed to fetch existing binds for frontend %q: %w", name, err)
 	}
 
-	var before []BindConfig
+	populateFrontendConfigFromMap(&current.frontendConfig, rawFrontend)
 	for _, raw := range rawBinds {
 		bc := mapBindFromAPI(raw)
-		if bc.Address != "" && bc.Port != 0 {
-			before = append(before, bc)
+		if bc.Address != "" && (bc.Port != 0 || bc.isSocket()) {
+			current.Binds = append(current.Binds, bc)
 		}
 	}
 
-	// Build a normalized view of the current frontend (config + binds) to
-	// detect if this apply would be a no-op.
-	var current frontendWithBinds
-	if rawFrontend != nil {
-		populateFrontendConfigFromMap(&current.frontendConfig, rawFrontend)
-		current.Binds = before
+	current.BackendSwitchingRules, err = liveSwitchingRules(ctx, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch backend switching rules for frontend %q: %w", name, err)
 	}
-
-	if reflect.DeepEqual(current.frontendConfig, manifest.frontendConfig) &&
-		bindsEqualByKey(before, manifest.Binds) {
-		internal.PrintStatus("Frontend", name, internal.ActionUnchanged)
-		return nil
+	current.Filters, err = filters.LiveWithContext(ctx, filters.ParentFrontend, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch filters for frontend %q: %w", name, err)
 	}
-
-	// Update existing frontend via PUT, then reconcile binds using the
-	// same diff logic as the interactive edit flow.
-	if _, err := internal.SendRequest(
-		"PUT",
-		path,
-		map[string]string{"version": strconv.Itoa(version)},
-		payload,
-	); err != nil {
-		return fmt.Errorf("failed to update frontend %q: %w", name, err)
+	current.LogTargets, err = logtargets.LiveWithContext(ctx, logtargets.ParentFrontend, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch log targets for frontend %q: %w", name, err)
 	}
-
-	if err := applyBindDiff(name, before, manifest.Binds); err != nil {
-		return fmt.Errorf("failed to apply bind changes for frontend %q: %w", name, err)
+	current.Captures, err = liveCaptures(ctx, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return current, false, fmt.Errorf("failed to fetch captures for frontend %q: %w", name, err)
 	}
-
-	internal.PrintStatus("Frontend", name, internal.ActionConfigured)
-	return nil
+	return current, true, nil
 }
 
 // bindsEqualByKey compares two slices of BindConfig using the same
-// semantics as applyBindDiff: identity by address:port, and equality via
-// bindConfigEqual.
+// semantics as applyBindDiff: identity by address:port (or socket path),
+// and equality via bindConfigEqual.
 func bindsEqualByKey(a, b []BindConfig) bool {
 	if len(a) != len(b) {
 		return false
@@ -139,12 +220,12 @@ func bindsEqualByKey(a, b []BindConfig) bool {
 
 	aByKey := make(map[string]BindConfig, len(a))
 	for _, bind := range a {
-		key := fmt.Sprintf("%s:%d", bind.Address, bind.Port)
+		key := bind.key()
 		aByKey[key] = bind
 	}
 
 	for _, bind := range b {
-		key := fmt.Sprintf("%s:%d", bind.Address, bind.Port)
+		key := bind.key()
 		existing, ok := aByKey[key]
 		if !ok {
 			return false
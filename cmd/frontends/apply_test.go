@@ -0,0 +1,217 @@
+package frontends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testFrontendManifest = `apiVersion: haproxyctl/v1
+kind: Frontend
+name: web
+mode: http
+default_backend: app
+binds:
+  - address: 0.0.0.0
+    port: 80
+  - address: 0.0.0.0
+    port: 8080
+`
+
+func TestApplyFrontendFromYAML_CreateAndUpdateAreAtomic(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "app"})
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(testFrontendManifest), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web created") {
+		t.Fatalf("expected created status, got:\n%s", output)
+	}
+	if got := len(srv.Binds("web")); got != 2 {
+		t.Fatalf("binds = %d, want 2", got)
+	}
+	if srv.Version() != 2 {
+		t.Fatalf("version after create = %d, want 2 (one transaction)", srv.Version())
+	}
+
+	updated := strings.Replace(testFrontendManifest, "port: 8080", "port: 8443", 1)
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(updated), "", false); err != nil {
+			t.Fatalf("update apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web configured") {
+		t.Fatalf("expected configured status, got:\n%s", output)
+	}
+	if srv.Version() != 3 {
+		t.Fatalf("version after update = %d, want 3 (one transaction)", srv.Version())
+	}
+	for _, b := range srv.Binds("web") {
+		if b["port"] == float64(8080) {
+			t.Fatalf("old bind still present: %+v", srv.Binds("web"))
+		}
+	}
+}
+
+func TestApplyFrontendFromYAML_FullFieldsRoundTrip(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "app"})
+
+	manifest := `apiVersion: haproxyctl/v1
+kind: Frontend
+name: web
+mode: http
+default_backend: app
+timeout_client: 60s
+maxconn: 10000
+backlog: 2048
+monitor_uri: /haproxy-health
+httplog: true
+log_format: "%ci:%cp %ST"
+http_connection_mode: http-keep-alive
+clitcpka: enabled
+dontlognull: disabled
+stats_options:
+  stats_enable: true
+  stats_uri_prefix: /stats
+  stats_refresh_delay: 10
+binds:
+  - address: 0.0.0.0
+    port: 80
+`
+	internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("first apply failed: %v", err)
+		}
+	})
+	frontend, _ := srv.Frontend("web")
+	if frontend["maxconn"] != float64(10000) || frontend["httplog"] != true || frontend["clitcpka"] != "enabled" {
+		t.Fatalf("fields not sent in API form: %+v", frontend)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	if err := ApplyFrontendFromYAML([]byte(strings.Replace(manifest, "log_format", "tcplog: true\nlog_format", 1)), "", false); err == nil {
+		t.Fatal("expected error for httplog together with tcplog")
+	}
+}
+
+func TestApplyFrontendFromYAML_BindNamesAndOptions(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "app"})
+
+	manifest := `apiVersion: haproxyctl/v1
+kind: Frontend
+name: web
+mode: http
+default_backend: app
+binds:
+  - address: "::"
+    port: 80
+    v4v6: true
+    tcp_user_timeout: 30s
+`
+	internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("first apply failed: %v", err)
+		}
+	})
+	binds := srv.Binds("web")
+	if len(binds) != 1 || binds[0]["name"] != ":::80" || binds[0]["v4v6"] != true || binds[0]["tcp_user_timeout"] != float64(30000) {
+		t.Fatalf("binds = %+v", binds)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	renamed := strings.Replace(manifest, `  - address: "::"`, "  - name: http\n    address: \"::\"", 1)
+	internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(renamed), "", false); err != nil {
+			t.Fatalf("rename apply failed: %v", err)
+		}
+	})
+	binds = srv.Binds("web")
+	if len(binds) != 1 || binds[0]["name"] != "http" || binds[0]["v4v6"] != true {
+		t.Fatalf("bind not renamed: %+v", binds)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("apply without name failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web unchanged") {
+		t.Fatalf("omitting the name should keep the live one, got:\n%s", output)
+	}
+}
+
+func TestApplyFrontendFromYAML_SocketBinds(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "app"})
+
+	manifest := `apiVersion: haproxyctl/v1
+kind: Frontend
+name: internal
+mode: http
+default_backend: app
+binds:
+  - address: /run/haproxy/app.sock
+    mode: "660"
+    user: haproxy
+    group: app
+  - address: abns@internal
+`
+	internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("first apply failed: %v", err)
+		}
+	})
+	binds := srv.Binds("internal")
+	if len(binds) != 2 {
+		t.Fatalf("binds = %+v", binds)
+	}
+	for _, b := range binds {
+		if _, ok := b["port"]; ok {
+			t.Fatalf("socket bind sent with a port: %+v", b)
+		}
+		if b["address"] == "/run/haproxy/app.sock" && (b["name"] != "run_haproxy_app.sock" || b["mode"] != "660" || b["group"] != "app") {
+			t.Fatalf("unix socket bind = %+v", b)
+		}
+	}
+
+	changed := strings.Replace(manifest, `mode: "660"`, `mode: "600"`, 1)
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/internal configured") {
+		t.Fatalf("expected configured status, got:\n%s", output)
+	}
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/internal unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+}
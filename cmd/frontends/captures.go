@@ -0,0 +1,229 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package frontends provides commands to manage HAProxy frontends.
+package frontends
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// Capture is a "declare capture request|response len <length>" slot. Its
+// position is the id that "http-request capture <sample> id <n>" and
+// "http-response capture" rules fill.
+type Capture struct {
+	Type   string `json:"type" yaml:"type"`
+	Length int    `json:"length" yaml:"length"`
+}
+
+// String renders the capture the way it appears in haproxy.cfg.
+func (c Capture) String() string {
+	return fmt.Sprintf("declare capture %s len %d", c.Type, c.Length)
+}
+
+func (c Capture) validate() error {
+	if c.Type != "request" && c.Type != "response" {
+		return fmt.Errorf("invalid capture type %q (allowed: request, response)", c.Type)
+	}
+	if c.Length <= 0 {
+		return fmt.Errorf("%q: length must be greater than 0", c.String())
+	}
+	return nil
+}
+
+func (c Capture) toPayload() map[string]interface{} {
+	return map[string]interface{}{"type": c.Type, "length": c.Length}
+}
+
+// mapCaptureFromAPI converts an API capture object.
+func mapCaptureFromAPI(obj map[string]interface{}) Capture {
+	var c Capture
+	c.Type, _ = obj["type"].(string)
+	c.Length, _ = internal.GetIntField(obj, "length")
+	return c
+}
+
+func capturesPath(frontendName string) string {
+	return "/services/haproxy/configuration/frontends/" + frontendName + "/captures"
+}
+
+// liveCaptures returns the capture slots of a frontend in order, or nil
+// when it has none.
+func liveCaptures(ctx context.Context, frontendName string) ([]Capture, error) {
+	list, err := internal.GetResourceListWithContext(ctx, capturesPath(frontendName))
+	if err != nil {
+		return nil, err
+	}
+	var captures []Capture
+	for _, raw := range list {
+		captures = append(captures, mapCaptureFromAPI(raw))
+	}
+	return captures, nil
+}
+
+// applyCaptureDiff turns the capture slots of a frontend from before into
+// after, staging the changes in tx when it is non-nil.
+func applyCaptureDiff(tx *internal.Transaction, frontendName string, before, after []Capture) error {
+	return internal.ApplyIndexedDiff(tx, capturesPath(frontendName), before, after, func(c Capture) interface{} { return c.toPayload() })
+}
+
+// GetCapturesCmd represents "get captures <frontend>".
+var GetCapturesCmd = &cobra.Command{
+	Use:     "captures <frontend_name>",
+	Aliases: []string{"capture"},
+	Short:   "List the capture slots declared on a frontend",
+	Long: `List the "declare capture" slots of a frontend. The index column is the
+slot id used by "http-request capture <sample> id <n>" and
+"http-response capture <sample> id <n>" rules.
+
+Examples:
+  haproxyctl get captures web
+  haproxyctl get captures web -o yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		captures, err := internal.GetResourceList(capturesPath(args[0]))
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Frontend", args[0]))
+			}
+			internal.Fatalf("Failed to fetch captures of frontend '%s': %v", args[0], err)
+		}
+		internal.SortForCmd(cmd, captures)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(captures, captureColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// captureColumns shows each slot as id, direction and length.
+var captureColumns = internal.ColumnSet{
+	Default: []string{"index", "type", "length"},
+}
+
+// CreateCaptureCmd represents "create captures <frontend>".
+var CreateCaptureCmd = &cobra.Command{
+	Use:     "captures <frontend_name>",
+	Aliases: []string{"capture"},
+	Short:   "Declare a capture slot on a frontend",
+	Long: `Declare a request or response capture slot on a frontend. The slot is
+appended unless --index places it at a given position; note that slot ids
+are positions, so inserting renumbers the slots after it.
+
+Fill the slot with an http-request or http-response capture rule, e.g. to
+log a header or cookie:
+
+  haproxyctl create captures web --type request --length 64
+  haproxyctl create http-request-rules --parent frontend/web --type capture \\
+    --set capture_sample="req.hdr(User-Agent)" --set capture_id=0
+
+Examples:
+  haproxyctl create captures web --type request --length 64
+  haproxyctl create captures web --type response --length 32`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		capture := Capture{
+			Type:   internal.GetFlagString(cmd, "type"),
+			Length: internal.GetFlagInt(cmd, "length"),
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateCapture(args[0], capture, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+			internal.Fatalf("Failed to create capture on frontend '%s': %v", args[0], err)
+		}
+	},
+}
+
+// DeleteCaptureCmd represents "delete captures <frontend> --index N".
+var DeleteCaptureCmd = &cobra.Command{
+	Use:     "captures <frontend_name>",
+	Aliases: []string{"capture"},
+	Short:   "Delete a capture slot from a frontend",
+	Long: `Delete the capture slot at --index (as shown by "get captures"). Later
+slots move up by one position, so capture rules that refer to them by id
+must be updated.
+
+Examples:
+  haproxyctl delete captures web --index 1`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		index := internal.GetFlagInt(cmd, "index")
+		if index < 0 {
+			internal.FatalCodef(internal.ExitUsage, "--index is required")
+		}
+		if err := DeleteCapture(args[0], index); err != nil {
+			internal.Fatalf("Failed to delete capture %d of frontend '%s': %v", index, args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateCaptureCmd.Flags().String("type", "request", "Capture direction: request or response")
+	CreateCaptureCmd.Flags().Int("length", 0, "Maximum number of characters kept (required)")
+	CreateCaptureCmd.Flags().Int("index", -1, "Slot position to insert at (default: append)")
+	CreateCaptureCmd.Flags().Bool("dry-run", false, "Print the declaration without creating it")
+
+	DeleteCaptureCmd.Flags().Int("index", -1, "Slot position to delete (required)")
+}
+
+// CreateCapture inserts a capture slot at index, or appends it when index is
+// negative.
+func CreateCapture(frontendName string, capture Capture, index int, dryRun bool) error {
+	if err := capture.validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(capture.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := liveCaptures(context.Background(), frontendName)
+	if err != nil {
+		return internal.FormatAPIError("Frontend", frontendName, "get", err)
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", capturesPath(frontendName)+"/"+strconv.Itoa(index), params, capture.toPayload()); err != nil {
+		return fmt.Errorf("failed to create %q: %w", capture.String(), err)
+	}
+
+	internal.PrintStatus("Capture", fmt.Sprintf("%s/%d", frontendName, index), internal.ActionCreated)
+	return nil
+}
+
+// DeleteCapture removes the capture slot at index.
+func DeleteCapture(frontendName string, index int) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%d", frontendName, index)
+	if _, err := internal.SendRequest("DELETE", capturesPath(frontendName)+"/"+strconv.Itoa(index), params, nil); err != nil {
+		return internal.FormatAPIError("Capture", id, "delete", err)
+	}
+
+	internal.PrintStatus("Capture", id, internal.ActionDeleted)
+	return nil
+}
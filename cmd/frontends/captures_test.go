@@ -0,0 +1,104 @@
+package frontends
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func captureSlots(srv *testserver.Server) string {
+	var out []string
+	for _, c := range srv.List("frontends", "web", "captures") {
+		out = append(out, fmt.Sprintf("%v:%v", c["type"], c["length"]))
+	}
+	return strings.Join(out, ",")
+}
+
+func TestApplyFrontendFromYAML_ManagesCapturesOnlyWhenListed(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddListItem("frontends", "web", "captures", map[string]interface{}{"type": "request", "length": 64})
+
+	manifest := "apiVersion: haproxyctl/v1\nkind: Frontend\nname: web\nmode: http\n"
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if got := captureSlots(srv); got != "request:64" {
+		t.Fatalf("captures = %s, want untouched request:64", got)
+	}
+
+	withCaptures := manifest + `captures:
+  - type: request
+    length: 64
+  - type: response
+    length: 32
+`
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(withCaptures), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if got := captureSlots(srv); got != "request:64,response:32" {
+		t.Fatalf("captures = %s, want request:64,response:32", got)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(withCaptures), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+}
+
+func TestCaptureValidate(t *testing.T) {
+	cases := []struct {
+		capture Capture
+		ok      bool
+	}{
+		{Capture{Type: "request", Length: 64}, true},
+		{Capture{Type: "response", Length: 1}, true},
+		{Capture{Type: "cookie", Length: 64}, false},
+		{Capture{Type: "request"}, false},
+	}
+	for _, tc := range cases {
+		if err := tc.capture.validate(); (err == nil) != tc.ok {
+			t.Errorf("validate(%+v) error = %v, want ok=%v", tc.capture, err, tc.ok)
+		}
+	}
+}
+
+func TestCreateAndDeleteCapture(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := CreateCapture("web", Capture{Type: "request", Length: 64}, -1, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+		if err := CreateCapture("web", Capture{Type: "response", Length: 16}, 0, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	if got := captureSlots(srv); got != "response:16,request:64" {
+		t.Fatalf("captures after create = %s", got)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteCapture("web", 0); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "capture/web/0 deleted") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if got := captureSlots(srv); got != "request:64" {
+		t.Fatalf("captures after delete = %s", got)
+	}
+}
@@ -18,10 +18,11 @@ limitations under the License.
 package frontends
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"strconv"
 
+	"haproxyctl/cmd/filters"
+	"haproxyctl/cmd/logtargets"
 	"haproxyctl/internal"
 
 	"github.com/spf13/cobra"
@@ -41,6 +42,21 @@ Examples:
     --default-backend webapp \
     --bind address=0.0.0.0,port=80,ssl=enabled
 
+  # several TLS listeners sharing one certificate and ALPN:
+  haproxyctl create frontends myfront \
+    --bind address=0.0.0.0,port=443,ssl=enabled \
+    --bind address=::,port=443,ssl=enabled \
+    --bind-ssl-certificate /etc/haproxy/certs/site.pem \
+    --bind-alpn h2,http/1.1
+
+  # a named dual-stack TLS bind with its own certificate and ALPN:
+  haproxyctl create frontends myfront \
+    --bind name=https,address=::,port=443,v4v6=true,ssl=enabled,ssl_certificate=/etc/haproxy/certs/site.pem,alpn=h2,http/1.1
+
+  # connection limits, logging and options:
+  haproxyctl create frontends myfront --maxconn 10000 --httplog \
+    --monitor-uri /haproxy-health --option dontlognull --option http-ignore-probes
+
   # from manifest (no name on the command line):
   haproxyctl create frontends -f examples/frontend-with-binds.yaml`,
 	Args: cobra.MaximumNArgs(1),
@@ -50,23 +66,23 @@ Examples:
 		// 1) Load from file if requested
 		if fn := internal.GetFlagString(cmd, "file"); fn != "" {
 			if err := frontend.LoadFromFile(fn); err != nil {
-				log.Fatalf("failed to load frontend from file: %v", err)
+				internal.Fatalf("failed to load frontend from file: %v", err)
 			}
 		} else {
 			// 2) Otherwise require exactly one arg
 			if len(args) != 1 {
-				log.Fatalf("frontend name is required when not using -f")
+				internal.FatalCodef(internal.ExitUsage, "frontend name is required when not using -f")
 			}
 			frontend.LoadFromFlags(cmd, args[0])
 		}
 
 		// (rest of your existing logic follows…)
 		if err := frontend.Validate(); err != nil {
-			log.Fatalf("invalid frontend configuration: %v", err)
+			internal.FatalCodef(internal.ExitInvalid, "invalid frontend configuration: %v", err)
 		}
 
 		outFmt := internal.GetFlagString(cmd, "output")
-		dryRun := internal.GetFlagBool(cmd, "dry-run")
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
 		if outFmt != "" || dryRun {
 			if outFmt == "" {
 				outFmt = internal.OutputFormatYAML
@@ -80,26 +96,32 @@ Examples:
 			return
 		}
 
-		version, err := internal.GetConfigurationVersion()
+		apiPayload, err := frontend.toClient()
 		if err != nil {
-			log.Fatalf("failed to fetch HAProxy version: %v", err)
+			internal.Fatalf("failed to create frontend %q: %v", frontend.Name, err)
 		}
-		apiPayload := frontend.ToPayload()
-		_, err = internal.SendRequest("POST",
-			"/services/haproxy/configuration/frontends",
-			map[string]string{"version": strconv.Itoa(version)},
-			apiPayload,
-		)
-		if err != nil {
-			log.Fatalf("failed to create frontend %q: %v", frontend.Name, err)
+		if err := internal.API().CreateFrontend(cmd.Context(), nil, apiPayload); err != nil {
+			internal.Fatalf("failed to create frontend %q: %v", frontend.Name, err)
 		}
 		internal.PrintStatus("Frontend", frontend.Name, internal.ActionCreated)
 
-		for _, b := range frontend.Binds {
-			if err := createBind(frontend.Name, b); err != nil {
-				log.Fatalf("failed to add bind to %q: %v", frontend.Name, err)
+		for _, b := range frontend.EffectiveBinds() {
+			if err := createBind(nil, frontend.Name, b); err != nil {
+				internal.Fatalf("failed to add bind to %q: %v", frontend.Name, err)
 			}
 		}
+		if err := applySwitchingRuleDiff(nil, frontend.Name, nil, frontend.BackendSwitchingRules); err != nil {
+			internal.Fatalf("failed to add backend switching rules to %q: %v", frontend.Name, err)
+		}
+		if err := filters.ApplyDiff(nil, filters.ParentFrontend, frontend.Name, nil, frontend.Filters); err != nil {
+			internal.Fatalf("failed to add filters to %q: %v", frontend.Name, err)
+		}
+		if err := logtargets.ApplyDiff(nil, logtargets.ParentFrontend, frontend.Name, nil, frontend.LogTargets); err != nil {
+			internal.Fatalf("failed to add log targets to %q: %v", frontend.Name, err)
+		}
+		if err := applyCaptureDiff(nil, frontend.Name, nil, frontend.Captures); err != nil {
+			internal.Fatalf("failed to add captures to %q: %v", frontend.Name, err)
+		}
 	},
 }
 
@@ -113,30 +135,35 @@ func init() {
 	CreateFrontendsCmd.Flags().String("timeout-http-keep-alive", "", "timeout http keep-alive")
 	CreateFrontendsCmd.Flags().String("timeout-queue", "", "timeout queue")
 	CreateFrontendsCmd.Flags().String("timeout-server", "", "timeout server")
+	CreateFrontendsCmd.Flags().Int("maxconn", 0, "Maximum concurrent connections accepted by the frontend")
+	CreateFrontendsCmd.Flags().Int("backlog", 0, "Listen queue length for pending connections")
+	CreateFrontendsCmd.Flags().String("monitor-uri", "", "URI answered with 200 by HAProxy itself, e.g. /haproxy-health")
+	CreateFrontendsCmd.Flags().Bool("httplog", false, "Log HTTP requests in the detailed HTTP format")
+	CreateFrontendsCmd.Flags().Bool("tcplog", false, "Log connections in the TCP format")
+	CreateFrontendsCmd.Flags().String("log-format", "", "Custom log format string")
+	CreateFrontendsCmd.Flags().String("http-connection-mode", "", "httpclose, http-server-close or http-keep-alive")
+	CreateFrontendsCmd.Flags().String("stats-uri", "", "Serve the statistics page under this URI (enables stats)")
+	CreateFrontendsCmd.Flags().StringArray("option", nil, "Enable an option such as dontlognull or clitcpka; no-<option> disables it. Repeatable.")
 
 	// Bind flag supports multiple binds
 	CreateFrontendsCmd.Flags().StringArray("bind", nil,
-		"Bind parameters (address=...,port=...,ssl=...). Repeat for multiple binds.")
+		"Bind parameters (name=...,address=...,port=...,ssl=...,ssl_certificate=...,crt_list=...,alpn=...,accept_proxy=...,transparent=...,v4v6=...,tcp_ut=...; sockets: address=/path,mode=...,user=...,group=...). Repeat for multiple binds.")
+	CreateFrontendsCmd.Flags().String("bind-ssl-certificate", "", "Default ssl_certificate for all ssl binds")
+	CreateFrontendsCmd.Flags().String("bind-alpn", "", "Default alpn for all ssl binds (e.g. h2,http/1.1)")
+	CreateFrontendsCmd.Flags().Bool("bind-accept-proxy", false, "Enable accept_proxy on all binds")
 
 	CreateFrontendsCmd.Flags().StringP("output", "o", "", "Output format: yaml or json")
 	CreateFrontendsCmd.Flags().Bool("dry-run", false, "Simulate without applying")
 }
 
-// createBind POSTS a single BindConfig to an existing frontend.
-func createBind(frontendName string, bind BindConfig) error {
-	version, err := internal.GetConfigurationVersion()
+// createBind POSTS a single BindConfig to an existing frontend, as part of
+// tx or as a standalone change when tx is nil.
+func createBind(tx *internal.Transaction, frontendName string, bind BindConfig) error {
+	payload, err := bind.toClient()
 	if err != nil {
-		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+		return err
 	}
-
-	endpoint := fmt.Sprintf("/services/haproxy/configuration/frontends/%s/binds", frontendName)
-	_, err = internal.SendRequest(
-		"POST",
-		endpoint,
-		map[string]string{"version": strconv.Itoa(version)},
-		bind.toPayload(),
-	)
-	if err != nil {
+	if err := internal.API().CreateBind(context.Background(), tx.Client(), frontendName, payload); err != nil {
 		return fmt.Errorf("failed to create bind on frontend %q: %w", frontendName, err)
 	}
 
@@ -144,43 +171,23 @@ func createBind(frontendName string, bind BindConfig) error {
 }
 
 // updateBind replaces an existing bind on a frontend, addressed by its
-// underlying name. The BindConfig's Name field is not exposed in YAML
-// manifests but is preserved internally when editing.
-func updateBind(frontendName string, bind BindConfig) error {
-	version, err := internal.GetConfigurationVersion()
+// name.
+func updateBind(tx *internal.Transaction, frontendName string, bind BindConfig) error {
+	payload, err := bind.toClient()
 	if err != nil {
-		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+		return err
 	}
-
-	endpoint := fmt.Sprintf("/services/haproxy/configuration/frontends/%s/binds/%s", frontendName, bind.Name)
-	_, err = internal.SendRequest(
-		"PUT",
-		endpoint,
-		map[string]string{"version": strconv.Itoa(version)},
-		bind.toPayload(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to update bind %q on frontend %q: %w", bind.Name, frontendName, err)
+	payload.Name = bind.apiName()
+	if err := internal.API().UpdateBind(context.Background(), tx.Client(), frontendName, payload); err != nil {
+		return fmt.Errorf("failed to update bind %q on frontend %q: %w", bind.apiName(), frontendName, err)
 	}
 
 	return nil
 }
 
 // deleteBind removes an existing bind from a frontend by name.
-func deleteBind(frontendName, bindName string) error {
-	version, err := internal.GetConfigurationVersion()
-	if err != nil {
-		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
-	}
-
-	endpoint := fmt.Sprintf("/services/haproxy/configuration/frontends/%s/binds/%s", frontendName, bindName)
-	_, err = internal.SendRequest(
-		"DELETE",
-		endpoint,
-		map[string]string{"version": strconv.Itoa(version)},
-		nil,
-	)
-	if err != nil {
+func deleteBind(tx *internal.Transaction, frontendName, bindName string) error {
+	if err := internal.API().DeleteBind(context.Background(), tx.Client(), frontendName, bindName); err != nil {
 		return fmt.Errorf("failed to delete bind %q from frontend %q: %w", bindName, frontendName, err)
 	}
 
@@ -18,8 +18,7 @@ limitations under the License.
 package frontends
 
 import (
-	"log"
-	"strconv"
+	"context"
 
 	"haproxyctl/internal"
 
@@ -31,27 +30,16 @@ var DeleteFrontendsCmd = &cobra.Command{
 	Use:   "frontends <frontend_name>",
 	Short: "Delete a specific HAProxy frontend",
 	Args:  cobra.ExactArgs(1),
-	Run: func(_ *cobra.Command, args []string) {
+	Run: func(cmd *cobra.Command, args []string) {
 		frontendName := args[0]
-		deleteFrontend(frontendName)
+		deleteFrontend(cmd.Context(), frontendName)
 	},
 }
 
 // deleteFrontend handles frontend deletion.
-func deleteFrontend(frontendName string) {
-	version, err := internal.GetConfigurationVersion()
-	if err != nil {
-		log.Fatalf("Failed to fetch HAProxy configuration version: %v", err)
-	}
-
-	endpoint := "/services/haproxy/configuration/frontends/" + frontendName
-	_, err = internal.SendRequest("DELETE",
-		endpoint,
-		map[string]string{"version": strconv.Itoa(version)},
-		nil,
-	)
-	if err != nil {
-		log.Fatalf("Failed to delete frontend '%s': %v", frontendName, err)
+func deleteFrontend(ctx context.Context, frontendName string) {
+	if err := internal.API().DeleteFrontend(ctx, nil, frontendName); err != nil {
+		internal.Fatalf("Failed to delete frontend '%s': %v", frontendName, err)
 	}
 
 	internal.PrintStatus("Frontend", frontendName, internal.ActionDeleted)
@@ -18,9 +18,9 @@ limitations under the License.
 package frontends
 
 import (
+	"context"
 	"fmt"
 	"haproxyctl/internal"
-	"log"
 	"os"
 	"strconv"
 	"strings"
@@ -32,53 +32,63 @@ import (
 var DescribeFrontendsCmd = &cobra.Command{
 	Use:   "frontends <frontend_name>",
 	Short: "Describe a specific HAProxy frontend and its binds",
-	Args:  cobra.ExactArgs(1),
-	Run: func(_ *cobra.Command, args []string) {
+	Long: `Describe a frontend: every setting it has, grouped into sections such
+as listeners, timeouts and logging, followed by its rules. --show-empty
+also lists the settings that are not set.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
 		frontendName := args[0]
-		describeFrontend(frontendName)
+		describeFrontend(cmd.Context(), frontendName, internal.GetFlagBool(cmd, "show-empty"))
 	},
 }
 
+func init() {
+	DescribeFrontendsCmd.Flags().Bool("show-empty", false, "Also list settings that are not set")
+}
+
 // describeFrontend fetches a frontend and its binds, and prints a detailed description.
-func describeFrontend(frontendName string) {
-	frontend, err := internal.GetResource("/services/haproxy/configuration/frontends/" + frontendName)
+func describeFrontend(ctx context.Context, frontendName string, showEmpty bool) {
+	frontend, err := internal.GetResourceWithContext(ctx, "/services/haproxy/configuration/frontends/"+frontendName)
 	if err != nil {
-		log.Fatalf("Failed to fetch frontend '%s': %v", frontendName, err)
+		internal.Fatalf("Failed to fetch frontend '%s': %v", frontendName, err)
 	}
 
 	// Attach binds to the frontend object so they can be shown in the "listeners" section.
-	internal.EnrichFrontendWithBinds(frontend)
+	internal.EnrichFrontendWithBinds(ctx, frontend)
 
-	httpRequestRules := fetchFrontendListSection(frontendName, "HTTP request rules", "/services/haproxy/configuration/frontends/"+frontendName+"/http_request_rules")
-	httpResponseRules := fetchFrontendListSection(frontendName, "HTTP response rules", "/services/haproxy/configuration/frontends/"+frontendName+"/http_response_rules")
-	tcpRequestRules := fetchFrontendListSection(frontendName, "TCP request rules", "/services/haproxy/configuration/frontends/"+frontendName+"/tcp_request_rules")
+	sections := make([][]map[string]interface{}, len(frontendRuleSections))
+	_ = internal.ForEachConcurrent(ctx, len(frontendRuleSections), func(ctx context.Context, i int) error {
+		s := frontendRuleSections[i]
+		sections[i] = fetchFrontendListSection(ctx, frontendName, s.title, "/services/haproxy/configuration/frontends/"+frontendName+"/"+s.child)
+		return nil
+	})
 
-	internal.PrintResourceDescription("Frontend", frontend, frontendDescriptionSections(), nil)
+	internal.PrintResourceDescription("Frontend", frontend, nil, internal.DescribeOptions{
+		Fields:    append(internal.SchemaFields(frontendPayload{}), "binds"),
+		ShowEmpty: showEmpty,
+	})
 
-	printRuleSection("HTTP Request Rules", httpRequestRules)
-	printRuleSection("HTTP Response Rules", httpResponseRules)
-	printRuleSection("TCP Request Rules", tcpRequestRules)
+	for i, s := range frontendRuleSections {
+		printRuleSection(s.title, sections[i])
+	}
 }
 
-// frontendDescriptionSections defines the sections and fields to display in frontend descriptions.
-func frontendDescriptionSections() map[string][]string {
-	return map[string][]string{
-		"basic":     {"name", "mode", "default_backend"},
-		"listeners": {"binds"},
-		"timeouts":  {"timeout_client", "timeout_http_request", "timeout_http_keep_alive"},
-		"logging":   {"log"},
-		"options":   {"forwardfor"},
-	}
+// frontendRuleSections are the rule lists describe prints after the
+// frontend settings, in order.
+var frontendRuleSections = []struct{ title, child string }{
+	{"HTTP Request Rules", "http_request_rules"},
+	{"HTTP Response Rules", "http_response_rules"},
+	{"TCP Request Rules", "tcp_request_rules"},
 }
 
 // fetchFrontendListSection retrieves a list-valued configuration section for a frontend,
 // such as HTTP/TCP rules. Failures are logged as warnings so that describe output
 // remains as complete as possible.
-func fetchFrontendListSection(frontendName, sectionLabel, endpoint string) []map[string]interface{} {
-	list, err := internal.GetResourceList(endpoint)
+func fetchFrontendListSection(ctx context.Context, frontendName, sectionLabel, endpoint string) []map[string]interface{} {
+	list, err := internal.GetResourceListWithContext(ctx, endpoint)
 	if err != nil {
 		if !internal.IsNotFoundError(err) {
-			log.Printf("warning: failed to fetch %s for frontend %q: %v", sectionLabel, frontendName, err)
+			internal.Warnf("failed to fetch %s for frontend %q: %v", sectionLabel, frontendName, err)
 		}
 		return nil
 	}
@@ -92,7 +102,7 @@ func printRuleSection(title string, rules []map[string]interface{}) {
 	}
 
 	if _, err := fmt.Fprintf(os.Stdout, "\n%s:\n", title); err != nil {
-		log.Printf("warning: failed to write %s header: %v", title, err)
+		internal.Warnf("failed to write %s header: %v", title, err)
 		return
 	}
 
@@ -102,7 +112,7 @@ func printRuleSection(title string, rules []map[string]interface{}) {
 			continue
 		}
 		if _, err := fmt.Fprintf(os.Stdout, "- %s\n", line); err != nil {
-			log.Printf("warning: failed to write %s line: %v", title, err)
+			internal.Warnf("failed to write %s line: %v", title, err)
 			return
 		}
 	}
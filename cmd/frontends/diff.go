@@ -0,0 +1,88 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package frontends provides commands to manage HAProxy frontends.
+package frontends
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CompareFrontendManifest returns the live and desired state of a frontend
+// manifest, normalized the same way apply compares them: bind_defaults
+// folded into the binds and binds sorted by address and port. Backend
+// switching rules, filters and log targets are only compared when the
+// manifest lists them.
+func CompareFrontendManifest(data []byte) (internal.ManifestComparison, error) {
+	var manifest frontendWithBinds
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return internal.ManifestComparison{}, fmt.Errorf("failed to parse frontend manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return internal.ManifestComparison{}, fmt.Errorf("invalid frontend configuration: %w", err)
+	}
+	manifest.normalizeDurations()
+
+	cmp := internal.ManifestComparison{Kind: "Frontend", Name: manifest.Name}
+
+	current, exists, err := fetchCurrentFrontend(context.Background(), manifest.Name)
+	if err != nil {
+		return cmp, err
+	}
+
+	manifest.APIVersion = "haproxyctl/v1"
+	manifest.Kind = "Frontend"
+	manifest.Binds = sortBinds(manifest.EffectiveBinds())
+	manifest.BindDefaults = nil
+	cmp.Desired = manifest
+
+	if exists {
+		current.APIVersion = manifest.APIVersion
+		current.Kind = manifest.Kind
+		current.Binds = sortBinds(current.Binds)
+		if manifest.BackendSwitchingRules == nil {
+			current.BackendSwitchingRules = nil
+		}
+		if manifest.Filters == nil {
+			current.Filters = nil
+		}
+		if manifest.LogTargets == nil {
+			current.LogTargets = nil
+		}
+		if manifest.Captures == nil {
+			current.Captures = nil
+		}
+		cmp.Live = current
+	}
+	return cmp, nil
+}
+
+func sortBinds(in []BindConfig) []BindConfig {
+	out := append([]BindConfig(nil), in...)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Address != out[j].Address {
+			return out[i].Address < out[j].Address
+		}
+		return out[i].Port < out[j].Port
+	})
+	return out
+}
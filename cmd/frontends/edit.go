@@ -19,11 +19,12 @@ package frontends
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"haproxyctl/cmd/filters"
+	"haproxyctl/cmd/logtargets"
 	"haproxyctl/internal"
-	"log"
 	"os"
-	"strconv"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -35,33 +36,34 @@ var EditFrontendsCmd = &cobra.Command{
 	Aliases: []string{"frontend"},
 	Short:   "Edit a frontend definition in your editor",
 	Args:    cobra.ExactArgs(1),
-	Run: func(_ *cobra.Command, args []string) {
+	Run: func(cmd *cobra.Command, args []string) {
 		frontendName := args[0]
-		if err := editFrontend(frontendName); err != nil {
-			log.Fatalf("Edit failed: %v", err)
+		if err := editFrontend(cmd.Context(), frontendName); err != nil {
+			internal.Fatalf("Edit failed: %v", err)
 		}
 	},
 }
 
-func editFrontend(frontendName string) error {
-	cfgVer, err := internal.GetConfigurationVersion()
-	if err != nil {
+func editFrontend(ctx context.Context, frontendName string) error {
+	// The update below is based on this version (see internal.WriteParams),
+	// the one the editor starts from.
+	if _, err := internal.GetConfigurationVersion(); err != nil {
 		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
 	}
 
-	rawFrontend, err := internal.GetResource(
-		"/services/haproxy/configuration/frontends/" + frontendName,
+	rawFrontend, err := internal.GetResourceWithContext(ctx,
+		"/services/haproxy/configuration/frontends/"+frontendName,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to fetch frontend %q: %w", frontendName, err)
 	}
 
-	rawBinds, err := internal.GetResourceList(
-		"/services/haproxy/configuration/frontends/" + frontendName + "/binds",
+	rawBinds, err := internal.GetResourceListWithContext(ctx,
+		"/services/haproxy/configuration/frontends/"+frontendName+"/binds",
 	)
 	if err != nil {
 		// Treat missing/empty binds as non-fatal
-		log.Printf("warning: failed to fetch binds for frontend %q: %v", frontendName, err)
+		internal.Warnf("failed to fetch binds for frontend %q: %v", frontendName, err)
 	}
 
 	// Build a manifest-style object: frontendWithBinds.
@@ -73,11 +75,28 @@ func editFrontend(frontendName string) error {
 
 	for _, bind := range rawBinds {
 		bc := mapBindFromAPI(bind)
-		if bc.Address != "" && bc.Port != 0 {
+		if bc.Address != "" && (bc.Port != 0 || bc.isSocket()) {
 			manifest.Binds = append(manifest.Binds, bc)
 		}
 	}
 
+	manifest.BackendSwitchingRules, err = liveSwitchingRules(ctx, frontendName)
+	if err != nil {
+		internal.Warnf("failed to fetch backend switching rules for frontend %q: %v", frontendName, err)
+	}
+	manifest.Filters, err = filters.LiveWithContext(ctx, filters.ParentFrontend, frontendName)
+	if err != nil {
+		internal.Warnf("failed to fetch filters for frontend %q: %v", frontendName, err)
+	}
+	manifest.LogTargets, err = logtargets.LiveWithContext(ctx, logtargets.ParentFrontend, frontendName)
+	if err != nil {
+		internal.Warnf("failed to fetch log targets for frontend %q: %v", frontendName, err)
+	}
+	manifest.Captures, err = liveCaptures(ctx, frontendName)
+	if err != nil {
+		internal.Warnf("failed to fetch captures for frontend %q: %v", frontendName, err)
+	}
+
 	origYAML, err := yaml.Marshal(manifest)
 	if err != nil {
 		return fmt.Errorf("failed to marshal frontend manifest to YAML: %w", err)
@@ -89,7 +108,7 @@ func editFrontend(frontendName string) error {
 	}
 	defer func() {
 		if rmErr := os.Remove(tmpFile); rmErr != nil {
-			log.Printf("warning: failed to remove temp file %q: %v", tmpFile, rmErr)
+			internal.Warnf("failed to remove temp file %q: %v", tmpFile, rmErr)
 		}
 	}()
 
@@ -122,75 +141,100 @@ func editFrontend(frontendName string) error {
 		return fmt.Errorf("invalid frontend configuration: %w", err)
 	}
 
-	payload := edited.ToPayload()
-
-	_, err = internal.SendRequest(
-		"PUT",
-		"/services/haproxy/configuration/frontends/"+frontendName,
-		map[string]string{"version": strconv.Itoa(cfgVer)},
-		payload,
-	)
+	payload, err := edited.toClient()
 	if err != nil {
+		return err
+	}
+	if err := internal.API().UpdateFrontend(ctx, nil, payload); err != nil {
 		return fmt.Errorf("failed to update frontend %q: %w", frontendName, err)
 	}
 
-	if err := applyBindDiff(frontendName, manifest.Binds, edited.Binds); err != nil {
+	if err := applyBindDiff(nil, frontendName, manifest.Binds, edited.EffectiveBinds()); err != nil {
 		return fmt.Errorf("failed to apply bind changes for frontend %q: %w", frontendName, err)
 	}
 
+	// The editor shows every rule, so a removed key means no rules.
+	if err := applySwitchingRuleDiff(nil, frontendName, manifest.BackendSwitchingRules, edited.BackendSwitchingRules); err != nil {
+		return fmt.Errorf("failed to apply backend switching rule changes for frontend %q: %w", frontendName, err)
+	}
+	if err := filters.ApplyDiff(nil, filters.ParentFrontend, frontendName, manifest.Filters, edited.Filters); err != nil {
+		return fmt.Errorf("failed to apply filter changes for frontend %q: %w", frontendName, err)
+	}
+	if err := logtargets.ApplyDiff(nil, logtargets.ParentFrontend, frontendName, manifest.LogTargets, edited.LogTargets); err != nil {
+		return fmt.Errorf("failed to apply log target changes for frontend %q: %w", frontendName, err)
+	}
+	if err := applyCaptureDiff(nil, frontendName, manifest.Captures, edited.Captures); err != nil {
+		return fmt.Errorf("failed to apply capture changes for frontend %q: %w", frontendName, err)
+	}
+
 	internal.PrintStatus("Frontend", frontendName, internal.ActionConfigured)
 	return nil
 }
 
 // mapBindFromAPI converts a generic bind object into a BindConfig suitable
-// for inclusion in frontendWithBinds manifests. It keeps the underlying
-// bind name (if any) for update/delete operations but does not expose it
-// in the YAML, where binds are treated as anonymous sub-resources.
+// for inclusion in frontendWithBinds manifests. The default
+// "<address>:<port>" name is left out, since apiName derives it again.
 func mapBindFromAPI(obj map[string]interface{}) BindConfig {
 	var b BindConfig
 
-	if v, ok := obj["name"].(string); ok {
-		b.Name = v
-	}
 	if v, ok := obj["address"].(string); ok {
 		b.Address = v
 	}
-	if p, ok := getIntField(obj, "port"); ok {
+	if p, ok := internal.GetIntField(obj, "port"); ok {
 		b.Port = p
 	}
+	if v, ok := obj["name"].(string); ok && v != b.defaultName() {
+		b.Name = v
+	}
 	if v, ok := obj["ssl"].(string); ok && v == "enabled" {
 		b.SSL = true
 	}
+	if v, ok := obj["ssl_certificate"].(string); ok {
+		b.SSLCertificate = v
+	}
+	if v, ok := obj["alpn"].(string); ok {
+		b.ALPN = v
+	}
+	if v, ok := obj["crt_list"].(string); ok {
+		b.CrtList = v
+	}
+	if v, ok := obj["accept_proxy"].(bool); ok {
+		b.AcceptProxy = v
+	}
+	b.Transparent, _ = obj["transparent"].(bool)
+	b.V4V6, _ = obj["v4v6"].(bool)
+	if ms, ok := internal.GetIntField(obj, "tcp_user_timeout"); ok {
+		b.TCPUserTimeout = internal.FormatMillisAsDuration(ms)
+	}
+	b.Mode, _ = obj["mode"].(string)
+	b.User, _ = obj["user"].(string)
+	b.Group, _ = obj["group"].(string)
 
 	return b
 }
 
 // applyBindDiff reconciles the bind list for a frontend based on the
-// original and edited manifests. Since bind names are not exposed in
-// the manifest, identity is determined by address+port; when a match
-// is found, the original name is preserved for update/delete calls.
-func applyBindDiff(frontendName string, before, after []BindConfig) error {
+// original and edited manifests. Identity is determined by address+port,
+// or by the path for socket binds.
+// A bind without a name keeps its live name; a bind whose name changed
+// is deleted and created again, since the name addresses it in the API.
+//
+// Changes are staged in tx when it is non-nil.
+func applyBindDiff(tx *internal.Transaction, frontendName string, before, after []BindConfig) error {
 	beforeByKey := make(map[string]BindConfig, len(before))
 	for _, b := range before {
-		key := fmt.Sprintf("%s:%d", b.Address, b.Port)
-		beforeByKey[key] = b
+		beforeByKey[b.key()] = b
 	}
 
 	afterByKey := make(map[string]BindConfig, len(after))
 	for _, b := range after {
-		key := fmt.Sprintf("%s:%d", b.Address, b.Port)
-		afterByKey[key] = b
+		afterByKey[b.key()] = b
 	}
 
 	// Deletes: present before, missing after.
 	for key, oldB := range beforeByKey {
 		if _, ok := afterByKey[key]; !ok {
-			// If we don't have a name, we can't address this bind via the API.
-			if oldB.Name == "" {
-				log.Printf("warning: cannot delete bind %q on frontend %q: missing underlying name", key, frontendName)
-				continue
-			}
-			if err := deleteBind(frontendName, oldB.Name); err != nil {
+			if err := deleteBind(tx, frontendName, oldB.apiName()); err != nil {
 				return err
 			}
 		}
@@ -200,26 +244,30 @@ func applyBindDiff(frontendName string, before, after []BindConfig) error {
 	for key, newB := range afterByKey {
 		oldB, existed := beforeByKey[key]
 		if !existed {
-			// New bind (no existing name needed).
-			if err := createBind(frontendName, newB); err != nil {
+			if err := createBind(tx, frontendName, newB); err != nil {
 				return err
 			}
 			continue
 		}
 
-		// Preserve the underlying name for updates.
-		newB.Name = oldB.Name
-
 		if bindConfigEqual(oldB, newB) {
 			continue
 		}
 
 		if newB.Name == "" {
-			log.Printf("warning: cannot update bind %q on frontend %q: missing underlying name", key, frontendName)
+			newB.Name = oldB.Name
+		}
+		if newB.apiName() != oldB.apiName() {
+			if err := deleteBind(tx, frontendName, oldB.apiName()); err != nil {
+				return err
+			}
+			if err := createBind(tx, frontendName, newB); err != nil {
+				return err
+			}
 			continue
 		}
 
-		if err := updateBind(frontendName, newB); err != nil {
+		if err := updateBind(tx, frontendName, newB); err != nil {
 			return err
 		}
 	}
@@ -227,11 +275,11 @@ func applyBindDiff(frontendName string, before, after []BindConfig) error {
 	return nil
 }
 
-// bindConfigEqual compares the fields of two BindConfig objects that
-// matter to the Data Plane API (address, port, ssl), ignoring the
-// internal Name used only for addressing.
+// bindConfigEqual reports whether the desired bind b matches the live
+// bind a. The name only counts when b sets one.
 func bindConfigEqual(a, b BindConfig) bool {
-	return a.Address == b.Address &&
-		a.Port == b.Port &&
-		a.SSL == b.SSL
+	if b.Name == "" || b.apiName() == a.apiName() {
+		a.Name, b.Name = "", ""
+	}
+	return a == b
 }
@@ -18,10 +18,9 @@ limitations under the License.
 package frontends
 
 import (
+	"context"
 	"fmt"
 	"haproxyctl/internal"
-	"log"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -41,7 +40,34 @@ var GetFrontendsCmd = &cobra.Command{
 	},
 }
 
+// frontendColumns are the table columns of "get frontends"; the routing and
+// listening details are only shown with -o wide.
+var frontendColumns = internal.ColumnSet{
+	Kind:    "Frontend",
+	Default: []string{"name", "mode", "maxconn"},
+	Wide:    []string{"default_backend", "binds", "client_timeout"},
+}
+
 func getFrontends(cmd *cobra.Command, frontendName string) {
+	watch := internal.WatchRequested(cmd)
+	if watch && frontendName != "" {
+		internal.FatalCodef(internal.ExitUsage, "--watch only works when listing frontends")
+	}
+
+	outputFormat := internal.GetFlagString(cmd, "output")
+	if outputFormat == "" {
+		outputFormat = "table"
+	}
+
+	if internal.ManifestFileOutput(cmd, outputFormat) {
+		data, err := frontendManifestOutput(cmd.Context(), frontendName)
+		if err != nil {
+			internal.Fatalf("Failed to fetch frontend(s): %v", err)
+		}
+		internal.FormatOutputForCmd(cmd, data, outputFormat)
+		return
+	}
+
 	var data interface{}
 	var err error
 
@@ -49,18 +75,19 @@ func getFrontends(cmd *cobra.Command, frontendName string) {
 		data, err = internal.GetResource("/services/haproxy/configuration/frontends/" + frontendName)
 		if err == nil {
 			if frontend, ok := data.(map[string]interface{}); ok {
-				internal.EnrichFrontendWithBinds(frontend)
+				internal.EnrichFrontendWithBinds(cmd.Context(), frontend)
 			}
 		}
 	} else {
 		data, err = internal.GetResourceList("/services/haproxy/configuration/frontends")
 		if err == nil {
 			if frontendList, ok := data.([]map[string]interface{}); ok {
-				for i := range frontendList {
-					internal.EnrichFrontendWithBinds(frontendList[i])
+				if err := internal.EnrichFrontendsWithBinds(cmd.Context(), frontendList); err != nil {
+					internal.Fatalf("Failed to fetch frontends: %v", err)
 				}
 
 				internal.SortByStringField(frontendList, "name")
+				internal.SortForCmd(cmd, frontendList)
 				data = frontendList
 			}
 		}
@@ -68,22 +95,87 @@ func getFrontends(cmd *cobra.Command, frontendName string) {
 
 	if err != nil {
 		if frontendName != "" && internal.IsNotFoundError(err) {
-			_, _ = fmt.Fprintln(os.Stdout, internal.ResourceID("Frontend", frontendName)+" not found")
-			return
+			internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Frontend", frontendName))
 		}
-		log.Fatalf("Failed to fetch frontend(s): %v", err)
+		internal.Fatalf("Failed to fetch frontend(s): %v", err)
 	}
 
-	outputFormat := internal.GetFlagString(cmd, "output")
+	internal.FormatOutputForCmd(cmd, internal.WithColumns(data, frontendColumns), outputFormat)
 
-	if outputFormat == "" {
-		outputFormat = "table"
+	if watch {
+		if err := internal.WatchListForCmd(cmd, "Frontend", "/services/haproxy/configuration/frontends"); err != nil {
+			internal.Fatalf("Failed to watch frontends: %v", err)
+		}
+	}
+}
+
+// FrontendManifests returns every frontend, with its binds, as a manifest
+// that apply accepts, sorted by name.
+func FrontendManifests(ctx context.Context) ([]interface{}, error) {
+	list, err := internal.GetResourceListWithContext(ctx, "/services/haproxy/configuration/frontends")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch frontends: %w", err)
+	}
+	internal.SortByStringField(list, "name")
+
+	// Each object takes several requests; fetch them concurrently.
+	fetched := make([]*frontendWithBinds, len(list))
+	err = internal.ForEachConcurrent(ctx, len(list), func(ctx context.Context, i int) error {
+		name, _ := list[i]["name"].(string)
+		current, exists, err := fetchCurrentFrontend(ctx, name)
+		if err != nil || !exists {
+			return err
+		}
+		fetched[i] = &current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]interface{}, 0, len(list))
+	for _, current := range fetched {
+		if current == nil {
+			continue
+		}
+		manifests = append(manifests, frontendManifest(*current))
 	}
+	return manifests, nil
+}
+
+// frontendManifest returns current, as fetched by fetchCurrentFrontend, as
+// a manifest.
+func frontendManifest(current frontendWithBinds) frontendWithBinds {
+	current.APIVersion = "haproxyctl/v1"
+	current.Kind = "Frontend"
+	current.Binds = sortBinds(current.Binds)
+	return current
+}
 
-	internal.FormatOutput(data, outputFormat)
+// frontendManifestOutput returns what "get frontends" writes to
+// --output-file: the manifest of frontendName, or a List of every
+// frontend's manifest when frontendName is empty.
+func frontendManifestOutput(ctx context.Context, frontendName string) (interface{}, error) {
+	if frontendName == "" {
+		manifests, err := FrontendManifests(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return internal.ManifestListOf(manifests), nil
+	}
+
+	current, exists, err := fetchCurrentFrontend(ctx, frontendName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Frontend", frontendName))
+	}
+	return frontendManifest(current), nil
 }
 
 func init() {
 	// Ensure this command also inherits the `-o` flag.
-	GetFrontendsCmd.Flags().StringP("output", "o", "", "Output format: yaml or json")
+	GetFrontendsCmd.Flags().StringP("output", "o", "", "Output format: table, wide, yaml, or json")
+	internal.AddWatchFlags(GetFrontendsCmd)
 }
@@ -0,0 +1,135 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package frontends provides commands to manage HAProxy frontends.
+package frontends
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	stateEnabled  = "enabled"
+	stateDisabled = "disabled"
+)
+
+var httpConnectionModes = []string{"httpclose", "http-server-close", "http-keep-alive"}
+
+// StatsOptions are the "stats" settings that serve the statistics page
+// from a frontend. RefreshDelay is in seconds, as in HAProxy.
+//
+//nolint:tagliatelle
+type StatsOptions struct {
+	Enable       bool   `json:"stats_enable,omitempty" yaml:"stats_enable,omitempty"`
+	URIPrefix    string `json:"stats_uri_prefix,omitempty" yaml:"stats_uri_prefix,omitempty"`
+	RefreshDelay int    `json:"stats_refresh_delay,omitempty" yaml:"stats_refresh_delay,omitempty"`
+	Admin        bool   `json:"stats_admin,omitempty" yaml:"stats_admin,omitempty"`
+	ShowLegends  bool   `json:"stats_show_legends,omitempty" yaml:"stats_show_legends,omitempty"`
+	HideVersion  bool   `json:"stats_hide_version,omitempty" yaml:"stats_hide_version,omitempty"`
+}
+
+// mapStatsOptionsFromAPI converts an API stats_options object.
+func mapStatsOptionsFromAPI(obj map[string]interface{}) *StatsOptions {
+	s := &StatsOptions{}
+	s.Enable, _ = obj["stats_enable"].(bool)
+	s.URIPrefix, _ = obj["stats_uri_prefix"].(string)
+	s.RefreshDelay, _ = internal.GetIntField(obj, "stats_refresh_delay")
+	s.Admin, _ = obj["stats_admin"].(bool)
+	s.ShowLegends, _ = obj["stats_show_legends"].(bool)
+	s.HideVersion, _ = obj["stats_hide_version"].(bool)
+	return s
+}
+
+// durations lists the frontend's timeout fields by their API name.
+func (c *frontendConfig) durations() map[string]*string {
+	return map[string]*string{
+		"timeout_client":          &c.TimeoutClient,
+		"timeout_http_request":    &c.TimeoutHTTPRequest,
+		"timeout_http_keep_alive": &c.TimeoutHTTPKeepAlive,
+		"timeout_queue":           &c.TimeoutQueue,
+		"timeout_server":          &c.TimeoutServer,
+	}
+}
+
+// normalizeDurations renders the timeouts the way they come back from the
+// API (e.g. 60s as 1m0s), so manifests and live frontends compare equal.
+func (c *frontendConfig) normalizeDurations() {
+	for _, value := range c.durations() {
+		if ms, err := internal.ParseDurationToMillis(*value); err == nil {
+			*value = internal.FormatMillisAsDuration(ms)
+		}
+	}
+}
+
+// options lists the frontend's "option" switches by their API name.
+func (c *frontendConfig) options() map[string]*string {
+	return map[string]*string{
+		"clitcpka":            &c.Clitcpka,
+		"contstats":           &c.Contstats,
+		"dontlog_normal":      &c.DontlogNormal,
+		"dontlognull":         &c.Dontlognull,
+		"http_ignore_probes":  &c.HTTPIgnoreProbes,
+		"log_separate_errors": &c.LogSeparateErrors,
+		"logasap":             &c.Logasap,
+		"socket_stats":        &c.SocketStats,
+	}
+}
+
+// setOption applies an "--option" flag: the HAProxy option name, e.g.
+// dontlognull or http-ignore-probes, or "no-<name>" to disable it.
+func (c *frontendConfig) setOption(value string) error {
+	state := stateEnabled
+	name := value
+	if rest, ok := strings.CutPrefix(value, "no-"); ok {
+		state, name = stateDisabled, rest
+	}
+	field, ok := c.options()[strings.ReplaceAll(name, "-", "_")]
+	if !ok {
+		return fmt.Errorf("unknown frontend option %q", value)
+	}
+	*field = state
+	return nil
+}
+
+func (c *frontendConfig) validateOptions() error {
+	options := c.options()
+	for _, name := range slices.Sorted(maps.Keys(options)) {
+		if state := options[name]; *state != "" && *state != stateEnabled && *state != stateDisabled {
+			return fmt.Errorf("invalid %s %q (allowed: enabled, disabled)", name, *state)
+		}
+	}
+	if c.HTTPConnectionMode != "" && !slices.Contains(httpConnectionModes, c.HTTPConnectionMode) {
+		return fmt.Errorf("invalid http_connection_mode %q (allowed: httpclose, http-server-close, http-keep-alive)", c.HTTPConnectionMode)
+	}
+	if c.Maxconn < 0 || c.Backlog < 0 {
+		return errors.New("maxconn and backlog cannot be negative")
+	}
+	if c.Httplog && c.Tcplog {
+		return errors.New("httplog and tcplog are mutually exclusive")
+	}
+	if c.MonitorURI != "" && !strings.HasPrefix(c.MonitorURI, "/") {
+		return fmt.Errorf("monitor_uri %q must start with /", c.MonitorURI)
+	}
+	if c.StatsOptions != nil && c.StatsOptions.RefreshDelay < 0 {
+		return errors.New("stats_refresh_delay cannot be negative")
+	}
+	return nil
+}
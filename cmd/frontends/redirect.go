@@ -0,0 +1,188 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package frontends provides commands to manage HAProxy frontends.
+package frontends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultRedirectCode = 301
+	httpPort            = 80
+)
+
+var allowedRedirectCodes = []int{301, 302, 303, 307, 308}
+
+// EnableHTTPSRedirectCmd represents "enable https-redirect <frontend>".
+var EnableHTTPSRedirectCmd = &cobra.Command{
+	Use:   "https-redirect <frontend_name>",
+	Short: "Redirect plain HTTP requests on a frontend to HTTPS",
+	Long: `Add the standard "http-request redirect scheme https unless { ssl_fc }"
+rule as the frontend's first http-request rule. With --bind, a plain HTTP
+bind on port 80 is added as well when the frontend does not have one yet.
+Both changes are made in one transaction; running the command again is a
+no-op.
+
+Examples:
+  haproxyctl enable https-redirect web
+  haproxyctl enable https-redirect web --code 308 --bind
+  haproxyctl enable https-redirect web --bind --bind-address :: --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := httpsRedirectOptions{
+			Code:        internal.GetFlagInt(cmd, "code"),
+			AddBind:     internal.GetFlagBool(cmd, "bind"),
+			BindAddress: internal.GetFlagString(cmd, "bind-address"),
+			DryRun:      internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline(),
+		}
+		if err := EnableHTTPSRedirect(args[0], opts); err != nil {
+			internal.Fatalf("Failed to enable HTTPS redirect on frontend '%s': %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	EnableHTTPSRedirectCmd.Flags().Int("code", defaultRedirectCode, "Redirect status code (301, 302, 303, 307 or 308)")
+	EnableHTTPSRedirectCmd.Flags().Bool("bind", false, "Also add a port 80 bind if the frontend has none")
+	EnableHTTPSRedirectCmd.Flags().String("bind-address", "0.0.0.0", "Address for the port 80 bind added by --bind")
+	EnableHTTPSRedirectCmd.Flags().Bool("dry-run", false, "Print the rule (and bind) without creating them")
+}
+
+type httpsRedirectOptions struct {
+	Code        int
+	AddBind     bool
+	BindAddress string
+	DryRun      bool
+}
+
+// httpsRedirectRule returns the http-request rule payload for the redirect.
+func httpsRedirectRule(code int) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "redirect",
+		"redir_type":  "scheme",
+		"redir_value": "https",
+		"redir_code":  code,
+		"cond":        "unless",
+		"cond_test":   "{ ssl_fc }",
+	}
+}
+
+// isHTTPSRedirectRule reports whether an API rule already redirects to https.
+func isHTTPSRedirectRule(rule map[string]interface{}) bool {
+	return rule["type"] == "redirect" && rule["redir_type"] == "scheme" && rule["redir_value"] == "https"
+}
+
+// EnableHTTPSRedirect adds the https redirect rule (and optionally a port 80
+// bind) to a frontend in a single transaction.
+func EnableHTTPSRedirect(frontendName string, opts httpsRedirectOptions) error {
+	if !slices.Contains(allowedRedirectCodes, opts.Code) {
+		return fmt.Errorf("invalid redirect code %d (allowed: 301, 302, 303, 307, 308)", opts.Code)
+	}
+
+	rule := httpsRedirectRule(opts.Code)
+	bind := BindConfig{Name: "http", Address: opts.BindAddress, Port: httpPort}
+
+	if opts.DryRun {
+		preview := map[string]interface{}{"http_request_rule": rule}
+		if opts.AddBind {
+			preview["bind"] = bind
+		}
+		internal.FormatOutput(preview, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	base := "/services/haproxy/configuration/frontends/" + frontendName
+	if _, err := internal.GetResource(base); err != nil {
+		return internal.FormatAPIError("Frontend", frontendName, "get", err)
+	}
+
+	rules, err := internal.GetResourceList(base + "/http_request_rules")
+	if err != nil {
+		return fmt.Errorf("failed to fetch http-request rules: %w", err)
+	}
+	needRule := true
+	for _, r := range rules {
+		if isHTTPSRedirectRule(r) {
+			needRule = false
+			break
+		}
+	}
+
+	needBind := false
+	if opts.AddBind {
+		rawBinds, err := internal.GetResourceList(base + "/binds")
+		if err != nil {
+			return fmt.Errorf("failed to fetch binds: %w", err)
+		}
+		needBind = true
+		for _, raw := range rawBinds {
+			if mapBindFromAPI(raw).Port == httpPort {
+				needBind = false
+				break
+			}
+		}
+	}
+
+	if !needRule && !needBind {
+		internal.PrintStatus("Frontend", frontendName, "https-redirect "+internal.ActionUnchanged)
+		return nil
+	}
+
+	tx, err := internal.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	abort := func(err error) error {
+		if abortErr := tx.Abort(); abortErr != nil {
+			return errors.Join(err, abortErr)
+		}
+		return err
+	}
+
+	if needRule {
+		// Index 0: redirect before any other http-request processing.
+		if _, err := internal.SendRequest("POST", base+"/http_request_rules/0", tx.Params(), rule); err != nil {
+			return abort(fmt.Errorf("failed to create redirect rule: %w", err))
+		}
+	}
+	if needBind {
+		payload, err := bind.toClient()
+		if err != nil {
+			return abort(err)
+		}
+		if err := internal.API().CreateBind(context.Background(), tx.Client(), frontendName, payload); err != nil {
+			return abort(fmt.Errorf("failed to create port %d bind: %w", httpPort, err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	internal.PrintStatus("Frontend", frontendName, "https-redirect enabled")
+	return nil
+}
@@ -0,0 +1,55 @@
+package frontends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestEnableHTTPSRedirect_AddsRuleAndBindOnce(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddBind("web", map[string]interface{}{"name": "https", "address": "0.0.0.0", "port": 443, "ssl": "enabled"})
+	srv.AddListItem("frontends", "web", "http_request_rules", map[string]interface{}{"type": "deny", "deny_status": 403})
+
+	opts := httpsRedirectOptions{Code: 308, AddBind: true, BindAddress: "0.0.0.0"}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := EnableHTTPSRedirect("web", opts); err != nil {
+			t.Fatalf("EnableHTTPSRedirect failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web https-redirect enabled") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+
+	rules := srv.List("frontends", "web", "http_request_rules")
+	if len(rules) != 2 || !isHTTPSRedirectRule(rules[0]) || rules[0]["redir_code"] != float64(308) {
+		t.Fatalf("redirect rule not inserted first: %+v", rules)
+	}
+	if len(srv.Binds("web")) != 2 {
+		t.Fatalf("port 80 bind not added: %+v", srv.Binds("web"))
+	}
+	if srv.Version() != 2 {
+		t.Fatalf("version = %d, want 2 (single commit)", srv.Version())
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := EnableHTTPSRedirect("web", opts); err != nil {
+			t.Fatalf("second EnableHTTPSRedirect failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "https-redirect unchanged") {
+		t.Fatalf("expected unchanged on second run, got:\n%s", output)
+	}
+}
+
+func TestEnableHTTPSRedirect_InvalidCode(t *testing.T) {
+	t.Parallel()
+
+	if err := EnableHTTPSRedirect("web", httpsRedirectOptions{Code: 200}); err == nil {
+		t.Fatal("expected error for invalid redirect code")
+	}
+}
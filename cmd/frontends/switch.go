@@ -0,0 +1,217 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package frontends provides commands to manage HAProxy frontends.
+package frontends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"haproxyctl/internal"
+	"haproxyctl/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+// SwitchFrontendCmd represents "switch frontend <name> --to <backend>".
+var SwitchFrontendCmd = &cobra.Command{
+	Use:     "frontend <frontend_name>",
+	Aliases: []string{"frontends"},
+	Short:   "Point a frontend at a different backend (blue-green switch)",
+	Long: `Atomically change a frontend's default_backend inside a transaction.
+
+Before committing, the target backend must have at least --min-healthy
+servers that are up and ready, not draining or in maintenance (use --force
+to skip the check).
+With --rules, backend switching rules that pointed at the old default
+backend are moved to the new one in the same transaction.
+
+Examples:
+  haproxyctl switch frontend web --to green
+  haproxyctl switch frontend web --to green --rules --min-healthy 2
+  haproxyctl switch frontend web --to blue --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := switchOptions{
+			Target:     internal.GetFlagString(cmd, "to"),
+			Rules:      internal.GetFlagBool(cmd, "rules"),
+			MinHealthy: internal.GetFlagInt(cmd, "min-healthy"),
+			Force:      internal.GetFlagBool(cmd, "force"),
+			DryRun:     internal.GetFlagBool(cmd, "dry-run"),
+		}
+		if opts.Target == "" {
+			internal.FatalCodef(internal.ExitUsage, "--to is required")
+		}
+		if err := SwitchFrontend(args[0], opts); err != nil {
+			internal.Fatalf("Failed to switch frontend '%s': %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	SwitchFrontendCmd.Flags().String("to", "", "Backend to switch the frontend to (required)")
+	SwitchFrontendCmd.Flags().Bool("rules", false, "Also retarget backend switching rules that used the old default backend")
+	SwitchFrontendCmd.Flags().Int("min-healthy", 1, "Minimum number of healthy servers required in the target backend")
+	SwitchFrontendCmd.Flags().Bool("force", false, "Switch even if the target backend has too few healthy servers")
+	SwitchFrontendCmd.Flags().Bool("dry-run", false, "Check the target and show what would change without switching")
+}
+
+type switchOptions struct {
+	Target     string
+	Rules      bool
+	MinHealthy int
+	Force      bool
+	DryRun     bool
+}
+
+// countHealthyServers returns how many servers of a backend are up and
+// ready (neither draining nor in maintenance) according to the runtime API,
+// and the total count.
+func countHealthyServers(backendName string) (healthy, total int, err error) {
+	servers, err := internal.GetResourceList(fmt.Sprintf("/services/haproxy/runtime/backends/%s/servers", backendName))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch runtime state of backend %q: %w", backendName, err)
+	}
+	for _, srv := range servers {
+		if srv["operational_state"] == "up" && srv["admin_state"] == "ready" {
+			healthy++
+		}
+	}
+	return healthy, len(servers), nil
+}
+
+// checkTargetHealth fails unless the target backend has at least
+// opts.MinHealthy healthy servers; with opts.Force it only warns.
+func checkTargetHealth(opts switchOptions) error {
+	healthy, total, err := countHealthyServers(opts.Target)
+	if err != nil {
+		return err
+	}
+	if healthy < opts.MinHealthy {
+		msg := fmt.Sprintf("backend %q has %d/%d healthy servers, need at least %d", opts.Target, healthy, total, opts.MinHealthy)
+		if !opts.Force {
+			return errors.New(msg + " (use --force to switch anyway)")
+		}
+		internal.Warnf("%s; switching anyway (--force)", msg)
+	}
+	return nil
+}
+
+// SwitchFrontend changes the default_backend of a frontend (and optionally
+// its matching backend switching rules) in one transaction, verifying the
+// target's health before the transaction is committed.
+func SwitchFrontend(frontendName string, opts switchOptions) error {
+	if internal.IsOffline() {
+		// Nothing can be fetched offline: show the requested change only.
+		_, _ = fmt.Fprintf(os.Stdout, "%s default_backend -> %s\n", internal.ResourceID("Frontend", frontendName), opts.Target)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	path := "/services/haproxy/configuration/frontends/" + frontendName
+	frontend, err := internal.GetResource(path)
+	if err != nil {
+		return internal.FormatAPIError("Frontend", frontendName, "get", err)
+	}
+	if _, err := internal.GetResource("/services/haproxy/configuration/backends/" + opts.Target); err != nil {
+		return internal.FormatAPIError("Backend", opts.Target, "get", err)
+	}
+
+	previous, _ := frontend["default_backend"].(string)
+	previousLabel := previous
+	if previousLabel == "" {
+		previousLabel = "<none>"
+	}
+
+	var ruleIndexes []int
+	if opts.Rules && previous != "" {
+		rules, err := internal.GetResourceList(path + "/backend_switching_rules")
+		if err != nil {
+			return fmt.Errorf("failed to fetch backend switching rules: %w", err)
+		}
+		for i, rule := range rules {
+			if rule["name"] == previous {
+				ruleIndexes = append(ruleIndexes, i)
+			}
+		}
+	}
+
+	if previous == opts.Target && len(ruleIndexes) == 0 {
+		internal.PrintStatus("Frontend", frontendName, internal.ActionUnchanged)
+		return nil
+	}
+
+	if opts.DryRun {
+		_, _ = fmt.Fprintf(os.Stdout, "%s default_backend %s -> %s (%d switching rule(s))\n",
+			internal.ResourceID("Frontend", frontendName), previousLabel, opts.Target, len(ruleIndexes))
+		if err := checkTargetHealth(opts); err != nil {
+			return err
+		}
+		internal.PrintDryRun()
+		return nil
+	}
+
+	tx, err := internal.StartTransaction()
+	if err != nil {
+		return err
+	}
+	abort := func(err error) error {
+		if abortErr := tx.Abort(); abortErr != nil {
+			return errors.Join(err, abortErr)
+		}
+		return err
+	}
+
+	// Start from the fetched object: fields haproxyctl does not model are
+	// kept in Extra and survive the update.
+	var payload client.Frontend
+	if err := internal.ConvertPayload(frontend, &payload); err != nil {
+		return abort(err)
+	}
+	payload.DefaultBackend = opts.Target
+	if err := internal.API().UpdateFrontend(context.Background(), tx.Client(), payload); err != nil {
+		return abort(fmt.Errorf("failed to update frontend: %w", err))
+	}
+
+	for _, i := range ruleIndexes {
+		rulePath := path + "/backend_switching_rules/" + strconv.Itoa(i)
+		rule, err := internal.GetResource(rulePath)
+		if err != nil {
+			return abort(fmt.Errorf("failed to fetch backend switching rule %d: %w", i, err))
+		}
+		rule["name"] = opts.Target
+		if _, err := internal.SendRequest("PUT", rulePath, tx.Params(), rule); err != nil {
+			return abort(fmt.Errorf("failed to update backend switching rule %d: %w", i, err))
+		}
+	}
+
+	// Verify the target right before committing, so the check is as close
+	// as possible to the moment traffic moves.
+	if err := checkTargetHealth(opts); err != nil {
+		return abort(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "%s switched from %s to %s\n", internal.ResourceID("Frontend", frontendName), previousLabel, opts.Target)
+	return nil
+}
@@ -0,0 +1,113 @@
+package frontends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func newSwitchServer(t *testing.T) *testserver.Server {
+	t.Helper()
+
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http", "default_backend": "blue", "maxconn": 1000})
+	for _, be := range []string{"blue", "green"} {
+		srv.AddBackend(map[string]interface{}{"name": be})
+		srv.AddServer(be, map[string]interface{}{"name": be + "1", "address": "10.0.0.1", "port": 80})
+	}
+	srv.AddListItem("frontends", "web", "backend_switching_rules", map[string]interface{}{"name": "blue", "cond": "if", "cond_test": "is_api"})
+	srv.AddListItem("frontends", "web", "backend_switching_rules", map[string]interface{}{"name": "static", "cond": "if", "cond_test": "is_static"})
+	return srv
+}
+
+func TestSwitchFrontend_MovesDefaultAndRules(t *testing.T) {
+	srv := newSwitchServer(t)
+
+	output := internal.CaptureStdout(t, func() {
+		err := SwitchFrontend("web", switchOptions{Target: "green", Rules: true, MinHealthy: 1})
+		if err != nil {
+			t.Fatalf("SwitchFrontend failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web switched from blue to green") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+
+	fe, _ := srv.Frontend("web")
+	if fe["default_backend"] != "green" || fe["maxconn"] != float64(1000) {
+		t.Fatalf("frontend not switched or fields lost: %+v", fe)
+	}
+	rules := srv.List("frontends", "web", "backend_switching_rules")
+	if rules[0]["name"] != "green" || rules[1]["name"] != "static" {
+		t.Fatalf("switching rules not retargeted correctly: %+v", rules)
+	}
+}
+
+func TestSwitchFrontend_RefusesUnhealthyTarget(t *testing.T) {
+	for _, state := range []struct{ operational, admin string }{
+		{"down", "ready"},
+		{"up", "drain"},
+		{"up", "maint"},
+	} {
+		t.Run(state.operational+"/"+state.admin, func(t *testing.T) {
+			srv := newSwitchServer(t)
+			srv.SetServerState("green", "green1", state.operational, state.admin)
+
+			err := SwitchFrontend("web", switchOptions{Target: "green", MinHealthy: 1})
+			if err == nil || !strings.Contains(err.Error(), "0/1 healthy") {
+				t.Fatalf("expected health check failure, got %v", err)
+			}
+
+			fe, _ := srv.Frontend("web")
+			if fe["default_backend"] != "blue" {
+				t.Fatalf("frontend switched despite failed check: %+v", fe)
+			}
+			if srv.Version() != 1 {
+				t.Fatalf("version = %d, want 1 (transaction discarded)", srv.Version())
+			}
+		})
+	}
+}
+
+func TestSwitchFrontend_DryRunWritesNothing(t *testing.T) {
+	srv := newSwitchServer(t)
+
+	output := internal.CaptureStdout(t, func() {
+		err := SwitchFrontend("web", switchOptions{Target: "green", Rules: true, MinHealthy: 1, DryRun: true})
+		if err != nil {
+			t.Fatalf("SwitchFrontend failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web default_backend blue -> green (1 switching rule(s))") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+
+	for _, r := range srv.Requests() {
+		if r.Method != "GET" {
+			t.Errorf("dry run sent %s %s", r.Method, r.Path)
+		}
+	}
+	if fe, _ := srv.Frontend("web"); fe["default_backend"] != "blue" {
+		t.Fatalf("frontend switched by a dry run: %+v", fe)
+	}
+}
+
+func TestSwitchFrontend_Offline(t *testing.T) {
+	srv := newSwitchServer(t)
+	internal.SetOffline(true)
+	t.Cleanup(func() { internal.SetOffline(false) })
+
+	output := internal.CaptureStdout(t, func() {
+		if err := SwitchFrontend("web", switchOptions{Target: "green", MinHealthy: 1}); err != nil {
+			t.Fatalf("SwitchFrontend failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Offline mode enabled") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("offline switch sent %d requests", n)
+	}
+}
@@ -0,0 +1,304 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package frontends provides commands to manage HAProxy frontends.
+package frontends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// SwitchingRule is a "use_backend <backend> [if|unless <condition>]" line.
+//
+//nolint:tagliatelle // manifest uses snake_case like frontendConfig
+type SwitchingRule struct {
+	Backend  string `json:"backend" yaml:"backend"`
+	Cond     string `json:"cond,omitempty" yaml:"cond,omitempty"`
+	CondTest string `json:"cond_test,omitempty" yaml:"cond_test,omitempty"`
+}
+
+// String renders the rule the way it appears in haproxy.cfg.
+func (r SwitchingRule) String() string {
+	if r.Cond == "" {
+		return "use_backend " + r.Backend
+	}
+	return fmt.Sprintf("use_backend %s %s %s", r.Backend, r.Cond, r.CondTest)
+}
+
+func (r SwitchingRule) validate() error {
+	if r.Backend == "" {
+		return errors.New("backend switching rule is missing backend")
+	}
+	switch r.Cond {
+	case "":
+		if r.CondTest != "" {
+			return fmt.Errorf("%q: cond_test needs cond (if or unless)", r.String())
+		}
+	case "if", "unless":
+		if r.CondTest == "" {
+			return fmt.Errorf("%q: cond %s needs a cond_test", r.String(), r.Cond)
+		}
+	default:
+		return fmt.Errorf("%q: invalid cond %q (expected if or unless)", r.String(), r.Cond)
+	}
+	return nil
+}
+
+func (r SwitchingRule) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"name": r.Backend}
+	if r.Cond != "" {
+		payload["cond"] = r.Cond
+		payload["cond_test"] = r.CondTest
+	}
+	return payload
+}
+
+// mapSwitchingRuleFromAPI converts an API backend switching rule.
+func mapSwitchingRuleFromAPI(obj map[string]interface{}) SwitchingRule {
+	var r SwitchingRule
+	r.Backend, _ = obj["name"].(string)
+	r.Cond, _ = obj["cond"].(string)
+	r.CondTest, _ = obj["cond_test"].(string)
+	return r
+}
+
+func switchingRulesPath(frontendName string) string {
+	return "/services/haproxy/configuration/frontends/" + frontendName + "/backend_switching_rules"
+}
+
+// liveSwitchingRules returns the backend switching rules of a frontend in
+// order, or nil when it has none.
+func liveSwitchingRules(ctx context.Context, frontendName string) ([]SwitchingRule, error) {
+	list, err := internal.GetResourceListWithContext(ctx, switchingRulesPath(frontendName))
+	if err != nil {
+		return nil, err
+	}
+	var rules []SwitchingRule
+	for _, raw := range list {
+		rules = append(rules, mapSwitchingRuleFromAPI(raw))
+	}
+	return rules, nil
+}
+
+// applySwitchingRuleDiff turns the backend switching rules of a frontend
+// from before into after, staging the changes in tx when it is non-nil.
+func applySwitchingRuleDiff(tx *internal.Transaction, frontendName string, before, after []SwitchingRule) error {
+	return internal.ApplyIndexedDiff(tx, switchingRulesPath(frontendName), before, after, func(r SwitchingRule) interface{} { return r.toPayload() })
+}
+
+// GetSwitchingRulesCmd represents "get backend-switching-rules <frontend>".
+var GetSwitchingRulesCmd = &cobra.Command{
+	Use:     "backend-switching-rules <frontend_name>",
+	Aliases: []string{"backend-switching-rule", "use-backend"},
+	Short:   "List the use_backend rules of a frontend",
+	Long: `List the backend switching (use_backend) rules of a frontend in
+evaluation order. The index column is the position used by the delete and
+move commands.
+
+Examples:
+  haproxyctl get backend-switching-rules web
+  haproxyctl get backend-switching-rules web -o yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rules, err := internal.GetResourceList(switchingRulesPath(args[0]))
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Frontend", args[0]))
+			}
+			internal.Fatalf("Failed to fetch backend switching rules of frontend '%s': %v", args[0], err)
+		}
+		internal.SortForCmd(cmd, rules)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(rules, switchingRuleColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// switchingRuleColumns shows each rule as position, backend and condition.
+var switchingRuleColumns = internal.ColumnSet{
+	Default: []string{"index", "name", "cond", "cond_test"},
+}
+
+// CreateSwitchingRuleCmd represents "create backend-switching-rules <frontend>".
+var CreateSwitchingRuleCmd = &cobra.Command{
+	Use:     "backend-switching-rules <frontend_name>",
+	Aliases: []string{"backend-switching-rule", "use-backend"},
+	Short:   "Add a use_backend rule to a frontend",
+	Long: `Add a "use_backend <backend> if|unless <condition>" rule to a frontend.
+The rule is appended unless --index places it at a given position; rules
+are evaluated in order and the first match wins.
+
+Examples:
+  haproxyctl create backend-switching-rules web --backend api --cond if --cond-test "{ path_beg /api }"
+  haproxyctl create backend-switching-rules web --backend static --cond if --cond-test is_static --index 0`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rule := SwitchingRule{
+			Backend:  internal.GetFlagString(cmd, "backend"),
+			Cond:     internal.GetFlagString(cmd, "cond"),
+			CondTest: internal.GetFlagString(cmd, "cond-test"),
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateSwitchingRule(args[0], rule, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+			internal.Fatalf("Failed to create backend switching rule on frontend '%s': %v", args[0], err)
+		}
+	},
+}
+
+// DeleteSwitchingRuleCmd represents "delete backend-switching-rules <frontend> --index N".
+var DeleteSwitchingRuleCmd = &cobra.Command{
+	Use:     "backend-switching-rules <frontend_name>",
+	Aliases: []string{"backend-switching-rule", "use-backend"},
+	Short:   "Delete a use_backend rule from a frontend",
+	Long: `Delete the backend switching rule at --index (as shown by "get
+backend-switching-rules"). Later rules move up by one position.
+
+Examples:
+  haproxyctl delete backend-switching-rules web --index 1`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		index := internal.GetFlagInt(cmd, "index")
+		if index < 0 {
+			internal.FatalCodef(internal.ExitUsage, "--index is required")
+		}
+		if err := DeleteSwitchingRule(args[0], index); err != nil {
+			internal.Fatalf("Failed to delete backend switching rule %d of frontend '%s': %v", index, args[0], err)
+		}
+	},
+}
+
+// MoveSwitchingRuleCmd represents "move backend-switching-rules <frontend> --index N --to M".
+var MoveSwitchingRuleCmd = &cobra.Command{
+	Use:     "backend-switching-rules <frontend_name>",
+	Aliases: []string{"backend-switching-rule", "use-backend"},
+	Short:   "Move a use_backend rule to another position",
+	Long: `Move the backend switching rule at --index to position --to, shifting
+the rules in between by one, in one transaction.
+
+Examples:
+  haproxyctl move backend-switching-rules web --index 2 --to 0`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to := internal.GetFlagInt(cmd, "index"), internal.GetFlagInt(cmd, "to")
+		if from < 0 || to < 0 {
+			internal.FatalCodef(internal.ExitUsage, "--index and --to are required")
+		}
+		if err := MoveSwitchingRule(args[0], from, to); err != nil {
+			internal.Fatalf("Failed to move backend switching rule %d of frontend '%s': %v", from, args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateSwitchingRuleCmd.Flags().String("backend", "", "Backend to send matching traffic to (required)")
+	CreateSwitchingRuleCmd.Flags().String("cond", "", "Condition keyword: if or unless")
+	CreateSwitchingRuleCmd.Flags().String("cond-test", "", "Condition, e.g. \"{ hdr(host) -i api.example.com }\" or an ACL name")
+	CreateSwitchingRuleCmd.Flags().Int("index", -1, "Position to insert the rule at (default: append)")
+	CreateSwitchingRuleCmd.Flags().Bool("dry-run", false, "Print the rule without creating it")
+
+	DeleteSwitchingRuleCmd.Flags().Int("index", -1, "Position of the rule to delete (required)")
+
+	MoveSwitchingRuleCmd.Flags().Int("index", -1, "Current position of the rule (required)")
+	MoveSwitchingRuleCmd.Flags().Int("to", -1, "New position of the rule (required)")
+}
+
+// CreateSwitchingRule inserts a rule at index, or appends it when index is
+// negative.
+func CreateSwitchingRule(frontendName string, rule SwitchingRule, index int, dryRun bool) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(rule.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := liveSwitchingRules(context.Background(), frontendName)
+	if err != nil {
+		return internal.FormatAPIError("Frontend", frontendName, "get", err)
+	}
+	if _, err := internal.GetResource("/services/haproxy/configuration/backends/" + rule.Backend); err != nil {
+		return internal.FormatAPIError("Backend", rule.Backend, "get", err)
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", switchingRulesPath(frontendName)+"/"+strconv.Itoa(index), params, rule.toPayload()); err != nil {
+		return fmt.Errorf("failed to create %q: %w", rule.String(), err)
+	}
+
+	internal.PrintStatus("BackendSwitchingRule", fmt.Sprintf("%s/%d", frontendName, index), internal.ActionCreated)
+	return nil
+}
+
+// DeleteSwitchingRule removes the rule at index.
+func DeleteSwitchingRule(frontendName string, index int) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%d", frontendName, index)
+	if _, err := internal.SendRequest("DELETE", switchingRulesPath(frontendName)+"/"+strconv.Itoa(index), params, nil); err != nil {
+		return internal.FormatAPIError("BackendSwitchingRule", id, "delete", err)
+	}
+
+	internal.PrintStatus("BackendSwitchingRule", id, internal.ActionDeleted)
+	return nil
+}
+
+// MoveSwitchingRule moves the rule at from to position to.
+func MoveSwitchingRule(frontendName string, from, to int) error {
+	current, err := liveSwitchingRules(context.Background(), frontendName)
+	if err != nil {
+		return internal.FormatAPIError("Frontend", frontendName, "get", err)
+	}
+	if from >= len(current) || to >= len(current) {
+		return fmt.Errorf("frontend %q has %d backend switching rules (positions 0-%d)", frontendName, len(current), len(current)-1)
+	}
+	id := fmt.Sprintf("%s/%d", frontendName, from)
+	if from == to {
+		internal.PrintStatus("BackendSwitchingRule", id, internal.ActionUnchanged)
+		return nil
+	}
+
+	path := switchingRulesPath(frontendName)
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		if _, err := internal.SendRequest("DELETE", path+"/"+strconv.Itoa(from), tx.Params(), nil); err != nil {
+			return fmt.Errorf("failed to remove rule %d: %w", from, err)
+		}
+		if _, err := internal.SendRequest("POST", path+"/"+strconv.Itoa(to), tx.Params(), current[from].toPayload()); err != nil {
+			return fmt.Errorf("failed to insert rule at %d: %w", to, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus("BackendSwitchingRule", id, fmt.Sprintf("moved to %d", to))
+	return nil
+}
@@ -0,0 +1,117 @@
+package frontends
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func switchingBackends(srv *testserver.Server) []string {
+	var out []string
+	for _, r := range srv.List("frontends", "web", "backend_switching_rules") {
+		out = append(out, r["name"].(string))
+	}
+	return out
+}
+
+func TestApplyFrontendFromYAML_ManagesSwitchingRulesOnlyWhenListed(t *testing.T) {
+	srv := newSwitchServer(t)
+
+	// Without backend_switching_rules the live rules are left alone.
+	manifest := "apiVersion: haproxyctl/v1\nkind: Frontend\nname: web\nmode: http\ndefault_backend: blue\n"
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(manifest), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if got := strings.Join(switchingBackends(srv), ","); got != "blue,static" {
+		t.Fatalf("rules = %s, want untouched blue,static", got)
+	}
+
+	withRules := manifest + `backend_switching_rules:
+  - backend: blue
+    cond: if
+    cond_test: is_api
+  - backend: green
+    cond: if
+    cond_test: "{ hdr(host) -i green.example.com }"
+`
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(withRules), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web configured") {
+		t.Fatalf("expected configured status, got:\n%s", output)
+	}
+	if got := strings.Join(switchingBackends(srv), ","); got != "blue,green" {
+		t.Fatalf("rules = %s, want blue,green", got)
+	}
+	// The first rule matched, so only the second was rewritten.
+	if n := srv.CountRequests("DELETE", "/v3/services/haproxy/configuration/frontends/web/backend_switching_rules/0"); n != 0 {
+		t.Fatalf("unchanged rule 0 was deleted %d times", n)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyFrontendFromYAML([]byte(withRules), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "frontend/web unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+}
+
+func TestSwitchingRuleValidate(t *testing.T) {
+	cases := []struct {
+		rule SwitchingRule
+		ok   bool
+	}{
+		{SwitchingRule{Backend: "api"}, true},
+		{SwitchingRule{Backend: "api", Cond: "unless", CondTest: "is_static"}, true},
+		{SwitchingRule{Cond: "if", CondTest: "is_api"}, false},
+		{SwitchingRule{Backend: "api", Cond: "if"}, false},
+		{SwitchingRule{Backend: "api", Cond: "when", CondTest: "x"}, false},
+		{SwitchingRule{Backend: "api", CondTest: "x"}, false},
+	}
+	for _, tc := range cases {
+		if err := tc.rule.validate(); (err == nil) != tc.ok {
+			t.Errorf("validate(%+v) error = %v, want ok=%v", tc.rule, err, tc.ok)
+		}
+	}
+}
+
+func TestCreateAndMoveSwitchingRule(t *testing.T) {
+	srv := newSwitchServer(t)
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := CreateSwitchingRule("web", SwitchingRule{Backend: "green"}, 0, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	if got := strings.Join(switchingBackends(srv), ","); got != "green,blue,static" {
+		t.Fatalf("rules after create = %s", got)
+	}
+
+	if err := CreateSwitchingRule("web", SwitchingRule{Backend: "missing"}, -1, false); err == nil {
+		t.Fatal("expected error for unknown backend")
+	}
+
+	version := srv.Version()
+	output := internal.CaptureStdout(t, func() {
+		if err := MoveSwitchingRule("web", 0, 2); err != nil {
+			t.Fatalf("move failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "moved to 2") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if got := strings.Join(switchingBackends(srv), ","); got != "blue,static,green" {
+		t.Fatalf("rules after move = %s", got)
+	}
+	if srv.Version() != version+1 {
+		t.Fatalf("move used %d versions, want one transaction", srv.Version()-version)
+	}
+}
@@ -20,8 +20,12 @@ package frontends
 import (
 	"errors"
 	"fmt"
+	"haproxyctl/cmd/filters"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/logtargets"
 	"haproxyctl/internal"
-	"log"
+	"haproxyctl/pkg/client"
+	"net"
 	"strconv"
 	"strings"
 
@@ -32,31 +36,154 @@ import (
 const sslEnabledValue = "enabled"
 
 // BindConfig represents a single frontend bind (what the HAProxy Data Plane API expects).
+//
+//nolint:tagliatelle
 type BindConfig struct {
-	Address string `json:"address" yaml:"address"`
-	Port    int    `json:"port"    yaml:"port"`
-	SSL     bool   `json:"ssl,omitempty" yaml:"ssl,omitempty"`
-	// Name is the underlying bind name in the Data Plane API.
-	// It is not part of the manifest and is used only to drive
-	// update/delete operations when reconciling binds.
-	Name string `json:"-" yaml:"-"`
+	// Name is the bind's name in the Data Plane API. It defaults to
+	// "<address>:<port>", which is left out of manifests; a manifest that
+	// sets it renames the bind, one that omits it keeps the live name.
+	Name           string `json:"name,omitempty" yaml:"name,omitempty"`
+	Address        string `json:"address" yaml:"address"`
+	Port           int    `json:"port"    yaml:"port"`
+	SSL            bool   `json:"ssl,omitempty" yaml:"ssl,omitempty"`
+	SSLCertificate string `json:"ssl_certificate,omitempty" yaml:"ssl_certificate,omitempty"`
+	ALPN           string `json:"alpn,omitempty" yaml:"alpn,omitempty"`
+	AcceptProxy    bool   `json:"accept_proxy,omitempty" yaml:"accept_proxy,omitempty"`
+	// CrtList is the path of a crt-list (see "haproxyctl get crt-lists")
+	// serving certificates by SNI, alone or next to ssl_certificate.
+	CrtList string `json:"crt_list,omitempty" yaml:"crt_list,omitempty"`
+	// Transparent accepts connections for foreign addresses (TPROXY),
+	// V4V6 lets an IPv6 bind on "::" accept IPv4 too, and TCPUserTimeout
+	// (tcp-ut) uses the duration syntax of the timeouts.
+	Transparent    bool   `json:"transparent,omitempty" yaml:"transparent,omitempty"`
+	V4V6           bool   `json:"v4v6,omitempty" yaml:"v4v6,omitempty"`
+	TCPUserTimeout string `json:"tcp_user_timeout,omitempty" yaml:"tcp_user_timeout,omitempty"`
+	// Mode (octal permissions), User and Group apply to unix socket binds,
+	// whose address is a path ("/run/app.sock" or "unix@...") and which
+	// have no port. Abstract namespace sockets ("abns@name") take neither.
+	Mode  string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	User  string `json:"user,omitempty" yaml:"user,omitempty"`
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+}
+
+const (
+	unixSocketPrefix = "unix@"
+	abnsSocketPrefix = "abns@"
+)
+
+// isUnixSocket reports whether the bind listens on a unix socket path.
+func (b BindConfig) isUnixSocket() bool {
+	return strings.HasPrefix(b.Address, "/") || strings.HasPrefix(b.Address, unixSocketPrefix)
+}
+
+// isSocket reports whether the bind listens on a unix or abstract
+// namespace socket instead of address+port.
+func (b BindConfig) isSocket() bool {
+	return b.isUnixSocket() || strings.HasPrefix(b.Address, abnsSocketPrefix)
 }
 
 // bindPayload is the wire-format representation of a bind, using the
 // v3 enum for ssl instead of a boolean.
+//
+//nolint:tagliatelle
 type bindPayload struct {
-	Address string `json:"address"`
-	Port    int    `json:"port"`
-	SSL     string `json:"ssl,omitempty"`
+	Name           string `json:"name,omitempty"`
+	Address        string `json:"address"`
+	Port           int    `json:"port,omitempty"`
+	SSL            string `json:"ssl,omitempty"`
+	SSLCertificate string `json:"ssl_certificate,omitempty"`
+	ALPN           string `json:"alpn,omitempty"`
+	AcceptProxy    bool   `json:"accept_proxy,omitempty"`
+	CrtList        string `json:"crt_list,omitempty"`
+	Transparent    bool   `json:"transparent,omitempty"`
+	V4V6           bool   `json:"v4v6,omitempty"`
+	TCPUserTimeout int    `json:"tcp_user_timeout,omitempty"`
+	Mode           string `json:"mode,omitempty"`
+	User           string `json:"user,omitempty"`
+	Group          string `json:"group,omitempty"`
+}
+
+// BindDefaults holds bind settings shared by every bind of a frontend.
+// They fill in fields a bind leaves unset: the certificate and ALPN only
+// apply to binds with ssl enabled, accept_proxy applies to all binds.
+//
+//nolint:tagliatelle
+type BindDefaults struct {
+	SSLCertificate string `json:"ssl_certificate,omitempty" yaml:"ssl_certificate,omitempty"`
+	ALPN           string `json:"alpn,omitempty" yaml:"alpn,omitempty"`
+	AcceptProxy    bool   `json:"accept_proxy,omitempty" yaml:"accept_proxy,omitempty"`
+}
+
+// apply returns b with any unset fields filled in from d.
+func (d BindDefaults) apply(b BindConfig) BindConfig {
+	if b.SSL {
+		if b.SSLCertificate == "" && b.CrtList == "" {
+			b.SSLCertificate = d.SSLCertificate
+		}
+		if b.ALPN == "" {
+			b.ALPN = d.ALPN
+		}
+	}
+	if d.AcceptProxy {
+		b.AcceptProxy = true
+	}
+	return b
+}
+
+// key identifies a bind when reconciling: "<address>:<port>", or the
+// address alone for socket binds.
+func (b BindConfig) key() string {
+	if b.isSocket() {
+		return b.Address
+	}
+	return fmt.Sprintf("%s:%d", b.Address, b.Port)
+}
+
+// defaultName is the "<address>:<port>" name HAProxy shows for unnamed
+// binds. Socket paths cannot appear in API URLs, so their slashes become
+// underscores ("/run/app.sock" is "run_app.sock").
+func (b BindConfig) defaultName() string {
+	if b.isSocket() {
+		path := strings.TrimPrefix(strings.TrimPrefix(b.Address, unixSocketPrefix), "/")
+		return strings.ReplaceAll(path, "/", "_")
+	}
+	return b.key()
+}
+
+// apiName returns the name that addresses the bind in the Data Plane API.
+// The v3 API requires one, and manifests usually omit it.
+func (b BindConfig) apiName() string {
+	if b.Name == "" {
+		return b.defaultName()
+	}
+	return b.Name
+}
+
+// toClient returns the payload of b as a bind of the typed client.
+func (b BindConfig) toClient() (client.Bind, error) {
+	var out client.Bind
+	return out, internal.ConvertPayload(b.toPayload(), &out)
 }
 
 // toPayload converts a BindConfig into the structure expected by
 // the HAProxy Data Plane API v3.
 func (b BindConfig) toPayload() bindPayload {
 	payload := bindPayload{
-		Address: b.Address,
-		Port:    b.Port,
+		Name:           b.apiName(),
+		Address:        b.Address,
+		Port:           b.Port,
+		SSLCertificate: b.SSLCertificate,
+		ALPN:           b.ALPN,
+		AcceptProxy:    b.AcceptProxy,
+		CrtList:        b.CrtList,
+		Transparent:    b.Transparent,
+		V4V6:           b.V4V6,
+		Mode:           b.Mode,
+		User:           b.User,
+		Group:          b.Group,
 	}
+	// Validate rejects a bad tcp_user_timeout before any payload is built.
+	payload.TCPUserTimeout, _ = internal.ParseDurationToMillis(b.TCPUserTimeout)
 	if b.SSL {
 		payload.SSL = sslEnabledValue
 	}
@@ -70,6 +197,7 @@ func (b BindConfig) toPayload() bindPayload {
 type frontendConfig struct {
 	Name                 string            `json:"name" yaml:"name"`
 	Mode                 string            `json:"mode,omitempty" yaml:"mode,omitempty"`
+	From                 string            `json:"from,omitempty" yaml:"from,omitempty"`
 	DefaultBackend       string            `json:"default_backend,omitempty" yaml:"default_backend,omitempty"`
 	ForwardFor           map[string]string `json:"forwardfor,omitempty" yaml:"forwardfor,omitempty"`
 	TimeoutClient        string            `json:"timeout_client,omitempty" yaml:"timeout_client,omitempty"`
@@ -77,6 +205,28 @@ type frontendConfig struct {
 	TimeoutHTTPKeepAlive string            `json:"timeout_http_keep_alive,omitempty" yaml:"timeout_http_keep_alive,omitempty"`
 	TimeoutQueue         string            `json:"timeout_queue,omitempty" yaml:"timeout_queue,omitempty"`
 	TimeoutServer        string            `json:"timeout_server,omitempty" yaml:"timeout_server,omitempty"`
+	Maxconn              int               `json:"maxconn,omitempty" yaml:"maxconn,omitempty"`
+	Backlog              int               `json:"backlog,omitempty" yaml:"backlog,omitempty"`
+	MonitorURI           string            `json:"monitor_uri,omitempty" yaml:"monitor_uri,omitempty"`
+	Httplog              bool              `json:"httplog,omitempty" yaml:"httplog,omitempty"`
+	Tcplog               bool              `json:"tcplog,omitempty" yaml:"tcplog,omitempty"`
+	LogFormat            string            `json:"log_format,omitempty" yaml:"log_format,omitempty"`
+	HTTPConnectionMode   string            `json:"http_connection_mode,omitempty" yaml:"http_connection_mode,omitempty"`
+	StatsOptions         *StatsOptions     `json:"stats_options,omitempty" yaml:"stats_options,omitempty"`
+	// The "option" switches below take "enabled" or "disabled", as in
+	// the API; "disabled" writes "no option <name>" to override defaults.
+	Clitcpka          string `json:"clitcpka,omitempty" yaml:"clitcpka,omitempty"`
+	Contstats         string `json:"contstats,omitempty" yaml:"contstats,omitempty"`
+	DontlogNormal     string `json:"dontlog_normal,omitempty" yaml:"dontlog_normal,omitempty"`
+	Dontlognull       string `json:"dontlognull,omitempty" yaml:"dontlognull,omitempty"`
+	HTTPIgnoreProbes  string `json:"http_ignore_probes,omitempty" yaml:"http_ignore_probes,omitempty"`
+	LogSeparateErrors string `json:"log_separate_errors,omitempty" yaml:"log_separate_errors,omitempty"`
+	Logasap           string `json:"logasap,omitempty" yaml:"logasap,omitempty"`
+	SocketStats       string `json:"socket_stats,omitempty" yaml:"socket_stats,omitempty"`
+	// ErrorFiles and ErrorFilesFromHTTPErrors are the errorfile and
+	// "errorfiles <section>" lines of the frontend.
+	ErrorFiles               []httperrors.ErrorFile      `json:"error_files,omitempty" yaml:"error_files,omitempty"`
+	ErrorFilesFromHTTPErrors []httperrors.ErrorFilesFrom `json:"errorfiles_from_http_errors,omitempty" yaml:"errorfiles_from_http_errors,omitempty"`
 }
 
 // frontendPayload is the wire-format representation of a frontend,
@@ -101,6 +251,9 @@ func populateFrontendConfigFromMap(cfg *frontendConfig, obj map[string]interface
 	if v, ok := obj["mode"].(string); ok {
 		cfg.Mode = v
 	}
+	if v, ok := obj["from"].(string); ok {
+		cfg.From = v
+	}
 	if v, ok := obj["default_backend"].(string); ok {
 		cfg.DefaultBackend = v
 	}
@@ -108,21 +261,28 @@ func populateFrontendConfigFromMap(cfg *frontendConfig, obj map[string]interface
 		cfg.ForwardFor = toStringMap(m)
 	}
 
-	if ms, ok := getIntField(obj, "timeout_client"); ok {
-		cfg.TimeoutClient = internal.FormatMillisAsDuration(ms)
-	}
-	if ms, ok := getIntField(obj, "timeout_http_request"); ok {
-		cfg.TimeoutHTTPRequest = internal.FormatMillisAsDuration(ms)
-	}
-	if ms, ok := getIntField(obj, "timeout_http_keep_alive"); ok {
-		cfg.TimeoutHTTPKeepAlive = internal.FormatMillisAsDuration(ms)
+	for field, value := range cfg.durations() {
+		if ms, ok := internal.GetIntField(obj, field); ok {
+			*value = internal.FormatMillisAsDuration(ms)
+		}
 	}
-	if ms, ok := getIntField(obj, "timeout_queue"); ok {
-		cfg.TimeoutQueue = internal.FormatMillisAsDuration(ms)
+
+	cfg.Maxconn, _ = internal.GetIntField(obj, "maxconn")
+	cfg.Backlog, _ = internal.GetIntField(obj, "backlog")
+	cfg.MonitorURI, _ = obj["monitor_uri"].(string)
+	cfg.Httplog, _ = obj["httplog"].(bool)
+	cfg.Tcplog, _ = obj["tcplog"].(bool)
+	cfg.LogFormat, _ = obj["log_format"].(string)
+	cfg.HTTPConnectionMode, _ = obj["http_connection_mode"].(string)
+	for name, field := range cfg.options() {
+		*field, _ = obj[name].(string)
 	}
-	if ms, ok := getIntField(obj, "timeout_server"); ok {
-		cfg.TimeoutServer = internal.FormatMillisAsDuration(ms)
+	if m, ok := obj["stats_options"].(map[string]interface{}); ok {
+		cfg.StatsOptions = mapStatsOptionsFromAPI(m)
 	}
+
+	cfg.ErrorFiles = httperrors.ErrorFilesFromAPI(obj["error_files"])
+	cfg.ErrorFilesFromHTTPErrors = httperrors.ErrorFilesFromFromAPI(obj["errorfiles_from_http_errors"])
 }
 
 // frontendWithBinds is the user‑facing structure: includes metadata, core frontendConfig,
@@ -131,7 +291,29 @@ type frontendWithBinds struct {
 	APIVersion     string `yaml:"apiVersion"`
 	Kind           string `yaml:"kind"`
 	frontendConfig `yaml:",inline"`
-	Binds          []BindConfig `json:"binds,omitempty" yaml:"binds,omitempty"`
+	BindDefaults   *BindDefaults `json:"bind_defaults,omitempty" yaml:"bind_defaults,omitempty"` //nolint:tagliatelle // manifest uses snake_case like frontendConfig
+	Binds          []BindConfig  `json:"binds,omitempty" yaml:"binds,omitempty"`
+	// BackendSwitchingRules are the frontend's use_backend rules in order.
+	// Apply leaves the live rules alone when the key is absent (nil); an
+	// explicit empty list removes them.
+	BackendSwitchingRules []SwitchingRule `json:"backend_switching_rules,omitempty" yaml:"backend_switching_rules,omitempty"` //nolint:tagliatelle // manifest uses snake_case like frontendConfig
+	// Filters, LogTargets and Captures are managed like BackendSwitchingRules.
+	Filters    []filters.Filter       `json:"filters,omitempty" yaml:"filters,omitempty"`
+	LogTargets []logtargets.LogTarget `json:"log_targets,omitempty" yaml:"log_targets,omitempty"` //nolint:tagliatelle // manifest uses snake_case like frontendConfig
+	Captures   []Capture              `json:"captures,omitempty" yaml:"captures,omitempty"`
+}
+
+// EffectiveBinds returns the binds with bind_defaults applied. This is the
+// list that is sent to (and compared against) the Data Plane API.
+func (f *frontendWithBinds) EffectiveBinds() []BindConfig {
+	if f.BindDefaults == nil {
+		return f.Binds
+	}
+	out := make([]BindConfig, 0, len(f.Binds))
+	for _, b := range f.Binds {
+		out = append(out, f.BindDefaults.apply(b))
+	}
+	return out
 }
 
 // LoadFromFile loads a YAML manifest into this struct.
@@ -157,10 +339,34 @@ func (f *frontendWithBinds) LoadFromFlags(cmd *cobra.Command, name string) {
 	f.TimeoutHTTPKeepAlive = internal.GetFlagString(cmd, "timeout-http-keep-alive")
 	f.TimeoutQueue = internal.GetFlagString(cmd, "timeout-queue")
 	f.TimeoutServer = internal.GetFlagString(cmd, "timeout-server")
+	f.Maxconn = internal.GetFlagInt(cmd, "maxconn")
+	f.Backlog = internal.GetFlagInt(cmd, "backlog")
+	f.MonitorURI = internal.GetFlagString(cmd, "monitor-uri")
+	f.Httplog = internal.GetFlagBool(cmd, "httplog")
+	f.Tcplog = internal.GetFlagBool(cmd, "tcplog")
+	f.LogFormat = internal.GetFlagString(cmd, "log-format")
+	f.HTTPConnectionMode = internal.GetFlagString(cmd, "http-connection-mode")
+	if uri := internal.GetFlagString(cmd, "stats-uri"); uri != "" {
+		f.StatsOptions = &StatsOptions{Enable: true, URIPrefix: uri}
+	}
+	for _, opt := range internal.GetFlagStringSlice(cmd, "option") {
+		if err := f.setOption(opt); err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid --option: %v", err)
+		}
+	}
 
 	// Parse repeated --bind flags into a slice of BindConfig
 	rawBinds := internal.GetFlagStringSlice(cmd, "bind")
 	f.Binds = parseBindsFromFlags(rawBinds)
+
+	defaults := BindDefaults{
+		SSLCertificate: internal.GetFlagString(cmd, "bind-ssl-certificate"),
+		ALPN:           internal.GetFlagString(cmd, "bind-alpn"),
+		AcceptProxy:    internal.GetFlagBool(cmd, "bind-accept-proxy"),
+	}
+	if defaults != (BindDefaults{}) {
+		f.BindDefaults = &defaults
+	}
 }
 
 // ToFrontendConfig strips out the binds metadata so you can POST the core API object.
@@ -168,6 +374,12 @@ func (f *frontendWithBinds) ToFrontendConfig() frontendConfig {
 	return f.frontendConfig
 }
 
+// toClient returns the payload of f as a frontend of the typed client.
+func (f *frontendWithBinds) toClient() (client.Frontend, error) {
+	var out client.Frontend
+	return out, internal.ConvertPayload(f.ToPayload(), &out)
+}
+
 // ToPayload converts the CLI/YAML view into the frontendPayload that
 // matches the Data Plane API v3 schema.
 func (f *frontendWithBinds) ToPayload() frontendPayload {
@@ -176,31 +388,31 @@ func (f *frontendWithBinds) ToPayload() frontendPayload {
 	}
 
 	if ms, err := internal.ParseDurationToMillis(f.TimeoutClient); err != nil {
-		log.Fatalf("invalid frontend timeout_client: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid frontend timeout_client: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutClient = ms
 	}
 
 	if ms, err := internal.ParseDurationToMillis(f.TimeoutHTTPRequest); err != nil {
-		log.Fatalf("invalid frontend timeout_http_request: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid frontend timeout_http_request: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutHTTPRequest = ms
 	}
 
 	if ms, err := internal.ParseDurationToMillis(f.TimeoutHTTPKeepAlive); err != nil {
-		log.Fatalf("invalid frontend timeout_http_keep_alive: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid frontend timeout_http_keep_alive: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutHTTPKeepAlive = ms
 	}
 
 	if ms, err := internal.ParseDurationToMillis(f.TimeoutQueue); err != nil {
-		log.Fatalf("invalid frontend timeout_queue: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid frontend timeout_queue: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutQueue = ms
 	}
 
 	if ms, err := internal.ParseDurationToMillis(f.TimeoutServer); err != nil {
-		log.Fatalf("invalid frontend timeout_server: %v", err)
+		internal.FatalCodef(internal.ExitInvalid, "invalid frontend timeout_server: %v", err)
 	} else if ms > 0 {
 		payload.TimeoutServer = ms
 	}
@@ -217,30 +429,126 @@ func (f *frontendWithBinds) Validate() error {
 		return fmt.Errorf("invalid mode %q (allowed: http, tcp)", f.Mode)
 	}
 	// Binds are optional; if provided, ensure address+port are set
-	for _, b := range f.Binds {
-		if b.Address == "" || b.Port == 0 {
-			return fmt.Errorf("each bind must have address and port: %+v", b)
+	seen := make(map[string]bool, len(f.Binds))
+	names := make(map[string]bool, len(f.Binds))
+	for _, b := range f.EffectiveBinds() {
+		key, err := b.validateListener()
+		if err != nil {
+			return err
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate bind %s", key)
+		}
+		seen[key] = true
+		if !b.SSL && (b.SSLCertificate != "" || b.CrtList != "" || b.ALPN != "") {
+			return fmt.Errorf("bind %s sets ssl_certificate/crt_list/alpn without ssl", key)
+		}
+		if names[b.apiName()] {
+			return fmt.Errorf("duplicate bind name %q", b.apiName())
+		}
+		names[b.apiName()] = true
+		if b.V4V6 && (b.isSocket() || !strings.Contains(b.Address, ":")) {
+			return fmt.Errorf("bind %s sets v4v6 without an IPv6 address", key)
+		}
+		if _, err := internal.ParseDurationToMillis(b.TCPUserTimeout); err != nil {
+			return fmt.Errorf("bind %s: invalid tcp_user_timeout: %w", key, err)
+		}
+	}
+	if err := internal.ValidateDurations(map[string]string{
+		"timeout_client":          f.TimeoutClient,
+		"timeout_http_request":    f.TimeoutHTTPRequest,
+		"timeout_http_keep_alive": f.TimeoutHTTPKeepAlive,
+		"timeout_queue":           f.TimeoutQueue,
+		"timeout_server":          f.TimeoutServer,
+	}); err != nil {
+		return err
+	}
+	if err := f.validateOptions(); err != nil {
+		return err
+	}
+	for _, r := range f.BackendSwitchingRules {
+		if err := r.validate(); err != nil {
+			return err
+		}
+	}
+	if err := filters.ValidateAll(f.Filters); err != nil {
+		return err
+	}
+	if err := httperrors.ValidateErrorFiles(f.ErrorFiles); err != nil {
+		return err
+	}
+	for _, e := range f.ErrorFilesFromHTTPErrors {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := logtargets.ValidateAll(f.LogTargets); err != nil {
+		return err
+	}
+	for _, c := range f.Captures {
+		if err := c.validate(); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// validateListener checks the address part of a bind: either address and
+// port, or a socket without a port. It returns the key that identifies
+// the bind in error messages and duplicate checks.
+func (b BindConfig) validateListener() (string, error) {
+	if b.isSocket() {
+		if b.Port != 0 {
+			return "", fmt.Errorf("bind %s: socket binds take no port", b.Address)
+		}
+		if !b.isUnixSocket() && (b.Mode != "" || b.User != "" || b.Group != "") {
+			return "", fmt.Errorf("bind %s: mode, user and group only apply to unix sockets", b.Address)
+		}
+		if b.Mode != "" {
+			if _, err := strconv.ParseUint(b.Mode, 8, 32); err != nil {
+				return "", fmt.Errorf("bind %s: mode %q is not an octal permission", b.Address, b.Mode)
+			}
+		}
+		return b.Address, nil
+	}
+	if b.Address == "" || b.Port == 0 {
+		return "", fmt.Errorf("each bind must have address and port (or a socket path): %+v", b)
+	}
+	if err := internal.ValidatePort(b.Port); err != nil {
+		return "", fmt.Errorf("bind %s: %w", b.Address, err)
+	}
+	if b.Mode != "" || b.User != "" || b.Group != "" {
+		return "", fmt.Errorf("bind %s: mode, user and group only apply to unix sockets", b.Address)
+	}
+	return net.JoinHostPort(b.Address, strconv.Itoa(b.Port)), nil
+}
+
 const bindKeyValueParts = 2
 
 // parseBindsFromFlags turns strings like "address=0.0.0.0,port=80,ssl=enabled"
-// into a []BindConfig, converting port→int and ssl→bool.
+// or "address=/run/app.sock,mode=660" into a []BindConfig, converting port→int and the switches→bool. A part
+// without "=" continues the previous value, so alpn=h2,http/1.1 works.
 func parseBindsFromFlags(flags []string) []BindConfig {
 	var out []BindConfig
 	for _, raw := range flags {
-		parts := strings.Split(raw, ",")
-		var b BindConfig
-		for _, kv := range parts {
+		var pairs [][]string
+		for _, kv := range strings.Split(raw, ",") {
 			pair := strings.SplitN(kv, "=", bindKeyValueParts)
 			if len(pair) != bindKeyValueParts {
+				if len(pairs) > 0 {
+					pairs[len(pairs)-1][1] += "," + kv
+				}
 				continue
 			}
+			pairs = append(pairs, pair)
+		}
+		var b BindConfig
+		for _, pair := range pairs {
 			key, val := pair[0], pair[1]
+			isTrue := val == "true" || val == "enabled"
 			switch key {
+			case "name":
+				b.Name = val
 			case "address":
 				b.Address = val
 			case "port":
@@ -248,10 +556,30 @@ func parseBindsFromFlags(flags []string) []BindConfig {
 					b.Port = p
 				}
 			case "ssl":
-				b.SSL = (val == "true" || val == "enabled")
+				b.SSL = isTrue
+			case "ssl_certificate":
+				b.SSLCertificate = val
+			case "crt_list":
+				b.CrtList = val
+			case "alpn":
+				b.ALPN = val
+			case "accept_proxy":
+				b.AcceptProxy = isTrue
+			case "transparent":
+				b.Transparent = isTrue
+			case "v4v6":
+				b.V4V6 = isTrue
+			case "tcp_ut", "tcp_user_timeout":
+				b.TCPUserTimeout = val
+			case "mode":
+				b.Mode = val
+			case "user":
+				b.User = val
+			case "group":
+				b.Group = val
 			}
 		}
-		if b.Address != "" && b.Port != 0 {
+		if b.Address != "" && (b.Port != 0 || b.isSocket()) {
 			out = append(out, b)
 		}
 	}
@@ -271,23 +599,3 @@ func toStringMap(m map[string]interface{}) map[string]string {
 	}
 	return out
 }
-
-// getIntField extracts an integer field from a generic map where
-// numbers are typically float64 from JSON decoding.
-func getIntField(obj map[string]interface{}, key string) (int, bool) {
-	v, ok := obj[key]
-	if !ok || v == nil {
-		return 0, false
-	}
-
-	switch n := v.(type) {
-	case int:
-		return n, true
-	case int64:
-		return int(n), true
-	case float64:
-		return int(n), true
-	default:
-		return 0, false
-	}
-}
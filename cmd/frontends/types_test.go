@@ -1,6 +1,9 @@
 package frontends
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestFrontendWithBindsToPayload_Timeouts(t *testing.T) {
 	t.Parallel()
@@ -38,3 +41,129 @@ func TestFrontendWithBindsToPayload_Timeouts(t *testing.T) {
 		t.Fatalf("TimeoutServer = %d, want %d", payload.TimeoutServer, 5000)
 	}
 }
+
+func TestFrontendWithBindsEffectiveBinds_Defaults(t *testing.T) {
+	t.Parallel()
+
+	f := &frontendWithBinds{
+		BindDefaults: &BindDefaults{
+			SSLCertificate: "/etc/haproxy/certs/site.pem",
+			ALPN:           "h2,http/1.1",
+			AcceptProxy:    true,
+		},
+		Binds: []BindConfig{
+			{Address: "0.0.0.0", Port: 443, SSL: true},
+			{Address: "0.0.0.0", Port: 8443, SSL: true, SSLCertificate: "/etc/haproxy/certs/other.pem"},
+			{Address: "0.0.0.0", Port: 80},
+		},
+	}
+
+	binds := f.EffectiveBinds()
+
+	if binds[0].SSLCertificate != "/etc/haproxy/certs/site.pem" || binds[0].ALPN != "h2,http/1.1" {
+		t.Fatalf("ssl bind did not inherit defaults: %+v", binds[0])
+	}
+	if binds[1].SSLCertificate != "/etc/haproxy/certs/other.pem" {
+		t.Fatalf("explicit certificate was overridden: %+v", binds[1])
+	}
+	if binds[2].SSLCertificate != "" || binds[2].ALPN != "" {
+		t.Fatalf("plain bind must not get TLS defaults: %+v", binds[2])
+	}
+	for _, b := range binds {
+		if !b.AcceptProxy {
+			t.Fatalf("accept_proxy default not applied: %+v", b)
+		}
+	}
+
+	if f.Binds[0].SSLCertificate != "" {
+		t.Fatal("EffectiveBinds must not modify the manifest binds")
+	}
+}
+
+func TestBindCrtList(t *testing.T) {
+	t.Parallel()
+
+	binds := parseBindsFromFlags([]string{"address=0.0.0.0,port=443,ssl=enabled,crt_list=/etc/haproxy/ssl/sites.txt"})
+	if len(binds) != 1 || binds[0].CrtList != "/etc/haproxy/ssl/sites.txt" {
+		t.Fatalf("crt_list not parsed: %+v", binds)
+	}
+	if payload := binds[0].toPayload(); payload.CrtList != "/etc/haproxy/ssl/sites.txt" {
+		t.Fatalf("crt_list not sent: %+v", payload)
+	}
+
+	defaults := BindDefaults{SSLCertificate: "/etc/haproxy/certs/site.pem"}
+	if b := defaults.apply(binds[0]); b.SSLCertificate != "" {
+		t.Fatalf("default certificate added to a crt-list bind: %+v", b)
+	}
+
+	f := &frontendWithBinds{
+		frontendConfig: frontendConfig{Name: "web", Mode: "http"},
+		Binds:          []BindConfig{{Address: "0.0.0.0", Port: 80, CrtList: "/etc/haproxy/ssl/sites.txt"}},
+	}
+	if err := f.Validate(); err == nil {
+		t.Fatal("expected error for crt_list without ssl")
+	}
+}
+
+func TestFrontendSetOption(t *testing.T) {
+	t.Parallel()
+
+	var cfg frontendConfig
+	if err := cfg.setOption("http-ignore-probes"); err != nil || cfg.HTTPIgnoreProbes != stateEnabled {
+		t.Fatalf("setOption: %v, http_ignore_probes = %q", err, cfg.HTTPIgnoreProbes)
+	}
+	if err := cfg.setOption("no-clitcpka"); err != nil || cfg.Clitcpka != stateDisabled {
+		t.Fatalf("setOption: %v, clitcpka = %q", err, cfg.Clitcpka)
+	}
+	if err := cfg.setOption("forwardfor"); err == nil {
+		t.Error("expected error for an unknown option")
+	}
+}
+
+func TestParseBindsFromFlags_Options(t *testing.T) {
+	t.Parallel()
+
+	got := parseBindsFromFlags([]string{"name=https,address=::,port=443,ssl=enabled,alpn=h2,http/1.1,v4v6=true,transparent=true,tcp_ut=30s"})
+	if len(got) != 1 {
+		t.Fatalf("binds = %+v", got)
+	}
+	want := BindConfig{Name: "https", Address: "::", Port: 443, SSL: true, ALPN: "h2,http/1.1", V4V6: true, Transparent: true, TCPUserTimeout: "30s"}
+	if got[0] != want {
+		t.Fatalf("bind = %+v, want %+v", got[0], want)
+	}
+	if p := got[0].toPayload(); p.Name != "https" || p.TCPUserTimeout != 30000 {
+		t.Fatalf("payload = %+v", p)
+	}
+	if p := (BindConfig{Address: "0.0.0.0", Port: 80}).toPayload(); p.Name != "0.0.0.0:80" {
+		t.Fatalf("default name = %q", p.Name)
+	}
+}
+
+func TestBindValidateSockets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		bind    BindConfig
+		wantErr string
+	}{
+		{"unix socket", BindConfig{Address: "/run/app.sock", Mode: "660", User: "haproxy"}, ""},
+		{"unix prefix", BindConfig{Address: "unix@/run/app.sock"}, ""},
+		{"abstract", BindConfig{Address: "abns@app"}, ""},
+		{"socket with port", BindConfig{Address: "/run/app.sock", Port: 80}, "no port"},
+		{"abstract with mode", BindConfig{Address: "abns@app", Mode: "600"}, "unix sockets"},
+		{"bad mode", BindConfig{Address: "/run/app.sock", Mode: "rw"}, "octal"},
+		{"mode on tcp", BindConfig{Address: "0.0.0.0", Port: 80, User: "haproxy"}, "unix sockets"},
+		{"no port", BindConfig{Address: "0.0.0.0"}, "address and port"},
+	}
+	for _, tt := range tests {
+		f := frontendWithBinds{frontendConfig: frontendConfig{Name: "web", Mode: "http"}, Binds: []BindConfig{tt.bind}}
+		err := f.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: error = %v, want mention of %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -3,14 +3,29 @@ package cmd
 import (
 	"haproxyctl/cmd/acls"
 	"haproxyctl/cmd/backends"
+	"haproxyctl/cmd/caches"
 	"haproxyctl/cmd/certificates"
+	"haproxyctl/cmd/cluster"
 	"haproxyctl/cmd/configuration"
+	"haproxyctl/cmd/crtlists"
+	"haproxyctl/cmd/filters"
 	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/httprules"
+	"haproxyctl/cmd/logforwards"
+	"haproxyctl/cmd/logtargets"
+	"haproxyctl/cmd/maps"
 	"haproxyctl/cmd/reloads"
+	"haproxyctl/cmd/resolvers"
+	"haproxyctl/cmd/rings"
 	"haproxyctl/cmd/servers"
+	"haproxyctl/cmd/spoe"
 	"haproxyctl/cmd/stats"
+	"haproxyctl/cmd/sticktables"
+	"haproxyctl/cmd/storage"
 	"haproxyctl/cmd/transactions"
 	"haproxyctl/cmd/userlists"
+	"haproxyctl/internal"
 
 	"github.com/spf13/cobra"
 )
@@ -25,7 +40,9 @@ Examples:
   haproxyctl get configuration version -o json
   haproxyctl get backend mybackend -o yaml
   haproxyctl get frontends -o json
-  haproxyctl get acl myfrontend -o yaml`,
+  haproxyctl get acl myfrontend -o yaml
+  haproxyctl get backends -o yaml --output-file backends.yaml
+  haproxyctl get backends -o yaml --output-file manifests/ --split-by-resource`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
 		// If no subcommand was provided, show help.
 		return cmd.Help()
@@ -43,13 +60,43 @@ func init() {
 	getCmd.AddCommand(acls.GetACLsCmd)
 	getCmd.AddCommand(backends.GetBackendsCmd)
 	getCmd.AddCommand(certificates.GetCertificatesCmd)
+	getCmd.AddCommand(crtlists.GetCrtListsCmd)
 	getCmd.AddCommand(configuration.GetConfigurationCmd)
 	getCmd.AddCommand(frontends.GetFrontendsCmd)
+	getCmd.AddCommand(httprules.GetHTTPRequestRulesCmd)
+	getCmd.AddCommand(httprules.GetHTTPResponseRulesCmd)
+	getCmd.AddCommand(frontends.GetSwitchingRulesCmd)
+	getCmd.AddCommand(backends.GetServerSwitchingRulesCmd)
+	getCmd.AddCommand(backends.GetStickRulesCmd)
+	getCmd.AddCommand(backends.GetHTTPChecksCmd)
+	getCmd.AddCommand(backends.GetTCPChecksCmd)
+	getCmd.AddCommand(filters.GetFiltersCmd)
+	getCmd.AddCommand(logtargets.GetLogTargetsCmd)
+	getCmd.AddCommand(frontends.GetCapturesCmd)
+	getCmd.AddCommand(maps.GetMapsCmd)
 	getCmd.AddCommand(reloads.GetReloadsCmd)
 	getCmd.AddCommand(stats.GetStatsCmd)
+	getCmd.AddCommand(sticktables.GetStickTablesCmd)
 	getCmd.AddCommand(transactions.GetTransactionsCmd)
 	getCmd.AddCommand(userlists.GetUserlistsCmd)
+	getCmd.AddCommand(userlists.GetUsersCmd)
+	getCmd.AddCommand(userlists.GetGroupsCmd)
+	getCmd.AddCommand(resolvers.GetResolversCmd)
+	getCmd.AddCommand(resolvers.GetNameserversCmd)
+	getCmd.AddCommand(caches.GetCachesCmd)
+	getCmd.AddCommand(httperrors.GetHTTPErrorsCmd)
+	getCmd.AddCommand(httperrors.GetErrorFilesCmd)
+	getCmd.AddCommand(storage.GetStorageFilesCmd)
+	getCmd.AddCommand(rings.GetRingsCmd)
+	getCmd.AddCommand(logforwards.GetLogForwardsCmd)
+	getCmd.AddCommand(spoe.GetSPOECmd)
+	getCmd.AddCommand(cluster.GetClusterCmd)
 	getCmd.AddCommand(servers.GetServersCmd)
+	getCmd.AddCommand(servers.GetWeightsCmd)
 
-	getCmd.PersistentFlags().StringP("output", "o", "", "Output format: yaml or json (default: table)")
+	getCmd.PersistentFlags().StringP("output", "o", "", "Output format: wide, name, yaml, json, jsonpath=<template> or custom-columns=<spec> (default: table)")
+	getCmd.PersistentFlags().String(internal.OutputFileFlag, "", "Write the output to this file instead of stdout")
+	getCmd.PersistentFlags().String(internal.SortByFlag, "", "Sort lists by a field path, e.g. .mode or -.weight for descending")
+	getCmd.PersistentFlags().Bool(internal.NoHeadersFlag, false, "Do not print headers in table output")
+	getCmd.PersistentFlags().Bool(internal.SplitByResourceFlag, false, "With --output-file, write each listed resource to its own file in that directory")
 }
@@ -0,0 +1,249 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"haproxyctl/cmd/backends"
+	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/userlists"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// getAllCmd represents "get all".
+var getAllCmd = &cobra.Command{
+	Use:   "all",
+	Short: "List frontends, backends, servers, userlists and certificates",
+	Long: `Fetch every frontend, backend, server, userlist and SSL certificate in one
+pass. The default output prints one table per kind; -o yaml and -o json
+print a single List whose items are manifests apply accepts (servers are
+part of their Backend, binds part of their Frontend).
+
+Examples:
+  haproxyctl get all
+  haproxyctl get all -o yaml
+  haproxyctl get all -o yaml --output-file backup/ --split-by-resource`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		format := internal.GetFlagString(cmd, "output")
+		if manifestOutput(format) {
+			list, err := allManifests(cmd.Context(), format)
+			if err != nil {
+				internal.Fatalf("Failed to fetch resources: %v", err)
+			}
+			internal.FormatOutputForCmd(cmd, list, format)
+			return
+		}
+
+		tables, err := allTables(cmd.Context())
+		if err != nil {
+			internal.Fatalf("Failed to fetch resources: %v", err)
+		}
+		printAllTables(tables)
+	},
+}
+
+// resourceTable is the table of one kind in the "get all" output.
+type resourceTable struct {
+	Title string
+	Rows  []interface{}
+}
+
+// manifestOutput reports whether format renders the manifests of every kind
+// rather than the per-kind summary tables.
+func manifestOutput(format string) bool {
+	switch format {
+	case internal.OutputFormatYAML, "json", internal.OutputFormatName:
+		return true
+	}
+	return internal.IsJSONPathFormat(format) || internal.IsCustomColumnsFormat(format)
+}
+
+// allManifests returns every resource as a List of manifests, in the order
+// apply needs them: userlists and backends before the frontends using them.
+func allManifests(ctx context.Context, format string) (internal.ManifestList, error) {
+	list := internal.ManifestListOf(nil)
+
+	fetchers := []func() ([]interface{}, error){
+		func() ([]interface{}, error) { return userlists.UserlistManifests(ctx) },
+		func() ([]interface{}, error) { return backends.BackendManifests(ctx) },
+		func() ([]interface{}, error) { return frontends.FrontendManifests(ctx) },
+		certificateManifests,
+	}
+	for _, fetch := range fetchers {
+		items, err := fetch()
+		if err != nil {
+			return list, err
+		}
+		list.Items = append(list.Items, items...)
+	}
+
+	// Several manifest types only carry yaml tags; render them through
+	// their YAML form so JSON uses the same field names.
+	if format == "json" {
+		for i, item := range list.Items {
+			m, err := internal.ManifestAsMap(item)
+			if err != nil {
+				return list, err
+			}
+			list.Items[i] = m
+		}
+	}
+	return list, nil
+}
+
+// certificateManifests returns the SSL certificate storage entries as
+// Certificate items, sorted by name.
+func certificateManifests() ([]interface{}, error) {
+	certs, err := internal.GetResourceList("/services/haproxy/storage/ssl_certificates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch certificates: %w", err)
+	}
+	internal.SortByStringField(certs, "storage_name")
+
+	items := make([]interface{}, 0, len(certs))
+	for _, cert := range certs {
+		item := map[string]interface{}{"apiVersion": "haproxyctl/v1", "kind": "Certificate"}
+		for k, v := range cert {
+			item[k] = v
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// allTables fetches the summary rows of every kind.
+func allTables(ctx context.Context) ([]resourceTable, error) {
+	var frontendRows, backendRows, serverRows, userlistRows, certificateRows []interface{}
+
+	frontendList, err := internal.GetResourceList("/services/haproxy/configuration/frontends")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch frontends: %w", err)
+	}
+	internal.SortByStringField(frontendList, "name")
+	for _, fe := range frontendList {
+		name, _ := fe["name"].(string)
+		binds, err := internal.GetResourceList("/services/haproxy/configuration/frontends/" + name + "/binds")
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch binds of frontend %q: %w", name, err)
+		}
+		frontendRows = append(frontendRows, map[string]interface{}{
+			"name":            name,
+			"mode":            fe["mode"],
+			"default_backend": fe["default_backend"],
+			"binds":           len(binds),
+		})
+	}
+
+	backendList, err := internal.GetResourceList("/services/haproxy/configuration/backends")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch backends: %w", err)
+	}
+	internal.SortByStringField(backendList, "name")
+	for _, be := range backendList {
+		name, _ := be["name"].(string)
+		srvs, err := internal.GetResourceList("/services/haproxy/configuration/backends/" + name + "/servers")
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch servers of backend %q: %w", name, err)
+		}
+		var algorithm interface{}
+		if balance, ok := be["balance"].(map[string]interface{}); ok {
+			algorithm = balance["algorithm"]
+		}
+		backendRows = append(backendRows, map[string]interface{}{
+			"name":    name,
+			"mode":    be["mode"],
+			"balance": algorithm,
+			"servers": len(srvs),
+		})
+
+		internal.SortByStringField(srvs, "name")
+		for _, srv := range srvs {
+			serverRows = append(serverRows, map[string]interface{}{
+				"name":    srv["name"],
+				"backend": name,
+				"address": srv["address"],
+				"port":    srv["port"],
+				"weight":  srv["weight"],
+			})
+		}
+	}
+
+	manifests, err := userlists.UserlistManifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range manifests {
+		if ul, ok := item.(*userlists.UserlistManifest); ok {
+			userlistRows = append(userlistRows, map[string]interface{}{
+				"name":   ul.Name,
+				"users":  len(ul.Users),
+				"groups": len(ul.Groups),
+			})
+		}
+	}
+
+	certs, err := certificateManifests()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range certs {
+		if cert, ok := item.(map[string]interface{}); ok {
+			certificateRows = append(certificateRows, map[string]interface{}{
+				"name": cert["storage_name"],
+				"file": cert["file"],
+			})
+		}
+	}
+
+	return []resourceTable{
+		{Title: "Frontends", Rows: frontendRows},
+		{Title: "Backends", Rows: backendRows},
+		{Title: "Servers", Rows: serverRows},
+		{Title: "Userlists", Rows: userlistRows},
+		{Title: "Certificates", Rows: certificateRows},
+	}, nil
+}
+
+// printAllTables prints one table per kind that has resources.
+func printAllTables(tables []resourceTable) {
+	printed := 0
+	for _, table := range tables {
+		if len(table.Rows) == 0 {
+			continue
+		}
+		if printed > 0 {
+			_, _ = fmt.Fprintln(os.Stdout)
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "%s:\n", table.Title)
+		internal.FormatOutput(table.Rows, "table")
+		printed++
+	}
+	if printed == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "No resources found.")
+	}
+}
+
+func init() {
+	getCmd.AddCommand(getAllCmd)
+}
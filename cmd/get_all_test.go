@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+
+	"gopkg.in/yaml.v2"
+)
+
+func seedGetAll(srv *testserver.Server) {
+	srv.AddBackend(map[string]interface{}{"name": "app", "mode": "http", "balance": map[string]interface{}{"algorithm": "roundrobin"}})
+	srv.AddServer("app", map[string]interface{}{"name": "app1", "address": "10.0.0.1", "port": 80})
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http", "default_backend": "app"})
+	srv.AddBind("web", map[string]interface{}{"name": "http", "address": "0.0.0.0", "port": 80})
+	srv.AddUserlist(map[string]interface{}{"name": "admins"})
+	srv.AddCertificate(map[string]interface{}{"storage_name": "site.pem", "file": "/etc/haproxy/ssl/site.pem"})
+}
+
+func TestGetAllTables(t *testing.T) {
+	srv := testserver.New(t)
+	seedGetAll(srv)
+
+	tables, err := allTables(context.Background())
+	if err != nil {
+		t.Fatalf("allTables() error = %v", err)
+	}
+	out := internal.CaptureStdout(t, func() { printAllTables(tables) })
+
+	for _, want := range []string{"Frontends:", "Backends:", "Servers:", "Userlists:", "Certificates:", "roundrobin", "10.0.0.1", "site.pem"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGetAllManifestList(t *testing.T) {
+	srv := testserver.New(t)
+	seedGetAll(srv)
+
+	list, err := allManifests(context.Background(), internal.OutputFormatYAML)
+	if err != nil {
+		t.Fatalf("allManifests() error = %v", err)
+	}
+	if list.Kind != internal.ListKind {
+		t.Fatalf("kind = %q, want List", list.Kind)
+	}
+
+	data, err := yaml.Marshal(list)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), "address: 10.0.0.1") {
+		t.Fatalf("backend manifest is missing its server:\n%s", data)
+	}
+
+	// The list reads back as its items, e.g. for apply -f.
+	parsed, err := internal.ParseManifests(data, "all.yaml")
+	if err != nil {
+		t.Fatalf("ParseManifests() error = %v", err)
+	}
+	if len(parsed) != len(list.Items) {
+		t.Fatalf("parsed %d manifests from a List of %d", len(parsed), len(list.Items))
+	}
+
+	// JSON items are plain maps with the manifest field names.
+	jsonList, err := allManifests(context.Background(), "json")
+	if err != nil {
+		t.Fatalf("allManifests(json) error = %v", err)
+	}
+	var kinds []string
+	for _, item := range jsonList.Items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			t.Fatalf("JSON item is a %T, want a map", item)
+		}
+		kinds = append(kinds, m["kind"].(string))
+	}
+	if got, want := strings.Join(kinds, ","), "Userlist,Backend,Frontend,Certificate"; got != want {
+		t.Fatalf("item kinds = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httperrors
+
+import (
+	"fmt"
+	"reflect"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ApplyHTTPErrorsFromYAML applies an HTTPErrors manifest in a declarative
+// way: the section is created when missing, otherwise replaced in place.
+func ApplyHTTPErrorsFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest HTTPErrorsManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse http-errors manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid http-errors configuration: %w", err)
+	}
+	manifest.normalize()
+
+	if outputFormat != "" || dryRun {
+		if outputFormat == "" {
+			internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		} else {
+			internal.FormatOutput(manifest.toAPIPayload(), outputFormat)
+		}
+		if dryRun {
+			internal.PrintDryRun()
+		}
+		return nil
+	}
+
+	name := manifest.Name
+	current, err := getHTTPErrorsManifest(name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return fmt.Errorf("failed to check http-errors existence: %w", err)
+	}
+	exists := err == nil
+
+	if exists && reflect.DeepEqual(*current, manifest) {
+		internal.PrintStatus(httpErrorsKind, name, internal.ActionUnchanged)
+		return nil
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if _, err := internal.SendRequest("PUT", httpErrorsSectionPath(name), params, manifest.toAPIPayload()); err != nil {
+			return fmt.Errorf("failed to update http-errors %q: %w", name, err)
+		}
+		internal.PrintStatus(httpErrorsKind, name, internal.ActionConfigured)
+		return nil
+	}
+	if _, err := internal.SendRequest("POST", httpErrorsPath, params, manifest.toAPIPayload()); err != nil {
+		return fmt.Errorf("failed to create http-errors %q: %w", name, err)
+	}
+	internal.PrintStatus(httpErrorsKind, name, internal.ActionCreated)
+	return nil
+}
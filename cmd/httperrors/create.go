@@ -0,0 +1,136 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httperrors provides commands to manage HAProxy http-errors
+// sections and the error files of frontends and backends.
+package httperrors
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"haproxyctl/cmd/storage"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// CreateHTTPErrorsCmd represents "create http-errors <name>".
+var CreateHTTPErrorsCmd = &cobra.Command{
+	Use:   "http-errors <name>",
+	Short: "Create an http-errors section",
+	Long: `Create an http-errors section from one or more --errorfile <code>=<file>
+flags. With --upload the files are local pages: each one is uploaded to the
+general storage first and the section points at the stored copy, so a
+custom page can be deployed in one step. Frontends and backends use the
+section with "haproxyctl create errorfiles --http-errors <name>".
+
+Examples:
+  haproxyctl create http-errors site --errorfile 503=/etc/haproxy/errors/503.http
+  haproxyctl create http-errors site --errorfile 503=./errors/503.http --errorfile 404=./errors/404.http --upload
+  haproxyctl create -f http-errors.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifest := HTTPErrorsManifest{APIVersion: apiVersionV1, Kind: httpErrorsKind, Name: args[0]}
+		for _, raw := range internal.GetFlagStringSlice(cmd, "errorfile") {
+			e, err := ParseErrorFile(raw)
+			if err != nil {
+				internal.FatalCodef(internal.ExitUsage, "Invalid --errorfile: %v", err)
+			}
+			manifest.ErrorFiles = append(manifest.ErrorFiles, e)
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+
+		if internal.GetFlagBool(cmd, "upload") {
+			if err := ValidateErrorFiles(manifest.ErrorFiles); err != nil {
+				internal.Fatalf("Failed to create http-errors %q: %v", args[0], err)
+			}
+			if !dryRun {
+				uploaded, err := uploadErrorFiles(cmd.Context(), manifest.ErrorFiles)
+				if err != nil {
+					internal.Fatalf("Failed to upload error pages: %v", err)
+				}
+				manifest.ErrorFiles = uploaded
+			}
+		}
+		if err := createHTTPErrors(manifest, dryRun); err != nil {
+			internal.Fatalf("Failed to create http-errors %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateHTTPErrorsCmd.Flags().StringArray("errorfile", nil, "Error page as <code>=<file> (repeatable)")
+	CreateHTTPErrorsCmd.Flags().Bool("upload", false, "Treat the files as local pages and upload them to the general storage first")
+	CreateHTTPErrorsCmd.Flags().Bool("dry-run", false, "Print the manifest without creating it")
+}
+
+// CreateHTTPErrorsFromFile is used for "haproxyctl create -f http-errors.yaml".
+func CreateHTTPErrorsFromFile(data []byte) error {
+	var manifest HTTPErrorsManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse http-errors manifest: %w", err)
+	}
+	if err := createHTTPErrors(manifest, internal.IsOffline()); err != nil {
+		return internal.FormatAPIError(httpErrorsKind, manifest.Name, "create", err)
+	}
+	return nil
+}
+
+// createHTTPErrors creates an http-errors section.
+func createHTTPErrors(manifest HTTPErrorsManifest, dryRun bool) error {
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	manifest.normalize()
+
+	if dryRun {
+		internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", httpErrorsPath, params, manifest.toAPIPayload()); err != nil {
+		return err
+	}
+
+	internal.PrintStatus(httpErrorsKind, manifest.Name, internal.ActionCreated)
+	return nil
+}
+
+// uploadErrorFiles uploads the local page of each error file to the general
+// storage and returns the list pointing at the stored copies.
+func uploadErrorFiles(ctx context.Context, list []ErrorFile) ([]ErrorFile, error) {
+	out := make([]ErrorFile, 0, len(list))
+	for _, e := range list {
+		data, err := os.ReadFile(e.File) //nolint:gosec // path comes from explicit CLI input
+		if err != nil {
+			return nil, fmt.Errorf("failed to read error page for %d: %w", e.Code, err)
+		}
+		path, err := storage.UploadFile(ctx, "", e.File, data)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ErrorFile{Code: e.Code, File: path})
+	}
+	return out, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httperrors provides commands to manage HAProxy http-errors
+// sections and the error files of frontends and backends.
+package httperrors
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteHTTPErrorsCmd represents "delete http-errors <name>".
+var DeleteHTTPErrorsCmd = &cobra.Command{
+	Use:   "http-errors <name>",
+	Short: "Delete an http-errors section",
+	Long: `Delete an http-errors section. Frontends and backends that still use it
+through "errorfiles" must be changed first, or HAProxy rejects the
+configuration. Uploaded pages stay in the general storage.
+
+Examples:
+  haproxyctl delete http-errors site`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := DeleteHTTPErrorsByName(args[0]); err != nil {
+			internal.Fatalf("Failed to delete http-errors %q: %v", args[0], err)
+		}
+	},
+}
+
+// DeleteHTTPErrorsByName deletes an http-errors section.
+func DeleteHTTPErrorsByName(name string) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("DELETE", httpErrorsSectionPath(name), params, nil); err != nil {
+		return internal.FormatAPIError(httpErrorsKind, name, "delete", err)
+	}
+
+	internal.PrintStatus(httpErrorsKind, name, internal.ActionDeleted)
+	return nil
+}
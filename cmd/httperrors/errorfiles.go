@@ -0,0 +1,330 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httperrors provides commands to manage HAProxy http-errors
+// sections and the error files of frontends and backends.
+package httperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"haproxyctl/cmd/filters"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+const errorFileKind = "ErrorFile"
+
+// GetErrorFilesCmd represents "get errorfiles --parent <type>/<name>".
+var GetErrorFilesCmd = &cobra.Command{
+	Use:     "errorfiles",
+	Aliases: []string{"errorfile"},
+	Short:   "List the error pages of a frontend or backend",
+	Long: `List the errorfile lines of a frontend or backend, followed by the
+http-errors sections it uses through "errorfiles" (codes "all" meaning every
+page of the section).
+
+Examples:
+  haproxyctl get errorfiles --parent backend/app
+  haproxyctl get errorfiles --parent frontend/web -o yaml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, filters.ParentTypes...)
+		files, from, err := LiveErrorFiles(parentType, parent)
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(parentType, parent))
+			}
+			internal.Fatalf("Failed to fetch error files of %s '%s': %v", parentType, parent, err)
+		}
+
+		rows := make([]map[string]interface{}, 0, len(files)+len(from))
+		for _, e := range files {
+			rows = append(rows, map[string]interface{}{"code": e.Code, "file": e.File})
+		}
+		for _, e := range from {
+			codes := "all"
+			if len(e.Codes) > 0 {
+				parts := make([]string, 0, len(e.Codes))
+				for _, c := range e.Codes {
+					parts = append(parts, strconv.Itoa(c))
+				}
+				codes = strings.Join(parts, ",")
+			}
+			rows = append(rows, map[string]interface{}{"http_errors": e.Name, "codes": codes})
+		}
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, errorFileColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// errorFileColumns shows both errorfile and errorfiles lines in one table.
+var errorFileColumns = internal.ColumnSet{
+	Default: []string{"code", "file", "http_errors", "codes"},
+}
+
+// CreateErrorFilesCmd represents "create errorfiles --parent <type>/<name>".
+var CreateErrorFilesCmd = &cobra.Command{
+	Use:     "errorfiles",
+	Aliases: []string{"errorfile"},
+	Short:   "Set an error page of a frontend or backend",
+	Long: `Set the page a frontend or backend returns for one status code
+("errorfile <code> <file>"), replacing the page already set for that code.
+With --upload, --file is a local page that is uploaded to the general
+storage first. With --http-errors instead of --file, the section uses the
+pages of an http-errors section ("errorfiles <section> [<code> ...]"): all
+of them, or only those for the --code values given.
+
+Examples:
+  haproxyctl create errorfiles --parent backend/app --code 503 --file /etc/haproxy/errors/503.http
+  haproxyctl create errorfiles --parent backend/app --code 503 --file ./errors/503.http --upload
+  haproxyctl create errorfiles --parent frontend/web --http-errors site
+  haproxyctl create errorfiles --parent frontend/web --http-errors site --code 404 --code 503`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, filters.ParentTypes...)
+		codes, _ := cmd.Flags().GetIntSlice("code")
+		file := internal.GetFlagString(cmd, "file")
+		section := internal.GetFlagString(cmd, "http-errors")
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+
+		var err error
+		switch {
+		case section != "" && file != "":
+			err = errors.New("--file and --http-errors are mutually exclusive")
+		case section != "":
+			err = SetErrorFilesFrom(parentType, parent, ErrorFilesFrom{Name: section, Codes: codes}, dryRun)
+		case len(codes) != 1 || file == "":
+			err = errors.New("either --http-errors, or --file with exactly one --code, is required")
+		default:
+			e := ErrorFile{Code: codes[0], File: file}
+			if internal.GetFlagBool(cmd, "upload") && !dryRun {
+				e, err = uploadErrorFile(cmd.Context(), e)
+				if err != nil {
+					break
+				}
+			}
+			err = SetErrorFile(parentType, parent, e, dryRun)
+		}
+		if err != nil {
+			internal.Fatalf("Failed to set error files of %s '%s': %v", parentType, parent, err)
+		}
+	},
+}
+
+// DeleteErrorFilesCmd represents "delete errorfiles --parent <type>/<name>".
+var DeleteErrorFilesCmd = &cobra.Command{
+	Use:     "errorfiles",
+	Aliases: []string{"errorfile"},
+	Short:   "Remove an error page of a frontend or backend",
+	Long: `Remove the errorfile line for --code, or the errorfiles line using the
+http-errors section given with --http-errors. HAProxy falls back to its
+built-in page.
+
+Examples:
+  haproxyctl delete errorfiles --parent backend/app --code 503
+  haproxyctl delete errorfiles --parent frontend/web --http-errors site`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, filters.ParentTypes...)
+		codes, _ := cmd.Flags().GetIntSlice("code")
+		section := internal.GetFlagString(cmd, "http-errors")
+		if (section == "") == (len(codes) == 0) || len(codes) > 1 {
+			internal.FatalCodef(internal.ExitUsage, "exactly one of --code or --http-errors is required")
+		}
+		var code int
+		if len(codes) == 1 {
+			code = codes[0]
+		}
+		if err := DeleteErrorFile(parentType, parent, code, section); err != nil {
+			internal.Fatalf("Failed to delete error file of %s '%s': %v", parentType, parent, err)
+		}
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{GetErrorFilesCmd, CreateErrorFilesCmd, DeleteErrorFilesCmd} {
+		internal.AddParentFlag(cmd, "error pages", filters.ParentTypes...)
+	}
+	CreateErrorFilesCmd.Flags().IntSlice("code", nil, "Status code of the page; repeatable with --http-errors")
+	CreateErrorFilesCmd.Flags().String("file", "", "Page returned for --code, as a path on the HAProxy host")
+	CreateErrorFilesCmd.Flags().Bool("upload", false, "Treat --file as a local page and upload it to the general storage first")
+	CreateErrorFilesCmd.Flags().String("http-errors", "", "Use the pages of this http-errors section")
+	CreateErrorFilesCmd.Flags().Bool("dry-run", false, "Print the change without applying it")
+	DeleteErrorFilesCmd.Flags().IntSlice("code", nil, "Status code whose errorfile line to remove")
+	DeleteErrorFilesCmd.Flags().String("http-errors", "", "http-errors section whose errorfiles line to remove")
+}
+
+func parentPath(parentType, parent string) string {
+	return fmt.Sprintf("/services/haproxy/configuration/%ss/%s", parentType, parent)
+}
+
+// LiveErrorFiles returns the errorfile and errorfiles lines of a frontend
+// or backend.
+func LiveErrorFiles(parentType, parent string) ([]ErrorFile, []ErrorFilesFrom, error) {
+	obj, err := internal.GetResource(parentPath(parentType, parent))
+	if err != nil {
+		return nil, nil, err
+	}
+	return ErrorFilesFromAPI(obj["error_files"]), ErrorFilesFromFromAPI(obj["errorfiles_from_http_errors"]), nil
+}
+
+// SetErrorFile sets the page of a frontend or backend for e.Code.
+func SetErrorFile(parentType, parent string, e ErrorFile, dryRun bool) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%s/%d", parentType, parent, e.Code)
+	return updateParent(parentType, parent, id, dryRun, func(files []ErrorFile, from []ErrorFilesFrom) ([]ErrorFile, []ErrorFilesFrom) {
+		out := []ErrorFile{e}
+		for _, f := range files {
+			if f.Code != e.Code {
+				out = append(out, f)
+			}
+		}
+		return out, from
+	})
+}
+
+// SetErrorFilesFrom makes a frontend or backend use the pages of an
+// http-errors section, replacing an earlier errorfiles line for it.
+func SetErrorFilesFrom(parentType, parent string, e ErrorFilesFrom, dryRun bool) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if !dryRun {
+		if _, err := internal.GetResource(httpErrorsSectionPath(e.Name)); err != nil {
+			return internal.FormatAPIError(httpErrorsKind, e.Name, "get", err)
+		}
+	}
+	id := fmt.Sprintf("%s/%s/%s", parentType, parent, e.Name)
+	return updateParent(parentType, parent, id, dryRun, func(files []ErrorFile, from []ErrorFilesFrom) ([]ErrorFile, []ErrorFilesFrom) {
+		out := make([]ErrorFilesFrom, 0, len(from)+1)
+		replaced := false
+		for _, f := range from {
+			if f.Name == e.Name {
+				f, replaced = e, true
+			}
+			out = append(out, f)
+		}
+		if !replaced {
+			out = append(out, e)
+		}
+		return files, out
+	})
+}
+
+// DeleteErrorFile removes the errorfile line for code or, when section is
+// set, the errorfiles line using that http-errors section.
+func DeleteErrorFile(parentType, parent string, code int, section string) error {
+	id := fmt.Sprintf("%s/%s/%d", parentType, parent, code)
+	if section != "" {
+		id = fmt.Sprintf("%s/%s/%s", parentType, parent, section)
+	}
+	found := false
+	err := updateParent(parentType, parent, id, false, func(files []ErrorFile, from []ErrorFilesFrom) ([]ErrorFile, []ErrorFilesFrom) {
+		var outFiles []ErrorFile
+		for _, f := range files {
+			if section == "" && f.Code == code {
+				found = true
+				continue
+			}
+			outFiles = append(outFiles, f)
+		}
+		var outFrom []ErrorFilesFrom
+		for _, f := range from {
+			if f.Name == section {
+				found = true
+				continue
+			}
+			outFrom = append(outFrom, f)
+		}
+		return outFiles, outFrom
+	})
+	if err == nil && !found {
+		return fmt.Errorf("%s not found", internal.ResourceID(errorFileKind, id))
+	}
+	return err
+}
+
+// updateParent applies change to the error files of a frontend or backend
+// and replaces the object when they differ. Only the two error file fields
+// are touched; everything else is sent back as the API returned it.
+func updateParent(parentType, parent, id string, dryRun bool, change func([]ErrorFile, []ErrorFilesFrom) ([]ErrorFile, []ErrorFilesFrom)) error {
+	if dryRun {
+		files, from := change(nil, nil)
+		internal.FormatOutput(map[string]interface{}{"error_files": files, "errorfiles_from_http_errors": from}, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	path := parentPath(parentType, parent)
+	obj, err := internal.GetResource(path)
+	if err != nil {
+		return internal.FormatAPIError(parentType, parent, "get", err)
+	}
+	files, from := ErrorFilesFromAPI(obj["error_files"]), ErrorFilesFromFromAPI(obj["errorfiles_from_http_errors"])
+	newFiles, newFrom := change(files, from)
+	SortErrorFiles(newFiles)
+
+	if reflect.DeepEqual(files, newFiles) && reflect.DeepEqual(from, newFrom) {
+		internal.PrintStatus(errorFileKind, id, internal.ActionUnchanged)
+		return nil
+	}
+	action := internal.ActionConfigured
+	switch before, after := len(files)+len(from), len(newFiles)+len(newFrom); {
+	case after > before:
+		action = internal.ActionCreated
+	case after < before:
+		action = internal.ActionDeleted
+	}
+
+	setOrDelete(obj, "error_files", len(newFiles) > 0, newFiles)
+	setOrDelete(obj, "errorfiles_from_http_errors", len(newFrom) > 0, newFrom)
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("PUT", path, params, obj); err != nil {
+		return internal.FormatAPIError(parentType, parent, "update", err)
+	}
+	internal.PrintStatus(errorFileKind, id, action)
+	return nil
+}
+
+func setOrDelete(obj map[string]interface{}, key string, set bool, value interface{}) {
+	if set {
+		obj[key] = value
+	} else {
+		delete(obj, key)
+	}
+}
+
+// uploadErrorFile uploads the local page of e to the general storage and
+// returns e pointing at the stored copy.
+func uploadErrorFile(ctx context.Context, e ErrorFile) (ErrorFile, error) {
+	uploaded, err := uploadErrorFiles(ctx, []ErrorFile{e})
+	if err != nil {
+		return ErrorFile{}, err
+	}
+	return uploaded[0], nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httperrors provides commands to manage HAProxy http-errors
+// sections and the error files of frontends and backends.
+package httperrors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetHTTPErrorsCmd represents "get http-errors".
+var GetHTTPErrorsCmd = &cobra.Command{
+	Use:   "http-errors [name]",
+	Short: "List HAProxy http-errors sections or fetch details of one",
+	Long: `List the http-errors sections, which group error pages that frontends and
+backends pull in with "errorfiles <section>", or show one section. With
+-o yaml the output is an HTTPErrors manifest that apply accepts.
+
+Examples:
+  haproxyctl get http-errors
+  haproxyctl get http-errors site -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFormat := internal.GetFlagString(cmd, "output")
+
+		if len(args) == 0 {
+			manifests, err := HTTPErrorsManifests()
+			if err != nil {
+				internal.Fatalf("Failed to fetch http-errors sections: %v", err)
+			}
+			if outputFormat != "" && outputFormat != "table" && outputFormat != internal.OutputFormatWide {
+				internal.FormatOutputForCmd(cmd, internal.WithColumns(manifests, internal.ColumnSet{Kind: httpErrorsKind}), outputFormat)
+				return
+			}
+			rows := make([]map[string]interface{}, 0, len(manifests))
+			for _, item := range manifests {
+				rows = append(rows, summaryRow(item.(*HTTPErrorsManifest)))
+			}
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, httpErrorsColumns), outputFormat)
+			return
+		}
+
+		manifest, err := getHTTPErrorsManifest(args[0])
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(httpErrorsKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch http-errors %q: %v", args[0], err)
+		}
+		if outputFormat == "" || outputFormat == "table" || outputFormat == internal.OutputFormatWide {
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(summaryRow(manifest), httpErrorsColumns), outputFormat)
+			return
+		}
+		internal.FormatOutputForCmd(cmd, manifest, outputFormat)
+	},
+}
+
+// httpErrorsColumns shows which codes a section has pages for; wide adds
+// the files.
+var httpErrorsColumns = internal.ColumnSet{
+	Kind:    httpErrorsKind,
+	Default: []string{"name", "codes"},
+	Wide:    []string{"files"},
+}
+
+// summaryRow flattens a manifest into a table row.
+func summaryRow(m *HTTPErrorsManifest) map[string]interface{} {
+	codes := make([]string, 0, len(m.ErrorFiles))
+	files := make([]string, 0, len(m.ErrorFiles))
+	for _, e := range m.ErrorFiles {
+		codes = append(codes, strconv.Itoa(e.Code))
+		files = append(files, e.File)
+	}
+	return map[string]interface{}{
+		"name":  m.Name,
+		"codes": strings.Join(codes, ","),
+		"files": strings.Join(files, ","),
+	}
+}
+
+// getHTTPErrorsManifest fetches an http-errors section.
+func getHTTPErrorsManifest(name string) (*HTTPErrorsManifest, error) {
+	obj, err := internal.GetResource(httpErrorsSectionPath(name))
+	if err != nil {
+		return nil, err
+	}
+	return manifestFromAPI(obj), nil
+}
+
+// HTTPErrorsManifests returns every http-errors section as a manifest that
+// apply accepts, sorted by name.
+func HTTPErrorsManifests() ([]interface{}, error) {
+	list, err := internal.GetResourceList(httpErrorsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch http-errors sections: %w", err)
+	}
+	internal.SortByStringField(list, "name")
+
+	manifests := make([]interface{}, 0, len(list))
+	for _, obj := range list {
+		manifests = append(manifests, manifestFromAPI(obj))
+	}
+	return manifests, nil
+}
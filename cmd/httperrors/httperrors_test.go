@@ -0,0 +1,157 @@
+package httperrors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testHTTPErrorsManifest = `apiVersion: haproxyctl/v1
+kind: HTTPErrors
+name: site
+error_files:
+  - code: 503
+    file: /etc/haproxy/errors/503.http
+  - code: 404
+    file: /etc/haproxy/errors/404.http
+`
+
+func TestApplyHTTPErrorsFromYAML(t *testing.T) {
+	srv := testserver.New(t)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyHTTPErrorsFromYAML([]byte(testHTTPErrorsManifest), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "httperrors/site created") {
+		t.Fatalf("expected created status, got:\n%s", output)
+	}
+	if _, ok := srv.HTTPErrors("site"); !ok {
+		t.Fatal("http-errors section not created")
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyHTTPErrorsFromYAML([]byte(testHTTPErrorsManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "httperrors/site unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	changed := strings.Replace(testHTTPErrorsManifest, "errors/404.http", "errors/not-found.http", 1)
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyHTTPErrorsFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "httperrors/site configured") {
+		t.Fatalf("expected configured status, got:\n%s", output)
+	}
+}
+
+func TestParseErrorFile(t *testing.T) {
+	t.Parallel()
+
+	e, err := ParseErrorFile("503=/etc/haproxy/errors/503.http")
+	if err != nil || e.Code != 503 || e.File != "/etc/haproxy/errors/503.http" {
+		t.Fatalf("ParseErrorFile = %+v, %v", e, err)
+	}
+	for _, bad := range []string{"503", "abc=/x", "302=/x", "503="} {
+		if _, err := ParseErrorFile(bad); err == nil {
+			t.Errorf("ParseErrorFile(%q): expected error", bad)
+		}
+	}
+	if err := ValidateErrorFiles([]ErrorFile{{Code: 503, File: "/a"}, {Code: 503, File: "/b"}}); err == nil {
+		t.Error("expected error for duplicate codes")
+	}
+}
+
+func TestSetErrorFile_UploadsAndIsIdempotent(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "app", "mode": "http"})
+
+	page := filepath.Join(t.TempDir(), "app-503.http")
+	if err := os.WriteFile(page, []byte("HTTP/1.0 503 Service Unavailable\r\n\r\nback soon\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		e, err := uploadErrorFile(context.Background(), ErrorFile{Code: 503, File: page})
+		if err != nil {
+			t.Fatalf("upload failed: %v", err)
+		}
+		if err := SetErrorFile("backend", "app", e, false); err != nil {
+			t.Fatalf("SetErrorFile failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "storagefile/app-503.http created") || !strings.Contains(output, "errorfile/backend/app/503 created") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if data, ok := srv.StorageFile("app-503.http"); !ok || !strings.Contains(string(data), "back soon") {
+		t.Fatalf("page not uploaded: %q", data)
+	}
+	backend, _ := srv.Backend("app")
+	files := ErrorFilesFromAPI(backend["error_files"])
+	if len(files) != 1 || files[0].File != "/etc/haproxy/general/app-503.http" {
+		t.Fatalf("error_files = %+v", files)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		e, err := uploadErrorFile(context.Background(), ErrorFile{Code: 503, File: page})
+		if err != nil {
+			t.Fatalf("second upload failed: %v", err)
+		}
+		if err := SetErrorFile("backend", "app", e, false); err != nil {
+			t.Fatalf("second SetErrorFile failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "storagefile/app-503.http unchanged") || !strings.Contains(output, "errorfile/backend/app/503 unchanged") {
+		t.Fatalf("expected unchanged on second run, got:\n%s", output)
+	}
+}
+
+func TestSetErrorFilesFrom_AndDelete(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddHTTPErrors(map[string]interface{}{"name": "site"})
+
+	if err := SetErrorFilesFrom("frontend", "web", ErrorFilesFrom{Name: "missing"}, false); err == nil {
+		t.Fatal("expected error for an unknown http-errors section")
+	}
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := SetErrorFilesFrom("frontend", "web", ErrorFilesFrom{Name: "site", Codes: []int{503}}, false); err != nil {
+			t.Fatalf("SetErrorFilesFrom failed: %v", err)
+		}
+	})
+	frontend, _ := srv.Frontend("web")
+	from := ErrorFilesFromFromAPI(frontend["errorfiles_from_http_errors"])
+	if len(from) != 1 || from[0].Name != "site" || len(from[0].Codes) != 1 || from[0].Codes[0] != 503 {
+		t.Fatalf("errorfiles_from_http_errors = %+v", from)
+	}
+	if frontend["mode"] != "http" {
+		t.Fatalf("other frontend fields lost: %+v", frontend)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteErrorFile("frontend", "web", 0, "site"); err != nil {
+			t.Fatalf("DeleteErrorFile failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "errorfile/frontend/web/site deleted") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if frontend, _ := srv.Frontend("web"); frontend["errorfiles_from_http_errors"] != nil {
+		t.Fatalf("errorfiles line not removed: %+v", frontend)
+	}
+	if err := DeleteErrorFile("frontend", "web", 503, ""); err == nil {
+		t.Fatal("expected error when no errorfile is set for the code")
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httperrors provides commands to manage HAProxy http-errors
+// sections and the error files of frontends and backends.
+package httperrors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	apiVersionV1   = "haproxyctl/v1"
+	httpErrorsKind = "HTTPErrors"
+
+	httpErrorsPath = "/services/haproxy/configuration/http_errors_sections"
+)
+
+// errorFileCodes are the status codes HAProxy accepts in an errorfile line.
+var errorFileCodes = []int{200, 400, 401, 403, 404, 405, 407, 408, 410, 413, 425, 429, 500, 501, 502, 503, 504}
+
+// ErrorFile is an "errorfile <code> <file>" line of an http-errors section,
+// frontend or backend. File is a path on the HAProxy host, for example one
+// returned by a general storage upload.
+type ErrorFile struct {
+	Code int    `json:"code" yaml:"code"`
+	File string `json:"file" yaml:"file"`
+}
+
+// ErrorFilesFrom is an "errorfiles <section> [<code> ...]" line of a
+// frontend or backend, which uses the error files of an http-errors
+// section: all of them, or only those for Codes.
+type ErrorFilesFrom struct {
+	Name  string `json:"name" yaml:"name"`
+	Codes []int  `json:"codes,omitempty" yaml:"codes,omitempty"`
+}
+
+// Validate checks that the code is one HAProxy accepts and a file is set.
+func (e ErrorFile) Validate() error {
+	if !validCode(e.Code) {
+		return fmt.Errorf("unsupported errorfile code %d (expected one of %s)", e.Code, codeList())
+	}
+	if e.File == "" {
+		return fmt.Errorf("errorfile %d: file is required", e.Code)
+	}
+	return nil
+}
+
+// Validate checks the section name and codes.
+func (e ErrorFilesFrom) Validate() error {
+	if e.Name == "" {
+		return errors.New("errorfiles: http-errors section name is required")
+	}
+	for _, code := range e.Codes {
+		if !validCode(code) {
+			return fmt.Errorf("errorfiles %s: unsupported code %d", e.Name, code)
+		}
+	}
+	return nil
+}
+
+// ValidateErrorFiles validates every entry and rejects two files for the
+// same code, which HAProxy would silently resolve to the last one.
+func ValidateErrorFiles(list []ErrorFile) error {
+	seen := make(map[int]bool, len(list))
+	for _, e := range list {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+		if seen[e.Code] {
+			return fmt.Errorf("duplicate errorfile for code %d", e.Code)
+		}
+		seen[e.Code] = true
+	}
+	return nil
+}
+
+func validCode(code int) bool {
+	for _, c := range errorFileCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func codeList() string {
+	parts := make([]string, 0, len(errorFileCodes))
+	for _, c := range errorFileCodes {
+		parts = append(parts, strconv.Itoa(c))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseErrorFile parses a "<code>=<file>" flag value, e.g.
+// "503=/etc/haproxy/errors/503.http".
+func ParseErrorFile(s string) (ErrorFile, error) {
+	code, file, ok := strings.Cut(s, "=")
+	if !ok {
+		return ErrorFile{}, fmt.Errorf("invalid errorfile %q (expected <code>=<file>)", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(code))
+	if err != nil {
+		return ErrorFile{}, fmt.Errorf("invalid errorfile %q: code must be a number", s)
+	}
+	e := ErrorFile{Code: n, File: strings.TrimSpace(file)}
+	return e, e.Validate()
+}
+
+// SortErrorFiles orders error files by code. HAProxy looks them up by code,
+// so the order carries no meaning and sorting lets lists compare equal.
+func SortErrorFiles(list []ErrorFile) {
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+}
+
+// ErrorFilesFromAPI converts the error_files field of an API object. It
+// returns nil when the field is absent or empty.
+func ErrorFilesFromAPI(v interface{}) []ErrorFile {
+	items, _ := v.([]interface{})
+	var out []ErrorFile
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		e := ErrorFile{}
+		e.Code, _ = internal.GetIntField(obj, "code")
+		e.File, _ = obj["file"].(string)
+		out = append(out, e)
+	}
+	SortErrorFiles(out)
+	return out
+}
+
+// ErrorFilesFromFromAPI converts the errorfiles_from_http_errors field of a
+// frontend or backend. It returns nil when the field is absent or empty.
+func ErrorFilesFromFromAPI(v interface{}) []ErrorFilesFrom {
+	items, _ := v.([]interface{})
+	var out []ErrorFilesFrom
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		e := ErrorFilesFrom{}
+		e.Name, _ = obj["name"].(string)
+		codes, _ := obj["codes"].([]interface{})
+		for _, c := range codes {
+			if n, ok := c.(float64); ok {
+				e.Codes = append(e.Codes, int(n))
+			}
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// HTTPErrorsManifest is the manifest view of an "http-errors <name>"
+// section.
+//
+//nolint:tagliatelle // manifest uses HAProxy's snake_case names like backendConfig
+type HTTPErrorsManifest struct {
+	APIVersion string      `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string      `json:"kind" yaml:"kind"`
+	Name       string      `json:"name" yaml:"name"`
+	ErrorFiles []ErrorFile `json:"error_files" yaml:"error_files"`
+}
+
+// Validate checks the manifest before anything is sent to the API.
+func (m *HTTPErrorsManifest) Validate() error {
+	if m.Name == "" {
+		return errors.New("http-errors section name is required")
+	}
+	if m.Kind != "" && m.Kind != httpErrorsKind {
+		return fmt.Errorf("kind must be %q", httpErrorsKind)
+	}
+	if m.APIVersion != "" && m.APIVersion != apiVersionV1 {
+		return fmt.Errorf("apiVersion must be %q", apiVersionV1)
+	}
+	if len(m.ErrorFiles) == 0 {
+		return errors.New("an http-errors section needs at least one errorfile")
+	}
+	return ValidateErrorFiles(m.ErrorFiles)
+}
+
+// toAPIPayload converts the manifest into the object expected by the
+// http_errors_sections endpoint.
+func (m *HTTPErrorsManifest) toAPIPayload() map[string]interface{} {
+	return map[string]interface{}{"name": m.Name, "error_files": m.ErrorFiles}
+}
+
+// normalize fills in kind and apiVersion and sorts the error files, so
+// manifests from files and from the API compare equal.
+func (m *HTTPErrorsManifest) normalize() {
+	m.APIVersion, m.Kind = apiVersionV1, httpErrorsKind
+	SortErrorFiles(m.ErrorFiles)
+}
+
+// manifestFromAPI converts an API http-errors section into a manifest.
+func manifestFromAPI(obj map[string]interface{}) *HTTPErrorsManifest {
+	m := &HTTPErrorsManifest{}
+	m.Name, _ = obj["name"].(string)
+	m.ErrorFiles = ErrorFilesFromAPI(obj["error_files"])
+	m.normalize()
+	return m
+}
+
+func httpErrorsSectionPath(name string) string {
+	return httpErrorsPath + "/" + name
+}
@@ -0,0 +1,61 @@
+package httprules
+
+import (
+	"fmt"
+	"reflect"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ApplyRulesFromYAML makes the rules of the manifest's frontend or backend
+// exactly the listed ones, in order; the manifest kind selects the
+// http-request or http-response list. Only the rules from the first
+// difference on are rewritten, in one transaction.
+func ApplyRulesFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest RuleManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse rule manifest: %w", err)
+	}
+	rl, err := manifest.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid rule configuration: %w", err)
+	}
+	parentType, parent, _ := manifest.parent()
+
+	if outputFormat != "" || dryRun {
+		if outputFormat == "" {
+			internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		} else {
+			internal.FormatOutput(manifest.Rules, outputFormat)
+		}
+		if dryRun {
+			internal.PrintDryRun()
+		}
+		return nil
+	}
+
+	current, err := rl.live(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+	if len(current) == len(manifest.Rules) && (len(current) == 0 || reflect.DeepEqual(current, manifest.Rules)) {
+		internal.PrintStatus(rl.kind, parent, internal.ActionUnchanged)
+		return nil
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		return rl.replace(tx, parentType, parent, current, manifest.Rules)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(current) == 0 {
+		internal.PrintStatus(rl.kind, parent, internal.ActionCreated)
+	} else {
+		internal.PrintStatus(rl.kind, parent, internal.ActionConfigured)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httprules provides commands to manage the http-request and
+// http-response rules of HAProxy frontends and backends.
+package httprules
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// CreateHTTPRequestRulesCmd represents "create http-request-rules --parent <type>/<name>".
+var CreateHTTPRequestRulesCmd = newCreateCmd(requestRules, `  haproxyctl create http-request-rules --parent frontend/web --type deny --set deny_status=403 --cond if --cond-test "{ src 203.0.113.0/24 }"
+  haproxyctl create http-request-rules --parent frontend/web --type set-header --set hdr_name=X-Forwarded-Proto --set hdr_format=https --index 0
+  haproxyctl create http-request-rules --parent backend/app --type redirect --set redir_type=prefix --set redir_value=https://new.example.com --dry-run`)
+
+// CreateHTTPResponseRulesCmd represents "create http-response-rules --parent <type>/<name>".
+var CreateHTTPResponseRulesCmd = newCreateCmd(responseRules, `  haproxyctl create http-response-rules --parent frontend/web --type set-header --set hdr_name=Strict-Transport-Security --set hdr_format="max-age=31536000"
+  haproxyctl create http-response-rules --parent backend/app --type del-header --set hdr_name=Server --index 0
+  haproxyctl create http-response-rules --parent backend/app --type deny --set deny_status=502 --cond if --cond-test "{ status 500 }" --dry-run`)
+
+func newCreateCmd(rl ruleList, examples string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     rl.resource,
+		Aliases: []string{rl.resource[:len(rl.resource)-1]},
+		Short:   "Add an " + rl.keyword + " rule to a frontend or backend",
+		Long: `Add one ` + rl.keyword + ` rule. --type is the action; its arguments are given
+with --set using the Data Plane API field names. The rule is appended
+unless --index places it at a given position.
+
+Examples:
+` + examples,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			parentType, parent := internal.ParentFromFlag(cmd, parentTypes...)
+			rule := ruleFromFlags(
+				internal.GetFlagString(cmd, "type"),
+				internal.GetFlagString(cmd, "cond"),
+				internal.GetFlagString(cmd, "cond-test"),
+				internal.GetFlagMap(cmd, "set"),
+			)
+			dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+			if err := createRule(rl, parentType, parent, rule, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+				internal.Fatalf("Failed to create %s rule on %s '%s': %v", rl.keyword, parentType, parent, err)
+			}
+		},
+	}
+	internal.AddParentFlag(cmd, "rules", parentTypes...)
+	cmd.Flags().String("type", "", "Rule action, e.g. deny, redirect, set-header, del-header (required)")
+	cmd.Flags().String("cond", "", "Condition keyword: if or unless")
+	cmd.Flags().String("cond-test", "", "Condition, e.g. \"{ path_beg /admin }\" or an ACL name")
+	cmd.Flags().StringToString("set", nil, "Rule field as key=value (repeatable), e.g. deny_status=403")
+	cmd.Flags().Int("index", -1, "Position to insert the rule at (default: append)")
+	cmd.Flags().Bool("dry-run", false, "Print the rule without creating it")
+	return cmd
+}
+
+// ruleFromFlags builds a rule payload. Integer values given with --set
+// (status codes and the like) are sent as numbers.
+func ruleFromFlags(ruleType, cond, condTest string, fields map[string]string) map[string]interface{} {
+	rule := map[string]interface{}{"type": ruleType}
+	for k, v := range fields {
+		if n, err := strconv.Atoi(v); err == nil {
+			rule[k] = n
+		} else {
+			rule[k] = v
+		}
+	}
+	if cond != "" {
+		rule["cond"] = cond
+		rule["cond_test"] = condTest
+	}
+	return rule
+}
+
+// createRule inserts one rule into a frontend or backend at index, or
+// appends it when index is negative.
+func createRule(rl ruleList, parentType, parent string, rule map[string]interface{}, index int, dryRun bool) error {
+	rule, err := normalizeRule(rule)
+	if err != nil {
+		return err
+	}
+	if t, _ := rule["type"].(string); t == "" {
+		return fmt.Errorf("--type is required")
+	}
+	if cond, _ := rule["cond"].(string); cond != "" && cond != "if" && cond != "unless" {
+		return fmt.Errorf("invalid --cond %q (expected if or unless)", cond)
+	}
+	if dryRun {
+		internal.FormatOutput(rule, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := rl.live(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", rl.path(parentType, parent)+"/"+strconv.Itoa(index), params, rule); err != nil {
+		return fmt.Errorf("failed to create %s: %w", rl.summary(rule), err)
+	}
+
+	internal.PrintStatus(rl.kind, fmt.Sprintf("%s/%d", parent, index), internal.ActionCreated)
+	return nil
+}
+
+// CreateRulesFromFile appends the rules of an HTTPRequestRule or
+// HTTPResponseRule manifest to its frontend or backend in one transaction.
+func CreateRulesFromFile(data []byte) error {
+	var manifest RuleManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse rule manifest: %w", err)
+	}
+	rl, err := manifest.Validate()
+	if err != nil {
+		return err
+	}
+	parentType, parent, _ := manifest.parent()
+
+	if internal.IsOffline() {
+		internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := rl.live(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		return rl.replace(tx, parentType, parent, current, slices.Concat(current, manifest.Rules))
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range manifest.Rules {
+		internal.PrintStatus(rl.kind, fmt.Sprintf("%s/%d", parent, len(current)+i), internal.ActionCreated)
+	}
+	return nil
+}
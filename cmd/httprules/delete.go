@@ -0,0 +1,117 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httprules provides commands to manage the http-request and
+// http-response rules of HAProxy frontends and backends.
+package httprules
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// DeleteHTTPRequestRulesCmd represents "delete http-request-rules --parent <type>/<name> --index N".
+var DeleteHTTPRequestRulesCmd = newDeleteCmd(requestRules)
+
+// DeleteHTTPResponseRulesCmd represents "delete http-response-rules --parent <type>/<name> --index N".
+var DeleteHTTPResponseRulesCmd = newDeleteCmd(responseRules)
+
+func newDeleteCmd(rl ruleList) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     rl.resource,
+		Aliases: []string{rl.resource[:len(rl.resource)-1]},
+		Short:   "Delete an " + rl.keyword + " rule from a frontend or backend",
+		Long: fmt.Sprintf(`Delete the %[1]s rule at --index (as shown by "get %[2]s").
+Later rules move up by one position.
+
+Examples:
+  haproxyctl delete %[2]s --parent frontend/web --index 2`, rl.keyword, rl.resource),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			parentType, parent := internal.ParentFromFlag(cmd, parentTypes...)
+			index := internal.GetFlagInt(cmd, "index")
+			if index < 0 {
+				internal.FatalCodef(internal.ExitUsage, "--index is required")
+			}
+			if err := deleteRule(rl, parentType, parent, index); err != nil {
+				internal.Fatalf("Failed to delete %s rule %d of %s '%s': %v", rl.keyword, index, parentType, parent, err)
+			}
+		},
+	}
+	internal.AddParentFlag(cmd, "rules", parentTypes...)
+	cmd.Flags().Int("index", -1, "Position of the rule to delete (required)")
+	return cmd
+}
+
+// deleteRule removes the rule at index from a frontend or backend.
+func deleteRule(rl ruleList, parentType, parent string, index int) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%d", parent, index)
+	if _, err := internal.SendRequest("DELETE", rl.path(parentType, parent)+"/"+strconv.Itoa(index), params, nil); err != nil {
+		return internal.FormatAPIError(rl.kind, id, "delete", err)
+	}
+
+	internal.PrintStatus(rl.kind, id, internal.ActionDeleted)
+	return nil
+}
+
+// DeleteRulesFromFile removes the rules listed in an HTTPRequestRule or
+// HTTPResponseRule manifest from its frontend or backend in one
+// transaction; other rules are kept.
+func DeleteRulesFromFile(data []byte) error {
+	var manifest RuleManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse rule manifest: %w", err)
+	}
+	rl, err := manifest.Validate()
+	if err != nil {
+		return err
+	}
+	parentType, parent, _ := manifest.parent()
+
+	current, err := rl.live(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+	desired := slices.DeleteFunc(slices.Clone(current), func(live map[string]interface{}) bool {
+		return slices.ContainsFunc(manifest.Rules, func(listed map[string]interface{}) bool {
+			return reflect.DeepEqual(live, listed)
+		})
+	})
+	if len(desired) == len(current) {
+		return fmt.Errorf("%s %q has none of the listed %s rules", parentType, parent, rl.keyword)
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		return rl.replace(tx, parentType, parent, current, desired)
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus(rl.kind, parent, internal.ActionDeleted)
+	return nil
+}
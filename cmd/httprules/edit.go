@@ -0,0 +1,123 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httprules provides commands to manage the http-request and
+// http-response rules of HAProxy frontends and backends.
+package httprules
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// EditHTTPRequestRulesCmd represents "edit http-request-rules --parent <type>/<name>".
+var EditHTTPRequestRulesCmd = newEditCmd(requestRules)
+
+// EditHTTPResponseRulesCmd represents "edit http-response-rules --parent <type>/<name>".
+var EditHTTPResponseRulesCmd = newEditCmd(responseRules)
+
+func newEditCmd(rl ruleList) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     rl.resource,
+		Aliases: []string{rl.resource[:len(rl.resource)-1]},
+		Short:   "Edit the " + rl.keyword + " rules of a frontend or backend in your editor",
+		Long: fmt.Sprintf(`Open the %[1]s rules of a frontend or backend as a manifest in
+$EDITOR. Rules can be added, changed, removed or reordered; the result
+replaces the section's rules in one transaction.
+
+Examples:
+  haproxyctl edit %[2]s --parent frontend/web`, rl.keyword, rl.resource),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			parentType, parent := internal.ParentFromFlag(cmd, parentTypes...)
+			if err := editRules(rl, parentType, parent); err != nil {
+				internal.Fatalf("Edit failed: %v", err)
+			}
+		},
+	}
+	internal.AddParentFlag(cmd, "rules", parentTypes...)
+	return cmd
+}
+
+func editRules(rl ruleList, parentType, parent string) error {
+	current, err := rl.live(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+	manifest := rl.manifest(parentType, parent, current)
+
+	origYAML, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule manifest to YAML: %w", err)
+	}
+
+	tmpFile, err := internal.WriteTempYAML("haproxyctl-"+rl.resource+"-"+parent+"-", manifest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rmErr := os.Remove(tmpFile); rmErr != nil {
+			internal.Warnf("failed to remove temp file %q: %v", tmpFile, rmErr)
+		}
+	}()
+
+	if err := internal.OpenInEditor(tmpFile); err != nil {
+		return err
+	}
+
+	// tmpFile is created via os.CreateTemp in WriteTempYAML and lives in
+	// the system temp directory, so this read is safe.
+	editedYAML, err := os.ReadFile(tmpFile) //nolint:gosec // tmpFile is controlled by this process
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	if bytes.Equal(bytes.TrimSpace(origYAML), bytes.TrimSpace(editedYAML)) {
+		internal.PrintStatus(rl.kind, parent, internal.ActionUnchanged)
+		return nil
+	}
+
+	var edited RuleManifest
+	if err := yaml.Unmarshal(editedYAML, &edited); err != nil {
+		return fmt.Errorf("failed to parse edited YAML: %w", err)
+	}
+	editedList, err := edited.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid rule manifest: %w", err)
+	}
+	if editedList != rl {
+		return fmt.Errorf("cannot change kind via edit (got %q, expected %q)", edited.Kind, rl.kind)
+	}
+	if t, name, _ := edited.parent(); t != parentType || name != parent {
+		return fmt.Errorf("cannot move rules to another section via edit (got %s %q, expected %s %q)", t, name, parentType, parent)
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		return rl.replace(tx, parentType, parent, current, edited.Rules)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update %s rules of %s %q: %w", rl.keyword, parentType, parent, err)
+	}
+
+	internal.PrintStatus(rl.kind, parent, internal.ActionConfigured)
+	return nil
+}
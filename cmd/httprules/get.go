@@ -0,0 +1,69 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httprules provides commands to manage the http-request and
+// http-response rules of HAProxy frontends and backends.
+package httprules
+
+import (
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetHTTPRequestRulesCmd represents "get http-request-rules --parent <type>/<name>".
+var GetHTTPRequestRulesCmd = newGetCmd(requestRules)
+
+// GetHTTPResponseRulesCmd represents "get http-response-rules --parent <type>/<name>".
+var GetHTTPResponseRulesCmd = newGetCmd(responseRules)
+
+func newGetCmd(rl ruleList) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     rl.resource,
+		Aliases: []string{rl.resource[:len(rl.resource)-1]},
+		Short:   "List the " + rl.keyword + " rules of a frontend or backend",
+		Long: fmt.Sprintf(`List the %[1]s rules of a frontend or backend in evaluation
+order. The index column is the position used by "delete %[2]s"
+and "move %[2]s".
+
+Examples:
+  haproxyctl get %[2]s --parent frontend/web
+  haproxyctl get %[2]s --parent backend/app -o yaml`, rl.keyword, rl.resource),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			parentType, parent := internal.ParentFromFlag(cmd, parentTypes...)
+			rules, err := internal.GetResourceList(rl.path(parentType, parent))
+			if err != nil {
+				if internal.IsNotFoundError(err) {
+					internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(displayParent(parentType), parent))
+				}
+				internal.Fatalf("Failed to fetch %s rules of %s '%s': %v", rl.keyword, parentType, parent, err)
+			}
+			internal.SortForCmd(cmd, rules)
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(rules, ruleColumns), internal.GetFlagString(cmd, "output"))
+		},
+	}
+	internal.AddParentFlag(cmd, "rules", parentTypes...)
+	return cmd
+}
+
+// ruleColumns shows the position, action and condition of each rule.
+var ruleColumns = internal.ColumnSet{
+	Default: []string{"index", "type", "cond", "cond_test"},
+	Wide:    []string{"redir_type", "redir_value", "hdr_name", "hdr_format", "deny_status", "status"},
+}
@@ -0,0 +1,146 @@
+package httprules
+
+import (
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func ruleTypes(items []map[string]interface{}) []string {
+	types := make([]string, 0, len(items))
+	for _, item := range items {
+		t, _ := item["type"].(string)
+		types = append(types, t)
+	}
+	return types
+}
+
+func TestApplyRulesFromYAML(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddListItem("frontends", "web", "http_request_rules", map[string]interface{}{"type": "deny", "deny_status": 403, "cond": "if", "cond_test": "is_blocked"})
+	srv.AddListItem("frontends", "web", "http_request_rules", map[string]interface{}{"type": "del-header", "hdr_name": "X-Debug"})
+
+	manifest := []byte(`apiVersion: haproxyctl/v1
+kind: HTTPRequestRule
+frontend: web
+rules:
+  - type: deny
+    deny_status: 403
+    cond: if
+    cond_test: is_blocked
+  - type: set-header
+    hdr_name: X-Forwarded-Proto
+    hdr_format: https
+`)
+	internal.CaptureStdout(t, func() {
+		if err := ApplyRulesFromYAML(manifest, "", false); err != nil {
+			t.Fatalf("ApplyRulesFromYAML failed: %v", err)
+		}
+	})
+	got := srv.List("frontends", "web", "http_request_rules")
+	if types := ruleTypes(got); len(types) != 2 || types[0] != "deny" || types[1] != "set-header" {
+		t.Fatalf("unexpected rules after apply: %v", types)
+	}
+	if n := srv.CountRequests("DELETE", "/v3/services/haproxy/configuration/frontends/web/http_request_rules/0"); n != 0 {
+		t.Fatalf("unchanged first rule was rewritten")
+	}
+
+	// The deny_status given as a YAML int matches the float the API returns.
+	version := srv.Version()
+	out := internal.CaptureStdout(t, func() {
+		if err := ApplyRulesFromYAML(manifest, "", false); err != nil {
+			t.Fatalf("second ApplyRulesFromYAML failed: %v", err)
+		}
+	})
+	if srv.Version() != version || out != "httprequestrule/web unchanged\n" {
+		t.Fatalf("expected unchanged apply, got %q (version %d -> %d)", out, version, srv.Version())
+	}
+}
+
+func TestCreateAndDeleteRule(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "app", "mode": "http"})
+	srv.AddListItem("backends", "app", "http_request_rules", map[string]interface{}{"type": "del-header", "hdr_name": "X-Debug"})
+
+	rule := ruleFromFlags("deny", "if", "{ src 203.0.113.0/24 }", map[string]string{"deny_status": "403"})
+	if rule["deny_status"] != 403 {
+		t.Fatalf("deny_status not converted to a number: %#v", rule["deny_status"])
+	}
+
+	internal.CaptureStdout(t, func() {
+		if err := createRule(requestRules, parentBackend, "app", rule, 0, false); err != nil {
+			t.Fatalf("createRule failed: %v", err)
+		}
+	})
+	got := srv.List("backends", "app", "http_request_rules")
+	if types := ruleTypes(got); len(types) != 2 || types[0] != "deny" {
+		t.Fatalf("unexpected rules after create: %v", types)
+	}
+
+	internal.CaptureStdout(t, func() {
+		if err := deleteRule(requestRules, parentBackend, "app", 1); err != nil {
+			t.Fatalf("deleteRule failed: %v", err)
+		}
+	})
+	if types := ruleTypes(srv.List("backends", "app", "http_request_rules")); len(types) != 1 || types[0] != "deny" {
+		t.Fatalf("unexpected rules after delete: %v", types)
+	}
+
+	if err := deleteRule(requestRules, parentBackend, "app", 5); err == nil {
+		t.Fatalf("expected error for an index out of range")
+	}
+	if err := createRule(requestRules, parentBackend, "app", ruleFromFlags("deny", "when", "x", nil), -1, false); err == nil {
+		t.Fatalf("expected error for an invalid condition keyword")
+	}
+}
+
+func TestResponseRules_ApplyAndMove(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "mode": "http"})
+	srv.AddListItem("frontends", "web", "http_request_rules", map[string]interface{}{"type": "del-header", "hdr_name": "X-Debug"})
+
+	manifest := []byte(`apiVersion: haproxyctl/v1
+kind: HTTPResponseRule
+frontend: web
+rules:
+  - type: del-header
+    hdr_name: Server
+  - type: set-header
+    hdr_name: X-Frame-Options
+    hdr_format: DENY
+  - type: set-header
+    hdr_name: Strict-Transport-Security
+    hdr_format: max-age=31536000
+`)
+	internal.CaptureStdout(t, func() {
+		if err := ApplyRulesFromYAML(manifest, "", false); err != nil {
+			t.Fatalf("ApplyRulesFromYAML failed: %v", err)
+		}
+	})
+	if got := srv.List("frontends", "web", "http_response_rules"); len(got) != 3 {
+		t.Fatalf("expected 3 response rules, got %d", len(got))
+	}
+	if got := srv.List("frontends", "web", "http_request_rules"); len(got) != 1 {
+		t.Fatalf("request rules changed: %v", got)
+	}
+
+	internal.CaptureStdout(t, func() {
+		if err := moveRule(responseRules, parentFrontend, "web", 2, 0); err != nil {
+			t.Fatalf("moveRule failed: %v", err)
+		}
+	})
+	var headers []string
+	for _, rule := range srv.List("frontends", "web", "http_response_rules") {
+		name, _ := rule["hdr_name"].(string)
+		headers = append(headers, name)
+	}
+	if len(headers) != 3 || headers[0] != "Strict-Transport-Security" || headers[1] != "Server" || headers[2] != "X-Frame-Options" {
+		t.Fatalf("unexpected order after move: %v", headers)
+	}
+
+	if err := moveRule(responseRules, parentFrontend, "web", 0, 3); err == nil {
+		t.Fatalf("expected error for a position out of range")
+	}
+}
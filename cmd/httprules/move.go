@@ -0,0 +1,95 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httprules provides commands to manage the http-request and
+// http-response rules of HAProxy frontends and backends.
+package httprules
+
+import (
+	"fmt"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// MoveHTTPRequestRulesCmd represents "move http-request-rules --parent <type>/<name> --index N --to M".
+var MoveHTTPRequestRulesCmd = newMoveCmd(requestRules)
+
+// MoveHTTPResponseRulesCmd represents "move http-response-rules --parent <type>/<name> --index N --to M".
+var MoveHTTPResponseRulesCmd = newMoveCmd(responseRules)
+
+func newMoveCmd(rl ruleList) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     rl.resource,
+		Aliases: []string{rl.resource[:len(rl.resource)-1]},
+		Short:   "Move an " + rl.keyword + " rule to another position",
+		Long: fmt.Sprintf(`Move the %[1]s rule at --index to position --to, shifting the
+rules in between by one. The rule is removed and re-inserted in one
+transaction, so it is never missing from the running configuration.
+
+Examples:
+  haproxyctl move %[2]s --parent frontend/web --index 3 --to 0`, rl.keyword, rl.resource),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			parentType, parent := internal.ParentFromFlag(cmd, parentTypes...)
+			from, to := internal.GetFlagInt(cmd, "index"), internal.GetFlagInt(cmd, "to")
+			if from < 0 || to < 0 {
+				internal.FatalCodef(internal.ExitUsage, "--index and --to are required")
+			}
+			if err := moveRule(rl, parentType, parent, from, to); err != nil {
+				internal.Fatalf("Failed to move %s rule %d of %s '%s': %v", rl.keyword, from, parentType, parent, err)
+			}
+		},
+	}
+	internal.AddParentFlag(cmd, "rules", parentTypes...)
+	cmd.Flags().Int("index", -1, "Current position of the rule (required)")
+	cmd.Flags().Int("to", -1, "New position of the rule (required)")
+	return cmd
+}
+
+// moveRule moves the rule at from to position to.
+func moveRule(rl ruleList, parentType, parent string, from, to int) error {
+	current, err := rl.live(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(displayParent(parentType), parent, "get", err)
+	}
+	if from >= len(current) || to >= len(current) {
+		return fmt.Errorf("%s %q has %d %s rules (positions 0-%d)", parentType, parent, len(current), rl.keyword, len(current)-1)
+	}
+	if from == to {
+		internal.PrintStatus(rl.kind, fmt.Sprintf("%s/%d", parent, from), internal.ActionUnchanged)
+		return nil
+	}
+
+	path := rl.path(parentType, parent)
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		if _, err := internal.SendRequest("DELETE", path+"/"+strconv.Itoa(from), tx.Params(), nil); err != nil {
+			return fmt.Errorf("failed to remove rule %d: %w", from, err)
+		}
+		if _, err := internal.SendRequest("POST", path+"/"+strconv.Itoa(to), tx.Params(), current[from]); err != nil {
+			return fmt.Errorf("failed to insert rule at %d: %w", to, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus(rl.kind, fmt.Sprintf("%s/%d", parent, from), fmt.Sprintf("moved to %d", to))
+	return nil
+}
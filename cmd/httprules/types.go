@@ -0,0 +1,211 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httprules provides commands to manage the http-request and
+// http-response rules of HAProxy frontends and backends.
+package httprules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	apiVersionV1 = "haproxyctl/v1"
+
+	parentFrontend = internal.ParentFrontend
+	parentBackend  = internal.ParentBackend
+)
+
+// parentTypes are the sections that own HTTP rules.
+var parentTypes = []string{parentFrontend, parentBackend}
+
+// ruleList describes one of the HTTP rule lists of a frontend or backend.
+type ruleList struct {
+	// kind is the manifest kind, also used in status messages.
+	kind string
+	// list is the Data Plane API list name.
+	list string
+	// keyword is the haproxy.cfg directive.
+	keyword string
+	// resource is the CLI resource name.
+	resource string
+}
+
+var (
+	requestRules  = ruleList{kind: "HTTPRequestRule", list: "http_request_rules", keyword: "http-request", resource: "http-request-rules"}
+	responseRules = ruleList{kind: "HTTPResponseRule", list: "http_response_rules", keyword: "http-response", resource: "http-response-rules"}
+)
+
+// ruleListForKind returns the rule list a manifest kind describes.
+func ruleListForKind(kind string) (ruleList, error) {
+	for _, rl := range []ruleList{requestRules, responseRules} {
+		if strings.EqualFold(kind, rl.kind) {
+			return rl, nil
+		}
+	}
+	return ruleList{}, fmt.Errorf("invalid kind %q, expected %q or %q", kind, requestRules.kind, responseRules.kind)
+}
+
+// RuleManifest describes every rule of one HTTP rule list of a frontend or
+// backend, in evaluation order. Kind selects the list (HTTPRequestRule or
+// HTTPResponseRule). Rules use the Data Plane API field names (type, cond,
+// cond_test, redir_type, hdr_name, deny_status, ...). Exactly one of
+// Frontend and Backend is set.
+type RuleManifest struct {
+	APIVersion string                   `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string                   `json:"kind" yaml:"kind"`
+	Frontend   string                   `json:"frontend,omitempty" yaml:"frontend,omitempty"`
+	Backend    string                   `json:"backend,omitempty" yaml:"backend,omitempty"`
+	Rules      []map[string]interface{} `json:"rules" yaml:"rules"`
+}
+
+// displayParent returns the parent type as shown by PrintStatus ("Frontend").
+func displayParent(parentType string) string {
+	return strings.ToUpper(parentType[:1]) + parentType[1:]
+}
+
+// path returns the endpoint of the rule list of a parent.
+func (rl ruleList) path(parentType, parent string) string {
+	return fmt.Sprintf("/services/haproxy/configuration/%ss/%s/%s", parentType, parent, rl.list)
+}
+
+// parent returns the parent type and name the manifest refers to.
+func (m *RuleManifest) parent() (string, string, error) {
+	switch {
+	case m.Frontend != "" && m.Backend != "":
+		return "", "", errors.New("rule manifest must set only one of frontend and backend")
+	case m.Frontend != "":
+		return parentFrontend, m.Frontend, nil
+	case m.Backend != "":
+		return parentBackend, m.Backend, nil
+	default:
+		return "", "", errors.New("rule manifest must set frontend or backend")
+	}
+}
+
+// Validate checks the manifest header, the parent and that every rule has
+// a type, normalises the rules to their JSON form and returns the rule
+// list the manifest describes.
+func (m *RuleManifest) Validate() (ruleList, error) {
+	if m.APIVersion != "" && m.APIVersion != apiVersionV1 {
+		return ruleList{}, fmt.Errorf("unsupported apiVersion %q (expected %s)", m.APIVersion, apiVersionV1)
+	}
+	rl, err := ruleListForKind(m.Kind)
+	if err != nil {
+		return ruleList{}, err
+	}
+	if _, _, err := m.parent(); err != nil {
+		return ruleList{}, err
+	}
+	for i, rule := range m.Rules {
+		normalized, err := normalizeRule(rule)
+		if err != nil {
+			return ruleList{}, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		if t, _ := normalized["type"].(string); t == "" {
+			return ruleList{}, fmt.Errorf("rules[%d]: rule is missing type", i)
+		}
+		m.Rules[i] = normalized
+	}
+	return rl, nil
+}
+
+// normalizeRule converts a rule read from YAML or JSON into the form the
+// API returns it in (string keys, float64 numbers, no index), so live and
+// desired rules compare equal when they mean the same thing.
+func normalizeRule(rule map[string]interface{}) (map[string]interface{}, error) {
+	asMap, err := internal.ManifestAsMap(rule)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(asMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rule: %w", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode rule: %w", err)
+	}
+	delete(out, "index")
+	return out, nil
+}
+
+// summary renders a rule roughly as it reads in haproxy.cfg, for messages.
+func (rl ruleList) summary(rule map[string]interface{}) string {
+	parts := []string{rl.keyword, fmt.Sprint(rule["type"])}
+	if cond, _ := rule["cond"].(string); cond != "" {
+		parts = append(parts, cond, fmt.Sprint(rule["cond_test"]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// live returns the normalised rules of a parent in order.
+func (rl ruleList) live(parentType, parent string) ([]map[string]interface{}, error) {
+	list, err := internal.GetResourceList(rl.path(parentType, parent))
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]map[string]interface{}, 0, len(list))
+	for _, obj := range list {
+		rule, err := normalizeRule(obj)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// manifest builds the manifest describing the rules of a parent.
+func (rl ruleList) manifest(parentType, parent string, rules []map[string]interface{}) RuleManifest {
+	m := RuleManifest{APIVersion: apiVersionV1, Kind: rl.kind, Rules: rules}
+	if parentType == parentFrontend {
+		m.Frontend = parent
+	} else {
+		m.Backend = parent
+	}
+	return m
+}
+
+// replace turns the rule list of a parent from current into desired inside
+// tx. Rules are identified only by position, so every rule from the first
+// difference on is removed and re-created in order.
+func (rl ruleList) replace(tx *internal.Transaction, parentType, parent string, current, desired []map[string]interface{}) error {
+	common := 0
+	for common < len(current) && common < len(desired) && reflect.DeepEqual(current[common], desired[common]) {
+		common++
+	}
+
+	path := rl.path(parentType, parent)
+	for i := len(current) - 1; i >= common; i-- {
+		if _, err := internal.SendRequest("DELETE", path+"/"+strconv.Itoa(i), tx.Params(), nil); err != nil {
+			return fmt.Errorf("failed to delete rule %d (%s): %w", i, rl.summary(current[i]), err)
+		}
+	}
+	for i := common; i < len(desired); i++ {
+		if _, err := internal.SendRequest("POST", path+"/"+strconv.Itoa(i), tx.Params(), desired[i]); err != nil {
+			return fmt.Errorf("failed to create rule %d (%s): %w", i, rl.summary(desired[i]), err)
+		}
+	}
+	return nil
+}
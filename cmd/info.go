@@ -0,0 +1,196 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// infoCmd represents the "info" command.
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the Data Plane API and HAProxy versions and check the connection",
+	Long: `Show which Data Plane API haproxyctl talks to, its version, the HAProxy
+version and uptime behind it and whether HAProxy reports itself healthy.
+Useful as a smoke test after "haproxyctl login". The health and HAProxy
+details are left out when the Data Plane API does not provide them.
+
+Exits with status 6 when the Data Plane API cannot be reached.
+
+Examples:
+  haproxyctl info
+  haproxyctl info --context prod -o yaml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		info := fetchAPIInfo(cmd.Context())
+
+		outputFormat := internal.GetFlagString(cmd, "output")
+		if outputFormat != "" {
+			internal.FormatOutput(info, outputFormat)
+		} else if err := printAPIInfo(info); err != nil {
+			internal.Fatalf("Failed to print info: %v", err)
+		}
+		if !info.Reachable {
+			os.Exit(internal.ExitUnreachable)
+		}
+	},
+}
+
+// apiInfo is what "haproxyctl info" reports about an endpoint.
+//
+//nolint:tagliatelle // snake_case like the rest of the CLI output
+type apiInfo struct {
+	Context        string `json:"context" yaml:"context"`
+	APIURL         string `json:"api_url" yaml:"api_url"`
+	Reachable      bool   `json:"reachable" yaml:"reachable"`
+	Error          string `json:"error,omitempty" yaml:"error,omitempty"`
+	APIVersion     string `json:"api_version,omitempty" yaml:"api_version,omitempty"`
+	APIBuildDate   string `json:"api_build_date,omitempty" yaml:"api_build_date,omitempty"`
+	Hostname       string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	HAProxyVersion string `json:"haproxy_version,omitempty" yaml:"haproxy_version,omitempty"`
+	HAProxyUptime  string `json:"haproxy_uptime,omitempty" yaml:"haproxy_uptime,omitempty"`
+	HAProxyHealth  string `json:"haproxy_health,omitempty" yaml:"haproxy_health,omitempty"`
+}
+
+// fetchAPIInfo queries /info, then /health and the runtime process info
+// when the API offers them. Only a failing /info marks the endpoint as
+// unreachable.
+func fetchAPIInfo(ctx context.Context) apiInfo {
+	var info apiInfo
+	if cfgFile, err := internal.LoadConfigFile(); err == nil {
+		info.Context = contextLabel(cfgFile.SelectedContext())
+	}
+	cfg, err := internal.LoadConfig()
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.APIURL = cfg.APIBaseURL
+
+	var apiResp struct {
+		API struct {
+			Version   string `json:"version"`
+			BuildDate string `json:"build_date"` //nolint:tagliatelle // Data Plane API field name
+		} `json:"api"`
+		System struct {
+			Hostname string `json:"hostname"`
+		} `json:"system"`
+	}
+	data, err := internal.SendRequestWithContext(ctx, "GET", "/info", nil, nil)
+	if err == nil {
+		err = json.Unmarshal(data, &apiResp)
+	}
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Reachable = true
+	info.APIVersion, info.APIBuildDate = apiResp.API.Version, apiResp.API.BuildDate
+	info.Hostname = apiResp.System.Hostname
+
+	var health struct {
+		HAProxy string `json:"haproxy"`
+	}
+	if data, err := internal.SendRequestWithContext(ctx, "GET", "/health", nil, nil); err == nil && json.Unmarshal(data, &health) == nil {
+		info.HAProxyHealth = health.HAProxy
+	}
+
+	if data, err := internal.SendRequestWithContext(ctx, "GET", "/services/haproxy/runtime/info", nil, nil); err == nil {
+		if process, ok := parseProcessInfo(data); ok {
+			info.HAProxyVersion = process.Version
+			if process.Uptime > 0 {
+				info.HAProxyUptime = (time.Duration(process.Uptime) * time.Second).String()
+			}
+		}
+	}
+	return info
+}
+
+// processInfo is the part of the runtime info endpoint "info" reports.
+type processInfo struct {
+	Version string `json:"version"`
+	Uptime  int    `json:"uptime"`
+}
+
+// parseProcessInfo reads the runtime info endpoint, which returns one
+// process in v3 and a list of them in older Data Plane APIs.
+func parseProcessInfo(data []byte) (processInfo, bool) {
+	type entry struct {
+		Info processInfo `json:"info"`
+	}
+	var single entry
+	if err := json.Unmarshal(data, &single); err == nil && single.Info.Version != "" {
+		return single.Info, true
+	}
+	var list []entry
+	if err := json.Unmarshal(data, &list); err == nil && len(list) > 0 {
+		return list[0].Info, true
+	}
+	return processInfo{}, false
+}
+
+// printAPIInfo prints info as aligned "field: value" lines, skipping what
+// the API did not report.
+func printAPIInfo(info apiInfo) error {
+	const (
+		tabWidth   = 8
+		tabPadding = 2
+	)
+
+	reachable := "yes"
+	if !info.Reachable {
+		reachable = "no"
+	}
+	rows := [][2]string{
+		{"Context", info.Context},
+		{"API URL", info.APIURL},
+		{"Reachable", reachable},
+		{"Error", info.Error},
+		{"API version", info.APIVersion},
+		{"API build date", info.APIBuildDate},
+		{"Hostname", info.Hostname},
+		{"HAProxy version", info.HAProxyVersion},
+		{"HAProxy uptime", info.HAProxyUptime},
+		{"HAProxy health", info.HAProxyHealth},
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, tabWidth, tabPadding, ' ', 0)
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s:\t%s\n", row[0], row[1]); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+	infoCmd.Flags().StringP("output", "o", "", "Output format: yaml or json (default: text)")
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+	"haproxyctl/pkg/client/clienttest"
+)
+
+func TestFetchAPIInfo(t *testing.T) {
+	srv := testserver.New(t)
+
+	info := fetchAPIInfo(context.Background())
+	if !info.Reachable || info.Error != "" {
+		t.Fatalf("expected a reachable API, got %+v", info)
+	}
+	if info.APIURL != srv.URL || info.APIVersion != clienttest.APIVersion || info.HAProxyVersion != clienttest.HAProxyVersion {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+	if info.HAProxyUptime != "1h0m0s" || info.HAProxyHealth != "up" {
+		t.Fatalf("uptime/health = %q/%q", info.HAProxyUptime, info.HAProxyHealth)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := printAPIInfo(info); err != nil {
+			t.Fatal(err)
+		}
+	})
+	for _, want := range []string{"Reachable:", "yes", "HAProxy version:", clienttest.HAProxyVersion} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestFetchAPIInfo_Unreachable(t *testing.T) {
+	srv := testserver.New(t)
+	srv.Close()
+
+	info := fetchAPIInfo(context.Background())
+	if info.Reachable || info.Error == "" {
+		t.Fatalf("expected an unreachable API with an error, got %+v", info)
+	}
+}
+
+func TestParseProcessInfo_List(t *testing.T) {
+	t.Parallel()
+
+	process, ok := parseProcessInfo([]byte(`[{"info":{"version":"2.8.3","uptime":60}}]`))
+	if !ok || process.Version != "2.8.3" || process.Uptime != 60 {
+		t.Fatalf("parseProcessInfo = %+v, %v", process, ok)
+	}
+	if _, ok := parseProcessInfo([]byte(`{}`)); ok {
+		t.Fatal("expected no process info in an empty object")
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logforwards
+
+import (
+	"fmt"
+	"reflect"
+
+	"haproxyctl/cmd/logtargets"
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ApplyLogForwardFromYAML applies a LogForward manifest in a declarative
+// way: the section is created when missing, otherwise replaced in place;
+// its binds are reconciled by name and its log targets by position.
+// Everything happens in one transaction.
+func ApplyLogForwardFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest LogForwardManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse log-forward manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid log-forward configuration: %w", err)
+	}
+	manifest.normalize()
+
+	if outputFormat != "" || dryRun {
+		if outputFormat == "" {
+			internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		} else {
+			internal.FormatOutput(manifest.toAPIPayload(), outputFormat)
+		}
+		if dryRun {
+			internal.PrintDryRun()
+		}
+		return nil
+	}
+
+	name := manifest.Name
+	current, err := getLogForwardManifest(name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return fmt.Errorf("failed to check log-forward existence: %w", err)
+	}
+	exists := err == nil
+
+	if exists && reflect.DeepEqual(*current, manifest) {
+		internal.PrintStatus(logForwardKind, name, internal.ActionUnchanged)
+		return nil
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		before := &LogForwardManifest{}
+		if exists {
+			if _, err := internal.SendRequest("PUT", logForwardPath(name), tx.Params(), manifest.toAPIPayload()); err != nil {
+				return fmt.Errorf("failed to update log-forward %q: %w", name, err)
+			}
+			before = current
+		} else if _, err := internal.SendRequest("POST", logForwardsPath, tx.Params(), manifest.toAPIPayload()); err != nil {
+			return fmt.Errorf("failed to create log-forward %q: %w", name, err)
+		}
+		return syncChildren(tx, name, before, &manifest)
+	})
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		internal.PrintStatus(logForwardKind, name, internal.ActionConfigured)
+	} else {
+		internal.PrintStatus(logForwardKind, name, internal.ActionCreated)
+	}
+	return nil
+}
+
+// syncChildren turns the binds, dgram binds and log targets of a
+// log-forward section from before into after, staging the changes in tx.
+func syncChildren(tx *internal.Transaction, name string, before, after *LogForwardManifest) error {
+	if err := syncBinds(tx, bindsPath(name), before.Binds, after.Binds); err != nil {
+		return err
+	}
+	if err := syncBinds(tx, dgramBindsPath(name), before.DgramBinds, after.DgramBinds); err != nil {
+		return err
+	}
+	if err := logtargets.ApplyDiff(tx, logtargets.ParentLogForward, name, before.LogTargets, after.LogTargets); err != nil {
+		return fmt.Errorf("failed to apply log target changes: %w", err)
+	}
+	return nil
+}
+
+// syncBinds turns the binds under path from before into after, matching
+// them by name. Both lists must be normalized.
+func syncBinds(tx *internal.Transaction, path string, before, after []Bind) error {
+	live := make(map[string]Bind, len(before))
+	for _, b := range before {
+		live[b.Name] = b
+	}
+	wanted := make(map[string]bool, len(after))
+
+	for _, b := range after {
+		wanted[b.Name] = true
+		old, ok := live[b.Name]
+		switch {
+		case !ok:
+			if _, err := internal.SendRequest("POST", path, tx.Params(), b.toPayload()); err != nil {
+				return fmt.Errorf("failed to create bind %s: %w", b.String(), err)
+			}
+		case old != b:
+			if _, err := internal.SendRequest("PUT", path+"/"+b.Name, tx.Params(), b.toPayload()); err != nil {
+				return fmt.Errorf("failed to update bind %s: %w", b.String(), err)
+			}
+		}
+	}
+	for _, b := range before {
+		if wanted[b.Name] {
+			continue
+		}
+		if _, err := internal.SendRequest("DELETE", path+"/"+b.Name, tx.Params(), nil); err != nil {
+			return fmt.Errorf("failed to delete bind %s: %w", b.String(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logforwards provides commands to manage HAProxy log-forward
+// sections, which receive syslog messages on their binds and relay them to
+// their log targets.
+package logforwards
+
+import (
+	"fmt"
+
+	"haproxyctl/cmd/logtargets"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// CreateLogForwardsCmd represents "create log-forwards <name>".
+var CreateLogForwardsCmd = &cobra.Command{
+	Use:     "log-forwards <name>",
+	Aliases: []string{"log-forward"},
+	Short:   "Create a log-forward section",
+	Long: `Create a log-forward section, with its binds and log targets, in one
+transaction. Messages received on --bind (TCP) and --dgram-bind (UDP) are
+relayed to every --log target. Log targets can be added or removed later
+with "haproxyctl create|delete log-targets --parent log-forward/<name>".
+
+Examples:
+  haproxyctl create log-forwards syslog \
+    --dgram-bind address=0.0.0.0,port=514 \
+    --bind address=0.0.0.0,port=601 \
+    --log address=10.0.0.7:514,facility=local0
+  haproxyctl create log-forwards relay --dgram-bind address=127.0.0.1,port=1514 \
+    --log address=ring@logbuf,facility=local0 --maxconn 100
+  haproxyctl create -f log-forward.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifest := LogForwardManifest{
+			APIVersion:       apiVersionV1,
+			Kind:             logForwardKind,
+			Name:             args[0],
+			Backlog:          internal.GetFlagInt(cmd, "backlog"),
+			Maxconn:          internal.GetFlagInt(cmd, "maxconn"),
+			TimeoutClient:    internal.GetFlagString(cmd, "timeout-client"),
+			AssumeRFC6587NTF: internal.GetFlagBool(cmd, "assume-rfc6587-ntf"),
+			DontParseLog:     internal.GetFlagBool(cmd, "dont-parse-log"),
+		}
+		for _, raw := range internal.GetFlagStringSlice(cmd, "bind") {
+			b, err := parseBindFlag(raw)
+			if err != nil {
+				internal.FatalCodef(internal.ExitUsage, "Invalid --bind: %v", err)
+			}
+			manifest.Binds = append(manifest.Binds, b)
+		}
+		for _, raw := range internal.GetFlagStringSlice(cmd, "dgram-bind") {
+			b, err := parseBindFlag(raw)
+			if err != nil {
+				internal.FatalCodef(internal.ExitUsage, "Invalid --dgram-bind: %v", err)
+			}
+			manifest.DgramBinds = append(manifest.DgramBinds, b)
+		}
+		for _, raw := range internal.GetFlagStringSlice(cmd, "log") {
+			t, err := logtargets.Parse(raw)
+			if err != nil {
+				internal.FatalCodef(internal.ExitUsage, "Invalid --log: %v", err)
+			}
+			manifest.LogTargets = append(manifest.LogTargets, t)
+		}
+
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := createLogForward(manifest, dryRun); err != nil {
+			internal.Fatalf("Failed to create log-forward %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateLogForwardsCmd.Flags().StringArray("bind", nil, "TCP bind as address=0.0.0.0,port=601[,name=...] (repeatable)")
+	CreateLogForwardsCmd.Flags().StringArray("dgram-bind", nil, "UDP bind as address=0.0.0.0,port=514[,name=...] (repeatable)")
+	CreateLogForwardsCmd.Flags().StringArray("log", nil, "Log target as address=10.0.0.7:514,facility=local0[,level=info][,format=rfc5424] (repeatable)")
+	CreateLogForwardsCmd.Flags().Int("maxconn", 0, "Maximum concurrent TCP connections")
+	CreateLogForwardsCmd.Flags().Int("backlog", 0, "Listen backlog of the TCP binds")
+	CreateLogForwardsCmd.Flags().String("timeout-client", "", "Idle timeout of TCP clients (e.g., 30s)")
+	CreateLogForwardsCmd.Flags().Bool("assume-rfc6587-ntf", false, "Treat TCP input as newline-framed (RFC 6587 non-transparent framing)")
+	CreateLogForwardsCmd.Flags().Bool("dont-parse-log", false, "Relay messages as-is without parsing them")
+	CreateLogForwardsCmd.Flags().Bool("dry-run", false, "Print the manifest without creating it")
+}
+
+// CreateLogForwardFromFile is used for "haproxyctl create -f log-forward.yaml".
+func CreateLogForwardFromFile(data []byte) error {
+	var manifest LogForwardManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse log-forward manifest: %w", err)
+	}
+	if err := createLogForward(manifest, internal.IsOffline()); err != nil {
+		return internal.FormatAPIError(logForwardKind, manifest.Name, "create", err)
+	}
+	return nil
+}
+
+// createLogForward creates a log-forward section, its binds and its log
+// targets in one transaction.
+func createLogForward(manifest LogForwardManifest, dryRun bool) error {
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	manifest.normalize()
+
+	if dryRun {
+		internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	err := internal.WithTransaction(func(tx *internal.Transaction) error {
+		if _, err := internal.SendRequest("POST", logForwardsPath, tx.Params(), manifest.toAPIPayload()); err != nil {
+			return err
+		}
+		return syncChildren(tx, manifest.Name, &LogForwardManifest{}, &manifest)
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus(logForwardKind, manifest.Name, internal.ActionCreated)
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logforwards provides commands to manage HAProxy log-forward
+// sections, which receive syslog messages on their binds and relay them to
+// their log targets.
+package logforwards
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteLogForwardsCmd represents "delete log-forwards <name>".
+var DeleteLogForwardsCmd = &cobra.Command{
+	Use:     "log-forwards <name>",
+	Aliases: []string{"log-forward"},
+	Short:   "Delete a log-forward section with its binds and log targets",
+	Long: `Delete a log-forward section together with its binds and log targets.
+
+Examples:
+  haproxyctl delete log-forwards syslog`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := DeleteLogForwardByName(args[0]); err != nil {
+			internal.Fatalf("Failed to delete log-forward %q: %v", args[0], err)
+		}
+	},
+}
+
+// DeleteLogForwardByName deletes a log-forward section.
+func DeleteLogForwardByName(name string) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("DELETE", logForwardPath(name), params, nil); err != nil {
+		return internal.FormatAPIError(logForwardKind, name, "delete", err)
+	}
+
+	internal.PrintStatus(logForwardKind, name, internal.ActionDeleted)
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logforwards provides commands to manage HAProxy log-forward
+// sections, which receive syslog messages on their binds and relay them to
+// their log targets.
+package logforwards
+
+import (
+	"fmt"
+	"strings"
+
+	"haproxyctl/cmd/logtargets"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetLogForwardsCmd represents "get log-forwards".
+var GetLogForwardsCmd = &cobra.Command{
+	Use:     "log-forwards [name]",
+	Aliases: []string{"log-forward"},
+	Short:   "List HAProxy log-forward sections or fetch details of one",
+	Long: `List the log-forward sections that relay syslog traffic, or show one
+section with its binds and log targets. With -o yaml the output is a
+LogForward manifest that apply accepts.
+
+Examples:
+  haproxyctl get log-forwards
+  haproxyctl get log-forwards syslog
+  haproxyctl get log-forwards syslog -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFormat := internal.GetFlagString(cmd, "output")
+
+		if len(args) == 0 {
+			manifests, err := LogForwardManifests()
+			if err != nil {
+				internal.Fatalf("Failed to fetch log-forwards: %v", err)
+			}
+			if outputFormat != "" && outputFormat != "table" && outputFormat != internal.OutputFormatWide {
+				internal.FormatOutputForCmd(cmd, internal.WithColumns(manifests, internal.ColumnSet{Kind: logForwardKind}), outputFormat)
+				return
+			}
+			rows := make([]map[string]interface{}, 0, len(manifests))
+			for _, item := range manifests {
+				rows = append(rows, summaryRow(item.(*LogForwardManifest)))
+			}
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, logForwardColumns), outputFormat)
+			return
+		}
+
+		manifest, err := getLogForwardManifest(args[0])
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(logForwardKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch log-forward %q: %v", args[0], err)
+		}
+		if outputFormat == "" || outputFormat == "table" || outputFormat == internal.OutputFormatWide {
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(summaryRow(manifest), logForwardColumns), outputFormat)
+			return
+		}
+		internal.FormatOutputForCmd(cmd, manifest, outputFormat)
+	},
+}
+
+// logForwardColumns shows where a section listens and where it relays to.
+var logForwardColumns = internal.ColumnSet{
+	Kind:    logForwardKind,
+	Default: []string{"name", "binds", "dgram_binds", "log_targets"},
+	Wide:    []string{"maxconn", "backlog", "timeout_client"},
+}
+
+// summaryRow flattens a manifest into a table row.
+func summaryRow(m *LogForwardManifest) map[string]interface{} {
+	targets := make([]string, 0, len(m.LogTargets))
+	for _, t := range m.LogTargets {
+		targets = append(targets, t.Address)
+	}
+	return map[string]interface{}{
+		"name":           m.Name,
+		"binds":          joinBinds(m.Binds),
+		"dgram_binds":    joinBinds(m.DgramBinds),
+		"log_targets":    strings.Join(targets, ","),
+		"maxconn":        m.Maxconn,
+		"backlog":        m.Backlog,
+		"timeout_client": m.TimeoutClient,
+	}
+}
+
+// getLogForwardManifest fetches a log-forward section with its binds, dgram
+// binds and log targets.
+func getLogForwardManifest(name string) (*LogForwardManifest, error) {
+	obj, err := internal.GetResource(logForwardPath(name))
+	if err != nil {
+		return nil, err
+	}
+	binds, err := internal.GetResourceList(bindsPath(name))
+	if err != nil && !internal.IsNotFoundError(err) {
+		return nil, fmt.Errorf("failed to fetch binds of log-forward %q: %w", name, err)
+	}
+	dgramBinds, err := internal.GetResourceList(dgramBindsPath(name))
+	if err != nil && !internal.IsNotFoundError(err) {
+		return nil, fmt.Errorf("failed to fetch dgram binds of log-forward %q: %w", name, err)
+	}
+	targets, err := logtargets.Live(logtargets.ParentLogForward, name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return nil, fmt.Errorf("failed to fetch log targets of log-forward %q: %w", name, err)
+	}
+	return manifestFromAPI(obj, binds, dgramBinds, targets), nil
+}
+
+// LogForwardManifests returns every log-forward section, with its binds and
+// log targets, as a manifest that apply accepts, sorted by name.
+func LogForwardManifests() ([]interface{}, error) {
+	list, err := internal.GetResourceList(logForwardsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch log-forwards: %w", err)
+	}
+	internal.SortByStringField(list, "name")
+
+	manifests := make([]interface{}, 0, len(list))
+	for _, raw := range list {
+		name, _ := raw["name"].(string)
+		manifest, err := getLogForwardManifest(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch log-forward %q: %w", name, err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
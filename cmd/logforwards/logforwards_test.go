@@ -0,0 +1,156 @@
+package logforwards
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/cmd/logtargets"
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testLogForwardManifest = `apiVersion: haproxyctl/v1
+kind: LogForward
+name: syslog
+maxconn: 100
+timeout_client: 30s
+dgram_binds:
+  - address: 0.0.0.0
+    port: 514
+binds:
+  - address: 0.0.0.0
+    port: 601
+log_targets:
+  - address: 10.0.0.7:514
+    facility: local0
+`
+
+func bindSummary(srv *testserver.Server, dgram bool) string {
+	var out []string
+	for _, b := range srv.LogForwardBinds("syslog", dgram) {
+		out = append(out, b["name"].(string))
+	}
+	return strings.Join(out, ",")
+}
+
+func TestApplyLogForwardFromYAML(t *testing.T) {
+	srv := testserver.New(t)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyLogForwardFromYAML([]byte(testLogForwardManifest), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "logforward/syslog created") {
+		t.Fatalf("expected created status, got:\n%s", output)
+	}
+	section, ok := srv.LogForward("syslog")
+	if !ok || section["timeout_client"] != float64(30000) || section["maxconn"] != float64(100) {
+		t.Fatalf("log-forward not sent in API form: %+v", section)
+	}
+	if got := bindSummary(srv, false); got != "0.0.0.0:601" {
+		t.Fatalf("binds = %s", got)
+	}
+	if got := bindSummary(srv, true); got != "0.0.0.0:514" {
+		t.Fatalf("dgram binds = %s", got)
+	}
+	if targets := srv.List("log_forwards", "syslog", "log_targets"); len(targets) != 1 || targets[0]["address"] != "10.0.0.7:514" {
+		t.Fatalf("log targets = %+v", targets)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyLogForwardFromYAML([]byte(testLogForwardManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "logforward/syslog unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	changed := strings.Replace(testLogForwardManifest, `binds:
+  - address: 0.0.0.0
+    port: 601
+`, "", 1)
+	changed = strings.Replace(changed, "10.0.0.7:514", "ring@logbuf", 1)
+	version := srv.Version()
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyLogForwardFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	if got := bindSummary(srv, false); got != "" {
+		t.Fatalf("binds after change = %s", got)
+	}
+	if targets := srv.List("log_forwards", "syslog", "log_targets"); len(targets) != 1 || targets[0]["address"] != "ring@logbuf" {
+		t.Fatalf("log targets after change = %+v", targets)
+	}
+	if srv.Version() != version+1 {
+		t.Fatalf("apply used %d versions, want one transaction", srv.Version()-version)
+	}
+}
+
+func TestLogForwardValidate(t *testing.T) {
+	udp := []Bind{{Address: "0.0.0.0", Port: 514}}
+	valid := LogForwardManifest{Name: "syslog", DgramBinds: udp}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]LogForwardManifest{
+		"missing name":   {DgramBinds: udp},
+		"no binds":       {Name: "syslog"},
+		"bad port":       {Name: "syslog", Binds: []Bind{{Address: "0.0.0.0"}}},
+		"duplicate":      {Name: "syslog", DgramBinds: append(udp, udp...)},
+		"bad timeout":    {Name: "syslog", DgramBinds: udp, TimeoutClient: "soon"},
+		"bad log target": {Name: "syslog", DgramBinds: udp, LogTargets: []logtargets.LogTarget{{Address: "10.0.0.7:514"}}},
+		"wrong kind":     {Name: "syslog", DgramBinds: udp, Kind: "Ring"},
+	}
+	for name, m := range cases {
+		if err := m.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseBindFlag(t *testing.T) {
+	b, err := parseBindFlag("address=0.0.0.0,port=514")
+	if err != nil || b != (Bind{Address: "0.0.0.0", Port: 514}) {
+		t.Fatalf("parseBindFlag = %+v, %v", b, err)
+	}
+	if _, err := parseBindFlag("address=0.0.0.0,port=syslog"); err == nil {
+		t.Fatal("expected error for non-numeric port")
+	}
+}
+
+func TestCreateAndDeleteLogForward(t *testing.T) {
+	srv := testserver.New(t)
+
+	manifest := LogForwardManifest{
+		Name:       "syslog",
+		DgramBinds: []Bind{{Address: "0.0.0.0", Port: 514}},
+		LogTargets: []logtargets.LogTarget{{Address: "10.0.0.7:514", Facility: "local0"}},
+	}
+	_ = internal.CaptureStdout(t, func() {
+		if err := createLogForward(manifest, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	if got := bindSummary(srv, true); got != "0.0.0.0:514" {
+		t.Fatalf("dgram binds = %s", got)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteLogForwardByName("syslog"); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "logforward/syslog deleted") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if _, ok := srv.LogForward("syslog"); ok {
+		t.Fatal("log-forward still present after delete")
+	}
+	if got := bindSummary(srv, true); got != "" {
+		t.Fatalf("dgram binds survived the delete: %s", got)
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logforwards provides commands to manage HAProxy log-forward
+// sections, which receive syslog messages on their binds and relay them to
+// their log targets.
+package logforwards
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"haproxyctl/cmd/logtargets"
+	"haproxyctl/internal"
+)
+
+const (
+	apiVersionV1   = "haproxyctl/v1"
+	logForwardKind = "LogForward"
+
+	logForwardsPath = "/services/haproxy/configuration/log_forwards"
+)
+
+// LogForwardManifest is the manifest view of a "log-forward <name>" section
+// with its TCP binds ("bind"), UDP binds ("dgram-bind") and log targets.
+// timeout_client is written like the backend timeouts (e.g. 30s) and sent to
+// the API in milliseconds.
+//
+//nolint:tagliatelle // manifest uses HAProxy's snake_case names like backendConfig
+type LogForwardManifest struct {
+	APIVersion       string                 `json:"apiVersion" yaml:"apiVersion"`
+	Kind             string                 `json:"kind" yaml:"kind"`
+	Name             string                 `json:"name" yaml:"name"`
+	Backlog          int                    `json:"backlog,omitempty" yaml:"backlog,omitempty"`
+	Maxconn          int                    `json:"maxconn,omitempty" yaml:"maxconn,omitempty"`
+	TimeoutClient    string                 `json:"timeout_client,omitempty" yaml:"timeout_client,omitempty"`
+	AssumeRFC6587NTF bool                   `json:"assume_rfc6587_ntf,omitempty" yaml:"assume_rfc6587_ntf,omitempty"`
+	DontParseLog     bool                   `json:"dont_parse_log,omitempty" yaml:"dont_parse_log,omitempty"`
+	Binds            []Bind                 `json:"binds,omitempty" yaml:"binds,omitempty"`
+	DgramBinds       []Bind                 `json:"dgram_binds,omitempty" yaml:"dgram_binds,omitempty"`
+	LogTargets       []logtargets.LogTarget `json:"log_targets,omitempty" yaml:"log_targets,omitempty"`
+}
+
+// Bind is a "bind" or "dgram-bind" line of a log-forward section. The name
+// defaults to "<address>:<port>", as for frontend binds.
+type Bind struct {
+	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
+	Address string `json:"address" yaml:"address"`
+	Port    int    `json:"port" yaml:"port"`
+}
+
+// String renders the bind address the way it appears in haproxy.cfg.
+func (b Bind) String() string {
+	return fmt.Sprintf("%s:%d", b.Address, b.Port)
+}
+
+func (b Bind) validate() error {
+	if b.Address == "" {
+		return fmt.Errorf("bind %+v must have an address", b)
+	}
+	if b.Port <= 0 || b.Port > 65535 {
+		return fmt.Errorf("bind %s: invalid port %d", b.String(), b.Port)
+	}
+	return nil
+}
+
+// withDefaultName returns b with its name filled in when it has none.
+func (b Bind) withDefaultName() Bind {
+	if b.Name == "" {
+		b.Name = b.String()
+	}
+	return b
+}
+
+func (b Bind) toPayload() map[string]interface{} {
+	return map[string]interface{}{"name": b.withDefaultName().Name, "address": b.Address, "port": b.Port}
+}
+
+// bindFromAPI converts an API bind or dgram bind object.
+func bindFromAPI(obj map[string]interface{}) Bind {
+	var b Bind
+	b.Name, _ = obj["name"].(string)
+	b.Address, _ = obj["address"].(string)
+	b.Port, _ = internal.GetIntField(obj, "port")
+	return b
+}
+
+// parseBindFlag parses "address=0.0.0.0,port=514[,name=syslog]".
+func parseBindFlag(s string) (Bind, error) {
+	var b Bind
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return b, fmt.Errorf("invalid bind %q (expected address=...,port=...[,name=...])", s)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "name":
+			b.Name = value
+		case "address":
+			b.Address = value
+		case "port":
+			port, err := strconv.Atoi(value)
+			if err != nil {
+				return b, fmt.Errorf("invalid bind port %q", value)
+			}
+			b.Port = port
+		default:
+			return b, fmt.Errorf("unknown bind field %q", key)
+		}
+	}
+	return b, nil
+}
+
+// validateBinds checks a bind list; label names it in errors.
+func validateBinds(label string, binds []Bind) error {
+	seen := make(map[string]bool, len(binds))
+	for _, b := range binds {
+		if err := b.validate(); err != nil {
+			return fmt.Errorf("%s: %w", label, err)
+		}
+		name := b.withDefaultName().Name
+		if seen[name] {
+			return fmt.Errorf("%s: duplicate bind %q", label, name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// Validate checks the manifest before anything is sent to the API.
+func (m *LogForwardManifest) Validate() error {
+	if m.Name == "" {
+		return errors.New("log-forward name is required")
+	}
+	if m.Kind != "" && m.Kind != logForwardKind {
+		return fmt.Errorf("kind must be %q", logForwardKind)
+	}
+	if m.APIVersion != "" && m.APIVersion != apiVersionV1 {
+		return fmt.Errorf("apiVersion must be %q", apiVersionV1)
+	}
+	if m.Backlog < 0 || m.Maxconn < 0 {
+		return errors.New("backlog and maxconn must not be negative")
+	}
+	if _, err := internal.ParseDurationToMillis(m.TimeoutClient); err != nil {
+		return fmt.Errorf("invalid timeout_client: %w", err)
+	}
+	if len(m.Binds) == 0 && len(m.DgramBinds) == 0 {
+		return errors.New("log-forward needs at least one bind or dgram_bind to receive logs on")
+	}
+	if err := validateBinds("binds", m.Binds); err != nil {
+		return err
+	}
+	if err := validateBinds("dgram_binds", m.DgramBinds); err != nil {
+		return err
+	}
+	return logtargets.ValidateAll(m.LogTargets)
+}
+
+// toAPIPayload converts the section fields (not the binds or log targets)
+// into the object expected by the log_forwards endpoint. It must be called
+// on a validated manifest.
+func (m *LogForwardManifest) toAPIPayload() map[string]interface{} {
+	payload := map[string]interface{}{"name": m.Name}
+	if m.Backlog > 0 {
+		payload["backlog"] = m.Backlog
+	}
+	if m.Maxconn > 0 {
+		payload["maxconn"] = m.Maxconn
+	}
+	if ms, _ := internal.ParseDurationToMillis(m.TimeoutClient); ms > 0 {
+		payload["timeout_client"] = ms
+	}
+	if m.AssumeRFC6587NTF {
+		payload["assume-rfc6587-ntf"] = true
+	}
+	if m.DontParseLog {
+		payload["dont-parse-log"] = true
+	}
+	return payload
+}
+
+// normalize fills in kind, apiVersion and bind names, renders the timeout
+// the way it comes back from the API and sorts the binds by name, so
+// manifests from files and from the API compare equal. Log targets keep
+// their order, which is significant.
+func (m *LogForwardManifest) normalize() {
+	m.APIVersion, m.Kind = apiVersionV1, logForwardKind
+	if ms, err := internal.ParseDurationToMillis(m.TimeoutClient); err == nil {
+		m.TimeoutClient = internal.FormatMillisAsDuration(ms)
+	}
+	for _, binds := range []*[]Bind{&m.Binds, &m.DgramBinds} {
+		for i := range *binds {
+			(*binds)[i] = (*binds)[i].withDefaultName()
+		}
+		sort.Slice(*binds, func(i, j int) bool { return (*binds)[i].Name < (*binds)[j].Name })
+		if len(*binds) == 0 {
+			*binds = nil
+		}
+	}
+	if len(m.LogTargets) == 0 {
+		m.LogTargets = nil
+	}
+}
+
+// manifestFromAPI converts an API log_forward object and its children into
+// a manifest.
+func manifestFromAPI(obj map[string]interface{}, binds, dgramBinds []map[string]interface{}, targets []logtargets.LogTarget) *LogForwardManifest {
+	m := &LogForwardManifest{LogTargets: targets}
+	m.Name, _ = obj["name"].(string)
+	m.Backlog, _ = internal.GetIntField(obj, "backlog")
+	m.Maxconn, _ = internal.GetIntField(obj, "maxconn")
+	if ms, ok := internal.GetIntField(obj, "timeout_client"); ok {
+		m.TimeoutClient = internal.FormatMillisAsDuration(ms)
+	}
+	m.AssumeRFC6587NTF, _ = obj["assume-rfc6587-ntf"].(bool)
+	m.DontParseLog, _ = obj["dont-parse-log"].(bool)
+	for _, raw := range binds {
+		m.Binds = append(m.Binds, bindFromAPI(raw))
+	}
+	for _, raw := range dgramBinds {
+		m.DgramBinds = append(m.DgramBinds, bindFromAPI(raw))
+	}
+	m.normalize()
+	return m
+}
+
+func logForwardPath(name string) string {
+	return logForwardsPath + "/" + name
+}
+
+func bindsPath(logForward string) string {
+	return logForwardPath(logForward) + "/binds"
+}
+
+func dgramBindsPath(logForward string) string {
+	return logForwardPath(logForward) + "/dgram_binds"
+}
+
+// joinBinds renders binds as a comma-separated list of addresses for tables.
+func joinBinds(binds []Bind) string {
+	out := make([]string, 0, len(binds))
+	for _, b := range binds {
+		out = append(out, b.String())
+	}
+	return strings.Join(out, ",")
+}
@@ -19,13 +19,12 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
+	"haproxyctl/internal"
+
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var loginCmd = &cobra.Command{
@@ -35,8 +34,37 @@ var loginCmd = &cobra.Command{
 This command will prompt you for the following values:
 api_base_url, username and password via interactive prompts.  
 These values get written to:
-  $HOME/.config/haproxyctl/config.json`,
+  $HOME/.config/haproxyctl/config.json
+
+With the global --context flag the values are stored for that named context
+(which is created if needed); otherwise they are stored as the top-level
+endpoint. Other contexts in the file are kept.
+
+With --auth token the username and password prompts are replaced by a
+bearer token prompt, for Data Plane APIs behind an OAuth proxy. With
+--token-command the token is instead fetched by running that command
+whenever haproxyctl needs one.
+
+With --keyring the password is stored in the system keyring (macOS
+keychain, the Secret Service via secret-tool, or the Windows Credential
+Manager) and config.json only keeps a reference to it. Without a usable keyring it is stored in config.json as
+before.
+
+Examples:
+  haproxyctl login
+  haproxyctl login --keyring
+  haproxyctl login --context prod --auth token
+  haproxyctl login --auth token --token-command "vault read -field=token secret/haproxy"`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
+		authMode := internal.GetFlagString(cmd, "auth")
+		tokenCommand := internal.GetFlagString(cmd, "token-command")
+		if tokenCommand != "" {
+			authMode = authModeToken
+		}
+		if authMode != authModeBasic && authMode != authModeToken {
+			return fmt.Errorf("invalid --auth %q (allowed: %s, %s)", authMode, authModeBasic, authModeToken)
+		}
+
 		// validator that disallows empty strings.
 		validateNonEmpty := func(input string) error {
 			if strings.TrimSpace(input) == "" {
@@ -45,10 +73,17 @@ These values get written to:
 			return nil
 		}
 
+		// A missing or unreadable file just means there is nothing to keep.
+		cfg, err := internal.LoadConfigFile()
+		if err != nil {
+			cfg = internal.Config{}
+		}
+		current, _ := cfg.Context(contextName)
+
 		// 1) API Base URL (free-form)
 		apiPrompt := promptui.Prompt{
 			Label:    "API Base URL",
-			Default:  viper.GetString("api_base_url"),
+			Default:  current.APIBaseURL,
 			Validate: validateNonEmpty,
 		}
 		apiBaseURL, err := apiPrompt.Run()
@@ -56,10 +91,27 @@ These values get written to:
 			return fmt.Errorf("prompt failed for api base url: %w", err)
 		}
 
+		endpoint := internal.Config{APIBaseURL: apiBaseURL}
+		if authMode == authModeToken {
+			endpoint.TokenCommand = tokenCommand
+			if tokenCommand == "" {
+				tokenPrompt := promptui.Prompt{
+					Label:    "Token",
+					Validate: validateNonEmpty,
+					Mask:     '*',
+				}
+				endpoint.Token, err = tokenPrompt.Run()
+				if err != nil {
+					return fmt.Errorf("prompt failed for token: %w", err)
+				}
+			}
+			return saveLogin(cfg, endpoint)
+		}
+
 		// 2) Username (free-form)
 		usernamePrompt := promptui.Prompt{
 			Label:    "Username",
-			Default:  viper.GetString("username"),
+			Default:  current.Username,
 			Validate: validateNonEmpty,
 		}
 		username, err := usernamePrompt.Run()
@@ -77,54 +129,55 @@ These values get written to:
 			return fmt.Errorf("prompt failed for password: %w", err)
 		}
 
-		// 3.2) Create config directory if it doesn't exist
-		configDir := filepath.Join(os.Getenv("HOME"), ".config", "haproxyctl")
-		configFile := filepath.Join(configDir, "config.json")
-		// viper setup
-		viper.SetConfigFile(configFile)
-		viper.SetConfigType("json")
-
-		const (
-			configDirPerm  = 0o700
-			configFilePerm = 0o600
-		)
-
-		// Ensure the config directory exists
-		// 3.1) Check if the config directory exists, if not, create
-		if err := os.MkdirAll(configDir, configDirPerm); err != nil {
-			return fmt.Errorf("cannot create config dir %q: %w", configDir, err)
-		}
-		// 3.2) make sure the file itself exists
-		if _, err := os.Stat(configFile); os.IsNotExist(err) {
-			// If the file does not exist, create it with appropriate permissions
-			if err := os.WriteFile(configFile, []byte{}, configFilePerm); err != nil {
-				return fmt.Errorf("cannot create config file %q: %w", configFile, err)
+		endpoint.Username, endpoint.Password = username, password
+		if internal.GetFlagBool(cmd, "keyring") {
+			account := contextLabel(contextName)
+			if err := internal.StoreKeyringSecret(account, password); err != nil {
+				internal.Warnf("failed to store password in the keyring, keeping it in the config file: %v", err)
+			} else {
+				endpoint.Password, endpoint.KeyringAccount = "", account
 			}
 		}
-		// 3.4) Set the values
-		// Note: viper will create the config file if it doesn't exist
-		// and write the values to it.
-		// This is a good place to set defaults if needed.
-		// e.g., viper.SetDefault("api_base_url", "https://api.example.com")
-		//       viper.SetDefault("username", "admin")
-		//       viper.SetDefault("password", "password")
-		viper.Set("api_base_url", apiBaseURL)
-		viper.Set("username", username)
-		viper.Set("password", password)
-		// 3.4) Overwrite the config file
-		// This will write the config to the specified file.
-		// If the file already exists, it will be overwritten.
-		if err := viper.WriteConfigAs(configFile); err != nil {
-			return fmt.Errorf("failed to write config: %w", err)
-		}
-		// 4) Print the configuration
-		cmd.Printf("Configuration saved to %s\n", configFile)
+		return saveLogin(cfg, endpoint)
+	},
+}
 
-		return nil
+const (
+	authModeBasic = "basic"
+	authModeToken = "token"
+)
 
-	},
+// saveLogin stores the credentials of endpoint for the selected context,
+// replacing those of the other auth mode, and keeps the rest of cfg.
+func saveLogin(cfg internal.Config, endpoint internal.Config) error {
+	if contextName == "" {
+		cfg.APIBaseURL = endpoint.APIBaseURL
+		cfg.Username, cfg.Password = endpoint.Username, endpoint.Password
+		cfg.KeyringAccount = endpoint.KeyringAccount
+		cfg.Token, cfg.TokenCommand = endpoint.Token, endpoint.TokenCommand
+	} else {
+		if cfg.Contexts == nil {
+			cfg.Contexts = map[string]internal.Config{}
+		}
+		// Keep the TLS settings of an existing context.
+		ctxCfg := cfg.Contexts[contextName]
+		ctxCfg.APIBaseURL = endpoint.APIBaseURL
+		ctxCfg.Username, ctxCfg.Password = endpoint.Username, endpoint.Password
+		ctxCfg.KeyringAccount = endpoint.KeyringAccount
+		ctxCfg.Token, ctxCfg.TokenCommand = endpoint.Token, endpoint.TokenCommand
+		cfg.Contexts[contextName] = ctxCfg
+	}
+	if err := internal.SaveConfig(cfg); err != nil {
+		return err
+	}
+
+	internal.PrintStatus("Context", contextLabel(contextName), "logged in")
+	return nil
 }
 
 func init() {
 	rootCmd.AddCommand(loginCmd)
+	loginCmd.Flags().String("auth", authModeBasic, "Authentication mode: basic (username and password) or token (bearer token)")
+	loginCmd.Flags().Bool("keyring", false, "Store the password in the system keyring instead of config.json")
+	loginCmd.Flags().String("token-command", "", "Command that prints a bearer token; implies --auth token")
 }
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"sort"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// logoutCmd represents the "logout" command.
+var logoutCmd = &cobra.Command{
+	Use:   "logout [context]",
+	Short: "Remove stored Data Plane API credentials",
+	Long: `Remove the username and password stored for a context from
+~/.config/haproxyctl/config.json, and from the system keyring when login
+--keyring put it there. Without an argument the default endpoint is logged
+out; --all logs out the default endpoint and every named context. The API
+URL is kept, so "haproxyctl login" can offer it again.
+
+Examples:
+  haproxyctl logout
+  haproxyctl logout prod-b
+  haproxyctl logout --all`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		all := internal.GetFlagBool(cmd, "all")
+		if all && len(args) > 0 {
+			internal.FatalCodef(internal.ExitUsage, "--all cannot be combined with a context name")
+		}
+
+		cfg, err := internal.LoadConfigFile()
+		if err != nil {
+			internal.Fatalf("Failed to load configuration: %v", err)
+		}
+
+		// "" is the default endpoint.
+		names := []string{""}
+		switch {
+		case len(args) == 1:
+			names = []string{args[0]}
+		case all:
+			for name := range cfg.Contexts {
+				names = append(names, name)
+			}
+			sort.Strings(names[1:])
+		}
+		for _, name := range names {
+			forgetKeyringPassword(cfg, name)
+			if err := cfg.ClearCredentials(name); err != nil {
+				internal.Fatalf("Failed to log out: %v", err)
+			}
+		}
+		if err := internal.SaveConfig(cfg); err != nil {
+			internal.Fatalf("Failed to save configuration: %v", err)
+		}
+
+		for _, name := range names {
+			internal.PrintStatus("Context", contextLabel(name), "logged out")
+		}
+	},
+}
+
+// forgetKeyringPassword removes the keyring entry of the named context, if
+// its password is kept there.
+func forgetKeyringPassword(cfg internal.Config, name string) {
+	endpoint, err := cfg.Context(name)
+	if err != nil || endpoint.KeyringAccount == "" {
+		return
+	}
+	if err := internal.DeleteKeyringSecret(endpoint.KeyringAccount); err != nil {
+		internal.Warnf("failed to remove password of %q from the keyring: %v", endpoint.KeyringAccount, err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+
+	logoutCmd.Flags().Bool("all", false, "Log out of the default endpoint and every named context")
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logtargets provides commands to manage the log targets (syslog
+// destinations) of the global section, frontends, backends and log-forward
+// sections.
+package logtargets
+
+import (
+	"fmt"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateLogTargetsCmd represents "create log-targets --parent <global|type/name>".
+var CreateLogTargetsCmd = &cobra.Command{
+	Use:     "log-targets",
+	Aliases: []string{"log-target"},
+	Short:   "Add a log target to the global section, a frontend or a backend",
+	Long: `Add a log line to a section. The target is appended unless --index
+places it at a given position. To manage the whole list, use log_targets
+in a Frontend, Backend or LogForward manifest or logTargets in the Global
+manifest.
+
+--ring sends the logs to a ring section (see "haproxyctl get rings")
+instead of a syslog server; it is the same as --address ring@<name>.
+
+Examples:
+  haproxyctl create log-targets --parent global --address 10.0.0.5:514 --facility local0 --level info
+  haproxyctl create log-targets --parent frontend/web --global
+  haproxyctl create log-targets --parent backend/app --address /dev/log --facility local1 --format rfc5424
+  haproxyctl create log-targets --parent global --ring logbuf --facility local0
+  haproxyctl create log-targets --parent log-forward/syslog --address 10.0.0.7:514 --facility local0`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, parentTypes...)
+		t := LogTarget{
+			Global:   internal.GetFlagBool(cmd, "global"),
+			Nolog:    internal.GetFlagBool(cmd, "nolog"),
+			Address:  internal.GetFlagString(cmd, "address"),
+			Facility: internal.GetFlagString(cmd, "facility"),
+			Level:    internal.GetFlagString(cmd, "level"),
+			Minlevel: internal.GetFlagString(cmd, "minlevel"),
+			Format:   internal.GetFlagString(cmd, "format"),
+			Length:   internal.GetFlagInt(cmd, "length"),
+		}
+		if ring := internal.GetFlagString(cmd, "ring"); ring != "" {
+			if t.Address != "" {
+				internal.FatalCodef(internal.ExitUsage, "--ring and --address are mutually exclusive")
+			}
+			t.Address = ringAddressPrefix + ring
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateLogTarget(parentType, parent, t, internal.GetFlagInt(cmd, "index"), dryRun); err != nil {
+			internal.Fatalf("Failed to create log target on %s: %v", parentID(parentType, parent), err)
+		}
+	},
+}
+
+func init() {
+	internal.AddParentFlag(CreateLogTargetsCmd, "log targets", parentTypes...)
+	CreateLogTargetsCmd.Flags().String("address", "", "Syslog destination, e.g. 10.0.0.5:514, udp@host:514 or /dev/log")
+	CreateLogTargetsCmd.Flags().String("ring", "", "Ring section to write to instead of --address")
+	CreateLogTargetsCmd.Flags().String("facility", "", "Syslog facility, e.g. local0")
+	CreateLogTargetsCmd.Flags().String("level", "", "Maximum level to send, e.g. info")
+	CreateLogTargetsCmd.Flags().String("minlevel", "", "Minimum level to send (needs --level)")
+	CreateLogTargetsCmd.Flags().String("format", "", "Log format, e.g. rfc3164, rfc5424, short or raw")
+	CreateLogTargetsCmd.Flags().Int("length", 0, "Maximum line length")
+	CreateLogTargetsCmd.Flags().Bool("global", false, "Add \"log global\" (use the global section's targets)")
+	CreateLogTargetsCmd.Flags().Bool("nolog", false, "Add \"nolog\" (disable logging)")
+	CreateLogTargetsCmd.Flags().Int("index", -1, "Position to insert the target at (default: append)")
+	CreateLogTargetsCmd.Flags().Bool("dry-run", false, "Print the log line without creating it")
+}
+
+// CreateLogTarget inserts a log target at index, or appends it when index
+// is negative.
+func CreateLogTarget(parentType, parent string, t LogTarget, index int, dryRun bool) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(t.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	current, err := Live(parentType, parent)
+	if err != nil {
+		return internal.FormatAPIError(parentType, parent, "get", err)
+	}
+	// HAProxy rejects the whole configuration when the ring is missing, so
+	// catch it here with a clearer error.
+	if ring, ok := t.ring(); ok {
+		if _, err := internal.GetResource("/services/haproxy/configuration/rings/" + ring); err != nil {
+			return internal.FormatAPIError("Ring", ring, "get", err)
+		}
+	}
+	if index < 0 || index > len(current) {
+		index = len(current)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", path(parentType, parent)+"/"+strconv.Itoa(index), params, t.toPayload()); err != nil {
+		return fmt.Errorf("failed to create %q: %w", t.String(), err)
+	}
+
+	internal.PrintStatus("LogTarget", fmt.Sprintf("%s/%d", parentID(parentType, parent), index), internal.ActionCreated)
+	return nil
+}
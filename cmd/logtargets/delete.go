@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logtargets provides commands to manage the log targets (syslog
+// destinations) of the global section, frontends, backends and log-forward
+// sections.
+package logtargets
+
+import (
+	"fmt"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteLogTargetsCmd represents "delete log-targets --parent <global|type/name> --index N".
+var DeleteLogTargetsCmd = &cobra.Command{
+	Use:     "log-targets",
+	Aliases: []string{"log-target"},
+	Short:   "Delete a log target from the global section, a frontend or a backend",
+	Long: `Delete the log target at --index (as shown by "get log-targets").
+Later targets move up by one position.
+
+Examples:
+  haproxyctl delete log-targets --parent global --index 1`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, parentTypes...)
+		index := internal.GetFlagInt(cmd, "index")
+		if index < 0 {
+			internal.FatalCodef(internal.ExitUsage, "--index is required")
+		}
+		if err := DeleteLogTarget(parentType, parent, index); err != nil {
+			internal.Fatalf("Failed to delete log target %d of %s: %v", index, parentID(parentType, parent), err)
+		}
+	},
+}
+
+func init() {
+	internal.AddParentFlag(DeleteLogTargetsCmd, "log targets", parentTypes...)
+	DeleteLogTargetsCmd.Flags().Int("index", -1, "Position of the log target to delete (required)")
+}
+
+// DeleteLogTarget removes the log target at index.
+func DeleteLogTarget(parentType, parent string, index int) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s/%d", parentID(parentType, parent), index)
+	if _, err := internal.SendRequest("DELETE", path(parentType, parent)+"/"+strconv.Itoa(index), params, nil); err != nil {
+		return internal.FormatAPIError("LogTarget", id, "delete", err)
+	}
+
+	internal.PrintStatus("LogTarget", id, internal.ActionDeleted)
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logtargets provides commands to manage the log targets (syslog
+// destinations) of the global section, frontends, backends and log-forward
+// sections.
+package logtargets
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetLogTargetsCmd represents "get log-targets --parent <global|type/name>".
+var GetLogTargetsCmd = &cobra.Command{
+	Use:     "log-targets",
+	Aliases: []string{"log-target"},
+	Short:   "List the log targets of the global section, a frontend or a backend",
+	Long: `List the log targets (log lines) of a section in declaration order.
+The index column is the position used by the delete command.
+
+Examples:
+  haproxyctl get log-targets --parent global
+  haproxyctl get log-targets --parent frontend/web -o yaml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		parentType, parent := internal.ParentFromFlag(cmd, parentTypes...)
+		list, err := internal.GetResourceList(path(parentType, parent))
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", parentID(parentType, parent))
+			}
+			internal.Fatalf("Failed to fetch log targets of %s: %v", parentID(parentType, parent), err)
+		}
+		internal.SortForCmd(cmd, list)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(list, logTargetColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// logTargetColumns shows where and what each target logs; wide adds the
+// formatting and sampling options.
+var logTargetColumns = internal.ColumnSet{
+	Default: []string{"index", "address", "facility", "level", "global", "nolog"},
+	Wide:    []string{"minlevel", "format", "length", "sample_range", "sample_size"},
+}
+
+func init() {
+	internal.AddParentFlag(GetLogTargetsCmd, "log targets", parentTypes...)
+}
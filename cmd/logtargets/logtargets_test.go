@@ -0,0 +1,108 @@
+package logtargets
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestLogTargetValidateAndString(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		target LogTarget
+		want   string
+	}{
+		{LogTarget{Global: true}, "log global"},
+		{LogTarget{Nolog: true}, "nolog"},
+		{LogTarget{Address: "10.0.0.5:514", Facility: "local0", Level: "info", Minlevel: "err"}, "log 10.0.0.5:514 local0 info err"},
+		{LogTarget{Address: "/dev/log", Facility: "local1", Format: "rfc5424", Length: 4096}, "log /dev/log len 4096 format rfc5424 local1"},
+		{LogTarget{Address: "ring@logbuf", Facility: "local0"}, "log ring@logbuf local0"},
+	}
+	for _, tc := range cases {
+		if err := tc.target.Validate(); err != nil {
+			t.Errorf("Validate(%s) = %v", tc.want, err)
+		}
+		if got := tc.target.String(); got != tc.want {
+			t.Errorf("String() = %q, want %q", got, tc.want)
+		}
+	}
+
+	invalid := []LogTarget{
+		{},
+		{Address: "10.0.0.5:514"},
+		{Global: true, Nolog: true},
+		{Global: true, Address: "10.0.0.5:514"},
+		{Address: "10.0.0.5:514", Facility: "local0", Minlevel: "err"},
+		{Address: "10.0.0.5:514", Facility: "local0", SampleRange: "1"},
+		{Address: "ring@", Facility: "local0"},
+	}
+	for _, target := range invalid {
+		if err := target.Validate(); err == nil {
+			t.Errorf("expected error for %+v", target)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	got, err := Parse("address=10.0.0.5:514,facility=local0,level=info,length=2048")
+	want := LogTarget{Address: "10.0.0.5:514", Facility: "local0", Level: "info", Length: 2048}
+	if err != nil || got != want {
+		t.Fatalf("Parse = %+v, %v", got, err)
+	}
+	for _, bad := range []string{"10.0.0.5:514", "address=x,host=y", "address=x,length=long"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): expected error", bad)
+		}
+	}
+}
+
+func TestCreateAndDeleteGlobalLogTarget(t *testing.T) {
+	srv := testserver.New(t)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := CreateLogTarget(ParentGlobal, "", LogTarget{Address: "10.0.0.5:514", Facility: "local0"}, -1, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "logtarget/global/0 created") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	targets := srv.List("global", "", "log_targets")
+	if len(targets) != 1 || targets[0]["facility"] != "local0" {
+		t.Fatalf("global log targets = %+v", targets)
+	}
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := DeleteLogTarget(ParentGlobal, "", 0); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+	})
+	if targets := srv.List("global", "", "log_targets"); len(targets) != 0 {
+		t.Fatalf("global log targets after delete = %+v", targets)
+	}
+}
+
+func TestCreateRingLogTarget(t *testing.T) {
+	srv := testserver.New(t)
+	ringTarget := LogTarget{Address: "ring@logbuf", Facility: "local0"}
+
+	if err := CreateLogTarget(ParentGlobal, "", ringTarget, -1, false); err == nil || !strings.Contains(err.Error(), "logbuf") {
+		t.Fatalf("expected missing ring error, got %v", err)
+	}
+
+	srv.AddRing(map[string]interface{}{"name": "logbuf"})
+	_ = internal.CaptureStdout(t, func() {
+		if err := CreateLogTarget(ParentGlobal, "", ringTarget, -1, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	targets := srv.List("global", "", "log_targets")
+	if len(targets) != 1 || targets[0]["address"] != "ring@logbuf" {
+		t.Fatalf("global log targets = %+v", targets)
+	}
+}
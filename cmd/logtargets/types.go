@@ -0,0 +1,261 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logtargets provides commands to manage the log targets (syslog
+// destinations) of the global section, frontends, backends and log-forward
+// sections.
+package logtargets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	// ParentGlobal is the parent type of the global section's log targets.
+	ParentGlobal = internal.ParentGlobal
+	// ParentFrontend is the parent type of log targets on a frontend.
+	ParentFrontend = internal.ParentFrontend
+	// ParentBackend is the parent type of log targets on a backend.
+	ParentBackend = internal.ParentBackend
+	// ParentLogForward is the parent type of log targets on a log-forward
+	// section.
+	ParentLogForward = internal.ParentLogForward
+
+	// ringAddressPrefix marks an address that writes to a ring section
+	// ("log ring@logbuf local0") instead of a syslog server.
+	ringAddressPrefix = "ring@"
+)
+
+// parentTypes are the sections that own log targets.
+var parentTypes = []string{ParentGlobal, ParentFrontend, ParentBackend, ParentLogForward}
+
+// LogTarget is a "log" line: either "log global", "nolog", or
+// "log <address> [len <n>] [format <f>] [sample <r>:<s>] <facility> [<level> [<minlevel>]]".
+//
+//nolint:tagliatelle // manifest uses the Data Plane API snake_case names
+type LogTarget struct {
+	Global      bool   `json:"global,omitempty" yaml:"global,omitempty"`
+	Nolog       bool   `json:"nolog,omitempty" yaml:"nolog,omitempty"`
+	Address     string `json:"address,omitempty" yaml:"address,omitempty"`
+	Facility    string `json:"facility,omitempty" yaml:"facility,omitempty"`
+	Level       string `json:"level,omitempty" yaml:"level,omitempty"`
+	Minlevel    string `json:"minlevel,omitempty" yaml:"minlevel,omitempty"`
+	Format      string `json:"format,omitempty" yaml:"format,omitempty"`
+	Length      int    `json:"length,omitempty" yaml:"length,omitempty"`
+	SampleRange string `json:"sample_range,omitempty" yaml:"sample_range,omitempty"`
+	SampleSize  int    `json:"sample_size,omitempty" yaml:"sample_size,omitempty"`
+}
+
+// String renders the target the way it appears in haproxy.cfg.
+func (t LogTarget) String() string {
+	switch {
+	case t.Global:
+		return "log global"
+	case t.Nolog:
+		return "nolog"
+	}
+	parts := []string{"log", t.Address}
+	if t.Length > 0 {
+		parts = append(parts, "len", strconv.Itoa(t.Length))
+	}
+	if t.Format != "" {
+		parts = append(parts, "format", t.Format)
+	}
+	if t.SampleRange != "" {
+		parts = append(parts, "sample", t.SampleRange+":"+strconv.Itoa(t.SampleSize))
+	}
+	parts = append(parts, t.Facility)
+	if t.Level != "" {
+		parts = append(parts, t.Level)
+		if t.Minlevel != "" {
+			parts = append(parts, t.Minlevel)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Validate checks that the target is one of the three forms of a log line.
+func (t LogTarget) Validate() error {
+	if t.Global || t.Nolog {
+		if t.Global && t.Nolog {
+			return errors.New("log target cannot set both global and nolog")
+		}
+		if t.Address != "" || t.Facility != "" {
+			return fmt.Errorf("%q takes no address or facility", t.String())
+		}
+		return nil
+	}
+	if t.Address == "" || t.Facility == "" {
+		return errors.New("log target needs address and facility (or global/nolog)")
+	}
+	if name, ok := t.ring(); ok && name == "" {
+		return fmt.Errorf("%q: ring address needs a ring name", t.String())
+	}
+	if t.Minlevel != "" && t.Level == "" {
+		return fmt.Errorf("%q: minlevel needs level", t.String())
+	}
+	if (t.SampleRange == "") != (t.SampleSize == 0) {
+		return fmt.Errorf("%q: sample_range and sample_size go together", t.String())
+	}
+	return nil
+}
+
+// ring returns the ring section the target writes to, if any.
+func (t LogTarget) ring() (string, bool) {
+	return strings.CutPrefix(t.Address, ringAddressPrefix)
+}
+
+// ValidateAll validates every target of a list.
+func ValidateAll(list []LogTarget) error {
+	for i, t := range list {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("log target %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Parse parses a log target written as comma-separated key=value pairs,
+// e.g. "address=10.0.0.5:514,facility=local0,level=info", as used by the
+// --log flags of the commands that create whole sections.
+func Parse(s string) (LogTarget, error) {
+	var t LogTarget
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return t, fmt.Errorf("invalid log target %q (expected address=...,facility=...[,level=...])", s)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "address":
+			t.Address = value
+		case "facility":
+			t.Facility = value
+		case "level":
+			t.Level = value
+		case "minlevel":
+			t.Minlevel = value
+		case "format":
+			t.Format = value
+		case "length":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return t, fmt.Errorf("invalid log target length %q", value)
+			}
+			t.Length = n
+		default:
+			return t, fmt.Errorf("unknown log target field %q", key)
+		}
+	}
+	return t, nil
+}
+
+func (t LogTarget) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{}
+	if t.Global {
+		payload["global"] = true
+	}
+	if t.Nolog {
+		payload["nolog"] = true
+	}
+	set := func(key, value string) {
+		if value != "" {
+			payload[key] = value
+		}
+	}
+	set("address", t.Address)
+	set("facility", t.Facility)
+	set("level", t.Level)
+	set("minlevel", t.Minlevel)
+	set("format", t.Format)
+	set("sample_range", t.SampleRange)
+	if t.Length > 0 {
+		payload["length"] = t.Length
+	}
+	if t.SampleSize > 0 {
+		payload["sample_size"] = t.SampleSize
+	}
+	return payload
+}
+
+// fromAPI converts an API log target object.
+func fromAPI(obj map[string]interface{}) LogTarget {
+	var t LogTarget
+	t.Global, _ = obj["global"].(bool)
+	t.Nolog, _ = obj["nolog"].(bool)
+	t.Address, _ = obj["address"].(string)
+	t.Facility, _ = obj["facility"].(string)
+	t.Level, _ = obj["level"].(string)
+	t.Minlevel, _ = obj["minlevel"].(string)
+	t.Format, _ = obj["format"].(string)
+	t.SampleRange, _ = obj["sample_range"].(string)
+	if v, ok := obj["length"].(float64); ok {
+		t.Length = int(v)
+	}
+	if v, ok := obj["sample_size"].(float64); ok {
+		t.SampleSize = int(v)
+	}
+	return t
+}
+
+// path returns the log_targets endpoint of the global section, a frontend,
+// a backend or a log-forward section; parent is ignored for the global
+// section.
+func path(parentType, parent string) string {
+	if parentType == ParentGlobal {
+		return "/services/haproxy/configuration/global/log_targets"
+	}
+	return fmt.Sprintf("/services/haproxy/configuration/%ss/%s/log_targets", parentType, parent)
+}
+
+// Live returns the log targets of a section in order, or nil when it has
+// none.
+func Live(parentType, parent string) ([]LogTarget, error) {
+	return LiveWithContext(context.Background(), parentType, parent)
+}
+
+// LiveWithContext is Live with a context.
+func LiveWithContext(ctx context.Context, parentType, parent string) ([]LogTarget, error) {
+	list, err := internal.GetResourceListWithContext(ctx, path(parentType, parent))
+	if err != nil {
+		return nil, err
+	}
+	var out []LogTarget
+	for _, raw := range list {
+		out = append(out, fromAPI(raw))
+	}
+	return out, nil
+}
+
+// ApplyDiff turns the log targets of a section from before into after,
+// staging the changes in tx when it is non-nil.
+func ApplyDiff(tx *internal.Transaction, parentType, parent string, before, after []LogTarget) error {
+	return internal.ApplyIndexedDiff(tx, path(parentType, parent), before, after, func(t LogTarget) interface{} { return t.toPayload() })
+}
+
+// parentID names a parent in status lines, e.g. "frontend/web" or "global".
+func parentID(parentType, parent string) string {
+	if parentType == ParentGlobal {
+		return ParentGlobal
+	}
+	return parentType + "/" + parent
+}
@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/maintenance"
+
+	"github.com/spf13/cobra"
+)
+
+// maintenanceCmd represents the "maintenance" command.
+var maintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: "Put frontends and backends into planned maintenance",
+	Long: `Divert traffic away from a frontend or backend for planned downtime, and
+restore it afterwards.
+
+Examples:
+  haproxyctl maintenance enable frontend web --backend maintenance
+  haproxyctl maintenance disable frontend web`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(maintenanceCmd)
+
+	maintenanceCmd.AddCommand(maintenance.EnableMaintenanceCmd)
+	maintenanceCmd.AddCommand(maintenance.DisableMaintenanceCmd)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance provides commands to put frontends and backends into
+// (and out of) planned maintenance.
+package maintenance
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DisableMaintenanceCmd represents "maintenance disable <frontend|backend> <name>".
+var DisableMaintenanceCmd = &cobra.Command{
+	Use:   "disable <frontend|backend> <name>",
+	Short: "Restore a frontend or backend after maintenance",
+	Long: `Remove the rule inserted by "haproxyctl maintenance enable", using the
+locally recorded copy of that rule to find it even if other rules were
+added or reordered in the meantime.
+
+Examples:
+  haproxyctl maintenance disable frontend web
+  haproxyctl maintenance disable backend app --dry-run`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, err := parseKind(args[0])
+		if err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid arguments: %v", err)
+		}
+		if err := DisableMaintenance(kind, args[1], internal.GetFlagBool(cmd, "dry-run")); err != nil {
+			internal.Fatalf("Failed to disable maintenance on %s '%s': %v", kind, args[1], err)
+		}
+	},
+}
+
+func init() {
+	DisableMaintenanceCmd.Flags().Bool("dry-run", false, "Show which rule would be removed without removing it")
+}
+
+// DisableMaintenance removes the recorded maintenance rule and forgets the
+// record.
+func DisableMaintenance(kind, name string, dryRun bool) error {
+	server, err := currentServer()
+	if err != nil {
+		return err
+	}
+	st, err := loadState()
+	if err != nil {
+		return err
+	}
+	idx := st.find(server, kind, name)
+	if idx < 0 {
+		return fmt.Errorf("%s is not in maintenance (no local record for %s)", internal.ResourceID(displayKind(kind), name), server)
+	}
+	rec := st.Entries[idx]
+
+	listPath := sectionPath(kind, name) + "/" + rec.List
+	rules, err := internal.GetResourceList(listPath)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", rec.List, err)
+	}
+	ruleIndex := -1
+	for i, r := range rules {
+		if ruleMatches(r, rec.Rule) {
+			ruleIndex = i
+			break
+		}
+	}
+
+	if dryRun {
+		if ruleIndex < 0 {
+			_, _ = fmt.Fprintf(os.Stdout, "maintenance rule no longer present in %s; the record would be dropped\n", rec.List)
+		} else {
+			_, _ = fmt.Fprintf(os.Stdout, "would remove %s/%d\n", rec.List, ruleIndex)
+		}
+		internal.PrintDryRun()
+		return nil
+	}
+
+	if ruleIndex >= 0 {
+		version, err := internal.GetConfigurationVersion()
+		if err != nil {
+			return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+		}
+		if _, err := internal.SendRequest("DELETE", listPath+"/"+strconv.Itoa(ruleIndex),
+			map[string]string{"version": strconv.Itoa(version)}, nil); err != nil {
+			return fmt.Errorf("failed to remove maintenance rule: %w", err)
+		}
+	} else {
+		internal.Warnf("maintenance rule no longer present in %s; dropping the local record", rec.List)
+	}
+
+	st.Entries = append(st.Entries[:idx], st.Entries[idx+1:]...)
+	if err := internal.WriteStateFile(stateFileName, st); err != nil {
+		return err
+	}
+
+	internal.PrintStatus(displayKind(kind), name, "maintenance disabled")
+	return nil
+}
@@ -0,0 +1,93 @@
+package maintenance
+
+import (
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func newMaintenanceServer(t *testing.T) *testserver.Server {
+	t.Helper()
+
+	t.Cleanup(internal.SetStateDir(t.TempDir()))
+	srv := testserver.New(t)
+	srv.AddFrontend(map[string]interface{}{"name": "web", "default_backend": "app"})
+	srv.AddBackend(map[string]interface{}{"name": "app"})
+	srv.AddBackend(map[string]interface{}{"name": "maintenance"})
+	srv.AddListItem("frontends", "web", listSwitchingRules, map[string]interface{}{"name": "api", "cond": "if", "cond_test": "is_api"})
+	return srv
+}
+
+func TestMaintenance_EnableDisableBackendRoundTrip(t *testing.T) {
+	srv := newMaintenanceServer(t)
+
+	opts := enableOptions{Backend: "maintenance", Status: defaultMaintenanceStatus}
+	internal.CaptureStdout(t, func() {
+		if err := EnableMaintenance(kindFrontend, "web", opts); err != nil {
+			t.Fatalf("EnableMaintenance failed: %v", err)
+		}
+		// A second enable is a no-op.
+		if err := EnableMaintenance(kindFrontend, "web", opts); err != nil {
+			t.Fatalf("second EnableMaintenance failed: %v", err)
+		}
+	})
+
+	rules := srv.List("frontends", "web", listSwitchingRules)
+	if len(rules) != 2 || rules[0]["name"] != "maintenance" || rules[0]["cond_test"] != "TRUE" {
+		t.Fatalf("maintenance rule not inserted first: %+v", rules)
+	}
+
+	// Someone adds a rule in front of ours during the maintenance window.
+	srv.AddListItem("frontends", "web", listSwitchingRules, map[string]interface{}{"name": "static", "cond": "if", "cond_test": "is_static"})
+
+	internal.CaptureStdout(t, func() {
+		if err := DisableMaintenance(kindFrontend, "web", false); err != nil {
+			t.Fatalf("DisableMaintenance failed: %v", err)
+		}
+	})
+
+	rules = srv.List("frontends", "web", listSwitchingRules)
+	if len(rules) != 2 || rules[0]["name"] != "api" || rules[1]["name"] != "static" {
+		t.Fatalf("unexpected rules after disable: %+v", rules)
+	}
+	if err := DisableMaintenance(kindFrontend, "web", false); err == nil {
+		t.Fatalf("expected error disabling maintenance twice")
+	}
+}
+
+func TestMaintenance_ErrorfileOnBackend(t *testing.T) {
+	srv := newMaintenanceServer(t)
+
+	opts := enableOptions{Errorfile: "/etc/haproxy/errors/503.http", ContentType: "text/html", Status: defaultMaintenanceStatus}
+	internal.CaptureStdout(t, func() {
+		if err := EnableMaintenance(kindBackend, "app", opts); err != nil {
+			t.Fatalf("EnableMaintenance failed: %v", err)
+		}
+	})
+
+	rules := srv.List("backends", "app", listRequestRules)
+	if len(rules) != 1 || rules[0]["type"] != "return" || rules[0]["return_content"] != "/etc/haproxy/errors/503.http" {
+		t.Fatalf("unexpected http-request rules: %+v", rules)
+	}
+
+	internal.CaptureStdout(t, func() {
+		if err := DisableMaintenance(kindBackend, "app", false); err != nil {
+			t.Fatalf("DisableMaintenance failed: %v", err)
+		}
+	})
+	if rules := srv.List("backends", "app", listRequestRules); len(rules) != 0 {
+		t.Fatalf("maintenance rule not removed: %+v", rules)
+	}
+}
+
+func TestEnableOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	if err := (enableOptions{Backend: "m", Status: 503}).validate(kindBackend); err == nil {
+		t.Fatalf("expected --backend to be rejected for backends")
+	}
+	if err := (enableOptions{Backend: "m", Errorfile: "f", Status: 503}).validate(kindFrontend); err == nil {
+		t.Fatalf("expected --backend and --errorfile to be exclusive")
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance provides commands to put frontends and backends into
+// (and out of) planned maintenance.
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"haproxyctl/internal"
+)
+
+const (
+	stateFileName = "maintenance.json"
+
+	kindFrontend = "frontend"
+	kindBackend  = "backend"
+
+	listSwitchingRules = "backend_switching_rules"
+	listRequestRules   = "http_request_rules"
+)
+
+// record is the locally stored description of one maintenance window: the
+// rule haproxyctl inserted, so "disable" can remove exactly that rule.
+type record struct {
+	Server    string                 `json:"server"`
+	Kind      string                 `json:"kind"`
+	Name      string                 `json:"name"`
+	List      string                 `json:"list"`
+	Rule      map[string]interface{} `json:"rule"`
+	EnabledAt time.Time              `json:"enabled_at"` //nolint:tagliatelle // snake_case like the rest of the state files
+}
+
+type stateFile struct {
+	Entries []record `json:"entries"`
+}
+
+// parseKind accepts "frontend(s)" or "backend(s)".
+func parseKind(s string) (string, error) {
+	switch strings.TrimSuffix(strings.ToLower(s), "s") {
+	case kindFrontend:
+		return kindFrontend, nil
+	case kindBackend:
+		return kindBackend, nil
+	default:
+		return "", fmt.Errorf("unsupported resource type %q (expected frontend or backend)", s)
+	}
+}
+
+// sectionPath returns the configuration path of a frontend or backend.
+func sectionPath(kind, name string) string {
+	return fmt.Sprintf("/services/haproxy/configuration/%ss/%s", kind, name)
+}
+
+// displayKind returns the kind as shown by PrintStatus ("Frontend").
+func displayKind(kind string) string {
+	return strings.ToUpper(kind[:1]) + kind[1:]
+}
+
+// currentServer identifies the Data Plane API the records belong to, so
+// equally named sections on different clusters do not collide.
+func currentServer() (string, error) {
+	cfg, err := internal.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	return cfg.APIBaseURL, nil
+}
+
+func loadState() (stateFile, error) {
+	var st stateFile
+	err := internal.ReadStateFile(stateFileName, &st)
+	return st, err
+}
+
+// find returns the index of the record for server/kind/name, or -1.
+func (st stateFile) find(server, kind, name string) int {
+	for i, r := range st.Entries {
+		if r.Server == server && r.Kind == kind && r.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// maintenanceRule builds the rule that diverts traffic. With a maintenance
+// backend it is a "use_backend <backend> if TRUE" switching rule; otherwise
+// an "http-request return" answering with status (and the errorfile, if any).
+func maintenanceRule(backend, errorfile, contentType string, status int) (string, map[string]interface{}) {
+	if backend != "" {
+		return listSwitchingRules, map[string]interface{}{
+			"name":      backend,
+			"cond":      "if",
+			"cond_test": "TRUE",
+		}
+	}
+
+	rule := map[string]interface{}{
+		"type":               "return",
+		"return_status_code": status,
+	}
+	if errorfile != "" {
+		rule["return_content_type"] = contentType
+		rule["return_content_format"] = "file"
+		rule["return_content"] = errorfile
+	}
+	return listRequestRules, rule
+}
+
+// ruleMatches reports whether an API rule carries every field of want.
+// Values are compared by their printed form, since numbers come back from
+// the API as floats.
+func ruleMatches(apiRule, want map[string]interface{}) bool {
+	for k, v := range want {
+		if fmt.Sprint(apiRule[k]) != fmt.Sprint(v) {
+			return false
+		}
+	}
+	return true
+}
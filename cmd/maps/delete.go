@@ -0,0 +1,86 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maps provides commands to manage HAProxy map files.
+package maps
+
+import (
+	"fmt"
+	"net/url"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteMapsCmd represents "delete map"; entries are removed with its
+// "entry" subcommand.
+var DeleteMapsCmd = &cobra.Command{
+	Use:     "map",
+	Aliases: []string{"maps"},
+	Short:   "Delete entries of runtime maps",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+// deleteMapEntryCmd represents "delete map entry <map> <key>".
+var deleteMapEntryCmd = &cobra.Command{
+	Use:     "entry <map_name> <key>",
+	Aliases: []string{"entries"},
+	Short:   "Remove one entry from a runtime map",
+	Long: `Remove an entry from a map through the runtime API with force_sync, so
+the change takes effect immediately and is written back to the map file.
+
+Examples:
+  haproxyctl delete map entry routes.map old.example.com`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := DeleteMapEntry(args[0], args[1], internal.GetFlagBool(cmd, "dry-run")); err != nil {
+			internal.Fatalf("Failed to delete entry '%s' of map '%s': %v", args[1], args[0], err)
+		}
+	},
+}
+
+func init() {
+	deleteMapEntryCmd.Flags().Bool("dry-run", false, "Show the entry removal without applying it")
+	DeleteMapsCmd.AddCommand(deleteMapEntryCmd)
+}
+
+// DeleteMapEntry removes key from a map.
+func DeleteMapEntry(mapName, key string, dryRun bool) error {
+	live, err := liveMapEntries(mapName)
+	if err != nil {
+		return err
+	}
+	old, exists := live[key]
+	if !exists {
+		return fmt.Errorf("map %q has no entry %q", mapName, key)
+	}
+
+	_, _ = fmt.Printf("- %s %s\n", key, old)
+	if dryRun {
+		internal.PrintDryRun()
+		return nil
+	}
+
+	params := map[string]string{"force_sync": "true"}
+	if _, err := internal.SendRequest("DELETE", entriesEndpoint(mapName)+"/"+url.PathEscape(key), params, nil); err != nil {
+		return fmt.Errorf("failed to remove entry %q: %w", key, err)
+	}
+	internal.PrintStatus("Map", mapName+" "+key, internal.ActionDeleted)
+	return nil
+}
@@ -0,0 +1,68 @@
+package maps
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestSetMapEntry_UpsertsAndSkipsUnchanged(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddMapEntry("routes.map", "web.example.com", "be_web")
+
+	out := internal.CaptureStdout(t, func() {
+		if err := SetMapEntry("routes.map", "api.example.com", "be_api", false); err != nil {
+			t.Fatalf("SetMapEntry (add) failed: %v", err)
+		}
+		if err := SetMapEntry("routes.map", "web.example.com", "be_green", false); err != nil {
+			t.Fatalf("SetMapEntry (update) failed: %v", err)
+		}
+		if err := SetMapEntry("routes.map", "web.example.com", "be_green", false); err != nil {
+			t.Fatalf("SetMapEntry (unchanged) failed: %v", err)
+		}
+	})
+
+	got := srv.MapEntries("routes.map")
+	if got["api.example.com"] != "be_api" || got["web.example.com"] != "be_green" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+	if n := srv.CountRequests("PUT", "/v3/services/haproxy/runtime/maps/routes.map/entries/web.example.com"); n != 1 {
+		t.Fatalf("expected 1 PUT for the changed entry, got %d", n)
+	}
+	for _, want := range []string{"+ api.example.com be_api", "~ web.example.com be_web -> be_green", "unchanged"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDeleteMapEntry(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddMapEntry("routes.map", "old.example.com", "be_old")
+	srv.AddMapEntry("routes.map", "web.example.com", "be_web")
+
+	internal.CaptureStdout(t, func() {
+		if err := DeleteMapEntry("routes.map", "old.example.com", true); err != nil {
+			t.Fatalf("DeleteMapEntry (dry-run) failed: %v", err)
+		}
+	})
+	if _, ok := srv.MapEntries("routes.map")["old.example.com"]; !ok {
+		t.Fatalf("dry-run removed the entry")
+	}
+
+	internal.CaptureStdout(t, func() {
+		if err := DeleteMapEntry("routes.map", "old.example.com", false); err != nil {
+			t.Fatalf("DeleteMapEntry failed: %v", err)
+		}
+	})
+	got := srv.MapEntries("routes.map")
+	if _, ok := got["old.example.com"]; ok || got["web.example.com"] != "be_web" {
+		t.Fatalf("unexpected entries after delete: %+v", got)
+	}
+
+	if err := DeleteMapEntry("routes.map", "missing.example.com", false); err == nil {
+		t.Fatalf("expected error for a missing key")
+	}
+}
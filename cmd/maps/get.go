@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maps provides commands to manage HAProxy map files.
+package maps
+
+import (
+	"sort"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+const mapsEndpoint = "/services/haproxy/runtime/maps"
+
+// GetMapsCmd represents "get maps".
+var GetMapsCmd = &cobra.Command{
+	Use:     "maps",
+	Aliases: []string{"map"},
+	Short:   "List the runtime maps loaded by HAProxy",
+	Long: `List the map files HAProxy has loaded. Use "get map entries <map>" to
+show the entries of one map.
+
+Examples:
+  haproxyctl get maps
+  haproxyctl get map entries routes.map`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		list, err := internal.GetResourceList(mapsEndpoint)
+		if err != nil {
+			internal.Fatalf("Failed to fetch maps: %v", err)
+		}
+		internal.SortByStringField(list, "file")
+		internal.SortForCmd(cmd, list)
+		internal.FormatOutputForCmd(cmd, list, internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// getMapEntriesCmd represents "get map entries <map>".
+var getMapEntriesCmd = &cobra.Command{
+	Use:     "entries <map_name>",
+	Aliases: []string{"entry"},
+	Short:   "Show the live entries of a runtime map",
+	Long: `Show the key/value entries of a map as HAProxy currently uses them.
+
+Examples:
+  haproxyctl get map entries routes.map
+  haproxyctl get map entries routes.map -o yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		live, err := liveMapEntries(args[0])
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Map", args[0]))
+			}
+			internal.Fatalf("Failed to fetch entries of map '%s': %v", args[0], err)
+		}
+
+		keys := make([]string, 0, len(live))
+		for k := range live {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		rows := make([]map[string]interface{}, 0, len(keys))
+		for _, k := range keys {
+			rows = append(rows, map[string]interface{}{"key": k, "value": live[k]})
+		}
+		internal.SortForCmd(cmd, rows)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, mapEntryColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// mapEntryColumns shows the key before the value.
+var mapEntryColumns = internal.ColumnSet{Default: []string{"key", "value"}}
+
+func init() {
+	GetMapsCmd.AddCommand(getMapEntriesCmd)
+}
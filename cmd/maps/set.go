@@ -0,0 +1,88 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maps provides commands to manage HAProxy map files.
+package maps
+
+import (
+	"fmt"
+	"net/url"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// SetMapCmd represents "set map <map> <key> <value>".
+var SetMapCmd = &cobra.Command{
+	Use:   "map <map_name> <key> <value>",
+	Short: "Add or change one entry of a runtime map",
+	Long: `Add an entry to a map, or change its value when the key already exists.
+The change is made through the runtime API with force_sync, so it takes
+effect immediately and is written back to the map file without a reload.
+
+Examples:
+  haproxyctl set map routes.map api.example.com api
+  haproxyctl set map routes.map www.example.com web --dry-run`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := SetMapEntry(args[0], args[1], args[2], internal.GetFlagBool(cmd, "dry-run")); err != nil {
+			internal.Fatalf("Failed to set entry '%s' of map '%s': %v", args[1], args[0], err)
+		}
+	},
+}
+
+func init() {
+	SetMapCmd.Flags().Bool("dry-run", false, "Show the entry change without applying it")
+}
+
+// SetMapEntry adds key to a map or updates its value.
+func SetMapEntry(mapName, key, value string, dryRun bool) error {
+	live, err := liveMapEntries(mapName)
+	if err != nil {
+		return err
+	}
+
+	old, exists := live[key]
+	switch {
+	case exists && old == value:
+		internal.PrintStatus("Map", mapName+" "+key, internal.ActionUnchanged)
+		return nil
+	case exists:
+		_, _ = fmt.Printf("~ %s %s -> %s\n", key, old, value)
+	default:
+		_, _ = fmt.Printf("+ %s %s\n", key, value)
+	}
+	if dryRun {
+		internal.PrintDryRun()
+		return nil
+	}
+
+	params := map[string]string{"force_sync": "true"}
+	endpoint := entriesEndpoint(mapName)
+	if exists {
+		if _, err := internal.SendRequest("PUT", endpoint+"/"+url.PathEscape(key), params, map[string]string{"value": value}); err != nil {
+			return fmt.Errorf("failed to update entry %q: %w", key, err)
+		}
+		internal.PrintStatus("Map", mapName+" "+key, internal.ActionConfigured)
+		return nil
+	}
+	if _, err := internal.SendRequest("POST", endpoint, params, mapEntry{Key: key, Value: value}); err != nil {
+		return fmt.Errorf("failed to add entry %q: %w", key, err)
+	}
+	internal.PrintStatus("Map", mapName+" "+key, internal.ActionCreated)
+	return nil
+}
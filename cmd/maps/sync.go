@@ -0,0 +1,155 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maps provides commands to manage HAProxy map files.
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// SyncMapsCmd represents "sync maps <map> -f <file>".
+var SyncMapsCmd = &cobra.Command{
+	Use:     "maps <map_name> -f <file>",
+	Aliases: []string{"map"},
+	Short:   "Bring a live map in line with a local map file",
+	Long: `Compare a local map file with the live contents of an HAProxy map and
+apply only the entries that were added or changed (and, with --prune,
+removed). Changes are made through the runtime API with force_sync, so
+they take effect immediately and are written back to the map file in
+storage without reloading HAProxy.
+
+The local file uses map file syntax: "<key> <value>" per line, "#" for
+comments.
+
+Examples:
+  haproxyctl sync maps routes.map -f routes.map
+  haproxyctl sync maps routes.map -f routes.map --prune --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := internal.GetFlagString(cmd, "filename")
+		if file == "" {
+			internal.FatalCodef(internal.ExitUsage, "-f/--filename is required")
+		}
+		f, err := os.Open(file) //nolint:gosec // CLI intentionally reads user-specified map paths
+		if err != nil {
+			internal.Fatalf("Failed to read map file: %v", err)
+		}
+		desired, err := parseMapFile(f)
+		_ = f.Close()
+		if err != nil {
+			internal.Fatalf("Failed to parse map file %s: %v", file, err)
+		}
+
+		opts := syncOptions{
+			Prune:  internal.GetFlagBool(cmd, "prune"),
+			DryRun: internal.GetFlagBool(cmd, "dry-run"),
+		}
+		if err := SyncMap(args[0], desired, opts); err != nil {
+			internal.Fatalf("Failed to sync map '%s': %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	SyncMapsCmd.Flags().StringP("filename", "f", "", "Local map file to sync from")
+	SyncMapsCmd.Flags().Bool("prune", false, "Remove live entries that are not in the local file")
+	SyncMapsCmd.Flags().Bool("dry-run", false, "Show the entry changes without applying them")
+}
+
+type syncOptions struct {
+	Prune  bool
+	DryRun bool
+}
+
+func entriesEndpoint(mapName string) string {
+	return fmt.Sprintf("/services/haproxy/runtime/maps/%s/entries", url.PathEscape(mapName))
+}
+
+// liveMapEntries returns the runtime entries of a map.
+func liveMapEntries(mapName string) (map[string]string, error) {
+	raw, err := internal.SendRequest("GET", entriesEndpoint(mapName), nil, nil)
+	if err != nil {
+		return nil, internal.FormatAPIError("Map", mapName, "get", err)
+	}
+	var entries []mapEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse map entries response: %w", err)
+	}
+	live := make(map[string]string, len(entries))
+	for _, e := range entries {
+		live[e.Key] = e.Value
+	}
+	return live, nil
+}
+
+// SyncMap applies the difference between desired and the live map.
+func SyncMap(mapName string, desired map[string]string, opts syncOptions) error {
+	live, err := liveMapEntries(mapName)
+	if err != nil {
+		return err
+	}
+
+	plan := planMapSync(desired, live, opts.Prune)
+	if plan.empty() {
+		internal.PrintStatus("Map", mapName, internal.ActionUnchanged)
+		return nil
+	}
+
+	for _, c := range plan.Add {
+		_, _ = fmt.Fprintf(os.Stdout, "+ %s %s\n", c.Key, c.New)
+	}
+	for _, c := range plan.Change {
+		_, _ = fmt.Fprintf(os.Stdout, "~ %s %s -> %s\n", c.Key, c.Old, c.New)
+	}
+	for _, c := range plan.Remove {
+		_, _ = fmt.Fprintf(os.Stdout, "- %s %s\n", c.Key, c.Old)
+	}
+	if opts.DryRun {
+		internal.PrintDryRun()
+		return nil
+	}
+
+	// force_sync writes every runtime change through to the map file.
+	params := map[string]string{"force_sync": "true"}
+	endpoint := entriesEndpoint(mapName)
+	for _, c := range plan.Add {
+		if _, err := internal.SendRequest("POST", endpoint, params, mapEntry{Key: c.Key, Value: c.New}); err != nil {
+			return fmt.Errorf("failed to add entry %q: %w", c.Key, err)
+		}
+	}
+	for _, c := range plan.Change {
+		if _, err := internal.SendRequest("PUT", endpoint+"/"+url.PathEscape(c.Key), params, map[string]string{"value": c.New}); err != nil {
+			return fmt.Errorf("failed to update entry %q: %w", c.Key, err)
+		}
+	}
+	for _, c := range plan.Remove {
+		if _, err := internal.SendRequest("DELETE", endpoint+"/"+url.PathEscape(c.Key), params, nil); err != nil {
+			return fmt.Errorf("failed to remove entry %q: %w", c.Key, err)
+		}
+	}
+
+	internal.PrintStatus("Map", mapName, fmt.Sprintf("synced (%d added, %d changed, %d removed)",
+		len(plan.Add), len(plan.Change), len(plan.Remove)))
+	return nil
+}
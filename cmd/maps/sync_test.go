@@ -0,0 +1,78 @@
+package maps
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestParseMapFile(t *testing.T) {
+	t.Parallel()
+
+	input := `# routes
+example.com   be_web
+api.example.com	be_api  # trailing text is part of the value
+
+static.example.com be_static
+`
+	got, err := parseMapFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseMapFile failed: %v", err)
+	}
+	if len(got) != 3 || got["example.com"] != "be_web" || got["api.example.com"] != "be_api  # trailing text is part of the value" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+
+	if _, err := parseMapFile(strings.NewReader("a b\na c\n")); err == nil {
+		t.Fatalf("expected duplicate key error")
+	}
+	if _, err := parseMapFile(strings.NewReader("lonely\n")); err == nil {
+		t.Fatalf("expected error for a line without value")
+	}
+}
+
+func TestSyncMap_AppliesOnlyDifferences(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddMapEntry("routes.map", "keep.example.com", "be_keep")
+	srv.AddMapEntry("routes.map", "move.example.com", "be_old")
+	srv.AddMapEntry("routes.map", "stale.example.com", "be_stale")
+
+	desired := map[string]string{
+		"keep.example.com": "be_keep",
+		"move.example.com": "be_new",
+		"new.example.com":  "be_added",
+	}
+
+	// Without --prune the stale entry survives.
+	internal.CaptureStdout(t, func() {
+		if err := SyncMap("routes.map", desired, syncOptions{}); err != nil {
+			t.Fatalf("SyncMap failed: %v", err)
+		}
+	})
+	got := srv.MapEntries("routes.map")
+	if got["move.example.com"] != "be_new" || got["new.example.com"] != "be_added" || got["stale.example.com"] != "be_stale" {
+		t.Fatalf("unexpected entries after sync: %+v", got)
+	}
+	if n := srv.CountRequests("PUT", "/v3/services/haproxy/runtime/maps/routes.map/entries/keep.example.com"); n != 0 {
+		t.Fatalf("unchanged entry was rewritten %d time(s)", n)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := SyncMap("routes.map", desired, syncOptions{Prune: true}); err != nil {
+			t.Fatalf("SyncMap --prune failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "- stale.example.com be_stale") || !strings.Contains(output, "1 removed") {
+		t.Fatalf("unexpected prune output:\n%s", output)
+	}
+	if got := srv.MapEntries("routes.map"); len(got) != 3 {
+		t.Fatalf("stale entry not pruned: %+v", got)
+	}
+	for _, r := range srv.Requests() {
+		if r.Method != "GET" && r.Query["force_sync"] != "true" {
+			t.Fatalf("%s %s sent without force_sync", r.Method, r.Path)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maps provides commands to manage HAProxy map files.
+package maps
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// mapEntry is one "key value" line of a map file.
+type mapEntry struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// parseMapFile reads HAProxy map file syntax: one "<key> <value>" pair per
+// line, separated by whitespace, with blank lines and "#" comments ignored.
+// The value is the rest of the line and may contain spaces.
+func parseMapFile(r io.Reader) (map[string]string, error) {
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sep := strings.IndexAny(line, " \t")
+		if sep < 0 {
+			return nil, fmt.Errorf("line %d: expected \"<key> <value>\", got %q", lineNo, line)
+		}
+		key := line[:sep]
+		if _, dup := entries[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", lineNo, key)
+		}
+		entries[key] = strings.TrimSpace(line[sep+1:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read map file: %w", err)
+	}
+	return entries, nil
+}
+
+// mapChange is one planned entry change. Old is empty for additions and
+// New is empty for removals.
+type mapChange struct {
+	Key string
+	Old string
+	New string
+}
+
+// mapPlan lists what a sync has to do, each slice sorted by key.
+type mapPlan struct {
+	Add    []mapChange
+	Change []mapChange
+	Remove []mapChange
+}
+
+func (p mapPlan) empty() bool {
+	return len(p.Add) == 0 && len(p.Change) == 0 && len(p.Remove) == 0
+}
+
+// planMapSync compares the desired entries with the live ones. Live entries
+// missing from desired are only removed when prune is set.
+func planMapSync(desired, live map[string]string, prune bool) mapPlan {
+	var plan mapPlan
+	for key, value := range desired {
+		old, ok := live[key]
+		switch {
+		case !ok:
+			plan.Add = append(plan.Add, mapChange{Key: key, New: value})
+		case old != value:
+			plan.Change = append(plan.Change, mapChange{Key: key, Old: old, New: value})
+		}
+	}
+	if prune {
+		for key, old := range live {
+			if _, ok := desired[key]; !ok {
+				plan.Remove = append(plan.Remove, mapChange{Key: key, Old: old})
+			}
+		}
+	}
+
+	for _, list := range [][]mapChange{plan.Add, plan.Change, plan.Remove} {
+		sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	}
+	return plan
+}
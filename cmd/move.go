@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/httprules"
+
+	"github.com/spf13/cobra"
+)
+
+// moveCmd represents the "move" command, which reorders position-indexed
+// resources such as HTTP rules.
+var moveCmd = &cobra.Command{
+	Use:   "move",
+	Short: "Move an ordered HAProxy resource to another position",
+	Long: `Change the position of a resource in an ordered list, such as the
+http-request or http-response rules of a frontend or backend, or the
+backend switching rules of a frontend.
+
+Examples:
+  haproxyctl move http-request-rules --parent frontend/web --index 3 --to 0
+  haproxyctl move http-response-rules --parent backend/app --index 0 --to 2
+  haproxyctl move backend-switching-rules web --index 2 --to 0`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+
+	moveCmd.AddCommand(httprules.MoveHTTPRequestRulesCmd)
+	moveCmd.AddCommand(httprules.MoveHTTPResponseRulesCmd)
+	moveCmd.AddCommand(frontends.MoveSwitchingRuleCmd)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix is the executable name prefix of plugins: "haproxyctl foo"
+// runs haproxyctl-foo when haproxyctl has no foo command.
+const pluginPrefix = "haproxyctl-"
+
+// EnvBinary tells a plugin which haproxyctl binary invoked it, so it can
+// call back into the CLI.
+const EnvBinary = "HAPROXYCTL_BINARY"
+
+// pluginCmd represents the "plugin" command.
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Work with haproxyctl plugins",
+	Long: `Plugins extend haproxyctl without forking it. Any executable on PATH named
+haproxyctl-<name> runs as "haproxyctl <name> [args...]" when haproxyctl has
+no <name> command of its own; dashes nest, so haproxyctl-cert-audit runs
+as "haproxyctl cert audit". Arguments are passed through unchanged.
+
+The plugin inherits the environment plus the resolved connection of the
+active context: HAPROXYCTL_API_URL, HAPROXYCTL_USERNAME,
+HAPROXYCTL_PASSWORD and HAPROXYCTL_CONTEXT, and HAPROXYCTL_BINARY with the
+path of haproxyctl itself. Set HAPROXYCTL_CONTEXT (not --context) to pick
+another context for a plugin.
+
+Examples:
+  haproxyctl plugin list`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+// pluginListCmd represents "plugin list".
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the plugins found on PATH",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		plugins := findPlugins(filepath.SplitList(os.Getenv("PATH")))
+		if len(plugins) == 0 {
+			_, _ = fmt.Fprintln(os.Stdout, "No plugins found on PATH.")
+			return
+		}
+		for _, p := range plugins {
+			_, _ = fmt.Fprintln(os.Stdout, p)
+		}
+	},
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
+
+// findPlugins returns the paths of the plugin executables in dirs. When
+// two directories hold the same plugin, the first one wins, as for exec.
+func findPlugins(dirs []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if !strings.HasPrefix(name, pluginPrefix) || e.IsDir() || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err != nil || info.Mode()&0o111 == 0 {
+				continue
+			}
+			seen[name] = true
+			out = append(out, path)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// reservedCommands are added by cobra while executing, so rootCmd.Find
+// does not know them yet; they never dispatch to plugins.
+var reservedCommands = []string{"help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+// lookupPlugin returns the plugin for args and the arguments left for it,
+// preferring the longest match (haproxyctl-cert-audit over haproxyctl-cert).
+// It returns "" when args name a built-in command or no plugin exists.
+func lookupPlugin(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || slices.Contains(reservedCommands, args[0]) {
+		return "", nil
+	}
+	if found, _, err := rootCmd.Find(args); err == nil && found != rootCmd {
+		return "", nil
+	}
+
+	var words []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			break
+		}
+		words = append(words, a)
+	}
+	for n := len(words); n > 0; n-- {
+		path, err := exec.LookPath(pluginPrefix + strings.Join(words[:n], "-"))
+		if err == nil {
+			return path, args[n:]
+		}
+	}
+	return "", nil
+}
+
+// pluginEnv returns the environment for a plugin: the current one with the
+// connection of the active context filled in. A missing or unreadable
+// config is not an error, plugins may not need the API at all.
+func pluginEnv() []string {
+	env := os.Environ()
+	if self, err := os.Executable(); err == nil {
+		env = append(env, EnvBinary+"="+self)
+	}
+	cfg, err := internal.LoadConfig()
+	if err != nil {
+		return env
+	}
+	set := map[string]string{
+		internal.EnvAPIURL:   cfg.APIBaseURL,
+		internal.EnvUsername: cfg.Username,
+		internal.EnvPassword: cfg.Password,
+	}
+	if file, err := internal.LoadConfigFile(); err == nil {
+		set[internal.EnvContext] = file.SelectedContext()
+	}
+	for key, value := range set {
+		if value != "" && os.Getenv(key) == "" {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+// runPlugin runs the plugin for args, if there is one, with the terminal
+// attached. It reports whether a plugin ran and its exit code.
+func runPlugin(args []string) (bool, int) {
+	path, rest := lookupPlugin(args)
+	if path == "" {
+		return false, 0
+	}
+
+	c := exec.Command(path, rest...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = pluginEnv()
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return true, exitErr.ExitCode()
+		}
+		internal.LogErrorf("Failed to run plugin %s: %v", path, err)
+		return true, 1
+	}
+	return true, 0
+}
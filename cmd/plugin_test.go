@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal/testserver"
+)
+
+// writePlugin puts an executable haproxyctl-<name> script in dir that
+// records its arguments and API URL in out.
+func writePlugin(t *testing.T, dir, name, out string) string {
+	t.Helper()
+	path := filepath.Join(dir, pluginPrefix+name)
+	script := "#!/bin/sh\necho \"$*|$HAPROXYCTL_API_URL\" > " + out + "\nexit 3\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunPlugin(t *testing.T) {
+	srv := testserver.New(t)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	writePlugin(t, dir, "audit", out)
+	nested := writePlugin(t, dir, "audit-certs", out)
+	t.Setenv("PATH", dir)
+	t.Setenv("HAPROXYCTL_API_URL", "")
+
+	ran, code := runPlugin([]string{"audit", "certs", "--all", "extra"})
+	if !ran || code != 3 {
+		t.Fatalf("runPlugin = %v, %d; want true, 3", ran, code)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "--all extra|"+srv.URL; got != want {
+		t.Fatalf("plugin saw %q, want %q", got, want)
+	}
+
+	if path, rest := lookupPlugin([]string{"audit", "backends"}); path == nested || len(rest) != 1 {
+		t.Fatalf("lookupPlugin(audit backends) = %q, %v", path, rest)
+	}
+	for _, args := range [][]string{{"get", "backends"}, {"help"}, {"--offline", "audit"}, {"missing"}, nil} {
+		if ran, _ := runPlugin(args); ran {
+			t.Errorf("runPlugin(%v) ran a plugin", args)
+		}
+	}
+	if plugins := findPlugins([]string{dir}); len(plugins) != 2 {
+		t.Fatalf("findPlugins = %v", plugins)
+	}
+}
@@ -20,8 +20,6 @@ package reloads
 import (
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
 
 	"haproxyctl/internal"
 
@@ -66,18 +64,17 @@ func getReloads(cmd *cobra.Command, id string) {
 
 	if err != nil {
 		if id != "" && internal.IsNotFoundError(err) {
-			_, _ = fmt.Fprintln(os.Stdout, internal.ResourceID("Reload", id)+" not found")
-			return
+			internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Reload", id))
 		}
-		log.Fatalf("Failed to fetch reload(s): %v", err)
+		internal.Fatalf("Failed to fetch reload(s): %v", err)
 	}
 
-	internal.FormatOutput(data, outputFormat)
+	internal.FormatOutputForCmd(cmd, data, outputFormat)
 }
 
 // getReloadsListFromAPI fetches the list of reloads.
 func getReloadsListFromAPI(cmd *cobra.Command) ([]map[string]interface{}, error) {
-	data, err := internal.SendRequestWithContext(cmd.Context(), "GET", "/services/haproxy/reloads", nil, nil)
+	data, err := internal.SendRequestWithContext(cmd.Context(), "GET", reloadsPath, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch reloads: %w", err)
 	}
@@ -89,13 +86,14 @@ func getReloadsListFromAPI(cmd *cobra.Command) ([]map[string]interface{}, error)
 
 	// Ensure deterministic ordering by reload ID when present.
 	internal.SortByStringField(list, "id")
+	internal.SortForCmd(cmd, list)
 
 	return list, nil
 }
 
 // getReloadFromAPI fetches a single reload by id.
 func getReloadFromAPI(cmd *cobra.Command, id string) (map[string]interface{}, error) {
-	data, err := internal.SendRequestWithContext(cmd.Context(), "GET", "/services/haproxy/reloads/"+id, nil, nil)
+	data, err := internal.SendRequestWithContext(cmd.Context(), "GET", reloadsPath+"/"+id, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch reload %q: %w", id, err)
 	}
@@ -0,0 +1,181 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reloads provides commands to inspect HAProxy reload history.
+package reloads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	reloadKind  = "Reload"
+	reloadsPath = "/services/haproxy/reloads"
+
+	statusSucceeded = "succeeded"
+	statusFailed    = "failed"
+)
+
+// ReloadCmd represents "reload".
+var ReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload HAProxy and wait for the reload to finish",
+	Long: `Make the Data Plane API reload HAProxy by committing an empty
+transaction, then poll the reload until it succeeds or fails. A failed
+reload prints HAProxy's output and exits with status 1.
+
+With --no-wait only the reload id is printed; "haproxyctl reload status"
+waits for it later.
+
+Examples:
+  haproxyctl reload
+  haproxyctl reload --timeout 2m
+  haproxyctl reload --no-wait`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		id, err := TriggerReload(cmd.Context())
+		if err != nil {
+			internal.Fatalf("Failed to reload HAProxy: %v", err)
+		}
+		if id == "" {
+			internal.PrintStatus(reloadKind, "haproxy", statusSucceeded)
+			return
+		}
+		if internal.GetFlagBool(cmd, "no-wait") {
+			internal.PrintStatus(reloadKind, id, "started")
+			return
+		}
+		waitAndReport(cmd, id)
+	},
+}
+
+// StatusReloadCmd represents "reload status <id>".
+var StatusReloadCmd = &cobra.Command{
+	Use:   "status <reload_id>",
+	Short: "Wait for a reload to finish and report its outcome",
+	Long: `Poll a reload until it succeeds or fails, for example one started by
+"haproxyctl reload --no-wait" or by a change (see "haproxyctl get
+reloads"). A failed reload prints HAProxy's output and exits with status 1.
+
+Examples:
+  haproxyctl reload status 2025-01-01-3`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		waitAndReport(cmd, args[0])
+	},
+}
+
+func init() {
+	ReloadCmd.Flags().Bool("no-wait", false, "Print the reload id without waiting for the reload")
+	for _, c := range []*cobra.Command{ReloadCmd, StatusReloadCmd} {
+		c.Flags().Duration("timeout", time.Minute, "How long to wait for the reload to finish")
+		c.Flags().Duration("interval", time.Second, "How often to poll the reload")
+	}
+	ReloadCmd.AddCommand(StatusReloadCmd)
+}
+
+// Reload is a reload as /services/haproxy/reloads reports it.
+//
+//nolint:tagliatelle // Data Plane API field names
+type Reload struct {
+	ID              string `json:"id"`
+	Status          string `json:"status"`
+	Response        string `json:"response,omitempty"`
+	ReloadTimestamp int64  `json:"reload_timestamp,omitempty"`
+}
+
+// TriggerReload commits an empty transaction, which makes the Data Plane
+// API reload HAProxy, and returns the id of that reload ("" when the API
+// reloaded synchronously and did not name it).
+func TriggerReload(ctx context.Context) (string, error) {
+	tx, err := internal.OpenTransaction()
+	if err != nil {
+		return "", err
+	}
+	id, err := tx.CommitForReload(ctx)
+	if err != nil {
+		if abortErr := tx.Abort(); abortErr != nil {
+			return "", errors.Join(err, abortErr)
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+// WaitForReload polls a reload every interval until it is no longer in
+// progress or timeout passes.
+func WaitForReload(ctx context.Context, id string, interval, timeout time.Duration) (Reload, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		data, err := internal.SendRequestWithContext(ctx, "GET", reloadsPath+"/"+id, nil, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return Reload{}, fmt.Errorf("reload %s did not finish within %s", id, timeout)
+			}
+			return Reload{}, fmt.Errorf("failed to fetch reload %q: %w", id, err)
+		}
+		var r Reload
+		if err := json.Unmarshal(data, &r); err != nil {
+			return Reload{}, fmt.Errorf("failed to parse reload response: %w", err)
+		}
+		if r.Status == statusSucceeded || r.Status == statusFailed {
+			return r, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return r, fmt.Errorf("reload %s did not finish within %s (status %s)", id, timeout, r.Status)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// waitAndReport waits for a reload with the command's --timeout and
+// --interval and reports the outcome.
+func waitAndReport(cmd *cobra.Command, id string) {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	WaitAndReport(cmd.Context(), id, interval, timeout)
+}
+
+// WaitAndReport waits for a reload and prints its outcome. A failed
+// reload is reported as an error with HAProxy's output, exiting with
+// status 1.
+func WaitAndReport(ctx context.Context, id string, interval, timeout time.Duration) {
+	r, err := WaitForReload(ctx, id, interval, timeout)
+	if err != nil {
+		internal.Fatalf("Failed to wait for reload: %v", err)
+	}
+	internal.PrintStatus(reloadKind, r.ID, r.Status)
+	if r.Status == statusFailed {
+		msg := internal.ResourceID(reloadKind, r.ID) + " failed"
+		if r.Response != "" {
+			msg += ":\n" + r.Response
+		}
+		os.Exit(internal.ReportError(msg, nil))
+	}
+}
@@ -0,0 +1,47 @@
+package reloads
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"haproxyctl/internal/testserver"
+)
+
+func TestTriggerReload_WaitsForSuccess(t *testing.T) {
+	srv := testserver.New(t)
+	ctx := context.Background()
+
+	id, err := TriggerReload(ctx)
+	if err != nil || id == "" {
+		t.Fatalf("TriggerReload = %q, %v", id, err)
+	}
+	if srv.ReloadCount() != 1 {
+		t.Fatalf("reloads = %d, want 1", srv.ReloadCount())
+	}
+
+	r, err := WaitForReload(ctx, id, time.Millisecond, time.Second)
+	if err != nil || r.ID != id || r.Status != statusSucceeded {
+		t.Fatalf("WaitForReload = %+v, %v", r, err)
+	}
+}
+
+func TestWaitForReload_ReportsFailure(t *testing.T) {
+	srv := testserver.New(t)
+	srv.FailNextReload("[ALERT] config : parsing [/etc/haproxy/haproxy.cfg:12] : unknown keyword 'bogus'")
+	ctx := context.Background()
+
+	id, err := TriggerReload(ctx)
+	if err != nil {
+		t.Fatalf("TriggerReload failed: %v", err)
+	}
+	r, err := WaitForReload(ctx, id, time.Millisecond, time.Second)
+	if err != nil || r.Status != statusFailed || !strings.Contains(r.Response, "unknown keyword") {
+		t.Fatalf("WaitForReload = %+v, %v", r, err)
+	}
+
+	if _, err := WaitForReload(ctx, "missing", time.Millisecond, time.Second); err == nil {
+		t.Fatal("expected an error for an unknown reload")
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/certificates"
+
+	"github.com/spf13/cobra"
+)
+
+// renewCmd represents the "renew" command, which obtains fresh
+// certificates from an ACME CA.
+var renewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "Obtain or renew a resource from an external issuer",
+	Long: `Obtain or renew a resource from an external issuer, such as a certificate
+from an ACME CA like Let's Encrypt.
+
+Examples:
+  haproxyctl renew certificates site -d example.com --http-frontend web`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renewCmd)
+
+	renewCmd.AddCommand(certificates.RenewCertificatesCmd)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolvers
+
+import (
+	"fmt"
+	"reflect"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ApplyResolverFromYAML applies a Resolver manifest in a declarative way:
+// the section is created when missing, otherwise replaced in place, and its
+// nameservers are reconciled by name. Everything happens in one transaction.
+func ApplyResolverFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest ResolverManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse resolver manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid resolver configuration: %w", err)
+	}
+	manifest.normalize()
+
+	if outputFormat != "" || dryRun {
+		if outputFormat == "" {
+			internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		} else {
+			internal.FormatOutput(manifest.toAPIPayload(), outputFormat)
+		}
+		if dryRun {
+			internal.PrintDryRun()
+		}
+		return nil
+	}
+
+	name := manifest.Name
+	current, err := getResolverManifest(name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return fmt.Errorf("failed to check resolver existence: %w", err)
+	}
+	exists := err == nil
+
+	if exists && reflect.DeepEqual(*current, manifest) {
+		internal.PrintStatus(resolverKind, name, internal.ActionUnchanged)
+		return nil
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		var before []Nameserver
+		if exists {
+			if _, err := internal.SendRequest("PUT", resolverPath(name), tx.Params(), manifest.toAPIPayload()); err != nil {
+				return fmt.Errorf("failed to update resolver %q: %w", name, err)
+			}
+			before = current.Nameservers
+		} else if _, err := internal.SendRequest("POST", resolversPath, tx.Params(), manifest.toAPIPayload()); err != nil {
+			return fmt.Errorf("failed to create resolver %q: %w", name, err)
+		}
+		return syncNameservers(tx, name, before, manifest.Nameservers)
+	})
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		internal.PrintStatus(resolverKind, name, internal.ActionConfigured)
+	} else {
+		internal.PrintStatus(resolverKind, name, internal.ActionCreated)
+	}
+	return nil
+}
+
+// syncNameservers turns the nameservers of a resolvers section from before
+// into after, matching them by name, staging the changes in tx.
+func syncNameservers(tx *internal.Transaction, resolver string, before, after []Nameserver) error {
+	live := make(map[string]Nameserver, len(before))
+	for _, n := range before {
+		live[n.Name] = n
+	}
+	wanted := make(map[string]bool, len(after))
+
+	for _, n := range after {
+		wanted[n.Name] = true
+		old, ok := live[n.Name]
+		switch {
+		case !ok:
+			if _, err := internal.SendRequest("POST", nameserversPath(resolver), tx.Params(), n.toPayload()); err != nil {
+				return fmt.Errorf("failed to create %q: %w", n.String(), err)
+			}
+		case old != n:
+			if _, err := internal.SendRequest("PUT", nameserversPath(resolver)+"/"+n.Name, tx.Params(), n.toPayload()); err != nil {
+				return fmt.Errorf("failed to update %q: %w", n.String(), err)
+			}
+		}
+	}
+	for _, n := range before {
+		if wanted[n.Name] {
+			continue
+		}
+		if _, err := internal.SendRequest("DELETE", nameserversPath(resolver)+"/"+n.Name, tx.Params(), nil); err != nil {
+			return fmt.Errorf("failed to delete %q: %w", n.String(), err)
+		}
+	}
+	return nil
+}
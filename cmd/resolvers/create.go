@@ -0,0 +1,179 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resolvers provides commands to manage HAProxy resolvers sections
+// and their nameservers.
+package resolvers
+
+import (
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// CreateResolversCmd represents "create resolvers <name>".
+var CreateResolversCmd = &cobra.Command{
+	Use:     "resolvers <name>",
+	Aliases: []string{"resolver"},
+	Short:   "Create a resolvers section",
+	Long: `Create a resolvers section, with its nameservers, in one transaction.
+Servers use it through their "resolvers" setting to follow DNS changes.
+
+Examples:
+  haproxyctl create resolvers dns \
+    --nameserver name=dns1,address=10.0.0.53,port=53 \
+    --nameserver name=dns2,address=10.0.1.53 \
+    --hold-valid 10s --resolve-retries 3
+  haproxyctl create resolvers system --parse-resolv-conf
+  haproxyctl create -f resolver.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifest := ResolverManifest{
+			APIVersion:          apiVersionV1,
+			Kind:                resolverKind,
+			Name:                args[0],
+			ParseResolvConf:     internal.GetFlagBool(cmd, "parse-resolv-conf"),
+			ResolveRetries:      internal.GetFlagInt(cmd, "resolve-retries"),
+			TimeoutResolve:      internal.GetFlagString(cmd, "timeout-resolve"),
+			TimeoutRetry:        internal.GetFlagString(cmd, "timeout-retry"),
+			HoldValid:           internal.GetFlagString(cmd, "hold-valid"),
+			HoldNx:              internal.GetFlagString(cmd, "hold-nx"),
+			HoldObsolete:        internal.GetFlagString(cmd, "hold-obsolete"),
+			AcceptedPayloadSize: internal.GetFlagInt(cmd, "accepted-payload-size"),
+		}
+		for _, raw := range internal.GetFlagStringSlice(cmd, "nameserver") {
+			n, err := parseNameserverFlag(raw)
+			if err != nil {
+				internal.FatalCodef(internal.ExitUsage, "Invalid --nameserver: %v", err)
+			}
+			manifest.Nameservers = append(manifest.Nameservers, n)
+		}
+
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := createResolver(manifest, dryRun); err != nil {
+			internal.Fatalf("Failed to create resolver %q: %v", args[0], err)
+		}
+	},
+}
+
+// CreateNameserverCmd represents "create nameservers <resolver>".
+var CreateNameserverCmd = &cobra.Command{
+	Use:     "nameservers <resolver>",
+	Aliases: []string{"nameserver"},
+	Short:   "Add a nameserver to a resolvers section",
+	Long: `Add a "nameserver <name> <address>:<port>" line to an existing
+resolvers section.
+
+Examples:
+  haproxyctl create nameservers dns --name dns3 --address 10.0.2.53
+  haproxyctl create nameservers dns --name local --address 127.0.0.1 --port 5353`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := Nameserver{
+			Name:    internal.GetFlagString(cmd, "name"),
+			Address: internal.GetFlagString(cmd, "address"),
+			Port:    internal.GetFlagInt(cmd, "port"),
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateNameserver(args[0], n, dryRun); err != nil {
+			internal.Fatalf("Failed to create nameserver on resolver %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateResolversCmd.Flags().StringArray("nameserver", nil, "Nameserver as name=dns1,address=10.0.0.53[,port=53] (repeatable)")
+	CreateResolversCmd.Flags().Bool("parse-resolv-conf", false, "Add the nameservers of /etc/resolv.conf")
+	CreateResolversCmd.Flags().Int("resolve-retries", 0, "Queries sent before giving up on a name")
+	CreateResolversCmd.Flags().String("timeout-resolve", "", "Time between two resolutions (e.g., 1s)")
+	CreateResolversCmd.Flags().String("timeout-retry", "", "Time to wait for an answer before retrying (e.g., 1s)")
+	CreateResolversCmd.Flags().String("hold-valid", "", "How long a valid answer is kept (e.g., 10s)")
+	CreateResolversCmd.Flags().String("hold-nx", "", "How long an NXDOMAIN answer is kept (e.g., 30s)")
+	CreateResolversCmd.Flags().String("hold-obsolete", "", "How long an address missing from answers is kept (e.g., 30s)")
+	CreateResolversCmd.Flags().Int("accepted-payload-size", 0, "Maximum DNS answer size advertised with EDNS0 (512-65535)")
+	CreateResolversCmd.Flags().Bool("dry-run", false, "Print the manifest without creating it")
+
+	CreateNameserverCmd.Flags().String("name", "", "Nameserver name (required)")
+	CreateNameserverCmd.Flags().String("address", "", "Nameserver IP address (required)")
+	CreateNameserverCmd.Flags().Int("port", 53, "Nameserver port")
+	CreateNameserverCmd.Flags().Bool("dry-run", false, "Print the nameserver without creating it")
+}
+
+// CreateResolverFromFile is used for "haproxyctl create -f resolver.yaml".
+func CreateResolverFromFile(data []byte) error {
+	var manifest ResolverManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse resolver manifest: %w", err)
+	}
+	if err := createResolver(manifest, internal.IsOffline()); err != nil {
+		return internal.FormatAPIError(resolverKind, manifest.Name, "create", err)
+	}
+	return nil
+}
+
+// createResolver creates a resolvers section and its nameservers in one
+// transaction.
+func createResolver(manifest ResolverManifest, dryRun bool) error {
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	manifest.normalize()
+
+	if dryRun {
+		internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	err := internal.WithTransaction(func(tx *internal.Transaction) error {
+		if _, err := internal.SendRequest("POST", resolversPath, tx.Params(), manifest.toAPIPayload()); err != nil {
+			return err
+		}
+		return syncNameservers(tx, manifest.Name, nil, manifest.Nameservers)
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus(resolverKind, manifest.Name, internal.ActionCreated)
+	return nil
+}
+
+// CreateNameserver adds a nameserver to an existing resolvers section.
+func CreateNameserver(resolver string, n Nameserver, dryRun bool) error {
+	if err := n.validate(); err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Println(n.String())
+		internal.PrintDryRun()
+		return nil
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", nameserversPath(resolver), params, n.toPayload()); err != nil {
+		return internal.FormatAPIError("Nameserver", resolver+"/"+n.Name, "create", err)
+	}
+
+	internal.PrintStatus("Nameserver", resolver+"/"+n.Name, internal.ActionCreated)
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resolvers provides commands to manage HAProxy resolvers sections
+// and their nameservers.
+package resolvers
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteResolversCmd represents "delete resolvers <name>".
+var DeleteResolversCmd = &cobra.Command{
+	Use:     "resolvers <name>",
+	Aliases: []string{"resolver"},
+	Short:   "Delete a resolvers section and its nameservers",
+	Long: `Delete a resolvers section and its nameservers. Servers that still
+refer to it must be updated first, or HAProxy rejects the configuration.
+
+Examples:
+  haproxyctl delete resolvers dns`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := DeleteResolverByName(args[0]); err != nil {
+			internal.Fatalf("Failed to delete resolver %q: %v", args[0], err)
+		}
+	},
+}
+
+// DeleteNameserverCmd represents "delete nameservers <resolver> <name>".
+var DeleteNameserverCmd = &cobra.Command{
+	Use:     "nameservers <resolver> <name>",
+	Aliases: []string{"nameserver"},
+	Short:   "Delete a nameserver from a resolvers section",
+	Long: `Delete a nameserver from a resolvers section.
+
+Examples:
+  haproxyctl delete nameservers dns dns2`,
+	Args: cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := DeleteNameserver(args[0], args[1]); err != nil {
+			internal.Fatalf("Failed to delete nameserver %q of resolver %q: %v", args[1], args[0], err)
+		}
+	},
+}
+
+// DeleteResolverByName deletes a resolvers section.
+func DeleteResolverByName(name string) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("DELETE", resolverPath(name), params, nil); err != nil {
+		return internal.FormatAPIError(resolverKind, name, "delete", err)
+	}
+
+	internal.PrintStatus(resolverKind, name, internal.ActionDeleted)
+	return nil
+}
+
+// DeleteNameserver removes a nameserver from a resolvers section.
+func DeleteNameserver(resolver, name string) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	id := resolver + "/" + name
+	if _, err := internal.SendRequest("DELETE", nameserversPath(resolver)+"/"+name, params, nil); err != nil {
+		return internal.FormatAPIError("Nameserver", id, "delete", err)
+	}
+
+	internal.PrintStatus("Nameserver", id, internal.ActionDeleted)
+	return nil
+}
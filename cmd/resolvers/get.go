@@ -0,0 +1,167 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resolvers provides commands to manage HAProxy resolvers sections
+// and their nameservers.
+package resolvers
+
+import (
+	"fmt"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetResolversCmd represents "get resolvers".
+var GetResolversCmd = &cobra.Command{
+	Use:     "resolvers [name]",
+	Aliases: []string{"resolver"},
+	Short:   "List HAProxy resolvers sections or fetch details of one",
+	Long: `List the resolvers sections used for DNS-based server discovery, or
+show one section with its nameservers. With -o yaml the output is a Resolver
+manifest that apply accepts.
+
+Examples:
+  haproxyctl get resolvers
+  haproxyctl get resolvers dns
+  haproxyctl get resolvers dns -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFormat := internal.GetFlagString(cmd, "output")
+
+		if len(args) == 0 {
+			manifests, err := ResolverManifests()
+			if err != nil {
+				internal.Fatalf("Failed to fetch resolvers: %v", err)
+			}
+			if outputFormat != "" && outputFormat != "table" && outputFormat != internal.OutputFormatWide {
+				internal.FormatOutputForCmd(cmd, internal.WithColumns(manifests, internal.ColumnSet{Kind: resolverKind}), outputFormat)
+				return
+			}
+			rows := make([]map[string]interface{}, 0, len(manifests))
+			for _, item := range manifests {
+				rows = append(rows, summaryRow(item.(*ResolverManifest)))
+			}
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, resolverColumns), outputFormat)
+			return
+		}
+
+		manifest, err := getResolverManifest(args[0])
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(resolverKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch resolver %q: %v", args[0], err)
+		}
+		if outputFormat == "" || outputFormat == "table" || outputFormat == internal.OutputFormatWide {
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(summaryRow(manifest), resolverColumns), outputFormat)
+			return
+		}
+		internal.FormatOutputForCmd(cmd, manifest, outputFormat)
+	},
+}
+
+// GetNameserversCmd represents "get nameservers <resolver>".
+var GetNameserversCmd = &cobra.Command{
+	Use:     "nameservers <resolver>",
+	Aliases: []string{"nameserver"},
+	Short:   "List the nameservers of a resolvers section",
+	Long: `List the nameservers of a resolvers section.
+
+Examples:
+  haproxyctl get nameservers dns`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		list, err := internal.GetResourceList(nameserversPath(args[0]))
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(resolverKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch nameservers of resolver %q: %v", args[0], err)
+		}
+		internal.SortByStringField(list, "name")
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(list, nameserverColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// resolverColumns shows a section with its nameservers and main timers.
+var resolverColumns = internal.ColumnSet{
+	Kind:    resolverKind,
+	Default: []string{"name", "nameservers", "resolve_retries", "timeout_resolve", "hold_valid"},
+	Wide:    []string{"parse_resolv_conf", "timeout_retry", "hold_nx", "hold_obsolete", "accepted_payload_size"},
+}
+
+// nameserverColumns shows each nameserver as name, address and port.
+var nameserverColumns = internal.ColumnSet{
+	Kind:    "Nameserver",
+	Default: []string{"name", "address", "port"},
+}
+
+// summaryRow flattens a manifest into a table row.
+func summaryRow(m *ResolverManifest) map[string]interface{} {
+	servers := make([]string, 0, len(m.Nameservers))
+	for _, n := range m.Nameservers {
+		servers = append(servers, fmt.Sprintf("%s=%s:%d", n.Name, n.Address, n.Port))
+	}
+	return map[string]interface{}{
+		"name":                  m.Name,
+		"nameservers":           strings.Join(servers, ","),
+		"parse_resolv_conf":     m.ParseResolvConf,
+		"resolve_retries":       m.ResolveRetries,
+		"timeout_resolve":       m.TimeoutResolve,
+		"timeout_retry":         m.TimeoutRetry,
+		"hold_valid":            m.HoldValid,
+		"hold_nx":               m.HoldNx,
+		"hold_obsolete":         m.HoldObsolete,
+		"accepted_payload_size": m.AcceptedPayloadSize,
+	}
+}
+
+// getResolverManifest fetches a resolvers section and its nameservers.
+func getResolverManifest(name string) (*ResolverManifest, error) {
+	obj, err := internal.GetResource(resolverPath(name))
+	if err != nil {
+		return nil, err
+	}
+	nameservers, err := internal.GetResourceList(nameserversPath(name))
+	if err != nil && !internal.IsNotFoundError(err) {
+		return nil, fmt.Errorf("failed to fetch nameservers of resolver %q: %w", name, err)
+	}
+	return manifestFromAPI(obj, nameservers), nil
+}
+
+// ResolverManifests returns every resolvers section, with its nameservers,
+// as a manifest that apply accepts, sorted by name.
+func ResolverManifests() ([]interface{}, error) {
+	list, err := internal.GetResourceList(resolversPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch resolvers: %w", err)
+	}
+	internal.SortByStringField(list, "name")
+
+	manifests := make([]interface{}, 0, len(list))
+	for _, raw := range list {
+		name, _ := raw["name"].(string)
+		manifest, err := getResolverManifest(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch resolver %q: %w", name, err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,137 @@
+package resolvers
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testResolverManifest = `apiVersion: haproxyctl/v1
+kind: Resolver
+name: dns
+resolve_retries: 3
+hold_valid: 10s
+nameservers:
+  - name: dns1
+    address: 10.0.0.53
+    port: 53
+  - name: dns2
+    address: 10.0.1.53
+    port: 53
+`
+
+func nameserverSummary(srv *testserver.Server) string {
+	var out []string
+	for _, n := range srv.Nameservers("dns") {
+		out = append(out, n["name"].(string)+"="+n["address"].(string))
+	}
+	return strings.Join(out, ",")
+}
+
+func TestApplyResolverFromYAML(t *testing.T) {
+	srv := testserver.New(t)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyResolverFromYAML([]byte(testResolverManifest), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "resolver/dns created") {
+		t.Fatalf("expected created status, got:\n%s", output)
+	}
+	section, ok := srv.Resolver("dns")
+	if !ok || section["hold_valid"] != float64(10000) || section["resolve_retries"] != float64(3) {
+		t.Fatalf("resolver not sent in API form: %+v", section)
+	}
+	if got := nameserverSummary(srv); got != "dns1=10.0.0.53,dns2=10.0.1.53" {
+		t.Fatalf("nameservers = %s", got)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyResolverFromYAML([]byte(testResolverManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "resolver/dns unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	changed := strings.Replace(testResolverManifest, `  - name: dns2
+    address: 10.0.1.53`, `  - name: dns3
+    address: 10.0.2.53`, 1)
+	version := srv.Version()
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyResolverFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	if got := nameserverSummary(srv); got != "dns1=10.0.0.53,dns3=10.0.2.53" {
+		t.Fatalf("nameservers after change = %s", got)
+	}
+	if srv.Version() != version+1 {
+		t.Fatalf("apply used %d versions, want one transaction", srv.Version()-version)
+	}
+	if n := srv.CountRequests("PUT", "/v3/services/haproxy/configuration/resolvers/dns/nameservers/dns1"); n != 0 {
+		t.Fatalf("unchanged nameserver was replaced %d times", n)
+	}
+}
+
+func TestResolverValidate(t *testing.T) {
+	valid := ResolverManifest{Name: "dns", Nameservers: []Nameserver{{Name: "dns1", Address: "10.0.0.53", Port: 53}}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]ResolverManifest{
+		"missing name":   {},
+		"missing port":   {Name: "dns", Nameservers: []Nameserver{{Name: "dns1", Address: "10.0.0.53"}}},
+		"duplicate":      {Name: "dns", Nameservers: []Nameserver{{Name: "a", Address: "1.1.1.1", Port: 53}, {Name: "a", Address: "8.8.8.8", Port: 53}}},
+		"bad duration":   {Name: "dns", HoldValid: "soon"},
+		"payload size":   {Name: "dns", AcceptedPayloadSize: 100},
+		"wrong kind":     {Name: "dns", Kind: "Userlist"},
+		"bad apiVersion": {Name: "dns", APIVersion: "v2"},
+	}
+	for name, m := range cases {
+		if err := m.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseNameserverFlag(t *testing.T) {
+	n, err := parseNameserverFlag("name=dns1,address=10.0.0.53")
+	if err != nil || n != (Nameserver{Name: "dns1", Address: "10.0.0.53", Port: 53}) {
+		t.Fatalf("parseNameserverFlag = %+v, %v", n, err)
+	}
+	if _, err := parseNameserverFlag("name=dns1,addr=10.0.0.53"); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestCreateAndDeleteNameserver(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddResolver(map[string]interface{}{"name": "dns"})
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := CreateNameserver("dns", Nameserver{Name: "dns1", Address: "10.0.0.53", Port: 53}, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	if got := nameserverSummary(srv); got != "dns1=10.0.0.53" {
+		t.Fatalf("nameservers = %s", got)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteResolverByName("dns"); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "resolver/dns deleted") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if _, ok := srv.Resolver("dns"); ok {
+		t.Fatal("resolver still present after delete")
+	}
+}
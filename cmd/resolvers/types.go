@@ -0,0 +1,227 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resolvers provides commands to manage HAProxy resolvers sections
+// and their nameservers.
+package resolvers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	apiVersionV1 = "haproxyctl/v1"
+	resolverKind = "Resolver"
+
+	resolversPath = "/services/haproxy/configuration/resolvers"
+)
+
+// ResolverManifest is the manifest view of a "resolvers <name>" section and
+// its nameservers. Durations are written like the backend timeouts (e.g.
+// 1s, 10s) and sent to the API in milliseconds.
+//
+//nolint:tagliatelle // manifest uses HAProxy's snake_case names like backendConfig
+type ResolverManifest struct {
+	APIVersion          string       `json:"apiVersion" yaml:"apiVersion"`
+	Kind                string       `json:"kind" yaml:"kind"`
+	Name                string       `json:"name" yaml:"name"`
+	Nameservers         []Nameserver `json:"nameservers,omitempty" yaml:"nameservers,omitempty"`
+	ParseResolvConf     bool         `json:"parse_resolv_conf,omitempty" yaml:"parse_resolv_conf,omitempty"`
+	ResolveRetries      int          `json:"resolve_retries,omitempty" yaml:"resolve_retries,omitempty"`
+	TimeoutResolve      string       `json:"timeout_resolve,omitempty" yaml:"timeout_resolve,omitempty"`
+	TimeoutRetry        string       `json:"timeout_retry,omitempty" yaml:"timeout_retry,omitempty"`
+	HoldValid           string       `json:"hold_valid,omitempty" yaml:"hold_valid,omitempty"`
+	HoldNx              string       `json:"hold_nx,omitempty" yaml:"hold_nx,omitempty"`
+	HoldObsolete        string       `json:"hold_obsolete,omitempty" yaml:"hold_obsolete,omitempty"`
+	AcceptedPayloadSize int          `json:"accepted_payload_size,omitempty" yaml:"accepted_payload_size,omitempty"`
+}
+
+// Nameserver is a "nameserver <name> <address>:<port>" line.
+type Nameserver struct {
+	Name    string `json:"name" yaml:"name"`
+	Address string `json:"address" yaml:"address"`
+	Port    int    `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// String renders the nameserver the way it appears in haproxy.cfg.
+func (n Nameserver) String() string {
+	return fmt.Sprintf("nameserver %s %s:%d", n.Name, n.Address, n.Port)
+}
+
+func (n Nameserver) validate() error {
+	if n.Name == "" || n.Address == "" {
+		return fmt.Errorf("nameserver %+v must have name and address", n)
+	}
+	if n.Port <= 0 || n.Port > 65535 {
+		return fmt.Errorf("%q: invalid port %d", n.String(), n.Port)
+	}
+	return nil
+}
+
+func (n Nameserver) toPayload() map[string]interface{} {
+	return map[string]interface{}{"name": n.Name, "address": n.Address, "port": n.Port}
+}
+
+// nameserverFromAPI converts an API nameserver object.
+func nameserverFromAPI(obj map[string]interface{}) Nameserver {
+	var n Nameserver
+	n.Name, _ = obj["name"].(string)
+	n.Address, _ = obj["address"].(string)
+	n.Port, _ = internal.GetIntField(obj, "port")
+	return n
+}
+
+// parseNameserverFlag parses "name=dns1,address=10.0.0.53,port=53". The
+// port defaults to 53.
+func parseNameserverFlag(s string) (Nameserver, error) {
+	n := Nameserver{Port: 53}
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return n, fmt.Errorf("invalid nameserver %q (expected name=...,address=...[,port=...])", s)
+		}
+		switch strings.TrimSpace(key) {
+		case "name":
+			n.Name = strings.TrimSpace(value)
+		case "address":
+			n.Address = strings.TrimSpace(value)
+		case "port":
+			port, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return n, fmt.Errorf("invalid nameserver port %q", value)
+			}
+			n.Port = port
+		default:
+			return n, fmt.Errorf("unknown nameserver field %q", key)
+		}
+	}
+	return n, nil
+}
+
+// Validate checks the manifest before anything is sent to the API.
+func (m *ResolverManifest) Validate() error {
+	if m.Name == "" {
+		return errors.New("resolver name is required")
+	}
+	if m.Kind != "" && m.Kind != resolverKind {
+		return fmt.Errorf("kind must be %q", resolverKind)
+	}
+	if m.APIVersion != "" && m.APIVersion != apiVersionV1 {
+		return fmt.Errorf("apiVersion must be %q", apiVersionV1)
+	}
+	seen := make(map[string]bool, len(m.Nameservers))
+	for _, n := range m.Nameservers {
+		if err := n.validate(); err != nil {
+			return err
+		}
+		if seen[n.Name] {
+			return fmt.Errorf("duplicate nameserver %q", n.Name)
+		}
+		seen[n.Name] = true
+	}
+	if m.ResolveRetries < 0 || m.AcceptedPayloadSize < 0 {
+		return errors.New("resolve_retries and accepted_payload_size must not be negative")
+	}
+	if m.AcceptedPayloadSize != 0 && (m.AcceptedPayloadSize < 512 || m.AcceptedPayloadSize > 65535) {
+		return fmt.Errorf("accepted_payload_size %d out of range (512-65535)", m.AcceptedPayloadSize)
+	}
+	for field, value := range m.durations() {
+		if _, err := internal.ParseDurationToMillis(*value); err != nil {
+			return fmt.Errorf("invalid %s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// durations returns the duration fields by their API name.
+func (m *ResolverManifest) durations() map[string]*string {
+	return map[string]*string{
+		"timeout_resolve": &m.TimeoutResolve,
+		"timeout_retry":   &m.TimeoutRetry,
+		"hold_valid":      &m.HoldValid,
+		"hold_nx":         &m.HoldNx,
+		"hold_obsolete":   &m.HoldObsolete,
+	}
+}
+
+// toAPIPayload converts the section fields (not the nameservers) into the
+// object expected by the resolvers endpoint. It must be called on a
+// validated manifest.
+func (m *ResolverManifest) toAPIPayload() map[string]interface{} {
+	payload := map[string]interface{}{"name": m.Name}
+	if m.ParseResolvConf {
+		payload["parse-resolv-conf"] = true
+	}
+	if m.ResolveRetries > 0 {
+		payload["resolve_retries"] = m.ResolveRetries
+	}
+	if m.AcceptedPayloadSize > 0 {
+		payload["accepted_payload_size"] = m.AcceptedPayloadSize
+	}
+	for field, value := range m.durations() {
+		if ms, _ := internal.ParseDurationToMillis(*value); ms > 0 {
+			payload[field] = ms
+		}
+	}
+	return payload
+}
+
+// normalize fills in kind and apiVersion, renders durations the way they
+// come back from the API and sorts the nameservers by name, so manifests
+// from files and from the API compare equal.
+func (m *ResolverManifest) normalize() {
+	m.APIVersion, m.Kind = apiVersionV1, resolverKind
+	for _, value := range m.durations() {
+		if ms, err := internal.ParseDurationToMillis(*value); err == nil {
+			*value = internal.FormatMillisAsDuration(ms)
+		}
+	}
+	sort.Slice(m.Nameservers, func(i, j int) bool { return m.Nameservers[i].Name < m.Nameservers[j].Name })
+}
+
+// manifestFromAPI converts an API resolvers object and its nameservers into
+// a manifest.
+func manifestFromAPI(obj map[string]interface{}, nameservers []map[string]interface{}) *ResolverManifest {
+	m := &ResolverManifest{}
+	m.Name, _ = obj["name"].(string)
+	m.ParseResolvConf, _ = obj["parse-resolv-conf"].(bool)
+	m.ResolveRetries, _ = internal.GetIntField(obj, "resolve_retries")
+	m.AcceptedPayloadSize, _ = internal.GetIntField(obj, "accepted_payload_size")
+	for field, value := range m.durations() {
+		if ms, ok := internal.GetIntField(obj, field); ok {
+			*value = internal.FormatMillisAsDuration(ms)
+		}
+	}
+	for _, raw := range nameservers {
+		m.Nameservers = append(m.Nameservers, nameserverFromAPI(raw))
+	}
+	m.normalize()
+	return m
+}
+
+func resolverPath(name string) string {
+	return resolversPath + "/" + name
+}
+
+func nameserversPath(resolver string) string {
+	return resolverPath(resolver) + "/nameservers"
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rings
+
+import (
+	"fmt"
+	"reflect"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ApplyRingFromYAML applies a Ring manifest in a declarative way: the
+// section is created when missing, otherwise replaced in place, and its
+// servers are reconciled by name. Everything happens in one transaction.
+func ApplyRingFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest RingManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse ring manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid ring configuration: %w", err)
+	}
+	manifest.normalize()
+
+	if outputFormat != "" || dryRun {
+		if outputFormat == "" {
+			internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		} else {
+			internal.FormatOutput(manifest.toAPIPayload(), outputFormat)
+		}
+		if dryRun {
+			internal.PrintDryRun()
+		}
+		return nil
+	}
+
+	// current stays nil when the ring does not exist yet.
+	current, err := getRingManifest(manifest.Name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return fmt.Errorf("failed to check ring existence: %w", err)
+	}
+	return applyRing(current, manifest)
+}
+
+// applyRing turns current (nil when the ring does not exist yet) into the
+// validated, normalized manifest in one transaction and prints the outcome.
+func applyRing(current *RingManifest, manifest RingManifest) error {
+	name := manifest.Name
+	if current != nil && reflect.DeepEqual(*current, manifest) {
+		internal.PrintStatus(ringKind, name, internal.ActionUnchanged)
+		return nil
+	}
+
+	err := internal.WithTransaction(func(tx *internal.Transaction) error {
+		var before []RingServer
+		if current != nil {
+			if _, err := internal.SendRequest("PUT", ringPath(name), tx.Params(), manifest.toAPIPayload()); err != nil {
+				return fmt.Errorf("failed to update ring %q: %w", name, err)
+			}
+			before = current.Servers
+		} else if _, err := internal.SendRequest("POST", ringsPath, tx.Params(), manifest.toAPIPayload()); err != nil {
+			return fmt.Errorf("failed to create ring %q: %w", name, err)
+		}
+		return syncRingServers(tx, name, before, manifest.Servers)
+	})
+	if err != nil {
+		return err
+	}
+
+	if current != nil {
+		internal.PrintStatus(ringKind, name, internal.ActionConfigured)
+	} else {
+		internal.PrintStatus(ringKind, name, internal.ActionCreated)
+	}
+	return nil
+}
+
+// syncRingServers turns the servers of a ring from before into after,
+// matching them by name, staging the changes in tx.
+func syncRingServers(tx *internal.Transaction, ring string, before, after []RingServer) error {
+	live := make(map[string]RingServer, len(before))
+	for _, s := range before {
+		live[s.Name] = s
+	}
+	wanted := make(map[string]bool, len(after))
+
+	for _, s := range after {
+		wanted[s.Name] = true
+		old, ok := live[s.Name]
+		switch {
+		case !ok:
+			if _, err := internal.SendRequest("POST", ringServersPath(ring), tx.Params(), s.toPayload()); err != nil {
+				return fmt.Errorf("failed to create %q: %w", s.String(), err)
+			}
+		case old != s:
+			if _, err := internal.SendRequest("PUT", ringServersPath(ring)+"/"+s.Name, tx.Params(), s.toPayload()); err != nil {
+				return fmt.Errorf("failed to update %q: %w", s.String(), err)
+			}
+		}
+	}
+	for _, s := range before {
+		if wanted[s.Name] {
+			continue
+		}
+		if _, err := internal.SendRequest("DELETE", ringServersPath(ring)+"/"+s.Name, tx.Params(), nil); err != nil {
+			return fmt.Errorf("failed to delete %q: %w", s.String(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rings provides commands to manage HAProxy ring sections, the
+// in-memory buffers used as log sinks, and the servers they forward to.
+package rings
+
+import (
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// CreateRingsCmd represents "create rings <name>".
+var CreateRingsCmd = &cobra.Command{
+	Use:     "rings <name>",
+	Aliases: []string{"ring"},
+	Short:   "Create a ring buffer",
+	Long: `Create a ring section, with the servers it forwards to, in one
+transaction. Log targets write to it with the address "ring@<name>", for
+example:
+  haproxyctl create log-targets --parent global --ring logbuf --facility local0
+
+Examples:
+  haproxyctl create rings logbuf --format rfc5424 --size 32768 --maxlen 1200
+  haproxyctl create rings logbuf \
+    --server name=collector,address=10.0.0.9,port=6514,log_proto=octet-count \
+    --timeout-connect 5s --timeout-server 10s
+  haproxyctl create -f ring.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifest := RingManifest{
+			APIVersion:     apiVersionV1,
+			Kind:           ringKind,
+			Name:           args[0],
+			Description:    internal.GetFlagString(cmd, "description"),
+			Format:         internal.GetFlagString(cmd, "format"),
+			Size:           internal.GetFlagInt(cmd, "size"),
+			Maxlen:         internal.GetFlagInt(cmd, "maxlen"),
+			TimeoutConnect: internal.GetFlagString(cmd, "timeout-connect"),
+			TimeoutServer:  internal.GetFlagString(cmd, "timeout-server"),
+		}
+		for _, raw := range internal.GetFlagStringSlice(cmd, "server") {
+			s, err := parseRingServerFlag(raw)
+			if err != nil {
+				internal.FatalCodef(internal.ExitUsage, "Invalid --server: %v", err)
+			}
+			manifest.Servers = append(manifest.Servers, s)
+		}
+
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := createRing(manifest, dryRun); err != nil {
+			internal.Fatalf("Failed to create ring %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateRingsCmd.Flags().String("description", "", "Description of the ring")
+	CreateRingsCmd.Flags().String("format", "", "Log format of the messages (iso, local, raw, rfc3164, rfc5424, short, priority, timed)")
+	CreateRingsCmd.Flags().Int("size", 0, "Buffer size in bytes")
+	CreateRingsCmd.Flags().Int("maxlen", 0, "Maximum length of a message, longer ones are truncated")
+	CreateRingsCmd.Flags().String("timeout-connect", "", "Connect timeout towards the ring servers (e.g., 5s)")
+	CreateRingsCmd.Flags().String("timeout-server", "", "Server timeout towards the ring servers (e.g., 10s)")
+	CreateRingsCmd.Flags().StringArray("server", nil, "Server to forward to as name=collector,address=10.0.0.9,port=6514[,log_proto=octet-count] (repeatable)")
+	CreateRingsCmd.Flags().Bool("dry-run", false, "Print the manifest without creating it")
+}
+
+// CreateRingFromFile is used for "haproxyctl create -f ring.yaml".
+func CreateRingFromFile(data []byte) error {
+	var manifest RingManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse ring manifest: %w", err)
+	}
+	if err := createRing(manifest, internal.IsOffline()); err != nil {
+		return internal.FormatAPIError(ringKind, manifest.Name, "create", err)
+	}
+	return nil
+}
+
+// createRing creates a ring section and its servers in one transaction.
+func createRing(manifest RingManifest, dryRun bool) error {
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	manifest.normalize()
+
+	if dryRun {
+		internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	err := internal.WithTransaction(func(tx *internal.Transaction) error {
+		if _, err := internal.SendRequest("POST", ringsPath, tx.Params(), manifest.toAPIPayload()); err != nil {
+			return err
+		}
+		return syncRingServers(tx, manifest.Name, nil, manifest.Servers)
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus(ringKind, manifest.Name, internal.ActionCreated)
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rings provides commands to manage HAProxy ring sections, the
+// in-memory buffers used as log sinks, and the servers they forward to.
+package rings
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteRingsCmd represents "delete rings <name>".
+var DeleteRingsCmd = &cobra.Command{
+	Use:     "rings <name>",
+	Aliases: []string{"ring"},
+	Short:   "Delete a ring buffer and its servers",
+	Long: `Delete a ring section and its servers. Log targets that still write
+to "ring@<name>" must be removed first, or HAProxy rejects the
+configuration.
+
+Examples:
+  haproxyctl delete rings logbuf`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := DeleteRingByName(args[0]); err != nil {
+			internal.Fatalf("Failed to delete ring %q: %v", args[0], err)
+		}
+	},
+}
+
+// DeleteRingByName deletes a ring section.
+func DeleteRingByName(name string) error {
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("DELETE", ringPath(name), params, nil); err != nil {
+		return internal.FormatAPIError(ringKind, name, "delete", err)
+	}
+
+	internal.PrintStatus(ringKind, name, internal.ActionDeleted)
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rings provides commands to manage HAProxy ring sections, the
+// in-memory buffers used as log sinks, and the servers they forward to.
+package rings
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// EditRingsCmd represents "edit rings <name>".
+var EditRingsCmd = &cobra.Command{
+	Use:     "rings <name>",
+	Aliases: []string{"ring"},
+	Short:   "Edit a ring definition in your editor",
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := editRing(args[0]); err != nil {
+			internal.Fatalf("Edit failed: %v", err)
+		}
+	},
+}
+
+func editRing(name string) error {
+	current, err := getRingManifest(name)
+	if err != nil {
+		return internal.FormatAPIError(ringKind, name, "get", err)
+	}
+
+	origYAML, err := yaml.Marshal(current)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ring manifest to YAML: %w", err)
+	}
+
+	tmpFile, err := internal.WriteTempYAML("haproxyctl-ring-"+name+"-", current)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rmErr := os.Remove(tmpFile); rmErr != nil {
+			internal.Warnf("failed to remove temp file %q: %v", tmpFile, rmErr)
+		}
+	}()
+
+	if err := internal.OpenInEditor(tmpFile); err != nil {
+		return err
+	}
+
+	editedYAML, err := os.ReadFile(tmpFile) //nolint:gosec // tmpFile is controlled by this process
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %w", err)
+	}
+	if bytes.Equal(bytes.TrimSpace(origYAML), bytes.TrimSpace(editedYAML)) {
+		internal.PrintStatus(ringKind, name, internal.ActionUnchanged)
+		return nil
+	}
+
+	var edited RingManifest
+	if err := yaml.Unmarshal(editedYAML, &edited); err != nil {
+		return fmt.Errorf("failed to parse edited YAML: %w", err)
+	}
+	if edited.Name != name {
+		return fmt.Errorf("cannot rename ring via edit (got %q, expected %q)", edited.Name, name)
+	}
+	if err := edited.Validate(); err != nil {
+		return fmt.Errorf("invalid ring configuration: %w", err)
+	}
+	edited.normalize()
+
+	return applyRing(current, edited)
+}
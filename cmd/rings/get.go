@@ -0,0 +1,136 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rings provides commands to manage HAProxy ring sections, the
+// in-memory buffers used as log sinks, and the servers they forward to.
+package rings
+
+import (
+	"fmt"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetRingsCmd represents "get rings".
+var GetRingsCmd = &cobra.Command{
+	Use:     "rings [name]",
+	Aliases: []string{"ring"},
+	Short:   "List HAProxy ring sections or fetch details of one",
+	Long: `List the ring buffers that log targets can write to, or show one ring
+with its servers. With -o yaml the output is a Ring manifest that apply
+accepts.
+
+Examples:
+  haproxyctl get rings
+  haproxyctl get rings logbuf
+  haproxyctl get rings logbuf -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFormat := internal.GetFlagString(cmd, "output")
+
+		if len(args) == 0 {
+			manifests, err := RingManifests()
+			if err != nil {
+				internal.Fatalf("Failed to fetch rings: %v", err)
+			}
+			if outputFormat != "" && outputFormat != "table" && outputFormat != internal.OutputFormatWide {
+				internal.FormatOutputForCmd(cmd, internal.WithColumns(manifests, internal.ColumnSet{Kind: ringKind}), outputFormat)
+				return
+			}
+			rows := make([]map[string]interface{}, 0, len(manifests))
+			for _, item := range manifests {
+				rows = append(rows, summaryRow(item.(*RingManifest)))
+			}
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, ringColumns), outputFormat)
+			return
+		}
+
+		manifest, err := getRingManifest(args[0])
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(ringKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch ring %q: %v", args[0], err)
+		}
+		if outputFormat == "" || outputFormat == "table" || outputFormat == internal.OutputFormatWide {
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(summaryRow(manifest), ringColumns), outputFormat)
+			return
+		}
+		internal.FormatOutputForCmd(cmd, manifest, outputFormat)
+	},
+}
+
+// ringColumns shows a ring with its size, format and forwarding servers.
+var ringColumns = internal.ColumnSet{
+	Kind:    ringKind,
+	Default: []string{"name", "format", "size", "maxlen", "servers"},
+	Wide:    []string{"description", "timeout_connect", "timeout_server"},
+}
+
+// summaryRow flattens a manifest into a table row.
+func summaryRow(m *RingManifest) map[string]interface{} {
+	servers := make([]string, 0, len(m.Servers))
+	for _, s := range m.Servers {
+		servers = append(servers, fmt.Sprintf("%s=%s:%d", s.Name, s.Address, s.Port))
+	}
+	return map[string]interface{}{
+		"name":            m.Name,
+		"description":     m.Description,
+		"format":          m.Format,
+		"size":            m.Size,
+		"maxlen":          m.Maxlen,
+		"timeout_connect": m.TimeoutConnect,
+		"timeout_server":  m.TimeoutServer,
+		"servers":         strings.Join(servers, ","),
+	}
+}
+
+// getRingManifest fetches a ring section and its servers.
+func getRingManifest(name string) (*RingManifest, error) {
+	obj, err := internal.GetResource(ringPath(name))
+	if err != nil {
+		return nil, err
+	}
+	servers, err := internal.GetResourceList(ringServersPath(name))
+	if err != nil && !internal.IsNotFoundError(err) {
+		return nil, fmt.Errorf("failed to fetch servers of ring %q: %w", name, err)
+	}
+	return manifestFromAPI(obj, servers), nil
+}
+
+// RingManifests returns every ring section, with its servers, as a manifest
+// that apply accepts, sorted by name.
+func RingManifests() ([]interface{}, error) {
+	list, err := internal.GetResourceList(ringsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rings: %w", err)
+	}
+	internal.SortByStringField(list, "name")
+
+	manifests := make([]interface{}, 0, len(list))
+	for _, raw := range list {
+		name, _ := raw["name"].(string)
+		manifest, err := getRingManifest(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch ring %q: %w", name, err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,144 @@
+package rings
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+const testRingManifest = `apiVersion: haproxyctl/v1
+kind: Ring
+name: logbuf
+format: rfc5424
+size: 32768
+maxlen: 1200
+timeout_connect: 5s
+servers:
+  - name: collector1
+    address: 10.0.0.9
+    port: 6514
+    log_proto: octet-count
+  - name: collector2
+    address: 10.0.1.9
+    port: 6514
+`
+
+func ringServerSummary(srv *testserver.Server) string {
+	var out []string
+	for _, s := range srv.RingServers("logbuf") {
+		out = append(out, s["name"].(string)+"="+s["address"].(string))
+	}
+	return strings.Join(out, ",")
+}
+
+func TestApplyRingFromYAML(t *testing.T) {
+	srv := testserver.New(t)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyRingFromYAML([]byte(testRingManifest), "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "ring/logbuf created") {
+		t.Fatalf("expected created status, got:\n%s", output)
+	}
+	section, ok := srv.Ring("logbuf")
+	if !ok || section["timeout_connect"] != float64(5000) || section["format"] != "rfc5424" {
+		t.Fatalf("ring not sent in API form: %+v", section)
+	}
+	if got := ringServerSummary(srv); got != "collector1=10.0.0.9,collector2=10.0.1.9" {
+		t.Fatalf("servers = %s", got)
+	}
+	if proto := srv.RingServers("logbuf")[0]["log-proto"]; proto != "octet-count" {
+		t.Fatalf("log-proto = %v", proto)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyRingFromYAML([]byte(testRingManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "ring/logbuf unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	changed := strings.Replace(testRingManifest, `  - name: collector2
+    address: 10.0.1.9`, `  - name: collector3
+    address: 10.0.2.9`, 1)
+	version := srv.Version()
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyRingFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	if got := ringServerSummary(srv); got != "collector1=10.0.0.9,collector3=10.0.2.9" {
+		t.Fatalf("servers after change = %s", got)
+	}
+	if srv.Version() != version+1 {
+		t.Fatalf("apply used %d versions, want one transaction", srv.Version()-version)
+	}
+	if n := srv.CountRequests("PUT", "/v3/services/haproxy/configuration/rings/logbuf/servers/collector1"); n != 0 {
+		t.Fatalf("unchanged server was replaced %d times", n)
+	}
+}
+
+func TestRingValidate(t *testing.T) {
+	valid := RingManifest{Name: "logbuf", Format: "raw", Servers: []RingServer{{Name: "c1", Address: "10.0.0.9", Port: 514}}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]RingManifest{
+		"missing name":   {},
+		"bad format":     {Name: "logbuf", Format: "json"},
+		"negative size":  {Name: "logbuf", Size: -1},
+		"bad duration":   {Name: "logbuf", TimeoutServer: "later"},
+		"missing port":   {Name: "logbuf", Servers: []RingServer{{Name: "c1", Address: "10.0.0.9"}}},
+		"bad log proto":  {Name: "logbuf", Servers: []RingServer{{Name: "c1", Address: "10.0.0.9", Port: 514, LogProto: "tcp"}}},
+		"duplicate":      {Name: "logbuf", Servers: []RingServer{{Name: "c1", Address: "10.0.0.9", Port: 514}, {Name: "c1", Address: "10.0.0.10", Port: 514}}},
+		"wrong kind":     {Name: "logbuf", Kind: "Resolver"},
+		"bad apiVersion": {Name: "logbuf", APIVersion: "v2"},
+	}
+	for name, m := range cases {
+		if err := m.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseRingServerFlag(t *testing.T) {
+	s, err := parseRingServerFlag("name=c1,address=10.0.0.9,port=6514,log_proto=octet-count")
+	if err != nil || s != (RingServer{Name: "c1", Address: "10.0.0.9", Port: 6514, LogProto: "octet-count"}) {
+		t.Fatalf("parseRingServerFlag = %+v, %v", s, err)
+	}
+	if _, err := parseRingServerFlag("name=c1,host=10.0.0.9"); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestCreateAndDeleteRing(t *testing.T) {
+	srv := testserver.New(t)
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := createRing(RingManifest{Name: "logbuf", Size: 32768}, false); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	})
+	if section, ok := srv.Ring("logbuf"); !ok || section["size"] != float64(32768) {
+		t.Fatalf("ring = %+v", section)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteRingByName("logbuf"); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "ring/logbuf deleted") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if _, ok := srv.Ring("logbuf"); ok {
+		t.Fatal("ring still present after delete")
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rings provides commands to manage HAProxy ring sections, the
+// in-memory buffers used as log sinks, and the servers they forward to.
+package rings
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	apiVersionV1 = "haproxyctl/v1"
+	ringKind     = "Ring"
+
+	ringsPath = "/services/haproxy/configuration/rings"
+)
+
+// allowedFormats are the log formats a ring accepts.
+var allowedFormats = []string{"iso", "local", "raw", "rfc3164", "rfc5424", "short", "priority", "timed"}
+
+// allowedLogProtos are the framings a ring server can use.
+var allowedLogProtos = []string{"legacy", "octet-count"}
+
+// RingManifest is the manifest view of a "ring <name>" section and its
+// servers. Timeouts are written like the backend timeouts (e.g. 5s) and sent
+// to the API in milliseconds.
+//
+//nolint:tagliatelle // manifest uses HAProxy's snake_case names like backendConfig
+type RingManifest struct {
+	APIVersion     string       `json:"apiVersion" yaml:"apiVersion"`
+	Kind           string       `json:"kind" yaml:"kind"`
+	Name           string       `json:"name" yaml:"name"`
+	Description    string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Format         string       `json:"format,omitempty" yaml:"format,omitempty"`
+	Size           int          `json:"size,omitempty" yaml:"size,omitempty"`
+	Maxlen         int          `json:"maxlen,omitempty" yaml:"maxlen,omitempty"`
+	TimeoutConnect string       `json:"timeout_connect,omitempty" yaml:"timeout_connect,omitempty"`
+	TimeoutServer  string       `json:"timeout_server,omitempty" yaml:"timeout_server,omitempty"`
+	Servers        []RingServer `json:"servers,omitempty" yaml:"servers,omitempty"`
+}
+
+// RingServer is a "server <name> <address>:<port>" line of a ring, to which
+// the buffered messages are forwarded.
+//
+//nolint:tagliatelle // manifest uses HAProxy's snake_case names like backendConfig
+type RingServer struct {
+	Name     string `json:"name" yaml:"name"`
+	Address  string `json:"address" yaml:"address"`
+	Port     int    `json:"port" yaml:"port"`
+	LogProto string `json:"log_proto,omitempty" yaml:"log_proto,omitempty"`
+}
+
+// String renders the server the way it appears in haproxy.cfg.
+func (s RingServer) String() string {
+	line := fmt.Sprintf("server %s %s:%d", s.Name, s.Address, s.Port)
+	if s.LogProto != "" {
+		line += " log-proto " + s.LogProto
+	}
+	return line
+}
+
+func (s RingServer) validate() error {
+	if s.Name == "" || s.Address == "" {
+		return fmt.Errorf("ring server %+v must have name and address", s)
+	}
+	if s.Port <= 0 || s.Port > 65535 {
+		return fmt.Errorf("%q: invalid port %d", s.String(), s.Port)
+	}
+	if s.LogProto != "" && !slices.Contains(allowedLogProtos, s.LogProto) {
+		return fmt.Errorf("%q: log_proto must be one of %s", s.String(), strings.Join(allowedLogProtos, ", "))
+	}
+	return nil
+}
+
+func (s RingServer) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"name": s.Name, "address": s.Address, "port": s.Port}
+	if s.LogProto != "" {
+		payload["log-proto"] = s.LogProto
+	}
+	return payload
+}
+
+// ringServerFromAPI converts an API ring server object.
+func ringServerFromAPI(obj map[string]interface{}) RingServer {
+	var s RingServer
+	s.Name, _ = obj["name"].(string)
+	s.Address, _ = obj["address"].(string)
+	s.Port, _ = internal.GetIntField(obj, "port")
+	s.LogProto, _ = obj["log-proto"].(string)
+	return s
+}
+
+// parseRingServerFlag parses "name=collector,address=10.0.0.9,port=514" with
+// an optional log_proto field.
+func parseRingServerFlag(s string) (RingServer, error) {
+	var srv RingServer
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return srv, fmt.Errorf("invalid ring server %q (expected name=...,address=...,port=...[,log_proto=...])", s)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "name":
+			srv.Name = value
+		case "address":
+			srv.Address = value
+		case "port":
+			port, err := strconv.Atoi(value)
+			if err != nil {
+				return srv, fmt.Errorf("invalid ring server port %q", value)
+			}
+			srv.Port = port
+		case "log_proto", "log-proto":
+			srv.LogProto = value
+		default:
+			return srv, fmt.Errorf("unknown ring server field %q", key)
+		}
+	}
+	return srv, nil
+}
+
+// Validate checks the manifest before anything is sent to the API.
+func (m *RingManifest) Validate() error {
+	if m.Name == "" {
+		return errors.New("ring name is required")
+	}
+	if m.Kind != "" && m.Kind != ringKind {
+		return fmt.Errorf("kind must be %q", ringKind)
+	}
+	if m.APIVersion != "" && m.APIVersion != apiVersionV1 {
+		return fmt.Errorf("apiVersion must be %q", apiVersionV1)
+	}
+	if m.Format != "" && !slices.Contains(allowedFormats, m.Format) {
+		return fmt.Errorf("format must be one of %s", strings.Join(allowedFormats, ", "))
+	}
+	if m.Size < 0 || m.Maxlen < 0 {
+		return errors.New("size and maxlen must not be negative")
+	}
+	for field, value := range m.durations() {
+		if _, err := internal.ParseDurationToMillis(*value); err != nil {
+			return fmt.Errorf("invalid %s: %w", field, err)
+		}
+	}
+	seen := make(map[string]bool, len(m.Servers))
+	for _, s := range m.Servers {
+		if err := s.validate(); err != nil {
+			return err
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("duplicate ring server %q", s.Name)
+		}
+		seen[s.Name] = true
+	}
+	return nil
+}
+
+// durations returns the duration fields by their API name.
+func (m *RingManifest) durations() map[string]*string {
+	return map[string]*string{
+		"timeout_connect": &m.TimeoutConnect,
+		"timeout_server":  &m.TimeoutServer,
+	}
+}
+
+// toAPIPayload converts the section fields (not the servers) into the object
+// expected by the rings endpoint. It must be called on a validated manifest.
+func (m *RingManifest) toAPIPayload() map[string]interface{} {
+	payload := map[string]interface{}{"name": m.Name}
+	if m.Description != "" {
+		payload["description"] = m.Description
+	}
+	if m.Format != "" {
+		payload["format"] = m.Format
+	}
+	if m.Size > 0 {
+		payload["size"] = m.Size
+	}
+	if m.Maxlen > 0 {
+		payload["maxlen"] = m.Maxlen
+	}
+	for field, value := range m.durations() {
+		if ms, _ := internal.ParseDurationToMillis(*value); ms > 0 {
+			payload[field] = ms
+		}
+	}
+	return payload
+}
+
+// normalize fills in kind and apiVersion, renders durations the way they
+// come back from the API and sorts the servers by name, so manifests from
+// files and from the API compare equal.
+func (m *RingManifest) normalize() {
+	m.APIVersion, m.Kind = apiVersionV1, ringKind
+	for _, value := range m.durations() {
+		if ms, err := internal.ParseDurationToMillis(*value); err == nil {
+			*value = internal.FormatMillisAsDuration(ms)
+		}
+	}
+	sort.Slice(m.Servers, func(i, j int) bool { return m.Servers[i].Name < m.Servers[j].Name })
+}
+
+// manifestFromAPI converts an API ring object and its servers into a
+// manifest.
+func manifestFromAPI(obj map[string]interface{}, servers []map[string]interface{}) *RingManifest {
+	m := &RingManifest{}
+	m.Name, _ = obj["name"].(string)
+	m.Description, _ = obj["description"].(string)
+	m.Format, _ = obj["format"].(string)
+	m.Size, _ = internal.GetIntField(obj, "size")
+	m.Maxlen, _ = internal.GetIntField(obj, "maxlen")
+	for field, value := range m.durations() {
+		if ms, ok := internal.GetIntField(obj, field); ok {
+			*value = internal.FormatMillisAsDuration(ms)
+		}
+	}
+	for _, raw := range servers {
+		m.Servers = append(m.Servers, ringServerFromAPI(raw))
+	}
+	m.normalize()
+	return m
+}
+
+func ringPath(name string) string {
+	return ringsPath + "/" + name
+}
+
+func ringServersPath(ring string) string {
+	return ringPath(ring) + "/servers"
+}
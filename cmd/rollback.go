@@ -0,0 +1,193 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// rollbackCmd represents the "rollback" command.
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Push back the configuration stored before an earlier change",
+	Long: `Before the first configuration change of every command, haproxyctl stores
+the raw configuration it is about to change under its version, in the local
+state directory next to config.json (one directory per Data Plane API URL). The newest
+` + strconv.Itoa(internal.MaxSnapshots) + ` snapshots are kept; --no-snapshot skips taking one.
+
+rollback pushes such a snapshot back as the raw configuration: --previous
+picks the newest one older than the current version, which undoes the last
+haproxyctl command, and --to-version picks a specific one. Rolling back is
+itself a change, so it stores a snapshot too and can be undone the same way.
+A snapshot is only pushed back to the Data Plane API it was taken from.
+
+Examples:
+  haproxyctl rollback --list
+  haproxyctl rollback --previous --dry-run
+  haproxyctl rollback --to-version 42`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if internal.GetFlagBool(cmd, "list") {
+			if err := listSnapshots(); err != nil {
+				internal.Fatalf("Failed to list snapshots: %v", err)
+			}
+			return
+		}
+		opts := rollbackOptions{
+			ToVersion: internal.GetFlagInt(cmd, "to-version"),
+			Previous:  internal.GetFlagBool(cmd, "previous"),
+			DryRun:    internal.GetFlagBool(cmd, "dry-run"),
+		}
+		if (opts.ToVersion > 0) == opts.Previous {
+			internal.FatalCodef(internal.ExitUsage, "rollback requires exactly one of --to-version or --previous (or --list)")
+		}
+		if err := rollback(cmd.Context(), opts); err != nil {
+			internal.Fatalf("Rollback failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	rollbackCmd.Flags().Int("to-version", 0, "Push back the snapshot taken at this configuration version")
+	rollbackCmd.Flags().Bool("previous", false, "Push back the newest snapshot older than the current version")
+	rollbackCmd.Flags().Bool("list", false, "List the stored snapshots of the current Data Plane API")
+	rollbackCmd.Flags().Bool("dry-run", false, "Show the diff against the live configuration without changing it")
+}
+
+type rollbackOptions struct {
+	ToVersion int
+	Previous  bool
+	DryRun    bool
+}
+
+// listSnapshots prints the stored snapshots, newest first.
+func listSnapshots() error {
+	snapshots, err := internal.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "No snapshots stored for this Data Plane API")
+		return nil
+	}
+
+	const (
+		tabWidth   = 8
+		tabPadding = 2
+	)
+	w := tabwriter.NewWriter(os.Stdout, 0, tabWidth, tabPadding, ' ', 0)
+	if _, err := fmt.Fprintln(w, "VERSION\tTAKEN"); err != nil {
+		return err
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		s := snapshots[i]
+		if _, err := fmt.Fprintf(w, "%d\t%s\n", s.Version, s.TakenAt.Format("2006-01-02 15:04:05 MST")); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// pickSnapshot returns the snapshot opts selects, given the current
+// configuration version.
+func pickSnapshot(snapshots []internal.Snapshot, current int, opts rollbackOptions) (internal.Snapshot, error) {
+	if len(snapshots) == 0 {
+		return internal.Snapshot{}, fmt.Errorf("no snapshots stored for this Data Plane API")
+	}
+	if opts.Previous {
+		for i := len(snapshots) - 1; i >= 0; i-- {
+			if snapshots[i].Version < current {
+				return snapshots[i], nil
+			}
+		}
+		return internal.Snapshot{}, fmt.Errorf("no snapshot older than the current version %d", current)
+	}
+	for _, s := range snapshots {
+		if s.Version == opts.ToVersion {
+			return s, nil
+		}
+	}
+	return internal.Snapshot{}, fmt.Errorf("no snapshot of version %d (see \"haproxyctl rollback --list\")", opts.ToVersion)
+}
+
+// rollback pushes the selected snapshot back as the raw configuration,
+// against the current configuration version.
+func rollback(ctx context.Context, opts rollbackOptions) error {
+	snapshots, err := internal.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	current, err := internal.GetConfigurationVersion()
+	if err != nil {
+		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+	}
+	target, err := pickSnapshot(snapshots, current, opts)
+	if err != nil {
+		return err
+	}
+	endpoint, err := internal.SnapshotEndpoint()
+	if err != nil {
+		return err
+	}
+	if target.Endpoint != endpoint {
+		return fmt.Errorf("snapshot of version %d was taken from %q, not %s; refusing to push it",
+			target.Version, target.Endpoint, endpoint)
+	}
+	data, err := target.Read()
+	if err != nil {
+		return err
+	}
+	live, err := internal.SendRequestWithContext(ctx, "GET", internal.RawConfigPath, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch raw configuration: %w", err)
+	}
+
+	name := "version " + strconv.Itoa(target.Version)
+	if bytes.Equal(live, data) {
+		internal.PrintStatus("Configuration", name, internal.ActionUnchanged)
+		return nil
+	}
+	if opts.DryRun {
+		internal.WriteUnifiedDiff(os.Stdout, string(live), string(data),
+			"live/version "+strconv.Itoa(current), "snapshot/"+name, internal.ColorEnabled(os.Stdout))
+		internal.PrintDryRun()
+		return nil
+	}
+
+	// Sent once: after a conflict the snapshot would silently replace
+	// changes made since the version it was compared against.
+	params := map[string]string{"version": strconv.Itoa(current)}
+	if _, err := internal.SendRawRequestOnce(ctx, "POST", internal.RawConfigPath, params, data, "text/plain"); err != nil {
+		if internal.IsVersionConflictError(err) {
+			return fmt.Errorf("configuration changed since version %d was read; try again", current)
+		}
+		return fmt.Errorf("failed to push raw configuration: %w", err)
+	}
+	internal.PrintStatus("Configuration", name, "rolled back")
+	return nil
+}
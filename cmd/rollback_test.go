@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+	"haproxyctl/pkg/client/clienttest"
+)
+
+// pushRaw replaces the raw configuration the way a fresh haproxyctl
+// process would, taking its automatic snapshot first.
+func pushRaw(t *testing.T, srv *testserver.Server, raw string) {
+	t.Helper()
+	internal.SetAutoSnapshots(true)
+	params := map[string]string{"version": strconv.Itoa(srv.Version())}
+	if _, err := internal.SendRawRequest("POST", internal.RawConfigPath, params, []byte(raw), "text/plain"); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	srv := testserver.New(t)
+	t.Cleanup(internal.SetStateDir(t.TempDir()))
+	t.Cleanup(func() { internal.SetAutoSnapshots(false) })
+
+	srv.SetRawConfig("global\n  maxconn 100\n")
+	pushRaw(t, srv, "global\n  maxconn 200\n")
+	pushRaw(t, srv, "global\n  maxconn 300\n")
+
+	snapshots, err := internal.ListSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Version != 1 || snapshots[1].Version != 2 {
+		t.Fatalf("snapshots = %+v, want versions 1 and 2", snapshots)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := rollback(context.Background(), rollbackOptions{ToVersion: 1, DryRun: true}); err != nil {
+			t.Fatalf("dry-run rollback failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "-  maxconn 300") || !strings.Contains(output, "+  maxconn 100") {
+		t.Fatalf("expected a diff against the live configuration, got:\n%s", output)
+	}
+	if srv.Version() != 3 {
+		t.Fatalf("dry run changed the configuration (version %d)", srv.Version())
+	}
+
+	internal.SetAutoSnapshots(true)
+	output = internal.CaptureStdout(t, func() {
+		if err := rollback(context.Background(), rollbackOptions{Previous: true}); err != nil {
+			t.Fatalf("rollback failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "version 2 rolled back") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if got := srv.RawConfig(); got != "global\n  maxconn 200\n" {
+		t.Fatalf("raw configuration = %q, want the version 2 snapshot", got)
+	}
+	if snapshots, _ := internal.ListSnapshots(); len(snapshots) != 3 || snapshots[2].Version != 3 {
+		t.Fatalf("rollback did not snapshot the configuration it replaced: %+v", snapshots)
+	}
+
+	if err := rollback(context.Background(), rollbackOptions{ToVersion: 9}); err == nil {
+		t.Fatal("expected error for a version without snapshot")
+	}
+}
+
+func TestRollback_RefusesSnapshotOfOtherEndpoint(t *testing.T) {
+	srv := testserver.New(t)
+	stateDir := t.TempDir()
+	t.Cleanup(internal.SetStateDir(stateDir))
+	t.Cleanup(func() { internal.SetAutoSnapshots(false) })
+
+	srv.SetRawConfig("global\n  maxconn 100\n")
+	pushRaw(t, srv, "global\n  maxconn 200\n")
+
+	// A second server at the same configuration version must not see the
+	// first one's snapshots, let alone get them pushed.
+	other := testserver.New(t)
+	other.SetRawConfig("global\n  maxconn 900\n")
+	if snapshots, err := internal.ListSnapshots(); err != nil || len(snapshots) != 0 {
+		t.Fatalf("snapshots of another endpoint = %+v, %v; want none", snapshots, err)
+	}
+	if err := rollback(context.Background(), rollbackOptions{ToVersion: 1}); err == nil {
+		t.Fatal("expected rollback to fail without a snapshot of this endpoint")
+	}
+	if got := other.RawConfig(); got != "global\n  maxconn 900\n" {
+		t.Fatalf("raw configuration of the other server = %q, want it unchanged", got)
+	}
+
+	// A snapshot recorded for another endpoint is refused even when it
+	// sits in this endpoint's directory.
+	infos, err := filepath.Glob(filepath.Join(stateDir, "snapshots", "*", "1.json"))
+	if err != nil || len(infos) != 1 {
+		t.Fatalf("snapshot info files = %v, %v", infos, err)
+	}
+	if err := os.WriteFile(infos[0], []byte(`{"endpoint": "http://elsewhere:5555/v3"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(internal.SetConfigOverride(&internal.Config{APIBaseURL: srv.URL, Username: clienttest.Username, Password: clienttest.Password}))
+	err = rollback(context.Background(), rollbackOptions{ToVersion: 1})
+	if err == nil || !strings.Contains(err.Error(), "refusing") {
+		t.Fatalf("rollback of a snapshot of another endpoint = %v, want a refusal", err)
+	}
+	if got := srv.RawConfig(); got != "global\n  maxconn 200\n" {
+		t.Fatalf("raw configuration = %q, want it unchanged", got)
+	}
+}
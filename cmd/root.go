@@ -20,10 +20,32 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
+
+	"haproxyctl/internal"
 
 	"github.com/spf13/cobra"
 )
 
+// contextName is the global --context flag.
+var contextName string
+
+// connection holds the global --api-url, --api-username and --api-password
+// flags. They are not called --server, --username and --password because
+// commands such as "create backends --server" and "create users --password"
+// have local flags of those names, which would shadow them.
+var connection internal.ConnectionOptions
+
+// requestTimeout is the global --timeout flag.
+var requestTimeout time.Duration
+
+// transientRetries is the global --retries flag.
+var transientRetries int
+
+// setupDone is set once flags and arguments were accepted and the global
+// settings applied, so a later error is the command's own failure.
+var setupDone bool
+
 // rootCmd represents the base command when called without any subcommands.
 var rootCmd = &cobra.Command{
 	Use:   "haproxyctl",
@@ -68,9 +90,57 @@ Examples:
   haproxyctl create -f backend.yaml
   haproxyctl create -f server.yaml
 
+  # Render a payload without any Data Plane API access
+  haproxyctl create backends mybackend --offline -o json
+
 Use "haproxyctl <command> --help" for more information about a given command.
 `,
 
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		internal.SetVerbosity(internal.GetFlagInt(cmd, "v"))
+		internal.SetDebug(internal.GetFlagBool(cmd, "debug"))
+		if err := internal.SetErrorFormat(internal.GetFlagString(cmd, "error-format")); err != nil {
+			return err
+		}
+		offline, err := cmd.Flags().GetBool("offline")
+		if err != nil {
+			return fmt.Errorf("failed to read flag offline: %w", err)
+		}
+		internal.SetOffline(offline)
+		internal.SetContext(contextName)
+		internal.SetConnectionOptions(connection)
+		// Read from the root: "reload --timeout" and "create backends
+		// --retries" are local flags of their own.
+		if cmd.Root().PersistentFlags().Changed("timeout") {
+			internal.SetRequestTimeout(requestTimeout)
+		}
+		if cmd.Root().PersistentFlags().Changed("retries") {
+			internal.SetTransientRetries(transientRetries)
+		}
+
+		retries, err := cmd.Flags().GetInt("conflict-retries")
+		if err != nil {
+			return fmt.Errorf("failed to read flag conflict-retries: %w", err)
+		}
+		internal.SetConflictRetries(retries)
+		internal.SetConcurrency(internal.GetFlagInt(cmd, "concurrency"))
+		internal.SetActiveTransaction(internal.GetFlagString(cmd, "transaction"))
+		internal.SetAutoSnapshots(!internal.GetFlagBool(cmd, "no-snapshot"))
+
+		internal.SetTLSOptions(internal.TLSOptions{
+			CertificateAuthority:  internal.GetFlagString(cmd, "certificate-authority"),
+			ClientCertificate:     internal.GetFlagString(cmd, "client-certificate"),
+			ClientKey:             internal.GetFlagString(cmd, "client-key"),
+			InsecureSkipTLSVerify: internal.GetFlagBool(cmd, "insecure-skip-tls-verify"),
+		})
+		// From here on errors are reported by Execute (see
+		// internal.ReportError), without the usage.
+		cmd.Root().SilenceErrors = true
+		cmd.Root().SilenceUsage = true
+		setupDone = true
+		return nil
+	},
+
 	Run: func(cmd *cobra.Command, _ []string) {
 		// Tool for managing HAProxy backends, show help if no subcommands are provided.
 		cmd.Println("No command specified. Showing help:")
@@ -80,16 +150,43 @@ Use "haproxyctl <command> --help" for more information about a given command.
 	},
 }
 
-// Execute runs the root command and dispatches subcommands.
+// Execute runs the root command and dispatches subcommands. Unknown
+// commands run a haproxyctl-<name> plugin from PATH when there is one.
 func Execute() {
+	if ran, code := runPlugin(os.Args[1:]); ran {
+		os.Exit(code)
+	}
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		if !setupDone {
+			// Flag, argument and unknown command errors: cobra has
+			// printed the error and the usage.
+			os.Exit(internal.ExitUsage)
+		}
+		os.Exit(internal.ReportError(err.Error(), err))
 	}
 }
 
 func init() {
 	// Define global flags (if needed in the future).
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.haproxyctl.yaml)")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "Named context from the config file to use (default: current_context, else the top-level endpoint)")
+	rootCmd.PersistentFlags().StringVar(&connection.APIURL, "api-url", "", "Data Plane API URL to use instead of the one of the context, e.g. http://10.0.0.5:5555/v3")
+	rootCmd.PersistentFlags().StringVar(&connection.Username, "api-username", "", "Data Plane API username to use instead of the one of the context")
+	rootCmd.PersistentFlags().StringVar(&connection.Password, "api-password", "", "Data Plane API password to use instead of the one of the context")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "timeout", 0, "How long to wait for each Data Plane API request, 0 for no limit (default: the context's timeout, else 30s)")
+	rootCmd.PersistentFlags().IntVar(&transientRetries, "retries", 0, "How many times to retry a request after a refused connection, 429 or 5xx, with backoff (default: the context's retries, else 0)")
+	rootCmd.PersistentFlags().Int("concurrency", internal.DefaultConcurrency, "How many requests to send at once when listing fetches details per object (servers, binds, rules)")
+	rootCmd.PersistentFlags().Bool("offline", false, "Never contact the Data Plane API; create/apply only render the manifest or payload")
+	rootCmd.PersistentFlags().Int("conflict-retries", internal.DefaultConflictRetries, "How many times to replay a change when the configuration version changed meanwhile (0 disables)")
+	rootCmd.PersistentFlags().String("error-format", internal.ErrorFormatText, "How a failed command reports its error on stderr: text or json (kind, name, operation, HTTP status, API message)")
+	rootCmd.PersistentFlags().Bool("debug", false, "Dump every Data Plane API request and response, headers and bodies, to stderr with credentials redacted")
+	rootCmd.PersistentFlags().IntP("v", "v", 0, "Log verbosity, 0-4: 1 adds retries and transactions, 2 every API call with status and duration, 3 query parameters, 4 bodies")
+	rootCmd.PersistentFlags().Bool("no-snapshot", false, "Do not store the configuration before changing it (see \"rollback\")")
+	rootCmd.PersistentFlags().String("transaction", "", "Stage changes in this open transaction (see \"create transactions\") instead of applying them")
+	rootCmd.PersistentFlags().String("certificate-authority", "", "PEM bundle of CAs to verify the Data Plane API certificate with")
+	rootCmd.PersistentFlags().String("client-certificate", "", "Client certificate (PEM) for mutual TLS with the Data Plane API")
+	rootCmd.PersistentFlags().String("client-key", "", "Private key (PEM) of --client-certificate")
+	rootCmd.PersistentFlags().Bool("insecure-skip-tls-verify", false, "Do not verify the Data Plane API certificate (insecure)")
 
 	// Ensure rootCmd shows help when run without arguments
 	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true}) // Hide default help command
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/pkg/client/clienttest"
+)
+
+// TestConnectionFlags_LocalServerFlag checks that the global connection
+// flags reach commands that have a --server flag of their own.
+func TestConnectionFlags_LocalServerFlag(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	config := `{"api_base_url": "http://127.0.0.1:1", "username": "other", "password": "other"}`
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer internal.SetConfigFile(configPath)()
+
+	srv := clienttest.NewUnstarted()
+	srv.Start()
+	defer srv.Close()
+
+	defer func() {
+		connection = internal.ConnectionOptions{}
+		internal.SetConnectionOptions(connection)
+		for _, name := range []string{"api-url", "api-username", "api-password", "no-snapshot"} {
+			if err := rootCmd.PersistentFlags().Set(name, rootCmd.PersistentFlags().Lookup(name).DefValue); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}()
+
+	rootCmd.SetArgs([]string{
+		"create", "backends", "b2", "--server", "name=s1,address=10.0.0.1,port=80",
+		"--api-url", srv.URL, "--api-username", clienttest.Username, "--api-password", clienttest.Password,
+		"--no-snapshot",
+	})
+	internal.CaptureStdout(t, func() {
+		if err := rootCmd.ExecuteContext(context.Background()); err != nil {
+			t.Fatalf("create backends: %v", err)
+		}
+	})
+
+	if _, ok := srv.Backend("b2"); !ok {
+		t.Fatal("backend b2 was not created on the --api-url server")
+	}
+	if servers := srv.Servers("b2"); len(servers) != 1 || servers[0]["name"] != "s1" {
+		t.Fatalf("servers of b2 = %v, want s1 from the local --server flag", servers)
+	}
+}
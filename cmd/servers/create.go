@@ -18,9 +18,8 @@ limitations under the License.
 package servers
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"strconv"
 
 	"haproxyctl/internal"
 
@@ -39,7 +38,9 @@ Examples:
   haproxyctl create servers mybackend myserver \
     --address 10.0.0.1 \
     --port 80 \
-    --weight 100`,
+    --weight 100
+  haproxyctl create servers mybackend myserver --address 10.0.0.2 --port 443 \
+    --check --inter 2s --rise 2 --fall 3 --ssl --verify required --sni "req.hdr(host)" --proto h2`,
 	Args: cobra.ExactArgs(serverArgsTwo),
 	Run: func(cmd *cobra.Command, args []string) {
 		backendName := args[0]
@@ -49,14 +50,14 @@ Examples:
 		server.LoadFromFlags(cmd, backendName, serverName)
 
 		if err := server.Validate(); err != nil {
-			log.Fatalf("Invalid server configuration: %v", err)
+			internal.FatalCodef(internal.ExitInvalid, "Invalid server configuration: %v", err)
 		}
 
 		outputFormat := internal.GetFlagString(cmd, "output")
-		dryRun := internal.GetFlagBool(cmd, "dry-run")
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
 
 		if err := CreateServer(server, outputFormat, dryRun); err != nil {
-			log.Fatalf("Failed to create server: %v", err)
+			internal.Fatalf("Failed to create server: %v", err)
 		}
 	},
 }
@@ -80,22 +81,21 @@ func CreateServer(server ServerConfig, outputFormat string, dryRun bool) error {
 		return nil
 	}
 
+	return CreateServerInTransaction(nil, server)
+}
+
+// CreateServerInTransaction creates a server as part of tx, or as a
+// standalone change when tx is nil.
+func CreateServerInTransaction(tx *internal.Transaction, server ServerConfig) error {
 	if err := server.NormalizeParent(); err != nil {
 		return fmt.Errorf("invalid server configuration: %w", err)
 	}
 
-	version, err := internal.GetConfigurationVersion()
+	payload, err := server.toClient()
 	if err != nil {
-		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+		return err
 	}
-
-	endpoint := fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers", server.Parent)
-
-	_, err = internal.SendRequest("POST", endpoint,
-		map[string]string{"version": strconv.Itoa(version)},
-		server.toPayload(),
-	)
-	if err != nil {
+	if err := internal.API().CreateServer(context.Background(), tx.Client(), server.Parent, payload); err != nil {
 		return fmt.Errorf("failed to create server '%s': %w", server.Name, err)
 	}
 
@@ -107,26 +107,21 @@ func CreateServer(server ServerConfig, outputFormat string, dryRun bool) error {
 // UpdateServer updates an existing server definition in a backend via
 // the HAProxy Data Plane API v3.
 func UpdateServer(server ServerConfig) error {
+	return UpdateServerInTransaction(nil, server)
+}
+
+// UpdateServerInTransaction updates a server as part of tx, or as a
+// standalone change when tx is nil.
+func UpdateServerInTransaction(tx *internal.Transaction, server ServerConfig) error {
 	if err := server.NormalizeParent(); err != nil {
 		return fmt.Errorf("invalid server configuration: %w", err)
 	}
 
-	version, err := internal.GetConfigurationVersion()
+	payload, err := server.toClient()
 	if err != nil {
-		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+		return err
 	}
-
-	endpoint := fmt.Sprintf(
-		"/services/haproxy/configuration/backends/%s/servers/%s",
-		server.Parent,
-		server.Name,
-	)
-
-	_, err = internal.SendRequest("PUT", endpoint,
-		map[string]string{"version": strconv.Itoa(version)},
-		server.toPayload(),
-	)
-	if err != nil {
+	if err := internal.API().UpdateServer(context.Background(), tx.Client(), server.Parent, payload); err != nil {
 		return fmt.Errorf("failed to update server '%s' in backend '%s': %w", server.Name, server.Parent, err)
 	}
 
@@ -150,7 +145,7 @@ func CreateServerFromFile(data []byte) error {
 		return fmt.Errorf("invalid server configuration: %w", err)
 	}
 
-	return CreateServer(server, "", false)
+	return CreateServer(server, "", internal.IsOffline())
 }
 
 func init() {
@@ -158,6 +153,19 @@ func init() {
 	CreateServersCmd.Flags().Int("port", 0, "Server port (required)")
 	CreateServersCmd.Flags().Int("weight", defaultServerWeight, "Server weight (default: 100)")
 	CreateServersCmd.Flags().Bool("ssl", false, "Enable SSL for the server")
+	CreateServersCmd.Flags().Bool("check", false, "Enable health checks")
+	CreateServersCmd.Flags().String("inter", "", "Interval between health checks (e.g., 2s)")
+	CreateServersCmd.Flags().Int("rise", 0, "Successful checks before the server is marked up")
+	CreateServersCmd.Flags().Int("fall", 0, "Failed checks before the server is marked down")
+	CreateServersCmd.Flags().Bool("backup", false, "Only use the server when all non-backup servers are down")
+	CreateServersCmd.Flags().Int("maxconn", 0, "Maximum concurrent connections to the server")
+	CreateServersCmd.Flags().String("cookie", "", "Cookie value identifying the server for cookie persistence")
+	CreateServersCmd.Flags().String("verify", "", "Certificate verification with --ssl: none or required")
+	CreateServersCmd.Flags().String("sni", "", "SNI expression sent with --ssl, e.g. req.hdr(host) or str(example.com)")
+	CreateServersCmd.Flags().String("alpn", "", "ALPN protocols offered with --ssl (e.g. h2,http/1.1)")
+	CreateServersCmd.Flags().String("proto", "", "Force the protocol to the server, e.g. h2")
+	CreateServersCmd.Flags().Bool("send-proxy", false, "Send a PROXY protocol v1 header")
+	CreateServersCmd.Flags().Bool("send-proxy-v2", false, "Send a PROXY protocol v2 header")
 
 	CreateServersCmd.Flags().StringP("output", "o", "", "Output format: yaml or json")
 	CreateServersCmd.Flags().Bool("dry-run", false, "Simulate creation without actually applying")
@@ -18,9 +18,11 @@ limitations under the License.
 package servers
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"strconv"
+	"os"
+	"path"
 
 	"haproxyctl/internal"
 
@@ -29,25 +31,56 @@ import (
 
 // DeleteServersCmd represents "delete server".
 var DeleteServersCmd = &cobra.Command{
-	Use:     "server <backend_name> <server_name>",
+	Use:     "server <backend_name> [server_name]",
 	Aliases: []string{"servers"},
 	Short:   "Delete a specific HAProxy server from a backend",
 	Long: `This command deletes a server from a specific backend.
 
-Example:
-  haproxyctl delete server mybackend myserver`,
-	Args: cobra.ExactArgs(serverArgsTwo),
-	Run: func(_ *cobra.Command, args []string) {
+With --all, every server in the backend (optionally only those whose name
+matches --match) is removed in a single transaction, so the backend never
+sits half-emptied if a deletion fails.
+
+Examples:
+  haproxyctl delete server mybackend myserver
+  haproxyctl delete servers mybackend --all
+  haproxyctl delete servers mybackend --all --match 'web-*' --dry-run`,
+	Args: cobra.RangeArgs(1, serverArgsTwo),
+	Run: func(cmd *cobra.Command, args []string) {
 		backendName := args[0]
-		serverName := args[1]
-		deleteServer(backendName, serverName)
+		all := internal.GetFlagBool(cmd, "all")
+		if !all && internal.GetFlagString(cmd, "match") != "" {
+			internal.FatalCodef(internal.ExitUsage, "--match can only be used with --all")
+		}
+
+		if all {
+			if len(args) > 1 {
+				internal.FatalCodef(internal.ExitUsage, "Cannot combine a server name with --all")
+			}
+			pattern := internal.GetFlagString(cmd, "match")
+			dryRun := internal.GetFlagBool(cmd, "dry-run")
+			if err := DeleteAllServers(backendName, pattern, dryRun); err != nil {
+				internal.Fatalf("Failed to delete servers in backend '%s': %v", backendName, err)
+			}
+			return
+		}
+
+		if len(args) < serverArgsTwo {
+			internal.FatalCodef(internal.ExitUsage, "Specify a server name or use --all")
+		}
+		deleteServer(backendName, args[1])
 	},
 }
 
+func init() {
+	DeleteServersCmd.Flags().Bool("all", false, "Delete all servers in the backend")
+	DeleteServersCmd.Flags().String("match", "", "With --all, only delete servers whose name matches this glob pattern")
+	DeleteServersCmd.Flags().Bool("dry-run", false, "With --all, list the servers that would be deleted without deleting them")
+}
+
 // deleteServer handles deletion of a server from a backend.
 func deleteServer(backendName, serverName string) {
 	if err := DeleteServer(backendName, serverName); err != nil {
-		log.Fatalf("Failed to delete server '%s' in backend '%s': %v", serverName, backendName, err)
+		internal.Fatalf("Failed to delete server '%s' in backend '%s': %v", serverName, backendName, err)
 	}
 }
 
@@ -55,14 +88,13 @@ func deleteServer(backendName, serverName string) {
 // This is shared between the CLI command and higher-level workflows
 // such as backend editing.
 func DeleteServer(backendName, serverName string) error {
-	version, err := internal.GetConfigurationVersion()
-	if err != nil {
-		return fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
-	}
+	return DeleteServerInTransaction(nil, backendName, serverName)
+}
 
-	endpoint := fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers/%s", backendName, serverName)
-	_, err = internal.SendRequest("DELETE", endpoint, map[string]string{"version": strconv.Itoa(version)}, nil)
-	if err != nil {
+// DeleteServerInTransaction removes a server as part of tx, or as a
+// standalone change when tx is nil.
+func DeleteServerInTransaction(tx *internal.Transaction, backendName, serverName string) error {
+	if err := internal.API().DeleteServer(context.Background(), tx.Client(), backendName, serverName); err != nil {
 		return fmt.Errorf("failed to delete server '%s' in backend '%s': %w", serverName, backendName, err)
 	}
 
@@ -70,3 +102,72 @@ func DeleteServer(backendName, serverName string) error {
 	internal.PrintStatus("Server", displayName, internal.ActionDeleted)
 	return nil
 }
+
+// DeleteAllServers removes every server in backendName whose name matches
+// pattern (all servers when pattern is empty) within one transaction. If
+// any deletion fails the transaction is discarded and no server is removed.
+func DeleteAllServers(backendName, pattern string, dryRun bool) error {
+	if pattern != "" {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --match pattern %q: %w", pattern, err)
+		}
+	}
+
+	list, err := internal.GetResourceList(fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers", backendName))
+	if err != nil {
+		return internal.FormatAPIError("Backend", backendName, "list servers of", err)
+	}
+	internal.SortByStringField(list, "name")
+
+	var names []string
+	for _, srv := range list {
+		name, _ := srv["name"].(string)
+		if name == "" {
+			continue
+		}
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, name); !ok {
+				continue
+			}
+		}
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "No servers matched in "+internal.ResourceID("Backend", backendName))
+		return nil
+	}
+
+	if dryRun {
+		for _, name := range names {
+			internal.PrintStatus("Server", backendName+"/"+name, internal.ActionDeleted+" (dry run)")
+		}
+		internal.PrintDryRun()
+		return nil
+	}
+
+	tx, err := internal.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	api := internal.API()
+	for _, name := range names {
+		if err := api.DeleteServer(context.Background(), tx.Client(), backendName, name); err != nil {
+			deleteErr := fmt.Errorf("failed to delete server '%s': %w", name, err)
+			if abortErr := tx.Abort(); abortErr != nil {
+				return errors.Join(deleteErr, abortErr)
+			}
+			return deleteErr
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		internal.PrintStatus("Server", backendName+"/"+name, internal.ActionDeleted)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package servers
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestDeleteAllServers_MatchInOneTransaction(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "web"})
+	for _, name := range []string{"web-1", "web-2", "canary"} {
+		srv.AddServer("web", map[string]interface{}{"name": name, "address": "10.0.0.1", "port": 80})
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteAllServers("web", "web-*", false); err != nil {
+			t.Fatalf("DeleteAllServers failed: %v", err)
+		}
+	})
+
+	for _, want := range []string{"server/web/web-1 deleted", "server/web/web-2 deleted"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	left := srv.Servers("web")
+	if len(left) != 1 || left[0]["name"] != "canary" {
+		t.Fatalf("remaining servers = %+v, want only canary", left)
+	}
+	if srv.Version() != 2 {
+		t.Fatalf("version = %d, want 2 (single commit)", srv.Version())
+	}
+}
+
+func TestDeleteAllServers_DryRun(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "web"})
+	srv.AddServer("web", map[string]interface{}{"name": "s1", "address": "10.0.0.1", "port": 80})
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteAllServers("web", "", true); err != nil {
+			t.Fatalf("DeleteAllServers failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Dry run mode enabled") {
+		t.Fatalf("expected dry-run message, got:\n%s", output)
+	}
+	if len(srv.Servers("web")) != 1 {
+		t.Fatal("dry run must not delete servers")
+	}
+}
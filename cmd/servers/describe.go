@@ -20,7 +20,6 @@ package servers
 import (
 	"fmt"
 	"haproxyctl/internal"
-	"log"
 
 	"github.com/spf13/cobra"
 )
@@ -35,30 +34,28 @@ var DescribeServersCmd = &cobra.Command{
 Example:
   haproxyctl describe server mybackend myserver`,
 	Args: cobra.ExactArgs(serverArgsTwo),
-	Run: func(_ *cobra.Command, args []string) {
+	Run: func(cmd *cobra.Command, args []string) {
 		backendName := args[0]
 		serverName := args[1]
-		describeServer(backendName, serverName)
+		describeServer(backendName, serverName, internal.GetFlagBool(cmd, "show-empty"))
 	},
 }
 
+func init() {
+	DescribeServersCmd.Flags().Bool("show-empty", false, "Also list settings that are not set")
+}
+
 // describeServer fetches and prints details of a server within a backend.
-func describeServer(backendName, serverName string) {
+func describeServer(backendName, serverName string, showEmpty bool) {
 	endpoint := fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers/%s", backendName, serverName)
 
 	server, err := internal.GetResource(endpoint)
 	if err != nil {
-		log.Fatalf("Failed to fetch server '%s' in backend '%s': %v", serverName, backendName, err)
+		internal.Fatalf("Failed to fetch server '%s' in backend '%s': %v", serverName, backendName, err)
 	}
 
-	internal.PrintResourceDescription("Server", server, serverDescriptionSections(), nil)
-}
-
-// serverDescriptionSections defines sections for server description output.
-func serverDescriptionSections() map[string][]string {
-	return map[string][]string{
-		"basic":    {"name", "address", "port", "weight"},
-		"health":   {"check", "check_alpn", "check_ssl", "inter", "rise", "fall"},
-		"advanced": {"maxconn", "ssl", "verify", "sni"},
-	}
+	internal.PrintResourceDescription("Server", server, nil, internal.DescribeOptions{
+		Fields:    internal.SchemaFields(serverPayload{}),
+		ShowEmpty: showEmpty,
+	})
 }
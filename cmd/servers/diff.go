@@ -0,0 +1,58 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package servers provides commands to manage HAProxy backend servers.
+package servers
+
+import (
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CompareServerManifest returns the live and desired state of a server
+// manifest. Both sides reference their backend through "parent".
+func CompareServerManifest(data []byte) (internal.ManifestComparison, error) {
+	var manifest ServerConfig
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return internal.ManifestComparison{}, fmt.Errorf("failed to parse server manifest: %w", err)
+	}
+	if err := manifest.NormalizeParent(); err != nil {
+		return internal.ManifestComparison{}, fmt.Errorf("invalid server configuration: %w", err)
+	}
+
+	cmp := internal.ManifestComparison{Kind: "Server", Name: manifest.Parent + "/" + manifest.Name}
+
+	manifest.APIVersion = "haproxyctl/v1"
+	manifest.Kind = "Server"
+	manifest.Backend = ""
+	cmp.Desired = manifest
+
+	obj, err := internal.GetResource(fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers/%s", manifest.Parent, manifest.Name))
+	if err != nil {
+		if internal.IsNotFoundError(err) {
+			return cmp, nil
+		}
+		return cmp, fmt.Errorf("failed to check server existence: %w", err)
+	}
+
+	current := mapServerResourceToConfig(manifest.Parent, obj)
+	current.Parent, current.Backend = current.Backend, ""
+	cmp.Live = current
+	return cmp, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package servers provides commands to manage HAProxy backend servers.
+package servers
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// EnableServerCmd represents "enable server <backend> <server>".
+var EnableServerCmd = &cobra.Command{
+	Use:     "server <backend_name> <server_name>",
+	Aliases: []string{"servers"},
+	Short:   "Put a server back into rotation at runtime",
+	Long: `Set a server's runtime admin state to ready. Shorthand for
+"haproxyctl set server <backend>/<server> --state ready".
+
+Examples:
+  haproxyctl enable server app app1
+  haproxyctl enable server app/app1`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runServerStateShortcut(cmd, args, adminStateReady)
+	},
+}
+
+// DisableServerCmd represents "disable server <backend> <server>".
+var DisableServerCmd = &cobra.Command{
+	Use:     "server <backend_name> <server_name>",
+	Aliases: []string{"servers"},
+	Short:   "Take a server out of rotation at runtime",
+	Long: `Put a server into maintenance (or, with --drain, let its existing
+connections finish) through the runtime API, without a reload. Shorthand
+for "haproxyctl set server <backend>/<server> --state maint|drain".
+
+Examples:
+  haproxyctl disable server app app1
+  haproxyctl disable server app app1 --drain`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		state := adminStateMaint
+		if internal.GetFlagBool(cmd, "drain") {
+			state = adminStateDrain
+		}
+		runServerStateShortcut(cmd, args, state)
+	},
+}
+
+func init() {
+	EnableServerCmd.Flags().Bool("dry-run", false, "Show the change without applying it")
+	DisableServerCmd.Flags().Bool("drain", false, "Drain the server instead of putting it into maintenance")
+	DisableServerCmd.Flags().Bool("dry-run", false, "Show the change without applying it")
+}
+
+// runServerStateShortcut sets the admin state of the server named by args.
+func runServerStateShortcut(cmd *cobra.Command, args []string, state string) {
+	backendName, serverName, err := parseServerRef(args)
+	if err != nil {
+		internal.Fatalf("%v", err)
+	}
+	opts := setServerOptions{State: state, DryRun: internal.GetFlagBool(cmd, "dry-run")}
+	if err := SetServer(backendName, serverName, opts); err != nil {
+		internal.Fatalf("Failed to set state of server '%s/%s': %v", backendName, serverName, err)
+	}
+}
@@ -21,8 +21,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"haproxyctl/internal"
-	"log"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -50,16 +48,19 @@ Examples:
 
 // getServers fetches the list of servers or a specific server from a backend.
 func getServers(cmd *cobra.Command, backendName, serverName string) {
+	watch := internal.WatchRequested(cmd)
+	if watch && serverName != "" {
+		internal.FatalCodef(internal.ExitUsage, "--watch only works when listing servers")
+	}
+
 	// First, ensure the backend exists so that a non-existent backend
 	// does not quietly appear as "No resources found" when listing
 	// servers.
 	if _, err := internal.GetResource("/services/haproxy/configuration/backends/" + backendName); err != nil {
 		if internal.IsNotFoundError(err) {
-			_, _ = fmt.Fprintf(os.Stderr, "Error: backend %q not found\n\n", backendName)
-			_ = cmd.Usage()
-			return
+			internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Backend", backendName))
 		}
-		log.Fatalf("Failed to fetch backend '%s': %v", backendName, err)
+		internal.Fatalf("Failed to fetch backend '%s': %v", backendName, err)
 	}
 
 	endpoint := fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers", backendName)
@@ -70,25 +71,25 @@ func getServers(cmd *cobra.Command, backendName, serverName string) {
 	if err != nil {
 		if internal.IsNotFoundError(err) && serverName != "" {
 			displayName := fmt.Sprintf("%s/%s", backendName, serverName)
-			_, _ = fmt.Fprintln(os.Stdout, internal.ResourceID("Server", displayName)+" not found")
-			return
+			internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Server", displayName))
 		}
-		log.Fatalf("Failed to fetch server(s) from backend '%s': %v", backendName, err)
+		internal.Fatalf("Failed to fetch server(s) from backend '%s': %v", backendName, err)
 	}
 
 	format := internal.GetFlagString(cmd, "output")
 
-	// Decode the JSON into a structured value so FormatOutput can
+	// Decode the JSON into a structured value so FormatOutputForCmd can
 	// render tables / yaml / json consistently.
 	var out interface{}
 	if serverName == "" {
 		var list []map[string]interface{}
 		if err := json.Unmarshal(data, &list); err != nil {
-			log.Fatalf("Failed to parse servers list response: %v\nResponse: %s", err, string(data))
+			internal.Fatalf("Failed to parse servers list response: %v\nResponse: %s", err, string(data))
 		}
 
 		// Ensure stable, predictable ordering of servers by name.
 		internal.SortByStringField(list, "name")
+		internal.SortForCmd(cmd, list)
 
 		// For YAML/JSON, return a manifest-style List of Servers.
 		// For table output, keep the existing flat list.
@@ -99,7 +100,7 @@ func getServers(cmd *cobra.Command, backendName, serverName string) {
 			}
 			out = internal.ManifestList{
 				APIVersion: "haproxyctl/v1",
-				Kind:       "List",
+				Kind:       internal.ListKind,
 				Items:      items,
 			}
 		} else {
@@ -112,7 +113,7 @@ func getServers(cmd *cobra.Command, backendName, serverName string) {
 	} else {
 		var srv map[string]interface{}
 		if err := json.Unmarshal(data, &srv); err != nil {
-			log.Fatalf("Failed to parse server response: %v\nResponse: %s", err, string(data))
+			internal.Fatalf("Failed to parse server response: %v\nResponse: %s", err, string(data))
 		}
 
 		if format == internal.OutputFormatYAML || format == "json" {
@@ -122,38 +123,27 @@ func getServers(cmd *cobra.Command, backendName, serverName string) {
 		}
 	}
 
-	internal.FormatOutput(out, format)
+	internal.FormatOutputForCmd(cmd, internal.WithColumns(out, internal.ColumnSet{Kind: "Server"}), format)
+
+	if watch {
+		endpoint := fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers", backendName)
+		if err := internal.WatchListForCmd(cmd, "Server", endpoint); err != nil {
+			internal.Fatalf("Failed to watch servers of backend '%s': %v", backendName, err)
+		}
+	}
 }
 
 // mapServerResourceToConfig converts a raw API server object into a
 // ServerConfig suitable for manifest-style output (e.g. get -o yaml).
 func mapServerResourceToConfig(backendName string, obj map[string]interface{}) ServerConfig {
-	var sc ServerConfig
-
+	sc := ServerFromAPI(backendName, obj)
 	sc.APIVersion = "haproxyctl/v1"
 	sc.Kind = "Server"
-
-	if v, ok := obj["name"].(string); ok {
-		sc.Name = v
-	}
-	if v, ok := obj["address"].(string); ok {
-		sc.Address = v
-	}
-	if p, ok := obj["port"].(float64); ok {
-		sc.Port = int(p)
-	}
-	if w, ok := obj["weight"].(float64); ok {
-		sc.Weight = int(w)
-	}
-	if v, ok := obj["ssl"].(string); ok && v == "enabled" {
-		sc.SSL = true
-	}
-
-	sc.Backend = backendName
 	return sc
 }
 
 func init() {
 	// Inherit the global -o flag for output formatting
 	GetServersCmd.Flags().StringP("output", "o", "", "Output format: yaml or json")
+	internal.AddWatchFlags(GetServersCmd)
 }
@@ -0,0 +1,178 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package servers provides commands to manage HAProxy backend servers.
+package servers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// Runtime admin states accepted by "set server --state".
+const (
+	adminStateReady = "ready"
+	adminStateDrain = "drain"
+	adminStateMaint = "maint"
+)
+
+var adminStates = []string{adminStateReady, adminStateDrain, adminStateMaint}
+
+// SetServerCmd represents "set server <backend>/<server>".
+var SetServerCmd = &cobra.Command{
+	Use:     "server <backend_name>/<server_name>",
+	Aliases: []string{"servers"},
+	Short:   "Change the runtime state or weight of a server",
+	Long: `Change a server's admin state and/or weight through the runtime API. The
+change takes effect immediately, without editing the configuration or
+reloading HAProxy, and is lost when HAProxy restarts.
+
+--state accepts:
+  ready  the server takes traffic normally
+  drain  existing connections finish, no new ones are sent
+  maint  the server is taken out of rotation entirely
+
+--weight (0-256) sets the effective weight, e.g. to shift traffic gradually
+to a new version during a deployment.
+
+Examples:
+  haproxyctl set server app/app1 --state drain
+  haproxyctl set server app/app1 --state maint
+  haproxyctl set server app app1 --state ready
+  haproxyctl set server app/canary --weight 25`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		backendName, serverName, err := parseServerRef(args)
+		if err != nil {
+			internal.Fatalf("%v", err)
+		}
+		opts := setServerOptions{
+			State:  internal.GetFlagString(cmd, "state"),
+			DryRun: internal.GetFlagBool(cmd, "dry-run"),
+		}
+		if cmd.Flags().Changed("weight") {
+			weight := internal.GetFlagInt(cmd, "weight")
+			opts.Weight = &weight
+		}
+		if err := SetServer(backendName, serverName, opts); err != nil {
+			internal.Fatalf("Failed to set server '%s/%s': %v", backendName, serverName, err)
+		}
+	},
+}
+
+func init() {
+	SetServerCmd.Flags().String("state", "", "Admin state: ready, drain or maint")
+	SetServerCmd.Flags().Int("weight", 0, "Effective weight (0-256)")
+	SetServerCmd.Flags().Bool("dry-run", false, "Show the change without applying it")
+}
+
+type setServerOptions struct {
+	// State is the admin state to set; empty leaves it alone.
+	State string
+	// Weight is the effective weight to set; nil leaves it alone.
+	Weight *int
+	DryRun bool
+}
+
+// parseServerRef accepts "<backend>/<server>" or "<backend> <server>".
+func parseServerRef(args []string) (string, string, error) {
+	if len(args) == 2 {
+		return args[0], args[1], nil
+	}
+	backendName, serverName, ok := strings.Cut(args[0], "/")
+	if !ok || backendName == "" || serverName == "" {
+		return "", "", fmt.Errorf("expected <backend>/<server>, got %q", args[0])
+	}
+	return backendName, serverName, nil
+}
+
+// SetServer changes the runtime admin state and/or weight of a server in
+// one request. Settings that already have the requested value are skipped.
+func SetServer(backendName, serverName string, opts setServerOptions) error {
+	if opts.State == "" && opts.Weight == nil {
+		return errors.New("at least one of --state or --weight is required")
+	}
+	if opts.State != "" && !slices.Contains(adminStates, opts.State) {
+		return fmt.Errorf("invalid --state %q (allowed: %s)", opts.State, strings.Join(adminStates, ", "))
+	}
+	if opts.Weight != nil && (*opts.Weight < 0 || *opts.Weight > maxServerWeight) {
+		return fmt.Errorf("invalid --weight %d (allowed: 0-%d)", *opts.Weight, maxServerWeight)
+	}
+
+	path := fmt.Sprintf("/services/haproxy/runtime/backends/%s/servers/%s", backendName, serverName)
+	current, err := internal.GetResource(path)
+	if err != nil {
+		return internal.FormatAPIError("Server", backendName+"/"+serverName, "get", err)
+	}
+
+	serverID := backendName + "/" + serverName
+	payload := map[string]interface{}{}
+	var changes []string
+
+	if opts.State != "" {
+		previous, _ := current["admin_state"].(string)
+		if previous != opts.State {
+			payload["admin_state"] = opts.State
+			changes = append(changes, fmt.Sprintf("state %s -> %s", previous, opts.State))
+		}
+	}
+	if opts.Weight != nil {
+		previous, err := effectiveWeight(backendName, serverName)
+		if err != nil {
+			return err
+		}
+		if previous != *opts.Weight {
+			payload["weight"] = *opts.Weight
+			changes = append(changes, fmt.Sprintf("weight %d -> %d", previous, *opts.Weight))
+		}
+	}
+
+	if len(changes) == 0 {
+		internal.PrintStatus("Server", serverID, internal.ActionUnchanged)
+		return nil
+	}
+	if opts.DryRun {
+		internal.PrintStatus("Server", serverID, strings.Join(changes, ", "))
+		internal.PrintDryRun()
+		return nil
+	}
+
+	if _, err := internal.SendRequest("PUT", path, nil, payload); err != nil {
+		return fmt.Errorf("failed to update runtime server: %w", err)
+	}
+	internal.PrintStatus("Server", serverID, strings.Join(changes, ", "))
+	return nil
+}
+
+// effectiveWeight returns the weight HAProxy is currently using for a
+// server, according to the runtime stats.
+func effectiveWeight(backendName, serverName string) (int, error) {
+	stats, err := runtimeServerStats(backendName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch current weight: %w", err)
+	}
+	w, ok := stats[serverName]["weight"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("runtime stats report no weight for server %q", serverName)
+	}
+	return int(w), nil
+}
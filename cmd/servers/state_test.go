@@ -0,0 +1,132 @@
+package servers
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+)
+
+func TestSetServer_StateIsRuntimeOnly(t *testing.T) {
+	srv := newWeightsServer(t)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := SetServer("app", "app1", setServerOptions{State: adminStateDrain}); err != nil {
+			t.Fatalf("SetServer failed: %v", err)
+		}
+		// Setting the same state again is a no-op.
+		if err := SetServer("app", "app1", setServerOptions{State: adminStateDrain}); err != nil {
+			t.Fatalf("second SetServer failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "server/app/app1 state ready -> drain") || !strings.Contains(output, "server/app/app1 unchanged") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if _, admin := srv.ServerState("app", "app1"); admin != adminStateDrain {
+		t.Fatalf("admin state = %q, want drain", admin)
+	}
+	if srv.Version() != 1 {
+		t.Fatalf("version = %d, want 1 (runtime changes do not touch the configuration)", srv.Version())
+	}
+}
+
+func TestSetServer_DryRunAndValidation(t *testing.T) {
+	srv := newWeightsServer(t)
+
+	internal.CaptureStdout(t, func() {
+		if err := SetServer("app", "app2", setServerOptions{State: adminStateMaint, DryRun: true}); err != nil {
+			t.Fatalf("dry-run SetServer failed: %v", err)
+		}
+	})
+	if _, admin := srv.ServerState("app", "app2"); admin != adminStateReady {
+		t.Fatalf("dry run changed admin state to %q", admin)
+	}
+
+	if err := SetServer("app", "app2", setServerOptions{State: "offline"}); err == nil {
+		t.Fatal("expected error for invalid state")
+	}
+	if err := SetServer("app", "nope", setServerOptions{State: adminStateMaint}); err == nil {
+		t.Fatal("expected error for unknown server")
+	}
+	if err := SetServer("app", "app2", setServerOptions{}); err == nil {
+		t.Fatal("expected error without --state or --weight")
+	}
+	tooHeavy := maxServerWeight + 1
+	if err := SetServer("app", "app2", setServerOptions{Weight: &tooHeavy}); err == nil {
+		t.Fatal("expected error for out-of-range weight")
+	}
+}
+
+func TestSetServer_Weight(t *testing.T) {
+	srv := newWeightsServer(t)
+
+	weight := 25
+	output := internal.CaptureStdout(t, func() {
+		if err := SetServer("app", "app1", setServerOptions{Weight: &weight}); err != nil {
+			t.Fatalf("SetServer failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "server/app/app1 weight 100 -> 25") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+
+	rows, err := GetWeights("app")
+	if err != nil {
+		t.Fatalf("GetWeights failed: %v", err)
+	}
+	if rows[0].Configured != 100 || rows[0].Effective == nil || *rows[0].Effective != 25 {
+		t.Fatalf("app1 weights = %+v (effective %v), want configured 100, effective 25", rows[0], rows[0].Effective)
+	}
+	if srv.Version() != 1 {
+		t.Fatalf("version = %d, want 1 (runtime changes do not touch the configuration)", srv.Version())
+	}
+
+	// Draining to weight 0 is a valid runtime weight.
+	zero := 0
+	internal.CaptureStdout(t, func() {
+		if err := SetServer("app", "app1", setServerOptions{Weight: &zero, State: adminStateDrain}); err != nil {
+			t.Fatalf("SetServer failed: %v", err)
+		}
+	})
+	if rows, _ := GetWeights("app"); *rows[0].Effective != 0 {
+		t.Fatalf("effective weight = %d, want 0", *rows[0].Effective)
+	}
+}
+
+func TestParseServerRef(t *testing.T) {
+	t.Parallel()
+
+	if b, s, err := parseServerRef([]string{"app/app1"}); err != nil || b != "app" || s != "app1" {
+		t.Fatalf("parseServerRef(app/app1) = %q, %q, %v", b, s, err)
+	}
+	if b, s, err := parseServerRef([]string{"app", "app1"}); err != nil || b != "app" || s != "app1" {
+		t.Fatalf("parseServerRef(app app1) = %q, %q, %v", b, s, err)
+	}
+	if _, _, err := parseServerRef([]string{"app"}); err == nil {
+		t.Fatal("expected error without a server name")
+	}
+}
+
+func TestEnableDisableServerShortcuts(t *testing.T) {
+	srv := newWeightsServer(t)
+
+	if err := DisableServerCmd.Flags().Set("drain", "true"); err != nil {
+		t.Fatalf("set flag: %v", err)
+	}
+	t.Cleanup(func() { _ = DisableServerCmd.Flags().Set("drain", "false") })
+
+	internal.CaptureStdout(t, func() {
+		DisableServerCmd.Run(DisableServerCmd, []string{"app", "app1"})
+	})
+	if _, admin := srv.ServerState("app", "app1"); admin != adminStateDrain {
+		t.Fatalf("admin state after disable --drain = %q, want drain", admin)
+	}
+
+	internal.CaptureStdout(t, func() {
+		EnableServerCmd.Run(EnableServerCmd, []string{"app/app1"})
+	})
+	if _, admin := srv.ServerState("app", "app1"); admin != adminStateReady {
+		t.Fatalf("admin state after enable = %q, want ready", admin)
+	}
+}
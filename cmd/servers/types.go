@@ -20,7 +20,11 @@ package servers
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+
 	"haproxyctl/internal"
+	"haproxyctl/pkg/client"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -42,37 +46,181 @@ type ServerConfig struct {
 	Weight  int    `json:"weight,omitempty" yaml:"weight,omitempty"`
 	SSL     bool   `json:"ssl,omitempty" yaml:"ssl,omitempty"`
 
+	// Health checking. Inter uses the duration syntax of the timeouts
+	// (e.g. 2s); rise and fall count consecutive check results.
+	Check bool   `json:"check,omitempty" yaml:"check,omitempty"`
+	Inter string `json:"inter,omitempty" yaml:"inter,omitempty"`
+	Rise  int    `json:"rise,omitempty" yaml:"rise,omitempty"`
+	Fall  int    `json:"fall,omitempty" yaml:"fall,omitempty"`
+
+	Backup  bool   `json:"backup,omitempty" yaml:"backup,omitempty"`
+	Maxconn int    `json:"maxconn,omitempty" yaml:"maxconn,omitempty"`
+	Cookie  string `json:"cookie,omitempty" yaml:"cookie,omitempty"`
+
+	// TLS and protocol settings towards the server.
+	Verify      string `json:"verify,omitempty" yaml:"verify,omitempty"`
+	SNI         string `json:"sni,omitempty" yaml:"sni,omitempty"`
+	ALPN        string `json:"alpn,omitempty" yaml:"alpn,omitempty"`
+	Proto       string `json:"proto,omitempty" yaml:"proto,omitempty"`
+	SendProxy   bool   `json:"send_proxy,omitempty" yaml:"send_proxy,omitempty"`       //nolint:tagliatelle // HAProxy field name
+	SendProxyV2 bool   `json:"send_proxy_v2,omitempty" yaml:"send_proxy_v2,omitempty"` //nolint:tagliatelle // HAProxy field name
+
 	// Backend/Parent are used client-side to determine the parent backend
 	// section (path parameter) but are not part of the v3 server object.
 	Backend string `yaml:"backend,omitempty"`
 	Parent  string `yaml:"parent,omitempty"`
 }
 
+const stateEnabled = "enabled"
+
 // serverPayload is the subset of ServerConfig that is sent to the
-// HAProxy Data Plane API, with ssl encoded as the v3 enum.
+// HAProxy Data Plane API, with the switches encoded as v3 enums and
+// inter in milliseconds.
+//
+//nolint:tagliatelle
 type serverPayload struct {
-	Name    string `json:"name"`
-	Address string `json:"address"`
-	Port    int    `json:"port"`
-	Weight  int    `json:"weight,omitempty"`
-	SSL     string `json:"ssl,omitempty"`
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	Port        int    `json:"port"`
+	Weight      int    `json:"weight,omitempty"`
+	SSL         string `json:"ssl,omitempty"`
+	Check       string `json:"check,omitempty"`
+	Inter       int    `json:"inter,omitempty"`
+	Rise        int    `json:"rise,omitempty"`
+	Fall        int    `json:"fall,omitempty"`
+	Backup      string `json:"backup,omitempty"`
+	Maxconn     int    `json:"maxconn,omitempty"`
+	Cookie      string `json:"cookie,omitempty"`
+	Verify      string `json:"verify,omitempty"`
+	SNI         string `json:"sni,omitempty"`
+	ALPN        string `json:"alpn,omitempty"`
+	Proto       string `json:"proto,omitempty"`
+	SendProxy   string `json:"send-proxy,omitempty"`
+	SendProxyV2 string `json:"send-proxy-v2,omitempty"`
+}
+
+// enabledIf returns the v3 "enabled" enum for a set switch.
+func enabledIf(set bool) string {
+	if set {
+		return stateEnabled
+	}
+	return ""
 }
 
 // toPayload converts a ServerConfig into the wire-format structure
 // expected by the v3 Data Plane API.
 func (s ServerConfig) toPayload() serverPayload {
 	payload := serverPayload{
-		Name:    s.Name,
-		Address: s.Address,
-		Port:    s.Port,
-		Weight:  s.Weight,
-	}
-	if s.SSL {
-		payload.SSL = "enabled"
+		Name:        s.Name,
+		Address:     s.Address,
+		Port:        s.Port,
+		Weight:      s.Weight,
+		SSL:         enabledIf(s.SSL),
+		Check:       enabledIf(s.Check),
+		Rise:        s.Rise,
+		Fall:        s.Fall,
+		Backup:      enabledIf(s.Backup),
+		Maxconn:     s.Maxconn,
+		Cookie:      s.Cookie,
+		Verify:      s.Verify,
+		SNI:         s.SNI,
+		ALPN:        s.ALPN,
+		Proto:       s.Proto,
+		SendProxy:   enabledIf(s.SendProxy),
+		SendProxyV2: enabledIf(s.SendProxyV2),
 	}
+	// Validate rejects a bad inter before any payload is built.
+	payload.Inter, _ = internal.ParseDurationToMillis(s.Inter)
 	return payload
 }
 
+// toClient returns the payload of s as a server of the typed client.
+func (s ServerConfig) toClient() (client.Server, error) {
+	var out client.Server
+	return out, internal.ConvertPayload(s.toPayload(), &out)
+}
+
+// Equal reports whether two servers send the same object to the Data
+// Plane API, so "2000" and "2s" for inter compare equal.
+func (s ServerConfig) Equal(o ServerConfig) bool {
+	return s.toPayload() == o.toPayload()
+}
+
+// ServerFromAPI converts an API server object into a ServerConfig of the
+// given backend.
+func ServerFromAPI(backendName string, obj map[string]interface{}) ServerConfig {
+	var sc ServerConfig
+	sc.Name, _ = obj["name"].(string)
+	sc.Address, _ = obj["address"].(string)
+	sc.Port, _ = internal.GetIntField(obj, "port")
+	sc.Weight, _ = internal.GetIntField(obj, "weight")
+	sc.SSL = obj["ssl"] == stateEnabled
+	sc.Check = obj["check"] == stateEnabled
+	if ms, ok := internal.GetIntField(obj, "inter"); ok {
+		sc.Inter = internal.FormatMillisAsDuration(ms)
+	}
+	sc.Rise, _ = internal.GetIntField(obj, "rise")
+	sc.Fall, _ = internal.GetIntField(obj, "fall")
+	sc.Backup = obj["backup"] == stateEnabled
+	sc.Maxconn, _ = internal.GetIntField(obj, "maxconn")
+	sc.Cookie, _ = obj["cookie"].(string)
+	sc.Verify, _ = obj["verify"].(string)
+	sc.SNI, _ = obj["sni"].(string)
+	sc.ALPN, _ = obj["alpn"].(string)
+	sc.Proto, _ = obj["proto"].(string)
+	sc.SendProxy = obj["send-proxy"] == stateEnabled
+	sc.SendProxyV2 = obj["send-proxy-v2"] == stateEnabled
+	sc.Backend = backendName
+	return sc
+}
+
+// ParseFlagOption sets one "key=value" setting of a "--server" flag on a
+// backend, e.g. check=true or inter=2s. It reports whether key is known.
+func (s *ServerConfig) ParseFlagOption(key, value string) bool {
+	isTrue := strings.EqualFold(value, "true") || value == stateEnabled
+	switch key {
+	case "name":
+		s.Name = value
+	case "address":
+		s.Address = value
+	case "port":
+		s.Port, _ = strconv.Atoi(value)
+	case "weight":
+		s.Weight, _ = strconv.Atoi(value)
+	case "ssl":
+		s.SSL = isTrue
+	case "check":
+		s.Check = isTrue
+	case "inter":
+		s.Inter = value
+	case "rise":
+		s.Rise, _ = strconv.Atoi(value)
+	case "fall":
+		s.Fall, _ = strconv.Atoi(value)
+	case "backup":
+		s.Backup = isTrue
+	case "maxconn":
+		s.Maxconn, _ = strconv.Atoi(value)
+	case "cookie":
+		s.Cookie = value
+	case "verify":
+		s.Verify = value
+	case "sni":
+		s.SNI = value
+	case "alpn":
+		s.ALPN = value
+	case "proto":
+		s.Proto = value
+	case "send_proxy", "send-proxy":
+		s.SendProxy = isTrue
+	case "send_proxy_v2", "send-proxy-v2":
+		s.SendProxyV2 = isTrue
+	default:
+		return false
+	}
+	return true
+}
+
 // NormalizeParent ensures compatibility between `parent` and `backend`.
 func (s *ServerConfig) NormalizeParent() error {
 	if s.Parent == "" && s.Backend != "" {
@@ -102,6 +250,19 @@ func (s *ServerConfig) LoadFromFlags(cmd *cobra.Command, backendName, serverName
 	s.Port = internal.GetFlagInt(cmd, "port")
 	s.Weight = internal.GetFlagInt(cmd, "weight")
 	s.SSL = internal.GetFlagBool(cmd, "ssl")
+	s.Check = internal.GetFlagBool(cmd, "check")
+	s.Inter = internal.GetFlagString(cmd, "inter")
+	s.Rise = internal.GetFlagInt(cmd, "rise")
+	s.Fall = internal.GetFlagInt(cmd, "fall")
+	s.Backup = internal.GetFlagBool(cmd, "backup")
+	s.Maxconn = internal.GetFlagInt(cmd, "maxconn")
+	s.Cookie = internal.GetFlagString(cmd, "cookie")
+	s.Verify = internal.GetFlagString(cmd, "verify")
+	s.SNI = internal.GetFlagString(cmd, "sni")
+	s.ALPN = internal.GetFlagString(cmd, "alpn")
+	s.Proto = internal.GetFlagString(cmd, "proto")
+	s.SendProxy = internal.GetFlagBool(cmd, "send-proxy")
+	s.SendProxyV2 = internal.GetFlagBool(cmd, "send-proxy-v2")
 }
 
 // Validate performs basic validation on the ServerConfig.
@@ -118,5 +279,29 @@ func (s *ServerConfig) Validate() error {
 	if s.Port == 0 {
 		return errors.New("server port is required")
 	}
+	if err := internal.ValidatePort(s.Port); err != nil {
+		return err
+	}
+	return s.ValidateOptions()
+}
+
+// ValidateOptions checks the health check, TLS and proxy settings. It is
+// shared with the servers listed in a Backend manifest.
+func (s *ServerConfig) ValidateOptions() error {
+	if _, err := internal.ParseDurationToMillis(s.Inter); err != nil {
+		return fmt.Errorf("invalid inter: %w", err)
+	}
+	if s.Rise < 0 || s.Fall < 0 || s.Maxconn < 0 {
+		return errors.New("rise, fall and maxconn cannot be negative")
+	}
+	if s.Verify != "" && s.Verify != "none" && s.Verify != "required" {
+		return fmt.Errorf("invalid verify %q (allowed: none, required)", s.Verify)
+	}
+	if s.SendProxy && s.SendProxyV2 {
+		return errors.New("send_proxy and send_proxy_v2 are mutually exclusive")
+	}
+	if !s.SSL && (s.Verify != "" || s.SNI != "") {
+		return errors.New("verify and sni need ssl")
+	}
 	return nil
 }
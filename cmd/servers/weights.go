@@ -0,0 +1,306 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package servers provides commands to manage HAProxy backend servers.
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"haproxyctl/internal"
+	"haproxyctl/pkg/client"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	implicitServerWeight = 1
+	maxServerWeight      = 256
+)
+
+// GetWeightsCmd represents "get weights <backend>".
+var GetWeightsCmd = &cobra.Command{
+	Use:     "weights <backend_name>",
+	Aliases: []string{"weight"},
+	Short:   "Show configured and effective weights of a backend's servers",
+	Long: `Show, per server, the weight from the configuration next to the effective
+weight HAProxy is currently using (from the runtime stats), which differs
+when weights were changed at runtime or a server is draining.
+
+Examples:
+  haproxyctl get weights app
+  haproxyctl get weights app -o yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rows, err := GetWeights(args[0])
+		if err != nil {
+			internal.Fatalf("Failed to fetch weights of backend '%s': %v", args[0], err)
+		}
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(weightRows(rows), weightColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// SetWeightsCmd represents "set weights <backend>".
+var SetWeightsCmd = &cobra.Command{
+	Use:     "weights <backend_name>",
+	Aliases: []string{"weight"},
+	Short:   "Set the weight of many servers of a backend at once",
+	Long: `Set the configured weight of every server (--all) or of the servers listed
+in a YAML file (--from-file) in one transaction. The file maps server names
+to weights:
+
+  app1: 100
+  app2: 50
+
+Only servers whose weight actually changes are updated.
+
+Examples:
+  haproxyctl set weights app --all 100
+  haproxyctl set weights app --from-file weights.yaml --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		backendName := args[0]
+		file := internal.GetFlagString(cmd, "from-file")
+		all := cmd.Flags().Changed("all")
+		if all == (file != "") {
+			internal.FatalCodef(internal.ExitUsage, "exactly one of --all or --from-file is required")
+		}
+
+		var desired map[string]int
+		if file != "" {
+			data, err := internal.LoadYAMLFile(file)
+			if err != nil {
+				internal.Fatalf("Failed to read weights file: %v", err)
+			}
+			if err := yaml.Unmarshal(data, &desired); err != nil {
+				internal.Fatalf("Failed to parse weights file %s: %v", file, err)
+			}
+		}
+
+		opts := setWeightsOptions{
+			Weights: desired,
+			DryRun:  internal.GetFlagBool(cmd, "dry-run"),
+		}
+		if all {
+			weight := internal.GetFlagInt(cmd, "all")
+			opts.All = &weight
+		}
+		if err := SetWeights(backendName, opts); err != nil {
+			internal.Fatalf("Failed to set weights on backend '%s': %v", backendName, err)
+		}
+	},
+}
+
+func init() {
+	SetWeightsCmd.Flags().Int("all", 0, "Weight to give every server of the backend")
+	SetWeightsCmd.Flags().String("from-file", "", "YAML file mapping server names to weights")
+	SetWeightsCmd.Flags().Bool("dry-run", false, "Show the weight changes without applying them")
+}
+
+// serverWeight is one row of "get weights".
+type serverWeight struct {
+	Server     string `json:"server" yaml:"server"`
+	Configured int    `json:"configured" yaml:"configured"`
+	Effective  *int   `json:"effective,omitempty" yaml:"effective,omitempty"`
+	Status     string `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// weightColumns are the columns of "get weights".
+var weightColumns = internal.ColumnSet{Default: []string{"server", "configured", "effective", "status"}}
+
+// weightRows converts "get weights" rows to the maps the output helpers
+// render, leaving out the effective weight and status when unknown.
+func weightRows(rows []serverWeight) []interface{} {
+	out := make([]interface{}, 0, len(rows))
+	for _, r := range rows {
+		row := map[string]interface{}{"server": r.Server, "configured": r.Configured}
+		if r.Effective != nil {
+			row["effective"] = *r.Effective
+		}
+		if r.Status != "" {
+			row["status"] = r.Status
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+// configuredWeight returns the weight of a configuration server object,
+// defaulting to HAProxy's implicit weight of 1.
+func configuredWeight(srv map[string]interface{}) int {
+	if w, ok := srv["weight"].(float64); ok {
+		return int(w)
+	}
+	return implicitServerWeight
+}
+
+// GetWeights joins the configured weights of a backend's servers with the
+// effective weights reported by the runtime stats.
+func GetWeights(backendName string) ([]serverWeight, error) {
+	servers, err := internal.GetResourceList(fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers", backendName))
+	if err != nil {
+		return nil, internal.FormatAPIError("Backend", backendName, "get", err)
+	}
+	runtime, err := runtimeServerStats(backendName)
+	if err != nil {
+		// Configured weights are still useful without runtime access.
+		internal.Warnf("effective weights unavailable: %v", err)
+	}
+
+	rows := make([]serverWeight, 0, len(servers))
+	for _, srv := range servers {
+		name, _ := srv["name"].(string)
+		row := serverWeight{Server: name, Configured: configuredWeight(srv)}
+		if st, ok := runtime[name]; ok {
+			if w, ok := st["weight"].(float64); ok {
+				effective := int(w)
+				row.Effective = &effective
+			}
+			row.Status, _ = st["status"].(string)
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Server < rows[j].Server })
+	return rows, nil
+}
+
+// runtimeServerStats returns the native stats of a backend's servers keyed
+// by server name.
+func runtimeServerStats(backendName string) (map[string]map[string]interface{}, error) {
+	raw, err := internal.SendRequest("GET", "/services/haproxy/stats/native",
+		map[string]string{"type": "server", "parent": backendName}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Stats []struct {
+			Type    string                 `json:"type"`
+			Name    string                 `json:"name"`
+			Backend string                 `json:"backend_name"` //nolint:tagliatelle // Data Plane API field name
+			Stats   map[string]interface{} `json:"stats"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse native stats response: %w", err)
+	}
+
+	out := make(map[string]map[string]interface{})
+	for _, s := range payload.Stats {
+		if s.Type == "server" && s.Backend == backendName {
+			out[s.Name] = s.Stats
+		}
+	}
+	return out, nil
+}
+
+type setWeightsOptions struct {
+	// All, when set, is the weight for every server.
+	All *int
+	// Weights maps server names to weights (used when All is nil).
+	Weights map[string]int
+	DryRun  bool
+}
+
+// SetWeights updates the configured weight of many servers in one
+// transaction, skipping servers that already have the requested weight.
+func SetWeights(backendName string, opts setWeightsOptions) error {
+	base := fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers", backendName)
+	servers, err := internal.GetResourceList(base)
+	if err != nil {
+		return internal.FormatAPIError("Backend", backendName, "get", err)
+	}
+
+	byName := make(map[string]map[string]interface{}, len(servers))
+	for _, srv := range servers {
+		name, _ := srv["name"].(string)
+		byName[name] = srv
+	}
+
+	desired := opts.Weights
+	if opts.All != nil {
+		desired = make(map[string]int, len(byName))
+		for name := range byName {
+			desired[name] = *opts.All
+		}
+	}
+	if len(desired) == 0 {
+		return errors.New("no server weights given")
+	}
+
+	names := make([]string, 0, len(desired))
+	for name, weight := range desired {
+		if _, ok := byName[name]; !ok {
+			return fmt.Errorf("server %q not found in backend %q", name, backendName)
+		}
+		if weight < 0 || weight > maxServerWeight {
+			return fmt.Errorf("invalid weight %d for server %q (must be 0-%d)", weight, name, maxServerWeight)
+		}
+		if configuredWeight(byName[name]) != weight {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		internal.PrintStatus("Backend", backendName, "weights "+internal.ActionUnchanged)
+		return nil
+	}
+
+	if opts.DryRun {
+		for _, name := range names {
+			_, _ = fmt.Fprintf(os.Stdout, "%s weight %d -> %d\n",
+				internal.ResourceID("Server", backendName+"/"+name), configuredWeight(byName[name]), desired[name])
+		}
+		internal.PrintDryRun()
+		return nil
+	}
+
+	tx, err := internal.StartTransaction()
+	if err != nil {
+		return err
+	}
+	api := internal.API()
+	for _, name := range names {
+		var srv client.Server
+		if err := internal.ConvertPayload(byName[name], &srv); err != nil {
+			return err
+		}
+		weight := desired[name]
+		srv.Weight = &weight
+		if err := api.UpdateServer(context.Background(), tx.Client(), backendName, srv); err != nil {
+			updateErr := internal.FormatAPIError("Server", backendName+"/"+name, "update", err)
+			if abortErr := tx.Abort(); abortErr != nil {
+				return errors.Join(updateErr, abortErr)
+			}
+			return updateErr
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		internal.PrintStatus("Server", backendName+"/"+name, internal.ActionConfigured)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package servers
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func newWeightsServer(t *testing.T) *testserver.Server {
+	t.Helper()
+
+	srv := testserver.New(t)
+	srv.AddBackend(map[string]interface{}{"name": "app"})
+	srv.AddServer("app", map[string]interface{}{"name": "app1", "address": "10.0.0.1", "port": 80, "weight": 100})
+	srv.AddServer("app", map[string]interface{}{"name": "app2", "address": "10.0.0.2", "port": 80, "weight": 50})
+	srv.AddServer("app", map[string]interface{}{"name": "app3", "address": "10.0.0.3", "port": 80})
+	return srv
+}
+
+func TestGetWeights_ConfiguredAndEffective(t *testing.T) {
+	srv := newWeightsServer(t)
+	srv.SetServerWeight("app", "app2", 10)
+	srv.SetServerState("app", "app3", "up", "drain")
+
+	rows, err := GetWeights("app")
+	if err != nil {
+		t.Fatalf("GetWeights failed: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3: %+v", len(rows), rows)
+	}
+
+	want := []struct {
+		server                string
+		configured, effective int
+		status                string
+	}{
+		{"app1", 100, 100, "UP"},
+		{"app2", 50, 10, "UP"},
+		{"app3", 1, 1, "DRAIN"},
+	}
+	for i, w := range want {
+		r := rows[i]
+		if r.Server != w.server || r.Configured != w.configured || r.Effective == nil || *r.Effective != w.effective || r.Status != w.status {
+			t.Fatalf("row %d = %+v (effective %v), want %+v", i, r, r.Effective, w)
+		}
+	}
+}
+
+func TestGetWeightsCmd_Table(t *testing.T) {
+	srv := newWeightsServer(t)
+	srv.SetServerWeight("app", "app2", 10)
+
+	output := internal.CaptureStdout(t, func() {
+		GetWeightsCmd.Run(GetWeightsCmd, []string{"app"})
+	})
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 5 || strings.Join(strings.Fields(lines[0]), " ") != "SERVER CONFIGURED EFFECTIVE STATUS" {
+		t.Fatalf("unexpected table:\n%s", output)
+	}
+	if got := strings.Join(strings.Fields(lines[3]), " "); got != "app2 50 10 UP" {
+		t.Fatalf("app2 row = %q, want \"app2 50 10 UP\":\n%s", got, output)
+	}
+}
+
+func TestSetWeights_AllInOneTransaction(t *testing.T) {
+	srv := newWeightsServer(t)
+
+	weight := 100
+	output := internal.CaptureStdout(t, func() {
+		if err := SetWeights("app", setWeightsOptions{All: &weight}); err != nil {
+			t.Fatalf("SetWeights failed: %v", err)
+		}
+	})
+
+	// app1 already has weight 100 and is left alone.
+	if strings.Contains(output, "app1") || !strings.Contains(output, "server/app/app2 configured") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	for _, s := range srv.Servers("app") {
+		if s["weight"] != float64(100) && s["weight"] != 100 {
+			t.Fatalf("server %v has weight %v, want 100", s["name"], s["weight"])
+		}
+	}
+	if srv.Version() != 2 {
+		t.Fatalf("version = %d, want 2 (single transaction)", srv.Version())
+	}
+}
+
+func TestSetWeights_FromFileRejectsUnknownServer(t *testing.T) {
+	srv := newWeightsServer(t)
+
+	err := SetWeights("app", setWeightsOptions{Weights: map[string]int{"app1": 10, "nope": 5}})
+	if err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Fatalf("expected unknown server error, got %v", err)
+	}
+	if srv.Version() != 1 {
+		t.Fatalf("configuration changed despite validation error")
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/maps"
+	"haproxyctl/cmd/servers"
+
+	"github.com/spf13/cobra"
+)
+
+// setCmd represents the "set" command, which changes individual settings
+// of existing resources.
+var setCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set specific settings on existing HAProxy resources",
+	Long: `Change specific settings of existing resources without editing whole
+manifests.
+
+Examples:
+  haproxyctl set weights app --all 100
+  haproxyctl set server app/app1 --state drain
+  haproxyctl set map routes.map api.example.com be_api`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+
+	setCmd.AddCommand(servers.SetWeightsCmd)
+	setCmd.AddCommand(servers.SetServerCmd)
+	setCmd.AddCommand(maps.SetMapCmd)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spoe provides commands to manage SPOE (Stream Processing Offload
+// Engine) files: their scopes, agents, messages and groups.
+package spoe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"haproxyctl/internal"
+)
+
+// SPOE files are versioned apart from the main configuration, so changes
+// to them go through a transaction of the file itself rather than
+// internal.WithTransaction.
+
+// fileTransaction is an open transaction on one SPOE file.
+type fileTransaction struct {
+	file string
+	ID   string `json:"id"`
+}
+
+// params returns the query parameters that stage a request in t.
+func (t *fileTransaction) params() map[string]string {
+	return map[string]string{"transaction_id": t.ID}
+}
+
+// withFileTransaction runs fn inside a new transaction on an SPOE file and
+// commits it, or aborts it when fn fails.
+func withFileTransaction(file string, fn func(tx *fileTransaction) error) error {
+	data, err := internal.SendRequest("GET", filePath(file)+"/version", nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch SPOE file version: %w", err)
+	}
+	var version int
+	if err := json.Unmarshal(data, &version); err != nil {
+		return fmt.Errorf("failed to parse SPOE file version: %w", err)
+	}
+
+	data, err = internal.SendRequest("POST", filePath(file)+"/transactions", map[string]string{"version": strconv.Itoa(version)}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start SPOE transaction: %w", err)
+	}
+	tx := &fileTransaction{file: file}
+	if err := json.Unmarshal(data, tx); err != nil || tx.ID == "" {
+		return fmt.Errorf("unexpected SPOE transaction response: %s", string(data))
+	}
+
+	txPath := filePath(file) + "/transactions/" + url.PathEscape(tx.ID)
+	if err := fn(tx); err != nil {
+		if _, abortErr := internal.SendRequest("DELETE", txPath, nil, nil); abortErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to delete SPOE transaction %s: %w", tx.ID, abortErr))
+		}
+		return err
+	}
+	if _, err := internal.SendRequest("PUT", txPath, nil, nil); err != nil {
+		return fmt.Errorf("failed to commit SPOE transaction %s: %w", tx.ID, err)
+	}
+	return nil
+}
+
+// fileNames lists the SPOE files known to the Data Plane API.
+func fileNames() ([]string, error) {
+	data, err := internal.SendRequest("GET", spoeFilesPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse SPOE files: %w", err)
+	}
+	return names, nil
+}
+
+// getSPOEManifest reads an SPOE file into a manifest.
+func getSPOEManifest(file string) (*SPOEManifest, error) {
+	data, err := internal.SendRequest("GET", filePath(file)+"/scopes", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var scopes []string
+	if err := json.Unmarshal(data, &scopes); err != nil {
+		return nil, fmt.Errorf("failed to parse SPOE scopes: %w", err)
+	}
+
+	manifest := &SPOEManifest{Name: file}
+	for _, raw := range scopes {
+		sc := Scope{Name: trimBrackets(raw)}
+		base := scopePath(file, sc.Name)
+		agents, err := internal.GetResourceList(base + "/agents")
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch agents of scope %s: %w", raw, err)
+		}
+		for _, obj := range agents {
+			sc.Agents = append(sc.Agents, agentFromAPI(obj))
+		}
+		messages, err := internal.GetResourceList(base + "/messages")
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch messages of scope %s: %w", raw, err)
+		}
+		for _, obj := range messages {
+			sc.Messages = append(sc.Messages, messageFromAPI(obj))
+		}
+		groups, err := internal.GetResourceList(base + "/groups")
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch groups of scope %s: %w", raw, err)
+		}
+		for _, obj := range groups {
+			sc.Groups = append(sc.Groups, groupFromAPI(obj))
+		}
+		manifest.Scopes = append(manifest.Scopes, sc)
+	}
+	manifest.normalize()
+	return manifest, nil
+}
+
+// trimBrackets strips the brackets the API keeps around scope names.
+func trimBrackets(scope string) string {
+	if len(scope) >= 2 && scope[0] == '[' && scope[len(scope)-1] == ']' {
+		return scope[1 : len(scope)-1]
+	}
+	return scope
+}
+
+// SPOEManifests returns every SPOE file as a manifest that apply accepts,
+// sorted by name.
+func SPOEManifests() ([]interface{}, error) {
+	names, err := fileNames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch SPOE files: %w", err)
+	}
+	sort.Strings(names)
+
+	manifests := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		manifest, err := getSPOEManifest(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch SPOE file %q: %w", name, err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spoe provides commands to manage SPOE (Stream Processing Offload
+// Engine) files: their scopes, agents, messages and groups.
+package spoe
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ApplySPOEFromYAML applies an SPOE manifest in a declarative way: the file
+// is created when missing, then its scopes, agents, messages and groups are
+// made to match the manifest in one transaction of the file. Objects the
+// manifest does not list are removed.
+func ApplySPOEFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest SPOEManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse SPOE manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("invalid SPOE configuration: %w", err)
+	}
+	manifest.normalize()
+
+	if outputFormat != "" || dryRun {
+		if outputFormat == "" {
+			outputFormat = internal.OutputFormatYAML
+		}
+		internal.FormatOutput(manifest, outputFormat)
+		if dryRun {
+			internal.PrintDryRun()
+		}
+		return nil
+	}
+
+	name := manifest.Name
+	current, err := getSPOEManifest(name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return fmt.Errorf("failed to check SPOE file existence: %w", err)
+	}
+	exists := err == nil
+
+	if exists && reflect.DeepEqual(*current, manifest) {
+		internal.PrintStatus(spoeKind, name, internal.ActionUnchanged)
+		return nil
+	}
+
+	if !exists {
+		if err := internal.UploadSPOEFileWithContext(context.Background(), name, nil); err != nil {
+			return fmt.Errorf("failed to create SPOE file %q: %w", name, err)
+		}
+		current = &SPOEManifest{Name: name}
+	}
+	err = withFileTransaction(name, func(tx *fileTransaction) error {
+		return syncScopes(tx, current.Scopes, manifest.Scopes)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply SPOE file %q: %w", name, err)
+	}
+
+	action := internal.ActionConfigured
+	if !exists {
+		action = internal.ActionCreated
+	}
+	internal.PrintStatus(spoeKind, name, action)
+	return nil
+}
+
+// scopeObjects returns the API payloads of a scope's objects by kind and
+// name.
+func scopeObjects(sc Scope) map[string]map[string]map[string]interface{} {
+	objects := map[string]map[string]map[string]interface{}{
+		"agents": {}, "messages": {}, "groups": {},
+	}
+	for _, a := range sc.Agents {
+		objects["agents"][a.Name] = a.toPayload()
+	}
+	for _, msg := range sc.Messages {
+		objects["messages"][msg.Name] = msg.toPayload()
+	}
+	for _, g := range sc.Groups {
+		objects["groups"][g.Name] = g.toPayload()
+	}
+	return objects
+}
+
+// syncScopes stages the changes that turn the current scopes into the
+// desired ones. Objects are removed before what they reference (agents,
+// then groups, then messages) and added in the opposite order.
+func syncScopes(tx *fileTransaction, current, desired []Scope) error {
+	currentByName := make(map[string]Scope, len(current))
+	for _, sc := range current {
+		currentByName[sc.Name] = sc
+	}
+	desiredByName := make(map[string]bool, len(desired))
+	for _, sc := range desired {
+		desiredByName[sc.Name] = true
+	}
+
+	for _, sc := range current {
+		if desiredByName[sc.Name] {
+			continue
+		}
+		if _, err := internal.SendRequest("DELETE", scopePath(tx.file, sc.Name), tx.params(), nil); err != nil {
+			return fmt.Errorf("failed to delete scope [%s]: %w", sc.Name, err)
+		}
+	}
+
+	for _, sc := range desired {
+		old, exists := currentByName[sc.Name]
+		if !exists {
+			if _, err := internal.SendRequest("POST", filePath(tx.file)+"/scopes", tx.params(), "["+sc.Name+"]"); err != nil {
+				return fmt.Errorf("failed to create scope [%s]: %w", sc.Name, err)
+			}
+		}
+		have, want := scopeObjects(old), scopeObjects(sc)
+		base := scopePath(tx.file, sc.Name)
+
+		for _, kind := range []string{"agents", "groups", "messages"} {
+			for _, name := range sortedNames(have[kind]) {
+				if _, keep := want[kind][name]; keep {
+					continue
+				}
+				if _, err := internal.SendRequest("DELETE", base+"/"+kind+"/"+name, tx.params(), nil); err != nil {
+					return fmt.Errorf("failed to delete %s %q in scope [%s]: %w", kind, name, sc.Name, err)
+				}
+			}
+		}
+		for _, kind := range []string{"messages", "groups", "agents"} {
+			for _, name := range sortedNames(want[kind]) {
+				payload := want[kind][name]
+				previous, found := have[kind][name]
+				switch {
+				case !found:
+					if _, err := internal.SendRequest("POST", base+"/"+kind, tx.params(), payload); err != nil {
+						return fmt.Errorf("failed to create %s %q in scope [%s]: %w", kind, name, sc.Name, err)
+					}
+				case !reflect.DeepEqual(previous, payload):
+					if _, err := internal.SendRequest("PUT", base+"/"+kind+"/"+name, tx.params(), payload); err != nil {
+						return fmt.Errorf("failed to update %s %q in scope [%s]: %w", kind, name, sc.Name, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sortedNames returns the keys of objects in order, so changes are staged
+// in the same order on every run.
+func sortedNames(objects map[string]map[string]interface{}) []string {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spoe provides commands to manage SPOE (Stream Processing Offload
+// Engine) files: their scopes, agents, messages and groups.
+package spoe
+
+import (
+	"context"
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// CreateSPOECmd represents "create spoe <file>".
+var CreateSPOECmd = &cobra.Command{
+	Use:     "spoe <file>",
+	Aliases: []string{"spoe-files"},
+	Short:   "Create an SPOE file",
+	Long: `Create an SPOE file, optionally with empty scopes. Agents, messages and
+groups are easiest to describe in an SPOE manifest and create with
+"haproxyctl apply -f". The file is used by a frontend or backend filter
+such as "filter spoe engine <scope> config <file>".
+
+Examples:
+  haproxyctl create spoe waf.conf --scope waf
+  haproxyctl create -f spoe.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifest := SPOEManifest{APIVersion: apiVersionV1, Kind: spoeKind, Name: args[0]}
+		for _, scope := range internal.GetFlagStringSlice(cmd, "scope") {
+			manifest.Scopes = append(manifest.Scopes, Scope{Name: scope})
+		}
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := createSPOE(manifest, dryRun); err != nil {
+			internal.Fatalf("Failed to create SPOE file %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateSPOECmd.Flags().StringArray("scope", nil, "Scope to create, without brackets (repeatable)")
+	CreateSPOECmd.Flags().Bool("dry-run", false, "Print the manifest without creating it")
+}
+
+// CreateSPOEFromFile is used for "haproxyctl create -f spoe.yaml".
+func CreateSPOEFromFile(data []byte) error {
+	var manifest SPOEManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse SPOE manifest: %w", err)
+	}
+	if err := createSPOE(manifest, internal.IsOffline()); err != nil {
+		return internal.FormatAPIError(spoeKind, manifest.Name, "create", err)
+	}
+	return nil
+}
+
+// createSPOE creates an SPOE file and fills it with the manifest's objects.
+func createSPOE(manifest SPOEManifest, dryRun bool) error {
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	manifest.normalize()
+
+	if dryRun {
+		internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	if err := internal.UploadSPOEFileWithContext(context.Background(), manifest.Name, nil); err != nil {
+		return err
+	}
+	if len(manifest.Scopes) > 0 {
+		err := withFileTransaction(manifest.Name, func(tx *fileTransaction) error {
+			return syncScopes(tx, nil, manifest.Scopes)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	internal.PrintStatus(spoeKind, manifest.Name, internal.ActionCreated)
+	return nil
+}
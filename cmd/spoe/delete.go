@@ -0,0 +1,159 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spoe provides commands to manage SPOE (Stream Processing Offload
+// Engine) files: their scopes, agents, messages and groups.
+package spoe
+
+import (
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteSPOECmd represents "delete spoe <file>".
+var DeleteSPOECmd = &cobra.Command{
+	Use:     "spoe <file>",
+	Aliases: []string{"spoe-files"},
+	Short:   "Delete an SPOE file or one of its scopes, agents, messages or groups",
+	Long: `Delete an SPOE file, or with --scope one scope of it. Together with
+--scope, --agent, --message or --group delete a single object of that
+scope instead. Filters that still reference the file must be removed
+first, or HAProxy rejects the configuration.
+
+Examples:
+  haproxyctl delete spoe waf.conf
+  haproxyctl delete spoe waf.conf --scope waf
+  haproxyctl delete spoe waf.conf --scope waf --message check-request`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		scope := internal.GetFlagString(cmd, "scope")
+		objects := map[string]string{
+			"agents":   internal.GetFlagString(cmd, "agent"),
+			"messages": internal.GetFlagString(cmd, "message"),
+			"groups":   internal.GetFlagString(cmd, "group"),
+		}
+		kind, name := "", ""
+		for k, v := range objects {
+			if v == "" {
+				continue
+			}
+			if name != "" {
+				internal.FatalCodef(internal.ExitUsage, "Only one of --agent, --message and --group can be given")
+			}
+			kind, name = k, v
+		}
+		if name != "" && scope == "" {
+			internal.FatalCodef(internal.ExitUsage, "--%s requires --scope", kind[:len(kind)-1])
+		}
+
+		var err error
+		switch {
+		case name != "":
+			err = DeleteSPOEObject(args[0], scope, kind, name)
+		case scope != "":
+			err = DeleteSPOEScope(args[0], scope)
+		default:
+			err = DeleteSPOEByName(args[0])
+		}
+		if err != nil {
+			internal.Fatalf("Failed to delete from SPOE file %q: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	DeleteSPOECmd.Flags().String("scope", "", "Scope to delete, or that holds the object to delete (without brackets)")
+	DeleteSPOECmd.Flags().String("agent", "", "Agent to delete from --scope")
+	DeleteSPOECmd.Flags().String("message", "", "Message to delete from --scope")
+	DeleteSPOECmd.Flags().String("group", "", "Group to delete from --scope")
+}
+
+// DeleteSPOEByName deletes an SPOE file.
+func DeleteSPOEByName(file string) error {
+	if _, err := internal.SendRequest("DELETE", filePath(file), nil, nil); err != nil {
+		return internal.FormatAPIError(spoeKind, file, "delete", err)
+	}
+
+	internal.PrintStatus(spoeKind, file, internal.ActionDeleted)
+	return nil
+}
+
+// DeleteSPOEScope deletes a scope, with everything in it, from an SPOE
+// file.
+func DeleteSPOEScope(file, scope string) error {
+	err := withFileTransaction(file, func(tx *fileTransaction) error {
+		_, err := internal.SendRequest("DELETE", scopePath(file, scope), tx.params(), nil)
+		return err
+	})
+	if err != nil {
+		return internal.FormatAPIError(spoeKind, file+"/["+scope+"]", "delete", err)
+	}
+
+	internal.PrintStatus(spoeKind, file+"/["+scope+"]", internal.ActionDeleted)
+	return nil
+}
+
+// DeleteSPOEObject deletes one agent, message or group (kind is the API's
+// plural) from a scope. Deleting a message or group an agent still uses is
+// refused, since HAProxy would reject the file.
+func DeleteSPOEObject(file, scope, kind, name string) error {
+	id := file + "/[" + scope + "]/" + name
+	manifest, err := getSPOEManifest(file)
+	if err != nil {
+		return internal.FormatAPIError(spoeKind, file, "get", err)
+	}
+	for _, sc := range manifest.Scopes {
+		if sc.Name != scope {
+			continue
+		}
+		if user := objectUser(sc, kind, name); user != "" {
+			return fmt.Errorf("%s %q is still used by %s", kind[:len(kind)-1], name, user)
+		}
+	}
+
+	err = withFileTransaction(file, func(tx *fileTransaction) error {
+		_, err := internal.SendRequest("DELETE", scopePath(file, scope)+"/"+kind+"/"+name, tx.params(), nil)
+		return err
+	})
+	if err != nil {
+		return internal.FormatAPIError(spoeKind, id, "delete", err)
+	}
+
+	internal.PrintStatus(spoeKind, id, internal.ActionDeleted)
+	return nil
+}
+
+// objectUser returns the agent or group that references a message or
+// group, or "" when nothing does.
+func objectUser(sc Scope, kind, name string) string {
+	if kind == "agents" {
+		return ""
+	}
+	for _, a := range sc.Agents {
+		if (kind == "messages" && contains(a.Messages, name)) || (kind == "groups" && contains(a.Groups, name)) {
+			return "agent " + a.Name
+		}
+	}
+	for _, g := range sc.Groups {
+		if kind == "messages" && contains(g.Messages, name) {
+			return "group " + g.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spoe provides commands to manage SPOE (Stream Processing Offload
+// Engine) files: their scopes, agents, messages and groups.
+package spoe
+
+import (
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetSPOECmd represents "get spoe [file]".
+var GetSPOECmd = &cobra.Command{
+	Use:     "spoe [file]",
+	Aliases: []string{"spoe-files"},
+	Short:   "List SPOE files or show the scopes, agents, messages and groups of one",
+	Long: `List the SPOE files managed by the Data Plane API, or show the objects of
+one file per scope. With -o yaml the output is an SPOE manifest that apply
+accepts.
+
+Examples:
+  haproxyctl get spoe
+  haproxyctl get spoe waf.conf
+  haproxyctl get spoe waf.conf -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFormat := internal.GetFlagString(cmd, "output")
+		structured := outputFormat == internal.OutputFormatYAML || outputFormat == "json"
+
+		if len(args) == 0 {
+			manifests, err := SPOEManifests()
+			if err != nil {
+				internal.Fatalf("Failed to fetch SPOE files: %v", err)
+			}
+			if structured {
+				internal.FormatOutputForCmd(cmd, manifests, outputFormat)
+				return
+			}
+			rows := make([]map[string]interface{}, 0, len(manifests))
+			for _, m := range manifests {
+				rows = append(rows, fileRow(m.(*SPOEManifest)))
+			}
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, spoeFileColumns), outputFormat)
+			return
+		}
+
+		manifest, err := getSPOEManifest(args[0])
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(spoeKind, args[0]))
+			}
+			internal.Fatalf("Failed to fetch SPOE file %q: %v", args[0], err)
+		}
+		if structured {
+			internal.FormatOutputForCmd(cmd, manifest, outputFormat)
+			return
+		}
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(objectRows(manifest), spoeObjectColumns), outputFormat)
+	},
+}
+
+// spoeFileColumns shows each SPOE file with how many objects it holds.
+var spoeFileColumns = internal.ColumnSet{
+	Kind:    spoeKind,
+	Default: []string{"name", "scopes", "agents", "messages", "groups"},
+}
+
+// spoeObjectColumns are the columns of one SPOE file's objects.
+var spoeObjectColumns = internal.ColumnSet{
+	Default: []string{"scope", "type", "name", "details"},
+}
+
+// fileRow summarises an SPOE file for the table view.
+func fileRow(m *SPOEManifest) map[string]interface{} {
+	var agents, messages, groups int
+	for _, sc := range m.Scopes {
+		agents += len(sc.Agents)
+		messages += len(sc.Messages)
+		groups += len(sc.Groups)
+	}
+	return map[string]interface{}{
+		"name": m.Name, "scopes": len(m.Scopes), "agents": agents, "messages": messages, "groups": groups,
+	}
+}
+
+// objectRows lists every object of an SPOE file with a short description:
+// the backend and messages of an agent, the event of a message and the
+// messages of a group.
+func objectRows(m *SPOEManifest) []map[string]interface{} {
+	var rows []map[string]interface{}
+	row := func(scope, kind, name, details string) {
+		rows = append(rows, map[string]interface{}{"scope": "[" + scope + "]", "type": kind, "name": name, "details": details})
+	}
+	for _, sc := range m.Scopes {
+		for _, a := range sc.Agents {
+			details := "backend " + a.UseBackend
+			if len(a.Messages) > 0 {
+				details += ", messages " + strings.Join(a.Messages, " ")
+			}
+			if len(a.Groups) > 0 {
+				details += ", groups " + strings.Join(a.Groups, " ")
+			}
+			row(sc.Name, "agent", a.Name, details)
+		}
+		for _, msg := range sc.Messages {
+			details := msg.Event
+			if msg.Cond != "" {
+				details += " " + msg.Cond + " " + msg.CondTest
+			}
+			row(sc.Name, "message", msg.Name, details)
+		}
+		for _, g := range sc.Groups {
+			row(sc.Name, "group", g.Name, strings.Join(g.Messages, " "))
+		}
+	}
+	return rows
+}
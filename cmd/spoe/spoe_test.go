@@ -0,0 +1,133 @@
+package spoe
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestApplySPOEFromYAML(t *testing.T) {
+	srv := testserver.New(t)
+	data, err := os.ReadFile("../../examples/spoe.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplySPOEFromYAML(data, "", false); err != nil {
+			t.Fatalf("create apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "spoe/waf.conf created") {
+		t.Fatalf("expected created status, got:\n%s", output)
+	}
+	if scopes := srv.SPOEScopes("waf.conf"); len(scopes) != 1 || scopes[0] != "[waf]" {
+		t.Fatalf("scopes = %v", scopes)
+	}
+	agents := srv.SPOEObjects("waf.conf", "waf", "agents")
+	if len(agents) != 1 || agents[0]["use-backend"] != "spoe-waf" || agents[0]["hello_timeout"] != float64(100) ||
+		agents[0]["continue-on-error"] != "enabled" || agents[0]["messages"] != "check-request" {
+		t.Fatalf("agent not sent in API form: %+v", agents)
+	}
+	messages := srv.SPOEObjects("waf.conf", "waf", "messages")
+	event, _ := messages[0]["event"].(map[string]interface{})
+	if len(messages) != 1 || event["name"] != "on-frontend-http-request" {
+		t.Fatalf("message not sent in API form: %+v", messages)
+	}
+	version := srv.SPOEVersion("waf.conf")
+
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplySPOEFromYAML(data, "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "spoe/waf.conf unchanged") || srv.SPOEVersion("waf.conf") != version {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	changed := strings.Replace(string(data), "timeout_processing: 15ms", "timeout_processing: 50ms", 1)
+	changed = strings.Replace(changed, "messages: [check-request]", "groups: [all]", 1)
+	changed += "    groups:\n      - name: all\n        messages: [check-request]\n"
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplySPOEFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "spoe/waf.conf configured") {
+		t.Fatalf("expected configured status, got:\n%s", output)
+	}
+	agents = srv.SPOEObjects("waf.conf", "waf", "agents")
+	if agents[0]["processing_timeout"] != float64(50) || agents[0]["groups"] != "all" || agents[0]["messages"] != nil {
+		t.Fatalf("agent not updated: %+v", agents)
+	}
+	if srv.SPOEVersion("waf.conf") != version+1 {
+		t.Fatalf("version = %d, want %d (single commit)", srv.SPOEVersion("waf.conf"), version+1)
+	}
+}
+
+func TestSPOEManifestValidate(t *testing.T) {
+	t.Parallel()
+
+	msg := Message{Name: "check", Event: "on-frontend-http-request"}
+	tests := []struct {
+		name     string
+		manifest SPOEManifest
+		wantErr  string
+	}{
+		{"valid", SPOEManifest{Name: "waf.conf", Scopes: []Scope{{Name: "waf", Messages: []Message{msg}, Agents: []Agent{{Name: "a", UseBackend: "b", Messages: []string{"check"}}}}}}, ""},
+		{"brackets", SPOEManifest{Name: "waf.conf", Scopes: []Scope{{Name: "[waf]"}}}, "without brackets"},
+		{"no backend", SPOEManifest{Name: "waf.conf", Scopes: []Scope{{Name: "waf", Agents: []Agent{{Name: "a"}}}}}, "use_backend"},
+		{"unknown message", SPOEManifest{Name: "waf.conf", Scopes: []Scope{{Name: "waf", Agents: []Agent{{Name: "a", UseBackend: "b", Messages: []string{"nope"}}}}}}, "unknown message"},
+		{"bad event", SPOEManifest{Name: "waf.conf", Scopes: []Scope{{Name: "waf", Messages: []Message{{Name: "m", Event: "on-nothing"}}}}}, "invalid event"},
+		{"bad timeout", SPOEManifest{Name: "waf.conf", Scopes: []Scope{{Name: "waf", Agents: []Agent{{Name: "a", UseBackend: "b", TimeoutIdle: "soon"}}}}}, "idle_timeout"},
+	}
+	for _, tt := range tests {
+		err := tt.manifest.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: error = %v, want mention of %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDeleteSPOEObject_RefusesUsedMessage(t *testing.T) {
+	srv := testserver.New(t)
+	data, err := os.ReadFile("../../examples/spoe.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplySPOEFromYAML(data, "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+
+	if err := DeleteSPOEObject("waf.conf", "waf", "messages", "check-request"); err == nil || !strings.Contains(err.Error(), "agent waf-agent") {
+		t.Fatalf("expected the message to be reported in use, got %v", err)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteSPOEObject("waf.conf", "waf", "agents", "waf-agent"); err != nil {
+			t.Fatalf("deleting the agent failed: %v", err)
+		}
+		if err := DeleteSPOEScope("waf.conf", "waf"); err != nil {
+			t.Fatalf("deleting the scope failed: %v", err)
+		}
+		if err := DeleteSPOEByName("waf.conf"); err != nil {
+			t.Fatalf("deleting the file failed: %v", err)
+		}
+	})
+	for _, want := range []string{"spoe/waf.conf/[waf]/waf-agent deleted", "spoe/waf.conf/[waf] deleted", "spoe/waf.conf deleted"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if scopes := srv.SPOEScopes("waf.conf"); scopes != nil {
+		t.Fatalf("file not deleted: %v", scopes)
+	}
+}
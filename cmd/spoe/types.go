@@ -0,0 +1,394 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spoe provides commands to manage SPOE (Stream Processing Offload
+// Engine) files: their scopes, agents, messages and groups.
+package spoe
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"haproxyctl/internal"
+)
+
+const (
+	apiVersionV1 = "haproxyctl/v1"
+	spoeKind     = "SPOE"
+
+	spoeFilesPath = "/services/haproxy/spoe/spoe_files"
+
+	enabledValue = "enabled"
+)
+
+// spoeEvents are the events a message can be sent on.
+var spoeEvents = []string{
+	"on-client-session", "on-server-session",
+	"on-frontend-tcp-request", "on-backend-tcp-request", "on-tcp-response",
+	"on-frontend-http-request", "on-backend-http-request", "on-http-response",
+}
+
+// SPOEManifest is the manifest view of an SPOE file, the file a "filter
+// spoe engine <scope> config <file>" line points at. Scopes are named
+// without the brackets haproxy writes around them.
+type SPOEManifest struct {
+	APIVersion string  `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string  `json:"kind" yaml:"kind"`
+	Name       string  `json:"name" yaml:"name"`
+	Scopes     []Scope `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// Scope is one "[<name>]" section of an SPOE file.
+type Scope struct {
+	Name     string    `json:"name" yaml:"name"`
+	Agents   []Agent   `json:"agents,omitempty" yaml:"agents,omitempty"`
+	Messages []Message `json:"messages,omitempty" yaml:"messages,omitempty"`
+	Groups   []Group   `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// Agent is a "spoe-agent <name>" section. Timeouts are written like the
+// backend timeouts (e.g. 100ms, 30s) and sent to the API in milliseconds.
+//
+//nolint:tagliatelle // manifest uses HAProxy's snake_case names like backendConfig
+type Agent struct {
+	Name              string   `json:"name" yaml:"name"`
+	UseBackend        string   `json:"use_backend" yaml:"use_backend"`
+	Messages          []string `json:"messages,omitempty" yaml:"messages,omitempty"`
+	Groups            []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	TimeoutHello      string   `json:"timeout_hello,omitempty" yaml:"timeout_hello,omitempty"`
+	TimeoutIdle       string   `json:"timeout_idle,omitempty" yaml:"timeout_idle,omitempty"`
+	TimeoutProcessing string   `json:"timeout_processing,omitempty" yaml:"timeout_processing,omitempty"`
+	MaxFrameSize      int      `json:"max_frame_size,omitempty" yaml:"max_frame_size,omitempty"`
+	MaxWaitingFrames  int      `json:"max_waiting_frames,omitempty" yaml:"max_waiting_frames,omitempty"`
+	VarPrefix         string   `json:"var_prefix,omitempty" yaml:"var_prefix,omitempty"`
+	EngineName        string   `json:"engine_name,omitempty" yaml:"engine_name,omitempty"`
+	RegisterVarNames  []string `json:"register_var_names,omitempty" yaml:"register_var_names,omitempty"`
+	SetOnError        string   `json:"set_on_error,omitempty" yaml:"set_on_error,omitempty"`
+	SetProcessTime    string   `json:"set_process_time,omitempty" yaml:"set_process_time,omitempty"`
+	SetTotalTime      string   `json:"set_total_time,omitempty" yaml:"set_total_time,omitempty"`
+	Async             bool     `json:"async,omitempty" yaml:"async,omitempty"`
+	Pipelining        bool     `json:"pipelining,omitempty" yaml:"pipelining,omitempty"`
+	SendFragPayload   bool     `json:"send_frag_payload,omitempty" yaml:"send_frag_payload,omitempty"`
+	ContinueOnError   bool     `json:"continue_on_error,omitempty" yaml:"continue_on_error,omitempty"`
+	ForceSetVar       bool     `json:"force_set_var,omitempty" yaml:"force_set_var,omitempty"`
+}
+
+// Message is a "spoe-message <name>": the arguments sent to the agent and
+// the event (with an optional condition) it is sent on. A message without
+// an event is only sent through a group.
+//
+//nolint:tagliatelle // manifest uses HAProxy's snake_case names like backendConfig
+type Message struct {
+	Name     string   `json:"name" yaml:"name"`
+	Args     []string `json:"args,omitempty" yaml:"args,omitempty"`
+	Event    string   `json:"event,omitempty" yaml:"event,omitempty"`
+	Cond     string   `json:"cond,omitempty" yaml:"cond,omitempty"`
+	CondTest string   `json:"cond_test,omitempty" yaml:"cond_test,omitempty"`
+}
+
+// Group is a "spoe-group <name>" of messages sent together with a
+// "send-spoe-group" action.
+type Group struct {
+	Name     string   `json:"name" yaml:"name"`
+	Messages []string `json:"messages" yaml:"messages"`
+}
+
+// Validate checks the manifest before anything is sent to the API.
+func (m *SPOEManifest) Validate() error {
+	if m.Name == "" {
+		return errors.New("SPOE file name is required")
+	}
+	if m.Kind != "" && m.Kind != spoeKind {
+		return fmt.Errorf("kind must be %q", spoeKind)
+	}
+	if m.APIVersion != "" && m.APIVersion != apiVersionV1 {
+		return fmt.Errorf("apiVersion must be %q", apiVersionV1)
+	}
+	seen := make(map[string]bool, len(m.Scopes))
+	for _, sc := range m.Scopes {
+		if sc.Name == "" || strings.ContainsAny(sc.Name, "[] ") {
+			return fmt.Errorf("invalid scope name %q (write it without brackets)", sc.Name)
+		}
+		if seen[sc.Name] {
+			return fmt.Errorf("duplicate scope %q", sc.Name)
+		}
+		seen[sc.Name] = true
+		if err := sc.validate(); err != nil {
+			return fmt.Errorf("scope %q: %w", sc.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the objects of a scope and that agents and groups only
+// use messages and groups of the same scope.
+func (sc Scope) validate() error {
+	messages := make(map[string]bool, len(sc.Messages))
+	for _, msg := range sc.Messages {
+		if msg.Name == "" || messages[msg.Name] {
+			return fmt.Errorf("messages need a unique name (got %q)", msg.Name)
+		}
+		messages[msg.Name] = true
+		if msg.Event != "" && !contains(spoeEvents, msg.Event) {
+			return fmt.Errorf("message %q: invalid event %q (allowed: %s)", msg.Name, msg.Event, strings.Join(spoeEvents, ", "))
+		}
+		if (msg.Cond == "") != (msg.CondTest == "") {
+			return fmt.Errorf("message %q: cond and cond_test go together", msg.Name)
+		}
+		if msg.Cond != "" && msg.Event == "" {
+			return fmt.Errorf("message %q: a condition needs an event", msg.Name)
+		}
+		if msg.Cond != "" && msg.Cond != "if" && msg.Cond != "unless" {
+			return fmt.Errorf("message %q: invalid cond %q (expected if or unless)", msg.Name, msg.Cond)
+		}
+	}
+	groups := make(map[string]bool, len(sc.Groups))
+	for _, g := range sc.Groups {
+		if g.Name == "" || groups[g.Name] {
+			return fmt.Errorf("groups need a unique name (got %q)", g.Name)
+		}
+		groups[g.Name] = true
+		if len(g.Messages) == 0 {
+			return fmt.Errorf("group %q has no messages", g.Name)
+		}
+		if err := knownNames("group "+g.Name, "message", g.Messages, messages); err != nil {
+			return err
+		}
+	}
+	agents := make(map[string]bool, len(sc.Agents))
+	for _, a := range sc.Agents {
+		if a.Name == "" || agents[a.Name] {
+			return fmt.Errorf("agents need a unique name (got %q)", a.Name)
+		}
+		agents[a.Name] = true
+		if a.UseBackend == "" {
+			return fmt.Errorf("agent %q: use_backend is required", a.Name)
+		}
+		if err := knownNames("agent "+a.Name, "message", a.Messages, messages); err != nil {
+			return err
+		}
+		if err := knownNames("agent "+a.Name, "group", a.Groups, groups); err != nil {
+			return err
+		}
+		if a.MaxFrameSize < 0 || a.MaxWaitingFrames < 0 {
+			return fmt.Errorf("agent %q: max_frame_size and max_waiting_frames must not be negative", a.Name)
+		}
+		for field, value := range a.durations() {
+			if _, err := internal.ParseDurationToMillis(*value); err != nil {
+				return fmt.Errorf("agent %q: invalid %s: %w", a.Name, field, err)
+			}
+		}
+	}
+	return nil
+}
+
+func knownNames(owner, what string, names []string, known map[string]bool) error {
+	for _, n := range names {
+		if !known[n] {
+			return fmt.Errorf("%s uses unknown %s %q", owner, what, n)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// durations returns the timeout fields by their API name.
+func (a *Agent) durations() map[string]*string {
+	return map[string]*string{
+		"hello_timeout":      &a.TimeoutHello,
+		"idle_timeout":       &a.TimeoutIdle,
+		"processing_timeout": &a.TimeoutProcessing,
+	}
+}
+
+// toPayload converts the agent into the spoe agents endpoint's object. It
+// must be called on a validated agent.
+func (a Agent) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"name": a.Name, "use-backend": a.UseBackend}
+	for key, value := range map[string][]string{"messages": a.Messages, "groups": a.Groups, "register-var-names": a.RegisterVarNames} {
+		if len(value) > 0 {
+			payload[key] = strings.Join(value, " ")
+		}
+	}
+	for field, value := range a.durations() {
+		if ms, _ := internal.ParseDurationToMillis(*value); ms > 0 {
+			payload[field] = ms
+		}
+	}
+	for key, value := range map[string]int{"max-frame-size": a.MaxFrameSize, "max-waiting-frames": a.MaxWaitingFrames} {
+		if value > 0 {
+			payload[key] = value
+		}
+	}
+	for key, value := range map[string]string{
+		"option_var-prefix":       a.VarPrefix,
+		"engine-name":             a.EngineName,
+		"option_set-on-error":     a.SetOnError,
+		"option_set-process-time": a.SetProcessTime,
+		"option_set-total-time":   a.SetTotalTime,
+	} {
+		if value != "" {
+			payload[key] = value
+		}
+	}
+	for key, value := range map[string]bool{
+		"async":             a.Async,
+		"pipelining":        a.Pipelining,
+		"send-frag-payload": a.SendFragPayload,
+		"continue-on-error": a.ContinueOnError,
+		"force-set-var":     a.ForceSetVar,
+	} {
+		if value {
+			payload[key] = enabledValue
+		}
+	}
+	return payload
+}
+
+// agentFromAPI converts an API agent object.
+func agentFromAPI(obj map[string]interface{}) Agent {
+	str := func(key string) string { s, _ := obj[key].(string); return s }
+	a := Agent{
+		Name:             str("name"),
+		UseBackend:       str("use-backend"),
+		Messages:         splitList(str("messages")),
+		Groups:           splitList(str("groups")),
+		RegisterVarNames: splitList(str("register-var-names")),
+		VarPrefix:        str("option_var-prefix"),
+		EngineName:       str("engine-name"),
+		SetOnError:       str("option_set-on-error"),
+		SetProcessTime:   str("option_set-process-time"),
+		SetTotalTime:     str("option_set-total-time"),
+		Async:            str("async") == enabledValue,
+		Pipelining:       str("pipelining") == enabledValue,
+		SendFragPayload:  str("send-frag-payload") == enabledValue,
+		ContinueOnError:  str("continue-on-error") == enabledValue,
+		ForceSetVar:      str("force-set-var") == enabledValue,
+	}
+	a.MaxFrameSize = intField(obj, "max-frame-size")
+	a.MaxWaitingFrames = intField(obj, "max-waiting-frames")
+	for field, value := range a.durations() {
+		if ms := intField(obj, field); ms > 0 {
+			*value = internal.FormatMillisAsDuration(ms)
+		}
+	}
+	return a
+}
+
+func (msg Message) toPayload() map[string]interface{} {
+	payload := map[string]interface{}{"name": msg.Name}
+	if len(msg.Args) > 0 {
+		payload["args"] = strings.Join(msg.Args, " ")
+	}
+	if msg.Event != "" {
+		event := map[string]interface{}{"name": msg.Event}
+		if msg.Cond != "" {
+			event["cond"] = msg.Cond
+			event["cond_test"] = msg.CondTest
+		}
+		payload["event"] = event
+	}
+	return payload
+}
+
+// messageFromAPI converts an API message object.
+func messageFromAPI(obj map[string]interface{}) Message {
+	msg := Message{}
+	msg.Name, _ = obj["name"].(string)
+	if args, ok := obj["args"].(string); ok {
+		msg.Args = splitList(args)
+	}
+	if event, ok := obj["event"].(map[string]interface{}); ok {
+		msg.Event, _ = event["name"].(string)
+		msg.Cond, _ = event["cond"].(string)
+		msg.CondTest, _ = event["cond_test"].(string)
+	}
+	return msg
+}
+
+func (g Group) toPayload() map[string]interface{} {
+	return map[string]interface{}{"name": g.Name, "messages": strings.Join(g.Messages, " ")}
+}
+
+// groupFromAPI converts an API group object.
+func groupFromAPI(obj map[string]interface{}) Group {
+	g := Group{}
+	g.Name, _ = obj["name"].(string)
+	if messages, ok := obj["messages"].(string); ok {
+		g.Messages = splitList(messages)
+	}
+	return g
+}
+
+// splitList splits a space-separated API value, returning nil for an
+// empty one so it compares equal to an omitted manifest list.
+func splitList(s string) []string {
+	if fields := strings.Fields(s); len(fields) > 0 {
+		return fields
+	}
+	return nil
+}
+
+// intField extracts an integer from a JSON-decoded object.
+func intField(obj map[string]interface{}, key string) int {
+	if v, ok := obj[key].(float64); ok {
+		return int(v)
+	}
+	return 0
+}
+
+// normalize fills in kind and apiVersion, renders durations the way they
+// come back from the API and sorts every list by name, so manifests from
+// files and from the API compare equal.
+func (m *SPOEManifest) normalize() {
+	m.APIVersion, m.Kind = apiVersionV1, spoeKind
+	sort.Slice(m.Scopes, func(i, j int) bool { return m.Scopes[i].Name < m.Scopes[j].Name })
+	for i := range m.Scopes {
+		sc := &m.Scopes[i]
+		for j := range sc.Agents {
+			for _, value := range sc.Agents[j].durations() {
+				if ms, err := internal.ParseDurationToMillis(*value); err == nil && ms > 0 {
+					*value = internal.FormatMillisAsDuration(ms)
+				}
+			}
+		}
+		sort.Slice(sc.Agents, func(a, b int) bool { return sc.Agents[a].Name < sc.Agents[b].Name })
+		sort.Slice(sc.Messages, func(a, b int) bool { return sc.Messages[a].Name < sc.Messages[b].Name })
+		sort.Slice(sc.Groups, func(a, b int) bool { return sc.Groups[a].Name < sc.Groups[b].Name })
+	}
+}
+
+// filePath returns the endpoint of an SPOE file.
+func filePath(file string) string {
+	return spoeFilesPath + "/" + url.PathEscape(file)
+}
+
+// scopePath returns the endpoint of a scope; scope is given without
+// brackets.
+func scopePath(file, scope string) string {
+	return filePath(file) + "/scopes/" + url.PathEscape("["+scope+"]")
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package stats
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -39,7 +40,14 @@ contains many fields and is best consumed programmatically.
 Examples:
   haproxyctl get stats -o json
   haproxyctl get stats --type backend --name s3_backend -o json
-  haproxyctl get stats --type server --parent s3_backend --name archive`,
+  haproxyctl get stats --type server --parent s3_backend --name archive
+
+Recording:
+  --record appends one timestamped snapshot per --interval to a file as
+  NDJSON (one JSON object per line), for --duration or until interrupted.
+
+  haproxyctl get stats --record out.ndjson --interval 10s --duration 1h
+  haproxyctl get stats --type backend --name app --record app.ndjson`,
 	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, _ []string) {
 		getStats(cmd)
@@ -57,17 +65,27 @@ func getStats(cmd *cobra.Command) {
 	name := internal.GetFlagString(cmd, "name")
 	parent := internal.GetFlagString(cmd, "parent")
 
-	data, err := getNativeStatsFromAPI(cmd, objType, name, parent)
+	if path := internal.GetFlagString(cmd, "record"); path != "" {
+		fetch := func(ctx context.Context) (map[string]interface{}, error) {
+			return getNativeStatsFromAPI(ctx, objType, name, parent)
+		}
+		if err := recordStatsToFile(cmd, path, fetch); err != nil {
+			internal.Fatalf("Failed to record HAProxy stats: %v", err)
+		}
+		return
+	}
+
+	data, err := getNativeStatsFromAPI(cmd.Context(), objType, name, parent)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Failed to fetch HAProxy stats: %v\n", err)
 		return
 	}
 
-	internal.FormatOutput(data, outputFormat)
+	internal.FormatOutputForCmd(cmd, data, outputFormat)
 }
 
 // getNativeStatsFromAPI calls the Data Plane API /stats/native endpoint.
-func getNativeStatsFromAPI(cmd *cobra.Command, objType, name, parent string) (map[string]interface{}, error) {
+func getNativeStatsFromAPI(ctx context.Context, objType, name, parent string) (map[string]interface{}, error) {
 	query := map[string]string{}
 	if objType != "" {
 		query["type"] = objType
@@ -79,7 +97,7 @@ func getNativeStatsFromAPI(cmd *cobra.Command, objType, name, parent string) (ma
 		query["parent"] = parent
 	}
 
-	raw, err := internal.SendRequestWithContext(cmd.Context(), "GET", "/services/haproxy/stats/native", query, nil)
+	raw, err := internal.SendRequestWithContext(ctx, "GET", "/services/haproxy/stats/native", query, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch native stats: %w", err)
 	}
@@ -96,4 +114,7 @@ func init() {
 	GetStatsCmd.Flags().String("type", "", "Object type to get stats for: frontend, backend, or server")
 	GetStatsCmd.Flags().String("name", "", "Object name to get stats for")
 	GetStatsCmd.Flags().String("parent", "", "Parent name (for server stats, the backend name)")
+	GetStatsCmd.Flags().String("record", "", "Append timestamped snapshots to this file (NDJSON) instead of printing once")
+	GetStatsCmd.Flags().Duration("interval", defaultRecordInterval, "Time between snapshots with --record")
+	GetStatsCmd.Flags().Duration("duration", 0, "How long to record with --record (0 = until interrupted)")
 }
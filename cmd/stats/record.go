@@ -0,0 +1,113 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stats provides commands to inspect HAProxy runtime statistics.
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultRecordInterval = 10 * time.Second
+
+// statsSnapshot is one line of a --record file.
+type statsSnapshot struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Stats     map[string]interface{} `json:"stats"`
+}
+
+// statsFetcher returns one stats payload.
+type statsFetcher func(ctx context.Context) (map[string]interface{}, error)
+
+// recordStatsToFile appends snapshots to path until duration elapses (or
+// forever when duration is 0) or the command is interrupted.
+func recordStatsToFile(cmd *cobra.Command, path string, fetch statsFetcher) error {
+	interval, err := cmd.Flags().GetDuration("interval")
+	if err != nil {
+		return fmt.Errorf("failed to read flag interval: %w", err)
+	}
+	duration, err := cmd.Flags().GetDuration("duration")
+	if err != nil {
+		return fmt.Errorf("failed to read flag duration: %w", err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive, got %s", interval)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec // path is chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil {
+			internal.Warnf("failed to close %s: %v", path, cerr)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	if duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "recording stats to %s every %s (Ctrl-C to stop)\n", path, interval)
+	n, err := recordStats(ctx, f, fetch, interval)
+	_, _ = fmt.Fprintf(os.Stderr, "recorded %d snapshot(s) to %s\n", n, path)
+	return err
+}
+
+// recordStats writes one NDJSON snapshot per interval, starting
+// immediately, until ctx is done. A failed fetch is reported and skipped
+// so that a flapping API does not end the recording.
+func recordStats(ctx context.Context, w io.Writer, fetch statsFetcher, interval time.Duration) (int, error) {
+	enc := json.NewEncoder(w)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	written := 0
+	for {
+		data, err := fetch(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return written, nil
+		case err != nil:
+			internal.Warnf("skipping snapshot: %v", err)
+		default:
+			if err := enc.Encode(statsSnapshot{Timestamp: time.Now().UTC(), Stats: data}); err != nil {
+				return written, fmt.Errorf("failed to write snapshot: %w", err)
+			}
+			written++
+		}
+
+		select {
+		case <-ctx.Done():
+			return written, nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecordStats_WritesNDJSONAndSkipsFailures(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	fetch := func(context.Context) (map[string]interface{}, error) {
+		calls++
+		switch calls {
+		case 2:
+			return nil, errors.New("temporary failure")
+		case 4:
+			cancel()
+		}
+		return map[string]interface{}{"call": calls}, nil
+	}
+
+	var buf bytes.Buffer
+	n, err := recordStats(ctx, &buf, fetch, time.Millisecond)
+	if err != nil {
+		t.Fatalf("recordStats failed: %v", err)
+	}
+	// Calls 1 and 3 are written; 2 failed and 4 raced with cancellation.
+	if n != 2 {
+		t.Fatalf("recorded %d snapshots, want 2\n%s", n, buf.String())
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	var got []float64
+	for scanner.Scan() {
+		var snap statsSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			t.Fatalf("line is not a JSON snapshot: %q: %v", scanner.Text(), err)
+		}
+		if snap.Timestamp.IsZero() {
+			t.Fatalf("snapshot without timestamp: %q", scanner.Text())
+		}
+		got = append(got, snap.Stats["call"].(float64))
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("unexpected snapshots: %v", got)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sticktables provides commands to inspect and clear HAProxy
+// runtime stick tables.
+package sticktables
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// entryMetaFields are entry attributes that are not stored data types.
+var entryMetaFields = map[string]bool{"id": true, "key": true, "use": true, "exp": true, "server_id": true}
+
+// DeleteStickTableEntryCmd represents "delete stick-table-entry <table> <key>".
+var DeleteStickTableEntryCmd = &cobra.Command{
+	Use:     "stick-table-entry <table_name> <key>",
+	Aliases: []string{"stick-table-entries"},
+	Short:   "Clear the counters of a stick table entry",
+	Long: `Reset every counter and rate stored for a key to zero, for example to
+lift a rate limit on a client that was blocked by mistake.
+
+The Data Plane API has no endpoint that removes a stick table entry, so
+the entry's data is zeroed with "set table" instead; the entry itself
+expires on its own once it is no longer tracked.
+
+Examples:
+  haproxyctl delete stick-table-entry st_web_ratelimit 203.0.113.7
+  haproxyctl delete stick-table-entry st_web_ratelimit 203.0.113.7 --dry-run`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ClearStickTableEntry(args[0], args[1], internal.GetFlagBool(cmd, "dry-run")); err != nil {
+			internal.Fatalf("Failed to clear entry '%s' of stick table '%s': %v", args[1], args[0], err)
+		}
+	},
+}
+
+func init() {
+	DeleteStickTableEntryCmd.Flags().Bool("dry-run", false, "Show the counters that would be reset without changing them")
+}
+
+// ClearStickTableEntry zeroes every stored data type of one entry.
+func ClearStickTableEntry(table, key string, dryRun bool) error {
+	entries, err := stickTableEntries(table, key, "")
+	if err != nil {
+		return internal.FormatAPIError("StickTable", table, "get", err)
+	}
+	var entry map[string]interface{}
+	for _, e := range entries {
+		if e["key"] == key {
+			entry = e
+			break
+		}
+	}
+	if entry == nil {
+		return fmt.Errorf("stick table %q has no entry %q", table, key)
+	}
+
+	data := make(map[string]interface{})
+	var names []string
+	for field, value := range entry {
+		if entryMetaFields[field] {
+			continue
+		}
+		if _, ok := value.(float64); !ok {
+			continue
+		}
+		data[field] = 0
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	id := internal.ResourceID("StickTable", table)
+	if len(names) == 0 {
+		internal.PrintStatus("StickTable", table+" "+key, internal.ActionUnchanged)
+		return nil
+	}
+	if dryRun {
+		_, _ = fmt.Printf("%s entry %s would reset %s\n", id, key, strings.Join(names, ", "))
+		internal.PrintDryRun()
+		return nil
+	}
+
+	body := map[string]interface{}{"key": key, "data_type": data}
+	if _, err := internal.SendRequest("PUT", entriesEndpoint(table), nil, body); err != nil {
+		return fmt.Errorf("failed to reset entry %q: %w", key, err)
+	}
+	internal.PrintStatus("StickTable", table+" "+key, "cleared")
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sticktables provides commands to inspect and clear HAProxy
+// runtime stick tables.
+package sticktables
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+const stickTablesEndpoint = "/services/haproxy/runtime/stick_tables"
+
+// filterOperators are the comparison operators HAProxy accepts in
+// "show table ... data.<type> <op> <value>".
+var filterOperators = []string{"eq", "ne", "lt", "le", "gt", "ge"}
+
+// GetStickTablesCmd represents "get stick-tables [name]".
+var GetStickTablesCmd = &cobra.Command{
+	Use:     "stick-tables [table_name]",
+	Aliases: []string{"stick-table", "sticktables", "sticktable"},
+	Short:   "List runtime stick tables or show the entries of one",
+	Long: `Without a name, list the stick tables HAProxy has loaded with their type,
+size and usage. With a name, show the table's entries; --filter keeps only
+entries whose stored data matches "<data_type> <op> <value>" (op is one of
+eq, ne, lt, le, gt, ge) and may be repeated.
+
+Examples:
+  haproxyctl get stick-tables
+  haproxyctl get stick-table st_web_ratelimit
+  haproxyctl get stick-table st_web_ratelimit --filter "http_req_rate gt 100"
+  haproxyctl get stick-table st_web_ratelimit --key 203.0.113.7 -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFormat := internal.GetFlagString(cmd, "output")
+		if len(args) == 0 {
+			list, err := internal.GetResourceList(stickTablesEndpoint)
+			if err != nil {
+				internal.Fatalf("Failed to fetch stick tables: %v", err)
+			}
+			internal.SortByStringField(list, "name")
+			internal.SortForCmd(cmd, list)
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(list, stickTableColumns), outputFormat)
+			return
+		}
+
+		filter, err := buildFilter(internal.GetFlagStringSlice(cmd, "filter"))
+		if err != nil {
+			internal.FatalCodef(internal.ExitUsage, "Invalid --filter: %v", err)
+		}
+		entries, err := stickTableEntries(args[0], internal.GetFlagString(cmd, "key"), filter)
+		if err != nil {
+			if internal.IsNotFoundError(err) {
+				internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("StickTable", args[0]))
+			}
+			internal.Fatalf("Failed to fetch entries of stick table '%s': %v", args[0], err)
+		}
+		internal.SortForCmd(cmd, entries)
+		internal.FormatOutputForCmd(cmd, entries, outputFormat)
+	},
+}
+
+// stickTableColumns shows tables the way "show table" summarises them.
+var stickTableColumns = internal.ColumnSet{
+	Kind:    "StickTable",
+	Default: []string{"name", "type", "size", "used"},
+}
+
+func init() {
+	GetStickTablesCmd.Flags().StringArray("filter", nil, "Only show entries matching \"<data_type> <op> <value>\" (repeatable)")
+	GetStickTablesCmd.Flags().String("key", "", "Only show the entry with this key")
+}
+
+// buildFilter validates --filter values and joins them into the API's
+// comma separated "data.<type> <op> <value>" format.
+func buildFilter(filters []string) (string, error) {
+	parts := make([]string, 0, len(filters))
+	for _, f := range filters {
+		fields := strings.Fields(f)
+		if len(fields) != 3 {
+			return "", fmt.Errorf("%q: expected \"<data_type> <op> <value>\"", f)
+		}
+		dataType, op, value := strings.TrimPrefix(fields[0], "data."), fields[1], fields[2]
+		if !slices.Contains(filterOperators, op) {
+			return "", fmt.Errorf("%q: unknown operator %q (want one of %s)", f, op, strings.Join(filterOperators, ", "))
+		}
+		parts = append(parts, fmt.Sprintf("data.%s %s %s", dataType, op, value))
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// entriesEndpoint returns the runtime entries endpoint of a stick table.
+func entriesEndpoint(table string) string {
+	return stickTablesEndpoint + "/" + url.PathEscape(table) + "/entries"
+}
+
+// stickTableEntries fetches the entries of a table, optionally narrowed to
+// one key and/or an API filter expression.
+func stickTableEntries(table, key, filter string) ([]map[string]interface{}, error) {
+	params := map[string]string{}
+	if key != "" {
+		params["key"] = key
+	}
+	if filter != "" {
+		params["filter"] = filter
+	}
+	data, err := internal.SendRequest("GET", entriesEndpoint(table), params, nil)
+	if err != nil {
+		return nil, err
+	}
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse stick table entries: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,79 @@
+package sticktables
+
+import (
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestBuildFilter(t *testing.T) {
+	t.Parallel()
+
+	got, err := buildFilter([]string{"http_req_rate gt 100", "data.gpc0 eq 1"})
+	if err != nil {
+		t.Fatalf("buildFilter failed: %v", err)
+	}
+	if want := "data.http_req_rate gt 100,data.gpc0 eq 1"; got != want {
+		t.Fatalf("buildFilter = %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{"http_req_rate > 100", "http_req_rate gt", "gpc0"} {
+		if _, err := buildFilter([]string{bad}); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestStickTableEntries_Filter(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddStickTableEntry("st_web", "192.0.2.1", map[string]int{"http_req_rate": 5})
+	srv.AddStickTableEntry("st_web", "192.0.2.2", map[string]int{"http_req_rate": 250})
+
+	filter, err := buildFilter([]string{"http_req_rate gt 100"})
+	if err != nil {
+		t.Fatalf("buildFilter failed: %v", err)
+	}
+	entries, err := stickTableEntries("st_web", "", filter)
+	if err != nil {
+		t.Fatalf("stickTableEntries failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0]["key"] != "192.0.2.2" {
+		t.Fatalf("unexpected filtered entries: %+v", entries)
+	}
+
+	if _, err := stickTableEntries("missing", "", ""); !internal.IsNotFoundError(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestClearStickTableEntry(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddStickTableEntry("st_web", "192.0.2.2", map[string]int{"http_req_rate": 250, "gpc0": 3})
+	srv.AddStickTableEntry("st_web", "192.0.2.3", map[string]int{"http_req_rate": 40})
+
+	internal.CaptureStdout(t, func() {
+		if err := ClearStickTableEntry("st_web", "192.0.2.2", true); err != nil {
+			t.Fatalf("ClearStickTableEntry (dry-run) failed: %v", err)
+		}
+	})
+	if data, _ := srv.StickTableEntry("st_web", "192.0.2.2"); data["http_req_rate"] != 250 {
+		t.Fatalf("dry-run changed the entry: %+v", data)
+	}
+
+	internal.CaptureStdout(t, func() {
+		if err := ClearStickTableEntry("st_web", "192.0.2.2", false); err != nil {
+			t.Fatalf("ClearStickTableEntry failed: %v", err)
+		}
+	})
+	if data, _ := srv.StickTableEntry("st_web", "192.0.2.2"); data["http_req_rate"] != 0 || data["gpc0"] != 0 {
+		t.Fatalf("entry not cleared: %+v", data)
+	}
+	if data, _ := srv.StickTableEntry("st_web", "192.0.2.3"); data["http_req_rate"] != 40 {
+		t.Fatalf("other entry changed: %+v", data)
+	}
+
+	if err := ClearStickTableEntry("st_web", "198.51.100.9", false); err == nil {
+		t.Fatalf("expected error for a missing key")
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage provides commands to manage the files kept in the Data
+// Plane API general storage, such as custom error pages.
+package storage
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// CreateStorageFilesCmd represents "create storage-files".
+var CreateStorageFilesCmd = &cobra.Command{
+	Use:     "storage-files [name] --from-file <path>",
+	Aliases: []string{"storage-file"},
+	Short:   "Upload a file to the general storage",
+	Long: `Upload a local file, such as a custom error page, to the Data Plane API
+general storage. The entry is named after the file unless a name is given.
+An entry of the same name is replaced when its contents differ, so running
+the command again is safe. The path HAProxy reads the file from is printed
+by "haproxyctl get storage-files".
+
+Examples:
+  haproxyctl create storage-files --from-file ./errors/503.http
+  haproxyctl create storage-files maintenance.http --from-file ./errors/503.http
+  render-page | haproxyctl create storage-files 503.http --from-file -`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := internal.GetFlagString(cmd, "from-file")
+		if source == "" {
+			internal.FatalCodef(internal.ExitUsage, "--from-file is required")
+		}
+		var name string
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if source == "-" && name == "" {
+			internal.FatalCodef(internal.ExitUsage, "A name is required when reading from stdin")
+		}
+		data, err := readMaybeStdin(source)
+		if err != nil {
+			internal.Fatalf("Failed to read %s: %v", source, err)
+		}
+
+		if internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline() {
+			internal.FormatOutput(map[string]interface{}{"source": source, "size": len(data)}, internal.OutputFormatYAML)
+			internal.PrintDryRun()
+			return
+		}
+		if _, err := UploadFile(cmd.Context(), name, source, data); err != nil {
+			internal.Fatalf("Failed to upload %s: %v", source, err)
+		}
+	},
+}
+
+func init() {
+	CreateStorageFilesCmd.Flags().String("from-file", "", "Local file to upload, or - for stdin (required)")
+	CreateStorageFilesCmd.Flags().Bool("dry-run", false, "Show what would be uploaded without sending it")
+}
@@ -0,0 +1,47 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage provides commands to manage the files kept in the Data
+// Plane API general storage, such as custom error pages.
+package storage
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteStorageFilesCmd represents "delete storage-files <name>".
+var DeleteStorageFilesCmd = &cobra.Command{
+	Use:     "storage-files <name>",
+	Aliases: []string{"storage-file"},
+	Short:   "Delete a file from the general storage",
+	Long: `Delete a file from the general storage. Errorfile lines that still point
+at it must be removed first, or HAProxy fails to load the configuration.
+
+Examples:
+  haproxyctl delete storage-files 503.http`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDeleteFile,
+}
+
+// runDeleteFile deletes the general storage entry named in args.
+func runDeleteFile(cmd *cobra.Command, args []string) {
+	if _, err := internal.SendRequestWithContext(cmd.Context(), "DELETE", generalStoragePath+"/"+args[0], nil, nil); err != nil {
+		internal.Fatalf("Failed to delete storage file %q: %v", args[0], internal.FormatAPIError(storageFileKind, args[0], "delete", err))
+	}
+	internal.PrintStatus(storageFileKind, args[0], internal.ActionDeleted)
+}
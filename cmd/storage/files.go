@@ -0,0 +1,160 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage provides commands to manage the files kept in the Data
+// Plane API general storage, such as custom error pages.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// ListFilesCmd represents "files list [name]".
+var ListFilesCmd = &cobra.Command{
+	Use:     "list [name]",
+	Aliases: []string{"ls"},
+	Short:   "List files in the general storage or show one",
+	Long: `List the files in the Data Plane API general storage, such as map files,
+Lua scripts and error pages, with the path HAProxy reads each one from.
+
+Examples:
+  haproxyctl files list
+  haproxyctl files list hosts.map -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runGetFiles,
+}
+
+// UploadFilesCmd represents "files upload [name] -f <path|->".
+var UploadFilesCmd = &cobra.Command{
+	Use:   "upload [name] -f <path|->",
+	Short: "Upload a map file, Lua script or other file to the general storage",
+	Long: `Upload a file to the Data Plane API general storage. The entry is named
+after the file unless a name is given; reading from stdin with -f - needs a
+name. An entry of the same name is replaced when its contents differ, so
+running the command again is safe. Reference the path "haproxyctl files
+list" prints from the configuration, e.g. in
+map_beg(/etc/haproxy/general/hosts.map) or a lua-load line.
+
+Examples:
+  haproxyctl files upload -f ./maps/hosts.map
+  haproxyctl files upload auth.lua -f ./lua/auth.lua
+  render-map | haproxyctl files upload hosts.map -f -`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := internal.GetFlagString(cmd, "filename")
+		if source == "" {
+			internal.FatalCodef(internal.ExitUsage, "-f is required (a path, or - for stdin)")
+		}
+		var name string
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if source == "-" && name == "" {
+			internal.FatalCodef(internal.ExitUsage, "A name is required when reading from stdin")
+		}
+		data, err := readMaybeStdin(source)
+		if err != nil {
+			internal.Fatalf("Failed to read %s: %v", source, err)
+		}
+
+		if internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline() {
+			internal.FormatOutput(map[string]interface{}{"source": source, "size": len(data)}, internal.OutputFormatYAML)
+			internal.PrintDryRun()
+			return
+		}
+		if _, err := UploadFile(cmd.Context(), name, source, data); err != nil {
+			internal.Fatalf("Failed to upload %s: %v", source, err)
+		}
+	},
+}
+
+// DownloadFilesCmd represents "files download <name>".
+var DownloadFilesCmd = &cobra.Command{
+	Use:   "download <name>",
+	Short: "Download a file from the general storage",
+	Long: `Print the contents of a general storage file, or write them to a local
+file with --to.
+
+Examples:
+  haproxyctl files download hosts.map > hosts.map
+  haproxyctl files download auth.lua --to ./lua/auth.lua`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := DownloadFile(cmd.Context(), args[0])
+		if err != nil {
+			internal.Fatalf("Failed to download storage file %q: %v", args[0], err)
+		}
+		if to := internal.GetFlagString(cmd, "to"); to != "" {
+			if err := os.WriteFile(to, data, 0o600); err != nil {
+				internal.Fatalf("Failed to write %s: %v", to, err)
+			}
+			return
+		}
+		_, _ = os.Stdout.Write(data)
+	},
+}
+
+// DeleteFilesCmd represents "files delete <name>".
+var DeleteFilesCmd = &cobra.Command{
+	Use:     "delete <name>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a file from the general storage",
+	Long: `Delete a file from the general storage. Map lookups, lua-load lines and
+errorfile lines that still use it must be removed first, or HAProxy fails to
+load the configuration.
+
+Examples:
+  haproxyctl files delete hosts.map`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDeleteFile,
+}
+
+func init() {
+	ListFilesCmd.Flags().StringP("output", "o", "", "Output format: wide, name, yaml or json (default: table)")
+
+	UploadFilesCmd.Flags().StringP("filename", "f", "", "Local file to upload, or - for stdin (required)")
+	UploadFilesCmd.Flags().Bool("dry-run", false, "Show what would be uploaded without sending it")
+
+	DownloadFilesCmd.Flags().String("to", "", "Write the contents to this local file instead of stdout")
+}
+
+// DownloadFile returns the contents of a general storage entry.
+func DownloadFile(ctx context.Context, name string) ([]byte, error) {
+	data, err := internal.SendRequestWithContext(ctx, "GET", generalStoragePath+"/"+name, nil, nil)
+	if err != nil {
+		return nil, internal.FormatAPIError(storageFileKind, name, "get", err)
+	}
+	return data, nil
+}
+
+// readMaybeStdin reads from a file path or stdin when path is "-".
+func readMaybeStdin(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err == nil && len(data) == 0 {
+			err = errors.New("no data on stdin")
+		}
+		return data, err
+	}
+	return os.ReadFile(path) //nolint:gosec // path comes from explicit CLI input
+}
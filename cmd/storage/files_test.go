@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func TestUploadAndDownloadFile(t *testing.T) {
+	srv := testserver.New(t)
+	ctx := context.Background()
+	script := []byte("core.register_service('hello', 'http', function(applet) end)\n")
+
+	var path string
+	output := internal.CaptureStdout(t, func() {
+		var err error
+		if path, err = UploadFile(ctx, "", "./lua/hello.lua", script); err != nil {
+			t.Fatalf("UploadFile failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "storagefile/hello.lua created") || path != "/etc/haproxy/general/hello.lua" {
+		t.Fatalf("unexpected output %q, path %q", output, path)
+	}
+
+	data, err := DownloadFile(ctx, "hello.lua")
+	if err != nil || string(data) != string(script) {
+		t.Fatalf("DownloadFile = %q, %v", data, err)
+	}
+	if _, err := DownloadFile(ctx, "missing.map"); err == nil {
+		t.Fatal("expected error for a missing file")
+	}
+
+	srv.AddStorageFile("hosts.map", []byte("example.com app\n"))
+	list, err := Files(ctx)
+	if err != nil || len(list) != 2 || list[0]["storage_name"] != "hello.lua" {
+		t.Fatalf("Files = %+v, %v", list, err)
+	}
+}
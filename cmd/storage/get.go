@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage provides commands to manage the files kept in the Data
+// Plane API general storage, such as custom error pages.
+package storage
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetStorageFilesCmd represents "get storage-files".
+var GetStorageFilesCmd = &cobra.Command{
+	Use:     "storage-files [name]",
+	Aliases: []string{"storage-file"},
+	Short:   "List files in the general storage or show one",
+	Long: `List the files uploaded to the Data Plane API general storage, with the
+path HAProxy reads each one from. Use that path in errorfile lines.
+
+Examples:
+  haproxyctl get storage-files
+  haproxyctl get storage-files 503.http -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runGetFiles,
+}
+
+// runGetFiles lists the general storage entries, or shows the one named
+// in args.
+func runGetFiles(cmd *cobra.Command, args []string) {
+	outputFormat := internal.GetFlagString(cmd, "output")
+
+	list, err := Files(cmd.Context())
+	if err != nil {
+		internal.Fatalf("Failed to fetch storage files: %v", err)
+	}
+	if len(args) == 0 {
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(list, storageFileColumns), outputFormat)
+		return
+	}
+
+	for _, entry := range list {
+		if entry["storage_name"] == args[0] {
+			internal.FormatOutputForCmd(cmd, internal.WithColumns(entry, storageFileColumns), outputFormat)
+			return
+		}
+	}
+	internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID(storageFileKind, args[0]))
+}
+
+// storageFileColumns shows each file with the path HAProxy reads it from.
+var storageFileColumns = internal.ColumnSet{
+	Kind:    storageFileKind,
+	Default: []string{"storage_name", "file"},
+	Wide:    []string{"size", "description"},
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage provides commands to manage the files kept in the Data
+// Plane API general storage, such as custom error pages.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"haproxyctl/internal"
+)
+
+const (
+	storageFileKind = "StorageFile"
+
+	generalStoragePath = "/services/haproxy/storage/general"
+)
+
+// Files returns the general storage entries, sorted by name.
+func Files(ctx context.Context) ([]map[string]interface{}, error) {
+	data, err := internal.SendRequestWithContext(ctx, "GET", generalStoragePath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var list []map[string]interface{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse storage list response: %w", err)
+	}
+	internal.SortByStringField(list, "storage_name")
+	return list, nil
+}
+
+// UploadFile stores data under name in the general storage, replacing an
+// entry of the same name whose contents differ, and returns the path HAProxy
+// reads the file from. An empty name defaults to the base name of source,
+// the local path the data was read from.
+func UploadFile(ctx context.Context, name, source string, data []byte) (string, error) {
+	if name == "" {
+		name = filepath.Base(source)
+	}
+
+	list, err := Files(ctx)
+	if err != nil {
+		return "", internal.FormatAPIError(storageFileKind, name, "get", err)
+	}
+	var existing map[string]interface{}
+	for _, entry := range list {
+		if entry["storage_name"] == name {
+			existing = entry
+		}
+	}
+
+	if existing != nil {
+		current, err := internal.SendRequestWithContext(ctx, "GET", generalStoragePath+"/"+name, nil, nil)
+		if err != nil {
+			return "", internal.FormatAPIError(storageFileKind, name, "get", err)
+		}
+		if bytes.Equal(current, data) {
+			internal.PrintStatus(storageFileKind, name, internal.ActionUnchanged)
+			path, _ := existing["file"].(string)
+			return path, nil
+		}
+	}
+
+	path, err := internal.UploadStorageFileWithContext(ctx, name, data, existing != nil)
+	if err != nil {
+		return "", internal.FormatAPIError(storageFileKind, name, "upload", err)
+	}
+	if existing != nil {
+		internal.PrintStatus(storageFileKind, name, internal.ActionConfigured)
+	} else {
+		internal.PrintStatus(storageFileKind, name, internal.ActionCreated)
+	}
+	return path, nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"haproxyctl/cmd/maps"
+
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the "sync" command.
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Incrementally sync live HAProxy data with local files",
+	Long: `Compare live HAProxy data with a local source of truth and apply only the
+differences.
+
+Examples:
+  haproxyctl sync maps routes.map -f routes.map --prune`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.AddCommand(maps.SyncMapsCmd)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package templates provides built-in configuration presets that expand
+// into several Data Plane API objects (tables, ACLs, rules, userlists).
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultTableSize = 100000
+	statusForbidden  = 403
+	statusTooMany    = 429
+)
+
+// CreateFromTemplateCmd represents "create from-template".
+var CreateFromTemplateCmd = &cobra.Command{
+	Use:     "from-template <preset>",
+	Aliases: []string{"template"},
+	Short:   "Create configuration from a built-in preset",
+	Long: `Expand a built-in, parameterized preset into the HAProxy objects it needs
+(stick tables, ACLs, http-request rules, userlists) and create them in a
+single transaction. ACLs and rules are appended after the frontend's
+existing ones.
+
+Presets:
+  rate-limit     Deny clients exceeding N requests per period (stick table)
+  ip-allowlist   Deny clients whose source address is not allowlisted
+  basic-auth     Require HTTP basic authentication against a userlist
+
+Examples:
+  haproxyctl create from-template rate-limit --frontend web --limit 100/10s
+  haproxyctl create from-template ip-allowlist --frontend admin --allow 10.0.0.0/8 --allow 192.168.1.0/24
+  haproxyctl create from-template basic-auth --frontend admin --user ops:s3cret --dry-run`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+var rateLimitCmd = &cobra.Command{
+	Use:   "rate-limit",
+	Short: "Rate limit clients by source address",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		limit, err := parseRateLimit(internal.GetFlagString(cmd, "limit"))
+		if err != nil {
+			internal.FatalCodef(internal.ExitInvalid, "Invalid template parameters: %v", err)
+		}
+		frontend := requireFrontend(cmd)
+		steps := rateLimitSteps(frontend, limit, internal.GetFlagInt(cmd, "table-size"), internal.GetFlagInt(cmd, "status"))
+		runTemplate(cmd, "rate-limit", frontend, steps)
+	},
+}
+
+var ipAllowlistCmd = &cobra.Command{
+	Use:   "ip-allowlist",
+	Short: "Only allow clients from the given networks",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		sources := internal.GetFlagStringSlice(cmd, "allow")
+		if len(sources) == 0 {
+			internal.FatalCodef(internal.ExitUsage, "Invalid template parameters: at least one --allow is required")
+		}
+		frontend := requireFrontend(cmd)
+		steps := ipAllowlistSteps(frontend, internal.GetFlagString(cmd, "acl-name"), sources, internal.GetFlagInt(cmd, "status"))
+		runTemplate(cmd, "ip-allowlist", frontend, steps)
+	},
+}
+
+var basicAuthCmd = &cobra.Command{
+	Use:   "basic-auth",
+	Short: "Require HTTP basic authentication",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		users, err := parseUsers(internal.GetFlagStringSlice(cmd, "user"))
+		if err != nil {
+			internal.FatalCodef(internal.ExitInvalid, "Invalid template parameters: %v", err)
+		}
+		if len(users) == 0 {
+			internal.FatalCodef(internal.ExitUsage, "Invalid template parameters: at least one --user is required")
+		}
+		frontend := requireFrontend(cmd)
+		userlist := internal.GetFlagString(cmd, "userlist")
+		if userlist == "" {
+			userlist = frontend + "_users"
+		}
+		steps := basicAuthSteps(frontend, userlist, internal.GetFlagString(cmd, "realm"), users)
+		runTemplate(cmd, "basic-auth", frontend, steps)
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{rateLimitCmd, ipAllowlistCmd, basicAuthCmd} {
+		c.Flags().String("frontend", "", "Frontend to attach the preset to (required)")
+		c.Flags().Bool("dry-run", false, "Print the objects the preset expands to without creating them")
+		CreateFromTemplateCmd.AddCommand(c)
+	}
+
+	rateLimitCmd.Flags().String("limit", "", "Maximum requests per period per client, e.g. 100/10s (required)")
+	rateLimitCmd.Flags().Int("table-size", defaultTableSize, "Number of entries in the stick table")
+	rateLimitCmd.Flags().Int("status", statusTooMany, "HTTP status returned to limited clients")
+
+	ipAllowlistCmd.Flags().StringArray("allow", nil, "Allowed source address or CIDR (repeatable)")
+	ipAllowlistCmd.Flags().String("acl-name", "allowlist", "Name of the ACL holding the allowed sources")
+	ipAllowlistCmd.Flags().Int("status", statusForbidden, "HTTP status returned to other clients")
+
+	basicAuthCmd.Flags().StringArray("user", nil, "User as user:password (repeatable); crypt(3) hashes are stored as secure passwords")
+	basicAuthCmd.Flags().String("userlist", "", "Userlist to create (default: <frontend>_users)")
+	basicAuthCmd.Flags().String("realm", "", "Authentication realm shown by browsers")
+}
+
+func requireFrontend(cmd *cobra.Command) string {
+	frontend := internal.GetFlagString(cmd, "frontend")
+	if frontend == "" {
+		internal.FatalCodef(internal.ExitUsage, "Invalid template parameters: --frontend is required")
+	}
+	return frontend
+}
+
+// runTemplate previews or creates the expanded steps.
+func runTemplate(cmd *cobra.Command, preset, frontend string, steps []templateStep) {
+	if internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline() {
+		internal.FormatOutput(steps, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return
+	}
+
+	if err := applyTemplate(frontend, steps); err != nil {
+		internal.Fatalf("Failed to create %s template on frontend %q: %v", preset, frontend, err)
+	}
+}
+
+// applyTemplate creates all steps inside one transaction so a preset is
+// either applied completely or not at all.
+func applyTemplate(frontend string, steps []templateStep) error {
+	if _, err := internal.GetResource("/services/haproxy/configuration/frontends/" + frontend); err != nil {
+		return internal.FormatAPIError("Frontend", frontend, "get", err)
+	}
+
+	// Indexed objects are appended after the existing entries of each list.
+	next := make(map[string]int)
+	for _, s := range steps {
+		if !s.isIndexed() {
+			continue
+		}
+		ep := s.listEndpoint()
+		if _, ok := next[ep]; ok {
+			continue
+		}
+		existing, err := internal.GetResourceList(ep)
+		if err != nil && !internal.IsNotFoundError(err) {
+			return fmt.Errorf("failed to list %s: %w", ep, err)
+		}
+		next[ep] = len(existing)
+	}
+
+	tx, err := internal.StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	type createdObject struct{ kind, name string }
+	created := make([]createdObject, 0, len(steps))
+	for _, s := range steps {
+		ep := s.listEndpoint()
+		name, _ := s.Payload["name"].(string)
+		if s.isIndexed() {
+			index := next[ep]
+			next[ep]++
+			ep += "/" + strconv.Itoa(index)
+			name = fmt.Sprintf("%s/%d", s.Parent, index)
+		}
+
+		if _, err := internal.SendRequest("POST", ep, tx.Params(), s.Payload); err != nil {
+			createErr := internal.FormatAPIError(s.Kind, name, "create", err)
+			if abortErr := tx.Abort(); abortErr != nil {
+				return errors.Join(createErr, abortErr)
+			}
+			return createErr
+		}
+		created = append(created, createdObject{kind: s.Kind, name: name})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, c := range created {
+		internal.PrintStatus(c.kind, c.name, internal.ActionCreated)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package templates provides built-in configuration presets that expand
+// into several Data Plane API objects (tables, ACLs, rules, userlists).
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of objects a template step can create.
+const (
+	kindBackend         = "Backend"
+	kindACL             = "ACL"
+	kindHTTPRequestRule = "HTTPRequestRule"
+	kindUserlist        = "Userlist"
+)
+
+// templateStep is one object created by a preset. Steps are created in
+// order inside a single transaction. ACLs and rules are appended to the
+// end of the parent frontend's list; their index is resolved at apply time.
+type templateStep struct {
+	Kind    string                 `json:"kind" yaml:"kind"`
+	Parent  string                 `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Payload map[string]interface{} `json:"payload" yaml:"payload"`
+}
+
+// isIndexed reports whether the step targets an ordered frontend list.
+func (s templateStep) isIndexed() bool {
+	return s.Kind == kindACL || s.Kind == kindHTTPRequestRule
+}
+
+// listEndpoint returns the collection endpoint the step is created in.
+func (s templateStep) listEndpoint() string {
+	switch s.Kind {
+	case kindBackend:
+		return "/services/haproxy/configuration/backends"
+	case kindUserlist:
+		return "/services/haproxy/configuration/userlists"
+	case kindACL:
+		return fmt.Sprintf("/services/haproxy/configuration/frontends/%s/acls", s.Parent)
+	case kindHTTPRequestRule:
+		return fmt.Sprintf("/services/haproxy/configuration/frontends/%s/http_request_rules", s.Parent)
+	default:
+		return ""
+	}
+}
+
+// rateLimit is a parsed --limit value such as "100/10s".
+type rateLimit struct {
+	Requests int
+	Period   string
+	PeriodMS int
+}
+
+// parseRateLimit parses "<requests>/<period>" (e.g. "100/10s", "20/1m").
+func parseRateLimit(s string) (rateLimit, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	if len(parts) != 2 {
+		return rateLimit{}, fmt.Errorf("invalid limit %q (expected <requests>/<period>, e.g. 100/10s)", s)
+	}
+
+	n, err := strconv.Atoi(parts[0])
+	if err != nil || n <= 0 {
+		return rateLimit{}, fmt.Errorf("invalid request count %q in limit %q", parts[0], s)
+	}
+
+	d, err := time.ParseDuration(parts[1])
+	if err != nil {
+		return rateLimit{}, fmt.Errorf("invalid period in limit %q: %w", s, err)
+	}
+	if d < time.Millisecond {
+		return rateLimit{}, errors.New("rate limit period must be at least 1ms")
+	}
+
+	return rateLimit{Requests: n, Period: parts[1], PeriodMS: int(d / time.Millisecond)}, nil
+}
+
+// rateLimitSteps expands the rate-limit preset: a dedicated stick table
+// backend, a track-sc rule and a deny rule on the frontend.
+func rateLimitSteps(frontend string, limit rateLimit, tableSize, denyStatus int) []templateStep {
+	table := "rl_" + frontend
+
+	return []templateStep{
+		{
+			Kind: kindBackend,
+			Payload: map[string]interface{}{
+				"name": table,
+				"stick_table": map[string]interface{}{
+					"type":   "ip",
+					"size":   tableSize,
+					"expire": limit.PeriodMS,
+					"store":  fmt.Sprintf("http_req_rate(%s)", limit.Period),
+				},
+			},
+		},
+		{
+			Kind:   kindHTTPRequestRule,
+			Parent: frontend,
+			Payload: map[string]interface{}{
+				"type":                   "track-sc",
+				"track_sc_stick_counter": 0,
+				"track_sc_key":           "src",
+				"track_sc_table":         table,
+			},
+		},
+		{
+			Kind:   kindHTTPRequestRule,
+			Parent: frontend,
+			Payload: map[string]interface{}{
+				"type":        "deny",
+				"deny_status": denyStatus,
+				"cond":        "if",
+				"cond_test":   fmt.Sprintf("{ sc_http_req_rate(0) gt %d }", limit.Requests),
+			},
+		},
+	}
+}
+
+// ipAllowlistSteps expands the ip-allowlist preset: a src ACL and a deny
+// rule for every client outside it.
+func ipAllowlistSteps(frontend, aclName string, sources []string, denyStatus int) []templateStep {
+	return []templateStep{
+		{
+			Kind:   kindACL,
+			Parent: frontend,
+			Payload: map[string]interface{}{
+				"acl_name":  aclName,
+				"criterion": "src",
+				"value":     strings.Join(sources, " "),
+			},
+		},
+		{
+			Kind:   kindHTTPRequestRule,
+			Parent: frontend,
+			Payload: map[string]interface{}{
+				"type":        "deny",
+				"deny_status": denyStatus,
+				"cond":        "unless",
+				"cond_test":   aclName,
+			},
+		},
+	}
+}
+
+// basicAuthSteps expands the basic-auth preset: a userlist holding the
+// given users and an auth rule challenging unauthenticated requests.
+// Passwords that look like crypt(3) hashes are stored as secure passwords,
+// anything else as insecure-password.
+func basicAuthSteps(frontend, userlist, realm string, users map[string]string) []templateStep {
+	apiUsers := make(map[string]interface{}, len(users))
+	for name, password := range users {
+		apiUsers[name] = map[string]interface{}{
+			"username":        name,
+			"password":        password,
+			"secure_password": strings.HasPrefix(password, "$"),
+		}
+	}
+
+	rule := map[string]interface{}{
+		"type":      "auth",
+		"cond":      "unless",
+		"cond_test": fmt.Sprintf("{ http_auth(%s) }", userlist),
+	}
+	if realm != "" {
+		rule["auth_realm"] = realm
+	}
+
+	return []templateStep{
+		{
+			Kind:    kindUserlist,
+			Payload: map[string]interface{}{"name": userlist, "users": apiUsers},
+		},
+		{
+			Kind:    kindHTTPRequestRule,
+			Parent:  frontend,
+			Payload: rule,
+		},
+	}
+}
+
+// parseUsers turns repeated "user:password" flags into a map.
+func parseUsers(values []string) (map[string]string, error) {
+	users := make(map[string]string, len(values))
+	for _, v := range values {
+		name, password, ok := strings.Cut(v, ":")
+		if !ok || name == "" || password == "" {
+			return nil, fmt.Errorf("invalid --user %q (expected user:password)", v)
+		}
+		users[name] = password
+	}
+	return users, nil
+}
@@ -0,0 +1,65 @@
+package templates
+
+import "testing"
+
+func TestParseRateLimit(t *testing.T) {
+	t.Parallel()
+
+	got, err := parseRateLimit("100/10s")
+	if err != nil {
+		t.Fatalf("parseRateLimit failed: %v", err)
+	}
+	if got.Requests != 100 || got.Period != "10s" || got.PeriodMS != 10000 {
+		t.Fatalf("parseRateLimit = %+v", got)
+	}
+
+	for _, bad := range []string{"", "100", "x/10s", "0/10s", "100/soon"} {
+		if _, err := parseRateLimit(bad); err == nil {
+			t.Fatalf("parseRateLimit(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestRateLimitSteps(t *testing.T) {
+	t.Parallel()
+
+	limit, _ := parseRateLimit("20/1m")
+	steps := rateLimitSteps("web", limit, defaultTableSize, statusTooMany)
+
+	if len(steps) != 3 {
+		t.Fatalf("got %d steps, want 3", len(steps))
+	}
+
+	table, ok := steps[0].Payload["stick_table"].(map[string]interface{})
+	if steps[0].Kind != kindBackend || !ok || table["store"] != "http_req_rate(1m)" || table["expire"] != 60000 {
+		t.Fatalf("unexpected table step: %+v", steps[0])
+	}
+	if steps[1].Payload["track_sc_table"] != "rl_web" {
+		t.Fatalf("track rule does not reference table: %+v", steps[1])
+	}
+	if steps[2].Payload["cond_test"] != "{ sc_http_req_rate(0) gt 20 }" {
+		t.Fatalf("unexpected deny condition: %+v", steps[2])
+	}
+	if steps[1].listEndpoint() != "/services/haproxy/configuration/frontends/web/http_request_rules" {
+		t.Fatalf("unexpected rule endpoint %q", steps[1].listEndpoint())
+	}
+}
+
+func TestBasicAuthSteps_PasswordKinds(t *testing.T) {
+	t.Parallel()
+
+	steps := basicAuthSteps("admin", "admin_users", "", map[string]string{
+		"plain":  "s3cret",
+		"hashed": "$5$salt$hash",
+	})
+
+	users, _ := steps[0].Payload["users"].(map[string]interface{})
+	plain, _ := users["plain"].(map[string]interface{})
+	hashed, _ := users["hashed"].(map[string]interface{})
+	if plain["secure_password"] != false || hashed["secure_password"] != true {
+		t.Fatalf("unexpected secure_password flags: plain=%v hashed=%v", plain, hashed)
+	}
+	if steps[1].Payload["cond_test"] != "{ http_auth(admin_users) }" {
+		t.Fatalf("unexpected auth condition: %+v", steps[1])
+	}
+}
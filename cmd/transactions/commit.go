@@ -0,0 +1,60 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transactions provides commands to open, commit and delete Data
+// Plane API configuration transactions that other commands stage into via
+// the global --transaction flag.
+package transactions
+
+import (
+	"context"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// CommitTransactionsCmd represents "commit transactions <id>".
+var CommitTransactionsCmd = &cobra.Command{
+	Use:     "transactions <id>",
+	Aliases: []string{"transaction"},
+	Short:   "Apply every change staged in a transaction",
+	Long: `Commit a transaction opened with "haproxyctl create transactions",
+applying all changes staged in it atomically with a single reload. The
+commit fails when the configuration changed since the transaction was
+opened; delete it and start over in that case.
+
+Examples:
+  haproxyctl commit transactions 9f6c1b1e-7d2a-4d8e-9a4b-2f0d3c5e8a71
+  haproxyctl commit transactions "$TX" --wait`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := CommitTransaction(cmd.Context(), args[0]); err != nil {
+			internal.Fatalf("Failed to commit transaction %q: %v", args[0], err)
+		}
+	},
+}
+
+// CommitTransaction commits the transaction id.
+func CommitTransaction(ctx context.Context, id string) error {
+	tx := &internal.Transaction{ID: id}
+	if _, err := tx.CommitForReload(ctx); err != nil {
+		return internal.FormatAPIError(transactionKind, id, "commit", err)
+	}
+
+	internal.PrintStatus(transactionKind, id, "committed")
+	return nil
+}
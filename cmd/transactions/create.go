@@ -0,0 +1,77 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transactions provides commands to open, commit and delete Data
+// Plane API configuration transactions that other commands stage into via
+// the global --transaction flag.
+package transactions
+
+import (
+	"fmt"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+const transactionKind = "Transaction"
+
+// CreateTransactionsCmd represents "create transactions".
+var CreateTransactionsCmd = &cobra.Command{
+	Use:     "transactions",
+	Aliases: []string{"transaction"},
+	Short:   "Open a configuration transaction",
+	Long: `Open a configuration transaction against the current configuration
+version. Pass its id to other commands with --transaction to stage their
+changes in it; nothing is applied until "haproxyctl commit transactions"
+commits them all at once, with a single reload. "haproxyctl delete
+transactions" discards the staged changes instead.
+
+A command that fails part-way may leave some of its changes staged; delete
+the transaction when in doubt.
+
+Examples:
+  TX=$(haproxyctl create transactions -q)
+  haproxyctl create backends api --mode http --transaction "$TX"
+  haproxyctl create servers api app1 --address 10.0.0.1 --port 8080 --transaction "$TX"
+  haproxyctl commit transactions "$TX"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		id, err := CreateTransaction()
+		if err != nil {
+			internal.Fatalf("Failed to create transaction: %v", err)
+		}
+		if internal.GetFlagBool(cmd, "quiet") {
+			fmt.Println(id)
+			return
+		}
+		internal.PrintStatus(transactionKind, id, internal.ActionCreated)
+	},
+}
+
+func init() {
+	CreateTransactionsCmd.Flags().BoolP("quiet", "q", false, "Print only the transaction id")
+}
+
+// CreateTransaction opens a new transaction and returns its id. It ignores
+// the active transaction, if any.
+func CreateTransaction() (string, error) {
+	tx, err := internal.OpenTransaction()
+	if err != nil {
+		return "", err
+	}
+	return tx.ID, nil
+}
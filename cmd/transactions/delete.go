@@ -0,0 +1,55 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transactions provides commands to open, commit and delete Data
+// Plane API configuration transactions that other commands stage into via
+// the global --transaction flag.
+package transactions
+
+import (
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// DeleteTransactionsCmd represents "delete transactions <id>".
+var DeleteTransactionsCmd = &cobra.Command{
+	Use:     "transactions <id>",
+	Aliases: []string{"transaction"},
+	Short:   "Discard a transaction and everything staged in it",
+	Long: `Delete an open transaction. None of the changes staged in it are
+applied.
+
+Examples:
+  haproxyctl delete transactions 9f6c1b1e-7d2a-4d8e-9a4b-2f0d3c5e8a71`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := DeleteTransaction(args[0]); err != nil {
+			internal.Fatalf("Failed to delete transaction %q: %v", args[0], err)
+		}
+	},
+}
+
+// DeleteTransaction deletes the transaction id without committing it.
+func DeleteTransaction(id string) error {
+	tx := &internal.Transaction{ID: id}
+	if err := tx.Abort(); err != nil {
+		return internal.FormatAPIError(transactionKind, id, "delete", err)
+	}
+
+	internal.PrintStatus(transactionKind, id, internal.ActionDeleted)
+	return nil
+}
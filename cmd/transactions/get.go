@@ -20,8 +20,6 @@ package transactions
 import (
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
 
 	"haproxyctl/internal"
 
@@ -69,13 +67,12 @@ func getTransactions(cmd *cobra.Command, id string) {
 
 	if err != nil {
 		if id != "" && internal.IsNotFoundError(err) {
-			_, _ = fmt.Fprintln(os.Stdout, internal.ResourceID("Transaction", id)+" not found")
-			return
+			internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Transaction", id))
 		}
-		log.Fatalf("Failed to fetch transaction(s): %v", err)
+		internal.Fatalf("Failed to fetch transaction(s): %v", err)
 	}
 
-	internal.FormatOutput(data, outputFormat)
+	internal.FormatOutputForCmd(cmd, data, outputFormat)
 }
 
 // getTransactionsListFromAPI fetches the list of transactions, optionally filtered by status.
@@ -97,6 +94,7 @@ func getTransactionsListFromAPI(cmd *cobra.Command, status string) ([]map[string
 
 	// Ensure deterministic ordering by transaction ID when present.
 	internal.SortByStringField(list, "id")
+	internal.SortForCmd(cmd, list)
 
 	return list, nil
 }
@@ -0,0 +1,110 @@
+package transactions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+// stageCacheDeletes deletes the "static" and "api" caches the way commands
+// do: through WithTransaction and through WriteParams(nil).
+func stageCacheDeletes(t *testing.T) string {
+	t.Helper()
+
+	return internal.CaptureStdout(t, func() {
+		err := internal.WithTransaction(func(tx *internal.Transaction) error {
+			_, err := internal.SendRequest("DELETE", "/services/haproxy/configuration/caches/static", tx.Params(), nil)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("staged delete failed: %v", err)
+		}
+		params, err := internal.WriteParams(nil)
+		if err != nil {
+			t.Fatalf("WriteParams failed: %v", err)
+		}
+		if _, err := internal.SendRequest("DELETE", "/services/haproxy/configuration/caches/api", params, nil); err != nil {
+			t.Fatalf("staged delete failed: %v", err)
+		}
+		internal.PrintStatus("Cache", "api", internal.ActionDeleted)
+	})
+}
+
+func TestTransactionLifecycle_Commit(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddCache(map[string]interface{}{"name": "static"})
+	srv.AddCache(map[string]interface{}{"name": "api"})
+	version := srv.Version()
+
+	id, err := CreateTransaction()
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	internal.SetActiveTransaction(id)
+	t.Cleanup(func() { internal.SetActiveTransaction("") })
+
+	output := stageCacheDeletes(t)
+	if !strings.Contains(output, "cache/api deleted (in transaction "+id+")") {
+		t.Fatalf("status does not name the transaction:\n%s", output)
+	}
+	if _, ok := srv.Cache("static"); !ok {
+		t.Fatal("change applied before the transaction was committed")
+	}
+	if srv.Version() != version {
+		t.Fatalf("version = %d, want %d", srv.Version(), version)
+	}
+
+	internal.SetActiveTransaction("")
+	output = internal.CaptureStdout(t, func() {
+		if err := CommitTransaction(context.Background(), id); err != nil {
+			t.Fatalf("CommitTransaction failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "transaction/"+id+" committed") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if _, ok := srv.Cache("static"); ok {
+		t.Fatal("cache static not deleted by the commit")
+	}
+	if _, ok := srv.Cache("api"); ok {
+		t.Fatal("cache api not deleted by the commit")
+	}
+	if srv.Version() != version+1 {
+		t.Fatalf("version = %d, want %d (single commit)", srv.Version(), version+1)
+	}
+}
+
+func TestTransactionLifecycle_Delete(t *testing.T) {
+	srv := testserver.New(t)
+	srv.AddCache(map[string]interface{}{"name": "static"})
+	srv.AddCache(map[string]interface{}{"name": "api"})
+
+	id, err := CreateTransaction()
+	if err != nil {
+		t.Fatalf("CreateTransaction failed: %v", err)
+	}
+	internal.SetActiveTransaction(id)
+	t.Cleanup(func() { internal.SetActiveTransaction("") })
+	_ = stageCacheDeletes(t)
+	internal.SetActiveTransaction("")
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteTransaction(id); err != nil {
+			t.Fatalf("DeleteTransaction failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "transaction/"+id+" deleted") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if _, ok := srv.Cache("static"); !ok {
+		t.Fatal("discarded change was applied")
+	}
+
+	err = CommitTransaction(context.Background(), id)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found committing a deleted transaction, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userlists
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"haproxyctl/internal"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ValidateUserlistFromYAML checks a userlist manifest the way apply does,
+// without contacting the Data Plane API.
+func ValidateUserlistFromYAML(data []byte) error {
+	var manifest UserlistManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse userlist manifest: %w", err)
+	}
+	if _, err := manifest.toAPIPayload(); err != nil {
+		return fmt.Errorf("invalid userlist configuration: %w", err)
+	}
+	return nil
+}
+
+// ApplyUserlistFromYAML applies a userlist manifest in a declarative way:
+//   - If the userlist does not exist, it is created.
+//   - If it exists with different users or groups, it is deleted and
+//     recreated in one transaction, since userlists cannot be replaced in
+//     place.
+//
+// With hash_passwords the manifest passwords are plain text; they are hashed
+// locally and a password matching the live hash does not count as a change.
+func ApplyUserlistFromYAML(data []byte, outputFormat string, dryRun bool) error {
+	var manifest UserlistManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse userlist manifest: %w", err)
+	}
+	if _, err := manifest.toAPIPayload(); err != nil {
+		return fmt.Errorf("invalid userlist configuration: %w", err)
+	}
+
+	if outputFormat != "" || dryRun {
+		if err := manifest.hashPasswords(nil); err != nil {
+			return err
+		}
+		payload, _ := manifest.toAPIPayload()
+		if outputFormat == "" {
+			internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		} else {
+			internal.FormatOutput(payload, outputFormat)
+		}
+		if dryRun {
+			internal.PrintDryRun()
+		}
+		return nil
+	}
+
+	name := manifest.Name
+	current, err := getUserlistManifest(context.Background(), name)
+	if err != nil && !internal.IsNotFoundError(err) {
+		return fmt.Errorf("failed to check userlist existence: %w", err)
+	}
+	exists := err == nil
+	if err := manifest.hashPasswords(current); err != nil {
+		return err
+	}
+	payload, _ := manifest.toAPIPayload()
+
+	manifest.APIVersion, manifest.Kind = apiVersionV1, userlistKind
+	manifest.sortMembers()
+	if exists && reflect.DeepEqual(*current, manifest) {
+		internal.PrintStatus("Userlist", name, internal.ActionUnchanged)
+		return nil
+	}
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		if exists {
+			if _, err := internal.SendRequest("DELETE", "/services/haproxy/configuration/userlists/"+name, tx.Params(), nil); err != nil {
+				return fmt.Errorf("failed to replace userlist %q: %w", name, err)
+			}
+		}
+		if _, err := internal.SendRequest("POST", "/services/haproxy/configuration/userlists", tx.Params(), payload); err != nil {
+			return fmt.Errorf("failed to create userlist %q: %w", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		internal.PrintStatus("Userlist", name, internal.ActionConfigured)
+	} else {
+		internal.PrintStatus("Userlist", name, internal.ActionCreated)
+	}
+	return nil
+}
@@ -38,11 +38,20 @@ func CreateUserlistFromFile(data []byte) error {
 		return fmt.Errorf("invalid kind %q, expected %q", manifest.Kind, userlistKind)
 	}
 
+	if err := manifest.hashPasswords(nil); err != nil {
+		return err
+	}
 	payload, err := manifest.toAPIPayload()
 	if err != nil {
 		return err
 	}
 
+	if internal.IsOffline() {
+		internal.FormatOutput(manifest, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
 	_, err = internal.SendRequest("POST", "/services/haproxy/configuration/userlists", nil, payload)
 	if err != nil {
 		return internal.FormatAPIError("Userlist", manifest.Name, "create", err)
@@ -20,7 +20,6 @@ package userlists
 import (
 	"fmt"
 	"haproxyctl/internal"
-	"log"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -34,7 +33,7 @@ var DeleteUserlistsCmd = &cobra.Command{
 	Run: func(_ *cobra.Command, args []string) {
 		name := args[0]
 		if err := DeleteUserlistByName(name); err != nil {
-			log.Fatalf("Failed to delete userlist %q: %v", name, err)
+			internal.Fatalf("Failed to delete userlist %q: %v", name, err)
 		}
 	},
 }
@@ -18,12 +18,11 @@ limitations under the License.
 package userlists
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"haproxyctl/internal"
-	"log"
 	"net/url"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -49,7 +48,7 @@ func getUserlists(cmd *cobra.Command, name string) {
 	if name == "" {
 		list, err := internal.GetResourceList("/services/haproxy/configuration/userlists")
 		if err != nil {
-			log.Fatalf("Failed to fetch userlists: %v", err)
+			internal.Fatalf("Failed to fetch userlists: %v", err)
 		}
 
 		if outputFormat == "" {
@@ -57,17 +56,16 @@ func getUserlists(cmd *cobra.Command, name string) {
 		}
 
 		internal.SortByStringField(list, "name")
-		internal.FormatOutput(list, outputFormat)
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(list, internal.ColumnSet{Kind: "Userlist"}), outputFormat)
 		return
 	}
 
-	manifest, err := getUserlistManifest(cmd, name)
+	manifest, err := getUserlistManifest(cmd.Context(), name)
 	if err != nil {
 		if internal.IsNotFoundError(err) {
-			_, _ = fmt.Fprintln(os.Stdout, internal.ResourceID("Userlist", name)+" not found")
-			return
+			internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Userlist", name))
 		}
-		log.Fatalf("Failed to fetch userlist %q: %v", name, err)
+		internal.Fatalf("Failed to fetch userlist %q: %v", name, err)
 	}
 
 	if outputFormat == "" {
@@ -76,20 +74,20 @@ func getUserlists(cmd *cobra.Command, name string) {
 			"users":  len(manifest.Users),
 			"groups": len(manifest.Groups),
 		}
-		internal.FormatOutput(row, "table")
+		internal.FormatOutputForCmd(cmd, row, "table")
 		return
 	}
 
-	internal.FormatOutput(manifest, outputFormat)
+	internal.FormatOutputForCmd(cmd, manifest, outputFormat)
 }
 
 // getUserlistManifest fetches a single userlist (with full_section=true) and
 // converts it into a manifest.
-func getUserlistManifest(cmd *cobra.Command, name string) (*UserlistManifest, error) {
+func getUserlistManifest(ctx context.Context, name string) (*UserlistManifest, error) {
 	escaped := url.PathEscape(name)
 	endpoint := "/services/haproxy/configuration/userlists/" + escaped
 
-	raw, err := internal.SendRequestWithContext(cmd.Context(), "GET", endpoint, map[string]string{"full_section": "true"}, nil)
+	raw, err := internal.SendRequestWithContext(ctx, "GET", endpoint, map[string]string{"full_section": "true"}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -101,3 +99,24 @@ func getUserlistManifest(cmd *cobra.Command, name string) (*UserlistManifest, er
 
 	return manifestFromAPI(obj)
 }
+
+// UserlistManifests returns every userlist, with its users and groups, as a
+// manifest that apply accepts, sorted by name.
+func UserlistManifests(ctx context.Context) ([]interface{}, error) {
+	list, err := internal.GetResourceList("/services/haproxy/configuration/userlists")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch userlists: %w", err)
+	}
+	internal.SortByStringField(list, "name")
+
+	manifests := make([]interface{}, 0, len(list))
+	for _, raw := range list {
+		name, _ := raw["name"].(string)
+		manifest, err := getUserlistManifest(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch userlist %q: %w", name, err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package userlists provides commands to manage HAProxy userlists, users, and groups.
+package userlists
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetGroupsCmd represents "get groups <userlist> [name]".
+var GetGroupsCmd = &cobra.Command{
+	Use:     "groups <userlist> [name]",
+	Aliases: []string{"group"},
+	Short:   "List the groups of a userlist",
+	Long: `List the groups of a userlist with their members.
+
+Examples:
+  haproxyctl get groups admins
+  haproxyctl get groups admins ops -o yaml`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		groups, err := listMembers(args[0], "groups")
+		if err != nil {
+			internal.Fatalf("Failed to fetch groups of userlist %q: %v", args[0], err)
+		}
+		rows := make([]map[string]interface{}, 0, len(groups))
+		for _, g := range groups {
+			name, _ := g["name"].(string)
+			if len(args) == 2 && name != args[1] {
+				continue
+			}
+			rows = append(rows, map[string]interface{}{"name": name, "users": g["users"]})
+		}
+		if len(args) == 2 && len(rows) == 0 {
+			internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("Group", args[0]+"/"+args[1]))
+		}
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, groupColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// groupColumns shows each group with its members.
+var groupColumns = internal.ColumnSet{
+	Kind:    "Group",
+	Default: []string{"name", "users"},
+}
+
+// CreateGroupsCmd represents "create groups <userlist> <name>".
+var CreateGroupsCmd = &cobra.Command{
+	Use:     "groups <userlist> <name>",
+	Aliases: []string{"group"},
+	Short:   "Add a group to a userlist",
+	Long: `Add a group to an existing userlist, optionally with initial members.
+Members can also be added later with "haproxyctl add-user-to-group".
+
+Examples:
+  haproxyctl create groups admins ops
+  haproxyctl create groups admins ops --users alice,bob`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		users, _ := cmd.Flags().GetStringSlice("users")
+		dryRun := internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline()
+		if err := CreateGroup(args[0], GroupManifest{Name: args[1], Users: users}, dryRun); err != nil {
+			internal.Fatalf("Failed to create group %q in userlist %q: %v", args[1], args[0], err)
+		}
+	},
+}
+
+// DeleteGroupsCmd represents "delete groups <userlist> <name>".
+var DeleteGroupsCmd = &cobra.Command{
+	Use:     "groups <userlist> <name>",
+	Aliases: []string{"group"},
+	Short:   "Remove a group from a userlist",
+	Long: `Remove a group from a userlist. Users that belong to the group are
+updated in the same transaction.
+
+Examples:
+  haproxyctl delete groups admins ops`,
+	Args: cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := DeleteGroup(args[0], args[1]); err != nil {
+			internal.Fatalf("Failed to delete group %q from userlist %q: %v", args[1], args[0], err)
+		}
+	},
+}
+
+// AddUserToGroupCmd represents "add-user-to-group <userlist> <user> <group>".
+var AddUserToGroupCmd = &cobra.Command{
+	Use:   "add-user-to-group <userlist> <user> <group>",
+	Short: "Add an existing user to a group of the same userlist",
+	Long: `Add an existing user to an existing group of the same userlist. The
+user's password and other groups are left as they are. Running the command
+again is a no-op.
+
+Examples:
+  haproxyctl add-user-to-group admins alice ops`,
+	Args: cobra.ExactArgs(3),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := AddUserToGroup(args[0], args[1], args[2]); err != nil {
+			internal.Fatalf("Failed to add user %q to group %q: %v", args[1], args[2], err)
+		}
+	},
+}
+
+func init() {
+	CreateGroupsCmd.Flags().StringSlice("users", nil, "Initial members of the group (comma-separated)")
+	CreateGroupsCmd.Flags().Bool("dry-run", false, "Print the group without creating it")
+}
+
+// CreateGroup adds a group to a userlist. Listed members must already exist.
+func CreateGroup(userlist string, group GroupManifest, dryRun bool) error {
+	if group.Name == "" {
+		return errors.New("group name is required")
+	}
+	payload := map[string]interface{}{"name": group.Name}
+	setNames(payload, "users", group.Users)
+	id := userlist + "/" + group.Name
+
+	if dryRun {
+		internal.FormatOutput(payload, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	users, err := listMembers(userlist, "users")
+	if err != nil {
+		return err
+	}
+	for _, u := range group.Users {
+		if findMember(users, "username", u) == nil {
+			return fmt.Errorf("user %q does not exist in userlist %q", u, userlist)
+		}
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("POST", membersPath(userlist, "groups"), params, payload); err != nil {
+		return internal.FormatAPIError("Group", id, "create", err)
+	}
+
+	internal.PrintStatus("Group", id, internal.ActionCreated)
+	return nil
+}
+
+// DeleteGroup removes a group and drops it from the groups of every user,
+// in one transaction.
+func DeleteGroup(userlist, name string) error {
+	users, err := listMembers(userlist, "users")
+	if err != nil {
+		return err
+	}
+	id := userlist + "/" + name
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		for _, u := range users {
+			groups := splitNames(u["groups"])
+			kept := removeName(groups, name)
+			if len(kept) == len(groups) {
+				continue
+			}
+			user := copyMember(u)
+			setNames(user, "groups", kept)
+			username, _ := u["username"].(string)
+			if _, err := internal.SendRequest("PUT", membersPath(userlist, "users")+"/"+username, tx.Params(), user); err != nil {
+				return fmt.Errorf("failed to remove user %q from the group: %w", username, err)
+			}
+		}
+		if _, err := internal.SendRequest("DELETE", membersPath(userlist, "groups")+"/"+name, tx.Params(), nil); err != nil {
+			return internal.FormatAPIError("Group", id, "delete", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus("Group", id, internal.ActionDeleted)
+	return nil
+}
+
+// AddUserToGroup appends group to the groups of an existing user.
+func AddUserToGroup(userlist, username, group string) error {
+	users, err := listMembers(userlist, "users")
+	if err != nil {
+		return err
+	}
+	groups, err := listMembers(userlist, "groups")
+	if err != nil {
+		return err
+	}
+	user := findMember(users, "username", username)
+	if user == nil {
+		return fmt.Errorf("user %q does not exist in userlist %q", username, userlist)
+	}
+	if findMember(groups, "name", group) == nil {
+		return fmt.Errorf("group %q does not exist in userlist %q", group, userlist)
+	}
+
+	id := userlist + "/" + username
+	current := splitNames(user["groups"])
+	if slices.Contains(current, group) {
+		internal.PrintStatus("User", id, "group "+group+" "+internal.ActionUnchanged)
+		return nil
+	}
+
+	updated := copyMember(user)
+	setNames(updated, "groups", append(current, group))
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := internal.SendRequest("PUT", membersPath(userlist, "users")+"/"+username, params, updated); err != nil {
+		return internal.FormatAPIError("User", id, "update", err)
+	}
+
+	internal.PrintStatus("User", id, "added to group "+group)
+	return nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package userlists provides commands to manage HAProxy userlists, users, and groups.
+package userlists
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HAProxy checks secure passwords with crypt(3). Hashing locally with the
+// glibc SHA-512 scheme ("$6$") keeps clear-text passwords away from the
+// Data Plane API and works on every HAProxy build linked against glibc.
+
+const (
+	sha512CryptPrefix = "$6$"
+	sha512SaltLength  = 16
+	sha512Rounds      = 5000
+	cryptAlphabet     = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// HashPassword returns a sha-512 crypt hash of password with a random salt.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, sha512SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	for i := range salt {
+		salt[i] = cryptAlphabet[int(salt[i])%len(cryptAlphabet)]
+	}
+	return sha512Crypt(password, string(salt), sha512Rounds), nil
+}
+
+// PasswordMatchesHash reports whether password hashes to hash, a sha-512
+// crypt string. Other hash schemes never match.
+func PasswordMatchesHash(password, hash string) bool {
+	salt, rounds, ok := parseSHA512Crypt(hash)
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(sha512Crypt(password, salt, rounds)), []byte(hash)) == 1
+}
+
+// parseSHA512Crypt extracts the salt and rounds of a "$6$[rounds=N$]salt$hash"
+// string.
+func parseSHA512Crypt(hash string) (salt string, rounds int, ok bool) {
+	rest, found := strings.CutPrefix(hash, sha512CryptPrefix)
+	if !found {
+		return "", 0, false
+	}
+	rounds = sha512Rounds
+	if r, after, found := strings.Cut(rest, "$"); found && strings.HasPrefix(r, "rounds=") {
+		n, err := strconv.Atoi(strings.TrimPrefix(r, "rounds="))
+		if err != nil {
+			return "", 0, false
+		}
+		rounds, rest = n, after
+	}
+	salt, _, found = strings.Cut(rest, "$")
+	if !found {
+		return "", 0, false
+	}
+	return salt, rounds, true
+}
+
+// sha512Crypt implements the SHA-512 based crypt(3) scheme as specified by
+// Ulrich Drepper ("Unix crypt using SHA-256 and SHA-512").
+func sha512Crypt(password, salt string, rounds int) string {
+	pw, s := []byte(password), []byte(salt)
+	if len(s) > sha512SaltLength {
+		s = s[:sha512SaltLength]
+	}
+	rounds = min(max(rounds, 1000), 999999999)
+
+	b := sha512.New()
+	b.Write(pw)
+	b.Write(s)
+	b.Write(pw)
+	sumB := b.Sum(nil)
+
+	a := sha512.New()
+	a.Write(pw)
+	a.Write(s)
+	n := len(pw)
+	for ; n > sha512.Size; n -= sha512.Size {
+		a.Write(sumB)
+	}
+	a.Write(sumB[:n])
+	for n = len(pw); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			a.Write(sumB)
+		} else {
+			a.Write(pw)
+		}
+	}
+	sum := a.Sum(nil)
+
+	dp := sha512.New()
+	for range pw {
+		dp.Write(pw)
+	}
+	p := repeatTo(dp.Sum(nil), len(pw))
+
+	ds := sha512.New()
+	for i := 0; i < 16+int(sum[0]); i++ {
+		ds.Write(s)
+	}
+	sp := repeatTo(ds.Sum(nil), len(s))
+
+	for r := 0; r < rounds; r++ {
+		c := sha512.New()
+		if r&1 != 0 {
+			c.Write(p)
+		} else {
+			c.Write(sum)
+		}
+		if r%3 != 0 {
+			c.Write(sp)
+		}
+		if r%7 != 0 {
+			c.Write(p)
+		}
+		if r&1 != 0 {
+			c.Write(sum)
+		} else {
+			c.Write(p)
+		}
+		sum = c.Sum(nil)
+	}
+
+	var out strings.Builder
+	out.WriteString(sha512CryptPrefix)
+	if rounds != sha512Rounds {
+		fmt.Fprintf(&out, "rounds=%d$", rounds)
+	}
+	out.Write(s)
+	out.WriteByte('$')
+	for i := 0; i < 21; i++ {
+		// The digest is encoded in groups of three bytes taken at a fixed
+		// stride of 21 and rotated per group.
+		x, y, z := i, i+21, i+42
+		switch i % 3 {
+		case 1:
+			x, y, z = i+21, i+42, i
+		case 2:
+			x, y, z = i+42, i, i+21
+		}
+		encodeCrypt64(&out, uint(sum[x])<<16|uint(sum[y])<<8|uint(sum[z]), 4)
+	}
+	encodeCrypt64(&out, uint(sum[63]), 2)
+	return out.String()
+}
+
+// repeatTo fills n bytes with copies of digest.
+func repeatTo(digest []byte, n int) []byte {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		out = append(out, digest[:min(len(digest), n-len(out))]...)
+	}
+	return out
+}
+
+// encodeCrypt64 writes the low 6*n bits of w in crypt's base64 alphabet,
+// least significant first.
+func encodeCrypt64(out *strings.Builder, w uint, n int) {
+	for ; n > 0; n-- {
+		out.WriteByte(cryptAlphabet[w&0x3f])
+		w >>= 6
+	}
+}
@@ -20,6 +20,7 @@ package userlists
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -36,6 +37,10 @@ type UserlistManifest struct {
 	Name       string          `json:"name" yaml:"name"`
 	Users      []UserManifest  `json:"users,omitempty" yaml:"users,omitempty"`
 	Groups     []GroupManifest `json:"groups,omitempty" yaml:"groups,omitempty"`
+
+	// HashPasswords marks the user passwords as plain text to be hashed
+	// locally (sha-512 crypt) before they are sent to the API.
+	HashPasswords bool `json:"hash_passwords,omitempty" yaml:"hash_passwords,omitempty"`
 }
 
 // UserManifest represents a single user in a userlist manifest.
@@ -126,9 +131,49 @@ func manifestFromAPI(obj map[string]interface{}) (*UserlistManifest, error) {
 		}
 	}
 
+	manifest.sortMembers()
 	return manifest, nil
 }
 
+// sortMembers orders users and groups by name, so manifests built from the
+// API's maps are stable and comparable.
+func (m *UserlistManifest) sortMembers() {
+	sort.Slice(m.Users, func(i, j int) bool { return m.Users[i].Name < m.Users[j].Name })
+	sort.Slice(m.Groups, func(i, j int) bool { return m.Groups[i].Name < m.Groups[j].Name })
+}
+
+// hashPasswords replaces plain-text passwords with sha-512 crypt hashes when
+// HashPasswords is set. A password that still matches the hash of the same
+// user in current keeps that hash, so re-applying an unchanged manifest is a
+// no-op; current may be nil.
+func (m *UserlistManifest) hashPasswords(current *UserlistManifest) error {
+	if !m.HashPasswords {
+		return nil
+	}
+	live := make(map[string]string)
+	if current != nil {
+		for _, u := range current.Users {
+			live[u.Name] = u.Password
+		}
+	}
+	for i, u := range m.Users {
+		if u.Password == "" {
+			return fmt.Errorf("user %q has no password to hash", u.Name)
+		}
+		if hash, ok := live[u.Name]; ok && PasswordMatchesHash(u.Password, hash) {
+			m.Users[i].Password = hash
+			continue
+		}
+		hash, err := HashPassword(u.Password)
+		if err != nil {
+			return err
+		}
+		m.Users[i].Password = hash
+	}
+	m.HashPasswords = false
+	return nil
+}
+
 // toAPIPayload converts a manifest into the wire-format map expected by the
 // Data Plane API userlists endpoints.
 func (m *UserlistManifest) toAPIPayload() (map[string]interface{}, error) {
@@ -0,0 +1,195 @@
+package userlists
+
+import (
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+)
+
+func seedUserlist(srv *testserver.Server) {
+	srv.AddUserlist(map[string]interface{}{
+		"name": "admins",
+		"users": map[string]interface{}{
+			"alice": map[string]interface{}{"username": "alice", "password": "$6$old", "secure_password": true, "groups": "ops"},
+		},
+		"groups": map[string]interface{}{
+			"ops": map[string]interface{}{"name": "ops", "users": "alice"},
+			"dev": map[string]interface{}{"name": "dev"},
+		},
+	})
+}
+
+// member returns a user or group from the stored userlist.
+func member(t *testing.T, srv *testserver.Server, field, name string) map[string]interface{} {
+	t.Helper()
+	userlist, ok := srv.Userlist("admins")
+	if !ok {
+		t.Fatal("userlist admins missing")
+	}
+	members, _ := userlist[field].(map[string]interface{})
+	m, _ := members[name].(map[string]interface{})
+	return m
+}
+
+func TestCreateUser_CreatesAndRotates(t *testing.T) {
+	srv := testserver.New(t)
+	seedUserlist(srv)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := CreateUser("admins", UserManifest{Name: "bob", Password: "$6$bob", Groups: []string{"dev"}}, userOptions{}); err != nil {
+			t.Fatalf("CreateUser failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "user/admins/bob created") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if bob := member(t, srv, "users", "bob"); bob["groups"] != "dev" || bob["secure_password"] != true {
+		t.Fatalf("bob = %+v", bob)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := CreateUser("admins", UserManifest{Name: "alice", Password: "$6$new"}, userOptions{}); err != nil {
+			t.Fatalf("rotating alice failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "user/admins/alice configured") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if alice := member(t, srv, "users", "alice"); alice["password"] != "$6$new" || alice["groups"] != "ops" {
+		t.Fatalf("alice = %+v, want new password and groups kept", alice)
+	}
+
+	if err := CreateUser("admins", UserManifest{Name: "carol", Password: "x", Groups: []string{"missing"}}, userOptions{}); err == nil {
+		t.Fatal("expected error for an unknown group")
+	}
+}
+
+func TestAddUserToGroup(t *testing.T) {
+	srv := testserver.New(t)
+	seedUserlist(srv)
+
+	output := internal.CaptureStdout(t, func() {
+		if err := AddUserToGroup("admins", "alice", "dev"); err != nil {
+			t.Fatalf("AddUserToGroup failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "user/admins/alice added to group dev") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if alice := member(t, srv, "users", "alice"); alice["groups"] != "ops,dev" || alice["password"] != "$6$old" {
+		t.Fatalf("alice = %+v", alice)
+	}
+
+	output = internal.CaptureStdout(t, func() {
+		if err := AddUserToGroup("admins", "alice", "dev"); err != nil {
+			t.Fatalf("second AddUserToGroup failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "group dev unchanged") {
+		t.Fatalf("expected unchanged on second run, got:\n%s", output)
+	}
+	if err := AddUserToGroup("admins", "alice", "missing"); err == nil {
+		t.Fatal("expected error for an unknown group")
+	}
+}
+
+func TestDeleteUserAndGroup_UpdateReferences(t *testing.T) {
+	srv := testserver.New(t)
+	seedUserlist(srv)
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := CreateGroup("admins", GroupManifest{Name: "audit", Users: []string{"alice"}}, false); err != nil {
+			t.Fatalf("CreateGroup failed: %v", err)
+		}
+		if err := DeleteGroup("admins", "ops"); err != nil {
+			t.Fatalf("DeleteGroup failed: %v", err)
+		}
+	})
+	if member(t, srv, "groups", "ops") != nil {
+		t.Fatal("group ops not deleted")
+	}
+	if alice := member(t, srv, "users", "alice"); alice["groups"] != nil {
+		t.Fatalf("alice still references ops: %+v", alice)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := DeleteUser("admins", "alice"); err != nil {
+			t.Fatalf("DeleteUser failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "user/admins/alice deleted") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	if audit := member(t, srv, "groups", "audit"); audit == nil || audit["users"] != nil {
+		t.Fatalf("audit = %+v, want no members", audit)
+	}
+}
+
+func TestSHA512Crypt(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors from "Unix crypt using SHA-256 and SHA-512".
+	if got := sha512Crypt("Hello world!", "saltstring", 5000); got != "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1" {
+		t.Errorf("default rounds: got %s", got)
+	}
+	want := "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."
+	if got := sha512Crypt("Hello world!", "saltstringsaltstring", 10000); got != want {
+		t.Errorf("10000 rounds: got %s", got)
+	}
+	if !PasswordMatchesHash("Hello world!", want) || PasswordMatchesHash("hello world!", want) {
+		t.Error("PasswordMatchesHash does not verify the rounds= vector")
+	}
+
+	hash, err := HashPassword("s3cret")
+	if err != nil || !strings.HasPrefix(hash, "$6$") || !PasswordMatchesHash("s3cret", hash) {
+		t.Fatalf("HashPassword = %q, %v", hash, err)
+	}
+}
+
+const testHashedUserlistManifest = `apiVersion: haproxyctl/v1
+kind: Userlist
+name: admins
+hash_passwords: true
+users:
+  - name: alice
+    password: s3cret
+`
+
+func TestApplyUserlistFromYAML_HashPasswords(t *testing.T) {
+	srv := testserver.New(t)
+
+	_ = internal.CaptureStdout(t, func() {
+		if err := ApplyUserlistFromYAML([]byte(testHashedUserlistManifest), "", false); err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+	})
+	alice := member(t, srv, "users", "alice")
+	hash, _ := alice["password"].(string)
+	if !PasswordMatchesHash("s3cret", hash) || alice["secure_password"] != true {
+		t.Fatalf("alice = %+v, want a sha-512 crypt hash of the password", alice)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := ApplyUserlistFromYAML([]byte(testHashedUserlistManifest), "", false); err != nil {
+			t.Fatalf("second apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "userlist/admins unchanged") {
+		t.Fatalf("expected unchanged status, got:\n%s", output)
+	}
+
+	changed := strings.Replace(testHashedUserlistManifest, "s3cret", "rotated", 1)
+	output = internal.CaptureStdout(t, func() {
+		if err := ApplyUserlistFromYAML([]byte(changed), "", false); err != nil {
+			t.Fatalf("third apply failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "userlist/admins configured") {
+		t.Fatalf("expected configured status, got:\n%s", output)
+	}
+	if hash, _ := member(t, srv, "users", "alice")["password"].(string); !PasswordMatchesHash("rotated", hash) {
+		t.Fatalf("password not rotated: %q", hash)
+	}
+}
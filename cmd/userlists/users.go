@@ -0,0 +1,339 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package userlists provides commands to manage HAProxy userlists, users, and groups.
+package userlists
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// GetUsersCmd represents "get users <userlist> [name]".
+var GetUsersCmd = &cobra.Command{
+	Use:     "users <userlist> [name]",
+	Aliases: []string{"user"},
+	Short:   "List the users of a userlist",
+	Long: `List the users of a userlist with the groups they belong to. Password
+hashes are not shown.
+
+Examples:
+  haproxyctl get users admins
+  haproxyctl get users admins alice -o yaml`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		users, err := listMembers(args[0], "users")
+		if err != nil {
+			internal.Fatalf("Failed to fetch users of userlist %q: %v", args[0], err)
+		}
+		rows := make([]map[string]interface{}, 0, len(users))
+		for _, u := range users {
+			name, _ := u["username"].(string)
+			if len(args) == 2 && name != args[1] {
+				continue
+			}
+			secure, _ := u["secure_password"].(bool)
+			rows = append(rows, map[string]interface{}{
+				"name":            name,
+				"groups":          u["groups"],
+				"secure_password": secure,
+			})
+		}
+		if len(args) == 2 && len(rows) == 0 {
+			internal.FatalCodef(internal.ExitNotFound, "%s not found", internal.ResourceID("User", args[0]+"/"+args[1]))
+		}
+		internal.FormatOutputForCmd(cmd, internal.WithColumns(rows, userColumns), internal.GetFlagString(cmd, "output"))
+	},
+}
+
+// userColumns shows each user with its groups.
+var userColumns = internal.ColumnSet{
+	Kind:    "User",
+	Default: []string{"name", "groups", "secure_password"},
+}
+
+// CreateUsersCmd represents "create users <userlist> <name>".
+var CreateUsersCmd = &cobra.Command{
+	Use:     "users <userlist> <name>",
+	Aliases: []string{"user"},
+	Short:   "Add a user to a userlist or change its password",
+	Long: `Add a user to an existing userlist. If the user already exists its
+password is replaced, so this is also how a single account is rotated
+without replacing the whole list. The user keeps its groups unless --groups
+is given.
+
+--password is stored as given: pass a crypt(3) hash (for example from
+"mkpasswd -m sha-512"), or a plain text password together with --hash to
+hash it locally (sha-512 crypt) so the clear text never reaches the API.
+--insecure-password stores it as plain text instead. Use --password-stdin
+to keep it out of the shell history.
+
+Examples:
+  haproxyctl create users admins alice --password '$6$...' --groups ops
+  mkpasswd -m sha-512 | haproxyctl create users admins alice --password-stdin
+  haproxyctl create users admins alice --password-stdin --hash < secret.txt`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		password := internal.GetFlagString(cmd, "password")
+		if internal.GetFlagBool(cmd, "password-stdin") {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				internal.Fatalf("Failed to read password from stdin: %v", err)
+			}
+			password = strings.TrimRight(line, "\r\n")
+		}
+		user := UserManifest{Name: args[1], Password: password}
+		if cmd.Flags().Changed("groups") {
+			user.Groups, _ = cmd.Flags().GetStringSlice("groups")
+			if user.Groups == nil {
+				user.Groups = []string{}
+			}
+		}
+		opts := userOptions{
+			Hash:     internal.GetFlagBool(cmd, "hash"),
+			Insecure: internal.GetFlagBool(cmd, "insecure-password"),
+			DryRun:   internal.GetFlagBool(cmd, "dry-run") || internal.IsOffline(),
+		}
+		if err := CreateUser(args[0], user, opts); err != nil {
+			internal.Fatalf("Failed to create user %q in userlist %q: %v", args[1], args[0], err)
+		}
+	},
+}
+
+// DeleteUsersCmd represents "delete users <userlist> <name>".
+var DeleteUsersCmd = &cobra.Command{
+	Use:     "users <userlist> <name>",
+	Aliases: []string{"user"},
+	Short:   "Remove a user from a userlist",
+	Long: `Remove a user from a userlist. Groups that list the user as a member
+are updated in the same transaction.
+
+Examples:
+  haproxyctl delete users admins alice`,
+	Args: cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := DeleteUser(args[0], args[1]); err != nil {
+			internal.Fatalf("Failed to delete user %q from userlist %q: %v", args[1], args[0], err)
+		}
+	},
+}
+
+func init() {
+	CreateUsersCmd.Flags().String("password", "", "Password hash, or plain text with --insecure-password")
+	CreateUsersCmd.Flags().Bool("password-stdin", false, "Read the password from the first line of stdin")
+	CreateUsersCmd.Flags().Bool("hash", false, "Hash the plain-text password locally (sha-512 crypt) before sending it")
+	CreateUsersCmd.Flags().Bool("insecure-password", false, "Store the password as plain text (insecure-password)")
+	CreateUsersCmd.Flags().StringSlice("groups", nil, "Groups the user belongs to (comma-separated; replaces the current groups)")
+	CreateUsersCmd.Flags().Bool("dry-run", false, "Print the user without creating it")
+}
+
+type userOptions struct {
+	Hash     bool
+	Insecure bool
+	DryRun   bool
+}
+
+// membersPath returns the users or groups endpoint of a userlist.
+func membersPath(userlist, kind string) string {
+	return "/services/haproxy/configuration/userlists/" + userlist + "/" + kind
+}
+
+// listMembers returns the users or groups of a userlist.
+func listMembers(userlist, kind string) ([]map[string]interface{}, error) {
+	list, err := internal.GetResourceList(membersPath(userlist, kind))
+	if err != nil {
+		return nil, internal.FormatAPIError("Userlist", userlist, "get", err)
+	}
+	return list, nil
+}
+
+// findMember returns the member whose key field equals name, or nil.
+func findMember(list []map[string]interface{}, key, name string) map[string]interface{} {
+	for _, m := range list {
+		if m[key] == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// splitNames splits a comma-separated member list as the API stores it.
+func splitNames(v interface{}) []string {
+	s, _ := v.(string)
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// CreateUser adds a user to a userlist, or replaces the password (and, when
+// user.Groups is non-nil, the groups) of an existing one.
+func CreateUser(userlist string, user UserManifest, opts userOptions) error {
+	if user.Name == "" {
+		return errors.New("user name is required")
+	}
+	if user.Password == "" {
+		return errors.New("--password or --password-stdin is required")
+	}
+	if opts.Hash && opts.Insecure {
+		return errors.New("--hash and --insecure-password are mutually exclusive")
+	}
+	plain := user.Password
+	if opts.Hash {
+		hash, err := HashPassword(plain)
+		if err != nil {
+			return err
+		}
+		user.Password = hash
+	}
+	payload := map[string]interface{}{
+		"username":        user.Name,
+		"password":        user.Password,
+		"secure_password": !opts.Insecure,
+	}
+	if len(user.Groups) > 0 {
+		payload["groups"] = strings.Join(user.Groups, ",")
+	}
+	id := userlist + "/" + user.Name
+
+	if opts.DryRun {
+		preview := copyMember(payload)
+		delete(preview, "password")
+		internal.FormatOutput(preview, internal.OutputFormatYAML)
+		internal.PrintDryRun()
+		return nil
+	}
+
+	users, err := listMembers(userlist, "users")
+	if err != nil {
+		return err
+	}
+	groups, err := listMembers(userlist, "groups")
+	if err != nil {
+		return err
+	}
+	for _, g := range user.Groups {
+		if findMember(groups, "name", g) == nil {
+			return fmt.Errorf("group %q does not exist in userlist %q", g, userlist)
+		}
+	}
+
+	existing := findMember(users, "username", user.Name)
+	if existing != nil && user.Groups == nil {
+		if current, ok := existing["groups"]; ok {
+			payload["groups"] = current
+		}
+	}
+	if existing != nil && opts.Hash {
+		// Keep the live hash when the password did not change.
+		if hash, _ := existing["password"].(string); PasswordMatchesHash(plain, hash) {
+			payload["password"] = hash
+		}
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if _, err := internal.SendRequest("PUT", membersPath(userlist, "users")+"/"+user.Name, params, payload); err != nil {
+			return internal.FormatAPIError("User", id, "update", err)
+		}
+		internal.PrintStatus("User", id, internal.ActionConfigured)
+		return nil
+	}
+	if _, err := internal.SendRequest("POST", membersPath(userlist, "users"), params, payload); err != nil {
+		return internal.FormatAPIError("User", id, "create", err)
+	}
+	internal.PrintStatus("User", id, internal.ActionCreated)
+	return nil
+}
+
+// DeleteUser removes a user and drops it from the member list of every
+// group, in one transaction.
+func DeleteUser(userlist, name string) error {
+	groups, err := listMembers(userlist, "groups")
+	if err != nil {
+		return err
+	}
+	id := userlist + "/" + name
+
+	err = internal.WithTransaction(func(tx *internal.Transaction) error {
+		for _, g := range groups {
+			members := splitNames(g["users"])
+			kept := removeName(members, name)
+			if len(kept) == len(members) {
+				continue
+			}
+			group := copyMember(g)
+			setNames(group, "users", kept)
+			groupName, _ := g["name"].(string)
+			if _, err := internal.SendRequest("PUT", membersPath(userlist, "groups")+"/"+groupName, tx.Params(), group); err != nil {
+				return fmt.Errorf("failed to remove %q from group %q: %w", name, groupName, err)
+			}
+		}
+		if _, err := internal.SendRequest("DELETE", membersPath(userlist, "users")+"/"+name, tx.Params(), nil); err != nil {
+			return internal.FormatAPIError("User", id, "delete", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	internal.PrintStatus("User", id, internal.ActionDeleted)
+	return nil
+}
+
+// removeName returns names without name.
+func removeName(names []string, name string) []string {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// setNames stores names as the comma-separated field the API expects,
+// dropping the field when there are none.
+func setNames(member map[string]interface{}, field string, names []string) {
+	if len(names) == 0 {
+		delete(member, field)
+		return
+	}
+	member[field] = strings.Join(names, ",")
+}
+
+// copyMember returns a shallow copy of a user or group object.
+func copyMember(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
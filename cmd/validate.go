@@ -0,0 +1,163 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"haproxyctl/cmd/acls"
+	"haproxyctl/cmd/backends"
+	"haproxyctl/cmd/caches"
+	"haproxyctl/cmd/configuration"
+	"haproxyctl/cmd/frontends"
+	"haproxyctl/cmd/httperrors"
+	"haproxyctl/cmd/httprules"
+	"haproxyctl/cmd/logforwards"
+	"haproxyctl/cmd/resolvers"
+	"haproxyctl/cmd/rings"
+	"haproxyctl/cmd/servers"
+	"haproxyctl/cmd/spoe"
+	"haproxyctl/cmd/userlists"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// validateCmd represents the "validate" command.
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check manifests without contacting the Data Plane API",
+	Long: `Check manifests the way apply does before it changes anything, without
+contacting the Data Plane API: apiVersion and kind, required fields, mode
+values, duration syntax, port ranges and duplicate server names or binds.
+-f accepts the same files, directories (-R) and URLs as apply.
+
+Every invalid document is reported, and the command exits with status 5 if
+there was any, which makes it suitable for CI. "haproxyctl apply --local"
+does the same. References to other objects (a default_backend, an ACL used
+by a rule) are not checked, since that needs the live configuration.
+
+Examples:
+  haproxyctl validate -f backend.yaml
+  haproxyctl validate -f ./manifests/ -R`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		file := internal.GetFlagString(cmd, "file")
+		if file == "" {
+			internal.FatalCodef(internal.ExitUsage, "validate requires -f/--file")
+		}
+		opts := manifestOptions{
+			Recursive: internal.GetFlagBool(cmd, "recursive"),
+			Selector:  internal.GetFlagString(cmd, "selector"),
+		}
+		if err := validateFromPath(file, opts); err != nil {
+			internal.Fatalf("%v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringP("file", "f", "", "Manifest file, directory, or http(s) URL to validate")
+	validateCmd.Flags().BoolP("recursive", "R", false, "Process the directory given with -f recursively")
+	validateCmd.Flags().StringP("selector", "l", "", "Only validate documents whose metadata.labels match, e.g. team=web")
+}
+
+// validateFromPath validates every manifest at path, printing each problem,
+// and fails when any manifest is invalid.
+func validateFromPath(path string, opts manifestOptions) error {
+	manifests, err := readManifests(path, opts)
+	if err != nil {
+		return err
+	}
+	if manifests, err = selectManifests(manifests, opts.Selector); err != nil {
+		return err
+	}
+
+	invalid := 0
+	for _, m := range manifests {
+		if err := validateManifest(m); err != nil {
+			invalid++
+			_, _ = fmt.Fprintf(os.Stdout, "%s: %v\n", m, err)
+		}
+	}
+	if invalid > 0 {
+		return internal.WithExitCode(internal.ExitInvalid, fmt.Errorf("%d of %d manifests invalid", invalid, len(manifests)))
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "%d manifests valid\n", len(manifests))
+	return nil
+}
+
+// validateManifest runs the offline checks of the manifest's kind.
+func validateManifest(m internal.Manifest) error {
+	if err := checkAPIVersion(m); err != nil {
+		return err
+	}
+
+	data := m.Data
+	switch strings.ToLower(m.Kind) {
+	case kindBackend:
+		return backends.ValidateBackendFromYAML(data)
+	case kindFrontend:
+		return frontends.ValidateFrontendFromYAML(data)
+	case kindServer:
+		return validateAs(data, (*servers.ServerConfig).Validate)
+	case kindGlobal:
+		return validateAs(data, (*configuration.GlobalConfig).Validate)
+	case kindDefaults:
+		return validateAs(data, (*configuration.DefaultsConfig).Validate)
+	case kindUserlist:
+		return userlists.ValidateUserlistFromYAML(data)
+	case kindResolver:
+		return validateAs(data, (*resolvers.ResolverManifest).Validate)
+	case kindCache:
+		return validateAs(data, (*caches.CacheManifest).Validate)
+	case kindHTTPErrors:
+		return validateAs(data, (*httperrors.HTTPErrorsManifest).Validate)
+	case kindRing:
+		return validateAs(data, (*rings.RingManifest).Validate)
+	case kindLogForward:
+		return validateAs(data, (*logforwards.LogForwardManifest).Validate)
+	case kindSPOE:
+		return validateAs(data, (*spoe.SPOEManifest).Validate)
+	case kindACL:
+		return validateAs(data, (*acls.ACLManifest).Validate)
+	case kindHTTPRequestRule, kindHTTPResponseRule:
+		return validateAs(data, func(m *httprules.RuleManifest) error {
+			_, err := m.Validate()
+			return err
+		})
+	case "":
+		return fmt.Errorf("kind is required")
+	default:
+		return fmt.Errorf("unsupported resource kind: %s (supported: Backend, Frontend, Server, Global, Defaults, Userlist, Resolver, Cache, HTTPErrors, Ring, LogForward, SPOE, ACL, HTTPRequestRule, HTTPResponseRule)", m.Kind)
+	}
+}
+
+// validateAs decodes data into a T and runs validate on it.
+func validateAs[T any](data []byte, validate func(*T) error) error {
+	var manifest T
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return validate(&manifest)
+}
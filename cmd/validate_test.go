@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"haproxyctl/internal"
+)
+
+const validManifests = `apiVersion: haproxyctl/v1
+kind: Backend
+name: app
+mode: http
+timeout_server: 30s
+servers:
+  - name: app1
+    address: 10.0.0.1
+    port: 8080
+  - name: app2
+    address: 10.0.0.2
+    port: 8080
+---
+apiVersion: haproxyctl/v1
+kind: Frontend
+name: web
+mode: http
+default_backend: app
+binds:
+  - address: 0.0.0.0
+    port: 80
+  - address: 0.0.0.0
+    port: 443
+`
+
+const invalidManifests = `apiVersion: haproxyctl/v1
+kind: Backend
+name: dup
+mode: http
+servers:
+  - name: app1
+    address: 10.0.0.1
+    port: 8080
+  - name: app1
+    address: 10.0.0.2
+    port: 8080
+---
+apiVersion: haproxyctl/v1
+kind: Backend
+name: slow
+mode: http
+timeout_server: 30 seconds
+---
+apiVersion: haproxyctl/v1
+kind: Frontend
+name: web
+mode: https
+---
+apiVersion: haproxyctl/v1
+kind: Frontend
+name: twice
+mode: tcp
+binds:
+  - address: 0.0.0.0
+    port: 443
+  - address: 0.0.0.0
+    port: 443
+---
+apiVersion: haproxyctl/v1
+kind: Server
+name: app3
+parent: app
+address: 10.0.0.3
+port: 70000
+---
+apiVersion: haproxyctl/v3
+kind: Cache
+name: static
+---
+apiVersion: haproxyctl/v1
+kind: Listener
+name: nope
+`
+
+func TestValidateFromPath(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(valid, []byte(validManifests), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte(invalidManifests), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		if err := validateFromPath(valid, manifestOptions{}); err != nil {
+			t.Fatalf("valid manifests rejected: %v", err)
+		}
+	})
+	if !strings.Contains(output, "2 manifests valid") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+
+	var err error
+	output = internal.CaptureStdout(t, func() {
+		err = validateFromPath(invalid, manifestOptions{})
+	})
+	if err == nil || !strings.Contains(err.Error(), "7 of 7 manifests invalid") {
+		t.Fatalf("expected every document to be rejected, got %v\n%s", err, output)
+	}
+	for _, want := range []string{
+		`duplicate server name "app1"`,
+		`timeout_server: invalid duration "30 seconds"`,
+		`invalid mode "https"`,
+		"duplicate bind 0.0.0.0:443",
+		"port 70000 out of range",
+		`unsupported apiVersion "haproxyctl/v3"`,
+		"unsupported resource kind: Listener",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not mention %q:\n%s", want, output)
+		}
+	}
+}
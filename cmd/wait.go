@@ -0,0 +1,55 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires top-level CLI commands for haproxyctl.
+package cmd
+
+import (
+	"time"
+
+	"haproxyctl/cmd/reloads"
+	"haproxyctl/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// reloadPollInterval is how often --wait polls the reload.
+var reloadPollInterval = time.Second
+
+// addWaitFlags adds --wait and --wait-timeout to c and its subcommands and
+// makes them wait for the reload of the command's last change.
+func addWaitFlags(c *cobra.Command) {
+	c.PersistentFlags().Bool("wait", false, "After the change, wait until HAProxy finished reloading with it (exit 1 if the reload fails)")
+	c.PersistentFlags().Duration("wait-timeout", time.Minute, "How long --wait waits for the reload")
+	c.PersistentPostRun = waitForLastReload
+}
+
+// waitForLastReload implements --wait. Commands only return after a
+// successful change, so it runs once everything has been sent; with no
+// reload to wait for (nothing changed, a dry run, or an API that reloads
+// synchronously) it returns at once.
+func waitForLastReload(cmd *cobra.Command, _ []string) {
+	if !internal.GetFlagBool(cmd, "wait") {
+		return
+	}
+	id := internal.LastReloadID()
+	if id == "" {
+		return
+	}
+	timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+
+	reloads.WaitAndReport(cmd.Context(), id, reloadPollInterval, timeout)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"haproxyctl/internal"
+	"haproxyctl/internal/testserver"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWaitForLastReload(t *testing.T) {
+	srv := testserver.New(t)
+	previous := reloadPollInterval
+	reloadPollInterval = time.Millisecond
+	t.Cleanup(func() { reloadPollInterval = previous })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	addWaitFlags(cmd)
+	if err := cmd.ParseFlags([]string{"--wait"}); err != nil {
+		t.Fatal(err)
+	}
+
+	params, err := internal.WriteParams(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := internal.SendRequest("POST", "/services/haproxy/configuration/backends", params, map[string]interface{}{"name": "app"}); err != nil {
+		t.Fatalf("create backend failed: %v", err)
+	}
+	id := internal.LastReloadID()
+	if id == "" || srv.ReloadCount() == 0 {
+		t.Fatalf("no reload recorded for the change (id %q)", id)
+	}
+
+	output := internal.CaptureStdout(t, func() {
+		waitForLastReload(cmd, nil)
+	})
+	if !strings.Contains(output, "reload/"+id+" succeeded") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+}
@@ -6,6 +6,7 @@ require (
 	github.com/manifoldco/promptui v0.9.0
 	github.com/spf13/cobra v1.9.1
 	github.com/spf13/viper v1.20.1
+	golang.org/x/crypto v0.32.0
 	golang.org/x/text v0.23.0
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,95 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"haproxyctl/pkg/client"
+)
+
+var (
+	tokenCacheMu sync.Mutex
+	// tokenCache holds the output of each token_command, which is run at
+	// most once per process.
+	tokenCache = map[string]string{}
+)
+
+// tokenSource returns how requests for cfg get a bearer token, or nil when
+// cfg uses basic auth. token_command takes precedence over token.
+func tokenSource(cfg Config) client.TokenSource {
+	switch {
+	case cfg.TokenCommand != "":
+		command := cfg.TokenCommand
+		return func(ctx context.Context) (string, error) {
+			return runTokenCommand(ctx, command)
+		}
+	case cfg.Token != "":
+		token := cfg.Token
+		return func(context.Context) (string, error) { return token, nil }
+	default:
+		return nil
+	}
+}
+
+// runTokenCommand runs command with sh -c and returns its trimmed stdout.
+func runTokenCommand(ctx context.Context, command string) (string, error) {
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+
+	if token, ok := tokenCache[command]; ok {
+		return token, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // the command comes from the user's own config file
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", errors.New("token command printed no token")
+	}
+	tokenCache[command] = token
+	return token, nil
+}
+
+// setAuth authenticates req for cfg with a bearer token or basic auth, for
+// requests that do not go through pkg/client.
+func setAuth(ctx context.Context, req *http.Request, cfg Config) error {
+	ts := tokenSource(cfg)
+	if ts == nil {
+		req.SetBasicAuth(cfg.Username, cfg.Password)
+		return nil
+	}
+	token, err := ts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get bearer token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
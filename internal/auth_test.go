@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendRequest_BearerToken(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("1"))
+	}))
+	defer srv.Close()
+
+	cfg := &Config{APIBaseURL: srv.URL, Token: "static"}
+	defer SetConfigOverride(cfg)()
+
+	if _, err := GetConfigurationVersion(); err != nil {
+		t.Fatalf("with token: %v", err)
+	}
+
+	cfg.TokenCommand = "echo from-command"
+	for range 2 {
+		if _, err := GetConfigurationVersion(); err != nil {
+			t.Fatalf("with token_command: %v", err)
+		}
+	}
+
+	cfg.TokenCommand = "exit 3"
+	if _, err := GetConfigurationVersion(); err == nil {
+		t.Fatalf("expected an error for a failing token command")
+	}
+
+	want := []string{"Bearer static", "Bearer from-command", "Bearer from-command"}
+	if len(got) != len(want) {
+		t.Fatalf("Authorization headers = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Authorization headers = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Package internal contains shared helpers for haproxyctl.
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Environment variables controlling HTTP record/replay.
+//
+//	HAPROXYCTL_CASSETTE=testdata/apply.json HAPROXYCTL_CASSETTE_MODE=record haproxyctl apply -f be.yaml
+//	HAPROXYCTL_CASSETTE=testdata/apply.json haproxyctl apply -f be.yaml
+const (
+	cassetteEnv     = "HAPROXYCTL_CASSETTE"
+	cassetteModeEnv = "HAPROXYCTL_CASSETTE_MODE"
+
+	cassetteModeRecord = "record"
+	cassetteModeReplay = "replay"
+)
+
+// replayAPIBaseURL is used when replaying without a config file, so CI does
+// not need to run `haproxyctl login` first.
+const replayAPIBaseURL = "http://cassette.invalid:5555"
+
+// cassetteInteraction is one recorded request/response pair. Only the
+// method, path and query are used for matching; hosts and credentials are
+// never written to disk.
+type cassetteInteraction struct {
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	RequestBody  string `json:"request_body,omitempty"` //nolint:tagliatelle // cassette file format
+	Status       int    `json:"status"`
+	ContentType  string `json:"content_type,omitempty"`  //nolint:tagliatelle // cassette file format
+	ResponseBody string `json:"response_body,omitempty"` //nolint:tagliatelle // cassette file format
+}
+
+type cassette struct {
+	path         string
+	mode         string
+	interactions []cassetteInteraction
+	used         []bool
+}
+
+var (
+	cassetteMu     sync.Mutex
+	activeCassette *cassette
+)
+
+// cassetteFromEnv returns the cassette selected by the environment, loading
+// it on first use (or when the variables change between tests). It returns
+// nil when record/replay is disabled.
+func cassetteFromEnv() (*cassette, error) {
+	path := os.Getenv(cassetteEnv)
+	if path == "" {
+		return nil, nil
+	}
+
+	mode := os.Getenv(cassetteModeEnv)
+	if mode == "" {
+		mode = cassetteModeReplay
+	}
+	if mode != cassetteModeRecord && mode != cassetteModeReplay {
+		return nil, fmt.Errorf("invalid %s %q (expected %q or %q)", cassetteModeEnv, mode, cassetteModeRecord, cassetteModeReplay)
+	}
+
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+
+	if activeCassette != nil && activeCassette.path == path && activeCassette.mode == mode {
+		return activeCassette, nil
+	}
+
+	c := &cassette{path: path, mode: mode}
+	if mode == cassetteModeReplay {
+		data, err := os.ReadFile(path) //nolint:gosec // cassette path is chosen by the user
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &c.interactions); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		c.used = make([]bool, len(c.interactions))
+	}
+
+	activeCassette = c
+	return c, nil
+}
+
+// isReplaying reports whether requests are served from a cassette.
+func isReplaying() bool {
+	c, err := cassetteFromEnv()
+	return err == nil && c != nil && c.mode == cassetteModeReplay
+}
+
+// cassetteKey identifies a request independent of host and query order.
+func cassetteKey(req *http.Request) string {
+	key := req.URL.Path
+	if q := req.URL.Query(); len(q) > 0 {
+		key += "?" + q.Encode()
+	}
+	return key
+}
+
+// newHTTPClient returns the client used by the request helpers, with the
+// TLS settings of cfg (see tlsTransport) and the request timeout (see
+// requestTimeout). When a cassette is configured, requests are recorded to
+// or replayed from it. Every call is logged at verbosity 2 and up (see
+// MaxVerbosity).
+func newHTTPClient(cfg Config) (*http.Client, error) {
+	transport, err := tlsTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := requestTimeout(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c, err := cassetteFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		transport = &cassetteTransport{cassette: c, next: transport}
+	}
+	return &http.Client{Transport: &loggingTransport{next: transport}, Timeout: timeout}, nil
+}
+
+type cassetteTransport struct {
+	cassette *cassette
+	next     http.RoundTripper
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cassette.mode == cassetteModeReplay {
+		return t.cassette.replay(req)
+	}
+	return t.cassette.record(req, t.next)
+}
+
+// replay returns the first unused interaction matching the request, so a
+// sequence of identical GETs (e.g. version lookups) replays in order.
+func (c *cassette) replay(req *http.Request) (*http.Response, error) {
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+
+	key := cassetteKey(req)
+	for i, in := range c.interactions {
+		if c.used[i] || in.Method != req.Method || in.URL != key {
+			continue
+		}
+		c.used[i] = true
+
+		header := make(http.Header)
+		if in.ContentType != "" {
+			header.Set("Content-Type", in.ContentType)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			StatusCode:    in.Status,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewBufferString(in.ResponseBody)),
+			ContentLength: int64(len(in.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no recorded interaction for %s %s", c.path, req.Method, key)
+}
+
+// record forwards the request and appends the exchange to the cassette. The
+// file is rewritten after every interaction so that commands exiting early
+// still leave a usable cassette behind.
+func (c *cassette) record(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body for recording: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body for recording: %w", err)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+
+	c.interactions = append(c.interactions, cassetteInteraction{
+		Method:       req.Method,
+		URL:          cassetteKey(req),
+		RequestBody:  string(reqBody),
+		Status:       resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ResponseBody: string(respBody),
+	})
+
+	if err := c.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *cassette) save() error {
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
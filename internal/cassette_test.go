@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRecordThenReplay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name":"web","mode":"http"}]`))
+	}))
+	restore := SetConfigOverride(&Config{APIBaseURL: srv.URL, Username: "admin", Password: "secret"})
+	t.Cleanup(restore)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	t.Setenv(cassetteEnv, path)
+	t.Setenv(cassetteModeEnv, cassetteModeRecord)
+
+	endpoint := "/services/haproxy/configuration/backends"
+	if _, err := GetResourceList(endpoint); err != nil {
+		t.Fatalf("record request failed: %v", err)
+	}
+	srv.Close()
+
+	t.Setenv(cassetteModeEnv, cassetteModeReplay)
+	list, err := GetResourceList(endpoint)
+	if err != nil {
+		t.Fatalf("replay request failed: %v", err)
+	}
+	if len(list) != 1 || list[0]["name"] != "web" {
+		t.Fatalf("unexpected replayed list: %+v", list)
+	}
+	if calls != 1 {
+		t.Fatalf("server saw %d calls, want 1", calls)
+	}
+
+	// Each interaction is replayed once; a second identical request has
+	// nothing left to match.
+	_, err = GetResourceList(endpoint)
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Fatalf("expected exhausted cassette error, got %v", err)
+	}
+}
+
+func TestCassetteKeyIgnoresQueryOrder(t *testing.T) {
+	t.Parallel()
+
+	a := httptest.NewRequest(http.MethodPost, "http://a:5555/v3/x?version=2&force_reload=true", nil)
+	b := httptest.NewRequest(http.MethodPost, "http://b:5555/v3/x?force_reload=true&version=2", nil)
+	if cassetteKey(a) != cassetteKey(b) {
+		t.Fatalf("keys differ: %q vs %q", cassetteKey(a), cassetteKey(b))
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+// clusterStateFile records, per Data Plane API URL, the node name of
+// endpoints that were last seen in cluster mode.
+const clusterStateFile = "cluster.json"
+
+// SetClusterNode remembers that the Data Plane API at apiURL runs in
+// cluster mode as node, or forgets it when node is empty. "get cluster"
+// and "cluster join/leave" keep it up to date, so other commands can name
+// the node that handled a change without asking the API again.
+func SetClusterNode(apiURL, node string) error {
+	nodes := map[string]string{}
+	if err := ReadStateFile(clusterStateFile, &nodes); err != nil {
+		return err
+	}
+	key := normalizeAPIBaseURL(apiURL)
+	if nodes[key] == node {
+		return nil
+	}
+	if node == "" {
+		delete(nodes, key)
+	} else {
+		nodes[key] = node
+	}
+	return WriteStateFile(clusterStateFile, nodes)
+}
+
+// ClusterNode returns the node name of the configured endpoint when it
+// was last seen in cluster mode, otherwise "".
+func ClusterNode() string {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.APIBaseURL == "" {
+		return ""
+	}
+	nodes := map[string]string{}
+	if err := ReadStateFile(clusterStateFile, &nodes); err != nil {
+		return ""
+	}
+	return nodes[normalizeAPIBaseURL(cfg.APIBaseURL)]
+}
+
+// clusterSuffix returns " (node <name>)" for a status line that reports a
+// change made through a cluster member, " (in transaction <id>)" for one
+// only staged in the active transaction, otherwise "".
+func clusterSuffix(action string) string {
+	if action == ActionUnchanged || !apiWrites.Load() {
+		return ""
+	}
+	if activeTransaction != "" {
+		return " (in transaction " + activeTransaction + ")"
+	}
+	if node := ClusterNode(); node != "" {
+		return " (node " + node + ")"
+	}
+	return ""
+}
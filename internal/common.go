@@ -17,10 +17,14 @@ limitations under the License.
 package internal
 
 import (
+	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -35,6 +39,43 @@ import (
 // OutputFormatYAML is the canonical YAML output format string.
 const OutputFormatYAML = "yaml"
 
+// OutputFormatName prints one kind/name identifier per line, e.g. backend/web.
+const OutputFormatName = "name"
+
+// OutputFormatWide is the table format that includes the extra columns of a
+// ColumnSet. For data without a ColumnSet it is the same as "table".
+const OutputFormatWide = "wide"
+
+// ColumnSet describes how a resource kind is listed: Default columns are
+// shown by the plain table, Wide columns are appended with "-o wide" and
+// Kind names the resource for "-o name".
+type ColumnSet struct {
+	Kind    string
+	Default []string
+	Wide    []string
+}
+
+// ColumnedData is data paired with the table columns to show for it. YAML and
+// JSON output render Data unchanged.
+type ColumnedData struct {
+	Data    interface{}
+	Columns ColumnSet
+}
+
+// WithColumns pairs data with the table columns to show for it.
+func WithColumns(data interface{}, columns ColumnSet) ColumnedData {
+	return ColumnedData{Data: data, Columns: columns}
+}
+
+// tableColumns returns the columns a table in outputFormat should show, or
+// nil to show every field.
+func (c ColumnedData) tableColumns(outputFormat string) []string {
+	if outputFormat == OutputFormatWide {
+		return append(append([]string{}, c.Columns.Default...), c.Columns.Wide...)
+	}
+	return c.Columns.Default
+}
+
 // LoadYAMLFile reads the content of a YAML file.
 func LoadYAMLFile(filepath string) ([]byte, error) {
 	data, err := os.ReadFile(filepath) //nolint:gosec // CLI intentionally reads user-specified manifest paths
@@ -46,6 +87,45 @@ func LoadYAMLFile(filepath string) ([]byte, error) {
 
 // FormatOutput prints structured data according to the requested output format.
 func FormatOutput(data interface{}, outputFormat string) {
+	formatOutputTo(os.Stdout, data, outputFormat, outputOptions{})
+}
+
+// outputOptions are the rendering switches set by command flags.
+type outputOptions struct {
+	// noHeaders omits the header lines of tables.
+	noHeaders bool
+}
+
+// formatOutputTo renders data in outputFormat to w.
+func formatOutputTo(w io.Writer, data interface{}, outputFormat string, opts outputOptions) {
+	var columns []string
+	var kind string
+	if c, ok := data.(ColumnedData); ok {
+		columns = c.tableColumns(outputFormat)
+		kind = c.Columns.Kind
+		data = c.Data
+	}
+
+	if outputFormat == OutputFormatName {
+		if err := printNames(w, data, kind); err != nil {
+			Fatalf("Failed to print names: %v", err)
+		}
+		return
+	}
+
+	if IsJSONPathFormat(outputFormat) {
+		if err := printJSONPath(w, data, outputFormat); err != nil {
+			Fatalf("Failed to render JSONPath output: %v", err)
+		}
+		return
+	}
+	if IsCustomColumnsFormat(outputFormat) {
+		if err := printCustomColumns(w, data, outputFormat, opts); err != nil {
+			Fatalf("Failed to render custom columns: %v", err)
+		}
+		return
+	}
+
 	// Normalize `[]map[string]interface{}` to `[]interface{}`.
 	if v, ok := data.([]map[string]interface{}); ok {
 		genericList := make([]interface{}, 0, len(v))
@@ -56,16 +136,16 @@ func FormatOutput(data interface{}, outputFormat string) {
 	}
 
 	// Handle default format.
-	if outputFormat == "table" || outputFormat == "" {
+	if outputFormat == "table" || outputFormat == "" || outputFormat == OutputFormatWide {
 		switch v := data.(type) {
 		case map[string]interface{}:
-			printTable([]interface{}{v}) // single object as table
+			printTable(w, []interface{}{v}, columns, opts) // single object as table
 			return
 		case []interface{}:
-			printTable(v) // list of objects as table
+			printTable(w, v, columns, opts) // list of objects as table
 			return
 		default:
-			log.Fatalf("Cannot print table for this data type: %T", v)
+			Fatalf("Cannot print table for this data type: %T", v)
 		}
 	}
 
@@ -74,23 +154,23 @@ func FormatOutput(data interface{}, outputFormat string) {
 	case OutputFormatYAML:
 		yamlOutput, err := yaml.Marshal(data)
 		if err != nil {
-			log.Fatalf("Failed to generate YAML: %v", err)
+			Fatalf("Failed to generate YAML: %v", err)
 		}
-		if _, err := fmt.Fprintln(os.Stdout, string(yamlOutput)); err != nil {
-			log.Printf("warning: failed to write YAML output: %v", err)
+		if _, err := fmt.Fprintln(w, string(yamlOutput)); err != nil {
+			Warnf("failed to write YAML output: %v", err)
 		}
 
 	case "json":
 		jsonOutput, err := json.MarshalIndent(data, "", "    ")
 		if err != nil {
-			log.Fatalf("Failed to generate JSON: %v", err)
+			Fatalf("Failed to generate JSON: %v", err)
 		}
-		if _, err := fmt.Fprintln(os.Stdout, string(jsonOutput)); err != nil {
-			log.Printf("warning: failed to write JSON output: %v", err)
+		if _, err := fmt.Fprintln(w, string(jsonOutput)); err != nil {
+			Warnf("failed to write JSON output: %v", err)
 		}
 
 	default:
-		log.Fatalf("Invalid output format: %s. Supported formats: yaml, json, table", outputFormat)
+		FatalCodef(ExitUsage, "Invalid output format: %s. Supported formats: yaml, json, name, table, wide, jsonpath=<template>, custom-columns=<spec>", outputFormat)
 	}
 }
 
@@ -101,7 +181,7 @@ func ConvertMapToTyped(input map[string]string, intFields []string) map[string]i
 		if Contains(intFields, k) {
 			intVal, err := strconv.Atoi(v)
 			if err != nil {
-				log.Fatalf("Failed to convert %s to integer: %v", k, err)
+				Fatalf("Failed to convert %s to integer: %v", k, err)
 			}
 			result[k] = intVal
 		} else {
@@ -111,6 +191,21 @@ func ConvertMapToTyped(input map[string]string, intFields []string) map[string]i
 	return result
 }
 
+// GetIntField extracts an integer field from a generic map where numbers
+// are typically float64 from JSON decoding.
+func GetIntField(obj map[string]interface{}, key string) (int, bool) {
+	switch n := obj[key].(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
+
 // SortByStringField sorts a slice of map[string]interface{} in place by the
 // given string field. Missing or non-string fields are treated as empty
 // strings, so they appear first but deterministically.
@@ -122,115 +217,94 @@ func SortByStringField(list []map[string]interface{}, field string) {
 	})
 }
 
-// PrintResourceDescription prints structured details for a resource (backend, frontend, etc.).
-func PrintResourceDescription(resourceType string, resource map[string]interface{}, sections map[string][]string, servers []map[string]interface{}) {
-	if _, err := fmt.Fprintf(os.Stdout, "%s: %s\n", resourceType, resource["name"]); err != nil {
-		log.Printf("warning: failed to write resource header: %v", err)
+// SortByPath sorts list in place by the value at a field path such as
+// ".name" or "{.default_server.check}". A leading "-" sorts in descending
+// order. Numbers compare numerically and everything else as text; items
+// without the field always sort last. The sort is stable, so items with equal
+// values keep their previous (e.g. name) order.
+func SortByPath(list []map[string]interface{}, spec string) error {
+	descending := strings.HasPrefix(spec, "-")
+	expr := strings.TrimPrefix(spec, "-")
+	expr = strings.TrimSuffix(strings.TrimPrefix(expr, "{"), "}")
+	if expr == "" {
+		return errors.New("sort-by: empty field path")
+	}
+	path, err := parseJSONPath(expr)
+	if err != nil {
+		return err
 	}
 
-	if basics, exists := sections["basic"]; exists {
-		for _, field := range basics {
-			if value, ok := resource[field]; ok && value != "" {
-				if _, err := fmt.Fprintf(os.Stdout, "%s: %v\n", formatFieldName(field), value); err != nil {
-					log.Printf("warning: failed to write basic field: %v", err)
-				}
-			}
+	keys := make(map[int]interface{}, len(list))
+	indexed := make([]int, len(list))
+	for i, item := range list {
+		indexed[i] = i
+		if values := evalJSONPath(path, item, item); len(values) > 0 {
+			keys[i] = values[0]
 		}
 	}
 
-	for sectionName, fields := range sections {
-		if sectionName == "basic" {
-			continue
+	sort.SliceStable(indexed, func(a, b int) bool {
+		ka, okA := keys[indexed[a]]
+		kb, okB := keys[indexed[b]]
+		if !okA || !okB {
+			return okA && !okB
 		}
-		if _, err := fmt.Fprintf(os.Stdout, "\n%s:\n", cases.Title(language.English).String(sectionName)); err != nil {
-			log.Printf("warning: failed to write section header: %v", err)
-		}
-		for _, field := range fields {
-			if value, ok := resource[field]; ok && value != "" {
-				if _, err := fmt.Fprintf(os.Stdout, "- %s: %v\n", formatFieldName(field), value); err != nil {
-					log.Printf("warning: failed to write section field: %v", err)
-				}
-			}
+		c := compareSortKeys(ka, kb)
+		if descending {
+			return c > 0
 		}
+		return c < 0
+	})
+
+	sorted := make([]map[string]interface{}, len(list))
+	for i, idx := range indexed {
+		sorted[i] = list[idx]
 	}
+	copy(list, sorted)
+	return nil
+}
 
-	if len(servers) > 0 {
-		if _, err := fmt.Fprintln(os.Stdout, "\nServers:"); err != nil {
-			log.Printf("warning: failed to write servers header: %v", err)
-		}
-		const (
-			tabWidth   = 8
-			tabPadding = 2
-		)
-
-		w := tabwriter.NewWriter(os.Stdout, 0, tabWidth, tabPadding, ' ', 0)
-		if _, err := fmt.Fprintf(w, "NAME\tADDRESS\tPORT\tWEIGHT\n"); err != nil {
-			log.Printf("warning: failed to write servers header: %v", err)
-		}
-		if _, err := fmt.Fprintf(w, "----\t-------\t----\t------\n"); err != nil {
-			log.Printf("warning: failed to write servers separator: %v", err)
-		}
-		for _, server := range servers {
-			if _, err := fmt.Fprintf(w, "%s\t%s\t%v\t%v\n",
-				server["name"],
-				server["address"],
-				server["port"],
-				server["weight"],
-			); err != nil {
-				log.Printf("warning: failed to write server row: %v", err)
-			}
-		}
-		if err := w.Flush(); err != nil {
-			log.Printf("warning: failed to flush servers table: %v", err)
-		}
+// compareSortKeys compares two field values for SortByPath.
+func compareSortKeys(a, b interface{}) int {
+	fa, errA := strconv.ParseFloat(fmt.Sprint(a), 64)
+	fb, errB := strconv.ParseFloat(fmt.Sprint(b), 64)
+	if errA == nil && errB == nil {
+		return cmp.Compare(fa, fb)
 	}
+	return strings.Compare(formatValue(a), formatValue(b))
 }
 
-// printTable formats structured data into a clean table like kubectl.
-func printTable(data []interface{}) {
+// printTable formats structured data into a clean table like kubectl. When
+// columns is empty, every field of the first row becomes a column.
+func printTable(out io.Writer, data []interface{}, columns []string, opts outputOptions) {
 	if len(data) == 0 {
-		if _, err := fmt.Fprintln(os.Stdout, "No resources found."); err != nil {
-			log.Printf("warning: failed to write empty-table message: %v", err)
+		if _, err := fmt.Fprintln(out, "No resources found."); err != nil {
+			Warnf("failed to write empty-table message: %v", err)
 		}
 		return
 	}
 
 	firstRow, ok := data[0].(map[string]interface{})
 	if !ok {
-		if _, err := fmt.Fprintln(os.Stdout, "Invalid data format."); err != nil {
-			log.Printf("warning: failed to write invalid-data message: %v", err)
+		if _, err := fmt.Fprintln(out, "Invalid data format."); err != nil {
+			Warnf("failed to write invalid-data message: %v", err)
 		}
 		return
 	}
 
-	headers := getSortedKeys(firstRow)
+	headers := columns
+	if len(headers) == 0 {
+		headers = getSortedKeys(firstRow)
+	}
 
 	const (
 		printTabWidth   = 8
 		printTabPadding = 2
 	)
 
-	w := tabwriter.NewWriter(os.Stdout, 0, printTabWidth, printTabPadding, ' ', 0)
+	w := tabwriter.NewWriter(out, 0, printTabWidth, printTabPadding, ' ', 0)
 
-	for _, key := range headers {
-		if _, err := fmt.Fprintf(w, "%s\t", strings.ToUpper(key)); err != nil {
-			log.Printf("warning: failed to write table header: %v", err)
-			return
-		}
-	}
-	if _, err := fmt.Fprintln(w); err != nil {
-		log.Printf("warning: failed to terminate header line: %v", err)
-		return
-	}
-
-	for range headers {
-		if _, err := fmt.Fprintf(w, "--------\t"); err != nil {
-			log.Printf("warning: failed to write header separator: %v", err)
-			return
-		}
-	}
-	if _, err := fmt.Fprintln(w); err != nil {
-		log.Printf("warning: failed to terminate separator line: %v", err)
+	if !opts.noHeaders && !writeTableHeader(w, headers) {
 		return
 	}
 
@@ -241,19 +315,142 @@ func printTable(data []interface{}) {
 		}
 		for _, key := range headers {
 			if _, err := fmt.Fprintf(w, "%v\t", formatValue(rowMap[key])); err != nil {
-				log.Printf("warning: failed to write table value: %v", err)
+				Warnf("failed to write table value: %v", err)
 				return
 			}
 		}
 		if _, err := fmt.Fprintln(w); err != nil {
-			log.Printf("warning: failed to terminate row line: %v", err)
+			Warnf("failed to terminate row line: %v", err)
 			return
 		}
 	}
 
 	if err := w.Flush(); err != nil {
-		log.Printf("warning: failed to flush table: %v", err)
+		Warnf("failed to flush table: %v", err)
+	}
+}
+
+// writeTableHeader writes the upper-cased header line and its separator. It
+// reports whether writing succeeded.
+func writeTableHeader(w io.Writer, headers []string) bool {
+	for _, key := range headers {
+		if _, err := fmt.Fprintf(w, "%s\t", strings.ToUpper(key)); err != nil {
+			Warnf("failed to write table header: %v", err)
+			return false
+		}
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		Warnf("failed to terminate header line: %v", err)
+		return false
+	}
+
+	for range headers {
+		if _, err := fmt.Fprintf(w, "--------\t"); err != nil {
+			Warnf("failed to write header separator: %v", err)
+			return false
+		}
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		Warnf("failed to terminate separator line: %v", err)
+		return false
+	}
+	return true
+}
+
+// customColumnsPrefix introduces a column spec in an output format, as in
+// -o custom-columns=NAME:.name,MODE:.mode.
+const customColumnsPrefix = "custom-columns="
+
+// customColumnNone is printed for a column whose path selects nothing.
+const customColumnNone = "<none>"
+
+// IsCustomColumnsFormat reports whether outputFormat is a
+// custom-columns=<spec> format.
+func IsCustomColumnsFormat(outputFormat string) bool {
+	return strings.HasPrefix(outputFormat, customColumnsPrefix)
+}
+
+type customColumn struct {
+	header string
+	path   []jsonPathStep
+}
+
+// parseCustomColumns parses "HEADER:path,..." where each path is a JSONPath
+// expression relative to one row, with or without surrounding braces.
+func parseCustomColumns(spec string) ([]customColumn, error) {
+	if spec == "" {
+		return nil, errors.New("custom-columns: no columns given")
+	}
+	var columns []customColumn
+	for _, part := range strings.Split(spec, ",") {
+		header, expr, ok := strings.Cut(part, ":")
+		if !ok || header == "" || expr == "" {
+			return nil, fmt.Errorf("custom-columns: expected HEADER:path, got %q", part)
+		}
+		expr = strings.TrimSuffix(strings.TrimPrefix(expr, "{"), "}")
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, customColumn{header: header, path: path})
 	}
+	return columns, nil
+}
+
+// printCustomColumns prints one row per item of a list (or a single row for
+// an object) with the columns of a custom-columns=<spec> output format.
+func printCustomColumns(out io.Writer, data interface{}, outputFormat string, opts outputOptions) error {
+	columns, err := parseCustomColumns(strings.TrimPrefix(outputFormat, customColumnsPrefix))
+	if err != nil {
+		return err
+	}
+	root, err := jsonPathData(data)
+	if err != nil {
+		return err
+	}
+	rows := []interface{}{root}
+	if m, ok := root.(map[string]interface{}); ok && m["kind"] == ListKind {
+		rows, _ = m["items"].([]interface{})
+	}
+
+	const (
+		printTabWidth   = 8
+		printTabPadding = 2
+	)
+	w := tabwriter.NewWriter(out, 0, printTabWidth, printTabPadding, ' ', 0)
+
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, column.header)
+	}
+	if !opts.noHeaders {
+		if _, err := fmt.Fprintln(w, strings.Join(headers, "\t")); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		cells := make([]string, 0, len(columns))
+		for _, column := range columns {
+			var values []string
+			for _, v := range evalJSONPath(column.path, root, row) {
+				s, err := formatJSONPathValue(v)
+				if err != nil {
+					return err
+				}
+				values = append(values, s)
+			}
+			cell := strings.Join(values, ",")
+			if len(values) == 0 {
+				cell = customColumnNone
+			}
+			cells = append(cells, cell)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
 }
 
 // getSortedKeys ensures "NAME" or similar fields appear first, with the rest sorted alphabetically.
@@ -368,6 +565,29 @@ func ParseDurationToMillis(s string) (int, error) {
 	return int(d / time.Millisecond), nil
 }
 
+// ValidateDurations checks that every non-empty value of fields, keyed by
+// manifest field name, is a duration ParseDurationToMillis accepts. Fields
+// are checked in name order so the reported one is stable.
+func ValidateDurations(fields map[string]string) error {
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		if _, err := ParseDurationToMillis(fields[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ValidatePort checks that port is a usable TCP/UDP port number.
+func ValidatePort(port int) error {
+	if port < 1 || port > maxPort {
+		return fmt.Errorf("port %d out of range (1-%d)", port, maxPort)
+	}
+	return nil
+}
+
+// maxPort is the highest TCP/UDP port number.
+const maxPort = 65535
+
 // FormatMillisAsDuration renders a millisecond value as a human‑readable
 // duration string (e.g. 30000 -> "30s"). A zero value returns an empty
 // string so omitted timeouts remain omitted in manifests.
@@ -380,8 +600,9 @@ func FormatMillisAsDuration(ms int) string {
 }
 
 // ManifestList represents a generic list of manifest-style resources,
-// similar to the Kubernetes List type. It is used only for structured
-// YAML/JSON output (e.g. get ... -o yaml) and is not used in table mode.
+// similar to the Kubernetes List type. It is written by structured
+// YAML/JSON output (e.g. get ... -o yaml), not in table mode, and read
+// back by ParseManifests.
 type ManifestList struct {
 	APIVersion string        `json:"apiVersion" yaml:"apiVersion"`
 	Kind       string        `json:"kind" yaml:"kind"`
@@ -141,3 +141,133 @@ func TestFormatOutput_TableAndYAML(t *testing.T) {
 		t.Fatalf("expected YAML manifest list output, got:\n%s", output)
 	}
 }
+
+func TestFormatOutput_WideColumns(t *testing.T) {
+	t.Parallel()
+
+	rows := []map[string]interface{}{{
+		"name":            "app",
+		"mode":            "http",
+		"connect_timeout": float64(5000),
+		"description":     "not a column",
+	}}
+	data := WithColumns(rows, ColumnSet{
+		Default: []string{"name", "mode"},
+		Wide:    []string{"connect_timeout"},
+	})
+
+	var table, wide, yamlOut strings.Builder
+	formatOutputTo(&table, data, "", outputOptions{})
+	formatOutputTo(&wide, data, OutputFormatWide, outputOptions{})
+	formatOutputTo(&yamlOut, data, OutputFormatYAML, outputOptions{})
+
+	if strings.Contains(table.String(), "CONNECT_TIMEOUT") || strings.Contains(table.String(), "DESCRIPTION") {
+		t.Fatalf("default table should only show default columns, got:\n%s", table.String())
+	}
+	if !strings.Contains(wide.String(), "CONNECT_TIMEOUT") || !strings.Contains(wide.String(), "5000") {
+		t.Fatalf("wide table should show the extra columns, got:\n%s", wide.String())
+	}
+	if strings.Contains(wide.String(), "DESCRIPTION") {
+		t.Fatalf("wide table should not show unlisted fields, got:\n%s", wide.String())
+	}
+	if !strings.Contains(yamlOut.String(), "description: not a column") {
+		t.Fatalf("YAML output should render the data unchanged, got:\n%s", yamlOut.String())
+	}
+}
+
+func TestFormatOutput_CustomColumns(t *testing.T) {
+	t.Parallel()
+
+	frontends := []map[string]interface{}{
+		{"name": "web", "mode": "http", "default_backend": "app"},
+		{"name": "tcp-in", "mode": "tcp"},
+	}
+
+	var out strings.Builder
+	formatOutputTo(&out, frontends, "custom-columns=NAME:.name,MODE:.mode,BACKEND:{.default_backend}", outputOptions{})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two rows, got:\n%s", out.String())
+	}
+	if strings.Join(strings.Fields(lines[0]), " ") != "NAME MODE BACKEND" {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if strings.Join(strings.Fields(lines[1]), " ") != "web http app" {
+		t.Fatalf("unexpected row %q", lines[1])
+	}
+	if strings.Join(strings.Fields(lines[2]), " ") != "tcp-in tcp <none>" {
+		t.Fatalf("unexpected row %q", lines[2])
+	}
+}
+
+func TestParseCustomColumns_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{"", "NAME", "NAME:", ":.name", "NAME:.items[x]"} {
+		if _, err := parseCustomColumns(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestSortByPath(t *testing.T) {
+	t.Parallel()
+
+	newList := func() []map[string]interface{} {
+		return []map[string]interface{}{
+			{"name": "a", "weight": float64(10), "check": map[string]interface{}{"inter": "2s"}},
+			{"name": "b"},
+			{"name": "c", "weight": float64(2), "check": map[string]interface{}{"inter": "1s"}},
+			{"name": "d", "weight": float64(10)},
+		}
+	}
+	names := func(list []map[string]interface{}) string {
+		var out []string
+		for _, item := range list {
+			out = append(out, item["name"].(string))
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{spec: ".weight", want: "c,a,d,b"},
+		{spec: "-.weight", want: "a,d,c,b"},
+		{spec: "{.check.inter}", want: "c,a,b,d"},
+		{spec: "-name", want: "d,c,b,a"},
+	}
+	for _, tt := range tests {
+		list := newList()
+		if err := SortByPath(list, tt.spec); err != nil {
+			t.Fatalf("SortByPath(%q) error: %v", tt.spec, err)
+		}
+		if got := names(list); got != tt.want {
+			t.Fatalf("SortByPath(%q) = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "-", ".items[x]"} {
+		if err := SortByPath(newList(), spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestGetIntField(t *testing.T) {
+	t.Parallel()
+
+	obj := map[string]interface{}{"json": float64(80), "int": 3, "int64": int64(7), "text": "80", "null": nil}
+	for key, want := range map[string]int{"json": 80, "int": 3, "int64": 7} {
+		if got, ok := GetIntField(obj, key); !ok || got != want {
+			t.Errorf("GetIntField(%q) = %d, %v, want %d", key, got, ok, want)
+		}
+	}
+	for _, key := range []string{"text", "null", "missing"} {
+		if _, ok := GetIntField(obj, key); ok {
+			t.Errorf("GetIntField(%q) reported a number", key)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is how many sub-requests (servers, binds, rules of
+// each listed object) are in flight at once unless --concurrency says
+// otherwise.
+const DefaultConcurrency = 8
+
+// concurrency is set once per process from the global --concurrency flag.
+var concurrency = DefaultConcurrency
+
+// SetConcurrency sets how many sub-requests ForEachConcurrent runs at
+// once. Values below 1 mean 1, one request at a time.
+func SetConcurrency(n int) {
+	concurrency = max(n, 1)
+}
+
+// ForEachConcurrent calls fn for every index in [0, n), with at most
+// SetConcurrency calls running at once. fn must only write to the i-th
+// element of whatever it fills in. After the first error the context
+// passed to fn is cancelled, no further calls start, and that error is
+// returned.
+func ForEachConcurrent(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr == nil {
+		return ctx.Err()
+	}
+	return firstErr
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEachConcurrent(t *testing.T) {
+	SetConcurrency(3)
+	t.Cleanup(func() { SetConcurrency(DefaultConcurrency) })
+
+	var running, peak atomic.Int32
+	results := make([]int, 20)
+	err := ForEachConcurrent(context.Background(), len(results), func(_ context.Context, i int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		results[i] = i * i
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Fatalf("peak concurrency = %d, want at most 3 (and some overlap)", p)
+	}
+	for i, v := range results {
+		if v != i*i {
+			t.Fatalf("results[%d] = %d", i, v)
+		}
+	}
+
+	// The first error cancels the rest.
+	boom := errors.New("boom")
+	var started atomic.Int32
+	err = ForEachConcurrent(context.Background(), 100, func(ctx context.Context, i int) error {
+		started.Add(1)
+		if i == 0 {
+			return boom
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Error("context not cancelled after the first error")
+		}
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if n := started.Load(); n > 10 {
+		t.Fatalf("%d calls started after the first error", n)
+	}
+}
@@ -19,9 +19,12 @@ package internal
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
+	"sort"
 )
 
 // Config holds HAProxy Data Plane API connection details.
@@ -33,10 +36,45 @@ import (
 //	  "username": "admin",
 //	  "password": "secret"
 //	}
+//
+// With "keyring_account" set and no "password", the password is read from
+// the system keyring (see login --keyring).
+//
+// Instead of a username and password, an endpoint behind an OAuth proxy
+// can set "token" (a static bearer token) or "token_command" (a shell
+// command printing one).
+//
+// An endpoint behind TLS with a private CA can also set
+// "certificate_authority" (a PEM bundle), "client_certificate" and
+// "client_key" for mutual TLS, or "insecure_skip_tls_verify".
+//
+// "timeout" bounds each request to the endpoint (default 30s, see
+// DefaultRequestTimeout), and "retries" retries requests failing with a
+// transient error such as 503 (see SetTransientRetries).
+//
+// Additional endpoints can be listed under "contexts", keyed by name, with
+// the same fields, like contexts in a kubeconfig. Every command talks to the
+// context chosen with the global --context flag, else HAPROXYCTL_CONTEXT,
+// else "current_context", else the top-level endpoint.
 type Config struct {
-	APIBaseURL string `json:"api_base_url"` //nolint:tagliatelle // must match config JSON format
-	Username   string `json:"username"`
-	Password   string `json:"password"`
+	APIBaseURL     string            `json:"api_base_url"` //nolint:tagliatelle // must match config JSON format
+	Username       string            `json:"username"`
+	Password       string            `json:"password"`
+	CurrentContext string            `json:"current_context,omitempty"` //nolint:tagliatelle // must match config JSON format
+	Contexts       map[string]Config `json:"contexts,omitempty"`
+
+	KeyringAccount string `json:"keyring_account,omitempty"` //nolint:tagliatelle // must match config JSON format
+
+	Token        string `json:"token,omitempty"`
+	TokenCommand string `json:"token_command,omitempty"` //nolint:tagliatelle // must match config JSON format
+
+	CertificateAuthority  string `json:"certificate_authority,omitempty"`    //nolint:tagliatelle // must match config JSON format
+	ClientCertificate     string `json:"client_certificate,omitempty"`       //nolint:tagliatelle // must match config JSON format
+	ClientKey             string `json:"client_key,omitempty"`               //nolint:tagliatelle // must match config JSON format
+	InsecureSkipTLSVerify bool   `json:"insecure_skip_tls_verify,omitempty"` //nolint:tagliatelle // must match config JSON format
+
+	Timeout string `json:"timeout,omitempty"`
+	Retries int    `json:"retries,omitempty"`
 }
 
 // Default config file path.
@@ -47,11 +85,151 @@ func init() {
 	configFilePath = filepath.Join(usr.HomeDir, ".config", "haproxyctl", "config.json")
 }
 
-// LoadConfig loads API configuration from ~/.config/haproxyctl/config.json.
+// configOverride, when set, replaces the on-disk configuration. Tests use it
+// (see internal/testserver) to point the request helpers at a fake API.
+var configOverride *Config
+
+// SetConfigOverride makes LoadConfig return cfg instead of reading the
+// config file. Passing nil restores the default behaviour. It returns a
+// function that restores the previous override.
+func SetConfigOverride(cfg *Config) func() {
+	previous := configOverride
+	configOverride = cfg
+	resetSession()
+	return func() {
+		configOverride = previous
+		resetSession()
+	}
+}
+
+// activeContext is set once per process from the global --context flag.
+var activeContext string
+
+// SetContext selects the named context for every request of this process.
+// An empty name falls back to HAPROXYCTL_CONTEXT, then current_context.
+func SetContext(name string) {
+	activeContext = name
+	resetSession()
+}
+
+// Environment variables that override the config file, e.g. in CI jobs and
+// containers that have no config file.
+const (
+	EnvAPIURL   = "HAPROXYCTL_API_URL"
+	EnvUsername = "HAPROXYCTL_USERNAME"
+	EnvPassword = "HAPROXYCTL_PASSWORD"
+	EnvContext  = "HAPROXYCTL_CONTEXT"
+)
+
+// LoadConfig returns the connection details of the active context (see
+// SetContext), with the HAPROXYCTL_API_URL, HAPROXYCTL_USERNAME and
+// HAPROXYCTL_PASSWORD environment variables and then the connection flags
+// (see SetConnectionOptions) taking precedence. With HAPROXYCTL_API_URL or
+// --api-url set, the config file is optional. A config override
+// is an already resolved endpoint and is returned as is.
 func LoadConfig() (Config, error) {
+	if configOverride != nil {
+		return *configOverride, nil
+	}
+	return ResolveContext("")
+}
+
+// ConnectionOptions are the endpoint and credentials given as global flags
+// (--api-url, --api-username, --api-password). Set fields take precedence over the
+// active context and the environment variables, for one invocation.
+type ConnectionOptions struct {
+	APIURL   string
+	Username string
+	Password string
+}
+
+var connectionOverrides ConnectionOptions
+
+// SetConnectionOptions sets the connection flag values for every request of
+// this process.
+func SetConnectionOptions(opts ConnectionOptions) {
+	connectionOverrides = opts
+	resetSession()
+}
+
+// withFlags applies the connection flags. Credentials given on the command
+// line mean basic auth, so they also drop the token of the context.
+func (c Config) withFlags() Config {
+	if connectionOverrides.APIURL != "" {
+		c.APIBaseURL = connectionOverrides.APIURL
+	}
+	if connectionOverrides.Username != "" || connectionOverrides.Password != "" {
+		c.Token = ""
+		c.TokenCommand = ""
+	}
+	if connectionOverrides.Username != "" {
+		c.Username = connectionOverrides.Username
+	}
+	if connectionOverrides.Password != "" {
+		c.Password = connectionOverrides.Password
+	}
+	return c
+}
+
+// withEnv applies the endpoint and credential environment variables.
+func (c Config) withEnv() Config {
+	if v := os.Getenv(EnvAPIURL); v != "" {
+		c.APIBaseURL = v
+	}
+	if v := os.Getenv(EnvUsername); v != "" {
+		c.Username = v
+	}
+	if v := os.Getenv(EnvPassword); v != "" {
+		c.Password = v
+	}
+	return c
+}
+
+// SelectedContext returns the context name commands use: --context, else
+// HAPROXYCTL_CONTEXT, else current_context, else "" for the top-level
+// endpoint.
+func (c Config) SelectedContext() string {
+	if activeContext != "" {
+		return activeContext
+	}
+	if env := os.Getenv(EnvContext); env != "" {
+		return env
+	}
+	return c.CurrentContext
+}
+
+// Context returns the connection details of the named context, with its
+// own context fields cleared. An empty name selects the top-level endpoint.
+func (c Config) Context(name string) (Config, error) {
+	if name == "" {
+		c.Contexts = nil
+		c.CurrentContext = ""
+		return c, nil
+	}
+	ctxCfg, ok := c.Contexts[name]
+	if !ok {
+		return Config{}, fmt.Errorf("context %q not found in config file", name)
+	}
+	ctxCfg.Contexts = nil
+	ctxCfg.CurrentContext = ""
+	return ctxCfg, nil
+}
+
+// LoadConfigFile loads ~/.config/haproxyctl/config.json as a whole, with
+// every context, e.g. to modify and save it again.
+func LoadConfigFile() (Config, error) {
+	if configOverride != nil {
+		return *configOverride, nil
+	}
+
 	var cfg Config
 	file, err := os.ReadFile(configFilePath) //nolint:gosec // configFilePath is a fixed path under the user's home dir
 	if err != nil {
+		// Replaying a cassette never reaches the network, so a missing
+		// config (e.g. in CI) is not an error.
+		if isReplaying() {
+			return Config{APIBaseURL: replayAPIBaseURL}, nil
+		}
 		return cfg, fmt.Errorf("failed to read config file: %w", err)
 	}
 	err = json.Unmarshal(file, &cfg)
@@ -60,3 +238,161 @@ func LoadConfig() (Config, error) {
 	}
 	return cfg, nil
 }
+
+// ResolveContext returns the connection details of the named context. An
+// empty name selects the active context with the environment overrides
+// applied, as LoadConfig does.
+func ResolveContext(name string) (Config, error) {
+	active := name == ""
+	cfg, err := LoadConfigFile()
+	if err != nil {
+		if !active || (os.Getenv(EnvAPIURL) == "" && connectionOverrides.APIURL == "") {
+			return cfg, err
+		}
+		cfg = Config{}
+	}
+	if active {
+		name = cfg.SelectedContext()
+	}
+	ctxCfg, err := cfg.Context(name)
+	if err != nil {
+		return ctxCfg, err
+	}
+	if active {
+		ctxCfg = ctxCfg.withEnv().withFlags()
+	}
+	return ctxCfg.withKeyringPassword()
+}
+
+// SaveConfig writes cfg to ~/.config/haproxyctl/config.json, readable only
+// by the current user. With a config override in place the override is
+// updated instead, so tests never touch the real file.
+func SaveConfig(cfg Config) error {
+	defer resetSession()
+	if configOverride != nil {
+		*configOverride = cfg
+		return nil
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configFilePath), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(configFilePath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// DefaultContextName is how the top-level endpoint is listed and selected
+// by the config context commands. It cannot be used as a context name.
+const DefaultContextName = "default"
+
+// ContextNames returns the named contexts, sorted.
+func (c Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UseContext makes name the current_context. DefaultContextName (or "")
+// selects the top-level endpoint again.
+func (c *Config) UseContext(name string) error {
+	if name == "" || name == DefaultContextName {
+		c.CurrentContext = ""
+		return nil
+	}
+	if _, ok := c.Contexts[name]; !ok {
+		return fmt.Errorf("context %q not found in config file", name)
+	}
+	c.CurrentContext = name
+	return nil
+}
+
+// SetContextEndpoint creates or updates the named context. Empty fields of
+// endpoint keep the context's existing values. It reports whether the
+// context was created.
+func (c *Config) SetContextEndpoint(name string, endpoint Config) (bool, error) {
+	if name == "" || name == DefaultContextName {
+		return false, fmt.Errorf("context name %q is reserved for the top-level endpoint", DefaultContextName)
+	}
+	if c.Contexts == nil {
+		c.Contexts = map[string]Config{}
+	}
+	ctxCfg, exists := c.Contexts[name]
+	if endpoint.APIBaseURL != "" {
+		ctxCfg.APIBaseURL = endpoint.APIBaseURL
+	}
+	if endpoint.Username != "" {
+		ctxCfg.Username = endpoint.Username
+	}
+	if endpoint.Password != "" {
+		ctxCfg.Password = endpoint.Password
+	}
+	if ctxCfg.APIBaseURL == "" {
+		return false, fmt.Errorf("context %q needs an API base URL", name)
+	}
+	c.Contexts[name] = ctxCfg
+	return !exists, nil
+}
+
+// DeleteContext removes the named context, and clears current_context when
+// it pointed at it.
+func (c *Config) DeleteContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return fmt.Errorf("context %q not found in config file", name)
+	}
+	delete(c.Contexts, name)
+	if c.CurrentContext == name {
+		c.CurrentContext = ""
+	}
+	return nil
+}
+
+// ClearCredentials removes the username, password, keyring reference and
+// token of the named context (the default endpoint when name is empty),
+// keeping its URL and token command so a later login can reuse them. The
+// keyring entry itself is left to the caller.
+func (c *Config) ClearCredentials(name string) error {
+	if name == "" {
+		c.Username, c.Password, c.Token = "", "", ""
+		c.KeyringAccount = ""
+		return nil
+	}
+	ctxCfg, ok := c.Contexts[name]
+	if !ok {
+		return fmt.Errorf("context %q not found in config file", name)
+	}
+	ctxCfg.Username, ctxCfg.Password, ctxCfg.Token = "", "", ""
+	ctxCfg.KeyringAccount = ""
+	c.Contexts[name] = ctxCfg
+	return nil
+}
+
+// UnresolvableContexts returns, sorted, the named contexts whose API host
+// cannot be resolved by lookup (net.LookupHost when nil).
+func (c *Config) UnresolvableContexts(lookup func(host string) ([]string, error)) []string {
+	if lookup == nil {
+		lookup = net.LookupHost
+	}
+
+	var stale []string
+	for name, ctxCfg := range c.Contexts {
+		u, err := url.Parse(ctxCfg.APIBaseURL)
+		if err != nil || u.Hostname() == "" {
+			stale = append(stale, name)
+			continue
+		}
+		if _, err := lookup(u.Hostname()); err != nil {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
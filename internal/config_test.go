@@ -0,0 +1,229 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConfig_ClearCredentials(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		APIBaseURL: "http://lb-a:5555",
+		Username:   "admin",
+		Password:   "secret",
+		Contexts: map[string]Config{
+			"prod-b": {APIBaseURL: "http://lb-b:5555", Username: "admin", Password: "secret"},
+		},
+	}
+
+	if err := cfg.ClearCredentials("prod-b"); err != nil {
+		t.Fatalf("ClearCredentials(prod-b) failed: %v", err)
+	}
+	if got := cfg.Contexts["prod-b"]; got.Username != "" || got.Password != "" || got.APIBaseURL != "http://lb-b:5555" {
+		t.Fatalf("prod-b after logout = %+v", got)
+	}
+	if cfg.Password != "secret" {
+		t.Fatalf("default credentials must be kept when logging out of a context")
+	}
+
+	if err := cfg.ClearCredentials(""); err != nil || cfg.Username != "" || cfg.Password != "" {
+		t.Fatalf("default logout = %+v, %v", cfg, err)
+	}
+	if err := cfg.ClearCredentials("missing"); err == nil {
+		t.Fatalf("expected error for unknown context")
+	}
+}
+
+func TestConfig_UnresolvableContexts(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Contexts: map[string]Config{
+		"live":    {APIBaseURL: "http://lb-live:5555"},
+		"gone":    {APIBaseURL: "http://lb-gone:5555/v3"},
+		"ip":      {APIBaseURL: "http://10.0.0.1:5555"},
+		"garbage": {APIBaseURL: ""},
+	}}
+	lookup := func(host string) ([]string, error) {
+		if host == "lb-gone" {
+			return nil, errors.New("no such host")
+		}
+		return []string{"10.0.0.1"}, nil
+	}
+
+	got := cfg.UnresolvableContexts(lookup)
+	if want := []string{"garbage", "gone"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("UnresolvableContexts = %v, want %v", got, want)
+	}
+}
+
+func TestLoadConfig_SelectsContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	previous := configFilePath
+	configFilePath = path
+	defer func() { configFilePath = previous }()
+	defer SetContext("")
+
+	data := `{
+  "api_base_url": "http://default:5555",
+  "username": "admin",
+  "password": "secret",
+  "current_context": "staging",
+  "contexts": {
+    "staging": {"api_base_url": "http://staging:5555", "username": "ops", "password": "s"},
+    "prod": {"api_base_url": "http://prod:5555", "username": "ops", "password": "p"}
+  }
+}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil || cfg.APIBaseURL != "http://staging:5555" || cfg.Contexts != nil {
+		t.Fatalf("current_context: got %+v, %v", cfg, err)
+	}
+
+	SetContext("prod")
+	cfg, err = LoadConfig()
+	if err != nil || cfg.APIBaseURL != "http://prod:5555" || cfg.Password != "p" {
+		t.Fatalf("--context prod: got %+v, %v", cfg, err)
+	}
+
+	SetContext("missing")
+	if _, err := LoadConfig(); err == nil {
+		t.Fatalf("expected an error for an unknown --context")
+	}
+
+	// The whole file stays available for commands that edit it.
+	SetContext("prod")
+	file, err := LoadConfigFile()
+	if err != nil || len(file.Contexts) != 2 || file.CurrentContext != "staging" {
+		t.Fatalf("LoadConfigFile() = %+v, %v", file, err)
+	}
+}
+
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	previous := configFilePath
+	configFilePath = path
+	defer func() { configFilePath = previous }()
+
+	// Without a config file, HAPROXYCTL_API_URL is enough.
+	t.Setenv(EnvAPIURL, "http://ci:5555")
+	t.Setenv(EnvUsername, "ci")
+	t.Setenv(EnvPassword, "ci-secret")
+	cfg, err := LoadConfig()
+	if err != nil || cfg.APIBaseURL != "http://ci:5555" || cfg.Username != "ci" || cfg.Password != "ci-secret" {
+		t.Fatalf("env only: got %+v, %v", cfg, err)
+	}
+
+	data := `{
+  "api_base_url": "http://default:5555",
+  "username": "admin",
+  "password": "secret",
+  "contexts": {
+    "prod": {"api_base_url": "http://prod:5555", "username": "ops", "password": "p"}
+  }
+}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	// HAPROXYCTL_CONTEXT selects the context, the other variables still
+	// override its fields.
+	t.Setenv(EnvContext, "prod")
+	t.Setenv(EnvAPIURL, "")
+	t.Setenv(EnvUsername, "")
+	cfg, err = LoadConfig()
+	if err != nil || cfg.APIBaseURL != "http://prod:5555" || cfg.Username != "ops" || cfg.Password != "ci-secret" {
+		t.Fatalf("HAPROXYCTL_CONTEXT: got %+v, %v", cfg, err)
+	}
+
+	// --context wins over HAPROXYCTL_CONTEXT.
+	SetContext("missing")
+	defer SetContext("")
+	if _, err := LoadConfig(); err == nil {
+		t.Fatalf("expected --context to take precedence over %s", EnvContext)
+	}
+}
+
+func TestConfig_ContextCommands(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{APIBaseURL: "http://default:5555"}
+
+	created, err := cfg.SetContextEndpoint("staging", Config{APIBaseURL: "http://staging:5555", Username: "ops", Password: "s"})
+	if err != nil || !created {
+		t.Fatalf("SetContextEndpoint(staging) = %v, %v", created, err)
+	}
+	created, err = cfg.SetContextEndpoint("staging", Config{Password: "rotated"})
+	if err != nil || created {
+		t.Fatalf("updating staging = %v, %v", created, err)
+	}
+	if got := cfg.Contexts["staging"]; got.APIBaseURL != "http://staging:5555" || got.Username != "ops" || got.Password != "rotated" {
+		t.Fatalf("staging after update = %+v", got)
+	}
+	if _, err := cfg.SetContextEndpoint(DefaultContextName, Config{APIBaseURL: "http://x:5555"}); err == nil {
+		t.Fatalf("expected error for the reserved default name")
+	}
+	if _, err := cfg.SetContextEndpoint("empty", Config{Username: "ops"}); err == nil {
+		t.Fatalf("expected error for a new context without URL")
+	}
+
+	if err := cfg.UseContext("missing"); err == nil {
+		t.Fatalf("expected error for unknown context")
+	}
+	if err := cfg.UseContext("staging"); err != nil || cfg.CurrentContext != "staging" {
+		t.Fatalf("UseContext(staging) = %q, %v", cfg.CurrentContext, err)
+	}
+	if got := cfg.ContextNames(); !reflect.DeepEqual(got, []string{"staging"}) {
+		t.Fatalf("ContextNames = %v", got)
+	}
+
+	if err := cfg.DeleteContext("staging"); err != nil {
+		t.Fatalf("DeleteContext(staging) failed: %v", err)
+	}
+	if cfg.CurrentContext != "" || len(cfg.Contexts) != 0 {
+		t.Fatalf("after delete = %+v", cfg)
+	}
+	if err := cfg.DeleteContext("staging"); err == nil {
+		t.Fatalf("expected error deleting a missing context")
+	}
+}
+
+func TestLoadConfig_ConnectionFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	previous := configFilePath
+	configFilePath = path
+	defer func() { configFilePath = previous }()
+	defer SetConnectionOptions(ConnectionOptions{})
+
+	// --api-url alone is enough without a config file.
+	SetConnectionOptions(ConnectionOptions{APIURL: "http://adhoc:5555"})
+	cfg, err := LoadConfig()
+	if err != nil || cfg.APIBaseURL != "http://adhoc:5555" {
+		t.Fatalf("flags only: got %+v, %v", cfg, err)
+	}
+
+	data := `{"api_base_url": "http://default:5555", "token": "abc", "username": "admin", "password": "secret"}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	// The flags win over the environment, and credentials replace the token.
+	t.Setenv(EnvAPIURL, "http://env:5555")
+	SetConnectionOptions(ConnectionOptions{APIURL: "http://adhoc:5555", Username: "ops"})
+	cfg, err = LoadConfig()
+	if err != nil || cfg.APIBaseURL != "http://adhoc:5555" || cfg.Username != "ops" || cfg.Password != "secret" || cfg.Token != "" {
+		t.Fatalf("flags over env: got %+v, %v", cfg, err)
+	}
+
+	SetConnectionOptions(ConnectionOptions{})
+	cfg, err = LoadConfig()
+	if err != nil || cfg.APIBaseURL != "http://env:5555" || cfg.Token != "abc" {
+		t.Fatalf("no flags: got %+v, %v", cfg, err)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// debugHTTP is set once per process from the global --debug flag.
+var debugHTTP bool
+
+// SetDebug makes every Data Plane API call dump its request and response,
+// headers and bodies included, to the log output (see SetLogOutput), with
+// credentials redacted (see sanitizeBody).
+func SetDebug(enabled bool) {
+	debugHTTP = enabled
+}
+
+// sensitiveHeaders are the headers dumped as redacted.
+var sensitiveHeaders = map[string]bool{"Authorization": true, "Cookie": true, "Set-Cookie": true}
+
+// sensitiveConfigLine matches the credentials of raw configuration text:
+// userlist passwords and "stats auth user:password".
+var sensitiveConfigLine = regexp.MustCompile(`(?m)(\b(?:password|insecure-password|auth)\s+)\S+`)
+
+// privateKeyBlock matches a PEM private key, as found in the certificate
+// bundles uploaded to and read from the storage endpoints.
+var privateKeyBlock = regexp.MustCompile(`(?s)(-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----).*?(-----END [A-Z0-9 ]*PRIVATE KEY-----)`)
+
+// isSensitiveKey reports whether a JSON field holds a credential.
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range []string{"password", "passwd", "secret", "token"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactJSON replaces the string values of credential fields (see
+// isSensitiveKey) anywhere in v.
+func redactJSON(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if _, isString := val.(string); isString && isSensitiveKey(k) {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactJSON(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = redactJSON(val)
+		}
+	}
+	return v
+}
+
+// sanitizeBody returns a request or response body as it may be shown:
+// uploads are left out, JSON has its credential fields redacted (and is
+// indented when pretty is set), credentials in raw configuration text
+// are redacted, and so are PEM private keys in either.
+func sanitizeBody(header http.Header, data []byte, pretty bool) string {
+	contentType := header.Get("Content-Type")
+	switch {
+	case len(data) == 0:
+		return ""
+	case strings.HasPrefix(contentType, "multipart/"):
+		return fmt.Sprintf("(file upload, %d bytes)", len(data))
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		v = redactJSON(v)
+		out, err := json.Marshal(v)
+		if pretty {
+			out, err = json.MarshalIndent(v, "", "  ")
+		}
+		if err == nil {
+			return redactPrivateKeys(string(out))
+		}
+	}
+	return redactPrivateKeys(sensitiveConfigLine.ReplaceAllString(string(data), "${1}"+redacted))
+}
+
+// redactPrivateKeys replaces the contents of PEM private keys in s, keeping
+// their BEGIN and END lines.
+func redactPrivateKeys(s string) string {
+	return privateKeyBlock.ReplaceAllString(s, "${1}"+redacted+"${2}")
+}
+
+// readBody reads and restores the body of a request or response, so it
+// can still be sent or read after being dumped.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+// writeHeaders writes h sorted by name, with sensitive values redacted.
+func writeHeaders(w io.Writer, h http.Header) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range h[name] {
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				value = redacted
+			}
+			_, _ = fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+}
+
+// dumpRequest writes the request line, headers and body of req.
+func dumpRequest(w io.Writer, req *http.Request) {
+	var body []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ = readBody(&rc)
+		}
+	}
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "--> %s %s\n", req.Method, req.URL.Redacted())
+	writeHeaders(&buf, req.Header)
+	if s := sanitizeBody(req.Header, body, true); s != "" {
+		_, _ = fmt.Fprintf(&buf, "\n%s\n", s)
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+// dumpResponse writes the status line, headers and body (already read
+// from resp) of a response.
+func dumpResponse(w io.Writer, resp *http.Response, body []byte, elapsed time.Duration) {
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "<-- %s (%s)\n", resp.Status, elapsed.Round(time.Microsecond))
+	writeHeaders(&buf, resp.Header)
+	if s := sanitizeBody(resp.Header, body, true); s != "" {
+		_, _ = fmt.Fprintf(&buf, "\n%s\n", s)
+	}
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,320 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// DescribeOptions controls which fields PrintResourceDescription shows.
+type DescribeOptions struct {
+	// Fields lists every field of the resource kind, usually taken from
+	// its payload type with SchemaFields. With ShowEmpty, fields that are
+	// unset on the resource are printed as "-".
+	Fields    []string
+	ShowEmpty bool
+	// Sections puts fields into a named section, overriding the default
+	// categories; fields in no section are shown under the header.
+	Sections map[string]string
+}
+
+// describeCategory groups fields in describe output by name: the listed
+// fields and any field starting with one of the prefixes or ending with
+// one of the suffixes.
+type describeCategory struct {
+	title    string
+	fields   []string
+	prefixes []string
+	suffixes []string
+}
+
+func (c describeCategory) matches(field string) bool {
+	return slices.Contains(c.fields, field) ||
+		slices.ContainsFunc(c.prefixes, func(p string) bool { return strings.HasPrefix(field, p) }) ||
+		slices.ContainsFunc(c.suffixes, func(s string) bool { return strings.HasSuffix(field, s) })
+}
+
+// timeoutsSection holds the millisecond timeouts, which are printed as
+// durations.
+const timeoutsSection = "Timeouts"
+
+// describeCategories follow the sections of the Data Plane API schema,
+// in the order they are printed. The first match wins.
+var describeCategories = []describeCategory{
+	{title: "Listeners", fields: []string{"binds"}},
+	{title: timeoutsSection, prefixes: []string{"timeout_"}, suffixes: []string{"_timeout"}},
+	{title: "Load Balancing", fields: []string{"balance", "hash_type", "fullconn", "retries", "retry_on", "redispatch", "weight", "backup"}},
+	{title: "Health Checks", fields: []string{"check", "inter", "fastinter", "downinter", "rise", "fall", "adv_check", "httpchk_params", "agent_check"}, prefixes: []string{"check_", "health_"}},
+	{title: "TLS", fields: []string{"ssl", "verify", "sni", "alpn", "crt_list", "ca_file", "crl_file"}, prefixes: []string{"ssl_"}},
+	{title: "Persistence", fields: []string{"cookie", "stick_table", "persist", "prefer_last_server"}},
+	{title: "Logging", fields: []string{"log", "httplog", "tcplog", "clflog", "httpslog", "logasap", "dontlognull", "dontlog_normal"}, prefixes: []string{"log_"}},
+	{title: "Error Pages", fields: []string{"error_files", "errorfiles_from_http_errors"}},
+	{title: "Stats", prefixes: []string{"stats_"}},
+	{title: "Default Server", fields: []string{"default_server"}},
+}
+
+// describeSection returns the section a field is shown in, or "" for the
+// fields shown under the header.
+func describeSection(field string, overrides map[string]string) string {
+	if title, ok := overrides[field]; ok {
+		return title
+	}
+	for _, c := range describeCategories {
+		if c.matches(field) {
+			return c.title
+		}
+	}
+	return ""
+}
+
+// SchemaFields returns the JSON field names of a payload struct, following
+// embedded structs the way encoding/json does, in sorted order.
+func SchemaFields(v interface{}) []string {
+	seen := map[string]bool{}
+	collectSchemaFields(reflect.TypeOf(v), seen)
+	fields := make([]string, 0, len(seen))
+	for f := range seen {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func collectSchemaFields(t reflect.Type, seen map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			collectSchemaFields(f.Type, seen)
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		seen[name] = true
+	}
+}
+
+// isEmptyValue reports whether a decoded API value carries no setting.
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case []map[string]interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// PrintResourceDescription prints a resource (backend, frontend, etc.) with
+// every set field, grouped into sections by category, followed by its
+// servers when given.
+func PrintResourceDescription(resourceType string, resource map[string]interface{}, servers []map[string]interface{}, opts DescribeOptions) {
+	printResourceDescription(os.Stdout, resourceType, resource, servers, opts)
+}
+
+func printResourceDescription(out io.Writer, resourceType string, resource map[string]interface{}, servers []map[string]interface{}, opts DescribeOptions) {
+	if _, err := fmt.Fprintf(out, "%s: %s\n", resourceType, resource["name"]); err != nil {
+		Warnf("failed to write resource header: %v", err)
+	}
+
+	fields := map[string]bool{}
+	for field, value := range resource {
+		if !isEmptyValue(value) {
+			fields[field] = true
+		}
+	}
+	if opts.ShowEmpty {
+		for _, field := range opts.Fields {
+			fields[field] = true
+		}
+	}
+	delete(fields, "name")
+
+	bySection := map[string][]string{}
+	for field := range fields {
+		section := describeSection(field, opts.Sections)
+		bySection[section] = append(bySection[section], field)
+	}
+
+	titles := []string{""}
+	for _, c := range describeCategories {
+		titles = append(titles, c.title)
+	}
+	var extra []string
+	for section := range bySection {
+		if !slices.Contains(titles, section) {
+			extra = append(extra, section)
+		}
+	}
+	sort.Strings(extra)
+	titles = append(titles, extra...)
+
+	for _, title := range titles {
+		sectionFields := bySection[title]
+		if len(sectionFields) == 0 {
+			continue
+		}
+		sort.Strings(sectionFields)
+		prefix := ""
+		if title != "" {
+			prefix = "- "
+			if _, err := fmt.Fprintf(out, "\n%s:\n", title); err != nil {
+				Warnf("failed to write section header: %v", err)
+			}
+		}
+		for _, field := range sectionFields {
+			value := resource[field]
+			if ms, ok := value.(float64); ok && title == timeoutsSection {
+				value = FormatMillisAsDuration(int(ms))
+			}
+			writeDescribeField(out, prefix, field, value)
+		}
+	}
+
+	if len(servers) > 0 {
+		printServerTable(out, servers)
+	}
+}
+
+// writeDescribeField prints one field; maps and lists of objects are
+// expanded on indented lines below it.
+func writeDescribeField(out io.Writer, prefix, field string, value interface{}) {
+	name := formatFieldName(field)
+	var lines []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 {
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				lines = append(lines, fmt.Sprintf("    %s: %s", k, formatValue(v[k])))
+			}
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			if _, ok := v[0].(map[string]interface{}); ok {
+				for _, item := range v {
+					lines = append(lines, "    - "+formatInlineObject(item))
+				}
+			}
+		}
+	}
+
+	var err error
+	switch {
+	case len(lines) > 0:
+		_, err = fmt.Fprintf(out, "%s%s:\n%s\n", prefix, name, strings.Join(lines, "\n"))
+	case isEmptyValue(value):
+		_, err = fmt.Fprintf(out, "%s%s: -\n", prefix, name)
+	case isList(value):
+		_, err = fmt.Fprintf(out, "%s%s: %s\n", prefix, name, formatScalarList(value.([]interface{})))
+	default:
+		_, err = fmt.Fprintf(out, "%s%s: %s\n", prefix, name, formatValue(value))
+	}
+	if err != nil {
+		Warnf("failed to write field %s: %v", field, err)
+	}
+}
+
+func isList(value interface{}) bool {
+	_, ok := value.([]interface{})
+	return ok
+}
+
+func formatScalarList(list []interface{}) string {
+	parts := make([]string, 0, len(list))
+	for _, item := range list {
+		parts = append(parts, formatValue(item))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatInlineObject renders an object as sorted "key=value" pairs.
+func formatInlineObject(item interface{}) string {
+	obj, ok := item.(map[string]interface{})
+	if !ok {
+		return formatValue(item)
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+formatValue(obj[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func printServerTable(out io.Writer, servers []map[string]interface{}) {
+	if _, err := fmt.Fprintln(out, "\nServers:"); err != nil {
+		Warnf("failed to write servers header: %v", err)
+	}
+	const (
+		tabWidth   = 8
+		tabPadding = 2
+	)
+
+	w := tabwriter.NewWriter(out, 0, tabWidth, tabPadding, ' ', 0)
+	if _, err := fmt.Fprintf(w, "NAME\tADDRESS\tPORT\tWEIGHT\n"); err != nil {
+		Warnf("failed to write servers header: %v", err)
+	}
+	if _, err := fmt.Fprintf(w, "----\t-------\t----\t------\n"); err != nil {
+		Warnf("failed to write servers separator: %v", err)
+	}
+	for _, server := range servers {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%v\t%v\n",
+			server["name"],
+			server["address"],
+			server["port"],
+			server["weight"],
+		); err != nil {
+			Warnf("failed to write server row: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		Warnf("failed to flush servers table: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintResourceDescription_GroupsFields(t *testing.T) {
+	t.Parallel()
+
+	resource := map[string]interface{}{
+		"name":            "app",
+		"mode":            "http",
+		"timeout_server":  float64(30000),
+		"timeout_connect": float64(5000),
+		"retries":         float64(3),
+		"balance":         map[string]interface{}{"algorithm": "roundrobin"},
+		"error_files":     []interface{}{map[string]interface{}{"code": float64(503), "file": "/e/503.http"}},
+		"from":            "",
+	}
+	var out bytes.Buffer
+	printResourceDescription(&out, "Backend", resource, nil, DescribeOptions{})
+	got := out.String()
+
+	want := `Backend: app
+Mode: http
+
+Timeouts:
+- Timeout Connect: 5s
+- Timeout Server: 30s
+
+Load Balancing:
+- Balance:
+    algorithm: roundrobin
+- Retries: 3
+
+Error Pages:
+- Error Files:
+    - code=503 file=/e/503.http
+`
+	if got != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrintResourceDescription_ShowEmptyAndSections(t *testing.T) {
+	t.Parallel()
+
+	type payload struct {
+		Name  string `json:"name"`
+		Mode  string `json:"mode,omitempty"`
+		Check string `json:"check,omitempty"`
+		Skip  string `json:"-"`
+	}
+	if fields := SchemaFields(payload{}); strings.Join(fields, ",") != "check,mode,name" {
+		t.Fatalf("SchemaFields = %v", fields)
+	}
+
+	var out bytes.Buffer
+	printResourceDescription(&out, "Server", map[string]interface{}{"name": "s1", "mode": "http"}, nil, DescribeOptions{
+		Fields:    SchemaFields(payload{}),
+		ShowEmpty: true,
+		Sections:  map[string]string{"mode": "Custom"},
+	})
+	got := out.String()
+	if !strings.Contains(got, "Health Checks:\n- Check: -\n") || !strings.Contains(got, "Custom:\n- Mode: http\n") {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+}
@@ -0,0 +1,368 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldChange is a single differing field inside a configuration section.
+// An empty Old or New means the field is missing on that side.
+type FieldChange struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// SectionDiff describes how one configuration section (for example
+// "global" or "backends/app") differs between two documents. OnlyIn is
+// "a" or "b" when the section exists on one side only.
+type SectionDiff struct {
+	Section string
+	OnlyIn  string
+	Changes []FieldChange
+}
+
+// DiffConfigurations compares two structured configuration documents, as
+// returned by "get configuration full", section by section. Named list
+// items (frontends, backends, ...) are matched by name, so ordering does
+// not produce spurious differences. The result is sorted by section.
+func DiffConfigurations(a, b map[string]interface{}) []SectionDiff {
+	sectionsA := splitSections(a)
+	sectionsB := splitSections(b)
+
+	names := make([]string, 0, len(sectionsA)+len(sectionsB))
+	for name := range sectionsA {
+		names = append(names, name)
+	}
+	for name := range sectionsB {
+		if _, ok := sectionsA[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []SectionDiff
+	for _, name := range names {
+		objA, inA := sectionsA[name]
+		objB, inB := sectionsB[name]
+		switch {
+		case !inB:
+			diffs = append(diffs, SectionDiff{Section: name, OnlyIn: "a"})
+		case !inA:
+			diffs = append(diffs, SectionDiff{Section: name, OnlyIn: "b"})
+		default:
+			if changes := diffFields(objA, objB); len(changes) > 0 {
+				diffs = append(diffs, SectionDiff{Section: name, Changes: changes})
+			}
+		}
+	}
+	return diffs
+}
+
+// PrintConfigurationDiff writes diffs in a compact, diff(1)-like form,
+// labelling the two sides with labelA and labelB.
+func PrintConfigurationDiff(w io.Writer, diffs []SectionDiff, labelA, labelB string) {
+	_, _ = fmt.Fprintf(w, "--- %s\n+++ %s\n", labelA, labelB)
+	for _, d := range diffs {
+		switch d.OnlyIn {
+		case "a":
+			_, _ = fmt.Fprintf(w, "%s: only in %s\n", d.Section, labelA)
+			continue
+		case "b":
+			_, _ = fmt.Fprintf(w, "%s: only in %s\n", d.Section, labelB)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s:\n", d.Section)
+		for _, c := range d.Changes {
+			if c.Old != "" {
+				_, _ = fmt.Fprintf(w, "  - %s: %s\n", c.Path, c.Old)
+			}
+			if c.New != "" {
+				_, _ = fmt.Fprintf(w, "  + %s: %s\n", c.Path, c.New)
+			}
+		}
+	}
+}
+
+// splitSections breaks a document into comparable units: object sections
+// stay whole, list sections are split into "<section>/<name>" items.
+func splitSections(doc map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for section, value := range doc {
+		items, ok := asList(value)
+		if !ok {
+			out[section] = value
+			continue
+		}
+		for i, item := range items {
+			out[section+"/"+itemKey(item, i)] = item
+		}
+	}
+	return out
+}
+
+// asList normalizes both []interface{} (decoded JSON) and
+// []map[string]interface{} (assembled documents) into one form.
+func asList(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []map[string]interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, true
+	default:
+		return nil, false
+	}
+}
+
+// itemKey identifies a list item by its name, falling back to its position.
+func itemKey(item interface{}, index int) string {
+	if m, ok := item.(map[string]interface{}); ok {
+		if name, ok := m["name"].(string); ok && name != "" {
+			return name
+		}
+	}
+	return strconv.Itoa(index)
+}
+
+func diffFields(a, b interface{}) []FieldChange {
+	flatA := make(map[string]string)
+	flatB := make(map[string]string)
+	flatten("", a, flatA)
+	flatten("", b, flatB)
+
+	paths := make([]string, 0, len(flatA)+len(flatB))
+	for p := range flatA {
+		paths = append(paths, p)
+	}
+	for p := range flatB {
+		if _, ok := flatA[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []FieldChange
+	for _, p := range paths {
+		if flatA[p] != flatB[p] {
+			changes = append(changes, FieldChange{Path: p, Old: flatA[p], New: flatB[p]})
+		}
+	}
+	return changes
+}
+
+// flatten turns nested maps and lists into dotted paths with scalar values.
+// Lists of named objects are keyed by name rather than position.
+func flatten(prefix string, value interface{}, out map[string]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	if items, ok := asList(value); ok {
+		for i, item := range items {
+			flatten(join(itemKey(item, i)), item, out)
+		}
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			flatten(join(k), child, out)
+		}
+	case nil:
+		// Absent and null are treated the same.
+	case string:
+		out[prefix] = v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			out[prefix] = fmt.Sprint(v)
+			return
+		}
+		out[prefix] = string(data)
+	}
+}
+
+// ManifestComparison holds the live and desired state of one manifest in
+// the normalized form apply compares them in. Live is nil when the
+// resource does not exist yet.
+type ManifestComparison struct {
+	Kind    string
+	Name    string
+	Live    interface{}
+	Desired interface{}
+}
+
+// unifiedContext is the number of unchanged lines shown around a change.
+const unifiedContext = 3
+
+const (
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiCyan  = "\033[36m"
+	ansiReset = "\033[0m"
+)
+
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// WriteUnifiedDiff writes a unified diff (diff -u) of the lines of a and b
+// to w and reports whether they differ. Nothing is written when they are
+// equal. With color set, removed and added lines are shown in red and
+// green.
+func WriteUnifiedDiff(w io.Writer, a, b, labelA, labelB string, color bool) bool {
+	lines := diffLines(splitLines(a), splitLines(b))
+
+	var changes []int
+	for i, l := range lines {
+		if l.op != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return false
+	}
+
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return code + s + ansiReset
+	}
+
+	_, _ = fmt.Fprintln(w, paint(ansiRed, "--- "+labelA))
+	_, _ = fmt.Fprintln(w, paint(ansiGreen, "+++ "+labelB))
+
+	for start := 0; start < len(changes); {
+		// Extend the hunk while the next change is close enough that the
+		// context lines would overlap.
+		end := start
+		for end+1 < len(changes) && changes[end+1]-changes[end] <= 2*unifiedContext {
+			end++
+		}
+		from := max(changes[start]-unifiedContext, 0)
+		to := min(changes[end]+unifiedContext+1, len(lines))
+
+		aStart, bStart := 0, 0
+		for _, l := range lines[:from] {
+			if l.op != '+' {
+				aStart++
+			}
+			if l.op != '-' {
+				bStart++
+			}
+		}
+		aLen, bLen := 0, 0
+		for _, l := range lines[from:to] {
+			if l.op != '+' {
+				aLen++
+			}
+			if l.op != '-' {
+				bLen++
+			}
+		}
+		_, _ = fmt.Fprintln(w, paint(ansiCyan, fmt.Sprintf("@@ -%s +%s @@", hunkRange(aStart, aLen), hunkRange(bStart, bLen))))
+
+		for _, l := range lines[from:to] {
+			text := string(l.op) + l.text
+			switch l.op {
+			case '-':
+				text = paint(ansiRed, text)
+			case '+':
+				text = paint(ansiGreen, text)
+			}
+			_, _ = fmt.Fprintln(w, text)
+		}
+		start = end + 1
+	}
+	return true
+}
+
+// hunkRange formats one side of a hunk header; like diff -u, an empty
+// range points at the line before it.
+func hunkRange(before, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, length)
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// diffLines computes a line diff from the longest common subsequence of a
+// and b. Manifests are small, so the quadratic table is fine.
+func diffLines(a, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, diffLine{'-', a[i]})
+			i++
+		default:
+			out = append(out, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, diffLine{'+', b[j]})
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDiffConfigurations(t *testing.T) {
+	t.Parallel()
+
+	a := map[string]interface{}{
+		"global": map[string]interface{}{"maxconn": float64(2000), "daemon": true},
+		"backends": []map[string]interface{}{
+			{"name": "app", "balance": map[string]interface{}{"algorithm": "roundrobin"}},
+			{"name": "legacy"},
+		},
+		"frontends": []interface{}{
+			map[string]interface{}{"name": "web", "default_backend": "app"},
+		},
+	}
+	b := map[string]interface{}{
+		"global": map[string]interface{}{"maxconn": 4000, "daemon": true},
+		"backends": []map[string]interface{}{
+			{"name": "new"},
+			{"name": "app", "balance": map[string]interface{}{"algorithm": "leastconn"}},
+		},
+		"frontends": []interface{}{
+			map[string]interface{}{"name": "web", "default_backend": "app"},
+		},
+	}
+
+	diffs := DiffConfigurations(a, b)
+
+	want := []SectionDiff{
+		{Section: "backends/app", Changes: []FieldChange{{Path: "balance.algorithm", Old: "roundrobin", New: "leastconn"}}},
+		{Section: "backends/legacy", OnlyIn: "a"},
+		{Section: "backends/new", OnlyIn: "b"},
+		{Section: "global", Changes: []FieldChange{{Path: "maxconn", Old: "2000", New: "4000"}}},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("got %d diffs, want %d: %+v", len(diffs), len(want), diffs)
+	}
+	for i := range want {
+		if diffs[i].Section != want[i].Section || diffs[i].OnlyIn != want[i].OnlyIn {
+			t.Fatalf("diff %d = %+v, want %+v", i, diffs[i], want[i])
+		}
+		if len(diffs[i].Changes) != len(want[i].Changes) {
+			t.Fatalf("diff %d changes = %+v, want %+v", i, diffs[i].Changes, want[i].Changes)
+		}
+		for j := range want[i].Changes {
+			if diffs[i].Changes[j] != want[i].Changes[j] {
+				t.Fatalf("diff %d change %d = %+v, want %+v", i, j, diffs[i].Changes[j], want[i].Changes[j])
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	PrintConfigurationDiff(&out, diffs, "prod-a", "prod-b")
+	for _, line := range []string{
+		"--- prod-a",
+		"backends/legacy: only in prod-a",
+		"  - balance.algorithm: roundrobin",
+		"  + balance.algorithm: leastconn",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Fatalf("output missing %q:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestDiffConfigurations_Identical(t *testing.T) {
+	t.Parallel()
+
+	doc := map[string]interface{}{"backends": []interface{}{map[string]interface{}{"name": "app"}}}
+	if diffs := DiffConfigurations(doc, doc); len(diffs) != 0 {
+		t.Fatalf("expected no diffs, got %+v", diffs)
+	}
+}
+
+func TestResolveContext(t *testing.T) {
+	restore := SetConfigOverride(&Config{
+		APIBaseURL: "http://default:5555",
+		Contexts: map[string]Config{
+			"prod-b": {APIBaseURL: "http://prod-b:5555", Username: "ops"},
+		},
+	})
+	defer restore()
+
+	cfg, err := ResolveContext("")
+	if err != nil || cfg.APIBaseURL != "http://default:5555" || cfg.Contexts != nil {
+		t.Fatalf("default context = %+v, %v", cfg, err)
+	}
+	cfg, err = ResolveContext("prod-b")
+	if err != nil || cfg.APIBaseURL != "http://prod-b:5555" || cfg.Username != "ops" {
+		t.Fatalf("prod-b context = %+v, %v", cfg, err)
+	}
+	if _, err := ResolveContext("missing"); err == nil {
+		t.Fatalf("expected error for unknown context")
+	}
+}
+
+func TestWriteUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	a := "name: web\nmode: http\nbalance: roundrobin\nmaxconn: 100\nretries: 3\nlog: global\ntimeout: 5s\n"
+	b := "name: web\nmode: tcp\nbalance: roundrobin\nmaxconn: 100\nretries: 3\nlog: global\ntimeout: 5s\n"
+
+	var buf bytes.Buffer
+	if !WriteUnifiedDiff(&buf, a, b, "live", "manifest", false) {
+		t.Fatal("expected a difference")
+	}
+	want := `--- live
++++ manifest
+@@ -1,5 +1,5 @@
+ name: web
+-mode: http
++mode: tcp
+ balance: roundrobin
+ maxconn: 100
+ retries: 3
+`
+	if buf.String() != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if !WriteUnifiedDiff(&buf, "", "name: new\n", "live", "manifest", false) {
+		t.Fatal("expected a difference for a new object")
+	}
+	if !strings.Contains(buf.String(), "@@ -0,0 +1,1 @@\n+name: new\n") {
+		t.Fatalf("unexpected diff for new object:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if WriteUnifiedDiff(&buf, a, a, "live", "manifest", false) || buf.Len() != 0 {
+		t.Fatalf("expected no output for equal input, got:\n%s", buf.String())
+	}
+}
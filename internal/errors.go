@@ -0,0 +1,298 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"haproxyctl/pkg/client"
+)
+
+// Error output formats of the global --error-format flag.
+const (
+	ErrorFormatText = "text"
+	ErrorFormatJSON = "json"
+)
+
+// errorFormat is set once per process from the global --error-format flag.
+var errorFormat = ErrorFormatText
+
+// SetErrorFormat selects how Fatalf reports the error a command failed
+// with: as a log line (text) or as one JSON object on stderr (json).
+func SetErrorFormat(format string) error {
+	switch format {
+	case ErrorFormatText, ErrorFormatJSON:
+		errorFormat = format
+		return nil
+	default:
+		return fmt.Errorf("invalid --error-format %q (expected %s or %s)", format, ErrorFormatText, ErrorFormatJSON)
+	}
+}
+
+// ResourceError is a failed Data Plane API operation on a named object,
+// as returned by FormatAPIError.
+type ResourceError struct {
+	Kind      string
+	Name      string
+	Operation string
+	Err       error
+}
+
+func (e *ResourceError) Error() string {
+	lowerKind := strings.ToLower(e.Kind)
+	if IsAlreadyExistsError(e.Err) {
+		return fmt.Sprintf("%s %q already exists (consider using 'haproxyctl apply -f ...')", lowerKind, e.Name)
+	}
+	if IsNotFoundError(e.Err) {
+		return fmt.Sprintf("%s %q not found", lowerKind, e.Name)
+	}
+	return fmt.Sprintf("HAProxy API %s %s: %v", e.Operation, ResourceID(e.Kind, e.Name), e.Err)
+}
+
+func (e *ResourceError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorReport is the JSON object --error-format json prints for a failed
+// command. Reason is one of the ErrorReason values; the other fields are
+// left out when they are not known.
+type ErrorReport struct {
+	Error     string `json:"error"`
+	Reason    string `json:"reason"`
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	Status    int    `json:"status,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// Reasons of an ErrorReport, for automation to branch on.
+const (
+	ReasonNotFound        = "not_found"
+	ReasonAlreadyExists   = "already_exists"
+	ReasonVersionConflict = "version_conflict"
+	ReasonInvalid         = "invalid"
+	ReasonUnauthorized    = "unauthorized"
+	ReasonForbidden       = "forbidden"
+	ReasonServerError     = "server_error"
+	ReasonAPIError        = "api_error"
+	ReasonTimeout         = "timeout"
+	ReasonConnection      = "connection"
+	ReasonOffline         = "offline"
+	ReasonUsage           = "usage"
+	ReasonError           = "error"
+)
+
+// Exit codes of a failed command. Anything not covered by a more specific
+// code exits with ExitError.
+const (
+	ExitError       = 1
+	ExitUsage       = 2
+	ExitNotFound    = 3
+	ExitConflict    = 4
+	ExitInvalid     = 5
+	ExitUnreachable = 6
+)
+
+// ExitCodeError gives err the exit code Code (see ExitCode).
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.Err
+}
+
+// WithExitCode makes a command failing with err exit with code, for
+// failures whose kind cannot be told from the error itself (e.g. a
+// manifest that does not validate).
+func WithExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitCodeError{Code: code, Err: err}
+}
+
+// ExitCode returns the exit code of a command failing with err: the one
+// given with WithExitCode, else the one of its ErrorReport reason.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var codeErr *ExitCodeError
+	if errors.As(err, &codeErr) {
+		return codeErr.Code
+	}
+	return NewErrorReport(err.Error(), err).exitCode()
+}
+
+// exitCode maps the reason of r to an exit code.
+func (r ErrorReport) exitCode() int {
+	switch r.Reason {
+	case ReasonUsage:
+		return ExitUsage
+	case ReasonNotFound:
+		return ExitNotFound
+	case ReasonAlreadyExists, ReasonVersionConflict:
+		return ExitConflict
+	case ReasonInvalid:
+		return ExitInvalid
+	case ReasonConnection, ReasonTimeout:
+		return ExitUnreachable
+	case ReasonServerError:
+		// A proxy in front of the Data Plane API answers for it when it
+		// is down.
+		if r.Status == http.StatusBadGateway || r.Status == http.StatusServiceUnavailable || r.Status == http.StatusGatewayTimeout {
+			return ExitUnreachable
+		}
+	}
+	return ExitError
+}
+
+// codeReasons are the reasons reported for an exit code given explicitly
+// (see WithExitCode and FatalCodef).
+var codeReasons = map[int]string{
+	ExitUsage:       ReasonUsage,
+	ExitNotFound:    ReasonNotFound,
+	ExitConflict:    ReasonAlreadyExists,
+	ExitInvalid:     ReasonInvalid,
+	ExitUnreachable: ReasonConnection,
+}
+
+// NewErrorReport describes a failure with message msg caused by err (which
+// may be nil).
+func NewErrorReport(msg string, err error) ErrorReport {
+	report := ErrorReport{Error: msg, Reason: ReasonError}
+	var resErr *ResourceError
+	if errors.As(err, &resErr) {
+		report.Kind = strings.ToLower(resErr.Kind)
+		report.Name = resErr.Name
+		report.Operation = resErr.Operation
+	}
+
+	var (
+		apiErr *client.APIError
+		urlErr *url.Error
+		opErr  *net.OpError
+	)
+	switch {
+	case errors.As(err, &apiErr):
+		report.Status = apiErr.StatusCode
+		report.Message = apiMessage(apiErr.Body)
+		report.Reason = statusReason(err, apiErr.StatusCode)
+	case errors.Is(err, ErrOffline):
+		report.Reason = ReasonOffline
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &urlErr) && urlErr.Timeout():
+		report.Reason = ReasonTimeout
+	case errors.As(err, &urlErr), errors.As(err, &opErr):
+		report.Reason = ReasonConnection
+	}
+
+	var codeErr *ExitCodeError
+	if errors.As(err, &codeErr) && report.Reason == ReasonError {
+		if reason, ok := codeReasons[codeErr.Code]; ok {
+			report.Reason = reason
+		}
+	}
+	return report
+}
+
+// statusReason maps the HTTP status of a failed API request to a reason.
+func statusReason(err error, status int) string {
+	switch {
+	case status == http.StatusNotFound:
+		return ReasonNotFound
+	case status == http.StatusConflict && IsVersionConflictError(err):
+		return ReasonVersionConflict
+	case status == http.StatusConflict:
+		return ReasonAlreadyExists
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return ReasonInvalid
+	case status == http.StatusUnauthorized:
+		return ReasonUnauthorized
+	case status == http.StatusForbidden:
+		return ReasonForbidden
+	case status >= http.StatusInternalServerError:
+		return ReasonServerError
+	default:
+		return ReasonAPIError
+	}
+}
+
+// apiMessage returns the "message" of a Data Plane API error body, or the
+// body itself when it is not such an object.
+func apiMessage(body string) string {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(body), &payload); err == nil && payload.Message != "" {
+		return payload.Message
+	}
+	return strings.TrimSpace(body)
+}
+
+// reportError writes the failure of a command in the selected error
+// format (see SetErrorFormat) and returns its exit code: code when it is
+// non-zero, else the one of the cause, the last error in args.
+func reportError(code int, format string, args ...interface{}) int {
+	msg := fmt.Sprintf(format, args...)
+	var cause error
+	for i := len(args) - 1; i >= 0 && cause == nil; i-- {
+		cause, _ = args[i].(error)
+	}
+	if code != 0 {
+		cause = WithExitCode(code, cause)
+		if cause == nil {
+			cause = WithExitCode(code, errors.New(msg))
+		}
+	}
+	return ReportError(msg, cause)
+}
+
+// ReportError writes msg, the failure of a command caused by err, in the
+// selected error format (see SetErrorFormat) and returns the exit code
+// (see ExitCode).
+func ReportError(msg string, err error) int {
+	code := ExitError
+	if err != nil {
+		code = ExitCode(err)
+	}
+	if errorFormat != ErrorFormatJSON {
+		printLine("error", msg)
+		return code
+	}
+
+	data, jerr := json.Marshal(NewErrorReport(msg, err))
+	if jerr != nil {
+		printLine("error", msg)
+		return code
+	}
+	_, _ = fmt.Fprintf(logOutput, "%s\n", data)
+	return code
+}
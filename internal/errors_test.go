@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"haproxyctl/pkg/client"
+)
+
+func TestNewErrorReport(t *testing.T) {
+	t.Parallel()
+
+	notFound := FormatAPIError("Backend", "app", "get", fmt.Errorf("failed to get resource: %w",
+		&client.APIError{StatusCode: 404, Body: `{"code":404,"message":"missing object: backend app"}`}))
+	conflict := &client.APIError{StatusCode: 409, Body: `{"code":409,"message":"version mismatch"}`}
+
+	tests := []struct {
+		name string
+		err  error
+		want ErrorReport
+	}{
+		{"not found", notFound, ErrorReport{Reason: ReasonNotFound, Kind: "backend", Name: "app", Operation: "get", Status: 404, Message: "missing object: backend app"}},
+		{"version conflict", conflict, ErrorReport{Reason: ReasonVersionConflict, Status: 409, Message: "version mismatch"}},
+		{"plain body", &client.APIError{StatusCode: 400, Body: "bad mode\n"}, ErrorReport{Reason: ReasonInvalid, Status: 400, Message: "bad mode"}},
+		{"offline", fmt.Errorf("failed: %w", ErrOffline), ErrorReport{Reason: ReasonOffline}},
+		{"other", fmt.Errorf("boom"), ErrorReport{Reason: ReasonError}},
+		{"missing file", fmt.Errorf("failed to read file: %w", &fs.PathError{Op: "stat", Path: "/x", Err: syscall.ENOENT}), ErrorReport{Reason: ReasonError}},
+		{"refused", fmt.Errorf("API request failed: %w", &url.Error{Op: "Get", URL: "http://h", Err: syscall.ECONNREFUSED}), ErrorReport{Reason: ReasonConnection}},
+	}
+	for _, tt := range tests {
+		got := NewErrorReport("msg", tt.err)
+		tt.want.Error = "msg"
+		if got != tt.want {
+			t.Errorf("%s: report = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if code := ExitCode(notFound); code != ExitNotFound {
+		t.Errorf("ExitCode(not found) = %d, want %d", code, ExitNotFound)
+	}
+	if code := ExitCode(WithExitCode(ExitInvalid, fmt.Errorf("2 of 3 manifests invalid"))); code != ExitInvalid {
+		t.Errorf("ExitCode(WithExitCode) = %d, want %d", code, ExitInvalid)
+	}
+	if code := ExitCode(&client.APIError{StatusCode: 503}); code != ExitUnreachable {
+		t.Errorf("ExitCode(503) = %d, want %d", code, ExitUnreachable)
+	}
+	if got := notFound.Error(); got != `backend "app" not found` {
+		t.Errorf("FormatAPIError message changed: %q", got)
+	}
+}
+
+func TestReportError_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	defer SetLogOutput(&buf)()
+	if err := SetErrorFormat(ErrorFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetErrorFormat(ErrorFormatText) }()
+
+	err := FormatAPIError("Server", "web1", "create", &client.APIError{StatusCode: 409, Body: `{"message":"object exists"}`})
+	reportError(0, "Failed to create server %q: %v", "web1", err)
+
+	var report ErrorReport
+	if jerr := json.Unmarshal(buf.Bytes(), &report); jerr != nil {
+		t.Fatalf("not one JSON object: %v\n%s", jerr, buf.String())
+	}
+	want := ErrorReport{
+		Error:  `Failed to create server "web1": server "web1" already exists (consider using 'haproxyctl apply -f ...')`,
+		Reason: ReasonAlreadyExists, Kind: "server", Name: "web1", Operation: "create", Status: 409, Message: "object exists",
+	}
+	if report != want {
+		t.Fatalf("report = %+v, want %+v", report, want)
+	}
+	if SetErrorFormat("xml") == nil {
+		t.Error("expected error for an unknown format")
+	}
+}
@@ -18,7 +18,6 @@ package internal
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -71,7 +70,7 @@ func ParseKeyValueString(input string) (map[string]string, error) {
 func ParseKeyValueFlag(cmd *cobra.Command, flagName string) map[string]string {
 	values, err := cmd.Flags().GetStringToString(flagName)
 	if err != nil {
-		log.Fatalf("Failed to parse flag %s: %v", flagName, err)
+		Fatalf("Failed to parse flag %s: %v", flagName, err)
 	}
 	if len(values) == 0 {
 		return nil // Return nil if no values provided
@@ -85,7 +84,7 @@ func GetFlagString(cmd *cobra.Command, name string) string {
 	if f := cmd.Flags().Lookup(name); f != nil {
 		value, err := cmd.Flags().GetString(name)
 		if err != nil {
-			log.Fatalf("Failed to read flag %s: %v", name, err)
+			Fatalf("Failed to read flag %s: %v", name, err)
 		}
 		return value
 	}
@@ -94,7 +93,7 @@ func GetFlagString(cmd *cobra.Command, name string) string {
 	if f := cmd.InheritedFlags().Lookup(name); f != nil {
 		value, err := cmd.InheritedFlags().GetString(name)
 		if err != nil {
-			log.Fatalf("Failed to read inherited flag %s: %v", name, err)
+			Fatalf("Failed to read inherited flag %s: %v", name, err)
 		}
 		return value
 	}
@@ -108,7 +107,7 @@ func GetFlagString(cmd *cobra.Command, name string) string {
 func GetFlagStringSlice(cmd *cobra.Command, name string) []string {
 	values, err := cmd.Flags().GetStringArray(name)
 	if err != nil {
-		log.Fatalf("Failed to read flag %s: %v", name, err)
+		Fatalf("Failed to read flag %s: %v", name, err)
 	}
 	return values
 }
@@ -117,7 +116,7 @@ func GetFlagStringSlice(cmd *cobra.Command, name string) []string {
 func GetFlagBool(cmd *cobra.Command, name string) bool {
 	value, err := cmd.Flags().GetBool(name)
 	if err != nil {
-		log.Fatalf("Failed to read flag %s: %v", name, err)
+		Fatalf("Failed to read flag %s: %v", name, err)
 	}
 	return value
 }
@@ -126,7 +125,7 @@ func GetFlagBool(cmd *cobra.Command, name string) bool {
 func GetFlagMap(cmd *cobra.Command, name string) map[string]string {
 	values, err := cmd.Flags().GetStringToString(name)
 	if err != nil {
-		log.Fatalf("Failed to read flag %s: %v", name, err)
+		Fatalf("Failed to read flag %s: %v", name, err)
 	}
 	return values
 }
@@ -135,7 +134,7 @@ func GetFlagMap(cmd *cobra.Command, name string) map[string]string {
 func GetFlagMapInterface(cmd *cobra.Command, name string) map[string]interface{} {
 	values, err := cmd.Flags().GetStringToString(name)
 	if err != nil {
-		log.Fatalf("Failed to read flag %s: %v", name, err)
+		Fatalf("Failed to read flag %s: %v", name, err)
 	}
 
 	result := make(map[string]interface{})
@@ -149,7 +148,7 @@ func GetFlagMapInterface(cmd *cobra.Command, name string) map[string]interface{}
 func GetFlagInt(cmd *cobra.Command, name string) int {
 	value, err := cmd.Flags().GetInt(name)
 	if err != nil {
-		log.Fatalf("Failed to read flag %s: %v", name, err)
+		Fatalf("Failed to read flag %s: %v", name, err)
 	}
 	return value
 }
@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ApplyIndexedDiff turns the position-indexed list at path (ACLs, rules,
+// filters, ...) from before into after. Entries are only addressable by
+// position, so every entry from the first difference on is removed and
+// re-created in order; payload converts an entry to its API object.
+//
+// Changes are staged in tx when it is non-nil.
+func ApplyIndexedDiff[T comparable](tx *Transaction, path string, before, after []T, payload func(T) interface{}) error {
+	return ApplyIndexedDiffFunc(tx, path, before, after, func(a, b T) bool { return a == b }, payload)
+}
+
+// ApplyIndexedDiffFunc is ApplyIndexedDiff for entries that are not
+// comparable with ==, such as entries carrying a list of headers.
+func ApplyIndexedDiffFunc[T any](tx *Transaction, path string, before, after []T, equal func(a, b T) bool, payload func(T) interface{}) error {
+	common := 0
+	for common < len(before) && common < len(after) && equal(before[common], after[common]) {
+		common++
+	}
+
+	for i := len(before) - 1; i >= common; i-- {
+		params, err := WriteParams(tx)
+		if err != nil {
+			return err
+		}
+		if _, err := SendRequest("DELETE", path+"/"+strconv.Itoa(i), params, nil); err != nil {
+			return fmt.Errorf("failed to delete %q: %w", fmt.Sprint(before[i]), err)
+		}
+	}
+	for i := common; i < len(after); i++ {
+		params, err := WriteParams(tx)
+		if err != nil {
+			return err
+		}
+		if _, err := SendRequest("POST", path+"/"+strconv.Itoa(i), params, payload(after[i])); err != nil {
+			return fmt.Errorf("failed to create %q: %w", fmt.Sprint(after[i]), err)
+		}
+	}
+	return nil
+}
+
+// IndexedEqual compares two position-indexed lists, treating nil and empty
+// alike.
+func IndexedEqual[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
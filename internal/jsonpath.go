@@ -0,0 +1,576 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// jsonPathPrefix introduces a JSONPath template in an output format, as in
+// -o jsonpath='{.items[*].name}'.
+const jsonPathPrefix = "jsonpath="
+
+// IsJSONPathFormat reports whether outputFormat is a jsonpath=<template> format.
+func IsJSONPathFormat(outputFormat string) bool {
+	return strings.HasPrefix(outputFormat, jsonPathPrefix)
+}
+
+// printJSONPath renders data through the template of a jsonpath=<template>
+// output format, following kubectl semantics: text outside {} is printed
+// as-is, several results of one expression are separated by spaces and
+// lists are exposed as the items of a List object.
+func printJSONPath(w io.Writer, data interface{}, outputFormat string) error {
+	tmpl, err := parseJSONPathTemplate(strings.TrimPrefix(outputFormat, jsonPathPrefix))
+	if err != nil {
+		return err
+	}
+	root, err := jsonPathData(data)
+	if err != nil {
+		return err
+	}
+	var sb strings.Builder
+	if err := tmpl.execute(&sb, root, root); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// jsonPathData converts data into plain maps and slices keyed by the field
+// names used in YAML/JSON output, wrapping lists in a List object.
+func jsonPathData(data interface{}) (interface{}, error) {
+	raw, err := yaml.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	var generic interface{}
+	if err := yaml.Unmarshal(raw, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode output: %w", err)
+	}
+	generic = stringKeys(generic)
+	if items, ok := generic.([]interface{}); ok {
+		return map[string]interface{}{"kind": ListKind, "items": items}, nil
+	}
+	return generic, nil
+}
+
+// jsonPathNode is one element of a parsed template: literal text, a path
+// expression, or a range block over the results of a path.
+type jsonPathNode struct {
+	text    string
+	path    []jsonPathStep
+	isRange bool
+	body    jsonPathTemplate
+}
+
+type jsonPathTemplate []jsonPathNode
+
+// parseJSONPathTemplate parses a template such as
+// {range .items[*]}{.name}{"\n"}{end}.
+func parseJSONPathTemplate(text string) (jsonPathTemplate, error) {
+	tmpl, _, err := parseJSONPathNodes(text, false)
+	return tmpl, err
+}
+
+// parseJSONPathNodes parses nodes until the end of text or, inside a range,
+// until the matching {end}, returning the text after it.
+func parseJSONPathNodes(text string, inRange bool) (jsonPathTemplate, string, error) {
+	var nodes jsonPathTemplate
+	for text != "" {
+		open := strings.IndexByte(text, '{')
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{text: text})
+			text = ""
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{text: text[:open]})
+		}
+		end, err := closingBrace(text, open)
+		if err != nil {
+			return nil, "", err
+		}
+		expr := strings.TrimSpace(text[open+1 : end])
+		text = text[end+1:]
+
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, "", errors.New("jsonpath: {end} without {range}")
+			}
+			return nodes, text, nil
+		case strings.HasPrefix(expr, "range "):
+			path, err := parseJSONPath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			body, rest, err := parseJSONPathNodes(text, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{path: path, isRange: true, body: body})
+			text = rest
+		case strings.HasPrefix(expr, "\"") || strings.HasPrefix(expr, "'"):
+			literal, err := unquoteJSONPath(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{text: literal})
+		default:
+			path, err := parseJSONPath(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{path: path})
+		}
+	}
+	if inRange {
+		return nil, "", errors.New("jsonpath: {range} without {end}")
+	}
+	return nodes, "", nil
+}
+
+// closingBrace returns the index of the } closing the { at open, skipping
+// braces inside quotes.
+func closingBrace(text string, open int) (int, error) {
+	var quote byte
+	for i := open + 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("jsonpath: unclosed { in %q", text)
+}
+
+func unquoteJSONPath(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		s = "\"" + strings.ReplaceAll(strings.Trim(s, "'"), "\"", "\\\"") + "\""
+	}
+	out, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("jsonpath: invalid string literal %s", s)
+	}
+	return out, nil
+}
+
+func (t jsonPathTemplate) execute(sb *strings.Builder, root, current interface{}) error {
+	for _, node := range t {
+		switch {
+		case node.isRange:
+			for _, v := range evalJSONPath(node.path, root, current) {
+				if err := node.body.execute(sb, root, v); err != nil {
+					return err
+				}
+			}
+		case node.path != nil:
+			for i, v := range evalJSONPath(node.path, root, current) {
+				if i > 0 {
+					sb.WriteByte(' ')
+				}
+				s, err := formatJSONPathValue(v)
+				if err != nil {
+					return err
+				}
+				sb.WriteString(s)
+			}
+		default:
+			sb.WriteString(node.text)
+		}
+	}
+	return nil
+}
+
+// formatJSONPathValue prints scalars as plain text and objects as JSON.
+func formatJSONPathValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("jsonpath: failed to encode value: %w", err)
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// jsonPathStep is one step of a path: a field (optionally recursive), a
+// wildcard, an index or slice, or a filter.
+type jsonPathStep struct {
+	kind      string
+	field     string
+	start     *int
+	end       *int
+	filter    []jsonPathStep
+	op        string
+	operand   interface{}
+	recursive bool
+}
+
+const (
+	stepRoot     = "root"
+	stepField    = "field"
+	stepWildcard = "wildcard"
+	stepIndex    = "index"
+	stepSlice    = "slice"
+	stepFilter   = "filter"
+)
+
+// parseJSONPath parses a path such as ".items[*].name", "$.a..b" or
+// "@.servers[?(@.port==80)]".
+func parseJSONPath(expr string) ([]jsonPathStep, error) {
+	steps := []jsonPathStep{}
+	rest := expr
+	switch {
+	case strings.HasPrefix(rest, "$"):
+		steps = append(steps, jsonPathStep{kind: stepRoot})
+		rest = rest[1:]
+	case strings.HasPrefix(rest, "@"):
+		rest = rest[1:]
+	}
+
+	for rest != "" {
+		recursive := false
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			recursive = true
+			rest = rest[2:]
+		case rest[0] == '.':
+			rest = rest[1:]
+		}
+		if rest == "" {
+			break
+		}
+
+		if rest[0] == '[' {
+			end := matchingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: unclosed [ in %q", expr)
+			}
+			step, err := parseJSONPathBracket(rest[1:end])
+			if err != nil {
+				return nil, err
+			}
+			step.recursive = recursive
+			steps = append(steps, step)
+			rest = rest[end+1:]
+			continue
+		}
+
+		n := strings.IndexAny(rest, ".[")
+		if n < 0 {
+			n = len(rest)
+		}
+		name := rest[:n]
+		rest = rest[n:]
+		if name == "*" {
+			steps = append(steps, jsonPathStep{kind: stepWildcard, recursive: recursive})
+			continue
+		}
+		steps = append(steps, jsonPathStep{kind: stepField, field: name, recursive: recursive})
+	}
+	return steps, nil
+}
+
+// matchingBracket returns the index of the ] closing the [ at s[0].
+func matchingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseJSONPathBracket(inner string) (jsonPathStep, error) {
+	inner = strings.TrimSpace(inner)
+	switch {
+	case inner == "*":
+		return jsonPathStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		return parseJSONPathFilter(inner[2 : len(inner)-1])
+	case strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, "\""):
+		name, err := unquoteJSONPath(inner)
+		if err != nil {
+			return jsonPathStep{}, err
+		}
+		return jsonPathStep{kind: stepField, field: name}, nil
+	case strings.Contains(inner, ":"):
+		parts := strings.SplitN(inner, ":", 2)
+		step := jsonPathStep{kind: stepSlice}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return jsonPathStep{}, fmt.Errorf("jsonpath: invalid slice [%s]", inner)
+			}
+			if i == 0 {
+				step.start = &n
+			} else {
+				step.end = &n
+			}
+		}
+		return step, nil
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("jsonpath: invalid index [%s]", inner)
+		}
+		return jsonPathStep{kind: stepIndex, start: &n}, nil
+	}
+}
+
+// parseJSONPathFilter parses "@.path", "@.path==value" and the other
+// comparison operators. Values are quoted strings, numbers or booleans.
+func parseJSONPathFilter(expr string) (jsonPathStep, error) {
+	step := jsonPathStep{kind: stepFilter}
+	left := expr
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if i := strings.Index(expr, op); i >= 0 {
+			step.op = op
+			left = expr[:i]
+			value := strings.TrimSpace(expr[i+len(op):])
+			operand, err := parseJSONPathOperand(value)
+			if err != nil {
+				return step, err
+			}
+			step.operand = operand
+			break
+		}
+	}
+	left = strings.TrimSpace(left)
+	if !strings.HasPrefix(left, "@") {
+		return step, fmt.Errorf("jsonpath: filter must start with @: %q", expr)
+	}
+	path, err := parseJSONPath(left)
+	if err != nil {
+		return step, err
+	}
+	step.filter = path
+	return step, nil
+}
+
+func parseJSONPathOperand(value string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(value, "'") || strings.HasPrefix(value, "\""):
+		return unquoteJSONPath(value)
+	case value == "true" || value == "false":
+		return value == "true", nil
+	default:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("jsonpath: invalid filter value %q", value)
+		}
+		return f, nil
+	}
+}
+
+// evalJSONPath returns every value path selects, starting at current.
+func evalJSONPath(path []jsonPathStep, root, current interface{}) []interface{} {
+	values := []interface{}{current}
+	for _, step := range path {
+		var next []interface{}
+		for _, v := range values {
+			if step.kind == stepRoot {
+				next = append(next, root)
+				continue
+			}
+			candidates := []interface{}{v}
+			if step.recursive {
+				candidates = descendants(v)
+			}
+			for _, c := range candidates {
+				next = append(next, applyJSONPathStep(step, root, c)...)
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// descendants returns v and every value nested inside it, depth first.
+func descendants(v interface{}) []interface{} {
+	out := []interface{}{v}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedMapKeys(v) {
+			out = append(out, descendants(v[key])...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			out = append(out, descendants(item)...)
+		}
+	}
+	return out
+}
+
+func applyJSONPathStep(step jsonPathStep, root, v interface{}) []interface{} {
+	switch step.kind {
+	case stepField:
+		if m, ok := v.(map[string]interface{}); ok {
+			if value, ok := m[step.field]; ok {
+				return []interface{}{value}
+			}
+		}
+	case stepWildcard:
+		switch v := v.(type) {
+		case map[string]interface{}:
+			out := make([]interface{}, 0, len(v))
+			for _, key := range sortedMapKeys(v) {
+				out = append(out, v[key])
+			}
+			return out
+		case []interface{}:
+			return v
+		}
+	case stepIndex:
+		if list, ok := v.([]interface{}); ok {
+			i := *step.start
+			if i < 0 {
+				i += len(list)
+			}
+			if i >= 0 && i < len(list) {
+				return []interface{}{list[i]}
+			}
+		}
+	case stepSlice:
+		if list, ok := v.([]interface{}); ok {
+			start, end := 0, len(list)
+			if step.start != nil {
+				start = clampIndex(*step.start, len(list))
+			}
+			if step.end != nil {
+				end = clampIndex(*step.end, len(list))
+			}
+			if start < end {
+				return list[start:end]
+			}
+		}
+	case stepFilter:
+		if list, ok := v.([]interface{}); ok {
+			var out []interface{}
+			for _, item := range list {
+				if matchesJSONPathFilter(step, root, item) {
+					out = append(out, item)
+				}
+			}
+			return out
+		}
+	}
+	return nil
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return max(0, min(i, n))
+}
+
+func matchesJSONPathFilter(step jsonPathStep, root, item interface{}) bool {
+	values := evalJSONPath(step.filter, root, item)
+	if step.op == "" {
+		return len(values) > 0
+	}
+	for _, v := range values {
+		if compareJSONPath(v, step.op, step.operand) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareJSONPath(v interface{}, op string, operand interface{}) bool {
+	var c int
+	if want, ok := operand.(float64); ok {
+		got, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil {
+			return op == "!="
+		}
+		c = cmp.Compare(got, want)
+	} else {
+		c = strings.Compare(fmt.Sprint(v), fmt.Sprint(operand))
+	}
+
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+// sortedMapKeys returns the keys of m in order, so wildcards and recursive
+// descent produce stable output.
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
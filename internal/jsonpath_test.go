@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrintJSONPath(t *testing.T) {
+	t.Parallel()
+
+	backends := []map[string]interface{}{
+		{"name": "api", "mode": "http", "servers": []interface{}{
+			map[string]interface{}{"name": "a1", "port": float64(8080)},
+			map[string]interface{}{"name": "a2", "port": float64(9090)},
+		}},
+		{"name": "web", "mode": "tcp"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "list wildcard", template: "{.items[*].name}", want: "api web"},
+		{name: "index", template: "{.items[1].mode}", want: "tcp"},
+		{name: "negative index", template: "{.items[-1].name}", want: "web"},
+		{name: "slice", template: "{.items[0:1].name}", want: "api"},
+		{name: "recursive", template: "{..port}", want: "8080 9090"},
+		{name: "filter", template: `{.items[?(@.mode=="tcp")].name}`, want: "web"},
+		{name: "numeric filter", template: "{.items[*].servers[?(@.port>8080)].name}", want: "a2"},
+		{name: "bracket field", template: "{.items[0]['mode']}", want: "http"},
+		{name: "text and literals", template: `names: {.items[*].name}{"\n"}`, want: "names: api web\n"},
+		{name: "range", template: `{range .items[*]}{.name}={.mode}{"\n"}{end}`, want: "api=http\nweb=tcp\n"},
+		{name: "object as JSON", template: "{.items[0].servers[0]}", want: `{"name":"a1","port":8080}`},
+		{name: "missing field", template: "{.items[*].balance}", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out strings.Builder
+			if err := printJSONPath(&out, backends, "jsonpath="+tt.template); err != nil {
+				t.Fatalf("printJSONPath(%q) error: %v", tt.template, err)
+			}
+			if out.String() != tt.want {
+				t.Fatalf("printJSONPath(%q) = %q, want %q", tt.template, out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintJSONPath_InvalidTemplates(t *testing.T) {
+	t.Parallel()
+
+	for _, template := range []string{"{.items[*].name", "{range .items[*]}{.name}", "{end}", "{.items[x]}"} {
+		var out strings.Builder
+		if err := printJSONPath(&out, map[string]interface{}{}, "jsonpath="+template); err == nil {
+			t.Fatalf("expected error for %q", template)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// keyringService is the service name haproxyctl's secrets are stored under.
+const keyringService = "haproxyctl"
+
+const keyringTimeout = 10 * time.Second
+
+// ErrKeyringUnavailable is returned when no supported system keyring was
+// found; callers fall back to storing the password in the config file.
+var ErrKeyringUnavailable = errors.New("no system keyring available")
+
+// keyring stores secrets by account name.
+type keyring interface {
+	Set(account, secret string) error
+	Get(account string) (string, error)
+	Delete(account string) error
+}
+
+// systemKeyring is the keyring of the current OS, or nil. Tests replace it.
+var systemKeyring = detectKeyring()
+
+// detectKeyring picks the macOS keychain (security) or the freedesktop
+// Secret Service (secret-tool) when its command line tool is installed, and
+// the Windows Credential Manager on Windows.
+func detectKeyring() keyring {
+	switch runtime.GOOS {
+	case "windows":
+		return windowsKeyring()
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return keychain{}
+		}
+	case "linux", "freebsd", "openbsd":
+		if _, err := exec.LookPath("secret-tool"); err == nil {
+			return secretService{}
+		}
+	}
+	return nil
+}
+
+// StoreKeyringSecret stores secret under account in the system keyring.
+func StoreKeyringSecret(account, secret string) error {
+	if systemKeyring == nil {
+		return ErrKeyringUnavailable
+	}
+	return systemKeyring.Set(account, secret)
+}
+
+// DeleteKeyringSecret removes account from the system keyring.
+func DeleteKeyringSecret(account string) error {
+	if systemKeyring == nil {
+		return ErrKeyringUnavailable
+	}
+	return systemKeyring.Delete(account)
+}
+
+// withKeyringPassword fills in the password of an endpoint whose password
+// is kept in the keyring (see Config.KeyringAccount).
+func (c Config) withKeyringPassword() (Config, error) {
+	if c.KeyringAccount == "" || c.Password != "" {
+		return c, nil
+	}
+	if systemKeyring == nil {
+		return c, fmt.Errorf("password of %q is stored in the keyring: %w", c.KeyringAccount, ErrKeyringUnavailable)
+	}
+	password, err := systemKeyring.Get(c.KeyringAccount)
+	if err != nil {
+		return c, fmt.Errorf("failed to read password of %q from the keyring: %w", c.KeyringAccount, err)
+	}
+	c.Password = password
+	return c, nil
+}
+
+// runKeyringTool runs a keyring command line tool with stdin as input and
+// returns its stdout.
+func runKeyringTool(stdin string, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), keyringTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// trimNewline removes the newline a keyring tool prints after a secret.
+// Other whitespace is part of the secret and kept.
+func trimNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
+
+// keychain uses the macOS "security" tool.
+type keychain struct{}
+
+func (keychain) Set(account, secret string) error {
+	// Passed as a command on stdin ("security -i"), so the secret does not
+	// show up in the process list.
+	command, err := keychainAddCommand(account, secret)
+	if err != nil {
+		return err
+	}
+	_, err = runKeyringTool(command, "security", "-i")
+	return err
+}
+
+// keychainAddCommand returns the "security -i" command line that stores
+// secret under account.
+func keychainAddCommand(account, secret string) (string, error) {
+	if strings.ContainsAny(account+secret, "\r\n") {
+		return "", errors.New("the macOS keychain cannot store secrets containing line breaks")
+	}
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return fmt.Sprintf("add-generic-password -U -s %s -a \"%s\" -w \"%s\"\n",
+		keyringService, quote.Replace(account), quote.Replace(secret)), nil
+}
+
+func (keychain) Get(account string) (string, error) {
+	secret, err := runKeyringTool("", "security", "find-generic-password", "-s", keyringService, "-a", account, "-w")
+	return trimNewline(secret), err
+}
+
+func (keychain) Delete(account string) error {
+	_, err := runKeyringTool("", "security", "delete-generic-password", "-s", keyringService, "-a", account)
+	return err
+}
+
+// secretService uses libsecret's "secret-tool".
+type secretService struct{}
+
+func (secretService) Set(account, secret string) error {
+	_, err := runKeyringTool(secret, "secret-tool",
+		"store", "--label", keyringService+" "+account, "service", keyringService, "account", account)
+	return err
+}
+
+func (secretService) Get(account string) (string, error) {
+	secret, err := runKeyringTool("", "secret-tool", "lookup", "service", keyringService, "account", account)
+	secret = trimNewline(secret)
+	if err == nil && secret == "" {
+		return "", fmt.Errorf("no secret stored for %q", account)
+	}
+	return secret, err
+}
+
+func (secretService) Delete(account string) error {
+	_, err := runKeyringTool("", "secret-tool", "clear", "service", keyringService, "account", account)
+	return err
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadConfig_KeyringPassword(t *testing.T) {
+	kr := map[string]string{}
+	defer SetKeyring(kr)()
+	if err := StoreKeyringSecret("prod", "from-keyring"); err != nil {
+		t.Fatalf("StoreKeyringSecret failed: %v", err)
+	}
+
+	cfg := Config{APIBaseURL: "http://prod:5555", Username: "admin", KeyringAccount: "prod"}
+	got, err := cfg.withKeyringPassword()
+	if err != nil || got.Password != "from-keyring" {
+		t.Fatalf("withKeyringPassword() = %+v, %v", got, err)
+	}
+
+	if err := DeleteKeyringSecret("prod"); err != nil || len(kr) != 0 {
+		t.Fatalf("DeleteKeyringSecret failed: %v (%v)", err, kr)
+	}
+	if _, err := cfg.withKeyringPassword(); err == nil {
+		t.Fatalf("expected an error for a missing keyring entry")
+	}
+
+	systemKeyring = nil
+	if _, err := cfg.withKeyringPassword(); !errors.Is(err, ErrKeyringUnavailable) {
+		t.Fatalf("withKeyringPassword() without keyring error = %v", err)
+	}
+	plain := Config{Password: "secret"}
+	if got, err := plain.withKeyringPassword(); err != nil || got.Password != "secret" {
+		t.Fatalf("plaintext password = %+v, %v", got, err)
+	}
+}
+
+func TestKeychainAddCommand(t *testing.T) {
+	got, err := keychainAddCommand("prod", `p"a\ss word`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `add-generic-password -U -s haproxyctl -a "prod" -w "p\"a\\ss word"` + "\n"
+	if got != want {
+		t.Fatalf("keychainAddCommand() = %q, want %q", got, want)
+	}
+	if _, err := keychainAddCommand("prod", "two\nlines"); err == nil {
+		t.Fatal("expected an error for a secret with a line break")
+	}
+}
+
+func TestTrimNewline(t *testing.T) {
+	for in, want := range map[string]string{
+		"secret\n":   "secret",
+		" secret \n": " secret ",
+		"secret\r\n": "secret",
+		"secret \t":  "secret \t",
+		"":           "",
+	} {
+		if got := trimNewline(in); got != want {
+			t.Errorf("trimNewline(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Selector is a parsed label selector such as "team=web,tier!=edge,canary".
+// A manifest matches when it satisfies every requirement.
+type Selector []labelRequirement
+
+type labelRequirement struct {
+	key   string
+	op    string // "=", "!=", "exists" or "!exists"
+	value string
+}
+
+// ParseSelector parses a comma-separated list of key=value, key==value,
+// key!=value, key (label present) and !key (label absent) requirements.
+// An empty string selects everything.
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var req labelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			req = labelRequirement{key: key, op: "!=", value: value}
+		case strings.Contains(part, "=="):
+			key, value, _ := strings.Cut(part, "==")
+			req = labelRequirement{key: key, op: "=", value: value}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			req = labelRequirement{key: key, op: "=", value: value}
+		case strings.HasPrefix(part, "!"):
+			req = labelRequirement{key: part[1:], op: "!exists"}
+		default:
+			req = labelRequirement{key: part, op: "exists"}
+		}
+		req.key, req.value = strings.TrimSpace(req.key), strings.TrimSpace(req.value)
+		if req.key == "" {
+			return nil, fmt.Errorf("invalid selector %q: missing label key", part)
+		}
+		sel = append(sel, req)
+	}
+	return sel, nil
+}
+
+// Matches reports whether labels satisfy every requirement of s.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, ok := labels[req.key]
+		switch req.op {
+		case "=":
+			if !ok || value != req.value {
+				return false
+			}
+		case "!=":
+			if ok && value == req.value {
+				return false
+			}
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "!exists":
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
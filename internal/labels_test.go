@@ -0,0 +1,42 @@
+package internal
+
+import "testing"
+
+func TestSelectorMatches(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"team": "web", "tier": "edge"}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"team=web", true},
+		{"team==web", true},
+		{"team=api", false},
+		{"team=web,tier!=edge", false},
+		{"team=web, tier!=core", true},
+		{"tier", true},
+		{"canary", false},
+		{"!canary", true},
+		{"!team", false},
+	}
+	for _, tt := range tests {
+		sel, err := ParseSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("ParseSelector(%q) error = %v", tt.selector, err)
+		}
+		if got := sel.Matches(labels); got != tt.want {
+			t.Errorf("%q.Matches(%v) = %v, want %v", tt.selector, labels, got, tt.want)
+		}
+	}
+
+	if sel, _ := ParseSelector("team=web"); sel.Matches(nil) {
+		t.Error("a v1 manifest without labels must not match team=web")
+	}
+	for _, bad := range []string{"=web", "!", "!=x"} {
+		if _, err := ParseSelector(bad); err == nil {
+			t.Errorf("ParseSelector(%q): expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// stateDirOverride, when set, replaces the directory local state files are
+// kept in (next to config.json by default). Tests point it at a temp dir.
+var stateDirOverride string
+
+// SetStateDir makes local state files live in dir. It returns a function
+// that restores the previous directory.
+func SetStateDir(dir string) func() {
+	previous := stateDirOverride
+	stateDirOverride = dir
+	return func() { stateDirOverride = previous }
+}
+
+func stateDir() string {
+	if stateDirOverride != "" {
+		return stateDirOverride
+	}
+	return filepath.Dir(configFilePath)
+}
+
+// ReadStateFile decodes the JSON state file name into v. A missing file is
+// not an error and leaves v untouched.
+func ReadStateFile(name string, v interface{}) error {
+	data, err := os.ReadFile(filepath.Join(stateDir(), name)) //nolint:gosec // name is a fixed file name chosen by the caller
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read state file %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse state file %s: %w", name, err)
+	}
+	return nil
+}
+
+// WriteStateFile stores v as JSON in the state file name, readable only by
+// the current user.
+func WriteStateFile(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state file %s: %w", name, err)
+	}
+	dir := stateDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxVerbosity is the highest useful value of the global -v flag:
+//
+//	0  warnings and errors (the default)
+//	1  retries, replays and other decisions made along the way
+//	2  every Data Plane API call: method, path, status and duration
+//	3  also the query parameters and body sizes
+//	4  also the request and response bodies, credentials redacted
+const MaxVerbosity = 4
+
+var (
+	verbosity int
+	logLevel            = new(slog.LevelVar)
+	logOutput io.Writer = os.Stderr
+	logger              = newLogger(os.Stderr)
+)
+
+func init() {
+	logLevel.Set(verbosityLevel(0))
+}
+
+// SetVerbosity sets how much haproxyctl logs to stderr (see MaxVerbosity).
+func SetVerbosity(v int) {
+	verbosity = min(max(v, 0), MaxVerbosity)
+	logLevel.Set(verbosityLevel(verbosity))
+}
+
+// V reports whether messages of verbosity level are logged.
+func V(level int) bool {
+	return verbosity >= level
+}
+
+// SetLogOutput makes log messages go to w. It returns a function that
+// restores the previous logger; tests use it to capture the log.
+func SetLogOutput(w io.Writer) func() {
+	previousOutput, previous := logOutput, logger
+	logOutput, logger = w, newLogger(w)
+	return func() { logOutput, logger = previousOutput, previous }
+}
+
+// verbosityLevel maps a verbosity to the slog level of its messages:
+// verbosity 0 is slog.LevelWarn, and each step below it is 4 lower.
+func verbosityLevel(v int) slog.Level {
+	return slog.LevelWarn - slog.Level(4*v)
+}
+
+func newLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: logLevel,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.TimeKey:
+				return slog.Attr{}
+			case slog.LevelKey:
+				if level, ok := a.Value.Any().(slog.Level); ok && level < slog.LevelWarn {
+					return slog.String(slog.LevelKey, "V"+strconv.Itoa(int(slog.LevelWarn-level)/4))
+				}
+			}
+			return a
+		},
+	}))
+}
+
+// Logf logs a message of verbosity level (see MaxVerbosity).
+func Logf(level int, format string, args ...interface{}) {
+	logger.Log(context.Background(), verbosityLevel(level), fmt.Sprintf(format, args...))
+}
+
+// logAttrs logs msg with structured attributes at verbosity level.
+func logAttrs(level int, msg string, attrs ...slog.Attr) {
+	logger.LogAttrs(context.Background(), verbosityLevel(level), msg, attrs...)
+}
+
+// Warnf logs a warning; warnings are always shown, as a plain
+// "warning: ..." line rather than in the diagnostic format of Logf.
+func Warnf(format string, args ...interface{}) {
+	printLine("warning", fmt.Sprintf(format, args...))
+}
+
+// LogErrorf logs an error, as a plain "error: ..." line, without exiting.
+func LogErrorf(format string, args ...interface{}) {
+	printLine("error", fmt.Sprintf(format, args...))
+}
+
+// printLine writes msg to the log output as "<prefix>: msg".
+func printLine(prefix, msg string) {
+	_, _ = fmt.Fprintf(logOutput, "%s: %s\n", prefix, strings.TrimRight(msg, "\n"))
+}
+
+// Fatalf reports an error in the selected error format (see
+// SetErrorFormat) and exits, like log.Fatalf, with the exit code of the
+// last error in args (see ExitCode), or ExitError without one.
+func Fatalf(format string, args ...interface{}) {
+	os.Exit(reportError(0, format, args...))
+}
+
+// FatalCodef is Fatalf exiting with code, e.g. ExitUsage for a missing or
+// invalid flag.
+func FatalCodef(code int, format string, args ...interface{}) {
+	os.Exit(reportError(code, format, args...))
+}
+
+// maxLoggedBody is how much of a request or response body verbosity 4
+// logs.
+const maxLoggedBody = 4096
+
+// loggingTransport logs every Data Plane API call at verbosity 2 and up
+// (see MaxVerbosity), and dumps it whole with --debug (see SetDebug).
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !V(2) && !debugHTTP {
+		return t.next.RoundTrip(req)
+	}
+	if debugHTTP {
+		dumpRequest(logOutput, req)
+	}
+
+	attrs := []slog.Attr{slog.String("method", req.Method), slog.String("path", req.URL.Path)}
+	if V(3) {
+		attrs = append(attrs, slog.String("query", req.URL.RawQuery), slog.Int64("request_bytes", req.ContentLength))
+	}
+	if V(4) && req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			data, _ := readBody(&rc)
+			attrs = append(attrs, slog.String("request_body", loggedBody(req.Header, data)))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+	attrs = append(attrs, slog.Duration("duration", elapsed))
+	if err != nil {
+		if debugHTTP {
+			_, _ = fmt.Fprintf(logOutput, "<-- %v (%s)\n", err, elapsed.Round(time.Microsecond))
+		}
+		logAttrs(2, "api call failed", append(attrs, slog.String("error", err.Error()))...)
+		return resp, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if V(3) {
+		attrs = append(attrs, slog.Int64("response_bytes", resp.ContentLength))
+	}
+	if debugHTTP || V(4) {
+		data, rerr := readBody(&resp.Body)
+		if rerr != nil {
+			return resp, rerr
+		}
+		if V(4) {
+			attrs = append(attrs, slog.String("response_body", loggedBody(resp.Header, data)))
+		}
+		if debugHTTP {
+			dumpResponse(logOutput, resp, data, elapsed)
+		}
+	}
+	logAttrs(2, "api call", attrs...)
+	return resp, nil
+}
+
+// loggedBody returns a body as logged at verbosity 4: sanitized (see
+// sanitizeBody) and cut after maxLoggedBody bytes.
+func loggedBody(header http.Header, data []byte) string {
+	s := sanitizeBody(header, data, false)
+	if len(s) > maxLoggedBody {
+		return s[:maxLoggedBody] + "..."
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoggingTransport_Verbosity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"app"}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	defer SetLogOutput(&buf)()
+	defer SetVerbosity(0)
+
+	client := &http.Client{Transport: &loggingTransport{next: http.DefaultTransport}}
+	send := func() {
+		t.Helper()
+		resp, err := client.Post(srv.URL+"/v3/services/haproxy/configuration/backends?version=1", "application/json", strings.NewReader(`{"name":"app"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	send()
+	if buf.Len() != 0 {
+		t.Fatalf("logged at verbosity 0:\n%s", buf.String())
+	}
+
+	SetVerbosity(2)
+	send()
+	line := buf.String()
+	for _, want := range []string{"level=V2", "method=POST", "path=/v3/services/haproxy/configuration/backends", "status=201", "duration="} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log line lacks %q:\n%s", want, line)
+		}
+	}
+	if strings.Contains(line, "query=") || strings.Contains(line, "time=") {
+		t.Errorf("verbosity 2 logged more than the call summary:\n%s", line)
+	}
+
+	buf.Reset()
+	SetVerbosity(4)
+	send()
+	if line := buf.String(); !strings.Contains(line, `query="version=1"`) || !strings.Contains(line, `response_body="{\"name\":\"app\"}"`) {
+		t.Errorf("verbosity 4 lacks query or body:\n%s", line)
+	}
+}
+
+func TestWarnf_AlwaysShown(t *testing.T) {
+	var buf bytes.Buffer
+	defer SetLogOutput(&buf)()
+
+	Logf(1, "hidden")
+	Warnf("disk %s", "full")
+	LogErrorf("failed:\n%s\n", "output")
+	if got := buf.String(); got != "warning: disk full\nerror: failed:\noutput\n" {
+		t.Fatalf("log = %q", got)
+	}
+}
@@ -0,0 +1,439 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Manifest API versions. haproxyctl/v1 documents keep every field at the
+// top level; haproxyctl/v2 ones nest the name, labels and annotations under
+// metadata and the object under spec.
+const (
+	APIVersionV1 = "haproxyctl/v1"
+	APIVersionV2 = "haproxyctl/v2"
+)
+
+// Manifest is a single YAML document from a manifest file.
+type Manifest struct {
+	APIVersion string
+	Kind       string
+	// Labels and Annotations come from the metadata of a haproxyctl/v2
+	// document; v1 documents have none.
+	Labels      map[string]string
+	Annotations map[string]string
+	// Data is the YAML of this document alone in the haproxyctl/v1 layout,
+	// ready for the per-kind loaders.
+	Data []byte
+	// Source and Index (1-based) locate the document for error messages;
+	// Item (1-based) is its position in the items of a kind: List
+	// document, or 0.
+	Source string
+	Index  int
+	Item   int
+}
+
+// ListKind is the kind of a document holding other documents under items,
+// as "get all -o yaml" writes it.
+const ListKind = "List"
+
+// String identifies the document, e.g. "all.yaml (document 2, Backend)" or
+// "all.yaml (document 1, item 3, Server)".
+func (m Manifest) String() string {
+	if m.Item > 0 {
+		return fmt.Sprintf("%s (document %d, item %d, %s)", m.Source, m.Index, m.Item, m.Kind)
+	}
+	return fmt.Sprintf("%s (document %d, %s)", m.Source, m.Index, m.Kind)
+}
+
+// ParseManifests splits data into its "---" separated YAML documents and
+// reads the apiVersion and kind of each. Empty documents are skipped, and
+// a kind: List document stands for the documents in its items.
+//
+// JSON is accepted too: a source ending in ".json", or content starting
+// with "{" or "[", holds one object, a list of objects, or a stream of
+// objects, each of which becomes one document.
+func ParseManifests(data []byte, source string) ([]Manifest, error) {
+	switch {
+	case strings.EqualFold(path.Ext(source), ".json"):
+		return parseJSONManifests(data, source)
+	case looksLikeJSON(data):
+		// A YAML flow mapping starts with "{" too; fall back to YAML
+		// when the content is not valid JSON.
+		if manifests, err := parseJSONManifests(data, source); err == nil {
+			return manifests, nil
+		}
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	var manifests []Manifest
+	for index := 1; ; index++ {
+		var doc yaml.MapSlice
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s (document %d): %w", source, index, err)
+		}
+		if len(doc) == 0 {
+			continue
+		}
+
+		docManifests, err := documentManifests(doc, source, index)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, docManifests...)
+	}
+	return manifests, nil
+}
+
+// documentManifests returns the Manifest of one decoded document, or those
+// of its items when it is a kind: List.
+func documentManifests(doc yaml.MapSlice, source string, index int) ([]Manifest, error) {
+	var (
+		kind  string
+		items interface{}
+	)
+	for _, item := range doc {
+		switch item.Key {
+		case "kind":
+			kind, _ = item.Value.(string)
+		case "items":
+			items = item.Value
+		}
+	}
+	if kind != ListKind {
+		m, err := newManifest(doc, source, index)
+		if err != nil {
+			return nil, err
+		}
+		return []Manifest{m}, nil
+	}
+
+	list, ok := items.([]interface{})
+	if !ok && items != nil {
+		return nil, fmt.Errorf("%s (document %d): items of a %s must be a list", source, index, ListKind)
+	}
+	manifests := make([]Manifest, 0, len(list))
+	for i, raw := range list {
+		item, ok := raw.(yaml.MapSlice)
+		if !ok {
+			return nil, fmt.Errorf("%s (document %d, item %d): expected a mapping", source, index, i+1)
+		}
+		if len(item) == 0 {
+			continue
+		}
+		m, err := newManifest(item, source, index)
+		if err != nil {
+			return nil, err
+		}
+		if m.Kind == ListKind {
+			return nil, fmt.Errorf("%s (document %d, item %d): a %s cannot contain another %s", source, index, i+1, ListKind, ListKind)
+		}
+		m.Item = i + 1
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
+// newManifest builds the Manifest of one decoded document, flattening a
+// haproxyctl/v2 document into the v1 layout.
+func newManifest(doc yaml.MapSlice, source string, index int) (Manifest, error) {
+	m := Manifest{Source: source, Index: index}
+	for _, item := range doc {
+		switch item.Key {
+		case "apiVersion":
+			m.APIVersion, _ = item.Value.(string)
+		case "kind":
+			m.Kind, _ = item.Value.(string)
+		}
+	}
+
+	if m.APIVersion == APIVersionV2 {
+		var err error
+		if doc, err = flattenV2(doc, &m); err != nil {
+			return m, fmt.Errorf("%s (document %d): %w", source, index, err)
+		}
+	}
+
+	raw, err := yaml.Marshal(doc)
+	if err != nil {
+		return m, fmt.Errorf("failed to re-encode %s (document %d): %w", source, index, err)
+	}
+	m.Data = raw
+	return m, nil
+}
+
+// flattenV2 turns a haproxyctl/v2 document into the equivalent v1 one:
+// metadata.name becomes name, the spec fields move to the top level, and
+// the labels and annotations are recorded on m.
+func flattenV2(doc yaml.MapSlice, m *Manifest) (yaml.MapSlice, error) {
+	var metadata struct {
+		Name        string            `yaml:"name"`
+		Labels      map[string]string `yaml:"labels"`
+		Annotations map[string]string `yaml:"annotations"`
+	}
+	var spec yaml.MapSlice
+
+	for _, item := range doc {
+		switch item.Key {
+		case "apiVersion", "kind":
+		case "metadata":
+			raw, err := yaml.Marshal(item.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid metadata: %w", err)
+			}
+			if err := yaml.UnmarshalStrict(raw, &metadata); err != nil {
+				return nil, fmt.Errorf("invalid metadata (name, labels, annotations): %w", err)
+			}
+		case "spec":
+			if item.Value == nil {
+				continue
+			}
+			var ok bool
+			if spec, ok = item.Value.(yaml.MapSlice); !ok {
+				return nil, errors.New("spec must be a mapping")
+			}
+		default:
+			return nil, fmt.Errorf("unexpected top-level field %q (%s keeps the object under spec)", item.Key, APIVersionV2)
+		}
+	}
+	for key := range metadata.Labels {
+		if key == "" {
+			return nil, errors.New("label keys must not be empty")
+		}
+	}
+
+	flat := yaml.MapSlice{
+		{Key: "apiVersion", Value: APIVersionV1},
+		{Key: "kind", Value: m.Kind},
+	}
+	if metadata.Name != "" {
+		flat = append(flat, yaml.MapItem{Key: "name", Value: metadata.Name})
+	}
+	for _, item := range spec {
+		switch item.Key {
+		case "apiVersion", "kind", "name":
+			return nil, fmt.Errorf("spec must not set %q (it belongs at the top level or under metadata)", item.Key)
+		}
+		flat = append(flat, item)
+	}
+
+	m.Labels, m.Annotations = metadata.Labels, metadata.Annotations
+	return flat, nil
+}
+
+// looksLikeJSON reports whether data starts like a JSON object or array.
+func looksLikeJSON(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// parseJSONManifests reads JSON manifests and re-encodes each as YAML, so
+// the per-kind loaders see the same Data whatever the input format.
+func parseJSONManifests(data []byte, source string) ([]Manifest, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var docs []interface{}
+	for {
+		var v interface{}
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s as JSON: %w", source, err)
+		}
+		if list, ok := v.([]interface{}); ok {
+			docs = append(docs, list...)
+		} else {
+			docs = append(docs, v)
+		}
+	}
+
+	var manifests []Manifest
+	for i, doc := range docs {
+		index := i + 1
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("failed to parse %s (document %d): expected a JSON object", source, index)
+		}
+		if len(obj) == 0 {
+			continue
+		}
+
+		raw, err := yaml.Marshal(jsonNumbers(obj))
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encode %s (document %d): %w", source, index, err)
+		}
+		var slice yaml.MapSlice
+		if err := yaml.Unmarshal(raw, &slice); err != nil {
+			return nil, fmt.Errorf("failed to re-encode %s (document %d): %w", source, index, err)
+		}
+
+		docManifests, err := documentManifests(slice, source, index)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, docManifests...)
+	}
+	return manifests, nil
+}
+
+// jsonNumbers replaces the json.Number values of a decoded document with
+// int64 or float64, so integers stay integers in the YAML encoding.
+func jsonNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = jsonNumbers(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonNumbers(item)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// kindOrder is the order kinds are applied in so that references resolve:
+// log targets need their ring, SPOE filters their file, servers their
+// backend and resolvers, frontends their default_backend, ACLs the section
+// they belong to and HTTP rules the ACLs they use.
+var kindOrder = []string{"ring", "logforward", "global", "defaults", "userlist", "resolver", "cache", "httperrors", "spoe", "backend", "server", "frontend", "acl", "httprequestrule", "httpresponserule"}
+
+func kindRank(kind string) int {
+	for i, k := range kindOrder {
+		if strings.EqualFold(k, kind) {
+			return i
+		}
+	}
+	return len(kindOrder)
+}
+
+// SortManifestsByKind orders manifests by kind dependency, keeping the
+// file order within each kind. Defaults sections are further ordered so
+// that a section follows the one it inherits from.
+func SortManifestsByKind(manifests []Manifest) {
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return kindRank(manifests[i].Kind) < kindRank(manifests[j].Kind)
+	})
+
+	start := sort.Search(len(manifests), func(i int) bool { return kindRank(manifests[i].Kind) >= kindRank("defaults") })
+	end := start
+	for end < len(manifests) && strings.EqualFold(manifests[end].Kind, "defaults") {
+		end++
+	}
+	orderByFrom(manifests[start:end])
+}
+
+// orderByFrom reorders defaults manifests in place so each comes after the
+// section named by its "from" key, keeping the file order otherwise.
+func orderByFrom(manifests []Manifest) {
+	type section struct {
+		Name string `yaml:"name"`
+		From string `yaml:"from"`
+	}
+	sections := make([]section, len(manifests))
+	index := make(map[string]int, len(manifests))
+	for i, m := range manifests {
+		_ = yaml.Unmarshal(m.Data, &sections[i])
+		if sections[i].Name != "" {
+			index[sections[i].Name] = i
+		}
+	}
+
+	ordered := make([]Manifest, 0, len(manifests))
+	done := make([]bool, len(manifests))
+	var visit func(i int)
+	visit = func(i int) {
+		if done[i] {
+			return
+		}
+		done[i] = true
+		if parent, ok := index[sections[i].From]; ok {
+			visit(parent)
+		}
+		ordered = append(ordered, manifests[i])
+	}
+	for i := range manifests {
+		visit(i)
+	}
+	copy(manifests, ordered)
+}
+
+// ManifestAsMap converts a typed manifest into a plain map by way of its
+// YAML form, so it renders with the same field names as JSON as it does
+// as YAML (several manifest types only carry yaml tags).
+func ManifestAsMap(manifest interface{}) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	m, ok := stringKeys(generic).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("manifest is a %T, not a mapping", generic)
+	}
+	return m, nil
+}
+
+// stringKeys turns the map[interface{}]interface{} values yaml.v2 decodes
+// into map[string]interface{}, recursively, so they can be JSON encoded.
+func stringKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = stringKeys(value)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = stringKeys(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
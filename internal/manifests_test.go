@@ -0,0 +1,271 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseManifestsSortsByKind(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`apiVersion: haproxyctl/v1
+kind: Frontend
+name: web
+default_backend: app
+---
+# comments and empty documents are skipped
+---
+apiVersion: haproxyctl/v1
+kind: Server
+name: app1
+parent:
+  type: backend
+  name: app
+---
+apiVersion: haproxyctl/v1
+kind: Backend
+name: app
+`)
+
+	manifests, err := ParseManifests(data, "all.yaml")
+	if err != nil {
+		t.Fatalf("ParseManifests() error = %v", err)
+	}
+	SortManifestsByKind(manifests)
+
+	var kinds []string
+	for _, m := range manifests {
+		kinds = append(kinds, m.Kind)
+	}
+	if got, want := strings.Join(kinds, ","), "Backend,Server,Frontend"; got != want {
+		t.Fatalf("kinds = %s, want %s", got, want)
+	}
+
+	if got, want := manifests[1].String(), "all.yaml (document 3, Server)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// Each document must decode on its own.
+	var server struct {
+		Name   string `yaml:"name"`
+		Parent struct {
+			Name string `yaml:"name"`
+		} `yaml:"parent"`
+	}
+	if err := yaml.Unmarshal(manifests[1].Data, &server); err != nil {
+		t.Fatalf("unmarshal server document: %v", err)
+	}
+	if server.Name != "app1" || server.Parent.Name != "app" {
+		t.Errorf("server document = %+v", server)
+	}
+}
+
+func TestParseManifestsInvalidDocument(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseManifests([]byte("kind: Backend\n---\nkind: [\n"), "bad.yaml")
+	if err == nil || !strings.Contains(err.Error(), "bad.yaml (document 2)") {
+		t.Fatalf("ParseManifests() error = %v, want document 2 error", err)
+	}
+}
+
+func TestParseManifestsList(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`apiVersion: haproxyctl/v1
+kind: List
+items:
+- apiVersion: haproxyctl/v1
+  kind: Backend
+  name: app
+- apiVersion: haproxyctl/v2
+  kind: Frontend
+  metadata:
+    name: web
+    labels: {team: web}
+  spec:
+    default_backend: app
+---
+apiVersion: haproxyctl/v1
+kind: Cache
+name: static
+`)
+	manifests, err := ParseManifests(data, "all.yaml")
+	if err != nil {
+		t.Fatalf("ParseManifests() error = %v", err)
+	}
+	if len(manifests) != 3 {
+		t.Fatalf("got %d manifests, want 3", len(manifests))
+	}
+	if got, want := manifests[1].String(), "all.yaml (document 1, item 2, Frontend)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if manifests[1].Labels["team"] != "web" || !strings.Contains(string(manifests[1].Data), "name: web") {
+		t.Errorf("v2 item not flattened: %+v\n%s", manifests[1], manifests[1].Data)
+	}
+	if got, want := manifests[2].String(), "all.yaml (document 2, Cache)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	jsonList := []byte(`{"apiVersion": "haproxyctl/v1", "kind": "List", "items": [{"apiVersion": "haproxyctl/v1", "kind": "Ring", "name": "logbuf"}]}`)
+	manifests, err = ParseManifests(jsonList, "all.json")
+	if err != nil || len(manifests) != 1 || manifests[0].Kind != "Ring" {
+		t.Fatalf("ParseManifests(JSON List) = %+v, %v", manifests, err)
+	}
+
+	for _, bad := range []string{
+		"kind: List\nitems: app\n",
+		"kind: List\nitems:\n- kind: List\n  items: []\n",
+	} {
+		if _, err := ParseManifests([]byte(bad), "bad.yaml"); err == nil {
+			t.Errorf("ParseManifests(%q): expected error", bad)
+		}
+	}
+}
+
+func TestParseManifestsJSON(t *testing.T) {
+	t.Parallel()
+
+	list := []byte(`[
+	{"apiVersion": "haproxyctl/v1", "kind": "Backend", "name": "app", "servers": [{"name": "app1", "port": 8080, "weight": 1000000}]},
+	{"apiVersion": "haproxyctl/v1", "kind": "Frontend", "name": "web"}
+]`)
+	stream := []byte(`{"apiVersion": "haproxyctl/v1", "kind": "Backend", "name": "app", "servers": [{"name": "app1", "port": 8080, "weight": 1000000}]}
+{"apiVersion": "haproxyctl/v1", "kind": "Frontend", "name": "web"}
+`)
+
+	for _, tt := range []struct {
+		source string
+		data   []byte
+	}{
+		{"all.json", list},
+		{"all.ndjson", stream},
+		{"https://example.com/manifests?ref=main", list},
+	} {
+		manifests, err := ParseManifests(tt.data, tt.source)
+		if err != nil {
+			t.Fatalf("%s: ParseManifests() error = %v", tt.source, err)
+		}
+		if len(manifests) != 2 || manifests[0].Kind != "Backend" || manifests[1].String() != tt.source+" (document 2, Frontend)" {
+			t.Fatalf("%s: manifests = %+v", tt.source, manifests)
+		}
+
+		var backend struct {
+			Servers []struct {
+				Port   int `yaml:"port"`
+				Weight int `yaml:"weight"`
+			} `yaml:"servers"`
+		}
+		if err := yaml.Unmarshal(manifests[0].Data, &backend); err != nil {
+			t.Fatalf("%s: unmarshal backend document: %v", tt.source, err)
+		}
+		if len(backend.Servers) != 1 || backend.Servers[0].Port != 8080 || backend.Servers[0].Weight != 1000000 {
+			t.Errorf("%s: backend document = %+v", tt.source, backend)
+		}
+	}
+
+	// A YAML flow mapping is not JSON but still parses.
+	manifests, err := ParseManifests([]byte("{apiVersion: haproxyctl/v1, kind: Cache, name: static}\n"), "cache.yaml")
+	if err != nil || len(manifests) != 1 || manifests[0].Kind != "Cache" {
+		t.Fatalf("flow mapping: manifests = %+v, err = %v", manifests, err)
+	}
+
+	if _, err := ParseManifests([]byte(`{"kind": "Backend",}`), "bad.json"); err == nil || !strings.Contains(err.Error(), "bad.json as JSON") {
+		t.Fatalf("ParseManifests() error = %v, want JSON error", err)
+	}
+}
+
+func TestParseManifestsV2(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`apiVersion: haproxyctl/v2
+kind: Backend
+metadata:
+  name: app
+  labels:
+    team: web
+    tier: 1
+  annotations:
+    owner: ops@example.com
+spec:
+  mode: http
+  servers:
+    - name: app1
+      address: 10.0.0.1
+      port: 8080
+`)
+
+	manifests, err := ParseManifests(data, "app.yaml")
+	if err != nil {
+		t.Fatalf("ParseManifests() error = %v", err)
+	}
+	m := manifests[0]
+	if m.APIVersion != APIVersionV2 || m.Kind != "Backend" {
+		t.Fatalf("manifest = %+v", m)
+	}
+	if m.Labels["team"] != "web" || m.Labels["tier"] != "1" || m.Annotations["owner"] != "ops@example.com" {
+		t.Fatalf("labels = %v, annotations = %v", m.Labels, m.Annotations)
+	}
+
+	var flat struct {
+		APIVersion string `yaml:"apiVersion"`
+		Name       string `yaml:"name"`
+		Mode       string `yaml:"mode"`
+		Servers    []struct {
+			Name string `yaml:"name"`
+		} `yaml:"servers"`
+	}
+	if err := yaml.Unmarshal(m.Data, &flat); err != nil {
+		t.Fatalf("unmarshal flattened document: %v", err)
+	}
+	if flat.APIVersion != APIVersionV1 || flat.Name != "app" || flat.Mode != "http" || len(flat.Servers) != 1 {
+		t.Fatalf("flattened document = %+v", flat)
+	}
+
+	for _, bad := range []string{
+		"apiVersion: haproxyctl/v2\nkind: Backend\nname: app\n",
+		"apiVersion: haproxyctl/v2\nkind: Backend\nmetadata: {name: app, owner: me}\n",
+		"apiVersion: haproxyctl/v2\nkind: Backend\nmetadata: {name: app}\nspec: {name: other}\n",
+		"apiVersion: haproxyctl/v2\nkind: Backend\nmetadata: {name: app}\nspec: [mode]\n",
+	} {
+		if _, err := ParseManifests([]byte(bad), "bad.yaml"); err == nil || !strings.Contains(err.Error(), "bad.yaml (document 1)") {
+			t.Errorf("ParseManifests(%q) error = %v, want document 1 error", bad, err)
+		}
+	}
+}
+
+func TestSortManifestsByKind_DefaultsFrom(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`kind: Backend
+name: app
+from: web
+---
+kind: Defaults
+name: api
+from: web
+---
+kind: Defaults
+name: web
+from: base
+---
+kind: Defaults
+name: base
+`)
+	manifests, err := ParseManifests(data, "all.yaml")
+	if err != nil {
+		t.Fatalf("ParseManifests() error = %v", err)
+	}
+	SortManifestsByKind(manifests)
+
+	var docs []int
+	for _, m := range manifests {
+		docs = append(docs, m.Index)
+	}
+	if got := fmt.Sprint(docs); got != "[4 3 2 1]" {
+		t.Fatalf("document order = %s, want [4 3 2 1]", got)
+	}
+}
@@ -0,0 +1,38 @@
+// Package internal contains shared helpers for haproxyctl.
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import "errors"
+
+// ErrOffline is returned by the request helpers when offline mode is
+// enabled, so no command can reach the Data Plane API by accident.
+var ErrOffline = errors.New("offline mode is enabled; refusing to contact the Data Plane API")
+
+// offlineMode is set once per process from the global --offline flag.
+var offlineMode bool
+
+// SetOffline enables or disables offline mode for the current process.
+func SetOffline(enabled bool) {
+	offlineMode = enabled
+}
+
+// IsOffline reports whether offline mode is enabled. Commands that can
+// render a manifest or payload locally treat offline mode as an implicit
+// --dry-run.
+func IsOffline() bool {
+	return offlineMode
+}
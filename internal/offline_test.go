@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOfflineModeBlocksRequests(t *testing.T) {
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+
+	if _, err := SendRequest("GET", "/services/haproxy/configuration/version", nil, nil); !errors.Is(err, ErrOffline) {
+		t.Fatalf("SendRequest error = %v, want ErrOffline", err)
+	}
+	if _, err := SendRawRequest("POST", "/services/haproxy/configuration/raw", nil, []byte("global"), "text/plain"); !errors.Is(err, ErrOffline) {
+		t.Fatalf("SendRawRequest error = %v, want ErrOffline", err)
+	}
+	if err := UploadSSLCertificate("example", []byte("pem")); !errors.Is(err, ErrOffline) {
+		t.Fatalf("UploadSSLCertificate error = %v, want ErrOffline", err)
+	}
+
+	output := CaptureStdout(t, PrintDryRun)
+	if !strings.Contains(output, "Offline mode enabled") {
+		t.Fatalf("expected offline dry-run message, got: %s", output)
+	}
+}
@@ -0,0 +1,239 @@
+// Package internal contains shared helpers for haproxyctl.
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags shared by the get commands for writing output to disk and shaping
+// tables.
+const (
+	OutputFileFlag      = "output-file"
+	SplitByResourceFlag = "split-by-resource"
+	NoHeadersFlag       = "no-headers"
+	SortByFlag          = "sort-by"
+)
+
+// SortForCmd sorts list by the command's --sort-by flag, when it has one
+// and it is set. See SortByPath for the accepted field paths.
+func SortForCmd(cmd *cobra.Command, list []map[string]interface{}) {
+	if cmd.Flags().Lookup(SortByFlag) == nil {
+		return
+	}
+	spec := GetFlagString(cmd, SortByFlag)
+	if spec == "" {
+		return
+	}
+	if err := SortByPath(list, spec); err != nil {
+		FatalCodef(ExitUsage, "Invalid --%s: %v", SortByFlag, err)
+	}
+}
+
+// ManifestFileOutput reports whether cmd writes -o yaml or -o json output
+// to --output-file. Such files are meant to be fed back into apply, so
+// commands that have manifests write those instead of API objects, the same
+// way export does.
+func ManifestFileOutput(cmd *cobra.Command, outputFormat string) bool {
+	if cmd.Flags().Lookup(OutputFileFlag) == nil || GetFlagString(cmd, OutputFileFlag) == "" {
+		return false
+	}
+	return outputFormat == OutputFormatYAML || outputFormat == "json"
+}
+
+// ManifestListOf returns manifests as the List document apply accepts.
+func ManifestListOf(manifests []interface{}) ManifestList {
+	return ManifestList{APIVersion: "haproxyctl/v1", Kind: ListKind, Items: manifests}
+}
+
+// FormatOutputForCmd prints data like FormatOutput, honouring the
+// --output-file and --split-by-resource flags when the command has them.
+//
+// With --output-file, the rendered output is written to the given file
+// instead of stdout. With --split-by-resource, --output-file names a
+// directory and every item of a list is written to its own YAML/JSON file,
+// so `get ... -o yaml` results can be fed straight back into apply.
+// --no-headers drops the header lines of table output.
+func FormatOutputForCmd(cmd *cobra.Command, data interface{}, outputFormat string) {
+	path := GetFlagString(cmd, OutputFileFlag)
+	split := cmd.Flags().Lookup(SplitByResourceFlag) != nil && GetFlagBool(cmd, SplitByResourceFlag)
+	opts := outputOptions{
+		noHeaders: cmd.Flags().Lookup(NoHeadersFlag) != nil && GetFlagBool(cmd, NoHeadersFlag),
+	}
+
+	if split && path == "" {
+		FatalCodef(ExitUsage, "--%s requires --%s to name a directory", SplitByResourceFlag, OutputFileFlag)
+	}
+	if path == "" {
+		formatOutputTo(os.Stdout, data, outputFormat, opts)
+		return
+	}
+
+	if split {
+		files, err := writeSplitOutput(path, data, outputFormat)
+		if err != nil {
+			Fatalf("Failed to write output: %v", err)
+		}
+		for _, f := range files {
+			_, _ = fmt.Fprintf(os.Stdout, "wrote %s\n", f)
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	formatOutputTo(&buf, data, outputFormat, opts)
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		Fatalf("Failed to write output file %s: %v", path, err)
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "wrote %s\n", path)
+}
+
+// writeSplitOutput writes each item in data to its own file under dir and
+// returns the paths written. Files are named "<kind>-<name>.<ext>" when the
+// item carries a kind (manifests) and "<name>.<ext>" otherwise.
+func writeSplitOutput(dir string, data interface{}, outputFormat string) ([]string, error) {
+	if c, ok := data.(ColumnedData); ok {
+		data = c.Data
+	}
+	if outputFormat != OutputFormatYAML && outputFormat != "json" {
+		return nil, fmt.Errorf("--%s requires -o yaml or -o json", SplitByResourceFlag)
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	items := splitItems(data)
+	files := make([]string, 0, len(items))
+	seen := make(map[string]int)
+	for i, item := range items {
+		base := itemFileBase(item, i)
+		seen[base]++
+		if n := seen[base]; n > 1 {
+			base = fmt.Sprintf("%s-%d", base, n)
+		}
+
+		path := filepath.Join(dir, base+"."+outputFormat)
+		var buf bytes.Buffer
+		formatOutputTo(&buf, item, outputFormat, outputOptions{})
+		if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+			return files, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// WriteManifestFiles writes every manifest to its own YAML file under dir,
+// named "<kind>-<name>.yaml", and returns the paths written.
+func WriteManifestFiles(dir string, manifests []interface{}) ([]string, error) {
+	return writeSplitOutput(dir, manifests, OutputFormatYAML)
+}
+
+// splitItems returns the individual resources contained in data. A single
+// object yields a one-element slice.
+func splitItems(data interface{}) []interface{} {
+	switch v := data.(type) {
+	case ManifestList:
+		return v.Items
+	case *ManifestList:
+		return v.Items
+	case []interface{}:
+		return v
+	case []map[string]interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, item)
+		}
+		return items
+	default:
+		return []interface{}{v}
+	}
+}
+
+// printNames prints a kind/name identifier for every resource in data, one
+// per line. Manifests name their own kind; other objects use kind, the
+// fallback given by the command's ColumnSet.
+func printNames(w io.Writer, data interface{}, kind string) error {
+	root, err := jsonPathData(data)
+	if err != nil {
+		return err
+	}
+	items := []interface{}{root}
+	if m, ok := root.(map[string]interface{}); ok && m["kind"] == ListKind {
+		items, _ = m["items"].([]interface{})
+	}
+
+	for _, item := range items {
+		fields, _ := item.(map[string]interface{})
+		itemKind, _ := fields["kind"].(string)
+		if itemKind == "" {
+			itemKind = kind
+		}
+		name, _ := fields["name"].(string)
+		if name == "" {
+			// Storage entries such as certificates are keyed by storage_name.
+			name, _ = fields["storage_name"].(string)
+		}
+		if itemKind == "" || name == "" {
+			return errors.New("-o name is not supported for this resource")
+		}
+		if _, err := fmt.Fprintln(w, ResourceID(itemKind, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemFileBase derives a file name (without extension) from an item's kind
+// and name, falling back to its index when it has no name. A manifest
+// without a name (e.g. Global) is named after its kind alone.
+func itemFileBase(item interface{}, index int) string {
+	var fields map[string]interface{}
+	if m, ok := item.(map[string]interface{}); ok {
+		fields = m
+	} else if m, err := ManifestAsMap(item); err == nil {
+		// Manifest types carry yaml tags, not always json ones.
+		fields = m
+	} else if raw, err := json.Marshal(item); err == nil {
+		_ = json.Unmarshal(raw, &fields)
+	}
+
+	kind, _ := fields["kind"].(string)
+	name, _ := fields["name"].(string)
+	if name == "" && kind != "" {
+		return strings.ToLower(kind)
+	}
+	if name == "" {
+		name = fmt.Sprintf("item-%d", index+1)
+	}
+	name = strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(name)
+
+	if kind != "" {
+		return strings.ToLower(kind) + "-" + name
+	}
+	return name
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWriteSplitOutput_ManifestList(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "out")
+	list := ManifestList{
+		APIVersion: "haproxyctl/v1",
+		Kind:       "List",
+		Items: []interface{}{
+			map[string]interface{}{"apiVersion": "haproxyctl/v1", "kind": "Server", "name": "s1"},
+			map[string]interface{}{"apiVersion": "haproxyctl/v1", "kind": "Server", "name": "s2"},
+		},
+	}
+
+	files, err := writeSplitOutput(dir, list, OutputFormatYAML)
+	if err != nil {
+		t.Fatalf("writeSplitOutput failed: %v", err)
+	}
+
+	want := []string{filepath.Join(dir, "server-s1.yaml"), filepath.Join(dir, "server-s2.yaml")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+
+	data, err := os.ReadFile(want[0])
+	if err != nil {
+		t.Fatalf("read %s: %v", want[0], err)
+	}
+	if !strings.Contains(string(data), "kind: Server") || !strings.Contains(string(data), "name: s1") {
+		t.Fatalf("unexpected file contents:\n%s", data)
+	}
+}
+
+func TestWriteSplitOutput_RejectsTable(t *testing.T) {
+	t.Parallel()
+
+	if _, err := writeSplitOutput(t.TempDir(), []interface{}{}, "table"); err == nil {
+		t.Fatal("expected error for table output")
+	}
+}
+
+func TestFormatOutputForCmd_OutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backends.json")
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String(OutputFileFlag, "", "")
+	if err := cmd.Flags().Set(OutputFileFlag, path); err != nil {
+		t.Fatalf("set flag: %v", err)
+	}
+
+	out := CaptureStdout(t, func() {
+		FormatOutputForCmd(cmd, []map[string]interface{}{{"name": "web"}}, "json")
+	})
+	if !strings.Contains(out, "wrote "+path) {
+		t.Fatalf("expected write notice, got %q", out)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output file: %v", err)
+	}
+	if !strings.Contains(string(data), `"name": "web"`) {
+		t.Fatalf("unexpected file contents:\n%s", data)
+	}
+}
+
+func TestFormatOutput_Names(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	rows := []map[string]interface{}{{"name": "api"}, {"name": "web"}}
+	formatOutputTo(&out, WithColumns(rows, ColumnSet{Kind: "Backend"}), OutputFormatName, outputOptions{})
+	if out.String() != "backend/api\nbackend/web\n" {
+		t.Fatalf("unexpected names:\n%s", out.String())
+	}
+
+	// Manifests carry their own kind; storage entries use storage_name.
+	out.Reset()
+	list := ManifestList{APIVersion: "haproxyctl/v1", Kind: "List", Items: []interface{}{
+		map[string]interface{}{"kind": "Frontend", "name": "public"},
+		map[string]interface{}{"kind": "Certificate", "storage_name": "site.pem"},
+	}}
+	formatOutputTo(&out, list, OutputFormatName, outputOptions{})
+	if out.String() != "frontend/public\ncertificate/site.pem\n" {
+		t.Fatalf("unexpected names:\n%s", out.String())
+	}
+
+	if err := printNames(&out, []map[string]interface{}{{"name": "x"}}, ""); err == nil {
+		t.Fatal("expected error for resources without a kind")
+	}
+}
+
+func TestFormatOutputForCmd_NoHeaders(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().Bool(NoHeadersFlag, false, "")
+	if err := cmd.Flags().Set(NoHeadersFlag, "true"); err != nil {
+		t.Fatalf("set flag: %v", err)
+	}
+
+	out := CaptureStdout(t, func() {
+		FormatOutputForCmd(cmd, []map[string]interface{}{{"name": "web", "mode": "http"}}, "")
+		FormatOutputForCmd(cmd, []map[string]interface{}{{"name": "api"}}, "custom-columns=NAME:.name")
+	})
+	if strings.Contains(out, "NAME") || strings.Contains(out, "----") {
+		t.Fatalf("expected no header lines, got:\n%s", out)
+	}
+	if !strings.Contains(out, "web") || !strings.Contains(out, "api") {
+		t.Fatalf("expected rows, got:\n%s", out)
+	}
+}
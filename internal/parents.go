@@ -0,0 +1,104 @@
+// Package internal contains shared helpers for haproxyctl.
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Parent types of sections that own lists such as rules, filters or log
+// targets, as used in their endpoints
+// (/services/haproxy/configuration/<type>s/<name>/...).
+const (
+	ParentGlobal     = "global"
+	ParentFrontend   = "frontend"
+	ParentBackend    = "backend"
+	ParentLogForward = "log_forward"
+)
+
+// ParentFlag is the flag naming the section a command works on.
+const ParentFlag = "parent"
+
+// ParseParent splits a "<type>/<name>" section reference such as
+// "frontend/web" or "backends/app" into one of types and the name.
+// ParentGlobal is written alone and has an empty name.
+func ParseParent(ref string, types ...string) (string, string, error) {
+	if Contains(types, ParentGlobal) && strings.EqualFold(ref, ParentGlobal) {
+		return ParentGlobal, "", nil
+	}
+	kind, name, ok := strings.Cut(ref, "/")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid parent %q (expected %s)", ref, parentRefs(types))
+	}
+	parentType := strings.TrimSuffix(strings.ReplaceAll(strings.ToLower(kind), "-", "_"), "s")
+	if parentType == ParentGlobal || !Contains(types, parentType) {
+		return "", "", fmt.Errorf("unsupported parent type %q (expected %s)", kind, joinOr(parentNames(types)))
+	}
+	return parentType, name, nil
+}
+
+// AddParentFlag registers the --parent flag on cmd; owned names what the
+// section owns ("filters") and types are the accepted parent types.
+func AddParentFlag(cmd *cobra.Command, owned string, types ...string) {
+	cmd.Flags().String(ParentFlag, "", fmt.Sprintf("Section owning the %s: %s (required)", owned, parentRefs(types)))
+}
+
+// ParentFromFlag parses the --parent flag of cmd with ParseParent, exiting
+// when it is missing or invalid.
+func ParentFromFlag(cmd *cobra.Command, types ...string) (string, string) {
+	ref := GetFlagString(cmd, ParentFlag)
+	if ref == "" {
+		FatalCodef(ExitUsage, "--%s is required (%s)", ParentFlag, parentRefs(types))
+	}
+	parentType, parent, err := ParseParent(ref, types...)
+	if err != nil {
+		FatalCodef(ExitUsage, "Invalid --%s: %v", ParentFlag, err)
+	}
+	return parentType, parent
+}
+
+// parentNames returns parent types as users write them ("log-forward").
+func parentNames(types []string) []string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = strings.ReplaceAll(t, "_", "-")
+	}
+	return names
+}
+
+// parentRefs describes the accepted references, e.g.
+// "frontend/<name> or backend/<name>".
+func parentRefs(types []string) string {
+	refs := parentNames(types)
+	for i, t := range types {
+		if t != ParentGlobal {
+			refs[i] += "/<name>"
+		}
+	}
+	return joinOr(refs)
+}
+
+// joinOr joins items as "a, b or c".
+func joinOr(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " or " + items[len(items)-1]
+}
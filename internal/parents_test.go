@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseParent(t *testing.T) {
+	t.Parallel()
+
+	if kind, name, err := ParseParent("frontends/web", ParentFrontend, ParentBackend); err != nil || kind != ParentFrontend || name != "web" {
+		t.Fatalf("ParseParent(frontends/web) = %q, %q, %v", kind, name, err)
+	}
+	if kind, name, err := ParseParent("backend/app", ParentFrontend, ParentBackend); err != nil || kind != ParentBackend || name != "app" {
+		t.Fatalf("ParseParent(backend/app) = %q, %q, %v", kind, name, err)
+	}
+	for _, bad := range []string{"web", "frontend/", "listen/web", "global", "log-forward/syslog"} {
+		if _, _, err := ParseParent(bad, ParentFrontend, ParentBackend); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+
+	all := []string{ParentGlobal, ParentFrontend, ParentBackend, ParentLogForward}
+	if kind, name, err := ParseParent("global", all...); err != nil || kind != ParentGlobal || name != "" {
+		t.Fatalf("ParseParent(global) = %q, %q, %v", kind, name, err)
+	}
+	if kind, name, err := ParseParent("backends/app", all...); err != nil || kind != ParentBackend || name != "app" {
+		t.Fatalf("ParseParent(backends/app) = %q, %q, %v", kind, name, err)
+	}
+	if kind, name, err := ParseParent("log-forward/syslog", all...); err != nil || kind != ParentLogForward || name != "syslog" {
+		t.Fatalf("ParseParent(log-forward/syslog) = %q, %q, %v", kind, name, err)
+	}
+	if _, _, err := ParseParent("defaults/main", all...); err == nil {
+		t.Fatal("expected error for defaults parent")
+	}
+	if _, _, err := ParseParent("global/x", all...); err == nil {
+		t.Fatal("expected error for global with a name")
+	}
+}
+
+func TestParseParent_Messages(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := ParseParent("web", ParentFrontend, ParentBackend)
+	if err == nil || err.Error() != `invalid parent "web" (expected frontend/<name> or backend/<name>)` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _, err = ParseParent("defaults/main", ParentGlobal, ParentFrontend, ParentBackend, ParentLogForward)
+	if err == nil || err.Error() != `unsupported parent type "defaults" (expected global, frontend, backend or log-forward)` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAddParentFlag(t *testing.T) {
+	t.Parallel()
+
+	cmd := &cobra.Command{Use: "get"}
+	AddParentFlag(cmd, "log targets", ParentGlobal, ParentFrontend, ParentBackend, ParentLogForward)
+
+	flag := cmd.Flags().Lookup(ParentFlag)
+	want := "Section owning the log targets: global, frontend/<name>, backend/<name> or log-forward/<name> (required)"
+	if flag == nil || flag.Usage != want {
+		t.Fatalf("--parent flag = %+v, want usage %q", flag, want)
+	}
+}
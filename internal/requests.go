@@ -22,12 +22,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+
+	"haproxyctl/pkg/client"
 )
 
 const httpErrorThreshold = 300
@@ -36,7 +37,7 @@ const httpErrorThreshold = 300
 func ParseAPIResponse(data []byte, target interface{}) {
 	err := json.Unmarshal(data, target)
 	if err != nil {
-		log.Fatalf("Failed to parse API response: %v\nResponse: %s", err, string(data))
+		Fatalf("Failed to parse API response: %v\nResponse: %s", err, string(data))
 	}
 }
 
@@ -53,9 +54,20 @@ func GetConfigurationVersion() (int, error) {
 		return 0, fmt.Errorf("failed to parse version as integer: %w", err)
 	}
 
+	storeVersion(versionInt)
 	return versionInt, nil
 }
 
+// currentConfigurationVersion returns the version a change should be based
+// on: the cached one (see cachedVersion), else GetConfigurationVersion. A
+// reconciliation sending many changes thus fetches the version once.
+func currentConfigurationVersion() (int, error) {
+	if version, ok := cachedVersion(); ok {
+		return version, nil
+	}
+	return GetConfigurationVersion()
+}
+
 // SendRequest is a generic function to send API requests.
 func SendRequest(method, endpoint string, queryParams map[string]string, body interface{}) ([]byte, error) {
 	return SendRequestWithContext(context.Background(), method, endpoint, queryParams, body)
@@ -63,61 +75,48 @@ func SendRequest(method, endpoint string, queryParams map[string]string, body in
 
 // SendRequestWithContext sends an API request using the provided context.
 // Most callers should prefer this so that requests can be cancelled when
-// the associated CLI command is cancelled.
+// the associated CLI command is cancelled. Changes sent with a "version"
+// parameter are replayed with a fresh version after a version conflict
+// (see SetConflictRetries), and requests failing with a transient error
+// are retried with backoff when retries are enabled (see
+// SetTransientRetries).
 func SendRequestWithContext(ctx context.Context, method, endpoint string, queryParams map[string]string, body interface{}) ([]byte, error) {
-	cfg, err := LoadConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	// Build URL with query parameters
-	baseURL := normalizeAPIBaseURL(cfg.APIBaseURL)
-	url := baseURL + endpoint
-	if len(queryParams) > 0 {
-		url += "?"
-		for key, value := range queryParams {
-			url += key + "=" + value + "&"
-		}
-		url = url[:len(url)-1] // Remove trailing "&"
-	}
-
-	// Convert body to JSON if needed
-	var reqBody []byte
-	if body != nil {
-		var err error
-		reqBody, err = json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-	}
+	return retryOnVersionConflict(queryParams, func(params map[string]string) ([]byte, error) {
+		return sendRequestOnce(ctx, method, endpoint, params, body)
+	})
+}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create API request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(cfg.Username, cfg.Password)
+func sendRequestOnce(ctx context.Context, method, endpoint string, queryParams map[string]string, body interface{}) ([]byte, error) {
+	data, _, err := sendRequestWithHeaders(ctx, method, endpoint, queryParams, body)
+	return data, err
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// sendRequestWithHeaders sends a request, retrying it after transient
+// errors (see retryOnTransientError).
+func sendRequestWithHeaders(ctx context.Context, method, endpoint string, queryParams map[string]string, body interface{}) ([]byte, http.Header, error) {
+	c, err := apiClient()
 	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
-	}
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to close response body: %v\n", cerr)
-		}
-	}()
-
-	if resp.StatusCode >= httpErrorThreshold {
-		bodyBytes, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return nil, fmt.Errorf("HAProxy API error (%d) and failed to read error body: %w", resp.StatusCode, readErr)
-		}
-		return nil, fmt.Errorf("HAProxy API error (%d): %s", resp.StatusCode, string(bodyBytes))
+		return nil, nil, err
 	}
+	snapshotBeforeChange(ctx, method, endpoint)
+	var (
+		data   []byte
+		header http.Header
+	)
+	err = retryOnTransientError(ctx, method, func() error {
+		data, header, err = c.DoWithHeaders(ctx, method, endpoint, queryParams, body)
+		noteResponse(method, header, err)
+		noteVersionedChange(endpoint, queryParams, err)
+		return err
+	})
+	return data, header, err
+}
 
-	return io.ReadAll(resp.Body)
+// SendRequestWithHeaders is SendRequestWithContext that also returns the
+// response headers, e.g. the Reload-ID of a commit. It does not replay
+// changes after a version conflict.
+func SendRequestWithHeaders(ctx context.Context, method, endpoint string, queryParams map[string]string, body interface{}) ([]byte, http.Header, error) {
+	return sendRequestWithHeaders(ctx, method, endpoint, queryParams, body)
 }
 
 // SendRawRequest sends a raw payload (e.g. entire HAProxy config) without JSON‑encoding.
@@ -126,66 +125,95 @@ func SendRawRequest(method, endpoint string, queryParams map[string]string, rawB
 	return SendRawRequestWithContext(context.Background(), method, endpoint, queryParams, rawBody, contentType)
 }
 
-// SendRawRequestWithContext is the context-aware form of SendRawRequest.
+// SendRawRequestWithContext is the context-aware form of SendRawRequest. Like
+// SendRequestWithContext, it retries versioned changes after a conflict.
 func SendRawRequestWithContext(ctx context.Context, method, endpoint string, queryParams map[string]string, rawBody []byte, contentType string) ([]byte, error) {
-	cfg, err := LoadConfig()
+	return retryOnVersionConflict(queryParams, func(params map[string]string) ([]byte, error) {
+		return sendRawRequestOnce(ctx, method, endpoint, params, rawBody, contentType)
+	})
+}
+
+// SendRawRequestOnce is SendRawRequestWithContext without the replay after
+// a version conflict, for payloads derived from the configuration at that
+// version (such as an edited raw configuration) that must not overwrite
+// changes made since.
+func SendRawRequestOnce(ctx context.Context, method, endpoint string, queryParams map[string]string, rawBody []byte, contentType string) ([]byte, error) {
+	return sendRawRequestOnce(ctx, method, endpoint, queryParams, rawBody, contentType)
+}
+
+func sendRawRequestOnce(ctx context.Context, method, endpoint string, queryParams map[string]string, rawBody []byte, contentType string) ([]byte, error) {
+	c, err := apiClient()
 	if err != nil {
 		return nil, err
 	}
+	snapshotBeforeChange(ctx, method, endpoint)
+	var data []byte
+	err = retryOnTransientError(ctx, method, func() error {
+		var header http.Header
+		data, header, err = c.DoRawWithHeaders(ctx, method, endpoint, queryParams, rawBody, contentType)
+		noteResponse(method, header, err)
+		noteVersionedChange(endpoint, queryParams, err)
+		return err
+	})
+	return data, err
+}
 
-	// Build URL + query string
-	baseURL := normalizeAPIBaseURL(cfg.APIBaseURL)
-	url := baseURL + endpoint
-	if len(queryParams) > 0 {
-		q := "?"
-		for k, v := range queryParams {
-			q += fmt.Sprintf("%s=%s&", k, v)
-		}
-		url += strings.TrimSuffix(q, "&")
+// apiClient returns the pkg/client Client for the configured endpoint,
+// shared by all requests (see currentSession) and sending through the
+// cassette transport when one is configured.
+func apiClient() (*client.Client, error) {
+	if IsOffline() {
+		return nil, ErrOffline
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(rawBody))
+	s, err := currentSession()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
-	req.Header.Set("Content-Type", contentType)
-	req.SetBasicAuth(cfg.Username, cfg.Password)
+	return s.api, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("raw request failed: %w", err)
+// API returns the typed Data Plane API client for the commands. Its
+// requests go through SendRequestWithContext, so they get the offline
+// mode, retries, snapshots and logging of every other request, and a
+// change made without a transaction uses WriteParams(nil).
+func API() *client.Client {
+	return client.New("", "", "", client.WithRequester(typedRequester{}))
+}
+
+// typedRequester is the client.Requester of API.
+type typedRequester struct{}
+
+func (typedRequester) Do(ctx context.Context, method, endpoint string, params map[string]string, body interface{}) ([]byte, error) {
+	return SendRequestWithContext(ctx, method, endpoint, params, body)
+}
+
+func (typedRequester) WriteParams(_ context.Context, tx *client.Transaction) (map[string]string, error) {
+	if tx != nil {
+		return map[string]string{"transaction_id": tx.ID}, nil
 	}
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to close response body: %v\n", cerr)
-		}
-	}()
+	return WriteParams(nil)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// ConvertPayload converts payload, the wire-format struct or map of a
+// command, into out, an object of the typed client, through their common
+// JSON form.
+func ConvertPayload(payload, out interface{}) error {
+	data, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read raw response body: %w", err)
+		return fmt.Errorf("failed to encode payload: %w", err)
 	}
-	if resp.StatusCode >= httpErrorThreshold {
-		return nil, fmt.Errorf("HAProxy API error (%d): %s", resp.StatusCode, string(body))
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
 	}
-	return body, nil
+	return nil
 }
 
 // normalizeAPIBaseURL ensures the configured API base URL includes a version
 // prefix (we default to v3) and has no trailing slash. This lets users enter
 // either "http://host:5555" or "http://host:5555/v3" in `haproxyctl login`.
 func normalizeAPIBaseURL(raw string) string {
-	base := strings.TrimSpace(raw)
-	base = strings.TrimRight(base, "/")
-
-	if strings.HasSuffix(base, "/v1") ||
-		strings.HasSuffix(base, "/v2") ||
-		strings.HasSuffix(base, "/v3") {
-		return base
-	}
-
-	// Default to Data Plane API v3 when no explicit version is present.
-	return base + "/v3"
+	return client.NormalizeBaseURL(raw)
 }
 
 // IsNotFoundError reports whether the given error corresponds to a 404
@@ -201,7 +229,12 @@ func IsNotFoundError(err error) bool {
 
 // GetResource retrieves a single resource (map[string]interface{}) from the API.
 func GetResource(endpoint string) (map[string]interface{}, error) {
-	data, err := SendRequest("GET", endpoint, nil, nil)
+	return GetResourceWithContext(context.Background(), endpoint)
+}
+
+// GetResourceWithContext is GetResource with a context.
+func GetResourceWithContext(ctx context.Context, endpoint string) (map[string]interface{}, error) {
+	data, err := SendRequestWithContext(ctx, "GET", endpoint, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resource: %w", err)
 	}
@@ -216,7 +249,13 @@ func GetResource(endpoint string) (map[string]interface{}, error) {
 
 // GetResourceList retrieves a list of resources ([]map[string]interface{}) from the API.
 func GetResourceList(endpoint string) ([]map[string]interface{}, error) {
-	data, err := SendRequest("GET", endpoint, nil, nil)
+	return GetResourceListWithContext(context.Background(), endpoint)
+}
+
+// GetResourceListWithContext is GetResourceList with a context, e.g. the
+// one of ForEachConcurrent.
+func GetResourceListWithContext(ctx context.Context, endpoint string) ([]map[string]interface{}, error) {
+	data, err := SendRequestWithContext(ctx, "GET", endpoint, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resource list: %w", err)
 	}
@@ -232,41 +271,100 @@ func GetResourceList(endpoint string) ([]map[string]interface{}, error) {
 // UploadSSLCertificateWithContext uploads a PEM bundle (key + cert + optional
 // chain) to the HAProxy Data Plane API ssl_certificates storage.
 func UploadSSLCertificateWithContext(ctx context.Context, name string, pem []byte) error {
-	cfg, err := LoadConfig()
+	_, err := uploadFile(ctx, http.MethodPost, "/services/haproxy/storage/ssl_certificates", "file", name+".pem", pem, "SSL certificate")
+	return err
+}
+
+// UploadSSLCrtListWithContext uploads a crt-list to the Data Plane API
+// ssl_crt_lists storage and returns the path HAProxy reads it from.
+func UploadSSLCrtListWithContext(ctx context.Context, name string, data []byte) (string, error) {
+	respBody, err := uploadFile(ctx, http.MethodPost, "/services/haproxy/storage/ssl_crt_lists", "file_upload", name, data, "crt-list")
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	var entry struct {
+		File string `json:"file"`
+	}
+	if err := json.Unmarshal(respBody, &entry); err != nil {
+		return "", fmt.Errorf("failed to parse crt-list upload response: %w", err)
+	}
+	return entry.File, nil
+}
+
+// UploadSPOEFileWithContext uploads an SPOE configuration file to the
+// Data Plane API spoe_files storage.
+func UploadSPOEFileWithContext(ctx context.Context, name string, data []byte) error {
+	_, err := uploadFile(ctx, http.MethodPost, "/services/haproxy/spoe/spoe_files", "file_upload", name, data, "SPOE file")
+	return err
+}
+
+// UploadStorageFileWithContext uploads a file such as an error page to the
+// Data Plane API general storage, replacing the entry of the same name when
+// replace is set. It returns the path HAProxy reads the file from.
+func UploadStorageFileWithContext(ctx context.Context, name string, data []byte, replace bool) (string, error) {
+	method, endpoint := http.MethodPost, "/services/haproxy/storage/general"
+	if replace {
+		method, endpoint = http.MethodPut, endpoint+"/"+name
+	}
+	respBody, err := uploadFile(ctx, method, endpoint, "file_upload", name, data, "storage file")
+	if err != nil {
+		return "", err
+	}
+
+	var entry struct {
+		File string `json:"file"`
+	}
+	if err := json.Unmarshal(respBody, &entry); err != nil {
+		return "", fmt.Errorf("failed to parse storage upload response: %w", err)
+	}
+	return entry.File, nil
+}
+
+// uploadFile sends data as the multipart form field of a storage upload
+// and returns the response body. what names the upload in errors.
+func uploadFile(ctx context.Context, method, endpoint, field, filename string, data []byte, what string) ([]byte, error) {
+	if IsOffline() {
+		return nil, ErrOffline
 	}
 
+	s, err := currentSession()
+	if err != nil {
+		return nil, err
+	}
+	cfg := s.cfg
+
 	baseURL := normalizeAPIBaseURL(cfg.APIBaseURL)
-	url := baseURL + "/services/haproxy/storage/ssl_certificates"
+	url := baseURL + endpoint
 
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
-	filename := name + ".pem"
-	part, err := writer.CreateFormFile("file", filename)
+	part, err := writer.CreateFormFile(field, filename)
 	if err != nil {
-		return fmt.Errorf("failed to create multipart form file: %w", err)
+		return nil, fmt.Errorf("failed to create multipart form file: %w", err)
 	}
 
-	if _, err := part.Write(pem); err != nil {
-		return fmt.Errorf("failed to write PEM data: %w", err)
+	if _, err := part.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write %s data: %w", what, err)
 	}
 
 	if err := writer.Close(); err != nil {
-		return fmt.Errorf("failed to finalize multipart body: %w", err)
+		return nil, fmt.Errorf("failed to finalize multipart body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return fmt.Errorf("failed to create SSL certificate upload request: %w", err)
+		return nil, fmt.Errorf("failed to create %s upload request: %w", what, err)
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.SetBasicAuth(cfg.Username, cfg.Password)
+	if err := setAuth(ctx, req, cfg); err != nil {
+		return nil, err
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.http.Do(req)
 	if err != nil {
-		return fmt.Errorf("SSL certificate upload failed: %w", err)
+		return nil, fmt.Errorf("%s upload failed: %w", what, err)
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
@@ -276,14 +374,15 @@ func UploadSSLCertificateWithContext(ctx context.Context, name string, pem []byt
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read SSL certificate upload response: %w", err)
+		return nil, fmt.Errorf("failed to read %s upload response: %w", what, err)
 	}
 
 	if resp.StatusCode >= httpErrorThreshold {
-		return fmt.Errorf("HAProxy API error (%d): %s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("HAProxy API error (%d): %s", resp.StatusCode, string(respBody))
 	}
 
-	return nil
+	noteResponse(method, resp.Header, nil)
+	return respBody, nil
 }
 
 // UploadSSLCertificate is a convenience wrapper around UploadSSLCertificateWithContext
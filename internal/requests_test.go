@@ -1,6 +1,12 @@
 package internal
 
-import "testing"
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
 
 func TestNormalizeAPIBaseURL(t *testing.T) {
 	t.Parallel()
@@ -43,3 +49,35 @@ func TestNormalizeAPIBaseURL(t *testing.T) {
 		})
 	}
 }
+
+func TestSendRequest_ReusesConnections(t *testing.T) {
+	var dials atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("7"))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			dials.Add(1)
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	defer SetConfigOverride(&Config{APIBaseURL: srv.URL, InsecureSkipTLSVerify: true})()
+
+	for range 5 {
+		if _, err := GetConfigurationVersion(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := dials.Load(); n != 1 {
+		t.Fatalf("5 requests opened %d connections, want 1", n)
+	}
+
+	// A change of the TLS flags is picked up by the next request.
+	SetTLSOptions(TLSOptions{ClientCertificate: "missing.pem", ClientKey: "missing.pem"})
+	defer SetTLSOptions(TLSOptions{})
+	if _, err := GetConfigurationVersion(); err == nil {
+		t.Fatal("expected the client to be rebuilt with the new TLS options")
+	}
+}
@@ -17,11 +17,12 @@ limitations under the License.
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
+	"sort"
 )
 
 // ExtractOptionalArg extracts the optional second argument (resource name), if provided.
@@ -33,64 +34,171 @@ func ExtractOptionalArg(args []string) string {
 }
 
 // EnrichBackendWithServers fetches and attaches servers to a backend object as []interface{}.
-func EnrichBackendWithServers(backend map[string]interface{}) {
+func EnrichBackendWithServers(ctx context.Context, backend map[string]interface{}) {
 	backendName, ok := backend["name"].(string)
 	if !ok || backendName == "" {
-		log.Fatalf("Backend has no valid name field: %+v", backend)
+		Fatalf("Backend has no valid name field: %+v", backend)
 	}
 
-	endpoint := fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers", backendName)
-	data, err := SendRequest("GET", endpoint, nil, nil)
+	servers, err := fetchBackendServers(ctx, backendName)
 	if err != nil {
-		log.Fatalf("Failed to fetch servers for backend %s: %v", backendName, err)
+		Fatalf("%v", err)
+	}
+	// Attach as []interface{}, which plays nicely with formatList().
+	backend["servers"] = servers
+}
+
+// fetchBackendServers returns the servers of a backend as []interface{}.
+func fetchBackendServers(ctx context.Context, backendName string) ([]interface{}, error) {
+	endpoint := fmt.Sprintf("/services/haproxy/configuration/backends/%s/servers", url.PathEscape(backendName))
+	data, err := SendRequestWithContext(ctx, "GET", endpoint, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch servers for backend %s: %w", backendName, err)
 	}
 
 	var servers []map[string]interface{}
 	if err := json.Unmarshal(data, &servers); err != nil {
-		log.Fatalf("Failed to parse servers response: %v\nResponse: %s", err, string(data))
+		return nil, fmt.Errorf("failed to parse servers response: %w\nResponse: %s", err, string(data))
 	}
 
 	serverInterfaces := make([]interface{}, 0, len(servers))
 	for _, server := range servers {
 		if !isServerObject(server) {
-			log.Fatalf("Unexpected server format in backend %s: %+v", backendName, server)
+			return nil, fmt.Errorf("unexpected server format in backend %s: %+v", backendName, server)
 		}
 		serverInterfaces = append(serverInterfaces, server)
 	}
+	return serverInterfaces, nil
+}
 
-	// Attach as []interface{}, which plays nicely with formatList().
-	backend["servers"] = serverInterfaces
+// GetBackendsWithServers fetches every backend with its servers attached
+// as "servers", like EnrichBackendWithServers. It asks for
+// full_section=true, which nests the servers of all backends (keyed by
+// name) in a single response. When no backend carries servers, either
+// there are none or the API does not nest them, so they are fetched per
+// backend, concurrently (see ForEachConcurrent).
+func GetBackendsWithServers(ctx context.Context) ([]map[string]interface{}, error) {
+	data, err := SendRequestWithContext(ctx, "GET", "/services/haproxy/configuration/backends", map[string]string{"full_section": "true"}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource list: %w", err)
+	}
+	var backends []map[string]interface{}
+	if err := json.Unmarshal(data, &backends); err != nil {
+		return nil, fmt.Errorf("failed to parse backends response: %w", err)
+	}
+
+	nested := false
+	for _, backend := range backends {
+		if _, ok := backend["servers"]; ok {
+			nested = true
+			break
+		}
+	}
+	if nested {
+		for _, backend := range backends {
+			backend["servers"] = nestedServers(backend["servers"])
+		}
+		return backends, nil
+	}
+
+	err = ForEachConcurrent(ctx, len(backends), func(ctx context.Context, i int) error {
+		name, _ := backends[i]["name"].(string)
+		servers, err := fetchBackendServers(ctx, name)
+		if err != nil {
+			return err
+		}
+		backends[i]["servers"] = servers
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backends, nil
+}
+
+// nestedServers converts the servers of a full_section backend, keyed by
+// name, to the []interface{} list EnrichBackendWithServers attaches,
+// sorted by name.
+func nestedServers(v interface{}) []interface{} {
+	var servers []interface{}
+	switch nested := v.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(nested))
+		for name := range nested {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			server, ok := nested[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := server["name"]; !ok {
+				server["name"] = name
+			}
+			servers = append(servers, server)
+		}
+	case []interface{}:
+		servers = nested
+	}
+	if servers == nil {
+		servers = []interface{}{}
+	}
+	return servers
 }
 
 // EnrichFrontendWithBinds fetches and attaches binds to a frontend object as []interface{}.
 // This allows table output to render a compact binds column similar to how servers
 // are shown for backends.
-func EnrichFrontendWithBinds(frontend map[string]interface{}) {
+func EnrichFrontendWithBinds(ctx context.Context, frontend map[string]interface{}) {
 	frontendName, ok := frontend["name"].(string)
 	if !ok || frontendName == "" {
-		log.Fatalf("Frontend has no valid name field: %+v", frontend)
+		Fatalf("Frontend has no valid name field: %+v", frontend)
 	}
 
-	// Encode the frontend name in case it contains characters that need escaping.
-	escapedName := url.PathEscape(frontendName)
+	binds, err := fetchFrontendBinds(ctx, frontendName)
+	if err != nil {
+		Fatalf("%v", err)
+	}
+	frontend["binds"] = binds
+}
 
-	endpoint := fmt.Sprintf("/services/haproxy/configuration/frontends/%s/binds", escapedName)
-	data, err := SendRequest("GET", endpoint, nil, nil)
+// EnrichFrontendsWithBinds is EnrichFrontendWithBinds for a list of
+// frontends, fetching the binds concurrently (see ForEachConcurrent).
+func EnrichFrontendsWithBinds(ctx context.Context, frontends []map[string]interface{}) error {
+	return ForEachConcurrent(ctx, len(frontends), func(ctx context.Context, i int) error {
+		frontendName, ok := frontends[i]["name"].(string)
+		if !ok || frontendName == "" {
+			return fmt.Errorf("frontend has no valid name field: %+v", frontends[i])
+		}
+		binds, err := fetchFrontendBinds(ctx, frontendName)
+		if err != nil {
+			return err
+		}
+		frontends[i]["binds"] = binds
+		return nil
+	})
+}
+
+// fetchFrontendBinds returns the binds of a frontend as []interface{}.
+func fetchFrontendBinds(ctx context.Context, frontendName string) ([]interface{}, error) {
+	// Encode the frontend name in case it contains characters that need escaping.
+	endpoint := fmt.Sprintf("/services/haproxy/configuration/frontends/%s/binds", url.PathEscape(frontendName))
+	data, err := SendRequestWithContext(ctx, "GET", endpoint, nil, nil)
 	if err != nil {
-		log.Fatalf("Failed to fetch binds for frontend %s: %v", frontendName, err)
+		return nil, fmt.Errorf("failed to fetch binds for frontend %s: %w", frontendName, err)
 	}
 
 	var binds []map[string]interface{}
 	if err := json.Unmarshal(data, &binds); err != nil {
-		log.Fatalf("Failed to parse binds response: %v\nResponse: %s", err, string(data))
+		return nil, fmt.Errorf("failed to parse binds response: %w\nResponse: %s", err, string(data))
 	}
 
 	bindInterfaces := make([]interface{}, 0, len(binds))
 	for _, bind := range binds {
 		bindInterfaces = append(bindInterfaces, bind)
 	}
-
-	frontend["binds"] = bindInterfaces
+	return bindInterfaces, nil
 }
 
 // ValidateBackend performs basic validation for backend fields.
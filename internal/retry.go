@@ -0,0 +1,179 @@
+// Package internal contains shared helpers for haproxyctl.
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"haproxyctl/pkg/client"
+)
+
+// DefaultConflictRetries is how many times a versioned change is replayed
+// after a configuration version conflict unless --conflict-retries says
+// otherwise.
+const DefaultConflictRetries = 3
+
+// conflictRetries is set once per process from the global
+// --conflict-retries flag.
+var conflictRetries = DefaultConflictRetries
+
+// SetConflictRetries sets how many times a change sent with a "version"
+// parameter is replayed after a version conflict. Zero disables retries.
+func SetConflictRetries(n int) {
+	conflictRetries = max(n, 0)
+}
+
+// IsVersionConflictError reports whether err is the Data Plane API
+// rejecting a change because the configuration version moved on since it
+// was read (409 "version mismatch"). A 409 for an object that already
+// exists is not a version conflict.
+func IsVersionConflictError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "haproxy api error (409)") && strings.Contains(msg, "version mismatch")
+}
+
+// retryOnVersionConflict sends a request with send and, while it fails
+// with a version conflict, re-fetches the configuration version and
+// replays it with the new one. Only requests carrying a "version"
+// parameter are retried: the API rejects a stale version before changing
+// anything, so replaying them is safe. Reads and transactional changes are
+// sent once.
+func retryOnVersionConflict(params map[string]string, send func(map[string]string) ([]byte, error)) ([]byte, error) {
+	data, err := send(params)
+	if _, versioned := params["version"]; !versioned {
+		return data, err
+	}
+
+	for attempt := 1; attempt <= conflictRetries && IsVersionConflictError(err); attempt++ {
+		version, verr := GetConfigurationVersion()
+		if verr != nil {
+			return nil, fmt.Errorf("%w (and failed to re-fetch the configuration version: %w)", err, verr)
+		}
+		Warnf("configuration version changed, retrying with version %d (%d/%d)", version, attempt, conflictRetries)
+
+		params = maps.Clone(params)
+		params["version"] = strconv.Itoa(version)
+		data, err = send(params)
+	}
+	return data, err
+}
+
+// transientRetriesOverride is set once per process from the global
+// --retries flag, when it is given.
+var transientRetriesOverride *int
+
+// SetTransientRetries sets how many times a request failing with a
+// transient error is retried, over the "retries" of the active context.
+// Zero disables retries.
+func SetTransientRetries(n int) {
+	n = max(n, 0)
+	transientRetriesOverride = &n
+}
+
+// ClearTransientRetries drops the --retries value again, so the "retries"
+// of the context applies.
+func ClearTransientRetries() {
+	transientRetriesOverride = nil
+}
+
+// transientRetries returns --retries, else the "retries" of the active
+// context. Without either, transient errors are not retried.
+func transientRetries() int {
+	if transientRetriesOverride != nil {
+		return *transientRetriesOverride
+	}
+	s, err := currentSession()
+	if err != nil {
+		return 0
+	}
+	return max(s.cfg.Retries, 0)
+}
+
+// Backoff between transient retries: the first wait is about
+// transientBackoff, doubling per attempt up to transientBackoffMax.
+// Variables so tests can shorten them.
+var (
+	transientBackoff    = 250 * time.Millisecond
+	transientBackoffMax = 8 * time.Second
+)
+
+// isTransientError reports whether a request failing with err may succeed
+// when sent again. A refused connection, 429 Too Many Requests and 503
+// Service Unavailable mean the API did not process the request, so any
+// request is retried. Other 5xx responses may come after the change was
+// made, so only reads are retried then.
+func isTransientError(method string, err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return method == http.MethodGet || method == http.MethodHead
+	}
+	return false
+}
+
+// backoffDelay returns the wait before retry attempt (1-based): an
+// exponential backoff with jitter, so that many clients retrying after the
+// same outage do not hit the API in lockstep.
+func backoffDelay(attempt int) time.Duration {
+	d := transientBackoff << (attempt - 1)
+	if d > transientBackoffMax || d <= 0 {
+		d = transientBackoffMax
+	}
+	return d/2 + rand.N(d/2+1) //nolint:gosec // jitter needs no cryptographic randomness
+}
+
+// retryOnTransientError calls send and, while it fails with a transient
+// error (see isTransientError), waits with backoff and calls it again, up
+// to transientRetries times.
+func retryOnTransientError(ctx context.Context, method string, send func() error) error {
+	err := send()
+	retries := 0
+	if err != nil && isTransientError(method, err) {
+		retries = transientRetries()
+	}
+	for attempt := 1; attempt <= retries && isTransientError(method, err); attempt++ {
+		delay := backoffDelay(attempt)
+		Warnf("%v, retrying in %s (%d/%d)", err, delay.Round(time.Millisecond), attempt, retries)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		err = send()
+	}
+	return err
+}
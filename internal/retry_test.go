@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// conflictServer rejects the first n versioned PUTs with a version
+// mismatch and records the versions it saw.
+type conflictServer struct {
+	mu        sync.Mutex
+	conflicts int
+	versions  []string
+	message   string
+}
+
+func (c *conflictServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if r.Method == http.MethodGet {
+		_, _ = w.Write([]byte(strconv.Itoa(10 + len(c.versions))))
+		return
+	}
+	c.versions = append(c.versions, r.URL.Query().Get("version"))
+	if c.conflicts > 0 {
+		c.conflicts--
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(c.message))
+		return
+	}
+	_, _ = w.Write([]byte("{}"))
+}
+
+func TestSendRequestRetriesVersionConflicts(t *testing.T) {
+	tests := []struct {
+		name      string
+		conflicts int
+		retries   int
+		message   string
+		params    map[string]string
+		wantErr   bool
+		versions  []string
+	}{
+		{
+			name:      "replays with fresh version",
+			conflicts: 2,
+			retries:   3,
+			message:   `{"code":409,"message":"version mismatch"}`,
+			params:    map[string]string{"version": "1"},
+			versions:  []string{"1", "11", "12"},
+		},
+		{
+			name:      "gives up after configured retries",
+			conflicts: 5,
+			retries:   1,
+			message:   `{"code":409,"message":"version mismatch"}`,
+			params:    map[string]string{"version": "1"},
+			wantErr:   true,
+			versions:  []string{"1", "11"},
+		},
+		{
+			name:      "already exists is not retried",
+			conflicts: 1,
+			retries:   3,
+			message:   `{"code":409,"message":"object web already exists"}`,
+			params:    map[string]string{"version": "1"},
+			wantErr:   true,
+			versions:  []string{"1"},
+		},
+		{
+			name:      "transactional change is not retried",
+			conflicts: 1,
+			retries:   3,
+			message:   `{"code":409,"message":"version mismatch"}`,
+			params:    map[string]string{"transaction_id": "tx"},
+			wantErr:   true,
+			versions:  []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &conflictServer{conflicts: tt.conflicts, message: tt.message}
+			srv := httptest.NewServer(cs)
+			defer srv.Close()
+
+			defer SetConfigOverride(&Config{APIBaseURL: srv.URL})()
+			SetConflictRetries(tt.retries)
+			defer SetConflictRetries(DefaultConflictRetries)
+
+			_, err := SendRequest("PUT", "/services/haproxy/configuration/backends/web", tt.params, map[string]string{"name": "web"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(cs.versions, tt.versions) {
+				t.Fatalf("versions sent = %q, want %q", cs.versions, tt.versions)
+			}
+		})
+	}
+}
+
+func TestSendRequestRetriesTransientErrors(t *testing.T) {
+	transientBackoff, transientBackoffMax = time.Millisecond, time.Millisecond
+	t.Cleanup(func() { transientBackoff, transientBackoffMax = 250*time.Millisecond, 8*time.Second })
+
+	var mu sync.Mutex
+	failures, requests := 0, 0
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if failures > 0 {
+			failures--
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte("7"))
+	}))
+	defer srv.Close()
+	defer SetConfigOverride(&Config{APIBaseURL: srv.URL, Retries: 2})()
+	defer ClearTransientRetries()
+
+	reset := func(n, code int) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures, requests, status = n, 0, code
+	}
+
+	// The context's retries cover two 503s.
+	reset(2, http.StatusServiceUnavailable)
+	if v, err := GetConfigurationVersion(); err != nil || v != 7 || requests != 3 {
+		t.Fatalf("after two 503s: version %d, err %v, %d requests", v, err, requests)
+	}
+
+	// --retries 0 turns them off.
+	SetTransientRetries(0)
+	reset(1, http.StatusServiceUnavailable)
+	if _, err := GetConfigurationVersion(); err == nil || requests != 1 {
+		t.Fatalf("--retries 0: err %v, %d requests", err, requests)
+	}
+
+	// A 500 may come after the change was made: changes are sent once,
+	// reads are retried.
+	SetTransientRetries(3)
+	reset(1, http.StatusInternalServerError)
+	if _, err := SendRequest("POST", "/services/haproxy/configuration/backends", nil, map[string]string{"name": "b"}); err == nil || requests != 1 {
+		t.Fatalf("POST after 500: err %v, %d requests", err, requests)
+	}
+	reset(1, http.StatusInternalServerError)
+	if _, err := GetConfigurationVersion(); err != nil || requests != 2 {
+		t.Fatalf("GET after 500: err %v, %d requests", err, requests)
+	}
+
+	// 4xx errors other than 429 are final.
+	reset(1, http.StatusBadRequest)
+	if _, err := GetConfigurationVersion(); err == nil || requests != 1 {
+		t.Fatalf("400: err %v, %d requests", err, requests)
+	}
+}
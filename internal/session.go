@@ -0,0 +1,145 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"haproxyctl/pkg/client"
+)
+
+// apiSession is the resolved configuration and the clients built from it,
+// shared by every request of the process so that connections (and TLS
+// sessions) are kept alive instead of dialed per request.
+type apiSession struct {
+	key  string
+	cfg  Config
+	http *http.Client
+	api  *client.Client
+
+	// version is the configuration version the next change is expected
+	// to be based on (see cachedVersion), valid when versionKnown.
+	versionMu    sync.Mutex
+	version      int
+	versionKnown bool
+}
+
+var (
+	sessionMu     sync.Mutex
+	activeSession *apiSession
+)
+
+// sessionEnv lists the environment variables the session is built from.
+var sessionEnv = []string{EnvAPIURL, EnvUsername, EnvPassword, EnvContext, cassetteEnv, cassetteModeEnv}
+
+// sessionKey identifies the environment a session was built in, so a
+// session is rebuilt when the variables change between tests. Tests also
+// edit a config override in place, so its contents are part of the key.
+func sessionKey() string {
+	values := make([]string, 0, len(sessionEnv)+1)
+	for _, name := range sessionEnv {
+		values = append(values, os.Getenv(name))
+	}
+	if configOverride != nil {
+		values = append(values, fmt.Sprintf("%+v", *configOverride))
+	}
+	return strings.Join(values, "\x00")
+}
+
+// currentSession returns the shared session, loading the configuration and
+// building the clients on first use.
+func currentSession() (*apiSession, error) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	key := sessionKey()
+	if activeSession != nil && activeSession.key == key {
+		return activeSession, nil
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	hc, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts := []client.Option{client.WithHTTPClient(hc)}
+	if ts := tokenSource(cfg); ts != nil {
+		opts = append(opts, client.WithTokenSource(ts))
+	}
+	activeSession = &apiSession{
+		key:  key,
+		cfg:  cfg,
+		http: hc,
+		api:  client.New(cfg.APIBaseURL, cfg.Username, cfg.Password, opts...),
+	}
+	return activeSession, nil
+}
+
+// resetSession drops the shared session. Everything that changes what
+// LoadConfig or newHTTPClient return calls it, so the next request picks
+// the change up.
+func resetSession() {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	activeSession = nil
+}
+
+// cachedVersion returns the configuration version of the session, if one
+// is known. It is learned from GetConfigurationVersion and advanced by
+// every versioned change the session makes, since each one bumps the
+// version by one; a version conflict means someone else changed the
+// configuration, and the retry fetches the version again.
+func cachedVersion() (int, bool) {
+	s, err := currentSession()
+	if err != nil {
+		return 0, false
+	}
+	s.versionMu.Lock()
+	defer s.versionMu.Unlock()
+	return s.version, s.versionKnown
+}
+
+// storeVersion records the configuration version the next change is
+// based on.
+func storeVersion(version int) {
+	s, err := currentSession()
+	if err != nil {
+		return
+	}
+	s.versionMu.Lock()
+	defer s.versionMu.Unlock()
+	s.version, s.versionKnown = version, true
+}
+
+// forgetVersion drops the cached version, e.g. after a change whose effect
+// on the version is not known, so the next change fetches it again.
+func forgetVersion() {
+	s, err := currentSession()
+	if err != nil {
+		return
+	}
+	s.versionMu.Lock()
+	defer s.versionMu.Unlock()
+	s.versionKnown = false
+}
@@ -0,0 +1,273 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxSnapshots is how many automatic snapshots are kept per Data Plane API;
+// older ones are removed when a new one is taken.
+const MaxSnapshots = 20
+
+// RawConfigPath is the Data Plane API endpoint of the raw configuration.
+const RawConfigPath = "/services/haproxy/configuration/raw"
+
+const (
+	snapshotsDirName = "snapshots"
+	snapshotSuffix   = ".cfg"
+	snapshotInfoExt  = ".json"
+)
+
+// Snapshot is a raw HAProxy configuration stored before haproxyctl changed
+// it (see SetAutoSnapshots).
+type Snapshot struct {
+	Version int
+	TakenAt time.Time
+	// Endpoint is the API base URL the configuration was read from; empty
+	// for snapshots stored without one.
+	Endpoint string
+	path     string
+}
+
+// snapshotInfo is stored next to a snapshot as <version>.json.
+type snapshotInfo struct {
+	Endpoint string `json:"endpoint"`
+}
+
+var (
+	// autoSnapshots is set once per process from the global --no-snapshot
+	// flag; library callers and tests leave it off.
+	autoSnapshots bool
+
+	snapshotMu    sync.Mutex
+	snapshotTaken bool
+)
+
+// SetAutoSnapshots makes the first configuration change of this process
+// store the raw configuration it is about to change, so "haproxyctl
+// rollback" can push it back.
+func SetAutoSnapshots(enabled bool) {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+	autoSnapshots = enabled
+	snapshotTaken = false
+}
+
+// snapshotBeforeChange takes the automatic snapshot before the first
+// request of this process that changes the configuration: a write below
+// the configuration endpoints or a transaction start or commit. A snapshot
+// that cannot be taken is reported but does not stop the change.
+func snapshotBeforeChange(ctx context.Context, method, endpoint string) {
+	if method == http.MethodGet || !changesConfiguration(endpoint) {
+		return
+	}
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+	if !autoSnapshots || snapshotTaken {
+		return
+	}
+	// Set first: the requests below must not take a snapshot of their own.
+	snapshotTaken = true
+	if _, err := TakeSnapshot(ctx); err != nil {
+		Warnf("failed to snapshot the configuration before changing it: %v", err)
+	}
+}
+
+func changesConfiguration(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "/services/haproxy/configuration/") ||
+		strings.HasPrefix(endpoint, transactionsEndpoint)
+}
+
+// TakeSnapshot stores the current raw configuration of the active Data
+// Plane API under its version. A version that is already stored for the
+// same endpoint is left as is, and only the newest MaxSnapshots snapshots
+// are kept.
+func TakeSnapshot(ctx context.Context) (Snapshot, error) {
+	endpoint, err := SnapshotEndpoint()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	version, err := GetConfigurationVersion()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+	}
+	dir := snapshotDirFor(endpoint)
+	path := filepath.Join(dir, strconv.Itoa(version)+snapshotSuffix)
+	if existing, err := readSnapshot(path, version); err == nil && existing.Endpoint == endpoint {
+		return existing, nil
+	}
+
+	raw, err := SendRequestWithContext(ctx, "GET", RawConfigPath, nil, nil)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to fetch raw configuration: %w", err)
+	}
+	after, err := GetConfigurationVersion()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+	}
+	if after != version {
+		return Snapshot{}, fmt.Errorf("configuration changed while it was being read (version %d -> %d)", version, after)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	info, err := json.Marshal(snapshotInfo{Endpoint: endpoint})
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := os.WriteFile(snapshotInfoPath(path), info, 0o600); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := pruneSnapshots(dir); err != nil {
+		return Snapshot{}, err
+	}
+	Logf(1, "stored configuration version %d of %s for rollback", version, endpoint)
+	return Snapshot{Version: version, TakenAt: time.Now(), Endpoint: endpoint, path: path}, nil
+}
+
+// ListSnapshots returns the stored snapshots of the active Data Plane API,
+// oldest version first.
+func ListSnapshots() ([]Snapshot, error) {
+	endpoint, err := SnapshotEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	return listSnapshots(snapshotDirFor(endpoint))
+}
+
+func listSnapshots(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, e := range entries {
+		version, err := strconv.Atoi(strings.TrimSuffix(e.Name(), snapshotSuffix))
+		if e.IsDir() || !strings.HasSuffix(e.Name(), snapshotSuffix) || err != nil {
+			continue
+		}
+		s, err := readSnapshot(filepath.Join(dir, e.Name()), version)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Version < snapshots[j].Version })
+	return snapshots, nil
+}
+
+// Read returns the raw configuration stored in s.
+func (s Snapshot) Read() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot of version %d: %w", s.Version, err)
+	}
+	return data, nil
+}
+
+// readSnapshot returns the snapshot of version stored at path, with the
+// endpoint recorded next to it.
+func readSnapshot(path string, version int) (Snapshot, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read snapshot %s: %w", filepath.Base(path), err)
+	}
+	s := Snapshot{Version: version, TakenAt: stat.ModTime(), path: path}
+	data, err := os.ReadFile(snapshotInfoPath(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read snapshot %s: %w", filepath.Base(path), err)
+	}
+	var info snapshotInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to parse %s: %w", filepath.Base(snapshotInfoPath(path)), err)
+	}
+	s.Endpoint = info.Endpoint
+	return s, nil
+}
+
+func snapshotInfoPath(path string) string {
+	return strings.TrimSuffix(path, snapshotSuffix) + snapshotInfoExt
+}
+
+// pruneSnapshots removes all but the newest MaxSnapshots snapshots in dir.
+func pruneSnapshots(dir string) error {
+	snapshots, err := listSnapshots(dir)
+	if err != nil {
+		return err
+	}
+	for len(snapshots) > MaxSnapshots {
+		if err := os.Remove(snapshots[0].path); err != nil {
+			return fmt.Errorf("failed to remove old snapshot of version %d: %w", snapshots[0].Version, err)
+		}
+		if err := os.Remove(snapshotInfoPath(snapshots[0].path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove old snapshot of version %d: %w", snapshots[0].Version, err)
+		}
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
+
+// SnapshotEndpoint returns the normalized API base URL of the active Data
+// Plane API, which snapshots are stored and checked against.
+func SnapshotEndpoint() (string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(normalizeAPIBaseURL(cfg.APIBaseURL)), nil
+}
+
+// snapshotDirFor is where the snapshots of endpoint are kept:
+// snapshots/<host>-<hash> in the local state directory. Configuration
+// versions repeat across servers, so every endpoint has its own directory
+// whichever context, environment variable or flag named it.
+func snapshotDirFor(endpoint string) string {
+	sum := sha256.Sum256([]byte(endpoint))
+	host := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host = strings.NewReplacer("/", "_", ":", "_", string(filepath.Separator), "_", "..", "_").Replace(host)
+	return filepath.Join(stateDir(), snapshotsDirName, host+"-"+hex.EncodeToString(sum[:6]))
+}
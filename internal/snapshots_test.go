@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPruneSnapshots_KeepsNewest(t *testing.T) {
+	defer SetStateDir(t.TempDir())()
+	defer SetConfigOverride(&Config{APIBaseURL: "http://127.0.0.1:1"})()
+
+	endpoint, err := SnapshotEndpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := snapshotDirFor(endpoint)
+	if !strings.HasPrefix(filepath.Base(dir), "127.0.0.1_1-") {
+		t.Fatalf("snapshot dir = %s, want one named after the endpoint", dir)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for v := 1; v <= MaxSnapshots+3; v++ {
+		if err := os.WriteFile(filepath.Join(dir, strconv.Itoa(v)+snapshotSuffix), []byte("global\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pruneSnapshots(dir); err != nil {
+		t.Fatalf("pruneSnapshots failed: %v", err)
+	}
+	snapshots, err := ListSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != MaxSnapshots || snapshots[0].Version != 4 || snapshots[len(snapshots)-1].Version != MaxSnapshots+3 {
+		t.Fatalf("kept %d snapshots from version %d, want %d from version 4", len(snapshots), snapshots[0].Version, MaxSnapshots)
+	}
+}
+
+func TestSnapshotDir_PerEndpoint(t *testing.T) {
+	a := snapshotDirFor(normalizeAPIBaseURL("http://lb-a:5555"))
+	b := snapshotDirFor(normalizeAPIBaseURL("http://lb-b:5555"))
+	if a == b {
+		t.Fatalf("endpoints share the snapshot directory %s", a)
+	}
+	if again := snapshotDirFor(normalizeAPIBaseURL("http://lb-a:5555/v3/")); again != a {
+		t.Fatalf("snapshot dir = %s for the same endpoint, want %s", again, a)
+	}
+}
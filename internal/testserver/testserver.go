@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testserver points haproxyctl's request helpers at the fake Data
+// Plane API of pkg/client/clienttest.
+package testserver
+
+import (
+	"testing"
+
+	"haproxyctl/internal"
+	"haproxyctl/pkg/client/clienttest"
+)
+
+// Server is the fake Data Plane API.
+type Server = clienttest.Server
+
+// New starts a fake Data Plane API and points haproxyctl's request helpers
+// at it for the lifetime of the test. The server is closed and the config
+// override removed when the test finishes.
+func New(t testing.TB) *Server {
+	t.Helper()
+
+	s := clienttest.New(t)
+	restore := internal.SetConfigOverride(&internal.Config{
+		APIBaseURL: s.URL,
+		Username:   clienttest.Username,
+		Password:   clienttest.Password,
+	})
+	t.Cleanup(restore)
+
+	return s
+}
@@ -2,12 +2,56 @@ package internal
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"testing"
 )
 
 const testLogOutputEnv = "HAPROXYCTL_TEST_LOG_OUTPUT"
 
+// SetConfigFile makes the configuration be read from path instead of
+// ~/.config/haproxyctl/config.json, for tests that exercise context
+// resolution rather than a config override. It returns a function that
+// restores the previous path.
+func SetConfigFile(path string) func() {
+	previous := configFilePath
+	configFilePath = path
+	resetSession()
+	return func() {
+		configFilePath = previous
+		resetSession()
+	}
+}
+
+// SetKeyring replaces the system keyring with secrets, an in-memory one,
+// for tests of commands that store or remove keyring entries. It returns a
+// function that restores the previous keyring.
+func SetKeyring(secrets map[string]string) func() {
+	previous := systemKeyring
+	systemKeyring = memoryKeyring(secrets)
+	return func() { systemKeyring = previous }
+}
+
+type memoryKeyring map[string]string
+
+func (k memoryKeyring) Set(account, secret string) error {
+	k[account] = secret
+	return nil
+}
+
+func (k memoryKeyring) Get(account string) (string, error) {
+	secret, ok := k[account]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return secret, nil
+}
+
+func (k memoryKeyring) Delete(account string) error {
+	delete(k, account)
+	return nil
+}
+
 // CaptureStdout runs fn while capturing everything written to os.Stdout.
 // It returns the captured output as a string. When the HAPROXYCTL_TEST_LOG_OUTPUT
 // environment variable is set, the captured output is also logged via t.Logf.
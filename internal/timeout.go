@@ -0,0 +1,65 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultRequestTimeout bounds every Data Plane API request unless the
+// context sets "timeout" or --timeout says otherwise, so a hung API fails
+// the command instead of blocking it forever.
+const DefaultRequestTimeout = 30 * time.Second
+
+// requestTimeoutOverride is set once per process from the global --timeout
+// flag, when it is given.
+var requestTimeoutOverride *time.Duration
+
+// SetRequestTimeout sets the timeout of every request of this process,
+// over the one of the active context. Zero disables the timeout.
+func SetRequestTimeout(d time.Duration) {
+	requestTimeoutOverride = &d
+	resetSession()
+}
+
+// ClearRequestTimeout drops the --timeout value again, so the timeout of
+// the context or DefaultRequestTimeout applies.
+func ClearRequestTimeout() {
+	requestTimeoutOverride = nil
+	resetSession()
+}
+
+// requestTimeout returns the timeout for requests to cfg: --timeout, else
+// the "timeout" of the context (a Go duration such as "10s"; "0" disables
+// it), else DefaultRequestTimeout.
+func requestTimeout(cfg Config) (time.Duration, error) {
+	if requestTimeoutOverride != nil {
+		if *requestTimeoutOverride < 0 {
+			return 0, fmt.Errorf("invalid --timeout %s: must not be negative", *requestTimeoutOverride)
+		}
+		return *requestTimeoutOverride, nil
+	}
+	if cfg.Timeout == "" {
+		return DefaultRequestTimeout, nil
+	}
+	d, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid timeout %q in config: expected a duration such as 10s", cfg.Timeout)
+	}
+	return d, nil
+}
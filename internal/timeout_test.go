@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		_, _ = w.Write([]byte("1"))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	cfg := &Config{APIBaseURL: srv.URL, Timeout: "50ms"}
+	defer SetConfigOverride(cfg)()
+
+	start := time.Now()
+	if _, err := GetConfigurationVersion(); err == nil {
+		t.Fatal("expected the hung request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("request took %s, want about 50ms", elapsed)
+	}
+
+	// --timeout wins over the config.
+	SetRequestTimeout(time.Minute)
+	defer ClearRequestTimeout()
+	if d, err := requestTimeout(*cfg); err != nil || d != time.Minute {
+		t.Fatalf("requestTimeout with --timeout = %s, %v", d, err)
+	}
+	ClearRequestTimeout()
+
+	for value, want := range map[string]time.Duration{"": DefaultRequestTimeout, "0": 0, "2m": 2 * time.Minute} {
+		if d, err := requestTimeout(Config{Timeout: value}); err != nil || d != want {
+			t.Errorf("requestTimeout(%q) = %s, %v; want %s", value, d, err, want)
+		}
+	}
+	if _, err := requestTimeout(Config{Timeout: "soon"}); err == nil {
+		t.Error("expected error for an invalid timeout")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSOptions are the TLS settings given as global flags. Set fields take
+// precedence over the ones of the active context.
+type TLSOptions struct {
+	CertificateAuthority  string
+	ClientCertificate     string
+	ClientKey             string
+	InsecureSkipTLSVerify bool
+}
+
+var tlsOverrides TLSOptions
+
+// SetTLSOptions sets the TLS flag values for every request of this process.
+func SetTLSOptions(opts TLSOptions) {
+	tlsOverrides = opts
+	resetSession()
+}
+
+// effectiveTLS merges the TLS flags over the settings of cfg.
+func effectiveTLS(cfg Config) TLSOptions {
+	opts := TLSOptions{
+		CertificateAuthority:  cfg.CertificateAuthority,
+		ClientCertificate:     cfg.ClientCertificate,
+		ClientKey:             cfg.ClientKey,
+		InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify || tlsOverrides.InsecureSkipTLSVerify,
+	}
+	if tlsOverrides.CertificateAuthority != "" {
+		opts.CertificateAuthority = tlsOverrides.CertificateAuthority
+	}
+	if tlsOverrides.ClientCertificate != "" {
+		opts.ClientCertificate = tlsOverrides.ClientCertificate
+	}
+	if tlsOverrides.ClientKey != "" {
+		opts.ClientKey = tlsOverrides.ClientKey
+	}
+	return opts
+}
+
+// tlsTransport returns http.DefaultTransport, or a clone of it with a TLS
+// configuration when cfg or the TLS flags ask for a custom CA bundle, a
+// client certificate or skipping verification.
+func tlsTransport(cfg Config) (http.RoundTripper, error) {
+	opts := effectiveTLS(cfg)
+	if opts == (TLSOptions{}) {
+		return http.DefaultTransport, nil
+	}
+
+	tlsConfig, err := opts.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("unexpected default HTTP transport type")
+	}
+	transport = transport.Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+func (o TLSOptions) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Explicitly requested by the operator, e.g. for a lab with
+		// self-signed certificates.
+		InsecureSkipVerify: o.InsecureSkipTLSVerify, //nolint:gosec // opt-in via --insecure-skip-tls-verify
+	}
+
+	if o.CertificateAuthority != "" {
+		pem, err := os.ReadFile(o.CertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate authority: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", o.CertificateAuthority)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (o.ClientCertificate == "") != (o.ClientKey == "") {
+		return nil, errors.New("client certificate and client key must be set together")
+	}
+	if o.ClientCertificate != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCertificate, o.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
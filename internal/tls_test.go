@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSendRequest_TLSOptions(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("7"))
+	}))
+	defer srv.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caPath, caPEM, 0o600); err != nil {
+		t.Fatalf("write CA: %v", err)
+	}
+
+	cfg := &Config{APIBaseURL: srv.URL, Username: "admin", Password: "secret"}
+	defer SetConfigOverride(cfg)()
+	defer SetTLSOptions(TLSOptions{})
+
+	if _, err := GetConfigurationVersion(); err == nil {
+		t.Fatalf("expected certificate verification to fail without a CA")
+	}
+
+	cfg.CertificateAuthority = caPath
+	if v, err := GetConfigurationVersion(); err != nil || v != 7 {
+		t.Fatalf("with certificate_authority: version = %d, %v", v, err)
+	}
+
+	cfg.CertificateAuthority = ""
+	SetTLSOptions(TLSOptions{InsecureSkipTLSVerify: true})
+	if v, err := GetConfigurationVersion(); err != nil || v != 7 {
+		t.Fatalf("with --insecure-skip-tls-verify: version = %d, %v", v, err)
+	}
+
+	SetTLSOptions(TLSOptions{ClientCertificate: caPath})
+	if _, err := GetConfigurationVersion(); err == nil {
+		t.Fatalf("expected an error for a client certificate without key")
+	}
+}
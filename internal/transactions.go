@@ -0,0 +1,179 @@
+// Package internal contains shared helpers for haproxyctl.
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"haproxyctl/pkg/client"
+)
+
+const transactionsEndpoint = "/services/haproxy/transactions"
+
+// Transaction is an open Data Plane API configuration transaction. Changes
+// sent with its Params are staged and only take effect on Commit.
+type Transaction struct {
+	ID      string `json:"id"`
+	Version int    `json:"_version"` //nolint:tagliatelle // Data Plane API field name
+	Status  string `json:"status"`
+
+	// external marks the transaction selected with --transaction: the
+	// user commits or deletes it, so Commit and Abort leave it open.
+	external bool
+}
+
+// activeTransaction is the id given with the global --transaction flag.
+var activeTransaction string
+
+// SetActiveTransaction makes every change stage into the transaction id
+// instead of being applied on its own or in a transaction of its own. An
+// empty id restores the default.
+func SetActiveTransaction(id string) {
+	activeTransaction = id
+}
+
+// ActiveTransaction returns the id set with SetActiveTransaction.
+func ActiveTransaction() string {
+	return activeTransaction
+}
+
+// StartTransaction opens a transaction against the current configuration
+// version, or returns the active transaction (see SetActiveTransaction),
+// which Commit and Abort then leave open.
+func StartTransaction() (*Transaction, error) {
+	if activeTransaction != "" {
+		return &Transaction{ID: activeTransaction, Status: "in_progress", external: true}, nil
+	}
+	return OpenTransaction()
+}
+
+// OpenTransaction always opens a new transaction against the current
+// configuration version.
+func OpenTransaction() (*Transaction, error) {
+	version, err := GetConfigurationVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+	}
+
+	data, err := SendRequest("POST", transactionsEndpoint, map[string]string{"version": strconv.Itoa(version)}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	var tx Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("failed to parse transaction response: %w", err)
+	}
+	if tx.ID == "" {
+		return nil, fmt.Errorf("transaction response has no id: %s", string(data))
+	}
+	Logf(1, "opened transaction %s at version %d", tx.ID, version)
+	return &tx, nil
+}
+
+// Params returns the query parameters that stage a request in t.
+func (t *Transaction) Params() map[string]string {
+	return map[string]string{"transaction_id": t.ID}
+}
+
+// Client returns t as a transaction of the typed client (see API), or nil
+// for a nil t.
+func (t *Transaction) Client() *client.Transaction {
+	if t == nil {
+		return nil
+	}
+	return &client.Transaction{ID: t.ID, Version: t.Version, Status: t.Status}
+}
+
+// Commit applies all changes staged in t.
+func (t *Transaction) Commit() error {
+	if t.external {
+		return nil
+	}
+	if _, err := SendRequest("PUT", transactionsEndpoint+"/"+t.ID, nil, nil); err != nil {
+		return fmt.Errorf("failed to commit transaction %s: %w", t.ID, err)
+	}
+	Logf(1, "committed transaction %s", t.ID)
+	return nil
+}
+
+// CommitForReload commits t and returns the id of the reload the Data
+// Plane API runs for it in the background (see "get reloads"), or "" when
+// it did not report one.
+func (t *Transaction) CommitForReload(ctx context.Context) (string, error) {
+	if t.external {
+		return "", nil
+	}
+	_, header, err := SendRequestWithHeaders(ctx, "PUT", transactionsEndpoint+"/"+t.ID, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to commit transaction %s: %w", t.ID, err)
+	}
+	return header.Get("Reload-ID"), nil
+}
+
+// Abort discards t and everything staged in it.
+func (t *Transaction) Abort() error {
+	if t.external {
+		return nil
+	}
+	if _, err := SendRequest("DELETE", transactionsEndpoint+"/"+t.ID, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete transaction %s: %w", t.ID, err)
+	}
+	Logf(1, "deleted transaction %s", t.ID)
+	return nil
+}
+
+// WriteParams returns the query parameters for a configuration change: the
+// transaction's when tx is non-nil, then the active transaction's (see
+// SetActiveTransaction), otherwise the current configuration version,
+// which makes the change a standalone (immediately applied) one.
+func WriteParams(tx *Transaction) (map[string]string, error) {
+	if tx != nil {
+		return tx.Params(), nil
+	}
+	if activeTransaction != "" {
+		return map[string]string{"transaction_id": activeTransaction}, nil
+	}
+	version, err := currentConfigurationVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+	}
+	return map[string]string{"version": strconv.Itoa(version)}, nil
+}
+
+// WithTransaction runs fn inside a new transaction and commits it once, so
+// every change fn makes is applied atomically with a single reload. When
+// fn fails the transaction is aborted and nothing is applied.
+func WithTransaction(fn func(tx *Transaction) error) error {
+	tx, err := StartTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if abortErr := tx.Abort(); abortErr != nil {
+			return errors.Join(err, abortErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
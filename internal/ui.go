@@ -3,7 +3,6 @@ package internal
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"strings"
 )
@@ -17,6 +16,8 @@ const (
 	ActionUnchanged = "unchanged"
 	// ActionDeleted indicates a resource was deleted.
 	ActionDeleted = "deleted"
+	// ActionPruned indicates a resource was deleted by apply --prune.
+	ActionPruned = "pruned"
 )
 
 // ResourceID builds a kubectl-like identifier such as "backend/example-backend".
@@ -24,18 +25,55 @@ func ResourceID(kind, name string) string {
 	return fmt.Sprintf("%s/%s", strings.ToLower(kind), name)
 }
 
+// statusTally, when set by TallyStatuses, counts the actions printed by
+// PrintStatus.
+var statusTally map[string]int
+
+// TallyStatuses runs fn and returns how many times each action was printed
+// by PrintStatus meanwhile, e.g. for the summary of a directory apply.
+func TallyStatuses(fn func() error) (map[string]int, error) {
+	counts := map[string]int{}
+	statusTally = counts
+	defer func() { statusTally = nil }()
+
+	err := fn()
+	return counts, err
+}
+
 // PrintStatus prints a concise status line for a resource, for example:
-// "backend/example-backend created".
+// "backend/example-backend created". After a change sent to an endpoint in
+// cluster mode the node is named too: "backend/example-backend created
+// (node lb1)".
 func PrintStatus(kind, name, action string) {
-	if _, err := fmt.Fprintf(os.Stdout, "%s %s\n", ResourceID(kind, name), action); err != nil {
-		log.Printf("warning: failed to write status for %s %q: %v", strings.ToLower(kind), name, err)
+	if statusTally != nil {
+		statusTally[action]++
+	}
+	if _, err := fmt.Fprintf(os.Stdout, "%s %s%s\n", ResourceID(kind, name), action, clusterSuffix(action)); err != nil {
+		Warnf("failed to write status for %s %q: %v", strings.ToLower(kind), name, err)
 	}
 }
 
-// PrintDryRun prints a standard dry‑run message.
+// ColorEnabled reports whether output to f should be colorized: f must be a
+// terminal and NO_COLOR (https://no-color.org) must be unset.
+func ColorEnabled(f *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// PrintDryRun prints a standard dry‑run message. In offline mode the
+// message makes it explicit that the API was never contacted.
 func PrintDryRun() {
+	if IsOffline() {
+		if _, err := fmt.Fprintln(os.Stdout, "Offline mode enabled. No API calls made."); err != nil {
+			Warnf("failed to write offline message: %v", err)
+		}
+		return
+	}
 	if _, err := fmt.Fprintln(os.Stdout, "Dry run mode enabled. No changes made."); err != nil {
-		log.Printf("warning: failed to write dry-run message: %v", err)
+		Warnf("failed to write dry-run message: %v", err)
 	}
 }
 
@@ -51,23 +89,13 @@ func IsAlreadyExistsError(err error) bool {
 
 // FormatAPIError normalizes HAProxy API errors into user‑friendly messages.
 // It recognises common cases like 404 and 409 and falls back to a generic
-// description otherwise.
+// description otherwise. The result is a *ResourceError, so the object
+// and operation are kept for --error-format json.
 func FormatAPIError(kind, name, operation string, err error) error {
 	if err == nil {
 		return nil
 	}
-
-	lowerKind := strings.ToLower(kind)
-
-	if IsAlreadyExistsError(err) {
-		return fmt.Errorf("%s %q already exists (consider using 'haproxyctl apply -f ...')", lowerKind, name)
-	}
-	if IsNotFoundError(err) {
-		return fmt.Errorf("%s %q not found", lowerKind, name)
-	}
-
-	// Preserve original context for unexpected errors.
-	return fmt.Errorf("HAProxy API %s %s: %w", operation, ResourceID(kind, name), err)
+	return &ResourceError{Kind: kind, Name: name, Operation: operation, Err: err}
 }
 
 // WrapIfAPIError applies FormatAPIError only when err is non‑nil.
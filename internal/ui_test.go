@@ -35,6 +35,27 @@ func TestPrintStatus(t *testing.T) {
 	}
 }
 
+func TestTallyStatuses(t *testing.T) {
+	var counts map[string]int
+	var err error
+	CaptureStdout(t, func() {
+		counts, err = TallyStatuses(func() error {
+			PrintStatus("Backend", "a", ActionCreated)
+			PrintStatus("Backend", "b", ActionCreated)
+			PrintStatus("Frontend", "web", ActionUnchanged)
+			return errors.New("boom")
+		})
+		PrintStatus("Backend", "c", ActionCreated)
+	})
+
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("expected fn error to be returned, got %v", err)
+	}
+	if counts[ActionCreated] != 2 || counts[ActionUnchanged] != 1 || counts[ActionConfigured] != 0 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+}
+
 func TestPrintDryRun(t *testing.T) {
 	output := CaptureStdout(t, func() {
 		PrintDryRun()
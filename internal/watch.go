@@ -0,0 +1,185 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags of the get commands that support watching.
+const (
+	WatchFlag         = "watch"
+	WatchIntervalFlag = "watch-interval"
+
+	defaultWatchInterval = 2 * time.Second
+)
+
+// Watch events printed after the initial listing.
+const (
+	EventAdded    = "added"
+	EventModified = "modified"
+	EventDeleted  = "deleted"
+)
+
+// AddWatchFlags registers -w/--watch and --watch-interval on a get command.
+func AddWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP(WatchFlag, "w", false, "After listing, watch for changes and print them as they happen")
+	cmd.Flags().Duration(WatchIntervalFlag, defaultWatchInterval, "How often --watch polls the configuration version")
+}
+
+// WatchRequested reports whether --watch is set on cmd.
+func WatchRequested(cmd *cobra.Command) bool {
+	return cmd.Flags().Lookup(WatchFlag) != nil && GetFlagBool(cmd, WatchFlag)
+}
+
+// WatchListForCmd polls the configuration version every --watch-interval
+// and, whenever it changes, re-lists endpoint and prints the objects of kind
+// that were added, modified or deleted. It runs until the command is
+// interrupted.
+func WatchListForCmd(cmd *cobra.Command, kind, endpoint string) error {
+	interval, err := cmd.Flags().GetDuration(WatchIntervalFlag)
+	if err != nil {
+		return fmt.Errorf("failed to read flag %s: %w", WatchIntervalFlag, err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("--%s must be positive, got %s", WatchIntervalFlag, interval)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	fetch := func(ctx context.Context) ([]map[string]interface{}, error) {
+		data, err := SendRequestWithContext(ctx, "GET", endpoint, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var list []map[string]interface{}
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		return list, nil
+	}
+	version := func(context.Context) (int, error) {
+		return GetConfigurationVersion()
+	}
+	return watchList(ctx, os.Stdout, kind, fetch, version, interval)
+}
+
+// watchList implements WatchListForCmd. A failed poll is reported and
+// retried on the next tick so that a flapping API does not end the watch.
+func watchList(
+	ctx context.Context,
+	w io.Writer,
+	kind string,
+	fetch func(context.Context) ([]map[string]interface{}, error),
+	version func(context.Context) (int, error),
+	interval time.Duration,
+) error {
+	last, err := version(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch configuration version: %w", err)
+	}
+	list, err := fetch(ctx)
+	if err != nil {
+		return err
+	}
+	known := indexByName(list)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := version(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			Warnf("failed to fetch configuration version: %v", err)
+			continue
+		}
+		if current == last {
+			continue
+		}
+		list, err := fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			Warnf("failed to list %ss: %v", kind, err)
+			continue
+		}
+		next := indexByName(list)
+		for _, event := range diffByName(known, next) {
+			if _, err := fmt.Fprintf(w, "%s %s (version %d)\n", ResourceID(kind, event.name), event.action, current); err != nil {
+				return err
+			}
+		}
+		known, last = next, current
+	}
+}
+
+type watchEvent struct {
+	name   string
+	action string
+}
+
+func indexByName(list []map[string]interface{}) map[string]map[string]interface{} {
+	out := make(map[string]map[string]interface{}, len(list))
+	for _, item := range list {
+		if name, ok := item["name"].(string); ok {
+			out[name] = item
+		}
+	}
+	return out
+}
+
+// diffByName returns the events turning before into after, sorted by name.
+func diffByName(before, after map[string]map[string]interface{}) []watchEvent {
+	var events []watchEvent
+	for name, item := range after {
+		old, ok := before[name]
+		switch {
+		case !ok:
+			events = append(events, watchEvent{name: name, action: EventAdded})
+		case !reflect.DeepEqual(old, item):
+			events = append(events, watchEvent{name: name, action: EventModified})
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			events = append(events, watchEvent{name: name, action: EventDeleted})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].name < events[j].name })
+	return events
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWatchSource replays a sequence of (version, list) snapshots, one per
+// poll, and cancels the watch once they are used up.
+type fakeWatchSource struct {
+	mu        sync.Mutex
+	snapshots []watchSnapshot
+	cancel    context.CancelFunc
+}
+
+type watchSnapshot struct {
+	version int
+	list    []map[string]interface{}
+	err     error
+}
+
+func (f *fakeWatchSource) current() watchSnapshot {
+	return f.snapshots[0]
+}
+
+func (f *fakeWatchSource) version(context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.snapshots) > 1 {
+		f.snapshots = f.snapshots[1:]
+	} else {
+		f.cancel()
+	}
+	return f.current().version, f.current().err
+}
+
+func (f *fakeWatchSource) fetch(context.Context) ([]map[string]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.current().list, nil
+}
+
+func TestWatchList_PrintsChanges(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	web := map[string]interface{}{"name": "web", "mode": "http"}
+	src := &fakeWatchSource{cancel: cancel, snapshots: []watchSnapshot{
+		// Every version call advances first, so the first entry is skipped.
+		{version: 1, list: []map[string]interface{}{web}},
+		{version: 1, list: []map[string]interface{}{web}},
+		{version: 2, list: []map[string]interface{}{web, {"name": "api"}}},
+		{err: errors.New("connection refused")},
+		{version: 3, list: []map[string]interface{}{{"name": "web", "mode": "tcp"}, {"name": "api"}}},
+		{version: 3, list: []map[string]interface{}{{"name": "web", "mode": "tcp"}, {"name": "api"}}},
+		{version: 4, list: []map[string]interface{}{{"name": "web", "mode": "tcp"}}},
+	}}
+
+	var out strings.Builder
+	if err := watchList(ctx, &out, "Backend", src.fetch, src.version, time.Millisecond); err != nil {
+		t.Fatalf("watchList failed: %v", err)
+	}
+
+	want := "backend/api added (version 2)\nbackend/web modified (version 3)\nbackend/api deleted (version 4)\n"
+	if out.String() != want {
+		t.Fatalf("unexpected events:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package internal contains shared helpers for haproxyctl.
+package internal
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// apiWrites is set once a change was sent to the Data Plane API, so
+// PrintStatus only names the cluster node for statuses that follow a real
+// write.
+var apiWrites atomic.Bool
+
+// lastReloadID is the Reload-ID of the most recent change the Data Plane
+// API applies with a background reload.
+var (
+	reloadMu     sync.Mutex
+	lastReloadID string
+)
+
+// noteResponse records a successful non-GET request and the reload it
+// started, if the API named one.
+func noteResponse(method string, header http.Header, err error) {
+	if err != nil || method == http.MethodGet {
+		return
+	}
+	apiWrites.Store(true)
+	forgetVersion()
+	if id := header.Get("Reload-ID"); id != "" {
+		reloadMu.Lock()
+		lastReloadID = id
+		reloadMu.Unlock()
+	}
+}
+
+// noteVersionedChange advances the cached configuration version (see
+// cachedVersion) after a successful change sent with a "version"
+// parameter: the API bumped it by one. Starting a transaction and files
+// with versions of their own (SPOE) leave the configuration version alone.
+func noteVersionedChange(endpoint string, params map[string]string, err error) {
+	if err != nil || !strings.HasPrefix(endpoint, "/services/haproxy/configuration/") || strings.Contains(endpoint, "/transactions") {
+		return
+	}
+	if version, perr := strconv.Atoi(params["version"]); perr == nil {
+		storeVersion(version + 1)
+	}
+}
+
+// LastReloadID returns the id of the reload started by the latest change
+// of this process, or "" when no change started one. HAProxy reloads with
+// the whole current configuration, so once that reload finished every
+// earlier change is live too.
+func LastReloadID() string {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	return lastReloadID
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"reflect"
+)
+
+// Result says what an Apply call did.
+type Result string
+
+const (
+	// Created means the object did not exist and was created.
+	Created Result = "created"
+	// Configured means the object existed and was changed.
+	Configured Result = "configured"
+	// Unchanged means the object already matched.
+	Unchanged Result = "unchanged"
+)
+
+// ApplyBackend creates the backend with its servers, or replaces an
+// existing one and reconciles its servers by name (adding, updating and
+// removing servers so exactly the given ones remain). All changes are made
+// in one transaction. Fields kept in Extra are carried over from the live
+// objects when b or a server has none, so they are not counted as changes.
+func (c *Client) ApplyBackend(ctx context.Context, b Backend, servers []Server) (Result, error) {
+	current, err := c.GetBackend(ctx, b.Name)
+	if err != nil && !IsNotFound(err) {
+		return "", err
+	}
+
+	if IsNotFound(err) {
+		err := c.WithTransaction(ctx, func(tx *Transaction) error {
+			if err := c.CreateBackend(ctx, tx, b); err != nil {
+				return err
+			}
+			for _, s := range servers {
+				if err := c.CreateServer(ctx, tx, b.Name, s); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		return Created, nil
+	}
+
+	if b.Extra == nil {
+		b.Extra = current.Extra
+	}
+
+	live, err := c.ListServers(ctx, b.Name)
+	if err != nil {
+		return "", err
+	}
+	liveByName := make(map[string]Server, len(live))
+	for _, s := range live {
+		liveByName[s.Name] = s
+	}
+
+	var create, update []Server
+	wanted := make(map[string]bool, len(servers))
+	for _, s := range servers {
+		wanted[s.Name] = true
+		existing, ok := liveByName[s.Name]
+		if !ok {
+			create = append(create, s)
+			continue
+		}
+		if s.Extra == nil {
+			s.Extra = existing.Extra
+		}
+		if !reflect.DeepEqual(existing, s) {
+			update = append(update, s)
+		}
+	}
+	var remove []string
+	for _, s := range live {
+		if !wanted[s.Name] {
+			remove = append(remove, s.Name)
+		}
+	}
+
+	if reflect.DeepEqual(current, b) && len(create)+len(update)+len(remove) == 0 {
+		return Unchanged, nil
+	}
+
+	err = c.WithTransaction(ctx, func(tx *Transaction) error {
+		if err := c.UpdateBackend(ctx, tx, b); err != nil {
+			return err
+		}
+		for _, name := range remove {
+			if err := c.DeleteServer(ctx, tx, b.Name, name); err != nil {
+				return err
+			}
+		}
+		for _, s := range update {
+			if err := c.UpdateServer(ctx, tx, b.Name, s); err != nil {
+				return err
+			}
+		}
+		for _, s := range create {
+			if err := c.CreateServer(ctx, tx, b.Name, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return Configured, nil
+}
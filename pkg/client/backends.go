@@ -0,0 +1,90 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+const backendsEndpoint = "/services/haproxy/configuration/backends"
+
+// Balance is a backend's load-balancing settings.
+type Balance struct {
+	Algorithm string `json:"algorithm"`
+}
+
+// Backend is a backend section. Commonly used fields are modeled; every
+// other field of the API object (health checks, stick tables, ...) is
+// kept in Extra, so a backend read with GetBackend is written back
+// unchanged by UpdateBackend.
+//
+//nolint:tagliatelle // Data Plane API field names
+type Backend struct {
+	Name    string   `json:"name"`
+	Mode    string   `json:"mode,omitempty"`
+	Balance *Balance `json:"balance,omitempty"`
+	// Timeouts are in milliseconds.
+	TimeoutServer int `json:"timeout_server,omitempty"`
+	TimeoutQueue  int `json:"timeout_queue,omitempty"`
+
+	Extra map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON encodes b with its Extra fields.
+func (b Backend) MarshalJSON() ([]byte, error) {
+	type plain Backend
+	return marshalWithExtra(plain(b), b.Extra)
+}
+
+// UnmarshalJSON decodes b, keeping the fields it does not model in Extra.
+func (b *Backend) UnmarshalJSON(data []byte) error {
+	type plain Backend
+	return unmarshalWithExtra(data, (*plain)(b), &b.Extra)
+}
+
+// ListBackends returns all backends.
+func (c *Client) ListBackends(ctx context.Context) ([]Backend, error) {
+	var out []Backend
+	if err := c.get(ctx, backendsEndpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetBackend returns the backend called name; see IsNotFound.
+func (c *Client) GetBackend(ctx context.Context, name string) (Backend, error) {
+	var out Backend
+	err := c.get(ctx, backendsEndpoint+"/"+url.PathEscape(name), &out)
+	return out, err
+}
+
+// CreateBackend adds a backend.
+func (c *Client) CreateBackend(ctx context.Context, tx *Transaction, b Backend) error {
+	return c.write(ctx, tx, http.MethodPost, backendsEndpoint, b)
+}
+
+// UpdateBackend replaces the backend called b.Name.
+func (c *Client) UpdateBackend(ctx context.Context, tx *Transaction, b Backend) error {
+	return c.write(ctx, tx, http.MethodPut, backendsEndpoint+"/"+url.PathEscape(b.Name), b)
+}
+
+// DeleteBackend removes the backend called name and its servers.
+func (c *Client) DeleteBackend(ctx context.Context, tx *Transaction, name string) error {
+	return c.write(ctx, tx, http.MethodDelete, backendsEndpoint+"/"+url.PathEscape(name), nil)
+}
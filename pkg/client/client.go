@@ -0,0 +1,290 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client is a typed Go client for the HAProxy Data Plane API v3.
+//
+// It is the transport haproxyctl itself uses, exposed so other programs
+// (for example Kubernetes operators) can manage HAProxy the same way the
+// CLI does without shelling out to it:
+//
+//	c := client.New("http://lb:5555", "admin", "secret")
+//	backends, err := c.ListBackends(ctx)
+//
+// Methods that change the configuration take a *Transaction. With a nil
+// transaction the change is applied on its own against the current
+// configuration version; otherwise it is staged in the transaction until
+// CommitTransaction.
+//
+// Errors of the Data Plane API are returned as *APIError, without the
+// object they are about; callers add it to their own messages.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const httpErrorThreshold = 300
+
+// Client talks to one Data Plane API endpoint.
+type Client struct {
+	baseURL     string
+	username    string
+	password    string
+	tokenSource TokenSource
+	httpClient  *http.Client
+	requester   Requester
+}
+
+// Requester sends the requests of the typed methods. A *Client is its own
+// Requester; WithRequester plugs in another one.
+type Requester interface {
+	Do(ctx context.Context, method, endpoint string, params map[string]string, body interface{}) ([]byte, error)
+	// WriteParams returns the query parameters of a configuration change:
+	// those staging it in tx, or applying it on its own when tx is nil.
+	WriteParams(ctx context.Context, tx *Transaction) (map[string]string, error)
+}
+
+// TokenSource returns the bearer token to send with a request.
+type TokenSource func(ctx context.Context) (string, error)
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient makes the client send requests through hc, e.g. to set
+// timeouts, TLS settings or a recording transport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRequester makes the typed methods send their requests through r,
+// e.g. to add retries or logging, or to base standalone changes on a
+// cached configuration version. haproxyctl uses it to give the typed
+// methods the behaviour of its other requests.
+func WithRequester(r Requester) Option {
+	return func(c *Client) { c.requester = r }
+}
+
+// WithBearerToken authenticates with "Authorization: Bearer <token>"
+// instead of basic auth, e.g. behind an OAuth proxy.
+func WithBearerToken(token string) Option {
+	return WithTokenSource(func(context.Context) (string, error) { return token, nil })
+}
+
+// WithTokenSource authenticates every request with a bearer token from ts
+// instead of basic auth, for tokens that expire or are fetched on demand.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) { c.tokenSource = ts }
+}
+
+// New returns a client for the Data Plane API at baseURL, which may omit
+// the API version ("http://host:5555" means "http://host:5555/v3").
+func New(baseURL, username, password string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    NormalizeBaseURL(baseURL),
+		username:   username,
+		password:   password,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NormalizeBaseURL ensures the API base URL includes a version prefix
+// (defaulting to v3) and has no trailing slash.
+func NormalizeBaseURL(raw string) string {
+	base := strings.TrimRight(strings.TrimSpace(raw), "/")
+
+	if strings.HasSuffix(base, "/v1") ||
+		strings.HasSuffix(base, "/v2") ||
+		strings.HasSuffix(base, "/v3") {
+		return base
+	}
+
+	// Default to Data Plane API v3 when no explicit version is present.
+	return base + "/v3"
+}
+
+// APIError is a non-2xx response from the Data Plane API.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HAProxy API error (%d): %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is a 404 from the Data Plane API.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Do sends a request to endpoint (relative to the base URL, e.g.
+// "/services/haproxy/configuration/backends") with body encoded as JSON,
+// and returns the raw response body.
+func (c *Client) Do(ctx context.Context, method, endpoint string, params map[string]string, body interface{}) ([]byte, error) {
+	data, _, err := c.DoWithHeaders(ctx, method, endpoint, params, body)
+	return data, err
+}
+
+// DoWithHeaders is Do that also returns the response headers, such as the
+// Reload-ID of a change HAProxy applies in the background.
+func (c *Client) DoWithHeaders(ctx context.Context, method, endpoint string, params map[string]string, body interface{}) ([]byte, http.Header, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+	return c.do(ctx, method, endpoint, params, reqBody, "application/json")
+}
+
+// DoRaw sends body as is with the given content type, e.g. a complete
+// HAProxy configuration as text/plain.
+func (c *Client) DoRaw(ctx context.Context, method, endpoint string, params map[string]string, body []byte, contentType string) ([]byte, error) {
+	data, _, err := c.do(ctx, method, endpoint, params, body, contentType)
+	return data, err
+}
+
+// DoRawWithHeaders is DoRaw that also returns the response headers.
+func (c *Client) DoRawWithHeaders(ctx context.Context, method, endpoint string, params map[string]string, body []byte, contentType string) ([]byte, http.Header, error) {
+	return c.do(ctx, method, endpoint, params, body, contentType)
+}
+
+// do sends one request and returns the response body and headers.
+func (c *Client) do(ctx context.Context, method, endpoint string, params map[string]string, body []byte, contentType string) ([]byte, http.Header, error) {
+	u := c.baseURL + endpoint
+	if len(params) > 0 {
+		q := url.Values{}
+		for k, v := range params {
+			q.Set(k, v)
+		}
+		u += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create API request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if err := c.authorize(ctx, req); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if resp.StatusCode >= httpErrorThreshold {
+			return nil, nil, fmt.Errorf("HAProxy API error (%d) and failed to read error body: %w", resp.StatusCode, err)
+		}
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode >= httpErrorThreshold {
+		return nil, resp.Header, &APIError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+	return data, resp.Header, nil
+}
+
+// authorize sets the bearer token of the token source, or basic auth.
+func (c *Client) authorize(ctx context.Context, req *http.Request) error {
+	if c.tokenSource == nil {
+		req.SetBasicAuth(c.username, c.password)
+		return nil
+	}
+	token, err := c.tokenSource(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get bearer token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// requests returns the Requester of the typed methods.
+func (c *Client) requests() Requester {
+	if c.requester != nil {
+		return c.requester
+	}
+	return c
+}
+
+// get decodes the JSON response of a GET request into out.
+func (c *Client) get(ctx context.Context, endpoint string, out interface{}) error {
+	data, err := c.requests().Do(ctx, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response of %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// write sends a configuration change, staged in tx or, with a nil tx,
+// applied directly against the current configuration version.
+func (c *Client) write(ctx context.Context, tx *Transaction, method, endpoint string, body interface{}) error {
+	r := c.requests()
+	params, err := r.WriteParams(ctx, tx)
+	if err != nil {
+		return err
+	}
+	_, err = r.Do(ctx, method, endpoint, params, body)
+	return err
+}
+
+// WriteParams returns the transaction_id parameter of tx, or with a nil tx
+// the current configuration version.
+func (c *Client) WriteParams(ctx context.Context, tx *Transaction) (map[string]string, error) {
+	if tx != nil {
+		return map[string]string{"transaction_id": tx.ID}, nil
+	}
+	version, err := c.ConfigurationVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"version": strconv.Itoa(version)}, nil
+}
+
+// ConfigurationVersion returns the current configuration version.
+func (c *Client) ConfigurationVersion(ctx context.Context) (int, error) {
+	data, err := c.requests().Do(ctx, http.MethodGet, "/services/haproxy/configuration/version", nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch HAProxy configuration version: %w", err)
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse version as integer: %w", err)
+	}
+	return version, nil
+}
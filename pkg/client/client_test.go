@@ -0,0 +1,226 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"haproxyctl/pkg/client"
+	"haproxyctl/pkg/client/clienttest"
+)
+
+func newTestClient(t *testing.T) (*client.Client, *clienttest.Server) {
+	t.Helper()
+
+	srv := clienttest.New(t)
+
+	return client.New(srv.URL, clienttest.Username, clienttest.Password), srv
+}
+
+func TestWithTransaction(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	err := c.WithTransaction(ctx, func(tx *client.Transaction) error {
+		if err := c.CreateBackend(ctx, tx, client.Backend{Name: "web", Mode: "http", Balance: &client.Balance{Algorithm: "roundrobin"}}); err != nil {
+			return err
+		}
+		for _, s := range []client.Server{
+			{Name: "s1", Address: "10.0.0.1", Port: 80},
+			{Name: "s2", Address: "10.0.0.2", Port: 80},
+		} {
+			if err := c.CreateServer(ctx, tx, "web", s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	live, err := c.ListServers(ctx, "web")
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+	if len(live) != 2 {
+		t.Fatalf("servers = %+v, want s1 and s2", live)
+	}
+	// The backend and its servers were committed at once, from version 1.
+	if got := srv.Version(); got != 2 {
+		t.Fatalf("configuration version = %d, want 2", got)
+	}
+}
+
+func TestBackendKeepsUnmodeledFields(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	srv.AddBackend(map[string]interface{}{"name": "web", "mode": "http", "adv_check": "httpchk", "retries": 3})
+
+	b, err := c.GetBackend(ctx, "web")
+	if err != nil {
+		t.Fatalf("GetBackend() error = %v", err)
+	}
+	if b.Extra["adv_check"] != "httpchk" {
+		t.Fatalf("Extra = %v, want adv_check", b.Extra)
+	}
+	b.Mode = "tcp"
+	if err := c.UpdateBackend(ctx, nil, b); err != nil {
+		t.Fatalf("UpdateBackend() error = %v", err)
+	}
+
+	stored, _ := srv.Backend("web")
+	if stored["mode"] != "tcp" || stored["adv_check"] != "httpchk" || stored["retries"] != float64(3) {
+		t.Fatalf("stored backend = %v, want mode tcp with adv_check and retries kept", stored)
+	}
+}
+
+// stagingRequester stages every change in one fixed transaction.
+type stagingRequester struct {
+	*client.Client
+	txID string
+}
+
+func (r stagingRequester) WriteParams(context.Context, *client.Transaction) (map[string]string, error) {
+	return map[string]string{"transaction_id": r.txID}, nil
+}
+
+func TestWithRequester(t *testing.T) {
+	t.Parallel()
+
+	raw, srv := newTestClient(t)
+	ctx := context.Background()
+	tx, err := raw.StartTransaction(ctx)
+	if err != nil {
+		t.Fatalf("StartTransaction() error = %v", err)
+	}
+
+	c := client.New("", "", "", client.WithRequester(stagingRequester{Client: raw, txID: tx.ID}))
+	if err := c.CreateBackend(ctx, nil, client.Backend{Name: "api"}); err != nil {
+		t.Fatalf("CreateBackend() error = %v", err)
+	}
+	if _, ok := srv.Backend("api"); ok {
+		t.Fatal("backend applied before the transaction was committed")
+	}
+	if err := raw.CommitTransaction(ctx, tx); err != nil {
+		t.Fatalf("CommitTransaction() error = %v", err)
+	}
+	if _, ok := srv.Backend("api"); !ok {
+		t.Fatal("backend missing after commit")
+	}
+}
+
+func TestApplyBackend(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	backend := client.Backend{Name: "web", Mode: "http", Balance: &client.Balance{Algorithm: "roundrobin"}}
+	servers := []client.Server{
+		{Name: "s1", Address: "10.0.0.1", Port: 80},
+		{Name: "s2", Address: "10.0.0.2", Port: 80},
+	}
+
+	steps := []struct {
+		servers []client.Server
+		want    client.Result
+	}{
+		{servers, client.Created},
+		{servers, client.Unchanged},
+		{servers[:1], client.Configured},
+	}
+	for i, step := range steps {
+		got, err := c.ApplyBackend(ctx, backend, step.servers)
+		if err != nil {
+			t.Fatalf("step %d: ApplyBackend() error = %v", i, err)
+		}
+		if got != step.want {
+			t.Fatalf("step %d: ApplyBackend() = %s, want %s", i, got, step.want)
+		}
+	}
+
+	live, err := c.ListServers(ctx, "web")
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+	if len(live) != 1 || live[0].Name != "s1" {
+		t.Fatalf("servers after apply = %+v, want only s1", live)
+	}
+	// Create and update each committed exactly one transaction.
+	if got := srv.Version(); got != 3 {
+		t.Fatalf("configuration version = %d, want 3", got)
+	}
+}
+
+func TestNamesAreEscaped(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	srv.AddBackend(map[string]interface{}{"name": "a#b"})
+	srv.AddServer("a#b", map[string]interface{}{"name": "s?1", "address": "10.0.0.1", "port": 80})
+
+	if _, err := c.GetBackend(ctx, "a#b"); err != nil {
+		t.Fatalf("GetBackend() error = %v", err)
+	}
+	if _, err := c.GetServer(ctx, "a#b", "s?1"); err != nil {
+		t.Fatalf("GetServer() error = %v", err)
+	}
+	if err := c.DeleteServer(ctx, nil, "a#b", "s?1"); err != nil {
+		t.Fatalf("DeleteServer() error = %v", err)
+	}
+}
+
+func TestGetBackendNotFound(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestClient(t)
+
+	_, err := c.GetBackend(context.Background(), "missing")
+	if !client.IsNotFound(err) {
+		t.Fatalf("GetBackend() error = %v, want not found", err)
+	}
+}
+
+func TestDirectWriteUsesCurrentVersion(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	if err := c.CreateBackend(ctx, nil, client.Backend{Name: "api"}); err != nil {
+		t.Fatalf("CreateBackend() error = %v", err)
+	}
+	if err := c.CreateServer(ctx, nil, "api", client.Server{Name: "a1", Address: "10.0.0.5", Port: 8080}); err != nil {
+		t.Fatalf("CreateServer() error = %v", err)
+	}
+	if got := len(srv.Servers("api")); got != 1 {
+		t.Fatalf("servers = %d, want 1", got)
+	}
+
+	backends, err := c.ListBackends(ctx)
+	if err != nil || len(backends) != 1 || backends[0].Name != "api" {
+		t.Fatalf("ListBackends() = %+v, %v", backends, err)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	t.Parallel()
+
+	srv := clienttest.New(t)
+	ctx := context.Background()
+
+	c := client.New(srv.URL, "", "", client.WithBearerToken(clienttest.Token))
+	if _, err := c.ConfigurationVersion(ctx); err != nil {
+		t.Fatalf("ConfigurationVersion() with bearer token error = %v", err)
+	}
+
+	c = client.New(srv.URL, "", "", client.WithBearerToken("wrong"))
+	if _, err := c.ConfigurationVersion(ctx); err == nil {
+		t.Fatalf("expected an error for a wrong bearer token")
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"net/http"
+)
+
+// ClusterName, ClusterAddress and ClusterPort describe the cluster a node
+// joins with any bootstrap key.
+const (
+	ClusterName    = "prod"
+	ClusterAddress = "10.0.0.1"
+	ClusterPort    = 5555
+)
+
+// ClusterMode returns "cluster" after a join and "single" otherwise.
+func (s *Server) ClusterMode() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cluster != nil {
+		return "cluster"
+	}
+	return "single"
+}
+
+// clusterEndpoints registers /cluster: reading the settings, joining with
+// a bootstrap key and leaving.
+func (s *Server) clusterEndpoints(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v3/cluster", func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, s.clusterSettings())
+	})
+
+	mux.HandleFunc("POST /v3/cluster", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		if key, _ := obj["bootstrap_key"].(string); key == "" {
+			writeError(w, http.StatusBadRequest, "bootstrap_key is required")
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.cluster = map[string]interface{}{"name": ClusterName, "address": ClusterAddress, "port": ClusterPort, "api_base_path": "/v3"}
+		writeJSON(w, http.StatusOK, s.clusterSettings())
+	})
+
+	mux.HandleFunc("DELETE /v3/cluster", func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.cluster = nil
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// clusterSettings returns the /cluster response. Callers must hold s.mu.
+func (s *Server) clusterSettings() map[string]interface{} {
+	if s.cluster == nil {
+		return map[string]interface{}{"mode": "single"}
+	}
+	return map[string]interface{}{"mode": "cluster", "status": "active", "cluster": copyObject(s.cluster)}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Indexed lists are the ordered sub-resources of a frontend or backend
+// (acls, http_request_rules, ...), addressed by position rather than name.
+// They are stored per "<ptype>/<parent>/<list>" key.
+
+func listKey(ptype, parent, list string) string {
+	return ptype + "/" + parent + "/" + list
+}
+
+// AddListItem appends obj to an indexed list without bumping the
+// configuration version, e.g. AddListItem("frontends", "web", "acls", acl).
+func (s *Server) AddListItem(ptype, parent, list string, obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := listKey(ptype, parent, list)
+	s.state.lists[key] = append(s.state.lists[key], copyObject(obj))
+}
+
+// List returns the items of an indexed list, with their "index" set.
+func (s *Server) List(ptype, parent, list string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.indexedList(listKey(ptype, parent, list))
+}
+
+// indexedList returns copies of the list items with "index" filled in.
+func (st *state) indexedList(key string) []map[string]interface{} {
+	items := st.lists[key]
+	out := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		obj := copyObject(item)
+		obj["index"] = i
+		out = append(out, obj)
+	}
+	return out
+}
+
+// parentExists reports whether the section owning an indexed list exists.
+func (st *state) parentExists(ptype, parent string) bool {
+	switch ptype {
+	case "frontends":
+		_, ok := st.frontends.get(parent)
+		return ok
+	case "backends":
+		_, ok := st.backends.get(parent)
+		return ok
+	case "log_forwards":
+		_, ok := st.logForwards.get(parent)
+		return ok
+	case "global":
+		return true
+	default:
+		return false
+	}
+}
+
+// indexedLists registers handlers for every indexed list under frontends,
+// backends and log forwards, plus the log targets of the global section,
+// which are kept under the "global//log_targets" key. More specific routes
+// (servers, binds) take precedence.
+func (s *Server) indexedLists(mux *http.ServeMux) {
+	s.indexedListRoutes(mux, apiPrefix+"/configuration/{ptype}/{parent}/{list}", listKeyFromRequest)
+	s.indexedListRoutes(mux, apiPrefix+"/configuration/global/log_targets", func(*http.Request) string {
+		return listKey("global", "", "log_targets")
+	})
+}
+
+// indexedListRoutes registers the list and item handlers of the indexed
+// lists matched by base; keyOf maps a request to its list.
+func (s *Server) indexedListRoutes(mux *http.ServeMux, base string, keyOf func(*http.Request) string) {
+	mux.HandleFunc("GET "+base, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		st, status, msg := s.readState(r)
+		if status != 0 {
+			writeError(w, status, msg)
+			return
+		}
+		if !st.parentExists(parentOfKey(keyOf(r))) {
+			writeError(w, http.StatusNotFound, "parent not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, st.indexedList(keyOf(r)))
+	})
+
+	mux.HandleFunc("GET "+base+"/{index}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		st, status, msg := s.readState(r)
+		if status != 0 {
+			writeError(w, status, msg)
+			return
+		}
+		items := st.indexedList(keyOf(r))
+		i, err := strconv.Atoi(r.PathValue("index"))
+		if err != nil || i < 0 || i >= len(items) {
+			writeError(w, http.StatusNotFound, "index "+r.PathValue("index")+" not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, items[i])
+	})
+
+	mux.HandleFunc("POST "+base+"/{index}", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		s.mutate(w, r, http.StatusCreated, func(st *state) (interface{}, int, string) {
+			if !st.parentExists(parentOfKey(keyOf(r))) {
+				return nil, http.StatusNotFound, "parent not found"
+			}
+			key := keyOf(r)
+			items := st.lists[key]
+			i, err := strconv.Atoi(r.PathValue("index"))
+			if err != nil || i < 0 || i > len(items) {
+				return nil, http.StatusUnprocessableEntity, "index out of range"
+			}
+			delete(obj, "index")
+			items = append(items, nil)
+			copy(items[i+1:], items[i:])
+			items[i] = obj
+			st.lists[key] = items
+			return obj, 0, ""
+		})
+	})
+
+	mux.HandleFunc("PUT "+base+"/{index}", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		s.mutate(w, r, http.StatusOK, func(st *state) (interface{}, int, string) {
+			key := keyOf(r)
+			i, err := strconv.Atoi(r.PathValue("index"))
+			if err != nil || i < 0 || i >= len(st.lists[key]) {
+				return nil, http.StatusNotFound, "index " + r.PathValue("index") + " not found"
+			}
+			delete(obj, "index")
+			st.lists[key][i] = obj
+			return obj, 0, ""
+		})
+	})
+
+	mux.HandleFunc("DELETE "+base+"/{index}", func(w http.ResponseWriter, r *http.Request) {
+		s.mutate(w, r, http.StatusNoContent, func(st *state) (interface{}, int, string) {
+			key := keyOf(r)
+			items := st.lists[key]
+			i, err := strconv.Atoi(r.PathValue("index"))
+			if err != nil || i < 0 || i >= len(items) {
+				return nil, http.StatusNotFound, "index " + r.PathValue("index") + " not found"
+			}
+			st.lists[key] = append(items[:i], items[i+1:]...)
+			return nil, 0, ""
+		})
+	})
+}
+
+func listKeyFromRequest(r *http.Request) string {
+	return listKey(r.PathValue("ptype"), r.PathValue("parent"), r.PathValue("list"))
+}
+
+// parentOfKey splits a list key back into its parent type and name.
+func parentOfKey(key string) (string, string) {
+	ptype, rest, _ := strings.Cut(key, "/")
+	parent, _, _ := strings.Cut(rest, "/")
+	return ptype, parent
+}
@@ -0,0 +1,188 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Runtime maps are not versioned configuration: entries change immediately
+// and never bump the configuration version. Each map is an ordered key ->
+// value store.
+
+type runtimeMap struct {
+	keys   []string
+	values map[string]string
+}
+
+// AddMap registers an (empty) runtime map, e.g. AddMap("routes.map").
+func (s *Server) AddMap(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.maps[name]; !ok {
+		s.maps[name] = &runtimeMap{values: make(map[string]string)}
+	}
+}
+
+// AddMapEntry seeds an entry in a runtime map, registering the map if needed.
+func (s *Server) AddMapEntry(name, key, value string) {
+	s.AddMap(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maps[name].set(key, value)
+}
+
+// MapEntries returns a copy of a runtime map's entries.
+func (s *Server) MapEntries(name string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]string)
+	if m, ok := s.maps[name]; ok {
+		for k, v := range m.values {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (m *runtimeMap) set(key, value string) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *runtimeMap) remove(key string) bool {
+	if _, ok := m.values[key]; !ok {
+		return false
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (m *runtimeMap) entries() []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(m.keys))
+	for _, k := range m.keys {
+		out = append(out, map[string]interface{}{"id": k, "key": k, "value": m.values[k]})
+	}
+	return out
+}
+
+// runtimeMaps registers the /runtime/maps endpoints.
+func (s *Server) runtimeMaps(mux *http.ServeMux) {
+	base := apiPrefix + "/runtime/maps"
+
+	mux.HandleFunc("GET "+base, func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		names := make([]string, 0, len(s.maps))
+		for name := range s.maps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		out := make([]map[string]interface{}, 0, len(names))
+		for _, name := range names {
+			out = append(out, map[string]interface{}{"id": name, "file": name})
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+
+	// lookup resolves the map named in the request; callers hold s.mu.
+	lookup := func(w http.ResponseWriter, r *http.Request) (*runtimeMap, bool) {
+		m, ok := s.maps[r.PathValue("parent")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "map "+r.PathValue("parent")+" not found")
+		}
+		return m, ok
+	}
+
+	mux.HandleFunc("GET "+base+"/{parent}/entries", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if m, ok := lookup(w, r); ok {
+			writeJSON(w, http.StatusOK, m.entries())
+		}
+	})
+
+	mux.HandleFunc("POST "+base+"/{parent}/entries", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		m, ok := lookup(w, r)
+		if !ok {
+			return
+		}
+		key, _ := obj["key"].(string)
+		value, _ := obj["value"].(string)
+		if key == "" {
+			writeError(w, http.StatusUnprocessableEntity, "key is required")
+			return
+		}
+		if _, exists := m.values[key]; exists {
+			writeError(w, http.StatusConflict, "entry "+key+" already exists")
+			return
+		}
+		m.set(key, value)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"id": key, "key": key, "value": value})
+	})
+
+	mux.HandleFunc("PUT "+base+"/{parent}/entries/{id}", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		m, ok := lookup(w, r)
+		if !ok {
+			return
+		}
+		key := r.PathValue("id")
+		if _, exists := m.values[key]; !exists {
+			writeError(w, http.StatusNotFound, "entry "+key+" not found")
+			return
+		}
+		value, _ := obj["value"].(string)
+		m.set(key, value)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": key, "key": key, "value": value})
+	})
+
+	mux.HandleFunc("DELETE "+base+"/{parent}/entries/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		m, ok := lookup(w, r)
+		if !ok {
+			return
+		}
+		if !m.remove(r.PathValue("id")) {
+			writeError(w, http.StatusNotFound, "entry "+r.PathValue("id")+" not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
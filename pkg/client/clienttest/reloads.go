@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	reloadInProgress = "in_progress"
+	reloadSucceeded  = "succeeded"
+	reloadFailed     = "failed"
+)
+
+// reload is one entry of /reloads. It reports in_progress for its first
+// read and its final status afterwards, so callers have to poll.
+type reload struct {
+	ID              string `json:"id"`
+	Status          string `json:"status"`
+	Response        string `json:"response,omitempty"`
+	ReloadTimestamp int    `json:"reload_timestamp"` //nolint:tagliatelle // Data Plane API field name
+
+	final   string
+	pending bool
+}
+
+// FailNextReload makes the next reload fail with output as its response.
+func (s *Server) FailNextReload(output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reloadFailure = output
+}
+
+// ReloadCount returns how many reloads were started.
+func (s *Server) ReloadCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.reloads)
+}
+
+// startReload records a new reload and returns its id. Callers must hold
+// s.mu.
+func (s *Server) startReload() string {
+	r := &reload{
+		ID:              fmt.Sprintf("2025-01-01-%d", len(s.reloads)+1),
+		Status:          reloadInProgress,
+		ReloadTimestamp: 1735689600 + len(s.reloads),
+		final:           reloadSucceeded,
+		pending:         true,
+	}
+	if s.reloadFailure != "" {
+		r.final, r.Response = reloadFailed, s.reloadFailure
+		s.reloadFailure = ""
+	}
+	s.reloads = append(s.reloads, r)
+	return r.ID
+}
+
+// read returns the reload as the API reports it now, advancing it from
+// in_progress to its final status.
+func (r *reload) read() reload {
+	out := *r
+	if r.pending {
+		r.pending = false
+		out.Response = ""
+		return out
+	}
+	r.Status = r.final
+	out.Status = r.final
+	return out
+}
+
+// reloadEndpoints registers the /reloads endpoints.
+func (s *Server) reloadEndpoints(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+apiPrefix+"/reloads", func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		list := make([]reload, 0, len(s.reloads))
+		for _, r := range s.reloads {
+			list = append(list, *r)
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+
+	mux.HandleFunc("GET "+apiPrefix+"/reloads/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, rl := range s.reloads {
+			if rl.ID == r.PathValue("id") {
+				writeJSON(w, http.StatusOK, rl.read())
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "reload "+r.PathValue("id")+" not found")
+	})
+}
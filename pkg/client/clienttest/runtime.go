@@ -0,0 +1,214 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// runtimeServerState is the runtime view of a configured server.
+type runtimeServerState struct {
+	OperationalState string
+	AdminState       string
+	// Weight, when set, overrides the configured weight.
+	Weight *int
+}
+
+// runtimeState returns the runtime state of a server, with the defaults
+// for servers nobody has touched. Callers must hold s.mu.
+func (s *Server) runtimeState(backend, server string) runtimeServerState {
+	rs, ok := s.runtime[backend+"/"+server]
+	if !ok {
+		rs = runtimeServerState{OperationalState: "up", AdminState: "ready"}
+	}
+	return rs
+}
+
+// SetServerState overrides the runtime state reported for a server.
+// Servers default to operational_state "up" and admin_state "ready".
+func (s *Server) SetServerState(backend, server, operational, admin string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.runtimeState(backend, server)
+	rs.OperationalState, rs.AdminState = operational, admin
+	s.runtime[backend+"/"+server] = rs
+}
+
+// SetServerWeight overrides the effective weight reported for a server in
+// native stats. By default the configured weight (or 1) is reported.
+func (s *Server) SetServerWeight(backend, server string, weight int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.runtimeState(backend, server)
+	rs.Weight = &weight
+	s.runtime[backend+"/"+server] = rs
+}
+
+// ServerState returns the runtime operational and admin state of a server.
+func (s *Server) ServerState(backend, server string) (operational, admin string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.runtimeState(backend, server)
+	return rs.OperationalState, rs.AdminState
+}
+
+// runtimeServers reports the live (not staged) servers of a backend with
+// their runtime state. Callers must hold s.mu.
+func (s *Server) runtimeServers(backend string) []map[string]interface{} {
+	servers := s.state.children(s.state.servers, backend).list()
+	out := make([]map[string]interface{}, 0, len(servers))
+	for _, srv := range servers {
+		name, _ := srv["name"].(string)
+		rs := s.runtimeState(backend, name)
+		out = append(out, map[string]interface{}{
+			"name":              name,
+			"address":           srv["address"],
+			"port":              srv["port"],
+			"operational_state": rs.OperationalState,
+			"admin_state":       rs.AdminState,
+		})
+	}
+	return out
+}
+
+func (s *Server) handleRuntimeServers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backend := r.PathValue("parent")
+	if _, ok := s.state.backends.get(backend); !ok {
+		writeError(w, http.StatusNotFound, "backend "+backend+" not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.runtimeServers(backend))
+}
+
+// runtimeServer returns the runtime view of one live server. Callers must
+// hold s.mu.
+func (s *Server) runtimeServer(backend, name string) (map[string]interface{}, bool) {
+	for _, srv := range s.runtimeServers(backend) {
+		if srv["name"] == name {
+			return srv, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) handleRuntimeServer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backend, name := r.PathValue("parent"), r.PathValue("name")
+	srv, ok := s.runtimeServer(backend, name)
+	if !ok {
+		writeError(w, http.StatusNotFound, "server "+backend+"/"+name+" not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, srv)
+}
+
+// handleReplaceRuntimeServer changes admin_state (ready, drain or maint),
+// operational_state (up, down or stopping) and weight at runtime. Like the real
+// endpoint it does not touch the configuration version.
+func (s *Server) handleReplaceRuntimeServer(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeObject(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backend, name := r.PathValue("parent"), r.PathValue("name")
+	if _, ok := s.runtimeServer(backend, name); !ok {
+		writeError(w, http.StatusNotFound, "server "+backend+"/"+name+" not found")
+		return
+	}
+
+	rs := s.runtimeState(backend, name)
+	if admin, ok := body["admin_state"].(string); ok && admin != "" {
+		switch admin {
+		case "ready", "drain", "maint":
+			rs.AdminState = admin
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "invalid admin_state "+admin)
+			return
+		}
+	}
+	if op, ok := body["operational_state"].(string); ok && op != "" {
+		rs.OperationalState = op
+	}
+	if weight, ok := body["weight"].(float64); ok {
+		w := int(weight)
+		rs.Weight = &w
+	}
+	s.runtime[backend+"/"+name] = rs
+
+	srv, _ := s.runtimeServer(backend, name)
+	writeJSON(w, http.StatusOK, srv)
+}
+
+// handleNativeStats serves server entries of /stats/native, filtered by the
+// type, parent and name query parameters like the real endpoint.
+func (s *Server) handleNativeStats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	if t := q.Get("type"); t != "" && t != "server" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"stats": []interface{}{}})
+		return
+	}
+
+	var entries []interface{}
+	for _, be := range s.state.backends.list() {
+		backend, _ := be["name"].(string)
+		if p := q.Get("parent"); p != "" && p != backend {
+			continue
+		}
+		for _, srv := range s.state.children(s.state.servers, backend).list() {
+			name, _ := srv["name"].(string)
+			if n := q.Get("name"); n != "" && n != name {
+				continue
+			}
+			rs := s.runtimeState(backend, name)
+			weight := 1
+			switch w := srv["weight"].(type) {
+			case float64:
+				weight = int(w)
+			case int:
+				weight = w
+			}
+			if rs.Weight != nil {
+				weight = *rs.Weight
+			}
+			status := strings.ToUpper(rs.OperationalState)
+			if rs.AdminState != "ready" {
+				status = strings.ToUpper(rs.AdminState)
+			}
+			entries = append(entries, map[string]interface{}{
+				"type":         "server",
+				"name":         name,
+				"backend_name": backend,
+				"stats":        map[string]interface{}{"status": status, "weight": weight},
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"runtimeAPI": "fake", "stats": entries})
+}
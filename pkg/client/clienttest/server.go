@@ -0,0 +1,795 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data
+// Plane API v3 for tests of code built on pkg/client. It covers the
+// endpoints haproxyctl relies on for its core flows (configuration version,
+// backends, servers, frontends, binds, userlists with their users and
+// groups, resolvers, rings, log forwards, caches, http-errors sections,
+// indexed lists such as acls and http_request_rules, the raw configuration,
+// SSL and general storage, runtime server state, maps and stick tables,
+// server native stats and transactions) and enforces the same version semantics as the real API, so
+// create/apply/edit flows can be exercised end to end without a running
+// HAProxy.
+package clienttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	// Username is the basic-auth user accepted by the fake API.
+	Username = "admin"
+	// Password is the basic-auth password accepted by the fake API.
+	Password = "secret"
+	// Token is the bearer token accepted by the fake API instead of basic auth.
+	Token = "test-token"
+	// APIVersion and HAProxyVersion are the versions /info and the runtime
+	// info endpoint report.
+	APIVersion     = "v3.0.5 7e3ba5b"
+	HAProxyVersion = "3.0.5-8e879a5"
+
+	apiPrefix = "/v3/services/haproxy"
+)
+
+// Request is a single request observed by the fake API.
+type Request struct {
+	Method string
+	Path   string
+	Query  map[string]string
+}
+
+// Server is a running fake Data Plane API.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	state        *state
+	transactions map[string]*transaction
+	nextTxID     int
+	requests     []Request
+	runtime      map[string]runtimeServerState
+	maps         map[string]*runtimeMap
+	stickTables  map[string]*stickTable
+	certFiles    map[string][]byte
+	generalFiles map[string][]byte
+	// crtLists holds the contents of each ssl_crt_lists storage file.
+	crtLists map[string][]byte
+	// spoeFiles and spoeTx are the SPOE files and their transactions.
+	spoeFiles map[string]*spoeFile
+	spoeTx    map[string]*spoeTransaction
+	// cluster holds the cluster settings after a join, nil in single mode.
+	cluster map[string]interface{}
+	// reloads are the reloads started by commits; reloadFailure, when
+	// set, is the output the next one fails with.
+	reloads       []*reload
+	reloadFailure string
+}
+
+// New starts a fake Data Plane API that is closed when the test finishes.
+// Point a client at it with client.New(s.URL, Username, Password).
+func New(t testing.TB) *Server {
+	t.Helper()
+
+	s := NewUnstarted()
+	s.Start()
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// NewUnstarted returns a fake Data Plane API that has not been started.
+// Callers start and close it themselves with Start/Close.
+func NewUnstarted() *Server {
+	s := &Server{
+		state:        newState(),
+		transactions: make(map[string]*transaction),
+		runtime:      make(map[string]runtimeServerState),
+		maps:         make(map[string]*runtimeMap),
+		stickTables:  make(map[string]*stickTable),
+		certFiles:    make(map[string][]byte),
+		generalFiles: make(map[string][]byte),
+		crtLists:     make(map[string][]byte),
+		spoeFiles:    make(map[string]*spoeFile),
+		spoeTx:       make(map[string]*spoeTransaction),
+	}
+	s.Server = httptest.NewUnstartedServer(s.routes())
+	return s
+}
+
+// Version returns the current configuration version.
+func (s *Server) Version() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.version
+}
+
+// Requests returns a copy of every request received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// CountRequests returns how many requests matched method and path.
+func (s *Server) CountRequests(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, r := range s.requests {
+		if r.Method == method && r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+// AddBackend seeds a backend without bumping the configuration version.
+func (s *Server) AddBackend(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.backends.put(obj)
+}
+
+// AddServer seeds a server in backend without bumping the configuration version.
+func (s *Server) AddServer(backend string, obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.children(s.state.servers, backend).put(obj)
+}
+
+// AddFrontend seeds a frontend without bumping the configuration version.
+func (s *Server) AddFrontend(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.frontends.put(obj)
+}
+
+// AddBind seeds a bind in frontend without bumping the configuration version.
+func (s *Server) AddBind(frontend string, obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.children(s.state.binds, frontend).put(obj)
+}
+
+// AddUserlist seeds a userlist without bumping the configuration version.
+func (s *Server) AddUserlist(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.userlists.put(obj)
+}
+
+// AddDefaults seeds a defaults section without bumping the configuration
+// version.
+func (s *Server) AddDefaults(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.defaults.put(obj)
+}
+
+// AddResolver seeds a resolvers section without bumping the configuration
+// version.
+func (s *Server) AddResolver(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.resolvers.put(obj)
+}
+
+// AddNameserver seeds a nameserver in a resolvers section without bumping
+// the configuration version.
+func (s *Server) AddNameserver(resolver string, obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.children(s.state.nameservers, resolver).put(obj)
+}
+
+// Resolver returns a stored resolvers section by name.
+func (s *Server) Resolver(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.resolvers.get(name)
+}
+
+// Nameservers returns the nameservers stored for a resolvers section,
+// sorted by name.
+func (s *Server) Nameservers(resolver string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.children(s.state.nameservers, resolver).list()
+}
+
+// AddRing seeds a ring section without bumping the configuration version.
+func (s *Server) AddRing(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.rings.put(obj)
+}
+
+// Ring returns a stored ring section by name.
+func (s *Server) Ring(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.rings.get(name)
+}
+
+// RingServers returns the servers stored for a ring, sorted by name.
+func (s *Server) RingServers(ring string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.children(s.state.ringServers, ring).list()
+}
+
+// AddCache seeds a cache section without bumping the configuration version.
+func (s *Server) AddCache(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.caches.put(obj)
+}
+
+// Cache returns a stored cache section by name.
+func (s *Server) Cache(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.caches.get(name)
+}
+
+// AddHTTPErrors seeds an http-errors section without bumping the
+// configuration version.
+func (s *Server) AddHTTPErrors(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.httpErrors.put(obj)
+}
+
+// HTTPErrors returns a stored http-errors section by name.
+func (s *Server) HTTPErrors(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.httpErrors.get(name)
+}
+
+// AddLogForward seeds a log-forward section without bumping the
+// configuration version.
+func (s *Server) AddLogForward(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.logForwards.put(obj)
+}
+
+// LogForward returns a stored log-forward section by name.
+func (s *Server) LogForward(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.logForwards.get(name)
+}
+
+// LogForwardBinds returns the TCP binds (or, with dgram, the UDP binds)
+// stored for a log-forward section, sorted by name.
+func (s *Server) LogForwardBinds(logForward string, dgram bool) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if dgram {
+		return s.state.children(s.state.logForwardDgramBinds, logForward).list()
+	}
+	return s.state.children(s.state.logForwardBinds, logForward).list()
+}
+
+// Defaults returns a stored defaults section by name.
+func (s *Server) Defaults(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.defaults.get(name)
+}
+
+// Userlist returns a stored userlist by name.
+func (s *Server) Userlist(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.userlists.get(name)
+}
+
+// AddCertificate seeds an ssl_certificates storage entry.
+func (s *Server) AddCertificate(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.certificates = append(s.state.certificates, copyObject(obj))
+}
+
+// Backend returns a stored backend by name.
+func (s *Server) Backend(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.backends.get(name)
+}
+
+// Servers returns the servers stored for backend, sorted by name.
+func (s *Server) Servers(backend string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.children(s.state.servers, backend).list()
+}
+
+// Frontend returns a stored frontend by name.
+func (s *Server) Frontend(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.frontends.get(name)
+}
+
+// Binds returns the binds stored for frontend, sorted by name.
+func (s *Server) Binds(frontend string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.children(s.state.binds, frontend).list()
+}
+
+// routes registers the emulated endpoints.
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET "+apiPrefix+"/configuration/version", s.handleVersion)
+	mux.HandleFunc("GET /v3/info", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"api":    map[string]interface{}{"version": APIVersion, "build_date": "2024-10-01T00:00:00.000Z"},
+			"system": map[string]interface{}{"hostname": "lb1", "uptime": 86400},
+		})
+	})
+	s.clusterEndpoints(mux)
+	s.reloadEndpoints(mux)
+	mux.HandleFunc("GET /v3/health", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"haproxy": "up"})
+	})
+	mux.HandleFunc("GET "+apiPrefix+"/runtime/info", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"info": map[string]interface{}{"version": HAProxyVersion, "uptime": 3600, "pid": 1},
+		})
+	})
+
+	s.collection(mux, "/configuration/backends", func(st *state, _ *http.Request) *collection {
+		return st.backends
+	}, func(st *state) []map[string]*collection {
+		return []map[string]*collection{st.servers}
+	})
+	s.collection(mux, "/configuration/backends/{parent}/servers", func(st *state, r *http.Request) *collection {
+		if _, ok := st.backends.get(r.PathValue("parent")); !ok {
+			return nil
+		}
+		return st.children(st.servers, r.PathValue("parent"))
+	}, nil)
+	s.collection(mux, "/configuration/frontends", func(st *state, _ *http.Request) *collection {
+		return st.frontends
+	}, func(st *state) []map[string]*collection {
+		return []map[string]*collection{st.binds}
+	})
+	s.collection(mux, "/configuration/frontends/{parent}/binds", func(st *state, r *http.Request) *collection {
+		if _, ok := st.frontends.get(r.PathValue("parent")); !ok {
+			return nil
+		}
+		return st.children(st.binds, r.PathValue("parent"))
+	}, nil)
+	s.collection(mux, "/configuration/userlists", func(st *state, _ *http.Request) *collection {
+		return st.userlists
+	}, nil)
+	s.userlistMembers(mux, "users", "username")
+	s.userlistMembers(mux, "groups", "name")
+	s.collection(mux, "/configuration/defaults", func(st *state, _ *http.Request) *collection {
+		return st.defaults
+	}, nil)
+	s.collection(mux, "/configuration/resolvers", func(st *state, _ *http.Request) *collection {
+		return st.resolvers
+	}, func(st *state) []map[string]*collection {
+		return []map[string]*collection{st.nameservers}
+	})
+	s.collection(mux, "/configuration/resolvers/{parent}/nameservers", func(st *state, r *http.Request) *collection {
+		if _, ok := st.resolvers.get(r.PathValue("parent")); !ok {
+			return nil
+		}
+		return st.children(st.nameservers, r.PathValue("parent"))
+	}, nil)
+	s.collection(mux, "/configuration/rings", func(st *state, _ *http.Request) *collection {
+		return st.rings
+	}, func(st *state) []map[string]*collection {
+		return []map[string]*collection{st.ringServers}
+	})
+	s.collection(mux, "/configuration/rings/{parent}/servers", func(st *state, r *http.Request) *collection {
+		if _, ok := st.rings.get(r.PathValue("parent")); !ok {
+			return nil
+		}
+		return st.children(st.ringServers, r.PathValue("parent"))
+	}, nil)
+	s.collection(mux, "/configuration/caches", func(st *state, _ *http.Request) *collection {
+		return st.caches
+	}, nil)
+	s.collection(mux, "/configuration/http_errors_sections", func(st *state, _ *http.Request) *collection {
+		return st.httpErrors
+	}, nil)
+	s.collection(mux, "/configuration/log_forwards", func(st *state, _ *http.Request) *collection {
+		return st.logForwards
+	}, func(st *state) []map[string]*collection {
+		return []map[string]*collection{st.logForwardBinds, st.logForwardDgramBinds}
+	})
+	s.collection(mux, "/configuration/log_forwards/{parent}/binds", func(st *state, r *http.Request) *collection {
+		if _, ok := st.logForwards.get(r.PathValue("parent")); !ok {
+			return nil
+		}
+		return st.children(st.logForwardBinds, r.PathValue("parent"))
+	}, nil)
+	s.collection(mux, "/configuration/log_forwards/{parent}/dgram_binds", func(st *state, r *http.Request) *collection {
+		if _, ok := st.logForwards.get(r.PathValue("parent")); !ok {
+			return nil
+		}
+		return st.children(st.logForwardDgramBinds, r.PathValue("parent"))
+	}, nil)
+	mux.HandleFunc("GET "+apiPrefix+"/configuration/global", s.handleGetGlobal)
+	mux.HandleFunc("PUT "+apiPrefix+"/configuration/global", s.handleReplaceGlobal)
+	s.indexedLists(mux)
+
+	s.rawConfig(mux)
+	s.sslStorage(mux)
+	s.generalStorage(mux)
+	s.crtListStorage(mux)
+	s.spoe(mux)
+
+	mux.HandleFunc("GET "+apiPrefix+"/runtime/backends/{parent}/servers", s.handleRuntimeServers)
+	mux.HandleFunc("GET "+apiPrefix+"/runtime/backends/{parent}/servers/{name}", s.handleRuntimeServer)
+	mux.HandleFunc("PUT "+apiPrefix+"/runtime/backends/{parent}/servers/{name}", s.handleReplaceRuntimeServer)
+	mux.HandleFunc("GET "+apiPrefix+"/stats/native", s.handleNativeStats)
+	s.runtimeMaps(mux)
+	s.runtimeStickTables(mux)
+
+	mux.HandleFunc("GET "+apiPrefix+"/transactions", s.handleListTransactions)
+	mux.HandleFunc("POST "+apiPrefix+"/transactions", s.handleStartTransaction)
+	mux.HandleFunc("GET "+apiPrefix+"/transactions/{id}", s.handleGetTransaction)
+	mux.HandleFunc("PUT "+apiPrefix+"/transactions/{id}", s.handleCommitTransaction)
+	mux.HandleFunc("DELETE "+apiPrefix+"/transactions/{id}", s.handleDeleteTransaction)
+
+	return s.withAuthAndLog(mux)
+}
+
+// withAuthAndLog enforces basic auth and records every request.
+func (s *Server) withAuthAndLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := make(map[string]string)
+		for k, v := range r.URL.Query() {
+			if len(v) > 0 {
+				query[k] = v[0]
+			}
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: query})
+		s.mu.Unlock()
+
+		user, pass, ok := r.BasicAuth()
+		bearer := r.Header.Get("Authorization") == "Bearer "+Token
+		if !bearer && (!ok || user != Username || pass != Password) {
+			writeError(w, http.StatusUnauthorized, "invalid credentials")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleListCertificates(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	certs := make([]map[string]interface{}, 0, len(s.state.certificates))
+	for _, c := range s.state.certificates {
+		certs = append(certs, copyObject(c))
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, certs)
+}
+
+// SetGlobal sets the global section without bumping the configuration
+// version.
+func (s *Server) SetGlobal(obj map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.global = copyObject(obj)
+}
+
+// Global returns a copy of the global section.
+func (s *Server) Global() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyObject(s.state.global)
+}
+
+func (s *Server) handleGetGlobal(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, status, msg := s.readState(r)
+	if status != 0 {
+		writeError(w, status, msg)
+		return
+	}
+	if st.global == nil {
+		writeError(w, http.StatusNotFound, "global section not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, st.global)
+}
+
+func (s *Server) handleReplaceGlobal(w http.ResponseWriter, r *http.Request) {
+	obj, ok := decodeObject(w, r)
+	if !ok {
+		return
+	}
+	s.mutate(w, r, http.StatusOK, func(st *state) (interface{}, int, string) {
+		st.global = obj
+		return obj, 0, ""
+	})
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	version := s.state.version
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, version)
+}
+
+// fullSectionChildren are the child collections a full_section=true list
+// nests in each object, keyed by the name of the field they go in.
+var fullSectionChildren = map[string]struct {
+	key  string
+	pick func(*state) map[string]*collection
+}{
+	"/configuration/backends":  {"servers", func(st *state) map[string]*collection { return st.servers }},
+	"/configuration/frontends": {"binds", func(st *state) map[string]*collection { return st.binds }},
+}
+
+// nestChildren adds the children of every object in list under key, keyed
+// by child name as the Data Plane API does. Objects without children get
+// no key.
+func nestChildren(list []map[string]interface{}, key string, children map[string]*collection) {
+	for _, obj := range list {
+		name, _ := obj["name"].(string)
+		c, ok := children[name]
+		if !ok || len(c.items) == 0 {
+			continue
+		}
+		nested := make(map[string]interface{}, len(c.items))
+		for _, child := range c.list() {
+			childName, _ := child["name"].(string)
+			nested[childName] = child
+		}
+		obj[key] = nested
+	}
+}
+
+// collection registers list/create and get/replace/delete handlers for a
+// named collection rooted at path. resolve returns nil when the parent
+// object does not exist; children, when set, selects the child collections
+// that are dropped together with a deleted object.
+func (s *Server) collection(
+	mux *http.ServeMux,
+	path string,
+	resolve func(*state, *http.Request) *collection,
+	children func(*state) []map[string]*collection,
+) {
+	base := apiPrefix + path
+
+	mux.HandleFunc("GET "+base, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		st, status, msg := s.readState(r)
+		if status != 0 {
+			writeError(w, status, msg)
+			return
+		}
+		c := resolve(st, r)
+		if c == nil {
+			writeError(w, http.StatusNotFound, "parent not found")
+			return
+		}
+		list := c.list()
+		if child, ok := fullSectionChildren[path]; ok && r.URL.Query().Get("full_section") == "true" {
+			nestChildren(list, child.key, child.pick(st))
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+
+	mux.HandleFunc("POST "+base, func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+
+		s.mutate(w, r, http.StatusCreated, func(st *state) (interface{}, int, string) {
+			c := resolve(st, r)
+			if c == nil {
+				return nil, http.StatusNotFound, "parent not found"
+			}
+			name, _ := obj["name"].(string)
+			if name == "" {
+				return nil, http.StatusUnprocessableEntity, "name is required"
+			}
+			if _, exists := c.get(name); exists {
+				return nil, http.StatusConflict, fmt.Sprintf("object %s already exists", name)
+			}
+			c.put(obj)
+			return obj, 0, ""
+		})
+	})
+
+	mux.HandleFunc("GET "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		st, status, msg := s.readState(r)
+		if status != 0 {
+			writeError(w, status, msg)
+			return
+		}
+		c := resolve(st, r)
+		if c == nil {
+			writeError(w, http.StatusNotFound, "parent not found")
+			return
+		}
+		obj, ok := c.get(r.PathValue("name"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "object "+r.PathValue("name")+" not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, obj)
+	})
+
+	mux.HandleFunc("PUT "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+
+		s.mutate(w, r, http.StatusOK, func(st *state) (interface{}, int, string) {
+			c := resolve(st, r)
+			if c == nil {
+				return nil, http.StatusNotFound, "parent not found"
+			}
+			name := r.PathValue("name")
+			if _, exists := c.get(name); !exists {
+				return nil, http.StatusNotFound, "object " + name + " not found"
+			}
+			obj["name"] = name
+			c.put(obj)
+			return obj, 0, ""
+		})
+	})
+
+	mux.HandleFunc("DELETE "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mutate(w, r, http.StatusNoContent, func(st *state) (interface{}, int, string) {
+			c := resolve(st, r)
+			if c == nil {
+				return nil, http.StatusNotFound, "parent not found"
+			}
+			name := r.PathValue("name")
+			if !c.remove(name) {
+				return nil, http.StatusNotFound, "object " + name + " not found"
+			}
+			// Deleting a parent section also drops its children.
+			if children != nil {
+				for _, m := range children(st) {
+					delete(m, name)
+				}
+			}
+			prefix := strings.TrimPrefix(path, "/configuration/") + "/" + name + "/"
+			for key := range st.lists {
+				if strings.HasPrefix(key, prefix) {
+					delete(st.lists, key)
+				}
+			}
+			return nil, 0, ""
+		})
+	})
+}
+
+// readState returns the state a read request should observe: the staged
+// state of its transaction, or the live configuration. Callers must hold
+// s.mu.
+func (s *Server) readState(r *http.Request) (*state, int, string) {
+	txID := r.URL.Query().Get("transaction_id")
+	if txID == "" {
+		return s.state, 0, ""
+	}
+	tx, ok := s.transactions[txID]
+	if !ok || tx.Status != txInProgress {
+		return nil, http.StatusNotFound, "transaction " + txID + " not found"
+	}
+	return tx.state, 0, ""
+}
+
+// mutate applies fn either to the staged state of the request's
+// transaction or, when no transaction is given, to the live state after
+// checking the version query parameter. Live changes bump the version.
+func (s *Server) mutate(w http.ResponseWriter, r *http.Request, okStatus int, fn func(*state) (interface{}, int, string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+
+	if txID := query.Get("transaction_id"); txID != "" {
+		st, status, msg := s.readState(r)
+		if status != 0 {
+			writeError(w, status, msg)
+			return
+		}
+		resp, status, msg := fn(st)
+		if status != 0 {
+			writeError(w, status, msg)
+			return
+		}
+		writeResponse(w, okStatus, resp)
+		return
+	}
+
+	version, err := strconv.Atoi(query.Get("version"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "version or transaction_id must be specified")
+		return
+	}
+	if version != s.state.version {
+		writeError(w, http.StatusConflict, fmt.Sprintf("version mismatch: got %d, current %d", version, s.state.version))
+		return
+	}
+
+	resp, status, msg := fn(s.state)
+	if status != 0 {
+		writeError(w, status, msg)
+		return
+	}
+	s.state.version++
+	w.Header().Set("Reload-ID", s.startReload())
+	writeResponse(w, okStatus, resp)
+}
+
+func decodeObject(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	var obj map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return nil, false
+	}
+	return obj, true
+}
+
+func writeResponse(w http.ResponseWriter, status int, body interface{}) {
+	if status == http.StatusNoContent || body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]interface{}{"code": status, "message": msg})
+}
@@ -0,0 +1,84 @@
+package clienttest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"haproxyctl/pkg/client"
+	"haproxyctl/pkg/client/clienttest"
+)
+
+func TestServerVersionedMutations(t *testing.T) {
+	srv := clienttest.New(t)
+	c := client.New(srv.URL, clienttest.Username, clienttest.Password)
+	ctx := context.Background()
+
+	version, err := c.ConfigurationVersion(ctx)
+	if err != nil {
+		t.Fatalf("ConfigurationVersion failed: %v", err)
+	}
+	if version != 1 {
+		t.Fatalf("initial version = %d, want 1", version)
+	}
+
+	_, err = c.Do(ctx, http.MethodPost, "/services/haproxy/configuration/backends",
+		map[string]string{"version": strconv.Itoa(version)},
+		map[string]interface{}{"name": "web", "mode": "http"},
+	)
+	if err != nil {
+		t.Fatalf("create backend failed: %v", err)
+	}
+	if srv.Version() != 2 {
+		t.Fatalf("version after create = %d, want 2", srv.Version())
+	}
+
+	// Reusing the stale version must be rejected like the real API does.
+	_, err = c.Do(ctx, http.MethodPost, "/services/haproxy/configuration/backends/web/servers",
+		map[string]string{"version": strconv.Itoa(version)},
+		map[string]interface{}{"name": "s1", "address": "10.0.0.1", "port": 80},
+	)
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for stale version, got %v", err)
+	}
+
+	if _, err := c.GetBackend(ctx, "missing"); !client.IsNotFound(err) {
+		t.Fatalf("expected 404 for missing backend, got %v", err)
+	}
+}
+
+func TestServerTransactionCommit(t *testing.T) {
+	srv := clienttest.New(t)
+	c := client.New(srv.URL, clienttest.Username, clienttest.Password)
+	ctx := context.Background()
+	srv.AddBackend(map[string]interface{}{"name": "web", "mode": "http"})
+
+	tx, err := c.StartTransaction(ctx)
+	if err != nil {
+		t.Fatalf("start transaction failed: %v", err)
+	}
+
+	for _, name := range []string{"s1", "s2"} {
+		if err := c.CreateServer(ctx, tx, "web", client.Server{Name: name, Address: "10.0.0.1", Port: 80}); err != nil {
+			t.Fatalf("staged server create failed: %v", err)
+		}
+	}
+
+	if got := len(srv.Servers("web")); got != 0 {
+		t.Fatalf("staged servers visible before commit: %d", got)
+	}
+
+	if err := c.CommitTransaction(ctx, tx); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+
+	if got := len(srv.Servers("web")); got != 2 {
+		t.Fatalf("servers after commit = %d, want 2", got)
+	}
+	if srv.Version() != 2 {
+		t.Fatalf("version after commit = %d, want 2", srv.Version())
+	}
+}
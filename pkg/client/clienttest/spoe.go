@@ -0,0 +1,412 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// SPOE files are versioned and transacted on their own, apart from the
+// main configuration: each file has a version, and its transactions live
+// under /spoe/spoe_files/{parent}/transactions.
+
+// spoeFile is the parsed content of one SPOE configuration file.
+type spoeFile struct {
+	version int
+	scopes  map[string]*spoeScope
+}
+
+// spoeScope holds the objects of one "[scope]" of an SPOE file.
+type spoeScope struct {
+	agents   *collection
+	messages *collection
+	groups   *collection
+}
+
+func newSPOEScope() *spoeScope {
+	return &spoeScope{agents: newCollection(), messages: newCollection(), groups: newCollection()}
+}
+
+func (f *spoeFile) clone() *spoeFile {
+	out := &spoeFile{version: f.version, scopes: make(map[string]*spoeScope, len(f.scopes))}
+	for name, sc := range f.scopes {
+		out.scopes[name] = &spoeScope{agents: sc.agents.clone(), messages: sc.messages.clone(), groups: sc.groups.clone()}
+	}
+	return out
+}
+
+// collection returns the scope collection for an API path segment.
+func (sc *spoeScope) collection(kind string) *collection {
+	switch kind {
+	case "agents":
+		return sc.agents
+	case "messages":
+		return sc.messages
+	case "groups":
+		return sc.groups
+	}
+	return nil
+}
+
+// spoeTransaction is a staged copy of one SPOE file.
+type spoeTransaction struct {
+	ID      string `json:"id"`
+	Version int    `json:"_version"` //nolint:tagliatelle // Data Plane API field name
+	Status  string `json:"status"`
+
+	file   string
+	staged *spoeFile
+}
+
+// AddSPOEFile seeds an empty SPOE file.
+func (s *Server) AddSPOEFile(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spoeFiles[name] = &spoeFile{version: 1, scopes: make(map[string]*spoeScope)}
+}
+
+// SPOEObjects returns the agents, messages or groups of an SPOE scope,
+// sorted by name; scope is given without brackets.
+func (s *Server) SPOEObjects(file, scope, kind string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.spoeFiles[file]
+	if !ok {
+		return nil
+	}
+	sc, ok := f.scopes["["+scope+"]"]
+	if !ok {
+		return nil
+	}
+	return sc.collection(kind).list()
+}
+
+// SPOEScopes returns the scope names of an SPOE file, with brackets.
+func (s *Server) SPOEScopes(file string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.spoeFiles[file]
+	if !ok {
+		return nil
+	}
+	return f.scopeNames()
+}
+
+func (f *spoeFile) scopeNames() []string {
+	names := make([]string, 0, len(f.scopes))
+	for name := range f.scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SPOEVersion returns the version of an SPOE file.
+func (s *Server) SPOEVersion(file string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f, ok := s.spoeFiles[file]; ok {
+		return f.version
+	}
+	return 0
+}
+
+// spoe registers the SPOE endpoints.
+func (s *Server) spoe(mux *http.ServeMux) {
+	base := apiPrefix + "/spoe/spoe_files"
+
+	mux.HandleFunc("GET "+base, func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		names := make([]string, 0, len(s.spoeFiles))
+		for name := range s.spoeFiles {
+			names = append(names, name)
+		}
+		s.mu.Unlock()
+		sort.Strings(names)
+		writeJSON(w, http.StatusOK, names)
+	})
+
+	mux.HandleFunc("POST "+base, func(w http.ResponseWriter, r *http.Request) {
+		name, _, ok := readUpload(w, r)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, exists := s.spoeFiles[name]; exists {
+			writeError(w, http.StatusConflict, "SPOE file "+name+" already exists")
+			return
+		}
+		s.spoeFiles[name] = &spoeFile{version: 1, scopes: make(map[string]*spoeScope)}
+		writeJSON(w, http.StatusCreated, name)
+	})
+
+	mux.HandleFunc("DELETE "+base+"/{parent}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		name := r.PathValue("parent")
+		if _, exists := s.spoeFiles[name]; !exists {
+			writeError(w, http.StatusNotFound, "SPOE file "+name+" not found")
+			return
+		}
+		delete(s.spoeFiles, name)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET "+base+"/{parent}/version", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		f, ok := s.spoeFiles[r.PathValue("parent")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "SPOE file "+r.PathValue("parent")+" not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, f.version)
+	})
+
+	s.spoeTransactions(mux, base+"/{parent}/transactions")
+
+	mux.HandleFunc("GET "+base+"/{parent}/scopes", func(w http.ResponseWriter, r *http.Request) {
+		s.spoeRead(w, r, func(f *spoeFile) (interface{}, int, string) {
+			return f.scopeNames(), 0, ""
+		})
+	})
+	mux.HandleFunc("POST "+base+"/{parent}/scopes", func(w http.ResponseWriter, r *http.Request) {
+		var scope string
+		if err := json.NewDecoder(r.Body).Decode(&scope); err != nil || scope == "" {
+			writeError(w, http.StatusBadRequest, "scope name must be a JSON string")
+			return
+		}
+		s.spoeMutate(w, r, http.StatusCreated, func(f *spoeFile) (interface{}, int, string) {
+			if _, exists := f.scopes[scope]; exists {
+				return nil, http.StatusConflict, "scope " + scope + " already exists"
+			}
+			f.scopes[scope] = newSPOEScope()
+			return scope, 0, ""
+		})
+	})
+	mux.HandleFunc("DELETE "+base+"/{parent}/scopes/{scope}", func(w http.ResponseWriter, r *http.Request) {
+		s.spoeMutate(w, r, http.StatusNoContent, func(f *spoeFile) (interface{}, int, string) {
+			if _, exists := f.scopes[r.PathValue("scope")]; !exists {
+				return nil, http.StatusNotFound, "scope " + r.PathValue("scope") + " not found"
+			}
+			delete(f.scopes, r.PathValue("scope"))
+			return nil, 0, ""
+		})
+	})
+
+	for _, kind := range []string{"agents", "messages", "groups"} {
+		s.spoeObjects(mux, base+"/{parent}/scopes/{scope}/"+kind, kind)
+	}
+}
+
+// spoeObjects registers the CRUD endpoints of one kind of scope object.
+func (s *Server) spoeObjects(mux *http.ServeMux, path, kind string) {
+	inScope := func(r *http.Request, fn func(c *collection) (interface{}, int, string)) func(*spoeFile) (interface{}, int, string) {
+		return func(f *spoeFile) (interface{}, int, string) {
+			sc, ok := f.scopes[r.PathValue("scope")]
+			if !ok {
+				return nil, http.StatusNotFound, "scope " + r.PathValue("scope") + " not found"
+			}
+			return fn(sc.collection(kind))
+		}
+	}
+
+	mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+		s.spoeRead(w, r, inScope(r, func(c *collection) (interface{}, int, string) {
+			return c.list(), 0, ""
+		}))
+	})
+	mux.HandleFunc("GET "+path+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.spoeRead(w, r, inScope(r, func(c *collection) (interface{}, int, string) {
+			obj, ok := c.get(r.PathValue("name"))
+			if !ok {
+				return nil, http.StatusNotFound, kind + " " + r.PathValue("name") + " not found"
+			}
+			return obj, 0, ""
+		}))
+	})
+	mux.HandleFunc("POST "+path, func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		s.spoeMutate(w, r, http.StatusCreated, inScope(r, func(c *collection) (interface{}, int, string) {
+			name, _ := obj["name"].(string)
+			if _, exists := c.get(name); exists {
+				return nil, http.StatusConflict, kind + " " + name + " already exists"
+			}
+			c.put(obj)
+			return obj, 0, ""
+		}))
+	})
+	mux.HandleFunc("PUT "+path+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		s.spoeMutate(w, r, http.StatusOK, inScope(r, func(c *collection) (interface{}, int, string) {
+			if _, exists := c.get(r.PathValue("name")); !exists {
+				return nil, http.StatusNotFound, kind + " " + r.PathValue("name") + " not found"
+			}
+			c.remove(r.PathValue("name"))
+			c.put(obj)
+			return obj, 0, ""
+		}))
+	})
+	mux.HandleFunc("DELETE "+path+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.spoeMutate(w, r, http.StatusNoContent, inScope(r, func(c *collection) (interface{}, int, string) {
+			if !c.remove(r.PathValue("name")) {
+				return nil, http.StatusNotFound, kind + " " + r.PathValue("name") + " not found"
+			}
+			return nil, 0, ""
+		}))
+	})
+}
+
+// spoeTransactions registers the per-file transaction endpoints.
+func (s *Server) spoeTransactions(mux *http.ServeMux, path string) {
+	mux.HandleFunc("POST "+path, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		f, ok := s.spoeFiles[r.PathValue("parent")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "SPOE file "+r.PathValue("parent")+" not found")
+			return
+		}
+		version, err := strconv.Atoi(r.URL.Query().Get("version"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "version must be specified")
+			return
+		}
+		if version != f.version {
+			writeError(w, http.StatusConflict, fmt.Sprintf("version mismatch: got %d, current %d", version, f.version))
+			return
+		}
+		s.nextTxID++
+		tx := &spoeTransaction{
+			ID:      fmt.Sprintf("spoe-tx-%04d", s.nextTxID),
+			Version: version,
+			Status:  txInProgress,
+			file:    r.PathValue("parent"),
+			staged:  f.clone(),
+		}
+		s.spoeTx[tx.ID] = tx
+		writeJSON(w, http.StatusCreated, tx)
+	})
+
+	mux.HandleFunc("PUT "+path+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		tx, ok := s.spoeTx[r.PathValue("id")]
+		if !ok || tx.Status != txInProgress || tx.file != r.PathValue("parent") {
+			writeError(w, http.StatusNotFound, "transaction "+r.PathValue("id")+" not found")
+			return
+		}
+		f, ok := s.spoeFiles[tx.file]
+		if !ok || f.version != tx.Version {
+			writeError(w, http.StatusNotAcceptable, "transaction is outdated")
+			return
+		}
+		tx.staged.version = f.version + 1
+		s.spoeFiles[tx.file] = tx.staged
+		tx.staged = nil
+		tx.Status = txSuccess
+		writeJSON(w, http.StatusOK, tx)
+	})
+
+	mux.HandleFunc("DELETE "+path+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.spoeTx[r.PathValue("id")]; !ok {
+			writeError(w, http.StatusNotFound, "transaction "+r.PathValue("id")+" not found")
+			return
+		}
+		delete(s.spoeTx, r.PathValue("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// spoeTarget returns the file a request reads or changes: the staged copy
+// for a transaction_id, otherwise the live file. Callers must hold s.mu.
+func (s *Server) spoeTarget(r *http.Request) (*spoeFile, int, string) {
+	parent := r.PathValue("parent")
+	if txID := r.URL.Query().Get("transaction_id"); txID != "" {
+		tx, ok := s.spoeTx[txID]
+		if !ok || tx.Status != txInProgress || tx.file != parent {
+			return nil, http.StatusNotFound, "transaction " + txID + " not found"
+		}
+		return tx.staged, 0, ""
+	}
+	f, ok := s.spoeFiles[parent]
+	if !ok {
+		return nil, http.StatusNotFound, "SPOE file " + parent + " not found"
+	}
+	return f, 0, ""
+}
+
+func (s *Server) spoeRead(w http.ResponseWriter, r *http.Request, fn func(*spoeFile) (interface{}, int, string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, status, msg := s.spoeTarget(r)
+	if status == 0 {
+		var resp interface{}
+		if resp, status, msg = fn(f); status == 0 {
+			writeJSON(w, http.StatusOK, resp)
+			return
+		}
+	}
+	writeError(w, status, msg)
+}
+
+// spoeMutate applies fn to the staged file of a transaction, or to the
+// live file when the request carries its current version, bumping it.
+func (s *Server) spoeMutate(w http.ResponseWriter, r *http.Request, okStatus int, fn func(*spoeFile) (interface{}, int, string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, status, msg := s.spoeTarget(r)
+	if status != 0 {
+		writeError(w, status, msg)
+		return
+	}
+	inTx := r.URL.Query().Get("transaction_id") != ""
+	if !inTx {
+		version, err := strconv.Atoi(r.URL.Query().Get("version"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "version or transaction_id must be specified")
+			return
+		}
+		if version != f.version {
+			writeError(w, http.StatusConflict, fmt.Sprintf("version mismatch: got %d, current %d", version, f.version))
+			return
+		}
+	}
+	resp, status, msg := fn(f)
+	if status != 0 {
+		writeError(w, status, msg)
+		return
+	}
+	if !inTx {
+		f.version++
+	}
+	writeResponse(w, okStatus, resp)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// state is one complete snapshot of the emulated configuration. The live
+// configuration and every open transaction each own a state.
+type state struct {
+	version   int
+	backends  *collection
+	servers   map[string]*collection
+	frontends *collection
+	binds     map[string]*collection
+	userlists *collection
+	defaults  *collection
+	resolvers *collection
+	// nameservers are the nameservers of each resolvers section.
+	nameservers map[string]*collection
+	rings       *collection
+	// ringServers are the servers each ring forwards to.
+	ringServers map[string]*collection
+	logForwards *collection
+	caches      *collection
+	httpErrors  *collection
+	// logForwardBinds and logForwardDgramBinds are the TCP and UDP binds
+	// of each log-forward section.
+	logForwardBinds      map[string]*collection
+	logForwardDgramBinds map[string]*collection
+	lists                map[string][]map[string]interface{}
+	// global is the global section object; nil until set, in which case
+	// the section is reported as not found.
+	global map[string]interface{}
+	// certificates are the ssl_certificates storage entries.
+	certificates []map[string]interface{}
+	// raw is the text served by /configuration/raw.
+	raw string
+}
+
+func newState() *state {
+	return &state{
+		version:              1,
+		backends:             newCollection(),
+		servers:              make(map[string]*collection),
+		frontends:            newCollection(),
+		binds:                make(map[string]*collection),
+		userlists:            newCollection(),
+		defaults:             newCollection(),
+		resolvers:            newCollection(),
+		nameservers:          make(map[string]*collection),
+		rings:                newCollection(),
+		ringServers:          make(map[string]*collection),
+		logForwards:          newCollection(),
+		caches:               newCollection(),
+		httpErrors:           newCollection(),
+		logForwardBinds:      make(map[string]*collection),
+		logForwardDgramBinds: make(map[string]*collection),
+		lists:                make(map[string][]map[string]interface{}),
+	}
+}
+
+// children returns (creating on demand) the child collection of parent.
+func (st *state) children(m map[string]*collection, parent string) *collection {
+	c, ok := m[parent]
+	if !ok {
+		c = newCollection()
+		m[parent] = c
+	}
+	return c
+}
+
+// clone deep-copies the state so a transaction can stage changes.
+func (st *state) clone() *state {
+	out := &state{
+		version:              st.version,
+		raw:                  st.raw,
+		backends:             st.backends.clone(),
+		servers:              make(map[string]*collection, len(st.servers)),
+		frontends:            st.frontends.clone(),
+		binds:                make(map[string]*collection, len(st.binds)),
+		userlists:            st.userlists.clone(),
+		defaults:             st.defaults.clone(),
+		resolvers:            st.resolvers.clone(),
+		nameservers:          make(map[string]*collection, len(st.nameservers)),
+		rings:                st.rings.clone(),
+		ringServers:          make(map[string]*collection, len(st.ringServers)),
+		logForwards:          st.logForwards.clone(),
+		caches:               st.caches.clone(),
+		httpErrors:           st.httpErrors.clone(),
+		logForwardBinds:      make(map[string]*collection, len(st.logForwardBinds)),
+		logForwardDgramBinds: make(map[string]*collection, len(st.logForwardDgramBinds)),
+		lists:                make(map[string][]map[string]interface{}, len(st.lists)),
+	}
+	if st.global != nil {
+		out.global = copyObject(st.global)
+	}
+	for _, cert := range st.certificates {
+		out.certificates = append(out.certificates, copyObject(cert))
+	}
+	for k, c := range st.servers {
+		out.servers[k] = c.clone()
+	}
+	for k, c := range st.binds {
+		out.binds[k] = c.clone()
+	}
+	for k, c := range st.nameservers {
+		out.nameservers[k] = c.clone()
+	}
+	for k, c := range st.ringServers {
+		out.ringServers[k] = c.clone()
+	}
+	for k, c := range st.logForwardBinds {
+		out.logForwardBinds[k] = c.clone()
+	}
+	for k, c := range st.logForwardDgramBinds {
+		out.logForwardDgramBinds[k] = c.clone()
+	}
+	for k, items := range st.lists {
+		copied := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			copied = append(copied, copyObject(item))
+		}
+		out.lists[k] = copied
+	}
+	return out
+}
+
+// collection stores named objects, keyed by their "name" field.
+type collection struct {
+	items map[string]map[string]interface{}
+}
+
+func newCollection() *collection {
+	return &collection{items: make(map[string]map[string]interface{})}
+}
+
+func (c *collection) get(name string) (map[string]interface{}, bool) {
+	obj, ok := c.items[name]
+	if !ok {
+		return nil, false
+	}
+	return copyObject(obj), true
+}
+
+func (c *collection) put(obj map[string]interface{}) {
+	name, _ := obj["name"].(string)
+	c.items[name] = copyObject(obj)
+}
+
+func (c *collection) remove(name string) bool {
+	if _, ok := c.items[name]; !ok {
+		return false
+	}
+	delete(c.items, name)
+	return true
+}
+
+// list returns copies of all objects sorted by name.
+func (c *collection) list() []map[string]interface{} {
+	names := make([]string, 0, len(c.items))
+	for name := range c.items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		out = append(out, copyObject(c.items[name]))
+	}
+	return out
+}
+
+func (c *collection) clone() *collection {
+	out := newCollection()
+	for name, obj := range c.items {
+		out.items[name] = copyObject(obj)
+	}
+	return out
+}
+
+// copyObject deep-copies a JSON object by round-tripping it, which also
+// normalizes numbers to float64 exactly like a real API response would.
+func copyObject(obj map[string]interface{}) map[string]interface{} {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return map[string]interface{}{}
+	}
+	return out
+}
@@ -0,0 +1,240 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Runtime stick tables, like maps, live outside the versioned
+// configuration. Each entry stores its counters as data type -> value.
+
+type stickTable struct {
+	typ     string
+	size    int
+	keys    []string
+	entries map[string]map[string]int
+}
+
+// AddStickTable registers an empty runtime stick table.
+func (s *Server) AddStickTable(name, typ string, size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.stickTables[name]; !ok {
+		s.stickTables[name] = &stickTable{typ: typ, size: size, entries: make(map[string]map[string]int)}
+	}
+}
+
+// AddStickTableEntry seeds an entry in a stick table, registering an ip
+// table of size 1000 if needed.
+func (s *Server) AddStickTableEntry(table, key string, data map[string]int) {
+	s.AddStickTable(table, "ip", 1000)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.stickTables[table]
+	if _, ok := t.entries[key]; !ok {
+		t.keys = append(t.keys, key)
+	}
+	t.entries[key] = make(map[string]int, len(data))
+	for k, v := range data {
+		t.entries[key][k] = v
+	}
+}
+
+// StickTableEntry returns a copy of an entry's counters.
+func (s *Server) StickTableEntry(table, key string) (map[string]int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.stickTables[table]
+	if !ok {
+		return nil, false
+	}
+	data, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	out := make(map[string]int, len(data))
+	for k, v := range data {
+		out[k] = v
+	}
+	return out, true
+}
+
+func (t *stickTable) info(name string) map[string]interface{} {
+	return map[string]interface{}{"name": name, "type": t.typ, "size": t.size, "used": len(t.keys)}
+}
+
+func (t *stickTable) entry(key string) map[string]interface{} {
+	out := map[string]interface{}{"id": key, "key": key, "use": false, "exp": 0}
+	for k, v := range t.entries[key] {
+		out[k] = v
+	}
+	return out
+}
+
+// stickTableFilter is one "data.<type> <op> <value>" condition.
+type stickTableFilter struct {
+	field string
+	op    string
+	value int
+}
+
+// parseStickTableFilters parses the comma separated filter query parameter.
+func parseStickTableFilters(raw string) ([]stickTableFilter, bool) {
+	var out []stickTableFilter
+	for _, part := range strings.Split(raw, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], "data.") {
+			return nil, false
+		}
+		v, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, false
+		}
+		switch fields[1] {
+		case "eq", "ne", "lt", "le", "gt", "ge":
+		default:
+			return nil, false
+		}
+		out = append(out, stickTableFilter{field: strings.TrimPrefix(fields[0], "data."), op: fields[1], value: v})
+	}
+	return out, true
+}
+
+func (f stickTableFilter) match(data map[string]int) bool {
+	v, ok := data[f.field]
+	if !ok {
+		return false
+	}
+	switch f.op {
+	case "eq":
+		return v == f.value
+	case "ne":
+		return v != f.value
+	case "lt":
+		return v < f.value
+	case "le":
+		return v <= f.value
+	case "gt":
+		return v > f.value
+	default:
+		return v >= f.value
+	}
+}
+
+// runtimeStickTables registers the /runtime/stick_tables endpoints.
+func (s *Server) runtimeStickTables(mux *http.ServeMux) {
+	base := apiPrefix + "/runtime/stick_tables"
+
+	mux.HandleFunc("GET "+base, func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		names := make([]string, 0, len(s.stickTables))
+		for name := range s.stickTables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		out := make([]map[string]interface{}, 0, len(names))
+		for _, name := range names {
+			out = append(out, s.stickTables[name].info(name))
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+
+	// lookup resolves the table named in the request; callers hold s.mu.
+	lookup := func(w http.ResponseWriter, r *http.Request) (*stickTable, bool) {
+		t, ok := s.stickTables[r.PathValue("parent")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "stick table "+r.PathValue("parent")+" not found")
+		}
+		return t, ok
+	}
+
+	mux.HandleFunc("GET "+base+"/{parent}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if t, ok := lookup(w, r); ok {
+			writeJSON(w, http.StatusOK, t.info(r.PathValue("parent")))
+		}
+	})
+
+	mux.HandleFunc("GET "+base+"/{parent}/entries", func(w http.ResponseWriter, r *http.Request) {
+		filters, ok := parseStickTableFilters(r.URL.Query().Get("filter"))
+		if !ok {
+			writeError(w, http.StatusBadRequest, "invalid filter "+r.URL.Query().Get("filter"))
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		t, ok := lookup(w, r)
+		if !ok {
+			return
+		}
+		key := r.URL.Query().Get("key")
+		out := make([]map[string]interface{}, 0, len(t.keys))
+	entries:
+		for _, k := range t.keys {
+			if key != "" && k != key {
+				continue
+			}
+			for _, f := range filters {
+				if !f.match(t.entries[k]) {
+					continue entries
+				}
+			}
+			out = append(out, t.entry(k))
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+
+	// PUT sets the given data types of an existing entry, like "set table".
+	mux.HandleFunc("PUT "+base+"/{parent}/entries", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		t, ok := lookup(w, r)
+		if !ok {
+			return
+		}
+		key, _ := obj["key"].(string)
+		data, ok := t.entries[key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "entry "+key+" not found")
+			return
+		}
+		values, _ := obj["data_type"].(map[string]interface{})
+		for k, v := range values {
+			n, ok := v.(float64)
+			if !ok {
+				writeError(w, http.StatusUnprocessableEntity, "invalid value for "+k)
+				return
+			}
+			data[k] = int(n)
+		}
+		writeJSON(w, http.StatusOK, t.entry(key))
+	})
+}
@@ -0,0 +1,477 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The raw configuration is versioned like the structured configuration:
+// pushing it requires the current version and bumps it. SSL storage
+// (certificates and crt-lists) and general storage are not versioned;
+// uploads and replacements take effect immediately.
+
+// SetRawConfig seeds the raw configuration text.
+func (s *Server) SetRawConfig(raw string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.raw = raw
+}
+
+// RawConfig returns the current raw configuration text.
+func (s *Server) RawConfig() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.raw
+}
+
+// CertificateFile returns the PEM contents last uploaded for a storage entry.
+func (s *Server) CertificateFile(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.certFiles[name]
+	return append([]byte(nil), data...), ok
+}
+
+// AddStorageFile seeds a general storage entry.
+func (s *Server) AddStorageFile(name string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generalFiles[name] = append([]byte(nil), data...)
+}
+
+// StorageFile returns the contents last uploaded for a general storage
+// entry.
+func (s *Server) StorageFile(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.generalFiles[name]
+	return append([]byte(nil), data...), ok
+}
+
+// rawConfig registers GET/POST /configuration/raw.
+func (s *Server) rawConfig(mux *http.ServeMux) {
+	path := apiPrefix + "/configuration/raw"
+
+	mux.HandleFunc("GET "+path, func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		raw := s.state.raw
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, raw)
+	})
+
+	mux.HandleFunc("POST "+path, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "failed to read body: "+err.Error())
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		version, err := strconv.Atoi(r.URL.Query().Get("version"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "version must be specified")
+			return
+		}
+		if version != s.state.version {
+			writeError(w, http.StatusConflict, "version mismatch: got "+strconv.Itoa(version)+", current "+strconv.Itoa(s.state.version))
+			return
+		}
+		s.state.raw = string(body)
+		s.state.version++
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// sslStorage registers the ssl_certificates storage endpoints.
+func (s *Server) sslStorage(mux *http.ServeMux) {
+	base := apiPrefix + "/storage/ssl_certificates"
+
+	mux.HandleFunc("GET "+base, s.handleListCertificates)
+
+	// A single entry is served as the stored PEM when its contents are
+	// known, and as the storage metadata otherwise.
+	mux.HandleFunc("GET "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		name := r.PathValue("name")
+		i := s.findCertificate(name)
+		if i < 0 {
+			writeError(w, http.StatusNotFound, "certificate "+name+" not found")
+			return
+		}
+		if data, ok := s.certFiles[name]; ok {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write(data)
+			return
+		}
+		writeJSON(w, http.StatusOK, copyObject(s.state.certificates[i]))
+	})
+
+	mux.HandleFunc("POST "+base, func(w http.ResponseWriter, r *http.Request) {
+		file, hdr, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "missing file: "+err.Error())
+			return
+		}
+		defer func() { _ = file.Close() }()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "failed to read file: "+err.Error())
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.findCertificate(hdr.Filename) >= 0 {
+			writeError(w, http.StatusConflict, "certificate "+hdr.Filename+" already exists")
+			return
+		}
+		obj := map[string]interface{}{"storage_name": hdr.Filename, "file": "/etc/haproxy/ssl/" + hdr.Filename}
+		s.state.certificates = append(s.state.certificates, obj)
+		s.certFiles[hdr.Filename] = data
+		writeJSON(w, http.StatusCreated, copyObject(obj))
+	})
+
+	mux.HandleFunc("PUT "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "failed to read body: "+err.Error())
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		name := r.PathValue("name")
+		i := s.findCertificate(name)
+		if i < 0 {
+			writeError(w, http.StatusNotFound, "certificate "+name+" not found")
+			return
+		}
+		s.certFiles[name] = data
+		writeJSON(w, http.StatusOK, copyObject(s.state.certificates[i]))
+	})
+}
+
+// findCertificate returns the index of a storage entry, or -1. Callers must
+// hold s.mu.
+func (s *Server) findCertificate(name string) int {
+	for i, c := range s.state.certificates {
+		if c["storage_name"] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddCrtList seeds an ssl_crt_lists storage file with its entries.
+func (s *Server) AddCrtList(name string, entries ...map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var data []byte
+	for _, e := range entries {
+		data = append(data, formatCrtListEntry(e)+"\n"...)
+	}
+	s.crtLists[name] = data
+}
+
+// CrtListFile returns the contents of a crt-list.
+func (s *Server) CrtListFile(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.crtLists[name]
+	return append([]byte(nil), data...), ok
+}
+
+// CrtListEntries returns the entries of a crt-list.
+func (s *Server) CrtListEntries(name string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return parseCrtListEntries(s.crtLists[name])
+}
+
+// crtListStorage registers the ssl_crt_lists storage endpoints. Files are
+// kept as text; the entries endpoints parse and append lines.
+func (s *Server) crtListStorage(mux *http.ServeMux) {
+	base := apiPrefix + "/storage/ssl_crt_lists"
+
+	mux.HandleFunc("GET "+base, func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		names := make([]string, 0, len(s.crtLists))
+		for name := range s.crtLists {
+			names = append(names, name)
+		}
+		s.mu.Unlock()
+
+		sort.Strings(names)
+		list := make([]map[string]interface{}, 0, len(names))
+		for _, name := range names {
+			list = append(list, crtListObject(name))
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+
+	mux.HandleFunc("GET "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		data, ok := s.crtLists[r.PathValue("name")]
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "crt-list "+r.PathValue("name")+" not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(data)
+	})
+
+	mux.HandleFunc("POST "+base, func(w http.ResponseWriter, r *http.Request) {
+		name, data, ok := readUpload(w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, exists := s.crtLists[name]; exists {
+			writeError(w, http.StatusConflict, "crt-list "+name+" already exists")
+			return
+		}
+		s.crtLists[name] = data
+		writeJSON(w, http.StatusCreated, crtListObject(name))
+	})
+
+	mux.HandleFunc("PUT "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "failed to read body: "+err.Error())
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		name := r.PathValue("name")
+		if _, exists := s.crtLists[name]; !exists {
+			writeError(w, http.StatusNotFound, "crt-list "+name+" not found")
+			return
+		}
+		s.crtLists[name] = data
+		writeJSON(w, http.StatusOK, crtListObject(name))
+	})
+
+	mux.HandleFunc("DELETE "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		name := r.PathValue("name")
+		if _, exists := s.crtLists[name]; !exists {
+			writeError(w, http.StatusNotFound, "crt-list "+name+" not found")
+			return
+		}
+		delete(s.crtLists, name)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET "+base+"/{name}/entries", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		data, ok := s.crtLists[r.PathValue("name")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "crt-list "+r.PathValue("name")+" not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, parseCrtListEntries(data))
+	})
+
+	mux.HandleFunc("POST "+base+"/{name}/entries", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		name := r.PathValue("name")
+		data, exists := s.crtLists[name]
+		if !exists {
+			writeError(w, http.StatusNotFound, "crt-list "+name+" not found")
+			return
+		}
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			data = append(data, '\n')
+		}
+		s.crtLists[name] = append(data, formatCrtListEntry(obj)+"\n"...)
+		writeJSON(w, http.StatusCreated, copyObject(obj))
+	})
+}
+
+// crtListObject is the API view of a crt-list storage entry.
+func crtListObject(name string) map[string]interface{} {
+	return map[string]interface{}{"storage_name": name, "file": "/etc/haproxy/ssl/" + name}
+}
+
+// formatCrtListEntry renders an API entry as a crt-list line.
+func formatCrtListEntry(e map[string]interface{}) string {
+	line, _ := e["file"].(string)
+	if opts, _ := e["ssl_bind_config"].(string); opts != "" {
+		line += " [" + opts + "]"
+	}
+	switch sni := e["sni_filter"].(type) {
+	case []interface{}:
+		for _, f := range sni {
+			line += fmt.Sprintf(" %v", f)
+		}
+	case []string:
+		line += " " + strings.Join(sni, " ")
+	}
+	return line
+}
+
+// parseCrtListEntries returns the API view of the lines of a crt-list.
+func parseCrtListEntries(data []byte) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		file, rest, _ := strings.Cut(line, " ")
+		entry := map[string]interface{}{"file": file, "line_number": i + 1}
+		rest = strings.TrimSpace(rest)
+		if strings.HasPrefix(rest, "[") {
+			if opts, after, ok := strings.Cut(rest[1:], "]"); ok {
+				entry["ssl_bind_config"] = strings.TrimSpace(opts)
+				rest = after
+			}
+		}
+		if sni := strings.Fields(rest); len(sni) > 0 {
+			entry["sni_filter"] = sni
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// generalStorage registers the general storage endpoints, which hold
+// arbitrary files such as error pages under /etc/haproxy/general.
+func (s *Server) generalStorage(mux *http.ServeMux) {
+	base := apiPrefix + "/storage/general"
+
+	mux.HandleFunc("GET "+base, func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		names := make([]string, 0, len(s.generalFiles))
+		for name := range s.generalFiles {
+			names = append(names, name)
+		}
+		s.mu.Unlock()
+
+		sort.Strings(names)
+		list := make([]map[string]interface{}, 0, len(names))
+		for _, name := range names {
+			list = append(list, generalFileObject(name))
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+
+	mux.HandleFunc("GET "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		data, ok := s.generalFiles[r.PathValue("name")]
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "file "+r.PathValue("name")+" not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+	})
+
+	mux.HandleFunc("POST "+base, func(w http.ResponseWriter, r *http.Request) {
+		name, data, ok := readUpload(w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, exists := s.generalFiles[name]; exists {
+			writeError(w, http.StatusConflict, "file "+name+" already exists")
+			return
+		}
+		s.generalFiles[name] = data
+		writeJSON(w, http.StatusCreated, generalFileObject(name))
+	})
+
+	mux.HandleFunc("PUT "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		_, data, ok := readUpload(w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		name := r.PathValue("name")
+		if _, exists := s.generalFiles[name]; !exists {
+			writeError(w, http.StatusNotFound, "file "+name+" not found")
+			return
+		}
+		s.generalFiles[name] = data
+		writeJSON(w, http.StatusOK, generalFileObject(name))
+	})
+
+	mux.HandleFunc("DELETE "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		name := r.PathValue("name")
+		if _, exists := s.generalFiles[name]; !exists {
+			writeError(w, http.StatusNotFound, "file "+name+" not found")
+			return
+		}
+		delete(s.generalFiles, name)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// readUpload reads the "file_upload" part of a multipart request, writing
+// an error response and returning false when it is missing.
+func readUpload(w http.ResponseWriter, r *http.Request) (string, []byte, bool) {
+	file, hdr, err := r.FormFile("file_upload")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "missing file_upload: "+err.Error())
+		return "", nil, false
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read file: "+err.Error())
+		return "", nil, false
+	}
+	return hdr.Filename, data, true
+}
+
+// generalFileObject is the API view of a general storage entry.
+func generalFileObject(name string) map[string]interface{} {
+	return map[string]interface{}{"storage_name": name, "file": "/etc/haproxy/general/" + name}
+}
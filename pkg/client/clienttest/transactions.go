@@ -0,0 +1,140 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	txInProgress = "in_progress"
+	txSuccess    = "success"
+)
+
+// transaction is a staged copy of the configuration, created from the
+// live state at a given version.
+type transaction struct {
+	ID      string `json:"id"`
+	Version int    `json:"_version"` //nolint:tagliatelle // Data Plane API field name
+	Status  string `json:"status"`
+
+	state *state
+}
+
+func (s *Server) handleListTransactions(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.transactions))
+	for id := range s.transactions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	list := make([]*transaction, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, s.transactions[id])
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleStartTransaction(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "version must be specified")
+		return
+	}
+	if version != s.state.version {
+		writeError(w, http.StatusConflict, fmt.Sprintf("version mismatch: got %d, current %d", version, s.state.version))
+		return
+	}
+
+	s.nextTxID++
+	tx := &transaction{
+		ID:      fmt.Sprintf("tx-%04d", s.nextTxID),
+		Version: version,
+		Status:  txInProgress,
+		state:   s.state.clone(),
+	}
+	s.transactions[tx.ID] = tx
+
+	writeJSON(w, http.StatusCreated, tx)
+}
+
+func (s *Server) handleGetTransaction(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.transactions[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "transaction "+r.PathValue("id")+" not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, tx)
+}
+
+// handleCommitTransaction replaces the live configuration with the staged
+// state, failing with 406 when the configuration moved on in the meantime
+// (matching the real API's behaviour for outdated transactions).
+func (s *Server) handleCommitTransaction(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.transactions[r.PathValue("id")]
+	if !ok || tx.Status != txInProgress {
+		writeError(w, http.StatusNotFound, "transaction "+r.PathValue("id")+" not found")
+		return
+	}
+	if tx.Version != s.state.version {
+		writeError(w, http.StatusNotAcceptable, "transaction is outdated")
+		return
+	}
+
+	tx.state.version = s.state.version + 1
+	s.state = tx.state
+	tx.state = nil
+	tx.Status = txSuccess
+
+	// Like the real API, a commit reloads HAProxy in the background and
+	// names the reload, unless force_reload asks for a synchronous one.
+	if r.URL.Query().Get("force_reload") == "true" {
+		writeJSON(w, http.StatusOK, tx)
+		return
+	}
+	w.Header().Set("Reload-ID", s.startReload())
+	writeJSON(w, http.StatusAccepted, tx)
+}
+
+func (s *Server) handleDeleteTransaction(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.transactions[id]; !ok {
+		writeError(w, http.StatusNotFound, "transaction "+id+" not found")
+		return
+	}
+	delete(s.transactions, id)
+	w.WriteHeader(http.StatusNoContent)
+}
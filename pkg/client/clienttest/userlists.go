@@ -0,0 +1,137 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clienttest provides an in-memory emulation of the HAProxy Data Plane API v3 for tests.
+package clienttest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// Users and groups live inside their userlist object, as the maps a
+// full_section GET of the userlist returns, so the member endpoints below
+// and the userlist endpoints always agree.
+
+// userlistMembers registers the users or groups endpoints of userlists.
+// field is the key of the member map in the userlist object, key the
+// member field holding its name.
+func (s *Server) userlistMembers(mux *http.ServeMux, field, key string) {
+	base := apiPrefix + "/configuration/userlists/{parent}/" + field
+
+	mux.HandleFunc("GET "+base, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		st, status, msg := s.readState(r)
+		if status != 0 {
+			writeError(w, status, msg)
+			return
+		}
+		userlist, ok := st.userlists.get(r.PathValue("parent"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "parent not found")
+			return
+		}
+		members, _ := userlist[field].(map[string]interface{})
+		names := make([]string, 0, len(members))
+		for name := range members {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		list := make([]interface{}, 0, len(names))
+		for _, name := range names {
+			list = append(list, members[name])
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+
+	// change applies fn to the member map of the request's userlist and
+	// stores the userlist back.
+	change := func(w http.ResponseWriter, r *http.Request, okStatus int, fn func(members map[string]interface{}) (interface{}, int, string)) {
+		s.mutate(w, r, okStatus, func(st *state) (interface{}, int, string) {
+			userlist, ok := st.userlists.get(r.PathValue("parent"))
+			if !ok {
+				return nil, http.StatusNotFound, "parent not found"
+			}
+			// Copy the member map so a transaction never writes through to
+			// the committed userlist.
+			members := make(map[string]interface{})
+			if current, ok := userlist[field].(map[string]interface{}); ok {
+				for name, member := range current {
+					members[name] = member
+				}
+			}
+			resp, status, msg := fn(members)
+			if status != 0 {
+				return nil, status, msg
+			}
+			if len(members) == 0 {
+				delete(userlist, field)
+			} else {
+				userlist[field] = members
+			}
+			st.userlists.put(userlist)
+			return resp, 0, ""
+		})
+	}
+
+	mux.HandleFunc("POST "+base, func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		change(w, r, http.StatusCreated, func(members map[string]interface{}) (interface{}, int, string) {
+			name, _ := obj[key].(string)
+			if name == "" {
+				return nil, http.StatusUnprocessableEntity, key + " is required"
+			}
+			if _, exists := members[name]; exists {
+				return nil, http.StatusConflict, fmt.Sprintf("object %s already exists", name)
+			}
+			members[name] = obj
+			return obj, 0, ""
+		})
+	})
+
+	mux.HandleFunc("PUT "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r)
+		if !ok {
+			return
+		}
+		change(w, r, http.StatusOK, func(members map[string]interface{}) (interface{}, int, string) {
+			name := r.PathValue("name")
+			if _, exists := members[name]; !exists {
+				return nil, http.StatusNotFound, "object " + name + " not found"
+			}
+			obj[key] = name
+			members[name] = obj
+			return obj, 0, ""
+		})
+	})
+
+	mux.HandleFunc("DELETE "+base+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		change(w, r, http.StatusNoContent, func(members map[string]interface{}) (interface{}, int, string) {
+			name := r.PathValue("name")
+			if _, exists := members[name]; !exists {
+				return nil, http.StatusNotFound, "object " + name + " not found"
+			}
+			delete(members, name)
+			return nil, 0, ""
+		})
+	})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+const frontendsEndpoint = "/services/haproxy/configuration/frontends"
+
+// Frontend is a frontend section. Commonly used fields are modeled; every
+// other field of the API object is kept in Extra, so a frontend read with
+// GetFrontend is written back unchanged by UpdateFrontend.
+//
+//nolint:tagliatelle // Data Plane API field names
+type Frontend struct {
+	Name           string `json:"name"`
+	Mode           string `json:"mode,omitempty"`
+	DefaultBackend string `json:"default_backend,omitempty"`
+
+	Extra map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON encodes f with its Extra fields.
+func (f Frontend) MarshalJSON() ([]byte, error) {
+	type plain Frontend
+	return marshalWithExtra(plain(f), f.Extra)
+}
+
+// UnmarshalJSON decodes f, keeping the fields it does not model in Extra.
+func (f *Frontend) UnmarshalJSON(data []byte) error {
+	type plain Frontend
+	return unmarshalWithExtra(data, (*plain)(f), &f.Extra)
+}
+
+// Bind is a listening address of a frontend. Without a Name the API names
+// the bind after its address and port. Fields the struct does not model
+// are kept in Extra.
+//
+//nolint:tagliatelle // Data Plane API field names
+type Bind struct {
+	Name           string `json:"name,omitempty"`
+	Address        string `json:"address"`
+	Port           int    `json:"port,omitempty"`
+	SSLCertificate string `json:"ssl_certificate,omitempty"`
+	// SSL is the API's "enabled"/"disabled" enum.
+	SSL string `json:"ssl,omitempty"`
+
+	Extra map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON encodes b with its Extra fields.
+func (b Bind) MarshalJSON() ([]byte, error) {
+	type plain Bind
+	return marshalWithExtra(plain(b), b.Extra)
+}
+
+// UnmarshalJSON decodes b, keeping the fields it does not model in Extra.
+func (b *Bind) UnmarshalJSON(data []byte) error {
+	type plain Bind
+	return unmarshalWithExtra(data, (*plain)(b), &b.Extra)
+}
+
+// ListFrontends returns all frontends.
+func (c *Client) ListFrontends(ctx context.Context) ([]Frontend, error) {
+	var out []Frontend
+	if err := c.get(ctx, frontendsEndpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetFrontend returns the frontend called name; see IsNotFound.
+func (c *Client) GetFrontend(ctx context.Context, name string) (Frontend, error) {
+	var out Frontend
+	err := c.get(ctx, frontendsEndpoint+"/"+url.PathEscape(name), &out)
+	return out, err
+}
+
+// CreateFrontend adds a frontend.
+func (c *Client) CreateFrontend(ctx context.Context, tx *Transaction, f Frontend) error {
+	return c.write(ctx, tx, http.MethodPost, frontendsEndpoint, f)
+}
+
+// UpdateFrontend replaces the frontend called f.Name.
+func (c *Client) UpdateFrontend(ctx context.Context, tx *Transaction, f Frontend) error {
+	return c.write(ctx, tx, http.MethodPut, frontendsEndpoint+"/"+url.PathEscape(f.Name), f)
+}
+
+// DeleteFrontend removes the frontend called name.
+func (c *Client) DeleteFrontend(ctx context.Context, tx *Transaction, name string) error {
+	return c.write(ctx, tx, http.MethodDelete, frontendsEndpoint+"/"+url.PathEscape(name), nil)
+}
+
+func bindsEndpoint(frontend string) string {
+	return frontendsEndpoint + "/" + url.PathEscape(frontend) + "/binds"
+}
+
+// ListBinds returns the binds of a frontend.
+func (c *Client) ListBinds(ctx context.Context, frontend string) ([]Bind, error) {
+	var out []Bind
+	if err := c.get(ctx, bindsEndpoint(frontend), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateBind adds a bind to a frontend.
+func (c *Client) CreateBind(ctx context.Context, tx *Transaction, frontend string, b Bind) error {
+	return c.write(ctx, tx, http.MethodPost, bindsEndpoint(frontend), b)
+}
+
+// UpdateBind replaces the bind called b.Name of a frontend.
+func (c *Client) UpdateBind(ctx context.Context, tx *Transaction, frontend string, b Bind) error {
+	return c.write(ctx, tx, http.MethodPut, bindsEndpoint(frontend)+"/"+url.PathEscape(b.Name), b)
+}
+
+// DeleteBind removes a bind from a frontend.
+func (c *Client) DeleteBind(ctx context.Context, tx *Transaction, frontend, name string) error {
+	return c.write(ctx, tx, http.MethodDelete, bindsEndpoint(frontend)+"/"+url.PathEscape(name), nil)
+}
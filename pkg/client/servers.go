@@ -0,0 +1,85 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Server is a server of a backend. Fields the struct does not model
+// (health check timings, TLS verification, ...) are kept in Extra.
+type Server struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Port    int    `json:"port,omitempty"`
+	// Weight is a pointer because 0 (no traffic) differs from unset (1).
+	Weight *int `json:"weight,omitempty"`
+	// SSL and Check are the API's "enabled"/"disabled" enums.
+	SSL   string `json:"ssl,omitempty"`
+	Check string `json:"check,omitempty"`
+
+	Extra map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON encodes s with its Extra fields.
+func (s Server) MarshalJSON() ([]byte, error) {
+	type plain Server
+	return marshalWithExtra(plain(s), s.Extra)
+}
+
+// UnmarshalJSON decodes s, keeping the fields it does not model in Extra.
+func (s *Server) UnmarshalJSON(data []byte) error {
+	type plain Server
+	return unmarshalWithExtra(data, (*plain)(s), &s.Extra)
+}
+
+func serversEndpoint(backend string) string {
+	return backendsEndpoint + "/" + url.PathEscape(backend) + "/servers"
+}
+
+// ListServers returns the servers of a backend.
+func (c *Client) ListServers(ctx context.Context, backend string) ([]Server, error) {
+	var out []Server
+	if err := c.get(ctx, serversEndpoint(backend), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetServer returns one server of a backend; see IsNotFound.
+func (c *Client) GetServer(ctx context.Context, backend, name string) (Server, error) {
+	var out Server
+	err := c.get(ctx, serversEndpoint(backend)+"/"+url.PathEscape(name), &out)
+	return out, err
+}
+
+// CreateServer adds a server to a backend.
+func (c *Client) CreateServer(ctx context.Context, tx *Transaction, backend string, s Server) error {
+	return c.write(ctx, tx, http.MethodPost, serversEndpoint(backend), s)
+}
+
+// UpdateServer replaces the server called s.Name in a backend.
+func (c *Client) UpdateServer(ctx context.Context, tx *Transaction, backend string, s Server) error {
+	return c.write(ctx, tx, http.MethodPut, serversEndpoint(backend)+"/"+url.PathEscape(s.Name), s)
+}
+
+// DeleteServer removes a server from a backend.
+func (c *Client) DeleteServer(ctx context.Context, tx *Transaction, backend, name string) error {
+	return c.write(ctx, tx, http.MethodDelete, serversEndpoint(backend)+"/"+url.PathEscape(name), nil)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2025 Armagan Karatosun
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const transactionsEndpoint = "/services/haproxy/transactions"
+
+// Transaction is a Data Plane API configuration transaction.
+type Transaction struct {
+	ID      string `json:"id"`
+	Version int    `json:"_version"` //nolint:tagliatelle // Data Plane API field name
+	Status  string `json:"status"`
+}
+
+// StartTransaction opens a transaction against the current configuration
+// version.
+func (c *Client) StartTransaction(ctx context.Context) (*Transaction, error) {
+	version, err := c.ConfigurationVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.requests().Do(ctx, http.MethodPost, transactionsEndpoint, map[string]string{"version": strconv.Itoa(version)}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	var tx Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("failed to parse transaction response: %w", err)
+	}
+	if tx.ID == "" {
+		return nil, fmt.Errorf("transaction response has no id: %s", string(data))
+	}
+	return &tx, nil
+}
+
+// CommitTransaction applies everything staged in tx.
+func (c *Client) CommitTransaction(ctx context.Context, tx *Transaction) error {
+	if _, err := c.requests().Do(ctx, http.MethodPut, transactionsEndpoint+"/"+tx.ID, nil, nil); err != nil {
+		return fmt.Errorf("failed to commit transaction %s: %w", tx.ID, err)
+	}
+	return nil
+}
+
+// AbortTransaction discards tx and everything staged in it.
+func (c *Client) AbortTransaction(ctx context.Context, tx *Transaction) error {
+	if _, err := c.requests().Do(ctx, http.MethodDelete, transactionsEndpoint+"/"+tx.ID, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete transaction %s: %w", tx.ID, err)
+	}
+	return nil
+}
+
+// WithTransaction runs fn inside a new transaction and commits it, so all
+// of fn's changes are applied atomically with a single reload. When fn
+// fails the transaction is aborted and nothing is applied.
+func (c *Client) WithTransaction(ctx context.Context, fn func(tx *Transaction) error) error {
+	tx, err := c.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if abortErr := c.AbortTransaction(ctx, tx); abortErr != nil {
+			return errors.Join(err, abortErr)
+		}
+		return err
+	}
+
+	return c.CommitTransaction(ctx, tx)
+}